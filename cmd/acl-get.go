@@ -0,0 +1,106 @@
+/*
+ * Minio Client (C) 2016 Minio, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/fatih/color"
+	"github.com/minio/cli"
+	"github.com/minio/mc/pkg/console"
+	"github.com/minio/minio/pkg/probe"
+)
+
+var (
+	aclGetFlags = []cli.Flag{}
+)
+
+var aclGetCmd = cli.Command{
+	Name:   "get",
+	Usage:  "Get access control list of a bucket or object.",
+	Action: mainACLGet,
+	Flags:  append(aclGetFlags, globalFlags...),
+	CustomHelpTemplate: `NAME:
+   mc acl {{.Name}} - {{.Usage}}
+
+USAGE:
+   mc acl {{.Name}} ALIAS/BUCKET[/OBJECT] [FLAGS]
+
+FLAGS:
+  {{range .Flags}}{{.}}
+  {{end}}
+EXAMPLES:
+   1. Get access control list of an object.
+     $ mc acl {{.Name}} s3/mybucket/report.pdf
+
+   2. Get access control list of a bucket.
+     $ mc acl {{.Name}} s3/mybucket
+`,
+}
+
+// checkACLGetSyntax - validate all the passed arguments
+func checkACLGetSyntax(ctx *cli.Context) {
+	if len(ctx.Args()) != 1 {
+		cli.ShowCommandHelpAndExit(ctx, "get", 1) // last argument is exit code
+	}
+}
+
+// aclGetMessage container for an access control list.
+type aclGetMessage struct {
+	Status string     `json:"status"`
+	Target string     `json:"target"`
+	Owner  aclOwner   `json:"owner"`
+	Grants []aclGrant `json:"grants"`
+}
+
+func (a aclGetMessage) JSON() string {
+	a.Status = "success"
+	aclGetMessageJSONBytes, e := json.Marshal(a)
+	fatalIf(probe.NewError(e), "Unable to marshal into JSON.")
+	return string(aclGetMessageJSONBytes)
+}
+
+func (a aclGetMessage) String() string {
+	msg := console.Colorize("ACLOwner", "Owner: "+a.Owner.ID)
+	if a.Owner.DisplayName != "" {
+		msg += console.Colorize("ACLOwner", " ("+a.Owner.DisplayName+")")
+	}
+	for _, grant := range a.Grants {
+		msg += "\n" + console.Colorize("ACLPermission", fmt.Sprintf("%-12s ", grant.Permission)) +
+			console.Colorize("ACLGrantee", grant.Grantee.String())
+	}
+	return msg
+}
+
+func mainACLGet(ctx *cli.Context) {
+	console.SetColor("ACLOwner", color.New(color.FgWhite, color.Bold))
+	console.SetColor("ACLPermission", color.New(color.FgCyan, color.Bold))
+	console.SetColor("ACLGrantee", color.New(color.FgWhite))
+
+	setGlobalsFromContext(ctx)
+	checkACLGetSyntax(ctx)
+
+	path := ctx.Args().First()
+	client, err := newS3Client(path)
+	fatalIf(err, "Unable to initialize ‘"+path+"’, access control lists are only supported on object storage.")
+
+	acl, err := client.GetACL()
+	fatalIf(err.Trace(path), "Unable to get access control list of ‘"+path+"’.")
+
+	printMsg(aclGetMessage{Target: path, Owner: acl.Owner, Grants: acl.Grants})
+}
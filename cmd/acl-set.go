@@ -0,0 +1,173 @@
+/*
+ * Minio Client (C) 2016 Minio, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"encoding/json"
+	"strings"
+
+	"github.com/fatih/color"
+	"github.com/minio/cli"
+	"github.com/minio/mc/pkg/console"
+	"github.com/minio/minio/pkg/probe"
+)
+
+var (
+	aclSetFlags = []cli.Flag{
+		cli.StringFlag{
+			Name:  "canned",
+			Usage: "Replace the access control list by a canned one, e.g. private or public-read.",
+		},
+	}
+)
+
+var aclSetCmd = cli.Command{
+	Name:   "set",
+	Usage:  "Replace access control list of a bucket or object.",
+	Action: mainACLSet,
+	Flags:  append(aclSetFlags, globalFlags...),
+	CustomHelpTemplate: `NAME:
+   mc acl {{.Name}} - {{.Usage}}
+
+USAGE:
+   mc acl {{.Name}} ALIAS/BUCKET[/OBJECT] PERMISSION=TYPE:GRANTEE [PERMISSION=TYPE:GRANTEE...]
+   mc acl {{.Name}} --canned ACL ALIAS/BUCKET[/OBJECT]
+
+PERMISSION:
+   read, write, read-acp, write-acp, full-control
+
+TYPE:
+   id     Canonical user id.
+   email  Email address of an account.
+   uri    Predefined group.
+
+FLAGS:
+  {{range .Flags}}{{.}}
+  {{end}}
+EXAMPLES:
+   1. Give full control of an object to its owner and read access to another account.
+     $ mc acl {{.Name}} s3/mybucket/report.pdf full-control=id:79a59df900b949e55d96a1e698fbacedfd6e09d98eacf8f8d5218e7cd47ef2be \
+           read=id:a9a7b886d6fd24a52fe8ca5bef65f89a64e0193f23000e241bf9b1c61be666e9
+
+   2. Give read access of an object to everyone.
+     $ mc acl {{.Name}} s3/mybucket/report.pdf full-control=id:79a59df900b949e55d96a1e698fbacedfd6e09d98eacf8f8d5218e7cd47ef2be \
+           read=uri:http://acs.amazonaws.com/groups/global/AllUsers
+
+   3. Make a bucket private.
+     $ mc acl {{.Name}} --canned private s3/mybucket
+`,
+}
+
+// checkACLSetSyntax - validate all the passed arguments
+func checkACLSetSyntax(ctx *cli.Context) {
+	args := ctx.Args()
+	// Either a canned access control list or grants.
+	if len(args) == 0 || (ctx.String("canned") != "") == (len(args) > 1) {
+		cli.ShowCommandHelpAndExit(ctx, "set", 1) // last argument is exit code
+	}
+}
+
+// parseCannedACL - validates a canned access control list.
+func parseCannedACL(canned string) (string, *probe.Error) {
+	for _, c := range cannedACLs {
+		if c == canned {
+			return canned, nil
+		}
+	}
+	return "", errInvalidCannedACL(canned).Trace(canned)
+}
+
+// parseACLGrants - parses PERMISSION=TYPE:GRANTEE arguments into grants.
+func parseACLGrants(args []string) ([]aclGrant, *probe.Error) {
+	var grants []aclGrant
+	for _, arg := range args {
+		i := strings.Index(arg, "=")
+		if i <= 0 {
+			return nil, errInvalidACLGrant(arg).Trace(arg)
+		}
+		permission := strings.Replace(strings.ToUpper(arg[:i]), "-", "_", -1)
+		if _, ok := aclGrantHeaders[permission]; !ok {
+			return nil, errInvalidACLGrant(arg).Trace(arg)
+		}
+		j := strings.Index(arg[i+1:], ":")
+		if j <= 0 || i+j+2 == len(arg) {
+			return nil, errInvalidACLGrant(arg).Trace(arg)
+		}
+		value := arg[i+j+2:]
+		grantee := aclGrantee{}
+		switch arg[i+1 : i+j+1] {
+		case "id":
+			grantee = aclGrantee{Type: aclGranteeCanonicalUser, ID: value}
+		case "email":
+			grantee = aclGrantee{Type: aclGranteeEmail, EmailAddress: value}
+		case "uri":
+			grantee = aclGrantee{Type: aclGranteeGroup, URI: value}
+		default:
+			return nil, errInvalidACLGrant(arg).Trace(arg)
+		}
+		grants = append(grants, aclGrant{Grantee: grantee, Permission: permission})
+	}
+	return grants, nil
+}
+
+// aclSetMessage container
+type aclSetMessage struct {
+	Status string     `json:"status"`
+	Target string     `json:"target"`
+	Canned string     `json:"canned,omitempty"`
+	Grants []aclGrant `json:"grants,omitempty"`
+}
+
+func (a aclSetMessage) JSON() string {
+	a.Status = "success"
+	aclSetMessageJSONBytes, e := json.Marshal(a)
+	fatalIf(probe.NewError(e), "Unable to marshal into JSON.")
+	return string(aclSetMessageJSONBytes)
+}
+
+func (a aclSetMessage) String() string {
+	return console.Colorize("ACL", "Access control list set on ‘"+a.Target+"’.")
+}
+
+func mainACLSet(ctx *cli.Context) {
+	console.SetColor("ACL", color.New(color.FgGreen, color.Bold))
+
+	setGlobalsFromContext(ctx)
+	checkACLSetSyntax(ctx)
+
+	args := ctx.Args()
+	path := args.First()
+
+	var canned string
+	var grants []aclGrant
+	var err *probe.Error
+	if ctx.String("canned") != "" {
+		canned, err = parseCannedACL(ctx.String("canned"))
+		fatalIf(err, "Unable to parse canned access control list.")
+	} else {
+		grants, err = parseACLGrants(args.Tail())
+		fatalIf(err, "Unable to parse grants.")
+	}
+
+	client, err := newS3Client(path)
+	fatalIf(err, "Unable to initialize ‘"+path+"’, access control lists are only supported on object storage.")
+
+	err = client.SetACL(canned, grants)
+	fatalIf(err.Trace(path), "Unable to set access control list of ‘"+path+"’.")
+
+	printMsg(aclSetMessage{Target: path, Canned: canned, Grants: grants})
+}
@@ -0,0 +1,320 @@
+/*
+ * Minio Client (C) 2016 Minio, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/dustin/go-humanize"
+	"github.com/fatih/color"
+	"github.com/minio/cli"
+	"github.com/minio/mc/pkg/console"
+	"github.com/minio/minio/pkg/probe"
+)
+
+// bench specific flags.
+var (
+	benchFlags = []cli.Flag{
+		cli.BoolFlag{
+			Name:  "help, h",
+			Usage: "Help of bench.",
+		},
+		cli.StringFlag{
+			Name:  "size",
+			Value: "64MiB",
+			Usage: "Size of every object, e.g. ‘1GiB’.",
+		},
+		cli.IntFlag{
+			Name:  "count",
+			Value: 16,
+			Usage: "Number of objects uploaded and downloaded.",
+		},
+		cli.IntFlag{
+			Name:  "parallel",
+			Value: 4,
+			Usage: "Number of objects transferred in parallel.",
+		},
+		cli.StringFlag{
+			Name:  "part-size",
+			Usage: "Part size of multipart uploads, e.g. ‘128MiB’. Between 5MiB and 5GiB.",
+		},
+		cli.IntFlag{
+			Name:  "concurrent-parts",
+			Usage: "Number of parts of an object uploaded in parallel. Each part is buffered in memory.",
+		},
+	}
+)
+
+// Measure throughput of a target.
+var benchCmd = cli.Command{
+	Name:   "bench",
+	Usage:  "Measure upload and download throughput of a target.",
+	Action: mainBench,
+	Flags:  append(benchFlags, globalFlags...),
+	CustomHelpTemplate: `NAME:
+   mc {{.Name}} - {{.Usage}}
+
+USAGE:
+   mc {{.Name}} [FLAGS] TARGET
+
+   Objects of random data are generated in memory and uploaded below a
+   new prefix of the target, then downloaded and discarded, and finally
+   removed. Local disks are not involved.
+
+FLAGS:
+  {{range .Flags}}{{.}}
+  {{end}}
+EXAMPLES:
+   1. Measure throughput of a bucket on Amazon S3 cloud storage.
+      $ mc {{.Name}} s3/mybucket
+
+   2. Measure throughput with 100 objects of 1GiB, 8 of them transferred in parallel.
+      $ mc {{.Name}} --size 1GiB --count 100 --parallel 8 s3/mybucket
+
+   3. Measure the overhead of mc itself, uploaded data is discarded.
+      $ mc {{.Name}} bench://null/
+
+   4. Measure throughput of a local disk.
+      $ mc {{.Name}} /mnt/data/
+
+   5. Copy generated objects like any others, to measure cp and mirror.
+      $ mc cp --recursive bench://random/100x64MiB/ s3/mybucket/
+      $ mc mirror s3/mybucket/ bench://null/mybucket/
+`,
+}
+
+// benchOpts - options of a benchmark.
+type benchOpts struct {
+	Size     int64
+	Count    int
+	Parallel int
+	Upload   PutOpts
+}
+
+// checkBenchSyntax - validate all the passed arguments
+func checkBenchSyntax(ctx *cli.Context) {
+	if len(ctx.Args()) != 1 {
+		cli.ShowCommandHelpAndExit(ctx, "bench", 1) // last argument is exit code
+	}
+	if _, err := parseBenchOpts(ctx); err != nil {
+		fatalIf(err.Trace(), "Unable to parse benchmark options.")
+	}
+}
+
+// parseBenchOpts - options of the command line flags.
+func parseBenchOpts(ctx *cli.Context) (benchOpts, *probe.Error) {
+	size, e := humanize.ParseBytes(ctx.String("size"))
+	if e != nil {
+		return benchOpts{}, probe.NewError(e).Trace(ctx.String("size"))
+	}
+	if ctx.Int("count") < 1 {
+		return benchOpts{}, errInvalidArgument().Trace(strconv.Itoa(ctx.Int("count")))
+	}
+	parallel, err := parseParallel(ctx.Int("parallel"))
+	if err != nil {
+		return benchOpts{}, err.Trace()
+	}
+	upload, err := parsePutOpts(ctx.String("part-size"), ctx.Int("concurrent-parts"))
+	if err != nil {
+		return benchOpts{}, err.Trace()
+	}
+	return benchOpts{Size: int64(size), Count: ctx.Int("count"), Parallel: parallel, Upload: upload}, nil
+}
+
+// benchMessage container for the result of a benchmark operation.
+type benchMessage struct {
+	Status     string  `json:"status"`
+	Operation  string  `json:"operation"`
+	Objects    int     `json:"objects"`
+	Size       int64   `json:"size"`
+	Duration   float64 `json:"duration"`
+	Throughput int64   `json:"throughput"`
+}
+
+// String colorized benchmark message.
+func (b benchMessage) String() string {
+	return console.Colorize("Operation", fmt.Sprintf("%-9s ", strings.Title(b.Operation)+":")) +
+		fmt.Sprintf("%d objects, %s in %.1fs, ", b.Objects, humanize.IBytes(uint64(b.Size)), b.Duration) +
+		console.Colorize("Throughput", humanize.IBytes(uint64(b.Throughput))+"/s")
+}
+
+// JSON jsonified benchmark message.
+func (b benchMessage) JSON() string {
+	b.Status = "success"
+	benchMessageJSONBytes, e := json.Marshal(b)
+	fatalIf(probe.NewError(e), "Unable to marshal into JSON.")
+	return string(benchMessageJSONBytes)
+}
+
+// newBenchMessage - result of an operation on objects taking the
+// duration.
+func newBenchMessage(operation string, opts benchOpts, duration time.Duration) benchMessage {
+	size := opts.Size * int64(opts.Count)
+	msg := benchMessage{
+		Operation: operation,
+		Objects:   opts.Count,
+		Size:      size,
+		Duration:  duration.Seconds(),
+	}
+	if duration > 0 {
+		msg.Throughput = int64(float64(size) / duration.Seconds())
+	}
+	return msg
+}
+
+// runBench - runs the operation for every object, up to parallel of
+// them at once. Returns the time taken and the first error.
+func runBench(count, parallel int, operation func(i int) *probe.Error) (time.Duration, *probe.Error) {
+	indexCh := make(chan int)
+	var wg sync.WaitGroup
+	var mutex sync.Mutex
+	var firstErr *probe.Error
+	start := time.Now()
+	for w := 0; w < parallel; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range indexCh {
+				if err := operation(i); err != nil {
+					mutex.Lock()
+					if firstErr == nil {
+						firstErr = err
+					}
+					mutex.Unlock()
+				}
+			}
+		}()
+	}
+	for i := 0; i < count; i++ {
+		indexCh <- i
+	}
+	close(indexCh)
+	wg.Wait()
+	return time.Since(start), firstErr
+}
+
+// doBench - uploads generated objects below a new prefix of the target,
+// downloads and removes them. Data uploaded to benchmark URLs is
+// discarded and can not be downloaded. Objects are removed even if the
+// benchmark fails.
+func doBench(targetURL string, opts benchOpts) ([]benchMessage, *probe.Error) {
+	prefix := urlJoinPath(targetURL, "mc-bench-"+newRandomID(8))
+	objectURL := func(i int) string {
+		return urlJoinPath(prefix, strconv.Itoa(i))
+	}
+
+	removed := false
+	defer func() {
+		if !removed {
+			// Objects not uploaded fail to be removed, errors are ignored.
+			removeBenchObjects(prefix, opts)
+		}
+	}()
+
+	var msgs []benchMessage
+	duration, err := runBench(opts.Count, opts.Parallel, func(i int) *probe.Error {
+		_, err := putTargetStream(globalContext, objectURL(i), newBenchReader(benchHostRandom, opts.Size), opts.Size, opts.Upload)
+		return err
+	})
+	if err != nil {
+		return nil, err.Trace(targetURL)
+	}
+	msgs = append(msgs, newBenchMessage("upload", opts, duration))
+
+	if !isBenchURL(targetURL) {
+		duration, err = runBench(opts.Count, opts.Parallel, func(i int) *probe.Error {
+			reader, err := getSourceStream(globalContext, objectURL(i), GetOpts{})
+			if err != nil {
+				return err
+			}
+			if closer, ok := reader.(io.Closer); ok {
+				defer closer.Close()
+			}
+			if _, e := io.Copy(ioutil.Discard, reader); e != nil {
+				return probe.NewError(e)
+			}
+			return nil
+		})
+		if err != nil {
+			return nil, err.Trace(targetURL)
+		}
+		msgs = append(msgs, newBenchMessage("download", opts, duration))
+	}
+
+	removed = true
+	duration, err = removeBenchObjects(prefix, opts)
+	if err != nil {
+		return nil, err.Trace(targetURL)
+	}
+	msg := newBenchMessage("remove", opts, duration)
+	msg.Size = 0
+	msg.Throughput = 0
+	return append(msgs, msg), nil
+}
+
+// removeBenchObjects - removes the objects of a benchmark below prefix,
+// returns the time taken and the first error.
+func removeBenchObjects(prefix string, opts benchOpts) (time.Duration, *probe.Error) {
+	duration, err := runBench(opts.Count, opts.Parallel, func(i int) *probe.Error {
+		clnt, err := newClient(urlJoinPath(prefix, strconv.Itoa(i)))
+		if err != nil {
+			return err
+		}
+		return clnt.Remove(globalContext, false)
+	})
+	// Folders of the prefix are left behind on filesystems, they are
+	// only removed once empty.
+	if clnt, clntErr := newClient(prefix); clntErr == nil && clnt.GetURL().Type == FileSystem {
+		if removeErr := clnt.Remove(globalContext, false); removeErr != nil && err == nil {
+			err = removeErr
+		}
+	}
+	if err != nil {
+		return duration, err.Trace(prefix)
+	}
+	return duration, nil
+}
+
+// mainBench - is a handler for mc bench command
+func mainBench(ctx *cli.Context) {
+	// Additional command specific theme customization.
+	console.SetColor("Operation", color.New(color.FgCyan, color.Bold))
+	console.SetColor("Throughput", color.New(color.FgYellow))
+
+	// Set global flags from context.
+	setGlobalsFromContext(ctx)
+
+	// check 'bench' cli arguments.
+	checkBenchSyntax(ctx)
+
+	opts, err := parseBenchOpts(ctx)
+	fatalIf(err, "Unable to parse benchmark options.")
+
+	targetURL := ctx.Args().First()
+	msgs, err := doBench(targetURL, opts)
+	fatalIf(err.Trace(targetURL), "Unable to measure throughput of ‘"+targetURL+"’.")
+	for _, msg := range msgs {
+		printMsg(msg)
+	}
+}
@@ -0,0 +1,105 @@
+/*
+ * Minio Client (C) 2016 Minio, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"sync"
+
+	"github.com/dustin/go-humanize"
+	"github.com/minio/minio/pkg/probe"
+)
+
+// bufferPool - buffers of parts of uploads, drawn from a budget of
+// memory shared by all uploads. Buffers exceeding the budget are only
+// handed out once others are returned, so that many parallel uploads
+// wait for memory instead of running out of it. A nil pool hands out
+// buffers without limit.
+type bufferPool struct {
+	mutex *sync.Mutex
+	cond  *sync.Cond
+	limit int64
+	used  int64
+}
+
+// Buffers of parts of uploads, nil unless ‘--memory-limit’ is set.
+var globalBufferPool *bufferPool
+
+// newBufferPool - pool of buffers of at most limit bytes in total.
+func newBufferPool(limit int64) *bufferPool {
+	mutex := &sync.Mutex{}
+	return &bufferPool{mutex: mutex, cond: sync.NewCond(mutex), limit: limit}
+}
+
+// setMemoryLimit - limits the memory of buffers of uploads to the size
+// of ‘--memory-limit’, like ‘512MiB’.
+func setMemoryLimit(limit string) *probe.Error {
+	if limit == "" {
+		globalBufferPool = nil
+		return nil
+	}
+	n, e := humanize.ParseBytes(limit)
+	if e != nil || n == 0 {
+		return errInvalidMemoryLimit(limit).Trace(limit)
+	}
+	globalBufferPool = newBufferPool(int64(n))
+	return nil
+}
+
+// fits - reports whether a buffer of the size fits the budget, a
+// buffer larger than the whole budget is handed out once no other is.
+func (p *bufferPool) fits(size int64) bool {
+	return p.used == 0 || p.used+size <= p.limit
+}
+
+// Get - buffer of the size, waits until it fits the budget.
+func (p *bufferPool) Get(size int64) []byte {
+	if p == nil {
+		return make([]byte, size)
+	}
+	p.mutex.Lock()
+	for !p.fits(size) {
+		p.cond.Wait()
+	}
+	p.used += size
+	p.mutex.Unlock()
+	return make([]byte, size)
+}
+
+// TryGet - buffer of the size, nil if it does not fit the budget.
+func (p *bufferPool) TryGet(size int64) []byte {
+	if p == nil {
+		return make([]byte, size)
+	}
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+	if !p.fits(size) {
+		return nil
+	}
+	p.used += size
+	return make([]byte, size)
+}
+
+// Put - returns a buffer to the budget, it must not be used anymore.
+func (p *bufferPool) Put(buf []byte) {
+	if p == nil || buf == nil {
+		return
+	}
+	p.mutex.Lock()
+	p.used -= int64(len(buf))
+	p.mutex.Unlock()
+	p.cond.Broadcast()
+}
@@ -0,0 +1,65 @@
+/*
+ * Minio Client (C) 2016 Minio, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"time"
+
+	. "gopkg.in/check.v1"
+)
+
+// Test limiting the memory of buffers of uploads.
+func (s *TestSuite) TestBufferPool(c *C) {
+	// Nil pools hand out buffers without limit.
+	var pool *bufferPool
+	c.Assert(len(pool.Get(10)), Equals, 10)
+	c.Assert(len(pool.TryGet(10)), Equals, 10)
+	pool.Put(make([]byte, 10))
+
+	pool = newBufferPool(10)
+	buf1 := pool.Get(6)
+	c.Assert(len(buf1), Equals, 6)
+	c.Assert(pool.TryGet(6), IsNil)
+	buf2 := pool.TryGet(4)
+	c.Assert(len(buf2), Equals, 4)
+
+	// Buffers exceeding the limit wait for others to be returned.
+	got := make(chan []byte)
+	go func() {
+		got <- pool.Get(8)
+	}()
+	select {
+	case <-got:
+		c.Fatal("Buffer exceeding the limit was handed out.")
+	case <-time.After(50 * time.Millisecond):
+	}
+	pool.Put(buf1)
+	pool.Put(buf2)
+	c.Assert(len(<-got), Equals, 8)
+
+	// Buffers larger than the limit are handed out alone.
+	pool = newBufferPool(10)
+	c.Assert(len(pool.Get(20)), Equals, 20)
+	c.Assert(pool.TryGet(1), IsNil)
+
+	c.Assert(setMemoryLimit("1MiB"), IsNil)
+	c.Assert(globalBufferPool.limit, Equals, int64(1<<20))
+	c.Assert(setMemoryLimit(""), IsNil)
+	c.Assert(globalBufferPool, IsNil)
+	c.Assert(setMemoryLimit("lots"), NotNil)
+	c.Assert(setMemoryLimit("0"), NotNil)
+}
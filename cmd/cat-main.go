@@ -21,9 +21,13 @@ import (
 	"fmt"
 	"io"
 	"os"
+	"path"
+	"path/filepath"
+	"sort"
 	"strings"
 	"syscall"
 
+	"github.com/dustin/go-humanize"
 	"github.com/minio/cli"
 	"github.com/minio/minio/pkg/probe"
 )
@@ -34,6 +38,30 @@ var (
 			Name:  "help, h",
 			Usage: "Help of cat",
 		},
+		cli.StringFlag{
+			Name:  "encrypt-key",
+			Usage: "Decrypt objects with customer provided keys, as comma separated list of ‘ALIAS/PREFIX=KEY’.",
+		},
+		cli.StringFlag{
+			Name:  "version-id",
+			Usage: "Display a specific version of the object.",
+		},
+		cli.StringFlag{
+			Name:  "offset",
+			Usage: "Start displaying at a byte offset, e.g. ‘1024’ or ‘1MiB’.",
+		},
+		cli.StringFlag{
+			Name:  "length",
+			Usage: "Display at most a number of bytes, e.g. ‘1024’ or ‘1MiB’.",
+		},
+		cli.StringFlag{
+			Name:  "buffer-size",
+			Usage: "Size of the ranges objects are read ahead in, e.g. ‘128MiB’.",
+		},
+		cli.IntFlag{
+			Name:  "concurrent-parts",
+			Usage: "Number of ranges of an object read ahead in parallel, each buffered in memory. Reading pauses while the output is blocked.",
+		},
 	}
 )
 
@@ -49,6 +77,9 @@ var catCmd = cli.Command{
 USAGE:
    mc {{.Name}} [FLAGS] SOURCE [SOURCE...]
 
+   Sources with wildcards ‘*’, ‘?’ or ‘[...]’ display all objects matching them in lexical order,
+   wildcards do not match the separator. Quote them to keep the shell from expanding them.
+
 FLAGS:
   {{range .Flags}}{{.}}
   {{end}}
@@ -62,6 +93,21 @@ EXAMPLES:
    3. Concantenate multiple files to one.
       $ mc {{.Name}} part.* > complete.img
 
+   4. Display the contents of an object encrypted with a customer provided key.
+      $ mc {{.Name}} --encrypt-key "s3/secret=32byteslongsecretkeymustbegiven1" s3/secret/passwords.txt
+
+   5. Display a previous version of an object in a versioned bucket.
+      $ mc {{.Name}} --version-id "3/L4kqtJlcpXroDTDmJ+rmSpXd3dIbrHY" s3/mybucket/config.json
+
+   6. Display 1KiB of a large log object, starting at 10MiB.
+      $ mc {{.Name}} --offset 10MiB --length 1KiB s3/logs/server.log
+
+   7. Extract a large archive, reading 4 ranges of 256MiB ahead while tar is busy.
+      $ mc {{.Name}} --buffer-size 256MiB --concurrent-parts 4 s3/backups/data.tar.gz | tar xzf -
+
+   8. Reassemble a sharded export from its parts, in lexical order.
+      $ mc {{.Name}} "s3/exports/2017-05-01/part-*" > export.csv
+
 `,
 }
 
@@ -76,10 +122,139 @@ func checkCatSyntax(ctx *cli.Context) {
 			fatalIf(probe.NewError(errors.New("")), fmt.Sprintf("Unknown flag ‘%s’ passed.", arg))
 		}
 	}
+	if ctx.String("version-id") != "" && (len(args) != 1 || args[0] == "-" || hasWildcard(args[0])) {
+		fatalIf(errInvalidArgument().Trace(args...), "--version-id can only be used with a single object.")
+	}
+	if ctx.String("offset") != "" || ctx.String("length") != "" {
+		if ctx.String("version-id") != "" {
+			fatalIf(errInvalidArgument().Trace(args...), "--offset and --length cannot be used with --version-id.")
+		}
+		for _, arg := range args {
+			if arg == "-" {
+				fatalIf(errInvalidArgument().Trace(args...), "--offset and --length cannot be used with standard input.")
+			}
+		}
+		_, err := parseByteRange(ctx.String("offset"), ctx.String("length"))
+		fatalIf(err, "Unable to parse byte range.")
+	}
+	if ctx.Int("concurrent-parts") > 1 && (ctx.String("version-id") != "" || ctx.String("offset") != "" || ctx.String("length") != "") {
+		fatalIf(errInvalidArgument().Trace(args...), "--concurrent-parts cannot be used with --version-id, --offset and --length.")
+	}
+	if _, err := parsePutOpts(ctx.String("buffer-size"), ctx.Int("concurrent-parts")); err != nil {
+		fatalIf(err.Trace(), "Unable to parse buffer options.")
+	}
 }
 
-// catURL displays contents of a URL to stdout.
-func catURL(sourceURL string) *probe.Error {
+// parseByteRange - parses ‘--offset’ and ‘--length’ into download
+// options, both accept plain and human readable byte counts.
+func parseByteRange(offset, length string) (GetOpts, *probe.Error) {
+	opts := GetOpts{}
+	if offset != "" {
+		n, e := humanize.ParseBytes(offset)
+		if e != nil {
+			return GetOpts{}, probe.NewError(e)
+		}
+		opts.Offset = int64(n)
+	}
+	if length != "" {
+		n, e := humanize.ParseBytes(length)
+		if e != nil {
+			return GetOpts{}, probe.NewError(e)
+		}
+		if n == 0 {
+			return GetOpts{}, errInvalidArgument().Trace(length)
+		}
+		opts.Length = int64(n)
+	}
+	return opts, nil
+}
+
+// hasWildcard - reports whether the URL holds wildcards of a pattern.
+func hasWildcard(urlStr string) bool {
+	return strings.ContainsAny(urlStr, "*?[")
+}
+
+// expandWildcardURL - URLs of all objects matching the wildcards of the
+// URL in lexical order, wildcards only match within a path segment.
+// Objects are listed below the folder holding the first wildcard, only
+// patterns spanning several path segments list recursively.
+func expandWildcardURL(urlStr string) ([]string, *probe.Error) {
+	separator := string(newClientURL(urlStr).Separator)
+	dir := urlStr[:strings.LastIndex(urlStr[:strings.IndexAny(urlStr, "*?[")], separator)+1]
+	pattern := filepath.ToSlash(urlStr[len(dir):])
+	if _, e := path.Match(pattern, ""); e != nil {
+		return nil, probe.NewError(e).Trace(urlStr)
+	}
+
+	listURL := dir
+	if listURL == "" {
+		listURL = "." + separator
+	}
+	clnt, err := newClient(listURL)
+	if err != nil {
+		return nil, err.Trace(urlStr)
+	}
+	listPath := clnt.GetURL().Path
+	recursive := strings.Contains(pattern, "/")
+	var urls []string
+	for content := range clnt.List(globalContext, recursive, false) {
+		if content.Err != nil {
+			return nil, content.Err.Trace(urlStr)
+		}
+		if content.Type.IsDir() {
+			continue
+		}
+		relPath := strings.TrimPrefix(content.URL.Path, listPath)
+		if ok, _ := path.Match(pattern, filepath.ToSlash(relPath)); ok {
+			urls = append(urls, dir+relPath)
+		}
+	}
+	if len(urls) == 0 {
+		return nil, errNoMatchingObjects(urlStr).Trace(urlStr)
+	}
+	sort.Strings(urls)
+	return urls, nil
+}
+
+// expandCatURLs - URLs to display for the arguments, arguments holding
+// wildcards are expanded unless an object of their name exists.
+func expandCatURLs(args []string) ([]string, *probe.Error) {
+	var urls []string
+	for _, arg := range args {
+		if arg == "-" || !hasWildcard(arg) {
+			urls = append(urls, arg)
+			continue
+		}
+		if _, _, err := url2Stat(arg); err == nil {
+			urls = append(urls, arg)
+			continue
+		}
+		matches, err := expandWildcardURL(arg)
+		if err != nil {
+			return nil, err.Trace(arg)
+		}
+		urls = append(urls, matches...)
+	}
+	return urls, nil
+}
+
+// catVersionURL displays contents of a specific version of the object to stdout.
+func catVersionURL(sourceURL, versionID string) *probe.Error {
+	clnt, err := newClient(sourceURL)
+	if err != nil {
+		return err.Trace(sourceURL)
+	}
+	reader, err := clnt.GetVersion(versionID)
+	if err != nil {
+		return err.Trace(sourceURL, versionID)
+	}
+	return catOut(reader).Trace(sourceURL)
+}
+
+// catURL displays contents of a URL to stdout. With more than one
+// concurrent part whole objects are read ahead in ranges of the buffer
+// size, ranges are only fetched while buffers are free.
+func catURL(sourceURL string, encKeys map[string]EncryptOpts, opts GetOpts, bufferOpts PutOpts) *probe.Error {
 	var reader io.Reader
 	switch sourceURL {
 	case "-":
@@ -88,9 +263,21 @@ func catURL(sourceURL string) *probe.Error {
 		// Ignore size, since os.Stat() would not return proper size all the
 		// time for local filesystem for example /proc files.
 		var err *probe.Error
-		if reader, err = getSourceStream(sourceURL); err != nil {
+		opts.SSE = getEncryptOpts(encKeys, sourceURL)
+		if bufferOpts.ConcurrentParts > 1 && opts.Offset == 0 && opts.Length == 0 {
+			var partSize int64
+			if partSize, err = optimalPartSize(-1, bufferOpts.PartSize); err == nil {
+				reader, err = getSourceRanges(globalContext, sourceURL, partSize, bufferOpts.ConcurrentParts, opts)
+			}
+		} else {
+			reader, err = getSourceStream(globalContext, sourceURL, opts)
+		}
+		if err != nil {
 			return err.Trace(sourceURL)
 		}
+		if closer, ok := reader.(io.Closer); ok {
+			defer closer.Close()
+		}
 	}
 	return catOut(reader).Trace(sourceURL)
 }
@@ -145,8 +332,23 @@ func mainCat(ctx *cli.Context) {
 		}
 	}
 
+	if versionID := ctx.String("version-id"); versionID != "" {
+		fatalIf(catVersionURL(args[0], versionID).Trace(args[0]), "Unable to read version ‘"+versionID+"’ of ‘"+args[0]+"’.")
+		return
+	}
+
+	encKeys, err := parseEncryptKeys(ctx.String("encrypt-key"), "")
+	fatalIf(err, "Unable to parse encryption keys.")
+	opts, err := parseByteRange(ctx.String("offset"), ctx.String("length"))
+	fatalIf(err, "Unable to parse byte range.")
+	bufferOpts, err := parsePutOpts(ctx.String("buffer-size"), ctx.Int("concurrent-parts"))
+	fatalIf(err, "Unable to parse buffer options.")
+
+	urls, err := expandCatURLs(args)
+	fatalIf(err, "Unable to expand wildcards.")
+
 	// Convert arguments to URLs: expand alias, fix format.
-	for _, url := range args {
-		fatalIf(catURL(url).Trace(url), "Unable to read from ‘"+url+"’.")
+	for _, url := range urls {
+		fatalIf(catURL(url, encKeys, opts, bufferOpts).Trace(url), "Unable to read from ‘"+url+"’.")
 	}
 }
@@ -0,0 +1,136 @@
+/*
+ * Minio Client (C) 2016 Minio, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/minio/minio/pkg/probe"
+	. "gopkg.in/check.v1"
+)
+
+// captureStdout - runs f with stdout redirected to a temporary file,
+// returns what it wrote.
+func captureStdout(c *C, f func()) []byte {
+	out, e := ioutil.TempFile(os.TempDir(), "stdout-")
+	c.Assert(e, IsNil)
+	defer os.Remove(out.Name())
+	defer out.Close()
+
+	defer func(stdout *os.File) { os.Stdout = stdout }(os.Stdout)
+	os.Stdout = out
+	f()
+
+	data, e := ioutil.ReadFile(out.Name())
+	c.Assert(e, IsNil)
+	return data
+}
+
+// Test reading whole objects ahead in ranges with cat.
+func (s *TestSuite) TestCatReadAhead(c *C) {
+	defer func(load func() (*configV8, *probe.Error)) { loadMcConfig = load }(loadMcConfig)
+	loadMcConfig = func() (*configV8, *probe.Error) { return newMcConfig(), nil }
+
+	root, e := ioutil.TempDir(os.TempDir(), "cat-")
+	c.Assert(e, IsNil)
+	defer os.RemoveAll(root)
+
+	// Three ranges of the smallest buffer size.
+	data := bytes.Repeat([]byte("0123456789abcdef"), (2*s3PartSizeLowerLimit+1024)/16)
+	object := filepath.Join(root, "object")
+	c.Assert(ioutil.WriteFile(object, data, 0600), IsNil)
+
+	reader, err := getSourceRanges(context.Background(), object, s3PartSizeLowerLimit, 2, GetOpts{})
+	c.Assert(err, IsNil)
+	_, ok := reader.(*rangeReader)
+	c.Assert(ok, Equals, true)
+	c.Assert(reader.(*rangeReader).Close(), IsNil)
+
+	bufferOpts := PutOpts{PartSize: s3PartSizeLowerLimit, ConcurrentParts: 2}
+	out := captureStdout(c, func() {
+		c.Assert(catURL(object, nil, GetOpts{}, bufferOpts), IsNil)
+	})
+	c.Assert(bytes.Equal(out, data), Equals, true)
+
+	// Byte ranges are read as one stream.
+	out = captureStdout(c, func() {
+		c.Assert(catURL(object, nil, GetOpts{Offset: 10, Length: 6}, bufferOpts), IsNil)
+	})
+	c.Assert(string(out), Equals, "abcdef")
+}
+
+// Test expanding wildcards of cat arguments.
+func (s *TestSuite) TestExpandWildcardURL(c *C) {
+	defer func(load func() (*configV8, *probe.Error)) { loadMcConfig = load }(loadMcConfig)
+	loadMcConfig = func() (*configV8, *probe.Error) { return newMcConfig(), nil }
+
+	root, e := ioutil.TempDir(os.TempDir(), "cat-")
+	c.Assert(e, IsNil)
+	defer os.RemoveAll(root)
+	for _, name := range []string{"a.log", "b.log", "c.txt", "sub/d.log", "sub/e.txt", "sub/deep/f.log", "x*"} {
+		file := filepath.Join(root, filepath.FromSlash(name))
+		c.Assert(os.MkdirAll(filepath.Dir(file), 0700), IsNil)
+		c.Assert(ioutil.WriteFile(file, []byte(name), 0600), IsNil)
+	}
+	rootURL := root + string(filepath.Separator)
+
+	testCases := []struct {
+		pattern string
+		matches []string
+	}{
+		// Patterns of a single segment do not match in sub folders.
+		{"*.log", []string{"a.log", "b.log"}},
+		{"?.txt", []string{"c.txt"}},
+		{"*/*.log", []string{"sub/d.log"}},
+		{"s?b/*", []string{"sub/d.log", "sub/e.txt"}},
+		{"sub/*/*", []string{"sub/deep/f.log"}},
+		{"*.none", nil},
+		{"[", nil},
+	}
+	for _, testCase := range testCases {
+		urls, err := expandWildcardURL(rootURL + filepath.FromSlash(testCase.pattern))
+		if testCase.matches == nil {
+			c.Assert(err, Not(IsNil), Commentf("%s", testCase.pattern))
+			continue
+		}
+		c.Assert(err, IsNil, Commentf("%s", testCase.pattern))
+		var matches []string
+		for _, u := range urls {
+			matches = append(matches, filepath.ToSlash(strings.TrimPrefix(u, rootURL)))
+		}
+		c.Assert(matches, DeepEquals, testCase.matches, Commentf("%s", testCase.pattern))
+	}
+
+	// Relative patterns are expanded in the working folder.
+	wd, e := os.Getwd()
+	c.Assert(e, IsNil)
+	defer os.Chdir(wd)
+	c.Assert(os.Chdir(root), IsNil)
+	urls, err := expandWildcardURL("*.log")
+	c.Assert(err, IsNil)
+	c.Assert(urls, DeepEquals, []string{"a.log", "b.log"})
+
+	// Existing objects named like a pattern are displayed as they are.
+	urls, err = expandCatURLs([]string{"x*", "-", "sub/*.txt"})
+	c.Assert(err, IsNil)
+	c.Assert(urls, DeepEquals, []string{"x*", "-", filepath.Join("sub", "e.txt")})
+}
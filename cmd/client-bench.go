@@ -0,0 +1,390 @@
+/*
+ * Minio Client (C) 2016 Minio, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"context"
+	"io"
+	"io/ioutil"
+	"math/rand"
+	"net/url"
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/dustin/go-humanize"
+	"github.com/minio/mc/pkg/hookreader"
+	"github.com/minio/minio/pkg/probe"
+)
+
+// Benchmark client, generating or discarding data at memory speed.
+// ‘bench://zero/SIZE’ and ‘bench://random/SIZE’ are objects of zeros
+// or random data, ‘bench://random/COUNTxSIZE/’ is a folder of COUNT
+// such objects. Uploads to ‘bench://null/’ are discarded.
+type benchClient struct {
+	targetURL *ClientURL
+}
+
+// Hosts of benchmark URLs.
+const (
+	benchHostZero   = "zero"
+	benchHostRandom = "random"
+	benchHostNull   = "null"
+)
+
+// Name of the API in errors of unsupported operations.
+const benchAPIType = "benchmark"
+
+// Generated data repeats a block of this size.
+const benchBlockSize = 1024 * 1024
+
+var (
+	benchRandomBlock     []byte
+	benchRandomBlockOnce sync.Once
+	// Generated objects are as old as the process.
+	benchModTime = time.Now().UTC()
+)
+
+// isBenchURL - reports whether the URL is served by the benchmark
+// client.
+func isBenchURL(urlStr string) bool {
+	return strings.HasPrefix(urlStr, "bench://")
+}
+
+// benchNew returns an initialized benchClient.
+func benchNew(urlStr string) (Client, *probe.Error) {
+	targetURL := newClientURL(urlStr)
+	switch targetURL.Host {
+	case benchHostZero, benchHostRandom, benchHostNull:
+	default:
+		return nil, errInvalidArgument().Trace(urlStr)
+	}
+	return &benchClient{targetURL: targetURL}, nil
+}
+
+// benchBlock - block repeated by the generated data of the host.
+func benchBlock(host string) []byte {
+	if host != benchHostRandom {
+		return make([]byte, benchBlockSize)
+	}
+	benchRandomBlockOnce.Do(func() {
+		benchRandomBlock = make([]byte, benchBlockSize)
+		rand.New(rand.NewSource(time.Now().UnixNano())).Read(benchRandomBlock)
+	})
+	return benchRandomBlock
+}
+
+// benchReader - reads size bytes of a repeated block.
+type benchReader struct {
+	block     []byte
+	offset    int64
+	remaining int64
+}
+
+// newBenchReader - generated data of the host.
+func newBenchReader(host string, size int64) *benchReader {
+	return &benchReader{block: benchBlock(host), remaining: size}
+}
+
+func (r *benchReader) Read(p []byte) (int, error) {
+	if r.remaining <= 0 {
+		return 0, io.EOF
+	}
+	if int64(len(p)) > r.remaining {
+		p = p[:r.remaining]
+	}
+	n := copy(p, r.block[r.offset%int64(len(r.block)):])
+	r.offset += int64(n)
+	r.remaining -= int64(n)
+	return n, nil
+}
+
+// Close - implements io.Closer.
+func (r *benchReader) Close() error {
+	return nil
+}
+
+// GetURL get url.
+func (c *benchClient) GetURL() ClientURL {
+	return *c.targetURL
+}
+
+// notImplemented - error of operations the benchmark client does not
+// support.
+func (c *benchClient) notImplemented(api string) *probe.Error {
+	return probe.NewError(APINotImplemented{
+		API:     api,
+		APIType: benchAPIType,
+	})
+}
+
+// parseBenchPath - size of the objects of the path and their number,
+// -1 for single objects, and whether the path is an object. Paths
+// which are not generated are not ok.
+func parseBenchPath(urlPath string) (size int64, count int, isObject bool, ok bool) {
+	segments := strings.Split(strings.Trim(urlPath, "/"), "/")
+	if len(segments) == 0 || len(segments) > 2 || segments[0] == "" {
+		return 0, 0, false, false
+	}
+	spec := segments[0]
+	count = -1
+	if i := strings.Index(spec, "x"); i >= 0 {
+		n, e := strconv.Atoi(spec[:i])
+		if e != nil || n < 0 {
+			return 0, 0, false, false
+		}
+		count = n
+		spec = spec[i+1:]
+	}
+	n, e := humanize.ParseBytes(spec)
+	if e != nil {
+		return 0, 0, false, false
+	}
+	size = int64(n)
+	if len(segments) == 1 {
+		return size, count, count < 0, true
+	}
+	// Objects of folders are numbered from zero.
+	index, e := strconv.Atoi(segments[1])
+	if count < 0 || e != nil || index < 0 || index >= count || strconv.Itoa(index) != segments[1] {
+		return 0, 0, false, false
+	}
+	return size, count, true, true
+}
+
+// Stat - size of generated objects, folders of objects and the root
+// of the null host are folders.
+func (c *benchClient) Stat() (*ClientContent, *probe.Error) {
+	urlPath := c.targetURL.Path
+	if c.targetURL.Host == benchHostNull {
+		if strings.Trim(urlPath, "/") == "" {
+			return &ClientContent{URL: *c.targetURL, Type: os.ModeDir}, nil
+		}
+		return nil, probe.NewError(PathNotFound{Path: c.targetURL.String()})
+	}
+	size, _, isObject, ok := parseBenchPath(urlPath)
+	if !ok {
+		return nil, probe.NewError(PathNotFound{Path: c.targetURL.String()})
+	}
+	if !isObject {
+		return &ClientContent{URL: *c.targetURL, Type: os.ModeDir}, nil
+	}
+	return &ClientContent{URL: *c.targetURL, Time: benchModTime, Size: size, Type: os.FileMode(0644)}, nil
+}
+
+// List - lists a generated object, or the objects of a folder.
+func (c *benchClient) List(ctx context.Context, recursive, incomplete bool) <-chan *ClientContent {
+	contentCh := make(chan *ClientContent)
+	go func() {
+		defer close(contentCh)
+		// Nothing is stored on the null host.
+		if incomplete || c.targetURL.Host == benchHostNull {
+			return
+		}
+		content, err := c.Stat()
+		if err != nil {
+			contentCh <- &ClientContent{Err: err.Trace(c.targetURL.String())}
+			return
+		}
+		if !content.Type.IsDir() {
+			contentCh <- content
+			return
+		}
+		if !recursive && !strings.HasSuffix(c.targetURL.Path, "/") {
+			contentCh <- content
+			return
+		}
+		size, count, _, _ := parseBenchPath(c.targetURL.Path)
+		dir := strings.TrimSuffix(c.targetURL.Path, "/") + "/"
+		for i := 0; i < count; i++ {
+			if e := ctx.Err(); e != nil {
+				contentCh <- &ClientContent{Err: probe.NewError(e).Trace(c.targetURL.String())}
+				return
+			}
+			objectURL := *c.targetURL
+			objectURL.Path = dir + strconv.Itoa(i)
+			contentCh <- &ClientContent{URL: objectURL, Time: benchModTime, Size: size, Type: os.FileMode(0644)}
+		}
+	}()
+	return contentCh
+}
+
+// Get - generates the data of the object.
+func (c *benchClient) Get(ctx context.Context, opts GetOpts) (io.Reader, *probe.Error) {
+	if e := ctx.Err(); e != nil {
+		return nil, probe.NewError(e).Trace(c.targetURL.String())
+	}
+	content, err := c.Stat()
+	if err != nil {
+		return nil, err.Trace(c.targetURL.String())
+	}
+	if content.Type.IsDir() {
+		return nil, probe.NewError(PathIsNotRegular{Path: c.targetURL.String()})
+	}
+	if opts.Offset > content.Size {
+		return nil, probe.NewError(ObjectRangeInvalid{Object: c.targetURL.String(), Offset: opts.Offset})
+	}
+	size := content.Size - opts.Offset
+	if opts.Length > 0 && opts.Length < size {
+		size = opts.Length
+	}
+	reader := newBenchReader(c.targetURL.Host, size)
+	reader.offset = opts.Offset
+	return reader, nil
+}
+
+// Put - discards the data, only uploads to the null host are allowed.
+func (c *benchClient) Put(ctx context.Context, reader io.Reader, size int64, contentType string, progress io.Reader, opts PutOpts) (int64, *probe.Error) {
+	if c.targetURL.Host != benchHostNull {
+		return 0, c.notImplemented("Put")
+	}
+	n, e := io.Copy(ioutil.Discard, newContextReader(ctx, hookreader.NewHook(reader, progress)))
+	if e != nil {
+		return n, probe.NewError(e)
+	}
+	if size >= 0 && n != size {
+		return n, probe.NewError(UnexpectedEOF{TotalSize: size, TotalWritten: n})
+	}
+	return n, nil
+}
+
+// Copy - discards the data of the size, like an upload.
+func (c *benchClient) Copy(ctx context.Context, source string, size int64, progress io.Reader, opts CopyOpts) *probe.Error {
+	_, err := c.Put(ctx, newBenchReader(benchHostZero, size), size, "", progress, PutOpts{})
+	return err
+}
+
+// Checksum - ETag computed from the generated data.
+func (c *benchClient) Checksum(partSize int64) (string, int64, *probe.Error) {
+	if partSize < 0 {
+		partSize = 0
+	}
+	reader, err := c.Get(context.Background(), GetOpts{})
+	if err != nil {
+		return "", 0, err.Trace(c.targetURL.String())
+	}
+	etag, err := readETag(reader, partSize)
+	if err != nil {
+		return "", 0, err.Trace(c.targetURL.String())
+	}
+	return etag, partSize, nil
+}
+
+// MakeBucket - folders of the null host always exist.
+func (c *benchClient) MakeBucket(region string) *probe.Error {
+	if c.targetURL.Host != benchHostNull {
+		return c.notImplemented("MakeBucket")
+	}
+	return nil
+}
+
+// Remove - nothing is stored on the null host.
+func (c *benchClient) Remove(ctx context.Context, incomplete bool) *probe.Error {
+	if c.targetURL.Host != benchHostNull {
+		return c.notImplemented("Remove")
+	}
+	return nil
+}
+
+// RemoveBulk - removes the objects read from the channel one by one.
+func (c *benchClient) RemoveBulk(contentCh <-chan *ClientContent) <-chan *ClientContent {
+	resultCh := make(chan *ClientContent)
+	go func() {
+		defer close(resultCh)
+		for content := range contentCh {
+			url := content.URL
+			clnt := &benchClient{targetURL: &url}
+			resultCh <- &ClientContent{URL: content.URL, Err: clnt.Remove(context.Background(), false)}
+		}
+	}()
+	return resultCh
+}
+
+// GetAccess - access policies not implemented.
+func (c *benchClient) GetAccess() (string, *probe.Error) {
+	return "", c.notImplemented("GetAccess")
+}
+
+// GetAccessRules - access policies not implemented.
+func (c *benchClient) GetAccessRules() (map[string]string, *probe.Error) {
+	return nil, c.notImplemented("GetAccessRules")
+}
+
+// SetAccess - access policies not implemented.
+func (c *benchClient) SetAccess(access string) *probe.Error {
+	return c.notImplemented("SetAccess")
+}
+
+// SelectObjectContent - queries not implemented.
+func (c *benchClient) SelectObjectContent(expression string, sse EncryptOpts, opts SelectObjectOpts) (io.ReadCloser, *probe.Error) {
+	return nil, c.notImplemented("SelectObjectContent")
+}
+
+// ShareDownload - share download not implemented.
+func (c *benchClient) ShareDownload(expires time.Duration, reqParams url.Values) (string, *probe.Error) {
+	return "", c.notImplemented("ShareDownload")
+}
+
+// ShareUpload - share upload not implemented.
+func (c *benchClient) ShareUpload(opts ShareUploadOpts) (map[string]string, *probe.Error) {
+	return nil, c.notImplemented("ShareUpload")
+}
+
+// ShareUploadURL - share upload not implemented.
+func (c *benchClient) ShareUploadURL(opts ShareUploadOpts) (string, *probe.Error) {
+	return "", c.notImplemented("ShareUploadURL")
+}
+
+// Watch - watching events not implemented.
+func (c *benchClient) Watch(params WatchParams) (*WatchObject, *probe.Error) {
+	return nil, c.notImplemented("Watch")
+}
+
+// Unwatch - watching events not implemented.
+func (c *benchClient) Unwatch(params WatchParams) *probe.Error {
+	return c.notImplemented("Unwatch")
+}
+
+// RestoreObject - archiving not implemented.
+func (c *benchClient) RestoreObject(days int, tier string) *probe.Error {
+	return c.notImplemented("RestoreObject")
+}
+
+// RestoreStatus - archiving not implemented.
+func (c *benchClient) RestoreStatus(sse EncryptOpts) (ObjectRestoreStatus, *probe.Error) {
+	return ObjectRestoreStatus{}, c.notImplemented("RestoreStatus")
+}
+
+// ListVersions - versioning not implemented.
+func (c *benchClient) ListVersions(recursive bool) <-chan *ClientContent {
+	contentCh := make(chan *ClientContent, 1)
+	contentCh <- &ClientContent{Err: c.notImplemented("ListVersions")}
+	close(contentCh)
+	return contentCh
+}
+
+// GetVersion - versioning not implemented.
+func (c *benchClient) GetVersion(versionID string) (io.Reader, *probe.Error) {
+	return nil, c.notImplemented("GetVersion")
+}
+
+// RemoveVersion - versioning not implemented.
+func (c *benchClient) RemoveVersion(versionID string) *probe.Error {
+	return c.notImplemented("RemoveVersion")
+}
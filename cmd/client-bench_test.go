@@ -0,0 +1,170 @@
+/*
+ * Minio Client (C) 2016 Minio, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"sync/atomic"
+
+	"github.com/minio/minio/pkg/probe"
+	. "gopkg.in/check.v1"
+)
+
+func (s *TestSuite) TestBenchClient(c *C) {
+	testCases := []struct {
+		path     string
+		size     int64
+		count    int
+		isObject bool
+		ok       bool
+	}{
+		{"/1KiB", 1024, -1, true, true},
+		{"/3x1KiB/", 1024, 3, false, true},
+		{"/3x1KiB/2", 1024, 3, true, true},
+		{"/3x1KiB/3", 0, 0, false, false},
+		{"/3x1KiB/02", 0, 0, false, false},
+		{"/1KiB/0", 0, 0, false, false},
+		{"/object", 0, 0, false, false},
+		{"/", 0, 0, false, false},
+	}
+	for _, testCase := range testCases {
+		size, count, isObject, ok := parseBenchPath(testCase.path)
+		c.Assert(ok, Equals, testCase.ok, Commentf("%s", testCase.path))
+		if ok {
+			c.Assert(size, Equals, testCase.size)
+			c.Assert(count, Equals, testCase.count)
+			c.Assert(isObject, Equals, testCase.isObject)
+		}
+	}
+
+	clnt, err := benchNew("bench://random/3x2MiB/")
+	c.Assert(err, IsNil)
+	var listed []string
+	for content := range clnt.List(context.Background(), true, false) {
+		c.Assert(content.Err, IsNil)
+		c.Assert(content.Size, Equals, int64(2*1024*1024))
+		listed = append(listed, content.URL.String())
+	}
+	c.Assert(listed, DeepEquals, []string{"bench://random/3x2MiB/0", "bench://random/3x2MiB/1", "bench://random/3x2MiB/2"})
+
+	// Generated data repeats a block, ranges read alike.
+	clnt, err = benchNew("bench://random/3x2MiB/1")
+	c.Assert(err, IsNil)
+	reader, err := clnt.Get(context.Background(), GetOpts{})
+	c.Assert(err, IsNil)
+	data, e := ioutil.ReadAll(reader)
+	c.Assert(e, IsNil)
+	c.Assert(len(data), Equals, 2*1024*1024)
+	c.Assert(bytes.Equal(data[:benchBlockSize], data[benchBlockSize:]), Equals, true)
+	reader, err = clnt.Get(context.Background(), GetOpts{Offset: benchBlockSize - 10, Length: 20})
+	c.Assert(err, IsNil)
+	part, e := ioutil.ReadAll(reader)
+	c.Assert(e, IsNil)
+	c.Assert(part, DeepEquals, data[benchBlockSize-10:benchBlockSize+10])
+
+	_, err = clnt.Put(context.Background(), bytes.NewReader(data), int64(len(data)), "", nil, PutOpts{})
+	c.Assert(err, NotNil)
+
+	// Uploads to the null host are discarded.
+	clnt, err = benchNew("bench://null/bucket/object")
+	c.Assert(err, IsNil)
+	n, err := clnt.Put(context.Background(), bytes.NewReader(data), int64(len(data)), "", nil, PutOpts{})
+	c.Assert(err, IsNil)
+	c.Assert(n, Equals, int64(len(data)))
+	_, err = clnt.Stat()
+	c.Assert(err, NotNil)
+
+	_, err = benchNew("bench://disk/1KiB")
+	c.Assert(err, NotNil)
+
+	// Operations run for every object, the first error is returned.
+	var ran int32
+	_, err = runBench(10, 3, func(i int) *probe.Error {
+		atomic.AddInt32(&ran, 1)
+		if i == 5 {
+			return errInvalidArgument()
+		}
+		return nil
+	})
+	c.Assert(err, NotNil)
+	c.Assert(ran, Equals, int32(10))
+}
+
+// Test removing the objects of failed benchmarks.
+func (s *TestSuite) TestBenchCleanup(c *C) {
+	var mutex sync.Mutex
+	objects := make(map[string]bool)
+	uploaded := 0
+	// Objects are uploaded and removed, downloads are denied.
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mutex.Lock()
+		defer mutex.Unlock()
+		switch {
+		case len(r.URL.Query()["location"]) == 1:
+			w.Write([]byte("<LocationConstraint xmlns=\"http://doc.s3.amazonaws.com/2006-03-01\"></LocationConstraint>"))
+		case r.Method == "GET" && len(r.URL.Query()["uploads"]) == 1:
+			w.Write([]byte("<ListMultipartUploadsResult></ListMultipartUploadsResult>"))
+		case r.Method == "POST" && len(r.URL.Query()["uploads"]) == 1:
+			w.Write([]byte("<InitiateMultipartUploadResult><UploadId>upload1</UploadId></InitiateMultipartUploadResult>"))
+		case r.Method == "PUT" && r.URL.Query().Get("uploadId") == "":
+			io.Copy(ioutil.Discard, r.Body)
+			objects[r.URL.Path] = true
+			uploaded++
+			w.Header().Set("ETag", "\"etag\"")
+		case r.Method == "PUT":
+			io.Copy(ioutil.Discard, r.Body)
+			w.Header().Set("ETag", "\"etag\"")
+		case r.Method == "POST" && r.URL.Query().Get("uploadId") != "":
+			objects[r.URL.Path] = true
+			uploaded++
+			w.Write([]byte("<CompleteMultipartUploadResult></CompleteMultipartUploadResult>"))
+		case r.Method == "DELETE":
+			delete(objects, r.URL.Path)
+			w.WriteHeader(http.StatusNoContent)
+		default:
+			w.WriteHeader(http.StatusForbidden)
+			w.Write([]byte("<Error><Code>AccessDenied</Code><Message>Access Denied.</Message></Error>"))
+		}
+	}))
+	defer server.Close()
+
+	defer func(load func() (*configV8, *probe.Error)) { loadMcConfig = load }(loadMcConfig)
+	loadMcConfig = func() (*configV8, *probe.Error) {
+		conf := newMcConfig()
+		conf.Hosts["s3"] = hostConfigV8{
+			URL:       server.URL,
+			AccessKey: "WLGDGYAQYIGI833EV05A",
+			SecretKey: "BYvgJM101sHngl2uzjXS/OBF/aMxAN06JrJ3qJlF",
+			API:       "S3v4",
+			Lookup:    "path",
+		}
+		return conf, nil
+	}
+
+	_, err := doBench("s3/bucket", benchOpts{Size: 1024, Count: 4, Parallel: 2})
+	c.Assert(err, Not(IsNil))
+	mutex.Lock()
+	defer mutex.Unlock()
+	c.Assert(uploaded, Equals, 4)
+	c.Assert(objects, HasLen, 0)
+}
@@ -89,7 +89,91 @@ type ObjectOnGlacier struct {
 }
 
 func (e ObjectOnGlacier) Error() string {
-	return "Object ‘" + e.Object + "’ is on Glacier storage."
+	return "Object ‘" + e.Object + "’ is on Glacier storage. Please restore it using ‘mc restore’."
+}
+
+// ObjectEncrypted - object is encrypted with a customer provided key
+// which was not supplied.
+type ObjectEncrypted struct {
+	Object string
+}
+
+func (e ObjectEncrypted) Error() string {
+	return "Object ‘" + e.Object + "’ is encrypted. Please provide the encryption key using ‘--encrypt-key’."
+}
+
+// ObjectDecryptionFailed - provided key does not match the key
+// the object was encrypted with.
+type ObjectDecryptionFailed struct {
+	Object string
+}
+
+func (e ObjectDecryptionFailed) Error() string {
+	return "Unable to decrypt object ‘" + e.Object + "’, provided encryption key does not match."
+}
+
+// ObjectVersionMissing - requested version of the object does not exist.
+type ObjectVersionMissing struct {
+	Object    string
+	VersionID string
+}
+
+func (e ObjectVersionMissing) Error() string {
+	return "Version ‘" + e.VersionID + "’ of object ‘" + e.Object + "’ does not exist."
+}
+
+// ObjectRangeInvalid - requested range starts beyond the end of the object.
+type ObjectRangeInvalid struct {
+	Object string
+	Offset int64
+}
+
+func (e ObjectRangeInvalid) Error() string {
+	return fmt.Sprintf("Offset %d is beyond the end of object ‘%s’.", e.Offset, e.Object)
+}
+
+// ObjectPreconditionFailed - object does not meet the conditions of
+// a copy.
+type ObjectPreconditionFailed struct {
+	Object string
+}
+
+func (e ObjectPreconditionFailed) Error() string {
+	return "Object ‘" + e.Object + "’ does not match the copy conditions, it was not copied."
+}
+
+// ObjectChecksumMismatch - data transferred does not match the
+// checksum of the object.
+type ObjectChecksumMismatch struct {
+	Object   string
+	Expected string
+	Actual   string
+}
+
+func (e ObjectChecksumMismatch) Error() string {
+	if e.Expected == "" {
+		return "Checksum of object ‘" + e.Object + "’ does not match the data received by the host."
+	}
+	return "Checksum of object ‘" + e.Object + "’ does not match, expected ‘" + e.Expected + "’ but computed ‘" + e.Actual + "’."
+}
+
+// ObjectChecksumUnverifiable - checksum of the object cannot be
+// computed, e.g. as the sizes of its parts are unknown.
+type ObjectChecksumUnverifiable struct {
+	Object string
+}
+
+func (e ObjectChecksumUnverifiable) Error() string {
+	return "Checksum of object ‘" + e.Object + "’ cannot be verified, the sizes of its parts are unknown."
+}
+
+// MFARequired - request was denied without a valid MFA.
+type MFARequired struct {
+	Path string
+}
+
+func (e MFARequired) Error() string {
+	return "Access to ‘" + e.Path + "’ requires MFA, please set serial number and current token of the device in ‘MC_MFA_<ALIAS>’ or with ‘mc config host add --mfa’."
 }
 
 // BucketNameTopLevel - generic error
@@ -17,7 +17,9 @@
 package cmd
 
 import (
+	"context"
 	"io"
+	"net/url"
 	"os"
 	"path"
 	"path/filepath"
@@ -36,7 +38,7 @@ import (
 
 // filesystem client
 type fsClient struct {
-	PathURL *clientURL
+	PathURL *ClientURL
 }
 
 const (
@@ -82,17 +84,17 @@ func isIgnoredFile(filename string) bool {
 }
 
 // URL get url.
-func (f *fsClient) GetURL() clientURL {
+func (f *fsClient) GetURL() ClientURL {
 	return *f.PathURL
 }
 
 // Unwatch - not implemented for fs, on purpose.
-func (f *fsClient) Unwatch(params watchParams) *probe.Error {
+func (f *fsClient) Unwatch(params WatchParams) *probe.Error {
 	return nil
 }
 
 // Watches for all fs events on an input path.
-func (f *fsClient) Watch(params watchParams) (*watchObject, *probe.Error) {
+func (f *fsClient) Watch(params WatchParams) (*WatchObject, *probe.Error) {
 	eventChan := make(chan Event)
 	errorChan := make(chan *probe.Error)
 	doneChan := make(chan bool)
@@ -100,8 +102,12 @@ func (f *fsClient) Watch(params watchParams) (*watchObject, *probe.Error) {
 	// an event if the receiver is not able to keep up the sending pace.
 	neventChan := make(chan notify.EventInfo, 1)
 
+	if params.ARN != "" {
+		return nil, probe.NewError(APINotImplemented{API: "Watch with ARN", APIType: "filesystem"})
+	}
+
 	var fsEvents []notify.Event
-	for _, event := range params.events {
+	for _, event := range params.Events {
 		switch event {
 		case "put":
 			fsEvents = append(fsEvents, EventTypePut...)
@@ -115,81 +121,90 @@ func (f *fsClient) Watch(params watchParams) (*watchObject, *probe.Error) {
 	// Set up a watchpoint listening for events within a directory tree rooted
 	// at current working directory. Dispatch remove events to c.
 	recursivePath := f.PathURL.Path
-	if params.recursive {
+	if params.Recursive {
 		recursivePath = f.PathURL.Path + "..."
 	}
 	if e := notify.Watch(recursivePath, neventChan, fsEvents...); e != nil {
 		return nil, probe.NewError(e)
 	}
-
-	// wait for doneChan to close the watcher, eventChan and errorChan
-	go func() {
-		<-doneChan
-
-		close(eventChan)
-		close(errorChan)
-		notify.Stop(neventChan)
-	}()
+	// Keys of events are relative to the watched folder.
+	root, e := filepath.Abs(f.PathURL.Path)
+	if e != nil {
+		root = f.PathURL.Path
+	}
+	eventKey := func(eventPath string) string {
+		key, e := filepath.Rel(root, eventPath)
+		if e != nil {
+			return filepath.ToSlash(eventPath)
+		}
+		return filepath.ToSlash(key)
+	}
 
 	timeFormatFS := "2006-01-02T15:04:05.000Z"
 
 	// Get fsnotify notifications for events and errors, and sent them
-	// using eventChan and errorChan
+	// using eventChan and errorChan, until doneChan is closed.
 	go func() {
+		defer close(errorChan)
+		defer close(eventChan)
+		defer notify.Stop(neventChan)
+
 		for {
+			var event notify.EventInfo
 			select {
-			case event, ok := <-neventChan:
-				if !ok {
-					return
-				}
-				var i os.FileInfo
-				if IsPutEvent(event.Event()) {
-					// Look for any writes, send a response to indicate a full copy.
-					var e error
-					i, e = os.Stat(event.Path())
-					if e != nil {
-						if os.IsNotExist(e) {
-							continue
-						}
-						errorChan <- probe.NewError(e)
-						continue
-					}
-					if i.IsDir() {
-						// we want files
+			case event = <-neventChan:
+			case <-doneChan:
+				return
+			}
+			var watchEvent Event
+			if IsPutEvent(event.Event()) {
+				// Look for any writes, send a response to indicate a full copy.
+				i, e := os.Stat(event.Path())
+				if e != nil {
+					if os.IsNotExist(e) {
 						continue
 					}
-					eventChan <- Event{
-						Time:   time.Now().Format(timeFormatFS),
-						Size:   i.Size(),
-						Path:   event.Path(),
-						Client: f,
-						Type:   EventCreate,
-					}
-				} else if IsDeleteEvent(event.Event()) {
-					eventChan <- Event{
-						Time:   time.Now().Format(timeFormatFS),
-						Path:   event.Path(),
-						Client: f,
-						Type:   EventRemove,
+					select {
+					case errorChan <- probe.NewError(e):
+					case <-doneChan:
+						return
 					}
+					continue
+				}
+				if i.IsDir() {
+					// we want files
+					continue
 				}
+				watchEvent = Event{Size: i.Size(), Type: EventCreate, Name: eventNameCreated + "Put"}
+			} else if IsDeleteEvent(event.Event()) {
+				watchEvent = Event{Type: EventRemove, Name: eventNameRemoved + "Delete"}
+			} else {
+				continue
+			}
+			watchEvent.Key = eventKey(event.Path())
+			if !params.Matches(watchEvent.Key, watchEvent.Name) {
+				continue
+			}
+			watchEvent.Time = time.Now().UTC().Format(timeFormatFS)
+			watchEvent.Path = event.Path()
+			watchEvent.Client = f
+			select {
+			case eventChan <- watchEvent:
+			case <-doneChan:
+				return
 			}
 		}
 	}()
 
-	return &watchObject{
-		events: eventChan,
-		errors: errorChan,
-		done:   doneChan,
-	}, nil
+	return NewWatchObject(eventChan, errorChan, doneChan), nil
 }
 
 /// Object operations.
 
 // Put - create a new file.
-func (f *fsClient) Put(reader io.Reader, size int64, contentType string, progress io.Reader) (int64, *probe.Error) {
-	// ContentType is not handled on purpose.
-	// For filesystem this is a redundant information.
+func (f *fsClient) Put(ctx context.Context, reader io.Reader, size int64, contentType string, progress io.Reader, opts PutOpts) (int64, *probe.Error) {
+	// ContentType and upload options are not handled on
+	// purpose. For filesystem this is a redundant information.
 
 	// Extract dir name.
 	objectDir, _ := filepath.Split(f.PathURL.Path)
@@ -223,23 +238,31 @@ func (f *fsClient) Put(reader io.Reader, size int64, contentType string, progres
 		}
 	}
 
-	// If exists, open in append mode. If not create it the part file.
-	partFile, e := os.OpenFile(objectPartPath, os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0600)
+	// If exists, continue at its end. If not create it the part file.
+	file, e := os.OpenFile(objectPartPath, os.O_CREATE|os.O_WRONLY, 0600)
 	if e != nil {
 		err := f.toClientError(e, f.PathURL.Path)
 		return 0, err.Trace(f.PathURL.Path)
 	}
 
-	// Get stat to get the current size.
-	partSt, e := partFile.Stat()
+	// Seek to the end to get the current size.
+	if _, e = file.Seek(0, os.SEEK_END); e != nil {
+		file.Close()
+		err := f.toClientError(e, objectPartPath)
+		return 0, err.Trace(objectPartPath)
+	}
+
+	// Blocks of zeros are left as holes, e.g. of disk images.
+	partFile, e := newSparseWriter(file)
 	if e != nil {
+		file.Close()
 		err := f.toClientError(e, objectPartPath)
 		return 0, err.Trace(objectPartPath)
 	}
 
 	var totalWritten int64
 	// Current file offset.
-	var currentOffset = partSt.Size()
+	var currentOffset = partFile.offset
 
 	// Verify if incoming reader implements ReaderAt.  Standard IO streams are excluded since their ReadAt() return illegal seek error
 	if readerAt, ok := reader.(io.ReaderAt); ok && !isStdIO(reader) {
@@ -255,6 +278,9 @@ func (f *fsClient) Put(reader io.Reader, size int64, contentType string, progres
 		// Loop through all offsets on incoming io.ReaderAt and write
 		// to the destination.
 		for {
+			if e = ctx.Err(); e != nil {
+				return 0, probe.NewError(e)
+			}
 			readAtSize, re := readerAt.ReadAt(readAtBuffer, currentOffset)
 			if re != nil && re != io.EOF {
 				// For any errors other than io.EOF, we return error
@@ -291,7 +317,7 @@ func (f *fsClient) Put(reader io.Reader, size int64, contentType string, progres
 		// Save currently copied total into totalWritten.
 		totalWritten = currentOffset
 	} else {
-		reader = hookreader.NewHook(reader, progress)
+		reader = newContextReader(ctx, hookreader.NewHook(reader, progress))
 		// Discard bytes until currentOffset.
 		if _, e = io.CopyN(ioutil.Discard, reader, currentOffset); e != nil {
 			return 0, probe.NewError(e)
@@ -344,7 +370,7 @@ func (f *fsClient) Put(reader io.Reader, size int64, contentType string, progres
 }
 
 // ShareDownload - share download not implemented for filesystem.
-func (f *fsClient) ShareDownload(expires time.Duration) (string, *probe.Error) {
+func (f *fsClient) ShareDownload(expires time.Duration, reqParams url.Values) (string, *probe.Error) {
 	return "", probe.NewError(APINotImplemented{
 		API:     "ShareDownload",
 		APIType: "filesystem",
@@ -352,13 +378,91 @@ func (f *fsClient) ShareDownload(expires time.Duration) (string, *probe.Error) {
 }
 
 // ShareUpload - share upload not implemented for filesystem.
-func (f *fsClient) ShareUpload(startsWith bool, expires time.Duration, contentType string) (map[string]string, *probe.Error) {
+func (f *fsClient) ShareUpload(opts ShareUploadOpts) (map[string]string, *probe.Error) {
 	return nil, probe.NewError(APINotImplemented{
 		API:     "ShareUpload",
 		APIType: "filesystem",
 	})
 }
 
+// ShareUploadURL - share upload not implemented for filesystem.
+func (f *fsClient) ShareUploadURL(opts ShareUploadOpts) (string, *probe.Error) {
+	return "", probe.NewError(APINotImplemented{
+		API:     "ShareUploadURL",
+		APIType: "filesystem",
+	})
+}
+
+// ListVersions - versioning not implemented for filesystem.
+func (f *fsClient) ListVersions(recursive bool) <-chan *ClientContent {
+	contentCh := make(chan *ClientContent, 1)
+	contentCh <- &ClientContent{
+		Err: probe.NewError(APINotImplemented{
+			API:     "ListVersions",
+			APIType: "filesystem",
+		}),
+	}
+	close(contentCh)
+	return contentCh
+}
+
+// RestoreObject - archiving not implemented for filesystem.
+func (f *fsClient) RestoreObject(days int, tier string) *probe.Error {
+	return probe.NewError(APINotImplemented{
+		API:     "RestoreObject",
+		APIType: "filesystem",
+	})
+}
+
+// RestoreStatus - archiving not implemented for filesystem.
+func (f *fsClient) RestoreStatus(sse EncryptOpts) (ObjectRestoreStatus, *probe.Error) {
+	return ObjectRestoreStatus{}, probe.NewError(APINotImplemented{
+		API:     "RestoreStatus",
+		APIType: "filesystem",
+	})
+}
+
+// Checksum - ETag the file has once uploaded in parts of the given
+// size, a negative or zero size hashes the file as a single part.
+func (f *fsClient) Checksum(partSize int64) (string, int64, *probe.Error) {
+	if partSize < 0 {
+		partSize = 0
+	}
+	reader, err := f.Get(context.Background(), GetOpts{})
+	if err != nil {
+		return "", 0, err.Trace(f.PathURL.Path)
+	}
+	etag, err := readETag(reader, partSize)
+	if err != nil {
+		return "", 0, err.Trace(f.PathURL.Path)
+	}
+	return etag, partSize, nil
+}
+
+// GetVersion - versioning not implemented for filesystem.
+func (f *fsClient) GetVersion(versionID string) (io.Reader, *probe.Error) {
+	return nil, probe.NewError(APINotImplemented{
+		API:     "GetVersion",
+		APIType: "filesystem",
+	})
+}
+
+// SelectObjectContent - queries not implemented for filesystem.
+func (f *fsClient) SelectObjectContent(expression string, sse EncryptOpts, opts SelectObjectOpts) (io.ReadCloser, *probe.Error) {
+	return nil, probe.NewError(APINotImplemented{
+		API:     "SelectObjectContent",
+		APIType: "filesystem",
+	})
+}
+
+// RemoveVersion - versioning not implemented for filesystem.
+func (f *fsClient) RemoveVersion(versionID string) *probe.Error {
+	return probe.NewError(APINotImplemented{
+		API:     "RemoveVersion",
+		APIType: "filesystem",
+	})
+}
+
 // readFile reads and returns the data inside the file located
 // at the provided filepath.
 func readFile(fpath string) (io.ReadCloser, error) {
@@ -400,13 +504,56 @@ func createFile(fpath string) (io.WriteCloser, error) {
 	return file, nil
 }
 
+// copyFileInKernel - copies the source to the target without reading
+// it, by cloning it on filesystems with reflinks or else by copying it
+// within the kernel. Reports whether the file was copied, files which
+// can't be copied this way are left untouched to be read and written.
+func copyFileInKernel(target, source *os.File, progress io.Reader) (int64, bool, error) {
+	report := func(n int64) error {
+		if progress == nil {
+			return nil
+		}
+		_, e := io.CopyN(ioutil.Discard, progress, n)
+		return e
+	}
+	if cloneFile(target, source) == nil {
+		st, e := source.Stat()
+		if e != nil {
+			return 0, true, e
+		}
+		return st.Size(), true, report(st.Size())
+	}
+	n, e := sendFile(target, source, report)
+	if e != nil && n == 0 {
+		return 0, false, nil
+	}
+	return n, true, e
+}
+
 // Copy - copy data from source to destination
-func (f *fsClient) Copy(source string, size int64, progress io.Reader) *probe.Error {
+func (f *fsClient) Copy(ctx context.Context, source string, size int64, progress io.Reader, opts CopyOpts) *probe.Error {
 	// Don't use f.Get() f.Put() directly. Instead use readFile and createFile
 	destination := f.PathURL.Path
 	if destination == source { // Cannot copy file into itself
 		return errOverWriteNotAllowed(destination).Trace(destination)
 	}
+	// Files have no ETags, only the modification time can be checked.
+	if opts.IfMatch != "" || opts.IfNoneMatch != "" {
+		return probe.NewError(APINotImplemented{
+			API:     "CopyConditions",
+			APIType: "filesystem",
+		})
+	}
+	if !opts.IfUnmodifiedSince.IsZero() {
+		st, e := os.Stat(source)
+		if e != nil {
+			err := f.toClientError(e, source)
+			return err.Trace(source)
+		}
+		if st.ModTime().After(opts.IfUnmodifiedSince) {
+			return probe.NewError(ObjectPreconditionFailed{Object: source})
+		}
+	}
 	rc, e := readFile(source)
 	if e != nil {
 		err := f.toClientError(e, destination)
@@ -419,9 +566,16 @@ func (f *fsClient) Copy(source string, size int64, progress io.Reader) *probe.Er
 		return err.Trace(destination)
 	}
 	defer wc.Close()
-	reader := hookreader.NewHook(rc, progress)
-	// Perform copy
-	n, _ := io.CopyN(wc, reader, size) // e == nil only if n != size
+	n, copied, e := copyFileInKernel(wc.(*os.File), rc.(*os.File), progress)
+	if e != nil {
+		err := f.toClientError(e, destination)
+		return err.Trace(destination)
+	}
+	if !copied {
+		reader := newContextReader(ctx, hookreader.NewHook(rc, progress))
+		// Perform copy
+		n, _ = io.CopyN(wc, reader, size) // e == nil only if n != size
+	}
 	// Only check size related errors if size is positive
 	if size > 0 {
 		if n < size { // Unexpected early EOF
@@ -442,7 +596,10 @@ func (f *fsClient) Copy(source string, size int64, progress io.Reader) *probe.Er
 
 // GetPartial download a part object from bucket.
 // sets err for any errors, reader is nil for errors.
-func (f *fsClient) Get() (io.Reader, *probe.Error) {
+func (f *fsClient) Get(ctx context.Context, opts GetOpts) (io.Reader, *probe.Error) {
+	if e := ctx.Err(); e != nil {
+		return nil, probe.NewError(e).Trace(f.PathURL.Path)
+	}
 	tmppath := f.PathURL.Path
 	// Golang strips trailing / if you clean(..) or
 	// EvalSymlinks(..). Adding '.' prevents it from doing so.
@@ -461,21 +618,63 @@ func (f *fsClient) Get() (io.Reader, *probe.Error) {
 		err := f.toClientError(e, f.PathURL.Path)
 		return nil, err.Trace(f.PathURL.Path)
 	}
+	if opts.Offset > 0 {
+		// Ranges starting at or beyond the end are invalid, as on S3.
+		st, e := fileData.Stat()
+		if e != nil {
+			fileData.Close()
+			err := f.toClientError(e, f.PathURL.Path)
+			return nil, err.Trace(f.PathURL.Path)
+		}
+		if opts.Offset >= st.Size() {
+			fileData.Close()
+			return nil, probe.NewError(ObjectRangeInvalid{Object: f.PathURL.String(), Offset: opts.Offset})
+		}
+		if _, e = fileData.Seek(opts.Offset, os.SEEK_SET); e != nil {
+			fileData.Close()
+			err := f.toClientError(e, f.PathURL.Path)
+			return nil, err.Trace(f.PathURL.Path)
+		}
+	}
+	if opts.Length > 0 {
+		return io.LimitReader(fileData, opts.Length), nil
+	}
 	return fileData, nil
 }
 
 // Remove - remove the path.
-func (f *fsClient) Remove(incomplete bool) *probe.Error {
+func (f *fsClient) Remove(ctx context.Context, incomplete bool) *probe.Error {
+	if e := ctx.Err(); e != nil {
+		return probe.NewError(e).Trace(f.PathURL.Path)
+	}
 	e := os.Remove(f.PathURL.Path)
 	err := f.toClientError(e, f.PathURL.Path)
 	return err.Trace(f.PathURL.Path)
 }
 
+// RemoveBulk - removes the files read from the channel one by one, in
+// order so that folders are removed after their contents.
+func (f *fsClient) RemoveBulk(contentCh <-chan *ClientContent) <-chan *ClientContent {
+	resultCh := make(chan *ClientContent)
+	go func() {
+		defer close(resultCh)
+		for content := range contentCh {
+			removed := &ClientContent{URL: content.URL}
+			if e := os.Remove(content.URL.Path); e != nil {
+				err := f.toClientError(e, content.URL.Path)
+				removed.Err = err.Trace(content.URL.Path)
+			}
+			resultCh <- removed
+		}
+	}()
+	return resultCh
+}
+
 // List - list files and folders.
-func (f *fsClient) List(recursive, incomplete bool) <-chan *clientContent {
-	contentCh := make(chan *clientContent)
+func (f *fsClient) List(ctx context.Context, recursive, incomplete bool) <-chan *ClientContent {
+	contentCh := make(chan *ClientContent)
 	if recursive {
-		go f.listRecursiveInRoutine(contentCh, incomplete)
+		go f.listRecursiveInRoutine(ctx, contentCh, incomplete)
 	} else {
 		go f.listInRoutine(contentCh, incomplete)
 	}
@@ -520,12 +719,12 @@ func readDir(dirname string) ([]os.FileInfo, error) {
 }
 
 // listPrefixes - list all files for any given prefix.
-func (f *fsClient) listPrefixes(prefix string, contentCh chan<- *clientContent, incomplete bool) {
+func (f *fsClient) listPrefixes(prefix string, contentCh chan<- *ClientContent, incomplete bool) {
 	dirName := filepath.Dir(prefix)
 	files, e := readDir(dirName)
 	if e != nil {
 		err := f.toClientError(e, dirName)
-		contentCh <- &clientContent{
+		contentCh <- &ClientContent{
 			Err: err.Trace(dirName),
 		}
 		return
@@ -539,6 +738,12 @@ func (f *fsClient) listPrefixes(prefix string, contentCh chan<- *clientContent,
 
 		file := filepath.Join(dirName, fi.Name())
 		if fi.Mode()&os.ModeSymlink == os.ModeSymlink {
+			if !strings.HasPrefix(file, prefix) {
+				continue
+			}
+			if listSymlink(*newClientURL(file), file, fi, incomplete, contentCh) {
+				continue
+			}
 			st, e := os.Stat(file)
 			if e != nil {
 				if os.IsPermission(e) {
@@ -550,7 +755,7 @@ func (f *fsClient) listPrefixes(prefix string, contentCh chan<- *clientContent,
 						newPath := filepath.Join(prefix, fi.Name())
 						lfi, le := f.handleWindowsSymlinks(newPath)
 						if le != nil {
-							contentCh <- &clientContent{
+							contentCh <- &ClientContent{
 								Err: le.Trace(newPath),
 							}
 							continue
@@ -565,7 +770,7 @@ func (f *fsClient) listPrefixes(prefix string, contentCh chan<- *clientContent,
 							}
 						}
 						pathURL.Path = filepath.Join(pathURL.Path, lfi.Name())
-						contentCh <- &clientContent{
+						contentCh <- &ClientContent{
 							URL:  pathURL,
 							Time: lfi.ModTime(),
 							Size: lfi.Size(),
@@ -576,7 +781,7 @@ func (f *fsClient) listPrefixes(prefix string, contentCh chan<- *clientContent,
 						}
 						continue
 					} else {
-						contentCh <- &clientContent{
+						contentCh <- &ClientContent{
 							Err: probe.NewError(PathInsufficientPermission{
 								Path: pathURL.Path,
 							}),
@@ -585,7 +790,7 @@ func (f *fsClient) listPrefixes(prefix string, contentCh chan<- *clientContent,
 					}
 				}
 				if os.IsNotExist(e) {
-					contentCh <- &clientContent{
+					contentCh <- &ClientContent{
 						Err: probe.NewError(BrokenSymlink{
 							Path: pathURL.Path,
 						}),
@@ -593,7 +798,7 @@ func (f *fsClient) listPrefixes(prefix string, contentCh chan<- *clientContent,
 					continue
 				}
 				if e != nil {
-					contentCh <- &clientContent{
+					contentCh <- &ClientContent{
 						Err: probe.NewError(e),
 					}
 					continue
@@ -609,7 +814,7 @@ func (f *fsClient) listPrefixes(prefix string, contentCh chan<- *clientContent,
 						continue
 					}
 				}
-				contentCh <- &clientContent{
+				contentCh <- &ClientContent{
 					URL:  *newClientURL(file),
 					Time: st.ModTime(),
 					Size: st.Size(),
@@ -629,7 +834,7 @@ func (f *fsClient) listPrefixes(prefix string, contentCh chan<- *clientContent,
 					continue
 				}
 			}
-			contentCh <- &clientContent{
+			contentCh <- &ClientContent{
 				URL:  *newClientURL(file),
 				Time: fi.ModTime(),
 				Size: fi.Size(),
@@ -641,7 +846,7 @@ func (f *fsClient) listPrefixes(prefix string, contentCh chan<- *clientContent,
 	return
 }
 
-func (f *fsClient) listInRoutine(contentCh chan<- *clientContent, incomplete bool) {
+func (f *fsClient) listInRoutine(contentCh chan<- *ClientContent, incomplete bool) {
 	// close the channel when the function returns.
 	defer close(contentCh)
 
@@ -658,7 +863,7 @@ func (f *fsClient) listInRoutine(contentCh chan<- *clientContent, incomplete boo
 			return
 		}
 		// For all other errors we return genuine error back to the caller.
-		contentCh <- &clientContent{Err: err.Trace(fpath)}
+		contentCh <- &ClientContent{Err: err.Trace(fpath)}
 		return
 	}
 
@@ -674,12 +879,17 @@ func (f *fsClient) listInRoutine(contentCh chan<- *clientContent, incomplete boo
 	case true:
 		files, e := readDir(fpath)
 		if err != nil {
-			contentCh <- &clientContent{Err: probe.NewError(e)}
+			contentCh <- &ClientContent{Err: probe.NewError(e)}
 			return
 		}
 		for _, file := range files {
 			fi := file
 			if fi.Mode()&os.ModeSymlink == os.ModeSymlink {
+				pathURL = *f.PathURL
+				pathURL.Path = filepath.Join(pathURL.Path, fi.Name())
+				if listSymlink(pathURL, filepath.Join(fpath, fi.Name()), fi, incomplete, contentCh) {
+					continue
+				}
 				fi, e = os.Stat(filepath.Join(fpath, fi.Name()))
 				if os.IsPermission(e) {
 					// On windows there are folder symlinks
@@ -690,7 +900,7 @@ func (f *fsClient) listInRoutine(contentCh chan<- *clientContent, incomplete boo
 						newPath := filepath.Join(fpath, fi.Name())
 						lfi, le := f.handleWindowsSymlinks(newPath)
 						if le != nil {
-							contentCh <- &clientContent{
+							contentCh <- &ClientContent{
 								Err: le.Trace(newPath),
 							}
 							continue
@@ -706,7 +916,7 @@ func (f *fsClient) listInRoutine(contentCh chan<- *clientContent, incomplete boo
 						}
 						pathURL = *f.PathURL
 						pathURL.Path = filepath.Join(pathURL.Path, lfi.Name())
-						contentCh <- &clientContent{
+						contentCh <- &ClientContent{
 							URL:  pathURL,
 							Time: lfi.ModTime(),
 							Size: lfi.Size(),
@@ -715,20 +925,20 @@ func (f *fsClient) listInRoutine(contentCh chan<- *clientContent, incomplete boo
 						}
 						continue
 					} else {
-						contentCh <- &clientContent{
+						contentCh <- &ClientContent{
 							Err: probe.NewError(PathInsufficientPermission{Path: pathURL.Path}),
 						}
 						continue
 					}
 				}
 				if os.IsNotExist(e) {
-					contentCh <- &clientContent{
+					contentCh <- &ClientContent{
 						Err: probe.NewError(BrokenSymlink{Path: file.Name()}),
 					}
 					continue
 				}
 				if e != nil {
-					contentCh <- &clientContent{
+					contentCh <- &ClientContent{
 						Err: probe.NewError(e),
 					}
 					continue
@@ -752,7 +962,7 @@ func (f *fsClient) listInRoutine(contentCh chan<- *clientContent, incomplete boo
 					continue
 				}
 
-				contentCh <- &clientContent{
+				contentCh <- &ClientContent{
 					URL:  pathURL,
 					Time: fi.ModTime(),
 					Size: fi.Size(),
@@ -771,7 +981,7 @@ func (f *fsClient) listInRoutine(contentCh chan<- *clientContent, incomplete boo
 				return
 			}
 		}
-		contentCh <- &clientContent{
+		contentCh <- &ClientContent{
 			URL:  pathURL,
 			Time: fst.ModTime(),
 			Size: fst.Size(),
@@ -781,13 +991,21 @@ func (f *fsClient) listInRoutine(contentCh chan<- *clientContent, incomplete boo
 	}
 }
 
-func (f *fsClient) listRecursiveInRoutine(contentCh chan *clientContent, incomplete bool) {
+func (f *fsClient) listRecursiveInRoutine(ctx context.Context, contentCh chan *ClientContent, incomplete bool) {
 	// close channels upon return.
 	defer close(contentCh)
 	var dirName string
 	var filePrefix string
 	pathURL := *f.PathURL
-	visitFS := func(fp string, fi os.FileInfo, e error) error {
+	// Real paths of the listed folder and of the folders behind
+	// followed links being listed, to detect links looping back.
+	visited := make(map[string]bool)
+	var visitFS func(fp string, fi os.FileInfo, e error) error
+	visitFS = func(fp string, fi os.FileInfo, e error) error {
+		// Walks stop once the context is done.
+		if ce := ctx.Err(); ce != nil {
+			return ce
+		}
 		// If file path ends with filepath.Separator and equals to root path, skip it.
 		if strings.HasSuffix(fp, string(pathURL.Separator)) {
 			if fp == dirName {
@@ -829,7 +1047,7 @@ func (f *fsClient) listRecursiveInRoutine(contentCh chan *clientContent, incompl
 		if e != nil {
 			// If operation is not permitted, we throw quickly back.
 			if strings.Contains(e.Error(), "operation not permitted") {
-				contentCh <- &clientContent{
+				contentCh <- &ClientContent{
 					Err: probe.NewError(e),
 				}
 				return nil
@@ -838,7 +1056,7 @@ func (f *fsClient) listRecursiveInRoutine(contentCh chan *clientContent, incompl
 				if runtime.GOOS == "windows" {
 					lfi, le := f.handleWindowsSymlinks(fp)
 					if le != nil {
-						contentCh <- &clientContent{
+						contentCh <- &ClientContent{
 							Err: le.Trace(fp),
 						}
 						return nil
@@ -854,7 +1072,7 @@ func (f *fsClient) listRecursiveInRoutine(contentCh chan *clientContent, incompl
 							return nil
 						}
 					}
-					contentCh <- &clientContent{
+					contentCh <- &ClientContent{
 						URL:  pathURL,
 						Time: lfi.ModTime(),
 						Size: lfi.Size(),
@@ -862,7 +1080,7 @@ func (f *fsClient) listRecursiveInRoutine(contentCh chan *clientContent, incompl
 						Err:  probe.NewError(e),
 					}
 				} else {
-					contentCh <- &clientContent{
+					contentCh <- &ClientContent{
 						Err: probe.NewError(PathInsufficientPermission{Path: fp}),
 					}
 				}
@@ -871,13 +1089,16 @@ func (f *fsClient) listRecursiveInRoutine(contentCh chan *clientContent, incompl
 			return e
 		}
 		if fi.Mode()&os.ModeSymlink == os.ModeSymlink {
+			if listSymlink(*newClientURL(fp), fp, fi, incomplete, contentCh) {
+				return nil
+			}
 			fi, e = os.Stat(fp)
 			if e != nil {
 				if os.IsPermission(e) {
 					if runtime.GOOS == "windows" {
 						lfi, le := f.handleWindowsSymlinks(fp)
 						if le != nil {
-							contentCh <- &clientContent{
+							contentCh <- &ClientContent{
 								Err: le.Trace(fp),
 							}
 							return nil
@@ -893,7 +1114,7 @@ func (f *fsClient) listRecursiveInRoutine(contentCh chan *clientContent, incompl
 								return nil
 							}
 						}
-						contentCh <- &clientContent{
+						contentCh <- &ClientContent{
 							URL:  pathURL,
 							Time: lfi.ModTime(),
 							Size: lfi.Size(),
@@ -902,27 +1123,30 @@ func (f *fsClient) listRecursiveInRoutine(contentCh chan *clientContent, incompl
 						}
 						return nil
 					}
-					contentCh <- &clientContent{
+					contentCh <- &ClientContent{
 						Err: probe.NewError(e),
 					}
 					return nil
 				}
 				// Ignore in-accessible broken symlinks.
 				if os.IsNotExist(e) {
-					contentCh <- &clientContent{
+					contentCh <- &ClientContent{
 						Err: probe.NewError(BrokenSymlink{Path: fp}),
 					}
 					return nil
 				}
 				// Ignore symlink loops.
 				if strings.Contains(e.Error(), "too many levels of symbolic links") {
-					contentCh <- &clientContent{
+					contentCh <- &ClientContent{
 						Err: probe.NewError(TooManyLevelsSymlink{Path: fp}),
 					}
 					return nil
 				}
 				return e
 			}
+			if fi.IsDir() && fsSymlinks == symlinksFollow {
+				return followSymlink(fp, visited, contentCh, visitFS)
+			}
 		}
 		if fi.Mode().IsRegular() {
 			if incomplete {
@@ -934,7 +1158,7 @@ func (f *fsClient) listRecursiveInRoutine(contentCh chan *clientContent, incompl
 					return nil
 				}
 			}
-			contentCh <- &clientContent{
+			contentCh <- &ClientContent{
 				URL:  *newClientURL(fp),
 				Time: fi.ModTime(),
 				Size: fi.Size(),
@@ -960,10 +1184,13 @@ func (f *fsClient) listRecursiveInRoutine(contentCh chan *clientContent, incompl
 		// filePrefix is kept for filtering incoming contents through WalkFunc.
 		filePrefix = pathURL.Path
 	}
+	if realPath, e := filepath.EvalSymlinks(dirName); e == nil {
+		visited[realPath] = true
+	}
 	// walks invokes our custom function.
 	e := ioutils.FTW(dirName, visitFS)
 	if e != nil {
-		contentCh <- &clientContent{
+		contentCh <- &ClientContent{
 			Err: probe.NewError(e),
 		}
 	}
@@ -1042,12 +1269,12 @@ func (f *fsClient) SetAccess(access string) *probe.Error {
 }
 
 // Stat - get metadata from path.
-func (f *fsClient) Stat() (content *clientContent, err *probe.Error) {
+func (f *fsClient) Stat() (content *ClientContent, err *probe.Error) {
 	st, err := f.fsStat()
 	if err != nil {
 		return nil, err.Trace(f.PathURL.String())
 	}
-	content = &clientContent{}
+	content = &ClientContent{}
 	content.URL = *f.PathURL
 	content.Size = st.Size()
 	content.Time = st.ModTime()
@@ -1066,6 +1293,61 @@ func (f *fsClient) toClientError(e error, fpath string) *probe.Error {
 	return probe.NewError(e)
 }
 
+// listSymlink - lists a link by the symlink mode, reports whether the
+// link was handled and the file or folder behind it is not listed.
+func listSymlink(pathURL ClientURL, fpath string, fi os.FileInfo, incomplete bool, contentCh chan<- *ClientContent) bool {
+	switch fsSymlinks {
+	case symlinksSkip:
+		return true
+	case symlinksPreserve:
+		if incomplete {
+			return true
+		}
+		target, e := os.Readlink(fpath)
+		if e != nil {
+			contentCh <- &ClientContent{Err: probe.NewError(e).Trace(fpath)}
+			return true
+		}
+		// Links are listed as empty files.
+		contentCh <- &ClientContent{
+			URL:     pathURL,
+			Time:    fi.ModTime(),
+			Type:    fi.Mode() &^ os.ModeType,
+			Symlink: target,
+		}
+		return true
+	}
+	return false
+}
+
+// followSymlink - lists the folder behind a link as if it was at the
+// path of the link. Links to a folder being listed are reported.
+func followSymlink(fp string, visited map[string]bool, contentCh chan<- *ClientContent, visitFS ioutils.FTWFunc) error {
+	realPath, e := filepath.EvalSymlinks(fp)
+	if e != nil {
+		contentCh <- &ClientContent{Err: probe.NewError(e).Trace(fp)}
+		return nil
+	}
+	isLoop := visited[realPath]
+	// Links to a parent folder loop as well.
+	if realParent, e := filepath.EvalSymlinks(filepath.Dir(fp)); e == nil {
+		sep := string(os.PathSeparator)
+		isLoop = isLoop || strings.HasPrefix(realParent+sep, strings.TrimSuffix(realPath, sep)+sep)
+	}
+	if isLoop {
+		contentCh <- &ClientContent{Err: probe.NewError(TooManyLevelsSymlink{Path: fp})}
+		return nil
+	}
+	visited[realPath] = true
+	defer delete(visited, realPath)
+	return ioutils.FTW(realPath, func(p string, fi os.FileInfo, e error) error {
+		if p == realPath {
+			return nil
+		}
+		return visitFS(fp+strings.TrimPrefix(p, realPath), fi, e)
+	})
+}
+
 // handle windows symlinks - eg: junction files.
 func (f *fsClient) handleWindowsSymlinks(fpath string) (os.FileInfo, *probe.Error) {
 	// On windows there are directory symlinks which are called junction files.
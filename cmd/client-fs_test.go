@@ -18,11 +18,13 @@ package cmd
 
 import (
 	"bytes"
+	"context"
 	"io"
 	"io/ioutil"
 	"os"
 	"path/filepath"
 	"runtime"
+	"time"
 
 	. "gopkg.in/check.v1"
 )
@@ -42,7 +44,7 @@ func (s *TestSuite) TestList(c *C) {
 
 	reader := bytes.NewReader([]byte(data))
 	var n int64
-	n, err = fsClient.Put(reader, int64(len(data)), "application/octet-stream", nil)
+	n, err = fsClient.Put(context.Background(), reader, int64(len(data)), "application/octet-stream", nil, PutOpts{})
 	c.Assert(err, IsNil)
 	c.Assert(n, Equals, int64(len(data)))
 
@@ -51,7 +53,7 @@ func (s *TestSuite) TestList(c *C) {
 	c.Assert(err, IsNil)
 
 	reader = bytes.NewReader([]byte(data))
-	n, err = fsClient.Put(reader, int64(len(data)), "application/octet-stream", nil)
+	n, err = fsClient.Put(context.Background(), reader, int64(len(data)), "application/octet-stream", nil, PutOpts{})
 	c.Assert(err, IsNil)
 	c.Assert(n, Equals, int64(len(data)))
 
@@ -59,8 +61,8 @@ func (s *TestSuite) TestList(c *C) {
 	c.Assert(err, IsNil)
 
 	// Verify previously create files and list them.
-	var contents []*clientContent
-	for content := range fsClient.List(false, false) {
+	var contents []*ClientContent
+	for content := range fsClient.List(context.Background(), false, false) {
 		if content.Err != nil {
 			err = content.Err
 			break
@@ -77,7 +79,7 @@ func (s *TestSuite) TestList(c *C) {
 	c.Assert(err, IsNil)
 
 	reader = bytes.NewReader([]byte(data))
-	n, err = fsClient.Put(reader, int64(len(data)), "application/octet-stream", nil)
+	n, err = fsClient.Put(context.Background(), reader, int64(len(data)), "application/octet-stream", nil, PutOpts{})
 	c.Assert(err, IsNil)
 	c.Assert(n, Equals, int64(len(data)))
 
@@ -86,7 +88,7 @@ func (s *TestSuite) TestList(c *C) {
 
 	contents = nil
 	// List non recursive to list only top level files.
-	for content := range fsClient.List(false, false) {
+	for content := range fsClient.List(context.Background(), false, false) {
 		if content.Err != nil {
 			err = content.Err
 			break
@@ -102,7 +104,7 @@ func (s *TestSuite) TestList(c *C) {
 
 	contents = nil
 	// List recursively all files and verify.
-	for content := range fsClient.List(true, false) {
+	for content := range fsClient.List(context.Background(), true, false) {
 		if content.Err != nil {
 			err = content.Err
 			break
@@ -135,7 +137,7 @@ func (s *TestSuite) TestList(c *C) {
 	c.Assert(err, IsNil)
 
 	reader = bytes.NewReader([]byte(data))
-	n, err = fsClient.Put(reader, int64(len(data)), "application/octet-stream", nil)
+	n, err = fsClient.Put(context.Background(), reader, int64(len(data)), "application/octet-stream", nil, PutOpts{})
 	c.Assert(err, IsNil)
 	c.Assert(n, Equals, int64(len(data)))
 
@@ -144,7 +146,7 @@ func (s *TestSuite) TestList(c *C) {
 
 	contents = nil
 	// List recursively all files and verify.
-	for content := range fsClient.List(true, false) {
+	for content := range fsClient.List(context.Background(), true, false) {
 		if content.Err != nil {
 			err = content.Err
 			break
@@ -240,7 +242,7 @@ func (s *TestSuite) TestPut(c *C) {
 	data := "hello"
 	reader := bytes.NewReader([]byte(data))
 	var n int64
-	n, err = fsClient.Put(reader, int64(len(data)), "application/octet-stream", nil)
+	n, err = fsClient.Put(context.Background(), reader, int64(len(data)), "application/octet-stream", nil, PutOpts{})
 	c.Assert(err, IsNil)
 	c.Assert(n, Equals, int64(len(data)))
 }
@@ -258,11 +260,11 @@ func (s *TestSuite) TestGet(c *C) {
 	data := "hello"
 	var reader io.Reader
 	reader = bytes.NewReader([]byte(data))
-	n, err := fsClient.Put(reader, int64(len(data)), "application/octet-stream", nil)
+	n, err := fsClient.Put(context.Background(), reader, int64(len(data)), "application/octet-stream", nil, PutOpts{})
 	c.Assert(err, IsNil)
 	c.Assert(n, Equals, int64(len(data)))
 
-	reader, err = fsClient.Get()
+	reader, err = fsClient.Get(context.Background(), GetOpts{})
 	c.Assert(err, IsNil)
 	var results bytes.Buffer
 	_, e = io.Copy(&results, reader)
@@ -284,11 +286,11 @@ func (s *TestSuite) TestGetRange(c *C) {
 	data := "hello world"
 	var reader io.Reader
 	reader = bytes.NewReader([]byte(data))
-	n, err := fsClient.Put(reader, int64(len(data)), "application/octet-stream", nil)
+	n, err := fsClient.Put(context.Background(), reader, int64(len(data)), "application/octet-stream", nil, PutOpts{})
 	c.Assert(err, IsNil)
 	c.Assert(n, Equals, int64(len(data)))
 
-	reader, err = fsClient.Get()
+	reader, err = fsClient.Get(context.Background(), GetOpts{})
 	c.Assert(err, IsNil)
 	var results bytes.Buffer
 	buf := make([]byte, 5)
@@ -298,6 +300,19 @@ func (s *TestSuite) TestGetRange(c *C) {
 	_, e = results.Write(buf)
 	c.Assert(e, IsNil)
 	c.Assert([]byte("hello"), DeepEquals, results.Bytes())
+
+	reader, err = fsClient.Get(context.Background(), GetOpts{Offset: 6, Length: 3})
+	c.Assert(err, IsNil)
+	results.Reset()
+	_, e = io.Copy(&results, reader)
+	c.Assert(e, IsNil)
+	c.Assert([]byte("wor"), DeepEquals, results.Bytes())
+
+	// Ranges beyond the end fail like on object storage.
+	_, err = fsClient.Get(context.Background(), GetOpts{Offset: int64(len(data))})
+	c.Assert(err, NotNil)
+	_, ok := err.ToGoError().(ObjectRangeInvalid)
+	c.Assert(ok, Equals, true)
 }
 
 // Test stat file.
@@ -313,7 +328,7 @@ func (s *TestSuite) TestStatObject(c *C) {
 	data := "hello"
 	dataLen := len(data)
 	reader := bytes.NewReader([]byte(data))
-	n, err := fsClient.Put(reader, int64(dataLen), "application/octet-stream", nil)
+	n, err := fsClient.Put(context.Background(), reader, int64(dataLen), "application/octet-stream", nil, PutOpts{})
 	c.Assert(err, IsNil)
 	c.Assert(n, Equals, int64(len(data)))
 
@@ -337,10 +352,166 @@ func (s *TestSuite) TestCopy(c *C) {
 	data := "hello world"
 	var reader io.Reader
 	reader = bytes.NewReader([]byte(data))
-	n, err := fsClientSource.Put(reader, int64(len(data)), "application/octet-stream", nil)
+	n, err := fsClientSource.Put(context.Background(), reader, int64(len(data)), "application/octet-stream", nil, PutOpts{})
 	c.Assert(err, IsNil)
 	c.Assert(n, Equals, int64(len(data)))
 
-	err = fsClientTarget.Copy(sourcePath, int64(len(data)), nil)
+	// Files are copied within the kernel where possible, the progress
+	// is reported all the same.
+	progress := newAccounter(int64(len(data)))
+	err = fsClientTarget.Copy(context.Background(), sourcePath, int64(len(data)), progress, CopyOpts{})
 	c.Assert(err, IsNil)
+	c.Assert(progress.Stat().Transferred, Equals, int64(len(data)))
+	copied, e := ioutil.ReadFile(targetPath)
+	c.Assert(e, IsNil)
+	c.Assert(string(copied), Equals, data)
+
+	// Source was modified after the given time.
+	err = fsClientTarget.Copy(context.Background(), sourcePath, int64(len(data)), nil, CopyOpts{IfUnmodifiedSince: time.Now().Add(-time.Hour)})
+	c.Assert(err, Not(IsNil))
+	_, ok := err.ToGoError().(ObjectPreconditionFailed)
+	c.Assert(ok, Equals, true)
+
+	err = fsClientTarget.Copy(context.Background(), sourcePath, int64(len(data)), nil, CopyOpts{IfUnmodifiedSince: time.Now().Add(time.Hour)})
+	c.Assert(err, IsNil)
+}
+
+// Test removal of files in bulk.
+func (s *TestSuite) TestRemoveBulk(c *C) {
+	root, e := ioutil.TempDir(os.TempDir(), "fs-")
+	c.Assert(e, IsNil)
+	defer os.RemoveAll(root)
+
+	data := "hello"
+	for _, name := range []string{"dir/object1", "dir/object2"} {
+		fsClient, err := fsNew(filepath.Join(root, name))
+		c.Assert(err, IsNil)
+		_, err = fsClient.Put(context.Background(), bytes.NewReader([]byte(data)), int64(len(data)), "application/octet-stream", nil, PutOpts{})
+		c.Assert(err, IsNil)
+	}
+
+	fsClient, err := fsNew(root)
+	c.Assert(err, IsNil)
+	contentCh := make(chan *ClientContent, 4)
+	for _, name := range []string{"dir/object1", "dir/object2", "dir", "missing"} {
+		contentCh <- &ClientContent{URL: *newClientURL(filepath.Join(root, name))}
+	}
+	close(contentCh)
+
+	var failed []string
+	for result := range fsClient.RemoveBulk(contentCh) {
+		if result.Err != nil {
+			failed = append(failed, filepath.Base(result.URL.Path))
+		}
+	}
+	c.Assert(failed, DeepEquals, []string{"missing"})
+	_, e = os.Stat(filepath.Join(root, "dir"))
+	c.Assert(os.IsNotExist(e), Equals, true)
+}
+
+// Test listing links by the symlink mode.
+func (s *TestSuite) TestListSymlinks(c *C) {
+	if runtime.GOOS == "windows" {
+		c.Skip("Links need privileges on windows.")
+	}
+	root, e := ioutil.TempDir(os.TempDir(), "fs-")
+	c.Assert(e, IsNil)
+	defer os.RemoveAll(root)
+	defer func() { fsSymlinks = symlinksDefault }()
+
+	src := filepath.Join(root, "src")
+	c.Assert(os.MkdirAll(filepath.Join(src, "dir"), 0700), IsNil)
+	c.Assert(os.MkdirAll(filepath.Join(root, "other"), 0700), IsNil)
+	c.Assert(ioutil.WriteFile(filepath.Join(src, "file"), []byte("hello"), 0600), IsNil)
+	c.Assert(ioutil.WriteFile(filepath.Join(root, "other", "object"), []byte("hello"), 0600), IsNil)
+	c.Assert(os.Symlink("file", filepath.Join(src, "file-link")), IsNil)
+	c.Assert(os.Symlink(filepath.Join("..", "other"), filepath.Join(src, "link")), IsNil)
+	c.Assert(os.Symlink("..", filepath.Join(src, "dir", "loop")), IsNil)
+
+	list := func(mode symlinkMode) (names []string, symlinks map[string]string, errs int) {
+		fsSymlinks = mode
+		symlinks = make(map[string]string)
+		fsClient, err := fsNew(src + string(filepath.Separator))
+		c.Assert(err, IsNil)
+		for content := range fsClient.List(context.Background(), true, false) {
+			if content.Err != nil {
+				errs++
+				continue
+			}
+			name, e := filepath.Rel(src, content.URL.Path)
+			c.Assert(e, IsNil)
+			names = append(names, filepath.ToSlash(name))
+			if content.Symlink != "" {
+				symlinks[filepath.ToSlash(name)] = content.Symlink
+			}
+		}
+		return names, symlinks, errs
+	}
+
+	names, _, errs := list(symlinksDefault)
+	c.Assert(names, DeepEquals, []string{"file", "file-link"})
+	c.Assert(errs, Equals, 0)
+
+	names, _, errs = list(symlinksFollow)
+	c.Assert(names, DeepEquals, []string{"file", "file-link", "link/object"})
+	c.Assert(errs, Equals, 1)
+
+	names, _, errs = list(symlinksSkip)
+	c.Assert(names, DeepEquals, []string{"file"})
+	c.Assert(errs, Equals, 0)
+
+	names, symlinks, errs := list(symlinksPreserve)
+	c.Assert(names, DeepEquals, []string{"dir/loop", "file", "file-link", "link"})
+	c.Assert(symlinks, DeepEquals, map[string]string{
+		"dir/loop":  "..",
+		"file-link": "file",
+		"link":      filepath.Join("..", "other"),
+	})
+	c.Assert(errs, Equals, 0)
+
+	_, err := parseSymlinkMode(true, false, true)
+	c.Assert(err, NotNil)
+}
+
+// Test watching for events of files.
+func (s *TestSuite) TestWatch(c *C) {
+	root, e := ioutil.TempDir(os.TempDir(), "fs-")
+	c.Assert(e, IsNil)
+	defer os.RemoveAll(root)
+
+	fsClient, err := fsNew(root)
+	c.Assert(err, IsNil)
+	wo, err := fsClient.Watch(WatchParams{Events: []string{"put", "delete"}, Prefix: "photo", Suffix: ".jpg", Recursive: true})
+	c.Assert(err, IsNil)
+
+	// Only events of files matching the prefix and suffix are sent.
+	var events []Event
+	nextEvent := func() {
+		select {
+		case event := <-wo.Events():
+			events = append(events, event)
+		case err := <-wo.Errors():
+			c.Fatal(err)
+		case <-time.After(5 * time.Second):
+			c.Fatal("Timed out waiting for events.")
+		}
+	}
+	c.Assert(ioutil.WriteFile(filepath.Join(root, "photo.png"), []byte("png"), 0600), IsNil)
+	c.Assert(ioutil.WriteFile(filepath.Join(root, "photo.jpg"), []byte("jpeg"), 0600), IsNil)
+	nextEvent()
+	c.Assert(os.Remove(filepath.Join(root, "photo.jpg")), IsNil)
+	nextEvent()
+	c.Assert(events[0].Type, Equals, EventCreate)
+	c.Assert(events[0].Size, Equals, int64(4))
+	c.Assert(events[1].Type, Equals, EventType(EventRemove))
+	for _, event := range events {
+		c.Assert(filepath.Base(event.Path), Equals, "photo.jpg")
+		c.Assert(event.Key, Equals, "photo.jpg")
+	}
+
+	// Watching stops without further events.
+	wo.Close()
+	c.Assert(ioutil.WriteFile(filepath.Join(root, "photo2.jpg"), []byte("jpeg"), 0600), IsNil)
+	for range wo.Events() {
+	}
 }
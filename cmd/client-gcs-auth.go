@@ -0,0 +1,240 @@
+/*
+ * Minio Client (C) 2016 Minio, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"crypto"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/pem"
+	"errors"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/minio/minio/pkg/probe"
+)
+
+const (
+	// Default endpoint exchanging signed assertions for access tokens.
+	gcsTokenEndpoint = "https://oauth2.googleapis.com/token"
+	// Scope of the access tokens, covers reading and writing objects
+	// as well as creating buckets.
+	gcsTokenScope = "https://www.googleapis.com/auth/devstorage.full_control"
+	// Lifetime requested for access tokens, the longest allowed.
+	gcsTokenDuration = time.Hour
+	// Tokens are refreshed this long before they expire, so that
+	// requests in flight never carry an expired token.
+	gcsTokenRefreshWindow = 5 * time.Minute
+)
+
+// gcsServiceAccount - JSON key file of a Google service account.
+type gcsServiceAccount struct {
+	Type         string `json:"type"`
+	ProjectID    string `json:"project_id"`
+	PrivateKeyID string `json:"private_key_id"`
+	PrivateKey   string `json:"private_key"`
+	ClientEmail  string `json:"client_email"`
+	TokenURI     string `json:"token_uri"`
+}
+
+// loadGCSServiceAccount - loads and validates a service account key
+// file.
+func loadGCSServiceAccount(credentials string) (*gcsServiceAccount, *rsa.PrivateKey, *probe.Error) {
+	data, e := ioutil.ReadFile(credentials)
+	if e != nil {
+		return nil, nil, probe.NewError(e).Trace(credentials)
+	}
+	account := &gcsServiceAccount{}
+	if e = json.Unmarshal(data, account); e != nil {
+		return nil, nil, probe.NewError(e).Trace(credentials)
+	}
+	if account.Type != "service_account" || account.ClientEmail == "" {
+		return nil, nil, errInvalidGCSCredentials(credentials).Trace(credentials)
+	}
+	block, _ := pem.Decode([]byte(account.PrivateKey))
+	if block == nil {
+		return nil, nil, errInvalidGCSCredentials(credentials).Trace(credentials)
+	}
+	parsedKey, e := x509.ParsePKCS8PrivateKey(block.Bytes)
+	if e != nil {
+		// Older key files hold PKCS1 keys.
+		if parsedKey, e = x509.ParsePKCS1PrivateKey(block.Bytes); e != nil {
+			return nil, nil, errInvalidGCSCredentials(credentials).Trace(credentials)
+		}
+	}
+	key, ok := parsedKey.(*rsa.PrivateKey)
+	if !ok {
+		return nil, nil, errInvalidGCSCredentials(credentials).Trace(credentials)
+	}
+	if account.TokenURI == "" {
+		account.TokenURI = gcsTokenEndpoint
+	}
+	return account, key, nil
+}
+
+// gcsTokenResponse - JSON response of the token endpoint.
+type gcsTokenResponse struct {
+	AccessToken      string `json:"access_token"`
+	ExpiresIn        int64  `json:"expires_in"`
+	Error            string `json:"error"`
+	ErrorDescription string `json:"error_description"`
+}
+
+// gcsTokenProvider - hands out access tokens of a service account,
+// refreshing them shortly before they expire.
+type gcsTokenProvider struct {
+	mutex     *sync.Mutex
+	account   *gcsServiceAccount
+	key       *rsa.PrivateKey
+	transport http.RoundTripper
+
+	token      string
+	expiration time.Time
+}
+
+// newGCSTokenProvider - instantiates a new token provider for the
+// service account.
+func newGCSTokenProvider(account *gcsServiceAccount, key *rsa.PrivateKey, transport http.RoundTripper) *gcsTokenProvider {
+	return &gcsTokenProvider{
+		mutex:     &sync.Mutex{},
+		account:   account,
+		key:       key,
+		transport: transport,
+	}
+}
+
+// Get - returns a valid access token, requesting a new one if the
+// current one is about to expire.
+func (p *gcsTokenProvider) Get() (string, *probe.Error) {
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+
+	if p.token != "" && time.Now().UTC().Add(gcsTokenRefreshWindow).Before(p.expiration) {
+		return p.token, nil
+	}
+	token, expiration, err := p.requestToken()
+	if err != nil {
+		return "", err.Trace(p.account.ClientEmail)
+	}
+	p.token = token
+	p.expiration = expiration
+	return p.token, nil
+}
+
+// assertion - JWT signed by the service account, asking for a token.
+func (p *gcsTokenProvider) assertion(now time.Time) (string, *probe.Error) {
+	header, e := json.Marshal(map[string]string{
+		"alg": "RS256",
+		"typ": "JWT",
+		"kid": p.account.PrivateKeyID,
+	})
+	if e != nil {
+		return "", probe.NewError(e)
+	}
+	claims, e := json.Marshal(map[string]interface{}{
+		"iss":   p.account.ClientEmail,
+		"scope": gcsTokenScope,
+		"aud":   p.account.TokenURI,
+		"iat":   now.Unix(),
+		"exp":   now.Add(gcsTokenDuration).Unix(),
+	})
+	if e != nil {
+		return "", probe.NewError(e)
+	}
+	encoding := base64.URLEncoding
+	signed := strings.TrimRight(encoding.EncodeToString(header), "=") + "." +
+		strings.TrimRight(encoding.EncodeToString(claims), "=")
+	sum := sha256.Sum256([]byte(signed))
+	signature, e := rsa.SignPKCS1v15(rand.Reader, p.key, crypto.SHA256, sum[:])
+	if e != nil {
+		return "", probe.NewError(e)
+	}
+	return signed + "." + strings.TrimRight(encoding.EncodeToString(signature), "="), nil
+}
+
+// requestToken - exchanges a signed assertion for an access token.
+func (p *gcsTokenProvider) requestToken() (string, time.Time, *probe.Error) {
+	now := time.Now().UTC()
+	assertion, err := p.assertion(now)
+	if err != nil {
+		return "", time.Time{}, err.Trace()
+	}
+	params := url.Values{}
+	params.Set("grant_type", "urn:ietf:params:oauth:grant-type:jwt-bearer")
+	params.Set("assertion", assertion)
+
+	req, e := http.NewRequest("POST", p.account.TokenURI, strings.NewReader(params.Encode()))
+	if e != nil {
+		return "", time.Time{}, probe.NewError(e)
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+
+	resp, e := (&http.Client{Transport: p.transport}).Do(req)
+	if e != nil {
+		return "", time.Time{}, probe.NewError(e)
+	}
+	defer resp.Body.Close()
+
+	respBody, e := ioutil.ReadAll(resp.Body)
+	if e != nil {
+		return "", time.Time{}, probe.NewError(e)
+	}
+	tokenResp := gcsTokenResponse{}
+	if e = json.Unmarshal(respBody, &tokenResp); e != nil && resp.StatusCode == http.StatusOK {
+		return "", time.Time{}, probe.NewError(e)
+	}
+	if resp.StatusCode != http.StatusOK {
+		if tokenResp.Error != "" {
+			return "", time.Time{}, probe.NewError(errors.New(tokenResp.Error + ": " + tokenResp.ErrorDescription))
+		}
+		return "", time.Time{}, probe.NewError(errors.New("Token request failed with " + resp.Status))
+	}
+	return tokenResp.AccessToken, now.Add(time.Duration(tokenResp.ExpiresIn) * time.Second), nil
+}
+
+// gcsTransport - authorizes every outgoing request with the current
+// access token of the provider.
+type gcsTransport struct {
+	provider  *gcsTokenProvider
+	transport http.RoundTripper
+}
+
+// RoundTrip - implements http.RoundTripper.
+func (t gcsTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	token, err := t.provider.Get()
+	if err != nil {
+		return nil, err.ToGoError()
+	}
+
+	// Never modify the original request.
+	newReq := new(http.Request)
+	*newReq = *req
+	newReq.Header = make(http.Header, len(req.Header))
+	for k, v := range req.Header {
+		newReq.Header[k] = v
+	}
+	newReq.Header.Set("Authorization", "Bearer "+token)
+	return t.transport.RoundTrip(newReq)
+}
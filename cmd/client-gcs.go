@@ -0,0 +1,1046 @@
+/*
+ * Minio Client (C) 2016 Minio, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"crypto/md5"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"hash"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/minio/mc/pkg/hookreader"
+	"github.com/minio/minio/pkg/probe"
+)
+
+// Google Cloud Storage client using the JSON API, authorized with the
+// key of a service account. Unlike the XML compatibility layer it
+// supports resumable uploads and buckets with uniform bucket-level
+// access, which reject requests carrying ACLs.
+type gcsClient struct {
+	targetURL *ClientURL
+	// Scheme and host of the API endpoint.
+	hostURL *url.URL
+	// Project new buckets are created in.
+	projectID  string
+	httpClient *http.Client
+	// Context requests of the client are sent within, if any.
+	ctx context.Context
+}
+
+// Name of the API in errors of unsupported operations.
+const gcsAPIType = "Google Cloud Storage"
+
+// Data of resumable uploads is sent in chunks of a multiple of this
+// size, except for the last chunk.
+const gcsChunkAlignment = 256 * 1024
+
+// HTTP status of a resumable upload which is not yet complete.
+const gcsResumeIncomplete = 308
+
+// newGCSFactory encloses New function with client cache, access
+// tokens are shared by clients of the same service account.
+func newGCSFactory() func(config *Config) (Client, *probe.Error) {
+	type cachedClient struct {
+		httpClient *http.Client
+		projectID  string
+	}
+	clientCache := make(map[string]cachedClient)
+	mutex := &sync.Mutex{}
+
+	// Return New function.
+	return func(config *Config) (Client, *probe.Error) {
+		targetURL := newClientURL(config.HostURL)
+		cacheKey := targetURL.Scheme + "://" + targetURL.Host + "\n" + config.Credentials + "\n" + config.CACert + "\n" + config.Cert + "\n" + config.Key
+
+		mutex.Lock()
+		defer mutex.Unlock()
+		cached, found := clientCache[cacheKey]
+		if !found {
+			account, key, err := loadGCSServiceAccount(config.Credentials)
+			if err != nil {
+				return nil, err.Trace(config.Credentials)
+			}
+			transport, err := newClientTransport(config)
+			if err != nil {
+				return nil, err.Trace(config.HostURL)
+			}
+			provider := newGCSTokenProvider(account, key, transport)
+			cached = cachedClient{
+				httpClient: &http.Client{Transport: gcsTransport{provider: provider, transport: transport}},
+				projectID:  account.ProjectID,
+			}
+			clientCache[cacheKey] = cached
+		}
+		return &gcsClient{
+			targetURL:  targetURL,
+			hostURL:    &url.URL{Scheme: targetURL.Scheme, Host: targetURL.Host},
+			projectID:  cached.projectID,
+			httpClient: cached.httpClient,
+		}, nil
+	}
+}
+
+// gcsNew returns an initialized gcsClient structure.
+var gcsNew = newGCSFactory()
+
+// gcsObject - object resource of the JSON API.
+type gcsObject struct {
+	Name               string            `json:"name,omitempty"`
+	Size               string            `json:"size,omitempty"`
+	Updated            string            `json:"updated,omitempty"`
+	MD5Hash            string            `json:"md5Hash,omitempty"`
+	StorageClass       string            `json:"storageClass,omitempty"`
+	ContentType        string            `json:"contentType,omitempty"`
+	CacheControl       string            `json:"cacheControl,omitempty"`
+	ContentDisposition string            `json:"contentDisposition,omitempty"`
+	ContentEncoding    string            `json:"contentEncoding,omitempty"`
+	ContentLanguage    string            `json:"contentLanguage,omitempty"`
+	Metadata           map[string]string `json:"metadata,omitempty"`
+}
+
+// gcsObjectList - response of listing objects.
+type gcsObjectList struct {
+	Items         []gcsObject `json:"items"`
+	Prefixes      []string    `json:"prefixes"`
+	NextPageToken string      `json:"nextPageToken"`
+}
+
+// gcsBucket - bucket resource of the JSON API.
+type gcsBucket struct {
+	Name        string `json:"name"`
+	TimeCreated string `json:"timeCreated"`
+}
+
+// gcsBucketList - response of listing buckets.
+type gcsBucketList struct {
+	Items         []gcsBucket `json:"items"`
+	NextPageToken string      `json:"nextPageToken"`
+}
+
+// gcsRewriteResponse - response of rewriting an object, large objects
+// take several requests.
+type gcsRewriteResponse struct {
+	Done         bool   `json:"done"`
+	RewriteToken string `json:"rewriteToken"`
+}
+
+// gcsErrorResponse - JSON error response.
+type gcsErrorResponse struct {
+	Error struct {
+		Code    int    `json:"code"`
+		Message string `json:"message"`
+	} `json:"error"`
+}
+
+// gcsError - error returned by the JSON API.
+type gcsError struct {
+	StatusCode int
+	Message    string
+}
+
+func (e gcsError) Error() string {
+	return e.Message
+}
+
+// size - size of the object.
+func (o gcsObject) size() int64 {
+	size, _ := strconv.ParseInt(o.Size, 10, 64)
+	return size
+}
+
+// modTime - last modification time of the object.
+func (o gcsObject) modTime() time.Time {
+	t, _ := time.Parse(time.RFC3339Nano, o.Updated)
+	return t
+}
+
+// etag - hex encoded MD5 sum of the object, composite objects have
+// none.
+func (o gcsObject) etag() string {
+	sum, e := base64.StdEncoding.DecodeString(o.MD5Hash)
+	if e != nil {
+		return ""
+	}
+	return hex.EncodeToString(sum)
+}
+
+// gcsObjectMetadata - object resource holding metadata keyed by header
+// name, user defined metadata is stored without its prefix.
+func gcsObjectMetadata(metadata map[string]string) gcsObject {
+	object := gcsObject{}
+	for key, value := range metadata {
+		switch key = metadataKey(key); key {
+		case "Content-Type":
+			object.ContentType = value
+		case "Cache-Control":
+			object.CacheControl = value
+		case "Content-Disposition":
+			object.ContentDisposition = value
+		case "Content-Encoding":
+			object.ContentEncoding = value
+		case "Content-Language":
+			object.ContentLanguage = value
+		default:
+			if strings.HasPrefix(key, userMetadataPrefix) {
+				if object.Metadata == nil {
+					object.Metadata = make(map[string]string)
+				}
+				object.Metadata[strings.TrimPrefix(key, userMetadataPrefix)] = value
+			}
+		}
+	}
+	return object
+}
+
+// headerMetadata - metadata of the object keyed by header name.
+func (o gcsObject) headerMetadata() map[string]string {
+	metadata := make(map[string]string)
+	for key, value := range map[string]string{
+		"Content-Type":        o.ContentType,
+		"Cache-Control":       o.CacheControl,
+		"Content-Disposition": o.ContentDisposition,
+		"Content-Encoding":    o.ContentEncoding,
+		"Content-Language":    o.ContentLanguage,
+	} {
+		if value != "" {
+			metadata[key] = value
+		}
+	}
+	for key, value := range o.Metadata {
+		metadata[http.CanonicalHeaderKey(userMetadataPrefix+key)] = value
+	}
+	return metadata
+}
+
+// gcsEscape - escapes a bucket or object name as a single segment of
+// a path.
+func gcsEscape(name string) string {
+	return strings.Replace(url.QueryEscape(name), "+", "%20", -1)
+}
+
+// GetURL get url.
+func (c *gcsClient) GetURL() ClientURL {
+	return *c.targetURL
+}
+
+// url2BucketAndObject gives bucketName and objectName from URL path.
+func (c *gcsClient) url2BucketAndObject() (bucketName, objectName string) {
+	path := strings.TrimPrefix(c.targetURL.Path, string(c.targetURL.Separator))
+	tokens := strings.SplitN(path, string(c.targetURL.Separator), 2)
+	bucketName = tokens[0]
+	if len(tokens) == 2 {
+		objectName = tokens[1]
+	}
+	return bucketName, objectName
+}
+
+// apiURL - URL of a resource of the JSON API, path segments are
+// escaped by the caller.
+func (c *gcsClient) apiURL(path string, query url.Values) string {
+	urlStr := c.hostURL.String() + path
+	if len(query) > 0 {
+		urlStr += "?" + query.Encode()
+	}
+	return urlStr
+}
+
+// gcsObjectPath - path of an object resource.
+func gcsObjectPath(bucket, object string) string {
+	return "/storage/v1/b/" + gcsEscape(bucket) + "/o/" + gcsEscape(object)
+}
+
+// withContext - copy of the client sending its requests within the
+// context.
+func (c *gcsClient) withContext(ctx context.Context) *gcsClient {
+	clnt := *c
+	clnt.ctx = ctx
+	return &clnt
+}
+
+// do - sends a request, responses with a status other than success or
+// an incomplete resumable upload are returned as gcsError.
+func (c *gcsClient) do(method, urlStr string, header http.Header, body io.Reader, size int64) (*http.Response, *probe.Error) {
+	req, e := http.NewRequest(method, urlStr, body)
+	if e != nil {
+		return nil, probe.NewError(e)
+	}
+	if c.ctx != nil {
+		req = req.WithContext(c.ctx)
+	}
+	for key, values := range header {
+		req.Header[key] = values
+	}
+	if body != nil {
+		req.ContentLength = size
+	}
+	resp, e := c.httpClient.Do(req)
+	if e != nil {
+		return nil, probe.NewError(e)
+	}
+	if resp.StatusCode/100 == 2 || resp.StatusCode == gcsResumeIncomplete {
+		return resp, nil
+	}
+	defer resp.Body.Close()
+	gcsErr := gcsError{StatusCode: resp.StatusCode, Message: resp.Status}
+	errResp := gcsErrorResponse{}
+	if data, e := ioutil.ReadAll(resp.Body); e == nil && json.Unmarshal(data, &errResp) == nil && errResp.Error.Message != "" {
+		gcsErr.Message = errResp.Error.Message
+	}
+	return nil, probe.NewError(gcsErr)
+}
+
+// doJSON - sends a request with an optional JSON body and decodes the
+// JSON response into result.
+func (c *gcsClient) doJSON(method, urlStr string, body interface{}, result interface{}) *probe.Error {
+	var reader io.Reader
+	var size int64
+	header := make(http.Header)
+	if body != nil {
+		data, e := json.Marshal(body)
+		if e != nil {
+			return probe.NewError(e)
+		}
+		reader = bytes.NewReader(data)
+		size = int64(len(data))
+		header.Set("Content-Type", "application/json")
+	}
+	resp, err := c.do(method, urlStr, header, reader, size)
+	if err != nil {
+		return err.Trace(method, urlStr)
+	}
+	defer resp.Body.Close()
+	if result == nil {
+		return nil
+	}
+	if e := json.NewDecoder(resp.Body).Decode(result); e != nil {
+		return probe.NewError(e)
+	}
+	return nil
+}
+
+// toClientError - translates errors of the JSON API to client errors.
+func (c *gcsClient) toClientError(err *probe.Error, bucket, object string) *probe.Error {
+	gcsErr, ok := err.ToGoError().(gcsError)
+	if !ok {
+		return err
+	}
+	switch gcsErr.StatusCode {
+	case http.StatusUnauthorized, http.StatusForbidden:
+		return probe.NewError(PathInsufficientPermission{Path: c.targetURL.String()})
+	case http.StatusNotFound:
+		if object == "" || strings.Contains(gcsErr.Message, "bucket does not exist") {
+			return probe.NewError(BucketDoesNotExist{Bucket: bucket})
+		}
+		return probe.NewError(ObjectMissing{})
+	case http.StatusPreconditionFailed:
+		return probe.NewError(ObjectPreconditionFailed{Object: c.targetURL.String()})
+	}
+	return err
+}
+
+// notImplemented - error of operations the JSON API client does not
+// support.
+func (c *gcsClient) notImplemented(api string) *probe.Error {
+	return probe.NewError(APINotImplemented{
+		API:     api,
+		APIType: gcsAPIType,
+	})
+}
+
+// statObject - object resource of an object.
+func (c *gcsClient) statObject(bucket, object string) (gcsObject, *probe.Error) {
+	result := gcsObject{}
+	if err := c.doJSON("GET", c.apiURL(gcsObjectPath(bucket, object), nil), nil, &result); err != nil {
+		return gcsObject{}, c.toClientError(err, bucket, object).Trace(bucket, object)
+	}
+	return result, nil
+}
+
+// Stat - metadata of the bucket, object or prefix.
+func (c *gcsClient) Stat() (*ClientContent, *probe.Error) {
+	bucket, object := c.url2BucketAndObject()
+	if bucket == "" {
+		return nil, probe.NewError(BucketNameEmpty{})
+	}
+	if object == "" {
+		if err := c.doJSON("GET", c.apiURL("/storage/v1/b/"+gcsEscape(bucket), nil), nil, &gcsBucket{}); err != nil {
+			return nil, c.toClientError(err, bucket, "").Trace(bucket)
+		}
+		return &ClientContent{URL: *c.targetURL, Type: os.ModeDir}, nil
+	}
+	object = strings.TrimRight(object, string(c.targetURL.Separator))
+	result, err := c.statObject(bucket, object)
+	if err == nil {
+		return &ClientContent{
+			URL:          *c.targetURL,
+			Time:         result.modTime(),
+			Size:         result.size(),
+			Type:         os.FileMode(0664),
+			StorageClass: result.StorageClass,
+		}, nil
+	}
+	if _, ok := err.ToGoError().(ObjectMissing); !ok {
+		return nil, err.Trace(bucket, object)
+	}
+	// Prefixes of objects are folders.
+	list := gcsObjectList{}
+	query := url.Values{}
+	query.Set("prefix", object+string(c.targetURL.Separator))
+	query.Set("maxResults", "1")
+	if err = c.doJSON("GET", c.apiURL("/storage/v1/b/"+gcsEscape(bucket)+"/o", query), nil, &list); err != nil {
+		return nil, c.toClientError(err, bucket, object).Trace(bucket, object)
+	}
+	if len(list.Items) > 0 || len(list.Prefixes) > 0 {
+		return &ClientContent{URL: *c.targetURL, Type: os.ModeDir}, nil
+	}
+	return nil, probe.NewError(ObjectMissing{})
+}
+
+// GetMetadata - metadata of the object keyed by header name, objects
+// encrypted with customer keys are not supported.
+func (c *gcsClient) GetMetadata(sse EncryptOpts) (map[string]string, *probe.Error) {
+	if !sse.isEmpty() {
+		return nil, c.notImplemented("Encryption")
+	}
+	bucket, object := c.url2BucketAndObject()
+	result, err := c.statObject(bucket, object)
+	if err != nil {
+		return nil, err.Trace(bucket, object)
+	}
+	return result.headerMetadata(), nil
+}
+
+// List - list buckets and objects, incomplete uploads can not be
+// listed.
+func (c *gcsClient) List(ctx context.Context, recursive, incomplete bool) <-chan *ClientContent {
+	contentCh := make(chan *ClientContent)
+	if incomplete {
+		close(contentCh)
+		return contentCh
+	}
+	go c.withContext(ctx).listInRoutine(contentCh, recursive)
+	return contentCh
+}
+
+// listBuckets - sends all buckets of the project.
+func (c *gcsClient) listBuckets(bucketCh chan<- gcsBucket) *probe.Error {
+	defer close(bucketCh)
+	query := url.Values{}
+	query.Set("project", c.projectID)
+	for {
+		list := gcsBucketList{}
+		if err := c.doJSON("GET", c.apiURL("/storage/v1/b", query), nil, &list); err != nil {
+			return c.toClientError(err, "", "").Trace(c.projectID)
+		}
+		for _, bucket := range list.Items {
+			bucketCh <- bucket
+		}
+		if list.NextPageToken == "" {
+			return nil
+		}
+		query.Set("pageToken", list.NextPageToken)
+	}
+}
+
+// listObjects - sends objects of the bucket with the prefix, along
+// with the prefixes up to the next separator unless recursive.
+func (c *gcsClient) listObjects(bucket, prefix string, recursive bool, contentCh chan<- *ClientContent) {
+	query := url.Values{}
+	query.Set("prefix", prefix)
+	if !recursive {
+		query.Set("delimiter", string(c.targetURL.Separator))
+	}
+	for {
+		list := gcsObjectList{}
+		if err := c.doJSON("GET", c.apiURL("/storage/v1/b/"+gcsEscape(bucket)+"/o", query), nil, &list); err != nil {
+			contentCh <- &ClientContent{Err: c.toClientError(err, bucket, prefix).Trace(bucket, prefix)}
+			return
+		}
+		for _, dir := range list.Prefixes {
+			url := *c.targetURL
+			url.Path = filepath.Join(string(url.Separator), bucket, dir)
+			contentCh <- &ClientContent{URL: url, Time: time.Now(), Type: os.ModeDir}
+		}
+		for _, object := range list.Items {
+			// Ignore empty directories.
+			if recursive && object.size() == 0 && strings.HasSuffix(object.Name, string(c.targetURL.Separator)) {
+				continue
+			}
+			url := *c.targetURL
+			url.Path = filepath.Join(string(url.Separator), bucket, object.Name)
+			contentCh <- &ClientContent{
+				URL:          url,
+				Time:         object.modTime(),
+				Size:         object.size(),
+				Type:         os.FileMode(0664),
+				StorageClass: object.StorageClass,
+			}
+		}
+		if list.NextPageToken == "" {
+			return
+		}
+		query.Set("pageToken", list.NextPageToken)
+	}
+}
+
+func (c *gcsClient) listInRoutine(contentCh chan *ClientContent, recursive bool) {
+	defer close(contentCh)
+	b, o := c.url2BucketAndObject()
+	switch {
+	case b == "":
+		bucketCh := make(chan gcsBucket)
+		errCh := make(chan *probe.Error, 1)
+		go func() { errCh <- c.listBuckets(bucketCh) }()
+		for bucket := range bucketCh {
+			url := *c.targetURL
+			url.Path = filepath.Join(string(url.Separator), bucket.Name)
+			created, _ := time.Parse(time.RFC3339Nano, bucket.TimeCreated)
+			contentCh <- &ClientContent{URL: url, Time: created, Type: os.ModeDir}
+			if recursive {
+				c.listObjects(bucket.Name, "", true, contentCh)
+			}
+		}
+		if err := <-errCh; err != nil {
+			contentCh <- &ClientContent{Err: err}
+		}
+	case !recursive && o == "" && !strings.HasSuffix(c.targetURL.Path, string(c.targetURL.Separator)):
+		bucket := gcsBucket{}
+		if err := c.doJSON("GET", c.apiURL("/storage/v1/b/"+gcsEscape(b), nil), nil, &bucket); err != nil {
+			contentCh <- &ClientContent{Err: c.toClientError(err, b, "").Trace(b)}
+			return
+		}
+		created, _ := time.Parse(time.RFC3339Nano, bucket.TimeCreated)
+		contentCh <- &ClientContent{URL: *c.targetURL, Time: created, Type: os.ModeDir}
+	default:
+		c.listObjects(b, o, recursive, contentCh)
+	}
+}
+
+// MakeBucket - make a new bucket in the project of the service
+// account.
+func (c *gcsClient) MakeBucket(region string) *probe.Error {
+	bucket, object := c.url2BucketAndObject()
+	if object != "" {
+		return probe.NewError(BucketNameTopLevel{})
+	}
+	if err := isValidBucketName(bucket); err != nil {
+		return err.Trace(bucket)
+	}
+	body := map[string]string{"name": bucket}
+	if region != "" {
+		body["location"] = region
+	}
+	query := url.Values{}
+	query.Set("project", c.projectID)
+	if err := c.doJSON("POST", c.apiURL("/storage/v1/b", query), body, nil); err != nil {
+		if gcsErr, ok := err.ToGoError().(gcsError); ok && gcsErr.StatusCode == http.StatusConflict {
+			return probe.NewError(BucketExists{Bucket: bucket})
+		}
+		return c.toClientError(err, bucket, "").Trace(bucket)
+	}
+	return nil
+}
+
+// Get - reads the object, or a range of it.
+func (c *gcsClient) Get(ctx context.Context, opts GetOpts) (io.Reader, *probe.Error) {
+	c = c.withContext(ctx)
+	if !opts.SSE.isEmpty() {
+		return nil, c.notImplemented("Encryption")
+	}
+	bucket, object := c.url2BucketAndObject()
+	query := url.Values{}
+	query.Set("alt", "media")
+	header := make(http.Header)
+	if opts.Offset > 0 || opts.Length > 0 {
+		byteRange := fmt.Sprintf("bytes=%d-", opts.Offset)
+		if opts.Length > 0 {
+			byteRange += strconv.FormatInt(opts.Offset+opts.Length-1, 10)
+		}
+		header.Set("Range", byteRange)
+	}
+	resp, err := c.do("GET", c.apiURL(gcsObjectPath(bucket, object), query), header, nil, 0)
+	if err != nil {
+		if gcsErr, ok := err.ToGoError().(gcsError); ok && gcsErr.StatusCode == http.StatusRequestedRangeNotSatisfiable {
+			return nil, probe.NewError(ObjectRangeInvalid{Object: c.targetURL.String(), Offset: opts.Offset})
+		}
+		return nil, c.toClientError(err, bucket, object).Trace(bucket, object)
+	}
+	if opts.Checksum && opts.Offset == 0 && opts.Length == 0 {
+		if expected := gcsMD5Hash(resp.Header); expected != "" {
+			return &gcsChecksumReader{
+				body:     resp.Body,
+				hash:     md5.New(),
+				object:   c.targetURL.String(),
+				expected: expected,
+			}, nil
+		}
+	}
+	return resp.Body, nil
+}
+
+// gcsMD5Hash - hex encoded MD5 sum of the ‘X-Goog-Hash’ header of a
+// response, composite objects have none.
+func gcsMD5Hash(header http.Header) string {
+	for _, value := range header["X-Goog-Hash"] {
+		for _, hash := range strings.Split(value, ",") {
+			hash = strings.TrimSpace(hash)
+			if strings.HasPrefix(hash, "md5=") {
+				sum, e := base64.StdEncoding.DecodeString(strings.TrimPrefix(hash, "md5="))
+				if e == nil {
+					return hex.EncodeToString(sum)
+				}
+			}
+		}
+	}
+	return ""
+}
+
+// gcsChecksumReader - verifies the MD5 sum of the data read once the
+// end of the object is reached.
+type gcsChecksumReader struct {
+	body     io.ReadCloser
+	hash     hash.Hash
+	object   string
+	expected string
+}
+
+func (r *gcsChecksumReader) Read(p []byte) (int, error) {
+	n, e := r.body.Read(p)
+	r.hash.Write(p[:n])
+	if e == io.EOF {
+		if actual := hex.EncodeToString(r.hash.Sum(nil)); actual != r.expected {
+			return n, ObjectChecksumMismatch{Object: r.object, Expected: r.expected, Actual: actual}
+		}
+	}
+	return n, e
+}
+
+func (r *gcsChecksumReader) Close() error {
+	return r.body.Close()
+}
+
+// uploadStateKey - identifies the state of a resumable upload of the
+// object from a source.
+func (c *gcsClient) uploadStateKey(bucket, object, resumeID string) string {
+	return sum256Hex([]byte(c.hostURL.String() + "/" + bucket + "/" + object + "\n" + resumeID))
+}
+
+// startUpload - initiates a resumable upload session, the URL of the
+// session is returned.
+func (c *gcsClient) startUpload(bucket, object string, size int64, metadata gcsObject) (string, *probe.Error) {
+	data, e := json.Marshal(metadata)
+	if e != nil {
+		return "", probe.NewError(e)
+	}
+	query := url.Values{}
+	query.Set("uploadType", "resumable")
+	query.Set("name", object)
+	header := make(http.Header)
+	header.Set("Content-Type", "application/json")
+	header.Set("X-Upload-Content-Type", metadata.ContentType)
+	if size >= 0 {
+		header.Set("X-Upload-Content-Length", strconv.FormatInt(size, 10))
+	}
+	resp, err := c.do("POST", c.apiURL("/upload/storage/v1/b/"+gcsEscape(bucket)+"/o", query), header, bytes.NewReader(data), int64(len(data)))
+	if err != nil {
+		return "", c.toClientError(err, bucket, object).Trace(bucket, object)
+	}
+	resp.Body.Close()
+	session := resp.Header.Get("Location")
+	if session == "" {
+		return "", probe.NewError(gcsError{StatusCode: resp.StatusCode, Message: "Upload session of ‘" + object + "’ has no location."})
+	}
+	return session, nil
+}
+
+// uploadOffset - number of bytes persisted by an upload session, a
+// negative offset is returned for completed sessions.
+func (c *gcsClient) uploadOffset(session string, size int64) (int64, *probe.Error) {
+	header := make(http.Header)
+	header.Set("Content-Range", fmt.Sprintf("bytes */%d", size))
+	resp, err := c.do("PUT", session, header, bytes.NewReader(nil), 0)
+	if err != nil {
+		return 0, err.Trace(session)
+	}
+	resp.Body.Close()
+	if resp.StatusCode != gcsResumeIncomplete {
+		return -1, nil
+	}
+	return persistedOffset(resp.Header), nil
+}
+
+// persistedOffset - offset following the bytes persisted by an upload
+// session according to the ‘Range’ header of its response.
+func persistedOffset(header http.Header) int64 {
+	byteRange := header.Get("Range")
+	if i := strings.LastIndex(byteRange, "-"); i >= 0 {
+		if last, e := strconv.ParseInt(byteRange[i+1:], 10, 64); e == nil {
+			return last + 1
+		}
+	}
+	return 0
+}
+
+// resumeUpload - continues an earlier upload of the object if its
+// session was persisted, otherwise a new session is started. The
+// offset of the data persisted is returned along.
+func (c *gcsClient) resumeUpload(bucket, object string, size int64, metadata gcsObject, resumeID string) (string, int64, *uploadStateV1, *probe.Error) {
+	var key string
+	if resumeID != "" && size >= 0 {
+		key = c.uploadStateKey(bucket, object, resumeID)
+		state, err := loadUploadState(key)
+		if err == nil && state != nil {
+			if state.Size == size {
+				offset, err := c.uploadOffset(state.UploadID, size)
+				if err == nil && offset >= 0 {
+					return state.UploadID, offset, state, nil
+				}
+			}
+			removeUploadState(key)
+		}
+	}
+	session, err := c.startUpload(bucket, object, size, metadata)
+	if err != nil {
+		return "", 0, nil, err.Trace(bucket, object)
+	}
+	if key == "" {
+		return session, 0, nil, nil
+	}
+	state := newUploadState(key, session, bucket, object, size, 0)
+	if err = state.save(); err != nil {
+		// Upload works without, it just can not be resumed.
+		return session, 0, nil, nil
+	}
+	return session, 0, state, nil
+}
+
+// Put - uploads the reader through a resumable upload session, in
+// chunks of the part size. Uploads with a resume ID persist their
+// session, so that an interrupted upload continues where it stopped.
+func (c *gcsClient) Put(ctx context.Context, reader io.Reader, size int64, contentType string, progress io.Reader, opts PutOpts) (int64, *probe.Error) {
+	c = c.withContext(ctx)
+	if !opts.SSE.isEmpty() {
+		return 0, c.notImplemented("Encryption")
+	}
+	bucket, object := c.url2BucketAndObject()
+	if bucket == "" {
+		return 0, probe.NewError(BucketNameEmpty{})
+	}
+	metadata := gcsObjectMetadata(opts.Metadata)
+	if metadata.ContentType == "" {
+		metadata.ContentType = contentType
+	}
+	if metadata.ContentType == "" {
+		metadata.ContentType = "application/octet-stream"
+	}
+	session, offset, state, err := c.resumeUpload(bucket, object, size, metadata, opts.ResumeID)
+	if err != nil {
+		return 0, err.Trace(bucket, object)
+	}
+
+	var checksum hash.Hash
+	if opts.Checksum {
+		// Data is hashed as read, including that persisted earlier.
+		checksum = md5.New()
+		reader = io.TeeReader(reader, checksum)
+	}
+	reader = hookreader.NewHook(reader, progress)
+	// Data persisted by an earlier attempt is skipped.
+	if offset > 0 {
+		if _, e := io.CopyN(ioutil.Discard, reader, offset); e != nil {
+			return 0, probe.NewError(UnexpectedEOF{TotalSize: size, TotalWritten: offset})
+		}
+	}
+
+	chunkSize, err := optimalPartSize(size, opts.PartSize)
+	if err != nil {
+		return 0, err.Trace()
+	}
+	chunkSize = (chunkSize + gcsChunkAlignment - 1) / gcsChunkAlignment * gcsChunkAlignment
+	buf := make([]byte, chunkSize)
+	var result gcsObject
+	for {
+		n, e := io.ReadFull(reader, buf)
+		if e != nil && e != io.EOF && e != io.ErrUnexpectedEOF {
+			return offset, probe.NewError(e)
+		}
+		// Total size is sent along with the last chunk.
+		total := "*"
+		isLast := e != nil || (size >= 0 && offset+int64(n) >= size)
+		if isLast {
+			if size >= 0 && offset+int64(n) != size {
+				return offset + int64(n), probe.NewError(UnexpectedEOF{TotalSize: size, TotalWritten: offset + int64(n)})
+			}
+			total = strconv.FormatInt(offset+int64(n), 10)
+		}
+		chunk := buf[:n]
+		for {
+			header := make(http.Header)
+			if len(chunk) == 0 {
+				header.Set("Content-Range", "bytes */"+total)
+			} else {
+				header.Set("Content-Range", fmt.Sprintf("bytes %d-%d/%s", offset, offset+int64(len(chunk))-1, total))
+			}
+			resp, err := c.do("PUT", session, header, bytes.NewReader(chunk), int64(len(chunk)))
+			if err != nil {
+				if state == nil {
+					c.cancelUpload(session)
+				}
+				return offset, c.toClientError(err, bucket, object).Trace(bucket, object)
+			}
+			if resp.StatusCode != gcsResumeIncomplete {
+				e = json.NewDecoder(resp.Body).Decode(&result)
+				resp.Body.Close()
+				if e != nil {
+					return offset, probe.NewError(e)
+				}
+				offset += int64(len(chunk))
+				break
+			}
+			resp.Body.Close()
+			// Only part of the chunk may have been persisted, the
+			// rest is sent again.
+			persisted := persistedOffset(resp.Header) - offset
+			if persisted <= 0 || persisted > int64(len(chunk)) || (isLast && persisted == int64(len(chunk))) {
+				return offset, probe.NewError(UnexpectedShortWrite{InputSize: len(chunk), WriteSize: int(persisted)})
+			}
+			offset += persisted
+			chunk = chunk[persisted:]
+			if len(chunk) == 0 {
+				break
+			}
+		}
+		if isLast {
+			break
+		}
+	}
+	if state != nil {
+		removeUploadState(state.key)
+	}
+	if checksum != nil {
+		expected := hex.EncodeToString(checksum.Sum(nil))
+		if actual := result.etag(); actual != "" && actual != expected {
+			return offset, probe.NewError(ObjectChecksumMismatch{Object: c.targetURL.String(), Expected: actual, Actual: expected})
+		}
+	}
+	return offset, nil
+}
+
+// cancelUpload - cancels an upload session which can not be resumed,
+// sessions of interrupted commands are cancelled as well.
+func (c *gcsClient) cancelUpload(session string) {
+	ctx, cancel := newCleanupContext()
+	defer cancel()
+	if resp, err := c.withContext(ctx).do("DELETE", session, nil, nil, 0); err == nil {
+		resp.Body.Close()
+	}
+}
+
+// Copy - copies the object within the service by rewriting it, large
+// objects are rewritten in several requests.
+func (c *gcsClient) Copy(ctx context.Context, source string, size int64, progress io.Reader, opts CopyOpts) *probe.Error {
+	c = c.withContext(ctx)
+	if !opts.SrcSSE.isEmpty() || !opts.TgtSSE.isEmpty() {
+		return c.notImplemented("Encryption")
+	}
+	if opts.hasConditions() {
+		return c.notImplemented("CopyConditions")
+	}
+	bucket, object := c.url2BucketAndObject()
+	if bucket == "" {
+		return probe.NewError(BucketNameEmpty{})
+	}
+	sourceURL := *c.targetURL
+	sourceURL.Path = source
+	sourceClnt := &gcsClient{targetURL: &sourceURL, hostURL: c.hostURL, projectID: c.projectID, httpClient: c.httpClient, ctx: c.ctx}
+	srcBucket, srcObject := sourceClnt.url2BucketAndObject()
+
+	// Metadata replaces that of the source, given entries are merged.
+	var body interface{}
+	if len(opts.Metadata) > 0 {
+		srcMetadata, err := sourceClnt.GetMetadata(EncryptOpts{})
+		if err != nil {
+			return err.Trace(source)
+		}
+		body = gcsObjectMetadata(mergeMetadata(srcMetadata, opts.Metadata))
+	}
+	path := gcsObjectPath(srcBucket, srcObject) + "/rewriteTo" + strings.TrimPrefix(gcsObjectPath(bucket, object), "/storage/v1")
+	query := url.Values{}
+	for {
+		result := gcsRewriteResponse{}
+		if err := c.doJSON("POST", c.apiURL(path, query), body, &result); err != nil {
+			return c.toClientError(err, srcBucket, srcObject).Trace(source)
+		}
+		if result.Done {
+			break
+		}
+		query.Set("rewriteToken", result.RewriteToken)
+	}
+	// Successful copy update progress bar if there is one.
+	if progress != nil {
+		if _, e := io.CopyN(ioutil.Discard, progress, size); e != nil {
+			return probe.NewError(e)
+		}
+	}
+	return nil
+}
+
+// Checksum - MD5 sum of the object. Objects with a MD5 sum are not
+// uploaded in parts, for other part sizes and composite objects the
+// ETag is computed from the data.
+func (c *gcsClient) Checksum(partSize int64) (string, int64, *probe.Error) {
+	bucket, object := c.url2BucketAndObject()
+	result, err := c.statObject(bucket, object)
+	if err != nil {
+		return "", 0, err.Trace(bucket, object)
+	}
+	if etag := result.etag(); etag != "" && partSize <= 0 {
+		return etag, 0, nil
+	}
+	if partSize < 0 {
+		partSize = 0
+	}
+	reader, err := c.Get(context.Background(), GetOpts{})
+	if err != nil {
+		return "", 0, err.Trace(bucket, object)
+	}
+	defer reader.(io.Closer).Close()
+	etag, err := readETag(reader, partSize)
+	if err != nil {
+		return "", 0, err.Trace(bucket, object)
+	}
+	return etag, partSize, nil
+}
+
+// Remove - remove object or bucket, incomplete uploads expire on
+// their own.
+func (c *gcsClient) Remove(ctx context.Context, incomplete bool) *probe.Error {
+	if incomplete {
+		return nil
+	}
+	c = c.withContext(ctx)
+	bucket, object := c.url2BucketAndObject()
+	path := "/storage/v1/b/" + gcsEscape(bucket)
+	if object != "" {
+		path = gcsObjectPath(bucket, object)
+	}
+	if err := c.doJSON("DELETE", c.apiURL(path, nil), nil, nil); err != nil {
+		return c.toClientError(err, bucket, object).Trace(bucket, object)
+	}
+	return nil
+}
+
+// RemoveBulk - removes the objects read from the channel one by one.
+func (c *gcsClient) RemoveBulk(contentCh <-chan *ClientContent) <-chan *ClientContent {
+	resultCh := make(chan *ClientContent)
+	go func() {
+		defer close(resultCh)
+		for content := range contentCh {
+			url := content.URL
+			clnt := &gcsClient{targetURL: &url, hostURL: c.hostURL, projectID: c.projectID, httpClient: c.httpClient}
+			resultCh <- &ClientContent{URL: content.URL, Err: clnt.Remove(context.Background(), false)}
+		}
+	}()
+	return resultCh
+}
+
+// GetAccess - access of buckets is managed through IAM.
+func (c *gcsClient) GetAccess() (string, *probe.Error) {
+	return "", c.notImplemented("GetAccess")
+}
+
+// GetAccessRules - access of buckets is managed through IAM.
+func (c *gcsClient) GetAccessRules() (map[string]string, *probe.Error) {
+	return nil, c.notImplemented("GetAccessRules")
+}
+
+// SetAccess - access of buckets is managed through IAM.
+func (c *gcsClient) SetAccess(access string) *probe.Error {
+	return c.notImplemented("SetAccess")
+}
+
+// SelectObjectContent - queries not implemented.
+func (c *gcsClient) SelectObjectContent(expression string, sse EncryptOpts, opts SelectObjectOpts) (io.ReadCloser, *probe.Error) {
+	return nil, c.notImplemented("SelectObjectContent")
+}
+
+// ShareDownload - share download not implemented.
+func (c *gcsClient) ShareDownload(expires time.Duration, reqParams url.Values) (string, *probe.Error) {
+	return "", c.notImplemented("ShareDownload")
+}
+
+// ShareUpload - share upload not implemented.
+func (c *gcsClient) ShareUpload(opts ShareUploadOpts) (map[string]string, *probe.Error) {
+	return nil, c.notImplemented("ShareUpload")
+}
+
+// ShareUploadURL - share upload not implemented.
+func (c *gcsClient) ShareUploadURL(opts ShareUploadOpts) (string, *probe.Error) {
+	return "", c.notImplemented("ShareUploadURL")
+}
+
+// Watch - watching events not implemented.
+func (c *gcsClient) Watch(params WatchParams) (*WatchObject, *probe.Error) {
+	return nil, c.notImplemented("Watch")
+}
+
+// Unwatch - watching events not implemented.
+func (c *gcsClient) Unwatch(params WatchParams) *probe.Error {
+	return c.notImplemented("Unwatch")
+}
+
+// RestoreObject - archiving not implemented.
+func (c *gcsClient) RestoreObject(days int, tier string) *probe.Error {
+	return c.notImplemented("RestoreObject")
+}
+
+// RestoreStatus - archiving not implemented.
+func (c *gcsClient) RestoreStatus(sse EncryptOpts) (ObjectRestoreStatus, *probe.Error) {
+	return ObjectRestoreStatus{}, c.notImplemented("RestoreStatus")
+}
+
+// ListVersions - versioning not implemented.
+func (c *gcsClient) ListVersions(recursive bool) <-chan *ClientContent {
+	contentCh := make(chan *ClientContent, 1)
+	contentCh <- &ClientContent{Err: c.notImplemented("ListVersions")}
+	close(contentCh)
+	return contentCh
+}
+
+// GetVersion - versioning not implemented.
+func (c *gcsClient) GetVersion(versionID string) (io.Reader, *probe.Error) {
+	return nil, c.notImplemented("GetVersion")
+}
+
+// RemoveVersion - versioning not implemented.
+func (c *gcsClient) RemoveVersion(versionID string) *probe.Error {
+	return c.notImplemented("RemoveVersion")
+}
@@ -0,0 +1,223 @@
+/*
+ * Minio Client (C) 2016 Minio, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"crypto/md5"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/pem"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+
+	. "gopkg.in/check.v1"
+)
+
+// gcsHandler - serves the JSON API for a single bucket, objects are
+// uploaded through resumable upload sessions.
+type gcsHandler struct {
+	mutex    *sync.Mutex
+	objects  map[string]gcsObject
+	data     map[string][]byte
+	sessions map[string]*gcsObject
+	uploads  map[string][]byte
+}
+
+func newGCSHandler() *gcsHandler {
+	return &gcsHandler{
+		mutex:    &sync.Mutex{},
+		objects:  make(map[string]gcsObject),
+		data:     make(map[string][]byte),
+		sessions: make(map[string]*gcsObject),
+		uploads:  make(map[string][]byte),
+	}
+}
+
+func (h *gcsHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	h.mutex.Lock()
+	defer h.mutex.Unlock()
+	path := r.URL.EscapedPath()
+	if path == "/token" {
+		if r.FormValue("grant_type") != "urn:ietf:params:oauth:grant-type:jwt-bearer" || len(strings.Split(r.FormValue("assertion"), ".")) != 3 {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		w.Write([]byte(`{"access_token":"token","expires_in":3600}`))
+		return
+	}
+	if r.Header.Get("Authorization") != "Bearer token" {
+		w.WriteHeader(http.StatusUnauthorized)
+		return
+	}
+	switch {
+	case r.Method == "POST" && path == "/upload/storage/v1/b/bucket/o":
+		object := &gcsObject{}
+		json.NewDecoder(r.Body).Decode(object)
+		object.Name = r.URL.Query().Get("name")
+		session := fmt.Sprintf("/upload/session/%d", len(h.sessions))
+		h.sessions[session] = object
+		w.Header().Set("Location", "http://"+r.Host+session)
+	case r.Method == "PUT" && h.sessions[path] != nil:
+		var first, last int64
+		var total string
+		contentRange := strings.TrimPrefix(r.Header.Get("Content-Range"), "bytes ")
+		if strings.HasPrefix(contentRange, "*/") {
+			total = strings.TrimPrefix(contentRange, "*/")
+		} else if _, e := fmt.Sscanf(contentRange, "%d-%d/%s", &first, &last, &total); e != nil || first != int64(len(h.uploads[path])) {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		data, _ := ioutil.ReadAll(r.Body)
+		h.uploads[path] = append(h.uploads[path], data...)
+		if size, e := strconv.Atoi(total); e != nil || size != len(h.uploads[path]) {
+			w.Header().Set("Range", fmt.Sprintf("bytes=0-%d", len(h.uploads[path])-1))
+			w.WriteHeader(gcsResumeIncomplete)
+			return
+		}
+		object := *h.sessions[path]
+		sum := md5.Sum(h.uploads[path])
+		object.MD5Hash = base64.StdEncoding.EncodeToString(sum[:])
+		object.Size = strconv.Itoa(len(h.uploads[path]))
+		object.Updated = "2016-07-01T10:30:00.000Z"
+		h.objects[object.Name] = object
+		h.data[object.Name] = h.uploads[path]
+		json.NewEncoder(w).Encode(object)
+	case r.Method == "GET" && path == "/storage/v1/b/bucket/o":
+		list := gcsObjectList{}
+		prefix := r.URL.Query().Get("prefix")
+		for name, object := range h.objects {
+			if strings.HasPrefix(name, prefix) {
+				list.Items = append(list.Items, object)
+			}
+		}
+		json.NewEncoder(w).Encode(list)
+	case r.Method == "GET" && strings.HasPrefix(path, "/storage/v1/b/bucket/o/"):
+		name, _ := url.QueryUnescape(strings.TrimPrefix(path, "/storage/v1/b/bucket/o/"))
+		object, ok := h.objects[name]
+		if !ok {
+			w.WriteHeader(http.StatusNotFound)
+			w.Write([]byte(`{"error":{"code":404,"message":"No such object: bucket/` + name + `"}}`))
+			return
+		}
+		if r.URL.Query().Get("alt") == "media" {
+			w.Header().Set("X-Goog-Hash", "crc32c=n03x6A==,md5="+object.MD5Hash)
+			w.Write(h.data[name])
+			return
+		}
+		json.NewEncoder(w).Encode(object)
+	default:
+		w.WriteHeader(http.StatusNotImplemented)
+	}
+}
+
+// newGCSTestClient - client of the handler, authorized with a new
+// service account.
+func newGCSTestClient(c *C, serverURL, root, urlPath string) Client {
+	key, e := rsa.GenerateKey(rand.Reader, 1024)
+	c.Assert(e, IsNil)
+	account, e := json.Marshal(gcsServiceAccount{
+		Type:        "service_account",
+		ProjectID:   "project",
+		PrivateKey:  string(pem.EncodeToMemory(&pem.Block{Type: "RSA PRIVATE KEY", Bytes: x509.MarshalPKCS1PrivateKey(key)})),
+		ClientEmail: "mc@project.iam.gserviceaccount.com",
+		TokenURI:    serverURL + "/token",
+	})
+	c.Assert(e, IsNil)
+	credentials := filepath.Join(root, "credentials.json")
+	c.Assert(ioutil.WriteFile(credentials, account, 0600), IsNil)
+	clnt, err := gcsNew(&Config{HostURL: serverURL + urlPath, Credentials: credentials})
+	c.Assert(err, IsNil)
+	return clnt
+}
+
+func (s *TestSuite) TestGCSClient(c *C) {
+	root, e := ioutil.TempDir(os.TempDir(), "gcs-")
+	c.Assert(e, IsNil)
+	defer os.RemoveAll(root)
+	handler := newGCSHandler()
+	server := httptest.NewServer(handler)
+	defer server.Close()
+
+	// Objects larger than the part size are uploaded in chunks.
+	data := bytes.Repeat([]byte("0123456789abcdef"), 6*1024*1024/16+10)
+	clnt := newGCSTestClient(c, server.URL, root, "/bucket/dir/object name")
+	n, err := clnt.Put(context.Background(), bytes.NewReader(data), int64(len(data)), "text/plain", nil, PutOpts{
+		PartSize: 5 * 1024 * 1024,
+		Metadata: map[string]string{"Cache-Control": "no-cache", "X-Amz-Meta-Mc-Mode": "644"},
+		Checksum: true,
+	})
+	c.Assert(err, IsNil)
+	c.Assert(n, Equals, int64(len(data)))
+	c.Assert(handler.objects["dir/object name"].ContentType, Equals, "text/plain")
+	c.Assert(handler.objects["dir/object name"].Metadata, DeepEquals, map[string]string{"Mc-Mode": "644"})
+
+	metadata, err := clnt.(*gcsClient).GetMetadata(EncryptOpts{})
+	c.Assert(err, IsNil)
+	c.Assert(metadata, DeepEquals, map[string]string{
+		"Content-Type":       "text/plain",
+		"Cache-Control":      "no-cache",
+		"X-Amz-Meta-Mc-Mode": "644",
+	})
+
+	content, err := clnt.Stat()
+	c.Assert(err, IsNil)
+	c.Assert(content.Size, Equals, int64(len(data)))
+	c.Assert(content.Type.IsRegular(), Equals, true)
+
+	reader, err := clnt.Get(context.Background(), GetOpts{Checksum: true})
+	c.Assert(err, IsNil)
+	read, e := ioutil.ReadAll(reader)
+	c.Assert(e, IsNil)
+	c.Assert(bytes.Equal(read, data), Equals, true)
+
+	sum := md5.Sum(data)
+	etag, etagPartSize, err := clnt.Checksum(-1)
+	c.Assert(err, IsNil)
+	c.Assert(etag, Equals, fmt.Sprintf("%x", sum))
+	c.Assert(etagPartSize, Equals, int64(0))
+
+	// Prefixes of objects are folders.
+	dir := newGCSTestClient(c, server.URL, root, "/bucket/dir")
+	content, err = dir.Stat()
+	c.Assert(err, IsNil)
+	c.Assert(content.Type.IsDir(), Equals, true)
+	var names []string
+	for content := range dir.List(context.Background(), true, false) {
+		c.Assert(content.Err, IsNil)
+		names = append(names, content.URL.Path)
+	}
+	c.Assert(names, DeepEquals, []string{"/bucket/dir/object name"})
+
+	missing := newGCSTestClient(c, server.URL, root, "/bucket/missing")
+	_, err = missing.Stat()
+	c.Assert(err, NotNil)
+	_, ok := err.ToGoError().(ObjectMissing)
+	c.Assert(ok, Equals, true)
+}
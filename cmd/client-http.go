@@ -0,0 +1,306 @@
+/*
+ * Minio Client (C) 2016 Minio, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"context"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"os"
+	"strconv"
+	"time"
+
+	"github.com/minio/minio/pkg/probe"
+)
+
+// Read-only client of plain HTTP(S) URLs without an alias, each URL is
+// a single object. All other operations need an alias of the server.
+type httpClient struct {
+	targetURL  *ClientURL
+	httpClient *http.Client
+}
+
+// httpNew returns an initialized httpClient.
+func httpNew(urlStr string) (Client, *probe.Error) {
+	opts, err := getTransportOpts()
+	if err != nil {
+		return nil, err.Trace(urlStr)
+	}
+	transport, err := newClientTransport(&Config{HostURL: urlStr, Insecure: globalInsecure, Transport: opts})
+	if err != nil {
+		return nil, err.Trace(urlStr)
+	}
+	return &httpClient{
+		targetURL:  newClientURL(urlStr),
+		httpClient: &http.Client{Transport: transport},
+	}, nil
+}
+
+// GetURL get url.
+func (c *httpClient) GetURL() ClientURL {
+	return *c.targetURL
+}
+
+// unsupported - error of operations which need an alias.
+func (c *httpClient) unsupported() *probe.Error {
+	return errInvalidAliasedURL(c.targetURL.String())
+}
+
+// do - sends a request for the URL within the context, failed
+// responses are errors.
+func (c *httpClient) do(ctx context.Context, method string, header http.Header) (*http.Response, *probe.Error) {
+	urlStr := c.targetURL.String()
+	req, e := http.NewRequest(method, urlStr, nil)
+	if e != nil {
+		return nil, probe.NewError(e).Trace(urlStr)
+	}
+	req = req.WithContext(ctx)
+	for k, v := range header {
+		req.Header[k] = v
+	}
+	resp, e := c.httpClient.Do(req)
+	if e != nil {
+		return nil, probe.NewError(e).Trace(urlStr)
+	}
+	if resp.StatusCode >= http.StatusBadRequest {
+		resp.Body.Close()
+		switch resp.StatusCode {
+		case http.StatusNotFound:
+			return nil, probe.NewError(PathNotFound{Path: urlStr})
+		case http.StatusUnauthorized, http.StatusForbidden:
+			return nil, probe.NewError(PathInsufficientPermission{Path: urlStr})
+		}
+		return nil, probe.NewError(httpStatusError{StatusCode: resp.StatusCode, Status: resp.Status}).Trace(urlStr)
+	}
+	return resp, nil
+}
+
+// httpStatusError - failed response of the server.
+type httpStatusError struct {
+	StatusCode int
+	Status     string
+}
+
+func (e httpStatusError) Error() string {
+	return "Server responded with ‘" + e.Status + "’."
+}
+
+// Stat - size and modification time of the object from the response
+// to a HEAD request, servers not allowing HEAD are sent a GET whose
+// body is not read.
+func (c *httpClient) Stat() (*ClientContent, *probe.Error) {
+	resp, err := c.do(context.Background(), "HEAD", nil)
+	if err != nil {
+		if statusErr, ok := err.ToGoError().(httpStatusError); !ok || statusErr.StatusCode != http.StatusMethodNotAllowed {
+			return nil, err.Trace(c.targetURL.String())
+		}
+		if resp, err = c.do(context.Background(), "GET", nil); err != nil {
+			return nil, err.Trace(c.targetURL.String())
+		}
+	}
+	resp.Body.Close()
+	content := &ClientContent{
+		URL:  *c.targetURL,
+		Size: resp.ContentLength,
+		Type: os.FileMode(0644),
+	}
+	if t, e := http.ParseTime(resp.Header.Get("Last-Modified")); e == nil {
+		content.Time = t
+	}
+	return content, nil
+}
+
+// List - lists the object itself, servers are not listed.
+func (c *httpClient) List(ctx context.Context, recursive, incomplete bool) <-chan *ClientContent {
+	contentCh := make(chan *ClientContent, 1)
+	defer close(contentCh)
+	if incomplete {
+		return contentCh
+	}
+	content, err := c.Stat()
+	if err != nil {
+		contentCh <- &ClientContent{Err: err.Trace(c.targetURL.String())}
+		return contentCh
+	}
+	contentCh <- content
+	return contentCh
+}
+
+// Get - downloads the object, ranges are requested with a Range
+// header. Data before the range is skipped if the server ignores it.
+func (c *httpClient) Get(ctx context.Context, opts GetOpts) (io.Reader, *probe.Error) {
+	if !opts.SSE.isEmpty() {
+		return nil, c.unsupported()
+	}
+	header := make(http.Header)
+	if opts.Offset > 0 || opts.Length > 0 {
+		byteRange := "bytes=" + strconv.FormatInt(opts.Offset, 10) + "-"
+		if opts.Length > 0 {
+			byteRange += strconv.FormatInt(opts.Offset+opts.Length-1, 10)
+		}
+		header.Set("Range", byteRange)
+	}
+	resp, err := c.do(ctx, "GET", header)
+	if err != nil {
+		if statusErr, ok := err.ToGoError().(httpStatusError); ok && statusErr.StatusCode == http.StatusRequestedRangeNotSatisfiable {
+			return nil, probe.NewError(ObjectRangeInvalid{Object: c.targetURL.String(), Offset: opts.Offset})
+		}
+		return nil, err.Trace(c.targetURL.String())
+	}
+	var reader io.Reader = resp.Body
+	if resp.StatusCode != http.StatusPartialContent && opts.Offset > 0 {
+		if _, e := io.CopyN(ioutil.Discard, resp.Body, opts.Offset); e != nil {
+			resp.Body.Close()
+			return nil, probe.NewError(ObjectRangeInvalid{Object: c.targetURL.String(), Offset: opts.Offset})
+		}
+	}
+	if opts.Length > 0 {
+		reader = io.LimitReader(reader, opts.Length)
+	}
+	return readCloser{Reader: reader, Closer: resp.Body}, nil
+}
+
+// readCloser - reader closing the underlying stream of a wrapped
+// reader.
+type readCloser struct {
+	io.Reader
+	io.Closer
+}
+
+// Checksum - ETag computed from the data of the object.
+func (c *httpClient) Checksum(partSize int64) (string, int64, *probe.Error) {
+	if partSize < 0 {
+		partSize = 0
+	}
+	reader, err := c.Get(context.Background(), GetOpts{})
+	if err != nil {
+		return "", 0, err.Trace(c.targetURL.String())
+	}
+	defer reader.(io.Closer).Close()
+	etag, err := readETag(reader, partSize)
+	if err != nil {
+		return "", 0, err.Trace(c.targetURL.String())
+	}
+	return etag, partSize, nil
+}
+
+// Put - needs an alias.
+func (c *httpClient) Put(ctx context.Context, reader io.Reader, size int64, contentType string, progress io.Reader, opts PutOpts) (int64, *probe.Error) {
+	return 0, c.unsupported()
+}
+
+// Copy - needs an alias.
+func (c *httpClient) Copy(ctx context.Context, source string, size int64, progress io.Reader, opts CopyOpts) *probe.Error {
+	return c.unsupported()
+}
+
+// MakeBucket - needs an alias.
+func (c *httpClient) MakeBucket(region string) *probe.Error {
+	return c.unsupported()
+}
+
+// GetAccess - needs an alias.
+func (c *httpClient) GetAccess() (string, *probe.Error) {
+	return "", c.unsupported()
+}
+
+// GetAccessRules - needs an alias.
+func (c *httpClient) GetAccessRules() (map[string]string, *probe.Error) {
+	return nil, c.unsupported()
+}
+
+// SetAccess - needs an alias.
+func (c *httpClient) SetAccess(access string) *probe.Error {
+	return c.unsupported()
+}
+
+// SelectObjectContent - needs an alias.
+func (c *httpClient) SelectObjectContent(expression string, sse EncryptOpts, opts SelectObjectOpts) (io.ReadCloser, *probe.Error) {
+	return nil, c.unsupported()
+}
+
+// ShareDownload - needs an alias.
+func (c *httpClient) ShareDownload(expires time.Duration, reqParams url.Values) (string, *probe.Error) {
+	return "", c.unsupported()
+}
+
+// ShareUpload - needs an alias.
+func (c *httpClient) ShareUpload(opts ShareUploadOpts) (map[string]string, *probe.Error) {
+	return nil, c.unsupported()
+}
+
+// ShareUploadURL - needs an alias.
+func (c *httpClient) ShareUploadURL(opts ShareUploadOpts) (string, *probe.Error) {
+	return "", c.unsupported()
+}
+
+// Watch - needs an alias.
+func (c *httpClient) Watch(params WatchParams) (*WatchObject, *probe.Error) {
+	return nil, c.unsupported()
+}
+
+// Unwatch - needs an alias.
+func (c *httpClient) Unwatch(params WatchParams) *probe.Error {
+	return c.unsupported()
+}
+
+// RestoreObject - needs an alias.
+func (c *httpClient) RestoreObject(days int, tier string) *probe.Error {
+	return c.unsupported()
+}
+
+// RestoreStatus - needs an alias.
+func (c *httpClient) RestoreStatus(sse EncryptOpts) (ObjectRestoreStatus, *probe.Error) {
+	return ObjectRestoreStatus{}, c.unsupported()
+}
+
+// ListVersions - needs an alias.
+func (c *httpClient) ListVersions(recursive bool) <-chan *ClientContent {
+	contentCh := make(chan *ClientContent, 1)
+	contentCh <- &ClientContent{Err: c.unsupported()}
+	close(contentCh)
+	return contentCh
+}
+
+// GetVersion - needs an alias.
+func (c *httpClient) GetVersion(versionID string) (io.Reader, *probe.Error) {
+	return nil, c.unsupported()
+}
+
+// RemoveVersion - needs an alias.
+func (c *httpClient) RemoveVersion(versionID string) *probe.Error {
+	return c.unsupported()
+}
+
+// Remove - needs an alias.
+func (c *httpClient) Remove(ctx context.Context, incomplete bool) *probe.Error {
+	return c.unsupported()
+}
+
+// RemoveBulk - needs an alias.
+func (c *httpClient) RemoveBulk(contentCh <-chan *ClientContent) <-chan *ClientContent {
+	resultCh := make(chan *ClientContent)
+	go func() {
+		defer close(resultCh)
+		for content := range contentCh {
+			resultCh <- &ClientContent{URL: content.URL, Err: c.unsupported()}
+		}
+	}()
+	return resultCh
+}
@@ -0,0 +1,93 @@
+/*
+ * Minio Client (C) 2016 Minio, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"time"
+
+	. "gopkg.in/check.v1"
+)
+
+func (s *TestSuite) TestHTTPClient(c *C) {
+	data := bytes.Repeat([]byte("0123456789"), 1000)
+	modTime := time.Date(2016, 5, 1, 10, 0, 0, 0, time.UTC)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/file.iso":
+			http.ServeContent(w, r, "file.iso", modTime, bytes.NewReader(data))
+		case "/norange.iso":
+			// Ranges and HEAD requests are ignored.
+			if r.Method != "GET" {
+				w.WriteHeader(http.StatusMethodNotAllowed)
+				return
+			}
+			w.Header().Set("Content-Length", strconv.Itoa(len(data)))
+			w.Write(data)
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	for _, name := range []string{"/file.iso", "/norange.iso"} {
+		clnt, err := httpNew(server.URL + name)
+		c.Assert(err, IsNil)
+		c.Assert(clnt.GetURL().Type == ObjectStorage, Equals, true)
+
+		content, err := clnt.Stat()
+		c.Assert(err, IsNil)
+		c.Assert(content.Size, Equals, int64(len(data)))
+		c.Assert(content.Type.IsRegular(), Equals, true)
+
+		reader, err := clnt.Get(context.Background(), GetOpts{})
+		c.Assert(err, IsNil)
+		read, e := ioutil.ReadAll(reader)
+		c.Assert(e, IsNil)
+		c.Assert(read, DeepEquals, data)
+		c.Assert(reader.(io.Closer).Close(), IsNil)
+
+		reader, err = clnt.Get(context.Background(), GetOpts{Offset: 1234, Length: 100})
+		c.Assert(err, IsNil)
+		read, e = ioutil.ReadAll(reader)
+		c.Assert(e, IsNil)
+		c.Assert(read, DeepEquals, data[1234:1334])
+		c.Assert(reader.(io.Closer).Close(), IsNil)
+
+		_, err = clnt.Put(context.Background(), bytes.NewReader(data), int64(len(data)), "", nil, PutOpts{})
+		c.Assert(err, NotNil)
+	}
+
+	clnt, err := httpNew(server.URL + "/file.iso")
+	c.Assert(err, IsNil)
+	content, err := clnt.Stat()
+	c.Assert(err, IsNil)
+	c.Assert(content.Time.Equal(modTime), Equals, true)
+
+	clnt, err = httpNew(server.URL + "/missing.iso")
+	c.Assert(err, IsNil)
+	_, err = clnt.Stat()
+	c.Assert(err, NotNil)
+	_, ok := err.ToGoError().(PathNotFound)
+	c.Assert(ok, Equals, true)
+}
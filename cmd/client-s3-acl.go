@@ -0,0 +1,160 @@
+/*
+ * Minio Client (C) 2016 Minio, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"encoding/xml"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"github.com/minio/minio/pkg/probe"
+)
+
+// Permissions that can be granted by an access control list.
+const (
+	aclPermissionRead        = "READ"
+	aclPermissionWrite       = "WRITE"
+	aclPermissionReadACP     = "READ_ACP"
+	aclPermissionWriteACP    = "WRITE_ACP"
+	aclPermissionFullControl = "FULL_CONTROL"
+)
+
+// Types of grantees of an access control list.
+const (
+	aclGranteeCanonicalUser = "CanonicalUser"
+	aclGranteeEmail         = "AmazonCustomerByEmail"
+	aclGranteeGroup         = "Group"
+)
+
+// aclGrantHeaders - headers granting a permission, grants are sent as
+// headers so that the owner does not have to be known.
+var aclGrantHeaders = map[string]string{
+	aclPermissionRead:        "X-Amz-Grant-Read",
+	aclPermissionWrite:       "X-Amz-Grant-Write",
+	aclPermissionReadACP:     "X-Amz-Grant-Read-Acp",
+	aclPermissionWriteACP:    "X-Amz-Grant-Write-Acp",
+	aclPermissionFullControl: "X-Amz-Grant-Full-Control",
+}
+
+// cannedACLs - canned access control lists understood by the server.
+var cannedACLs = []string{
+	"private",
+	"public-read",
+	"public-read-write",
+	"authenticated-read",
+	"aws-exec-read",
+	"bucket-owner-read",
+	"bucket-owner-full-control",
+	"log-delivery-write",
+}
+
+// aclOwner - owner of a bucket or object.
+type aclOwner struct {
+	ID          string `xml:"ID" json:"id"`
+	DisplayName string `xml:"DisplayName,omitempty" json:"displayName,omitempty"`
+}
+
+// aclGrantee - receiver of a grant, identified by its canonical user
+// id, email address or group uri depending on its type.
+type aclGrantee struct {
+	Type         string `xml:"type,attr" json:"type"`
+	ID           string `xml:"ID,omitempty" json:"id,omitempty"`
+	DisplayName  string `xml:"DisplayName,omitempty" json:"displayName,omitempty"`
+	EmailAddress string `xml:"EmailAddress,omitempty" json:"emailAddress,omitempty"`
+	URI          string `xml:"URI,omitempty" json:"uri,omitempty"`
+}
+
+// String - grantee in the syntax of grant headers.
+func (g aclGrantee) String() string {
+	switch g.Type {
+	case aclGranteeEmail:
+		return `emailAddress="` + g.EmailAddress + `"`
+	case aclGranteeGroup:
+		return `uri="` + g.URI + `"`
+	}
+	return `id="` + g.ID + `"`
+}
+
+// aclGrant - a permission given to a grantee.
+type aclGrant struct {
+	Grantee    aclGrantee `xml:"Grantee" json:"grantee"`
+	Permission string     `xml:"Permission" json:"permission"`
+}
+
+// accessControlPolicy - access control list of a bucket or object.
+type accessControlPolicy struct {
+	XMLName xml.Name   `xml:"AccessControlPolicy" json:"-"`
+	Owner   aclOwner   `xml:"Owner" json:"owner"`
+	Grants  []aclGrant `xml:"AccessControlList>Grant" json:"grants"`
+}
+
+// GetACL - get access control list of the bucket or object.
+func (c *s3Client) GetACL() (accessControlPolicy, *probe.Error) {
+	bucket, object := c.url2BucketAndObject()
+	if bucket == "" {
+		return accessControlPolicy{}, probe.NewError(BucketNameEmpty{})
+	}
+	resp, err := c.executeMethod("GET", s3RequestMetadata{
+		bucketName:  bucket,
+		objectName:  object,
+		queryValues: url.Values{"acl": []string{""}},
+	})
+	if err != nil {
+		return accessControlPolicy{}, c.objectError(err, bucket)
+	}
+	defer resp.Body.Close()
+
+	acl := accessControlPolicy{}
+	if e := xml.NewDecoder(resp.Body).Decode(&acl); e != nil {
+		return accessControlPolicy{}, probe.NewError(e)
+	}
+	return acl, nil
+}
+
+// SetACL - replace access control list of the bucket or object, either
+// by a canned access control list or by grants.
+func (c *s3Client) SetACL(canned string, grants []aclGrant) *probe.Error {
+	bucket, object := c.url2BucketAndObject()
+	if bucket == "" {
+		return probe.NewError(BucketNameEmpty{})
+	}
+	header := make(http.Header)
+	if canned != "" {
+		header.Set("X-Amz-Acl", canned)
+	}
+	// Grantees of the same permission share a header.
+	grantees := make(map[string][]string)
+	for _, grant := range grants {
+		grantees[grant.Permission] = append(grantees[grant.Permission], grant.Grantee.String())
+	}
+	for permission, values := range grantees {
+		header.Set(aclGrantHeaders[permission], strings.Join(values, ", "))
+	}
+
+	resp, err := c.executeMethod("PUT", s3RequestMetadata{
+		bucketName:  bucket,
+		objectName:  object,
+		queryValues: url.Values{"acl": []string{""}},
+		header:      header,
+	})
+	if err != nil {
+		return c.objectError(err, bucket)
+	}
+	resp.Body.Close()
+	return nil
+}
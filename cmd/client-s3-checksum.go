@@ -0,0 +1,328 @@
+/*
+ * Minio Client (C) 2016 Minio, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"context"
+	"crypto/md5"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"hash"
+	"io"
+	"net/http"
+	"net/url"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/minio/minio/pkg/probe"
+)
+
+// etagHash - computes the ETag S3 assigns to uploaded data, the MD5 of
+// the data for single part uploads, or the MD5 of the MD5s of all parts
+// followed by the number of parts for multipart uploads.
+type etagHash struct {
+	// Size of parts of a multipart upload, zero for single part.
+	partSize int64
+	// Sizes of the leading parts, if they differ from the part size.
+	partSizes []int64
+	part      hash.Hash
+	partLen   int64
+	sums      []byte
+	parts     int
+}
+
+// newETagHash - new hash of an upload, zero part size for single part.
+func newETagHash(partSize int64) *etagHash {
+	return &etagHash{partSize: partSize, part: md5.New()}
+}
+
+// newETagPartsHash - new hash of a multipart upload with parts of the
+// given sizes, data past the last part is added to it.
+func newETagPartsHash(partSizes []int64) *etagHash {
+	last := partSizes[len(partSizes)-1]
+	if last == 0 {
+		// Only an empty object has an empty part.
+		last = 1
+	}
+	return &etagHash{partSize: last, partSizes: partSizes[:len(partSizes)-1], part: md5.New()}
+}
+
+// currentPartSize - size of the part data is written to.
+func (h *etagHash) currentPartSize() int64 {
+	if h.parts < len(h.partSizes) {
+		return h.partSizes[h.parts]
+	}
+	return h.partSize
+}
+
+// Write - implements io.Writer, data is split into parts.
+func (h *etagHash) Write(p []byte) (int, error) {
+	n := len(p)
+	for len(p) > 0 {
+		partSize := h.currentPartSize()
+		if partSize > 0 && h.partLen == partSize {
+			h.sums = h.part.Sum(h.sums)
+			h.parts++
+			h.part.Reset()
+			h.partLen = 0
+			partSize = h.currentPartSize()
+		}
+		chunk := p
+		if partSize > 0 && int64(len(chunk)) > partSize-h.partLen {
+			chunk = chunk[:partSize-h.partLen]
+		}
+		h.part.Write(chunk)
+		h.partLen += int64(len(chunk))
+		p = p[len(chunk):]
+	}
+	return n, nil
+}
+
+// ETag - ETag of the data written so far.
+func (h *etagHash) ETag() string {
+	if h.partSize == 0 {
+		return hex.EncodeToString(h.part.Sum(nil))
+	}
+	// The last part is always uploaded, even if it is empty.
+	sums := h.part.Sum(append([]byte{}, h.sums...))
+	sum := md5.Sum(sums)
+	return hex.EncodeToString(sum[:]) + "-" + strconv.Itoa(h.parts+1)
+}
+
+var checksumETag = regexp.MustCompile(`^[0-9a-f]{32}(-[0-9]+)?$`)
+
+// trimETag - ETag without surrounding quotes.
+func trimETag(etag string) string {
+	return strings.Trim(etag, "\"")
+}
+
+// isChecksumETag - the ETag is derived from the MD5 of the data, which
+// is not the case for objects encrypted with SSE-C or SSE-KMS.
+func isChecksumETag(etag string, header http.Header) bool {
+	if header.Get("X-Amz-Server-Side-Encryption-Customer-Algorithm") != "" ||
+		header.Get("X-Amz-Server-Side-Encryption") == "aws:kms" {
+		return false
+	}
+	return checksumETag.MatchString(trimETag(etag))
+}
+
+// etagPartsCount - number of parts of a multipart ETag, zero for
+// single part uploads.
+func etagPartsCount(etag string) int {
+	i := strings.LastIndex(etag, "-")
+	if i < 0 {
+		return 0
+	}
+	n, _ := strconv.Atoi(trimETag(etag[i+1:]))
+	return n
+}
+
+// checksumReader - verifies the data read against the checksum of the
+// object once all of it is read.
+type checksumReader struct {
+	reader   io.Reader
+	hash     io.Writer
+	sum      func() string
+	expected string
+	object   string
+}
+
+// newETagReader - verifies the data against the ETag of the object.
+func newETagReader(reader io.Reader, h *etagHash, etag, object string) *checksumReader {
+	return &checksumReader{reader: reader, hash: h, sum: h.ETag, expected: trimETag(etag), object: object}
+}
+
+// newSHA256Reader - verifies the data against its base64 encoded
+// SHA256, as returned by ‘x-amz-checksum-sha256’.
+func newSHA256Reader(reader io.Reader, checksum, object string) *checksumReader {
+	h := sha256.New()
+	sum := func() string {
+		return base64.StdEncoding.EncodeToString(h.Sum(nil))
+	}
+	return &checksumReader{reader: reader, hash: h, sum: sum, expected: checksum, object: object}
+}
+
+// Read - implements io.Reader, a mismatch is returned instead of EOF.
+func (r *checksumReader) Read(p []byte) (int, error) {
+	n, e := r.reader.Read(p)
+	r.hash.Write(p[:n])
+	if e == io.EOF {
+		if computed := r.sum(); computed != r.expected {
+			return n, ObjectChecksumMismatch{Object: r.object, Expected: r.expected, Actual: computed}
+		}
+	}
+	return n, e
+}
+
+// isFullObjectSHA256 - the SHA256 checksum covers the data of the whole
+// object, unlike checksums of multipart uploads which are computed
+// from the checksums of their parts.
+func isFullObjectSHA256(checksum string) bool {
+	return checksum != "" && !strings.Contains(checksum, "-")
+}
+
+// verifyETag - compares the ETag returned for an upload with the one
+// computed locally, ETags not derived from the data are not verified.
+func verifyETag(object, etag string, header http.Header, h *etagHash) *probe.Error {
+	if !isChecksumETag(etag, header) {
+		return nil
+	}
+	if computed := h.ETag(); computed != trimETag(etag) {
+		return probe.NewError(ObjectChecksumMismatch{Object: object, Expected: trimETag(etag), Actual: computed})
+	}
+	return nil
+}
+
+// readETag - ETag of all data of the reader, split into parts of the
+// given size, zero for a single part. The reader is closed if possible.
+func readETag(reader io.Reader, partSize int64) (string, *probe.Error) {
+	if closer, ok := reader.(io.Closer); ok {
+		defer closer.Close()
+	}
+	h := newETagHash(partSize)
+	if _, e := io.Copy(h, reader); e != nil {
+		return "", probe.NewError(e)
+	}
+	return h.ETag(), nil
+}
+
+// etagPartSizes - sizes of the parts the ETag of an object of the
+// given size was computed from, read with a HEAD of every part. Single
+// part objects have no parts, false is returned if the host does not
+// report the sizes of parts.
+func (c *s3Client) etagPartSizes(bucket, object, etag string, size int64, sse EncryptOpts) ([]int64, bool, *probe.Error) {
+	parts := etagPartsCount(etag)
+	if parts == 0 {
+		return nil, true, nil
+	}
+	header := make(http.Header)
+	sse.setGetHeaders(header)
+	partSizes := make([]int64, 0, parts)
+	var total int64
+	for partNumber := 1; partNumber <= parts; partNumber++ {
+		resp, err := c.executeMethod("HEAD", s3RequestMetadata{
+			bucketName:  bucket,
+			objectName:  object,
+			queryValues: url.Values{"partNumber": []string{strconv.Itoa(partNumber)}},
+			header:      header,
+		})
+		if err != nil {
+			return nil, false, err.Trace(bucket, object)
+		}
+		resp.Body.Close()
+		if resp.ContentLength < 0 {
+			return nil, false, nil
+		}
+		partSizes = append(partSizes, resp.ContentLength)
+		total += resp.ContentLength
+	}
+	// Hosts ignoring part numbers report the size of the object.
+	if total != size {
+		return nil, false, nil
+	}
+	return partSizes, true, nil
+}
+
+// uniformPartSize - size of parts which all have the same size but the
+// last, which is not larger, -1 otherwise. Zero for single part.
+func uniformPartSize(partSizes []int64) int64 {
+	if len(partSizes) == 0 {
+		return 0
+	}
+	partSize := partSizes[0]
+	for i, size := range partSizes {
+		if size > partSize || (i < len(partSizes)-1 && size != partSize) {
+			return -1
+		}
+	}
+	if partSize == 0 {
+		// Only an empty object has an empty part.
+		return 1
+	}
+	return partSize
+}
+
+// newChecksumReader - verifies the body of a GET of a whole object,
+// against its SHA256 if the host returned one, against its ETag
+// otherwise.
+func (c *s3Client) newChecksumReader(bucket, object string, resp *http.Response, sse EncryptOpts) (io.Reader, *probe.Error) {
+	if checksum := resp.Header.Get("X-Amz-Checksum-Sha256"); isFullObjectSHA256(checksum) {
+		return newSHA256Reader(resp.Body, checksum, c.targetURL.String()), nil
+	}
+	etag := resp.Header.Get("ETag")
+	if !isChecksumETag(etag, resp.Header) {
+		return resp.Body, nil
+	}
+	partSizes, ok, err := c.etagPartSizes(bucket, object, etag, resp.ContentLength, sse)
+	if err != nil {
+		resp.Body.Close()
+		return nil, err.Trace(bucket, object)
+	}
+	if !ok {
+		resp.Body.Close()
+		return nil, probe.NewError(ObjectChecksumUnverifiable{Object: c.targetURL.String()})
+	}
+	h := newETagHash(0)
+	if len(partSizes) > 0 {
+		h = newETagPartsHash(partSizes)
+	}
+	return newETagReader(resp.Body, h, etag, c.targetURL.String()), nil
+}
+
+// Checksum - ETag of the object, along with the size of its parts. If
+// a part size is requested which the ETag was not computed from, or the
+// ETag is not derived from the data, the ETag is computed from the data
+// in parts of that size. A negative part size accepts any ETag.
+func (c *s3Client) Checksum(partSize int64) (string, int64, *probe.Error) {
+	bucket, object := c.url2BucketAndObject()
+	resp, err := c.executeMethod("HEAD", s3RequestMetadata{
+		bucketName: bucket,
+		objectName: object,
+	})
+	if err != nil {
+		return "", 0, c.objectError(err, bucket).Trace(bucket, object)
+	}
+	resp.Body.Close()
+
+	// ETags of parts of different sizes are computed from the data.
+	etag := resp.Header.Get("ETag")
+	if isChecksumETag(etag, resp.Header) {
+		partSizes, ok, err := c.etagPartSizes(bucket, object, etag, resp.ContentLength, EncryptOpts{})
+		if err != nil {
+			return "", 0, err.Trace(bucket, object)
+		}
+		etagPartSize := uniformPartSize(partSizes)
+		if ok && etagPartSize >= 0 && (partSize < 0 || partSize == etagPartSize) {
+			return trimETag(etag), etagPartSize, nil
+		}
+	}
+
+	if partSize < 0 {
+		partSize = 0
+	}
+	reader, err := c.Get(context.Background(), GetOpts{})
+	if err != nil {
+		return "", 0, err.Trace(bucket, object)
+	}
+	etag, err = readETag(reader, partSize)
+	if err != nil {
+		return "", 0, err.Trace(bucket, object)
+	}
+	return etag, partSize, nil
+}
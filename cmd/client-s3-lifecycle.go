@@ -0,0 +1,148 @@
+/*
+ * Minio Client (C) 2016 Minio, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"crypto/md5"
+	"encoding/base64"
+	"encoding/xml"
+	"net/http"
+	"net/url"
+
+	"github.com/minio/minio/pkg/probe"
+	"github.com/ricoharisin91/minio-go"
+)
+
+// lifecycleExpiration - expiry of objects, either after a number of
+// days or on a date.
+type lifecycleExpiration struct {
+	Days int    `xml:"Days,omitempty" json:"days,omitempty"`
+	Date string `xml:"Date,omitempty" json:"date,omitempty"`
+}
+
+// lifecycleTransition - transition of objects to another storage class.
+type lifecycleTransition struct {
+	Days         int    `xml:"Days,omitempty" json:"days,omitempty"`
+	Date         string `xml:"Date,omitempty" json:"date,omitempty"`
+	StorageClass string `xml:"StorageClass" json:"storageClass"`
+}
+
+// lifecycleFilter - objects a rule applies to.
+type lifecycleFilter struct {
+	Prefix string `xml:"Prefix" json:"prefix"`
+}
+
+// lifecycleRule - a single lifecycle rule.
+type lifecycleRule struct {
+	XMLName    xml.Name             `xml:"Rule" json:"-"`
+	ID         string               `xml:"ID" json:"id"`
+	Filter     lifecycleFilter      `xml:"Filter" json:"filter"`
+	Status     string               `xml:"Status" json:"status"`
+	Expiration *lifecycleExpiration `xml:"Expiration,omitempty" json:"expiration,omitempty"`
+	Transition *lifecycleTransition `xml:"Transition,omitempty" json:"transition,omitempty"`
+
+	// Rules created before filters were introduced carry the prefix
+	// directly, it is moved into the filter once read.
+	Prefix string `xml:"Prefix,omitempty" json:"-"`
+}
+
+// lifecycleConfiguration - lifecycle configuration of a bucket.
+type lifecycleConfiguration struct {
+	XMLName xml.Name        `xml:"LifecycleConfiguration" json:"-"`
+	Rules   []lifecycleRule `xml:"Rule" json:"rules"`
+}
+
+// GetLifecycle - get lifecycle configuration of the bucket, a bucket
+// without configuration has no rules.
+func (c *s3Client) GetLifecycle() (lifecycleConfiguration, *probe.Error) {
+	bucket, _ := c.url2BucketAndObject()
+	if bucket == "" {
+		return lifecycleConfiguration{}, probe.NewError(BucketNameEmpty{})
+	}
+	resp, err := c.executeMethod("GET", s3RequestMetadata{
+		bucketName:  bucket,
+		queryValues: url.Values{"lifecycle": []string{""}},
+	})
+	if err != nil {
+		if minio.ToErrorResponse(err.ToGoError()).Code == "NoSuchLifecycleConfiguration" {
+			return lifecycleConfiguration{}, nil
+		}
+		return lifecycleConfiguration{}, err.Trace(bucket)
+	}
+	defer resp.Body.Close()
+
+	lifecycle := lifecycleConfiguration{}
+	if e := xml.NewDecoder(resp.Body).Decode(&lifecycle); e != nil {
+		return lifecycleConfiguration{}, probe.NewError(e)
+	}
+	for i, rule := range lifecycle.Rules {
+		if rule.Prefix != "" {
+			lifecycle.Rules[i].Filter.Prefix = rule.Prefix
+			lifecycle.Rules[i].Prefix = ""
+		}
+	}
+	return lifecycle, nil
+}
+
+// SetLifecycle - replace lifecycle configuration of the bucket, an
+// empty configuration removes it.
+func (c *s3Client) SetLifecycle(lifecycle lifecycleConfiguration) *probe.Error {
+	if len(lifecycle.Rules) == 0 {
+		return c.RemoveLifecycle()
+	}
+	bucket, _ := c.url2BucketAndObject()
+	if bucket == "" {
+		return probe.NewError(BucketNameEmpty{})
+	}
+	body, e := xml.Marshal(lifecycle)
+	if e != nil {
+		return probe.NewError(e)
+	}
+	// Content-MD5 is mandatory for this request.
+	sum := md5.Sum(body)
+	header := make(http.Header)
+	header.Set("Content-Md5", base64.StdEncoding.EncodeToString(sum[:]))
+
+	resp, err := c.executeMethod("PUT", s3RequestMetadata{
+		bucketName:   bucket,
+		queryValues:  url.Values{"lifecycle": []string{""}},
+		header:       header,
+		contentBytes: body,
+	})
+	if err != nil {
+		return err.Trace(bucket)
+	}
+	resp.Body.Close()
+	return nil
+}
+
+// RemoveLifecycle - remove lifecycle configuration of the bucket.
+func (c *s3Client) RemoveLifecycle() *probe.Error {
+	bucket, _ := c.url2BucketAndObject()
+	if bucket == "" {
+		return probe.NewError(BucketNameEmpty{})
+	}
+	resp, err := c.executeMethod("DELETE", s3RequestMetadata{
+		bucketName:  bucket,
+		queryValues: url.Values{"lifecycle": []string{""}},
+	})
+	if err != nil {
+		return err.Trace(bucket)
+	}
+	resp.Body.Close()
+	return nil
+}
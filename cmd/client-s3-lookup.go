@@ -0,0 +1,106 @@
+/*
+ * Minio Client (C) 2016 Minio, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"net/http"
+	"regexp"
+	"strings"
+)
+
+// Bucket names which are valid as a label of a host name.
+var dnsBucketRgx = regexp.MustCompile(`^[a-z0-9][a-z0-9.-]{1,61}[a-z0-9]$`)
+
+// lookupTransport - rewrites requests to address buckets as
+// configured, ‘dns’ as host and ‘path’ as first segment of the path.
+// Requests signed with signature V4 are signed again, the canonical
+// resource of signature V2 does not depend on the style.
+type lookupTransport struct {
+	lookup    string
+	creds     s3Credentials
+	transport http.RoundTripper
+}
+
+// RoundTrip - rewrites and sends the request.
+func (t lookupTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	host, path, ok := t.rewrite(req)
+	if !ok {
+		return t.transport.RoundTrip(req)
+	}
+
+	// Never modify the original request.
+	newReq := new(http.Request)
+	*newReq = *req
+	newURL := *req.URL
+	newURL.Host = host
+	newURL.Path = path
+	newURL.RawPath = s3EncodePath(path)
+	newReq.URL = &newURL
+	newReq.Host = host
+	newReq.Header = make(http.Header, len(req.Header))
+	for k, v := range req.Header {
+		newReq.Header[k] = v
+	}
+	if region := regionFromAuthV4(req); region != "" {
+		s3SignV4(newReq, t.creds, region, "s3")
+	}
+	return t.transport.RoundTrip(newReq)
+}
+
+// rewrite - host and path of the request in the configured style,
+// false if the request is left as is.
+func (t lookupTransport) rewrite(req *http.Request) (host, path string, ok bool) {
+	// Presigned requests can not be signed again.
+	query := req.URL.Query()
+	if query.Get("X-Amz-Signature") != "" || query.Get("Signature") != "" {
+		return "", "", false
+	}
+	// Location requests are always sent path style.
+	if _, ok := query["location"]; ok {
+		return "", "", false
+	}
+	switch t.lookup {
+	case "dns":
+		segments := strings.SplitN(strings.TrimPrefix(req.URL.Path, "/"), "/", 2)
+		bucket := segments[0]
+		if !dnsBucketRgx.MatchString(bucket) || strings.Contains(bucket, "..") {
+			return "", "", false
+		}
+		// Certificates of hosts only match a single label.
+		if req.URL.Scheme == "https" && strings.Contains(bucket, ".") {
+			return "", "", false
+		}
+		path = "/"
+		if len(segments) == 2 {
+			path += segments[1]
+		}
+		return bucket + "." + req.URL.Host, path, true
+	case "path":
+		if !isVirtualHostStyle(req.URL.Host) {
+			return "", "", false
+		}
+		i := strings.LastIndex(req.URL.Host, ".s3")
+		if i < 0 {
+			i = strings.LastIndex(req.URL.Host, ".storage.googleapis.com")
+		}
+		if i <= 0 {
+			return "", "", false
+		}
+		return req.URL.Host[i+1:], "/" + req.URL.Host[:i] + req.URL.Path, true
+	}
+	return "", "", false
+}
@@ -0,0 +1,131 @@
+/*
+ * Minio Client (C) 2016 Minio, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/minio/minio/pkg/probe"
+)
+
+// objectInfo - metadata of an object as returned by a HEAD request.
+type objectInfo struct {
+	Size         int64
+	LastModified time.Time
+	ETag         string
+	ContentType  string
+	StorageClass string
+	VersionID    string
+	// Server side encryption, ‘AES256’ or ‘aws:kms’ for keys managed
+	// by the server and ‘SSE-C’ for customer provided keys, empty if
+	// the object is not encrypted.
+	Encryption string
+	KMSKeyID   string
+	// Standard and user defined metadata keyed by header name, except
+	// the content type.
+	Metadata map[string]string
+}
+
+// StatObject - metadata of the object or of a version of it, read with
+// a HEAD request instead of listing the object.
+func (c *s3Client) StatObject(versionID string, sse EncryptOpts) (*objectInfo, *probe.Error) {
+	bucket, object := c.url2BucketAndObject()
+	if object == "" {
+		return nil, probe.NewError(ObjectMissing{})
+	}
+	header := make(http.Header)
+	sse.setGetHeaders(header)
+	var queryValues url.Values
+	if versionID != "" {
+		queryValues = url.Values{"versionId": []string{versionID}}
+	}
+	resp, err := c.executeMethod("HEAD", s3RequestMetadata{
+		bucketName:  bucket,
+		objectName:  object,
+		queryValues: queryValues,
+		header:      header,
+	})
+	if err != nil {
+		if versionID != "" {
+			return nil, c.versionError(err, bucket, versionID)
+		}
+		return nil, c.objectError(err, bucket)
+	}
+	resp.Body.Close()
+	return objectInfoFromHeader(resp.Header), nil
+}
+
+// objectInfoFromHeader - metadata of an object from the headers of a
+// response to a HEAD request.
+func objectInfoFromHeader(h http.Header) *objectInfo {
+	info := &objectInfo{
+		ETag:         strings.Trim(h.Get("ETag"), "\""),
+		ContentType:  h.Get("Content-Type"),
+		StorageClass: h.Get("X-Amz-Storage-Class"),
+		VersionID:    h.Get("X-Amz-Version-Id"),
+		Encryption:   h.Get("X-Amz-Server-Side-Encryption"),
+		KMSKeyID:     h.Get("X-Amz-Server-Side-Encryption-Aws-Kms-Key-Id"),
+		Metadata:     metadataFromHeader(h),
+	}
+	// Content type is reported on its own.
+	delete(info.Metadata, "Content-Type")
+	info.Size, _ = strconv.ParseInt(h.Get("Content-Length"), 10, 64)
+	info.LastModified, _ = http.ParseTime(h.Get("Last-Modified"))
+	// Objects of the standard class are not marked.
+	if info.StorageClass == "" {
+		info.StorageClass = "STANDARD"
+	}
+	if h.Get("X-Amz-Server-Side-Encryption-Customer-Algorithm") != "" {
+		info.Encryption = "SSE-C"
+	}
+	return info
+}
+
+// GetMetadata - metadata of the object, i.e. its standard and user
+// defined headers.
+func (c *s3Client) GetMetadata(sse EncryptOpts) (map[string]string, *probe.Error) {
+	bucket, object := c.url2BucketAndObject()
+	if object == "" {
+		return nil, probe.NewError(ObjectMissing{})
+	}
+	metadata, err := c.statMetadata(bucket, object, sse)
+	if err != nil {
+		return nil, c.objectError(err, bucket)
+	}
+	return metadata, nil
+}
+
+// statMetadata - reads metadata of an object with a HEAD request, keys
+// of encrypted objects have to be given.
+func (c *s3Client) statMetadata(bucket, object string, sse EncryptOpts) (map[string]string, *probe.Error) {
+	header := make(http.Header)
+	sse.setGetHeaders(header)
+	resp, err := c.executeMethod("HEAD", s3RequestMetadata{
+		bucketName: bucket,
+		objectName: object,
+		header:     header,
+	})
+	if err != nil {
+		return nil, err.Trace(bucket, object)
+	}
+	resp.Body.Close()
+	return metadataFromHeader(resp.Header), nil
+}
@@ -0,0 +1,675 @@
+/*
+ * Minio Client (C) 2016 Minio, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/xml"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/minio/mc/pkg/hookreader"
+	"github.com/minio/minio/pkg/probe"
+	"github.com/ricoharisin91/minio-go"
+)
+
+const (
+	// Default part size of a multipart upload, objects smaller than
+	// this are uploaded with a single PUT.
+	s3MinPartSize = 64 * 1024 * 1024
+	// Maximum number of parts of a multipart upload.
+	s3MaxPartsCount = 10000
+	// Bounds of part sizes accepted by the server.
+	s3PartSizeLowerLimit = 5 * 1024 * 1024
+	s3PartSizeUpperLimit = 5 * 1024 * 1024 * 1024
+	// Largest object copied with a single request, larger objects are
+	// copied in parts.
+	s3MaxCopySize = 5 * 1024 * 1024 * 1024
+	// Default part size of multipart copies.
+	s3CopyPartSize = 512 * 1024 * 1024
+)
+
+// initiateMultipartUploadResult - response of initiate multipart upload.
+type initiateMultipartUploadResult struct {
+	XMLName  xml.Name `xml:"InitiateMultipartUploadResult"`
+	Bucket   string
+	Key      string
+	UploadID string `xml:"UploadId"`
+}
+
+// completePart - uploaded part, listed to complete a multipart upload.
+type completePart struct {
+	XMLName    xml.Name `xml:"Part" json:"-"`
+	PartNumber int      `json:"partNumber"`
+	ETag       string   `json:"etag"`
+}
+
+// copyPartResult - response of upload part copy.
+type copyPartResult struct {
+	XMLName xml.Name `xml:"CopyPartResult"`
+	ETag    string
+}
+
+// completeMultipartUpload - request body of complete multipart upload.
+type completeMultipartUpload struct {
+	XMLName xml.Name       `xml:"CompleteMultipartUpload"`
+	Parts   []completePart `xml:"Part"`
+}
+
+// completeParts - sorts uploaded parts by part number, parts may be
+// uploaded out of order.
+type completeParts []completePart
+
+func (p completeParts) Len() int           { return len(p) }
+func (p completeParts) Swap(i, j int)      { p[i], p[j] = p[j], p[i] }
+func (p completeParts) Less(i, j int) bool { return p[i].PartNumber < p[j].PartNumber }
+
+// optimalPartSize - smallest multiple of the requested part size which
+// fits the object in the maximum number of parts, unknown sizes use the
+// requested part size. Zero requests the default part size. Parts are
+// at most of the maximum part size, larger objects do not fit.
+func optimalPartSize(size, partSize int64) (int64, *probe.Error) {
+	if partSize <= 0 {
+		partSize = s3MinPartSize
+	}
+	if size > partSize*s3MaxPartsCount {
+		partSize = (size/s3MaxPartsCount/partSize + 1) * partSize
+	}
+	if partSize > s3PartSizeUpperLimit {
+		partSize = s3PartSizeUpperLimit
+	}
+	if size > partSize*s3MaxPartsCount {
+		return 0, errObjectTooLarge(size).Trace(strconv.FormatInt(size, 10))
+	}
+	return partSize, nil
+}
+
+// newMultipartUpload - initiates a multipart upload, header is sent
+// along, e.g. for content type or encryption.
+func (c *s3Client) newMultipartUpload(bucket, object string, header http.Header) (string, *probe.Error) {
+	resp, err := c.executeMethod("POST", s3RequestMetadata{
+		bucketName:  bucket,
+		objectName:  object,
+		queryValues: url.Values{"uploads": []string{""}},
+		header:      header,
+	})
+	if err != nil {
+		return "", err.Trace(bucket, object)
+	}
+	defer resp.Body.Close()
+
+	result := initiateMultipartUploadResult{}
+	if e := xml.NewDecoder(resp.Body).Decode(&result); e != nil {
+		return "", probe.NewError(e)
+	}
+	return result.UploadID, nil
+}
+
+// uploadPart - uploads a single part of a multipart upload.
+func (c *s3Client) uploadPart(bucket, object, uploadID string, partNumber int, data []byte, header http.Header) (completePart, *probe.Error) {
+	queryValues := url.Values{}
+	queryValues.Set("partNumber", strconv.Itoa(partNumber))
+	queryValues.Set("uploadId", uploadID)
+	resp, err := c.executeMethod("PUT", s3RequestMetadata{
+		bucketName:   bucket,
+		objectName:   object,
+		queryValues:  queryValues,
+		header:       header,
+		contentBytes: data,
+	})
+	if err != nil {
+		return completePart{}, err.Trace(bucket, object, strconv.Itoa(partNumber))
+	}
+	resp.Body.Close()
+	return completePart{PartNumber: partNumber, ETag: resp.Header.Get("ETag")}, nil
+}
+
+// copyPart - copies a range of the source as a single part of a
+// multipart upload.
+func (c *s3Client) copyPart(bucket, object, uploadID string, partNumber int, source string, start, end int64, header http.Header) (completePart, *probe.Error) {
+	partHeader := make(http.Header)
+	for k, v := range header {
+		partHeader[k] = v
+	}
+	partHeader.Set("X-Amz-Copy-Source", s3EncodePath(source))
+	partHeader.Set("X-Amz-Copy-Source-Range", "bytes="+strconv.FormatInt(start, 10)+"-"+strconv.FormatInt(end, 10))
+
+	queryValues := url.Values{}
+	queryValues.Set("partNumber", strconv.Itoa(partNumber))
+	queryValues.Set("uploadId", uploadID)
+	resp, err := c.executeMethod("PUT", s3RequestMetadata{
+		bucketName:  bucket,
+		objectName:  object,
+		queryValues: queryValues,
+		header:      partHeader,
+	})
+	if err != nil {
+		return completePart{}, err.Trace(bucket, object, strconv.Itoa(partNumber))
+	}
+	defer resp.Body.Close()
+
+	data, e := ioutil.ReadAll(resp.Body)
+	if e != nil {
+		return completePart{}, probe.NewError(e)
+	}
+	if err = s3ResponseError(bytes.NewReader(data)); err != nil {
+		return completePart{}, err.Trace(bucket, object, strconv.Itoa(partNumber))
+	}
+	result := copyPartResult{}
+	if e = xml.Unmarshal(data, &result); e != nil {
+		return completePart{}, probe.NewError(e)
+	}
+	return completePart{PartNumber: partNumber, ETag: result.ETag}, nil
+}
+
+// completeMultipartUploadResult - response of complete multipart upload.
+type completeMultipartUploadResult struct {
+	ETag string
+}
+
+// completeMultipartUpload - assembles the uploaded parts, returns the
+// ETag of the object.
+func (c *s3Client) completeMultipartUpload(bucket, object, uploadID string, parts []completePart) (string, *probe.Error) {
+	body, e := xml.Marshal(completeMultipartUpload{Parts: parts})
+	if e != nil {
+		return "", probe.NewError(e)
+	}
+	resp, err := c.executeMethod("POST", s3RequestMetadata{
+		bucketName:   bucket,
+		objectName:   object,
+		queryValues:  url.Values{"uploadId": []string{uploadID}},
+		contentBytes: body,
+	})
+	if err != nil {
+		return "", err.Trace(bucket, object)
+	}
+	defer resp.Body.Close()
+	data, e := ioutil.ReadAll(resp.Body)
+	if e != nil {
+		return "", probe.NewError(e)
+	}
+	if err = s3ResponseError(bytes.NewReader(data)); err != nil {
+		return "", err.Trace(bucket, object)
+	}
+	result := completeMultipartUploadResult{}
+	if e = xml.Unmarshal(data, &result); e != nil {
+		return "", probe.NewError(e)
+	}
+	return result.ETag, nil
+}
+
+// isUploadActive - verifies that a multipart upload can still be
+// continued, i.e. it was neither aborted nor completed.
+func (c *s3Client) isUploadActive(bucket, object, uploadID string) bool {
+	queryValues := url.Values{}
+	queryValues.Set("uploadId", uploadID)
+	queryValues.Set("max-parts", "1")
+	resp, err := c.executeMethod("GET", s3RequestMetadata{
+		bucketName:  bucket,
+		objectName:  object,
+		queryValues: queryValues,
+	})
+	if err != nil {
+		return false
+	}
+	resp.Body.Close()
+	return true
+}
+
+// listMultipartUploadsResult - response of list multipart uploads.
+type listMultipartUploadsResult struct {
+	NextKeyMarker      string
+	NextUploadIDMarker string `xml:"NextUploadIdMarker"`
+	IsTruncated        bool
+	Uploads            []struct {
+		Key      string
+		UploadID string `xml:"UploadId"`
+	} `xml:"Upload"`
+}
+
+// listUploadIDs - lists the IDs of all incomplete uploads of the object.
+func (c *s3Client) listUploadIDs(bucket, object string) ([]string, *probe.Error) {
+	queryValues := url.Values{}
+	queryValues.Set("uploads", "")
+	queryValues.Set("prefix", object)
+	var uploadIDs []string
+	for {
+		resp, err := c.executeMethod("GET", s3RequestMetadata{
+			bucketName:  bucket,
+			queryValues: queryValues,
+		})
+		if err != nil {
+			return nil, err.Trace(bucket, object)
+		}
+		result := listMultipartUploadsResult{}
+		e := xml.NewDecoder(resp.Body).Decode(&result)
+		resp.Body.Close()
+		if e != nil {
+			return nil, probe.NewError(e)
+		}
+		for _, upload := range result.Uploads {
+			// Uploads of other objects may share the prefix.
+			if upload.Key == object {
+				uploadIDs = append(uploadIDs, upload.UploadID)
+			}
+		}
+		if !result.IsTruncated {
+			return uploadIDs, nil
+		}
+		queryValues.Set("key-marker", result.NextKeyMarker)
+		queryValues.Set("upload-id-marker", result.NextUploadIDMarker)
+	}
+}
+
+// abortMultipartUpload - aborts a multipart upload, freeing its parts.
+// Uploads of interrupted commands are aborted as well.
+func (c *s3Client) abortMultipartUpload(bucket, object, uploadID string) *probe.Error {
+	ctx, cancel := newCleanupContext()
+	defer cancel()
+	resp, err := c.executeMethod("DELETE", s3RequestMetadata{
+		bucketName:  bucket,
+		objectName:  object,
+		queryValues: url.Values{"uploadId": []string{uploadID}},
+		ctx:         ctx,
+	})
+	if err != nil {
+		return err.Trace(bucket, object)
+	}
+	resp.Body.Close()
+	return nil
+}
+
+// putObject - uploads an object with the given options, objects of
+// known size smaller than a part are sent with a single PUT.
+func (c *s3Client) putObject(bucket, object string, reader io.Reader, size int64, contentType string, progress io.Reader, opts PutOpts) (int64, *probe.Error) {
+	header := make(http.Header)
+	header.Set("Content-Type", contentType)
+	setMetadataHeaders(header, opts.Metadata)
+	opts.SSE.setPutHeaders(header)
+
+	partSize, err := optimalPartSize(size, opts.PartSize)
+	if err != nil {
+		return 0, err.Trace(bucket, object)
+	}
+	if size < 0 || size >= partSize {
+		// Only SSE-C headers have to be repeated for every part.
+		partHeader := make(http.Header)
+		opts.SSE.setGetHeaders(partHeader)
+		n, err := c.putObjectMultipart(bucket, object, reader, size, header, partHeader, progress, partSize, opts)
+		if err != nil {
+			return n, err.Trace(bucket, object)
+		}
+		return n, nil
+	}
+
+	body := hookreader.NewHook(io.LimitReader(reader, size), progress)
+	if opts.Checksum {
+		return c.putObjectChecksum(bucket, object, body, size, header)
+	}
+	resp, err := c.executeMethod("PUT", s3RequestMetadata{
+		bucketName:    bucket,
+		objectName:    object,
+		header:        header,
+		contentBody:   body,
+		contentLength: size,
+	})
+	if err != nil {
+		return 0, err.Trace(bucket, object)
+	}
+	resp.Body.Close()
+	return size, nil
+}
+
+// putObjectChecksum - uploads an object smaller than a part along with
+// its MD5 and SHA256, which the host verifies the data against. The
+// SHA256 is stored with the object, the ETag returned is verified.
+func (c *s3Client) putObjectChecksum(bucket, object string, reader io.Reader, size int64, header http.Header) (int64, *probe.Error) {
+	data := make([]byte, size)
+	if n, e := io.ReadFull(reader, data); e != nil {
+		return int64(n), probe.NewError(UnexpectedEOF{TotalSize: size, TotalWritten: int64(n)})
+	}
+	sum := sha256.Sum256(data)
+	header.Set("Content-Md5", sumMD5Base64(data))
+	header.Set("X-Amz-Checksum-Sha256", base64.StdEncoding.EncodeToString(sum[:]))
+	resp, err := c.executeMethod("PUT", s3RequestMetadata{
+		bucketName:   bucket,
+		objectName:   object,
+		header:       header,
+		contentBytes: data,
+	})
+	if err != nil {
+		return 0, err.Trace(bucket, object)
+	}
+	resp.Body.Close()
+	checksum := newETagHash(0)
+	checksum.Write(data)
+	if err = verifyETag(c.targetURL.String(), resp.Header.Get("ETag"), header, checksum); err != nil {
+		return size, err.Trace(bucket, object)
+	}
+	return size, nil
+}
+
+// uploadStateKey - identifies the state of a resumable upload of the
+// object from a source.
+func (c *s3Client) uploadStateKey(bucket, object, resumeID string) string {
+	return sum256Hex([]byte(c.hostURL.String() + "/" + bucket + "/" + object + "\n" + resumeID))
+}
+
+// resumeMultipartUpload - continues an earlier upload of the object if
+// its state was persisted, otherwise a new upload is initiated. State
+// is persisted only for uploads with a resume ID and a known size.
+func (c *s3Client) resumeMultipartUpload(bucket, object string, size int64, initHeader http.Header, partSize int64, resumeID string) (string, *uploadStateV1, *probe.Error) {
+	var key string
+	if resumeID != "" && size >= 0 {
+		key = c.uploadStateKey(bucket, object, resumeID)
+		state, err := loadUploadState(key)
+		if err == nil && state != nil {
+			if state.Size == size && state.PartSize == partSize && c.isUploadActive(bucket, object, state.UploadID) {
+				return state.UploadID, state, nil
+			}
+			removeUploadState(key)
+		}
+	}
+	uploadID, err := c.newMultipartUpload(bucket, object, initHeader)
+	if err != nil {
+		return "", nil, err.Trace(bucket, object)
+	}
+	if key == "" {
+		return uploadID, nil, nil
+	}
+	state := newUploadState(key, uploadID, bucket, object, size, partSize)
+	if err = state.save(); err != nil {
+		// Upload works without, it just can not be resumed.
+		return uploadID, nil, nil
+	}
+	return uploadID, state, nil
+}
+
+// putObjectMultipart - uploads the reader until EOF as a multipart
+// upload. initHeader is sent on initiation and partHeader with every
+// part. Up to opts.ConcurrentParts parts are uploaded in parallel,
+// each of them buffered in memory drawn from globalBufferPool.
+//
+// Uploads with a resume ID persist their progress, a failed upload is
+// then kept to be resumed by the next attempt. Other uploads are
+// aborted on failure.
+func (c *s3Client) putObjectMultipart(bucket, object string, reader io.Reader, size int64, initHeader, partHeader http.Header, progress io.Reader, partSize int64, opts PutOpts) (int64, *probe.Error) {
+	concurrency := opts.ConcurrentParts
+	if concurrency < 1 {
+		concurrency = 1
+	}
+	uploadID, state, err := c.resumeMultipartUpload(bucket, object, size, initHeader, partSize, opts.ResumeID)
+	if err != nil {
+		return 0, err.Trace(bucket, object)
+	}
+	abort := func() {
+		if state != nil {
+			removeUploadState(state.key)
+		}
+		c.abortMultipartUpload(bucket, object, uploadID)
+	}
+
+	var checksum *etagHash
+	if opts.Checksum {
+		// Data is hashed as read, including that of parts uploaded
+		// earlier.
+		checksum = newETagHash(partSize)
+		reader = io.TeeReader(reader, checksum)
+	}
+	reader = hookreader.NewHook(reader, progress)
+	var total int64
+	var parts completeParts
+	if state != nil {
+		// Skip data of parts uploaded earlier, reading it keeps the
+		// progress in sync.
+		parts = state.completedParts()
+		skip := int64(len(parts)) * partSize
+		if skip > size {
+			skip = size
+		}
+		n, e := io.CopyN(ioutil.Discard, reader, skip)
+		total += n
+		if e != nil {
+			abort()
+			return total, probe.NewError(UnexpectedEOF{TotalSize: size, TotalWritten: total})
+		}
+	}
+
+	// Buffers of uploaded parts are handed back by the channel. At most
+	// concurrency buffers are held, more are only drawn from the pool
+	// while its memory limit allows, waiting for a buffer held otherwise.
+	buffers := make(chan []byte, concurrency)
+	held := 0
+
+	var wg sync.WaitGroup
+	var mutex sync.Mutex
+	var uploadErr *probe.Error
+	failed := func() bool {
+		mutex.Lock()
+		defer mutex.Unlock()
+		return uploadErr != nil
+	}
+
+	for partNumber := len(parts) + 1; ; partNumber++ {
+		var buf []byte
+		select {
+		case buf = <-buffers:
+		default:
+			if held < concurrency {
+				if held == 0 {
+					buf = globalBufferPool.Get(partSize)
+				} else {
+					buf = globalBufferPool.TryGet(partSize)
+				}
+			}
+			if buf != nil {
+				held++
+			} else {
+				buf = <-buffers
+			}
+		}
+		// Stop once a part failed, waiting for a buffer lets the
+		// failure of an earlier part surface.
+		if failed() {
+			buffers <- buf
+			break
+		}
+		n, e := io.ReadFull(reader, buf)
+		if e != nil && e != io.EOF && e != io.ErrUnexpectedEOF {
+			mutex.Lock()
+			uploadErr = probe.NewError(e)
+			mutex.Unlock()
+			buffers <- buf
+			break
+		}
+		// Always upload the first part, an empty object is a valid upload.
+		if n == 0 && partNumber > 1 {
+			buffers <- buf
+			break
+		}
+		// Streams of unknown size may exceed the parts of an upload.
+		if partNumber > s3MaxPartsCount {
+			mutex.Lock()
+			uploadErr = errTooManyParts(partSize).Trace(bucket, object)
+			mutex.Unlock()
+			buffers <- buf
+			break
+		}
+		header := partHeader
+		if checksum != nil {
+			header = make(http.Header)
+			for k, v := range partHeader {
+				header[k] = v
+			}
+			header.Set("Content-Md5", sumMD5Base64(buf[:n]))
+		}
+		wg.Add(1)
+		go func(partNumber int, buf []byte, n int, header http.Header) {
+			defer wg.Done()
+			part, err := c.uploadPart(bucket, object, uploadID, partNumber, buf[:n], header)
+			mutex.Lock()
+			if err != nil && uploadErr == nil {
+				uploadErr = err.Trace(bucket, object)
+			}
+			parts = append(parts, part)
+			mutex.Unlock()
+			if err == nil && state != nil {
+				state.addPart(part)
+			}
+			buffers <- buf
+		}(partNumber, buf, n, header)
+		total += int64(n)
+		if e != nil {
+			break
+		}
+	}
+	wg.Wait()
+	for i := 0; i < held; i++ {
+		globalBufferPool.Put(<-buffers)
+	}
+
+	if uploadErr != nil {
+		if state == nil {
+			abort()
+		}
+		return total, uploadErr
+	}
+	if size >= 0 && total != size {
+		abort()
+		return total, probe.NewError(UnexpectedEOF{TotalSize: size, TotalWritten: total})
+	}
+	sort.Sort(parts)
+	etag, err := c.completeMultipartUpload(bucket, object, uploadID, parts)
+	if err != nil {
+		abort()
+		return total, err.Trace(bucket, object)
+	}
+	if state != nil {
+		removeUploadState(state.key)
+	}
+	if checksum != nil {
+		if err = verifyETag(c.targetURL.String(), etag, initHeader, checksum); err != nil {
+			return total, err.Trace(bucket, object)
+		}
+	}
+	return total, nil
+}
+
+// copyObjectMultipart - server side copy of objects too large for a
+// single copy request, the source is copied in ranges as parts of a
+// multipart upload. Metadata and ETag of the source are read first,
+// every part is copied only if the source still has that ETag so that
+// a source replaced during the copy is not mixed up.
+func (c *s3Client) copyObjectMultipart(bucket, object, source string, size int64, progress io.Reader, opts CopyOpts) *probe.Error {
+	srcBucket, srcObject := sourceBucketAndObject(source)
+	headHeader := make(http.Header)
+	opts.SrcSSE.setGetHeaders(headHeader)
+	resp, err := c.executeMethod("HEAD", s3RequestMetadata{
+		bucketName: srcBucket,
+		objectName: srcObject,
+		header:     headHeader,
+	})
+	if err != nil {
+		return err.Trace(srcBucket, srcObject)
+	}
+	resp.Body.Close()
+
+	// Parts do not carry metadata, it is set on initiation.
+	initHeader := make(http.Header)
+	setMetadataHeaders(initHeader, mergeMetadata(metadataFromHeader(resp.Header), opts.Metadata))
+	opts.TgtSSE.setPutHeaders(initHeader)
+	if opts.IfMatch == "" {
+		opts.IfMatch = resp.Header.Get("ETag")
+	}
+
+	// Customer keys of source and target have to be sent with every
+	// part, as well as the conditions on the source.
+	partHeader := make(http.Header)
+	opts.SrcSSE.setCopySourceHeaders(partHeader)
+	opts.TgtSSE.setGetHeaders(partHeader)
+	setCopyConditionHeaders(partHeader, opts)
+
+	uploadID, err := c.newMultipartUpload(bucket, object, initHeader)
+	if err != nil {
+		return err.Trace(bucket, object)
+	}
+	partSize, err := optimalPartSize(size, s3CopyPartSize)
+	if err != nil {
+		return err.Trace(bucket, object)
+	}
+	var parts []completePart
+	for start, partNumber := int64(0), 1; start < size; start, partNumber = start+partSize, partNumber+1 {
+		end := start + partSize - 1
+		if end >= size {
+			end = size - 1
+		}
+		part, err := c.copyPart(bucket, object, uploadID, partNumber, source, start, end, partHeader)
+		if err != nil {
+			c.abortMultipartUpload(bucket, object, uploadID)
+			return err.Trace(bucket, object)
+		}
+		parts = append(parts, part)
+		if progress != nil {
+			if _, e := io.CopyN(ioutil.Discard, progress, end-start+1); e != nil {
+				c.abortMultipartUpload(bucket, object, uploadID)
+				return probe.NewError(e)
+			}
+		}
+	}
+	if _, err = c.completeMultipartUpload(bucket, object, uploadID, parts); err != nil {
+		c.abortMultipartUpload(bucket, object, uploadID)
+		return err.Trace(bucket, object)
+	}
+	return nil
+}
+
+// sourceBucketAndObject - splits a copy source of the form
+// ‘/bucket/object’.
+func sourceBucketAndObject(source string) (bucket, object string) {
+	tokens := strings.SplitN(strings.TrimPrefix(source, "/"), "/", 2)
+	bucket = tokens[0]
+	if len(tokens) == 2 {
+		object = tokens[1]
+	}
+	return bucket, object
+}
+
+// s3ResponseError - some operations, e.g. copy object and complete
+// multipart upload, may fail after a ‘200 OK’ has been sent. The error
+// is then found in the response body.
+func s3ResponseError(body io.Reader) *probe.Error {
+	data, e := ioutil.ReadAll(body)
+	if e != nil {
+		return probe.NewError(e)
+	}
+	if !bytes.Contains(data, []byte("<Error>")) {
+		return nil
+	}
+	errResp := minio.ErrorResponse{}
+	if e = xml.Unmarshal(data, &errResp); e != nil {
+		return probe.NewError(e)
+	}
+	return probe.NewError(errResp)
+}
@@ -0,0 +1,210 @@
+/*
+ * Minio Client (C) 2016 Minio, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"bytes"
+	"encoding/hex"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Payload modes of uploads signed with signature V4.
+const (
+	// Payloads are not signed, saving to hash them.
+	payloadUnsigned = "unsigned"
+	// Payloads are sent in chunks of aws-chunked encoding, each
+	// signed as it is sent.
+	payloadStreaming = "streaming"
+)
+
+var validPayloads = []string{payloadUnsigned, payloadStreaming}
+
+const (
+	streamingPayload       = "STREAMING-AWS4-HMAC-SHA256-PAYLOAD"
+	streamingSignAlgorithm = "AWS4-HMAC-SHA256-PAYLOAD"
+	// Size of the chunks of streamed payloads.
+	streamingChunkSize = 64 * 1024
+)
+
+// isValidPayload - reports whether the payload mode is known, empty
+// mode leaves signing payloads to minio-go.
+func isValidPayload(payload string) bool {
+	if payload == "" {
+		return true
+	}
+	for _, p := range validPayloads {
+		if p == payload {
+			return true
+		}
+	}
+	return false
+}
+
+// payloadTransport - signs payloads of uploads as configured, requests
+// are signed again with the keys of the provider.
+type payloadTransport struct {
+	payload   string
+	provider  credentialsProvider
+	transport http.RoundTripper
+}
+
+// RoundTrip - implements http.RoundTripper.
+func (t payloadTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	region := regionFromAuthV4(req)
+	if req.Method != "PUT" || req.Body == nil || region == "" {
+		// Only signed uploads have a payload to sign.
+		return t.transport.RoundTrip(req)
+	}
+	// Chunks are only sent for payloads of known size.
+	if t.payload == payloadStreaming && req.ContentLength <= 0 {
+		return t.transport.RoundTrip(req)
+	}
+	creds, err := t.provider.Get()
+	if err != nil {
+		return nil, err.ToGoError()
+	}
+
+	// Never modify the original request.
+	newReq := new(http.Request)
+	*newReq = *req
+	newURL := *req.URL
+	newReq.URL = &newURL
+	newReq.Header = make(http.Header, len(req.Header))
+	for k, v := range req.Header {
+		newReq.Header[k] = v
+	}
+	if t.payload == payloadUnsigned {
+		newReq.Header.Set("X-Amz-Content-Sha256", unsignedPayload)
+		s3SignV4(newReq, creds, region, "s3")
+		return t.transport.RoundTrip(newReq)
+	}
+
+	size := req.ContentLength
+	newReq.Header.Set("X-Amz-Content-Sha256", streamingPayload)
+	newReq.Header.Set("X-Amz-Decoded-Content-Length", strconv.FormatInt(size, 10))
+	if encoding := req.Header.Get("Content-Encoding"); encoding != "" {
+		newReq.Header.Set("Content-Encoding", "aws-chunked,"+encoding)
+	} else {
+		newReq.Header.Set("Content-Encoding", "aws-chunked")
+	}
+	newReq.ContentLength = streamingContentLength(size)
+	s3SignV4(newReq, creds, region, "s3")
+
+	// Signature of the headers seeds the signatures of the chunks.
+	date := newReq.Header.Get("X-Amz-Date")
+	t0, e := time.Parse(iso8601DateFormat, date)
+	if e != nil {
+		return nil, e
+	}
+	auth := newReq.Header.Get("Authorization")
+	seedSignature := auth[strings.LastIndex(auth, "Signature=")+len("Signature="):]
+	signingKey := getSigningKeyV4(creds.SecretKey, region, "s3", t0)
+	newReq.Body = newStreamingReader(req.Body, size, signingKey, date, getScopeV4(region, "s3", t0), seedSignature)
+	return t.transport.RoundTrip(newReq)
+}
+
+// streamingChunkLength - length of an encoded chunk of the given size.
+func streamingChunkLength(size int64) int64 {
+	// hex(size);chunk-signature=<64 hex digits>\r\n<data>\r\n
+	return int64(len(strconv.FormatInt(size, 16))) + int64(len(";chunk-signature=")) + 64 + 2 + size + 2
+}
+
+// streamingContentLength - length of a payload of the given size
+// encoded in chunks, including the final empty chunk.
+func streamingContentLength(size int64) int64 {
+	chunks := size / streamingChunkSize
+	length := chunks * streamingChunkLength(streamingChunkSize)
+	if remainder := size % streamingChunkSize; remainder > 0 {
+		length += streamingChunkLength(remainder)
+	}
+	return length + streamingChunkLength(0)
+}
+
+// streamingReader - encodes the payload in signed chunks, every
+// signature chains the previous one.
+type streamingReader struct {
+	body       io.ReadCloser
+	remaining  int64
+	signingKey []byte
+	date       string
+	scope      string
+	signature  string
+	chunk      []byte
+	buf        bytes.Buffer
+	done       bool
+}
+
+// newStreamingReader - reader of the payload of the given size in
+// chunks, seeded by the signature of the request.
+func newStreamingReader(body io.ReadCloser, size int64, signingKey []byte, date, scope, seedSignature string) *streamingReader {
+	return &streamingReader{
+		body:       body,
+		remaining:  size,
+		signingKey: signingKey,
+		date:       date,
+		scope:      scope,
+		signature:  seedSignature,
+		chunk:      make([]byte, streamingChunkSize),
+	}
+}
+
+// signChunk - appends the signed chunk to the buffer.
+func (r *streamingReader) signChunk(data []byte) {
+	stringToSign := streamingSignAlgorithm + "\n" + r.date + "\n" + r.scope + "\n" +
+		r.signature + "\n" + emptySHA256 + "\n" + sum256Hex(data)
+	r.signature = hex.EncodeToString(sumHMAC(r.signingKey, []byte(stringToSign)))
+	r.buf.WriteString(strconv.FormatInt(int64(len(data)), 16) + ";chunk-signature=" + r.signature + "\r\n")
+	r.buf.Write(data)
+	r.buf.WriteString("\r\n")
+}
+
+// Read - implements io.Reader.
+func (r *streamingReader) Read(p []byte) (int, error) {
+	for r.buf.Len() == 0 {
+		if r.done {
+			return 0, io.EOF
+		}
+		if r.remaining == 0 {
+			// Final chunk is empty.
+			r.signChunk(nil)
+			r.done = true
+			continue
+		}
+		n := int64(len(r.chunk))
+		if r.remaining < n {
+			n = r.remaining
+		}
+		if _, e := io.ReadFull(r.body, r.chunk[:n]); e != nil {
+			if e == io.EOF {
+				e = io.ErrUnexpectedEOF
+			}
+			return 0, e
+		}
+		r.remaining -= n
+		r.signChunk(r.chunk[:n])
+	}
+	return r.buf.Read(p)
+}
+
+// Close - implements io.Closer.
+func (r *streamingReader) Close() error {
+	return r.body.Close()
+}
@@ -0,0 +1,114 @@
+/*
+ * Minio Client (C) 2016 Minio, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/minio/minio/pkg/probe"
+)
+
+// processCredentials - JSON credentials printed by a credential
+// process, as specified for ‘credential_process’ of the AWS CLI.
+type processCredentials struct {
+	Version         int
+	AccessKeyID     string `json:"AccessKeyId"`
+	SecretAccessKey string
+	SessionToken    string
+	// Credentials never expire if not set.
+	Expiration *time.Time
+}
+
+// processProvider - hands out credentials printed by an external
+// command, e.g. of Vault or a single sign-on tool. The command is run
+// again shortly before the credentials expire.
+type processProvider struct {
+	mutex   *sync.Mutex
+	command string
+
+	creds      s3Credentials
+	expiration *time.Time
+}
+
+// Providers of commands, so that commands run once per invocation.
+var (
+	processProviders   = make(map[string]*processProvider)
+	processProvidersMu sync.Mutex
+)
+
+// getProcessProvider - provider running the command.
+func getProcessProvider(command string) *processProvider {
+	processProvidersMu.Lock()
+	defer processProvidersMu.Unlock()
+	provider, ok := processProviders[command]
+	if !ok {
+		provider = &processProvider{mutex: &sync.Mutex{}, command: command}
+		processProviders[command] = provider
+	}
+	return provider
+}
+
+// Get - returns valid credentials, running the command again if the
+// current ones are about to expire.
+func (p *processProvider) Get() (s3Credentials, *probe.Error) {
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+
+	if p.creds.AccessKey != "" && (p.expiration == nil || time.Now().UTC().Add(stsRefreshWindow).Before(*p.expiration)) {
+		return p.creds, nil
+	}
+	out, err := p.run()
+	if err != nil {
+		return s3Credentials{}, err.Trace(p.command)
+	}
+	creds := processCredentials{}
+	if e := json.Unmarshal(out, &creds); e != nil {
+		return s3Credentials{}, errInvalidCredentialProcessOutput(p.command, e.Error()).Trace(p.command)
+	}
+	if creds.Version != 1 {
+		return s3Credentials{}, errInvalidCredentialProcessOutput(p.command, "unsupported version").Trace(p.command)
+	}
+	if creds.AccessKeyID == "" || creds.SecretAccessKey == "" {
+		return s3Credentials{}, errInvalidCredentialProcessOutput(p.command, "access and secret keys are missing").Trace(p.command)
+	}
+	p.creds = s3Credentials{
+		AccessKey:    creds.AccessKeyID,
+		SecretKey:    creds.SecretAccessKey,
+		SessionToken: creds.SessionToken,
+	}
+	p.expiration = creds.Expiration
+	return p.creds, nil
+}
+
+// run - runs the command through the shell and returns its output,
+// prompts of the command are left to the terminal.
+func (p *processProvider) run() ([]byte, *probe.Error) {
+	cmd := shellCommand(p.command)
+	var stdout bytes.Buffer
+	cmd.Stdin = os.Stdin
+	cmd.Stdout = &stdout
+	cmd.Stderr = os.Stderr
+	if e := cmd.Run(); e != nil {
+		return nil, probe.NewError(errors.New("Credential process failed, " + e.Error()))
+	}
+	return stdout.Bytes(), nil
+}
@@ -0,0 +1,111 @@
+/*
+ * Minio Client (C) 2016 Minio, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"io"
+	"io/ioutil"
+	"net/http"
+	"strconv"
+	"sync"
+
+	"github.com/ricoharisin91/minio-go"
+)
+
+// s3ObjectReader - reads an object with requests sent within the
+// context of the client. The object is requested on the first read,
+// reads at an offset request only the range read, e.g. to resume
+// partial downloads.
+type s3ObjectReader struct {
+	mutex  sync.Mutex
+	clnt   *s3Client
+	bucket string
+	object string
+	body   io.ReadCloser
+}
+
+// getObject - ranged GET of the object, ranges are requested only for
+// positive lengths.
+func (r *s3ObjectReader) getObject(offset, length int64) (*http.Response, error) {
+	header := make(http.Header)
+	if length > 0 {
+		header.Set("Range", "bytes="+strconv.FormatInt(offset, 10)+"-"+strconv.FormatInt(offset+length-1, 10))
+	}
+	resp, err := r.clnt.executeMethod("GET", s3RequestMetadata{
+		bucketName: r.bucket,
+		objectName: r.object,
+		header:     header,
+	})
+	if err != nil {
+		return nil, err.ToGoError()
+	}
+	return resp, nil
+}
+
+// Read - reads the object from its start.
+func (r *s3ObjectReader) Read(p []byte) (int, error) {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	if r.body == nil {
+		resp, e := r.getObject(0, 0)
+		if e != nil {
+			return 0, e
+		}
+		r.body = resp.Body
+	}
+	return r.body.Read(p)
+}
+
+// ReadAt - reads len(p) bytes of the object at the offset, io.EOF is
+// returned once the object ends.
+func (r *s3ObjectReader) ReadAt(p []byte, offset int64) (int, error) {
+	if len(p) == 0 {
+		return 0, nil
+	}
+	resp, e := r.getObject(offset, int64(len(p)))
+	if e != nil {
+		if minio.ToErrorResponse(e).Code == "InvalidRange" {
+			return 0, io.EOF
+		}
+		return 0, e
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusPartialContent {
+		// Hosts ignoring the range return the whole object.
+		if _, e = io.CopyN(ioutil.Discard, resp.Body, offset); e != nil {
+			if e == io.EOF {
+				return 0, io.EOF
+			}
+			return 0, e
+		}
+	}
+	n, e := io.ReadFull(resp.Body, p)
+	if e == io.ErrUnexpectedEOF {
+		e = io.EOF
+	}
+	return n, e
+}
+
+// Close - closes the response of the object, if it was requested.
+func (r *s3ObjectReader) Close() error {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	if r.body == nil {
+		return nil
+	}
+	return r.body.Close()
+}
@@ -0,0 +1,192 @@
+/*
+ * Minio Client (C) 2016 Minio, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"bytes"
+	"encoding/json"
+	"encoding/xml"
+	"io/ioutil"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+
+	"github.com/minio/minio/pkg/probe"
+)
+
+const bucketRegionsVersion = "1"
+
+// bucketRegions - regions of buckets by host, persisted so that buckets
+// are not looked up again by every invocation. Without a file the
+// regions are only cached in memory.
+type bucketRegions struct {
+	mutex *sync.Mutex
+	file  string
+
+	Version string                       `json:"version"`
+	Hosts   map[string]map[string]string `json:"hosts"`
+}
+
+// newBucketRegions - new empty cache of bucket regions.
+func newBucketRegions() *bucketRegions {
+	return &bucketRegions{
+		mutex:   &sync.Mutex{},
+		Version: bucketRegionsVersion,
+		Hosts:   make(map[string]map[string]string),
+	}
+}
+
+// Regions of buckets of all hosts.
+var globalBucketRegions = newBucketRegions()
+
+// getBucketRegionsFile - file of the persisted bucket regions.
+func getBucketRegionsFile() string {
+	return filepath.Join(mustGetMcConfigDir(), "regions.json")
+}
+
+// initBucketRegions - loads regions persisted by earlier invocations,
+// an unreadable file is replaced by an empty cache.
+func initBucketRegions() {
+	globalBucketRegions.Load(getBucketRegionsFile())
+}
+
+// Load - loads the regions from file, later updates are saved to it.
+func (r *bucketRegions) Load(file string) *probe.Error {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	r.file = file
+	data, e := ioutil.ReadFile(file)
+	if e != nil {
+		if os.IsNotExist(e) {
+			return nil
+		}
+		return probe.NewError(e)
+	}
+	loaded := newBucketRegions()
+	if e = json.Unmarshal(data, loaded); e != nil {
+		return probe.NewError(e)
+	}
+	if loaded.Version == bucketRegionsVersion && loaded.Hosts != nil {
+		r.Hosts = loaded.Hosts
+	}
+	return nil
+}
+
+// Get - region of the bucket on the host.
+func (r *bucketRegions) Get(host, bucket string) (string, bool) {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	region, ok := r.Hosts[host][bucket]
+	return region, ok
+}
+
+// Set - sets the region of the bucket on the host and saves the
+// regions if they are persisted.
+func (r *bucketRegions) Set(host, bucket, region string) *probe.Error {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	if cached, ok := r.Hosts[host][bucket]; ok && cached == region {
+		return nil
+	}
+	if r.Hosts[host] == nil {
+		r.Hosts[host] = make(map[string]string)
+	}
+	r.Hosts[host][bucket] = region
+	if r.file == "" {
+		return nil
+	}
+
+	data, e := json.MarshalIndent(r, "", "\t")
+	if e != nil {
+		return probe.NewError(e)
+	}
+	// Write to a temporary file first, so that concurrent invocations
+	// never read a partially written file.
+	tmpFile := r.file + ".tmp"
+	if e = ioutil.WriteFile(tmpFile, data, 0600); e != nil {
+		return probe.NewError(e)
+	}
+	if e = os.Rename(tmpFile, r.file); e != nil {
+		return probe.NewError(e)
+	}
+	return nil
+}
+
+// regionTransport - answers bucket location requests with the region
+// configured for the host or cached by earlier lookups, lookups sent
+// to the host are added to the cache.
+type regionTransport struct {
+	host      string
+	region    string
+	regions   *bucketRegions
+	transport http.RoundTripper
+}
+
+// RoundTrip - implements http.RoundTripper.
+func (t regionTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	if req.Method != "GET" || len(req.URL.Query()["location"]) != 1 {
+		return t.transport.RoundTrip(req)
+	}
+	// Location requests are always sent path style.
+	bucket := strings.Trim(req.URL.Path, "/")
+
+	region, ok := t.region, t.region != ""
+	if !ok {
+		region, ok = t.regions.Get(t.host, bucket)
+	}
+	if ok {
+		body, e := xml.Marshal(struct {
+			XMLName xml.Name `xml:"http://s3.amazonaws.com/doc/2006-03-01/ LocationConstraint"`
+			Region  string   `xml:",chardata"`
+		}{Region: region})
+		if e != nil {
+			return nil, e
+		}
+		return &http.Response{
+			Status:        "200 OK",
+			StatusCode:    http.StatusOK,
+			Proto:         "HTTP/1.1",
+			ProtoMajor:    1,
+			ProtoMinor:    1,
+			Header:        http.Header{"Content-Type": []string{"application/xml"}},
+			Body:          ioutil.NopCloser(bytes.NewReader(body)),
+			ContentLength: int64(len(body)),
+			Request:       req,
+		}, nil
+	}
+
+	resp, e := t.transport.RoundTrip(req)
+	if e != nil || resp.StatusCode != http.StatusOK {
+		return resp, e
+	}
+	body, e := ioutil.ReadAll(resp.Body)
+	resp.Body.Close()
+	if e != nil {
+		return nil, e
+	}
+	resp.Body = ioutil.NopCloser(bytes.NewReader(body))
+	if xml.Unmarshal(body, &region) == nil {
+		// Failing to persist the region only costs another lookup.
+		t.regions.Set(t.host, bucket, region)
+	}
+	return resp, nil
+}
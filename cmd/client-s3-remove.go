@@ -0,0 +1,146 @@
+/*
+ * Minio Client (C) 2016 Minio, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"encoding/xml"
+	"net/http"
+	"net/url"
+
+	"github.com/minio/minio/pkg/probe"
+	"github.com/ricoharisin91/minio-go"
+)
+
+// Maximum number of objects removed by a single multi-object delete.
+const s3MaxDeleteKeys = 1000
+
+// deleteObject - object to remove with a multi-object delete, the
+// latest version unless a version is given.
+type deleteObject struct {
+	Key       string
+	VersionID string `xml:"VersionId,omitempty"`
+}
+
+// deleteRequest - request body of multi-object delete, in quiet mode
+// only failures are reported.
+type deleteRequest struct {
+	XMLName xml.Name       `xml:"Delete"`
+	Quiet   bool           `xml:"Quiet"`
+	Objects []deleteObject `xml:"Object"`
+}
+
+// deleteResult - response of multi-object delete.
+type deleteResult struct {
+	XMLName xml.Name `xml:"DeleteResult"`
+	Errors  []struct {
+		Key       string
+		VersionID string `xml:"VersionId"`
+		Code      string
+		Message   string
+	} `xml:"Error"`
+}
+
+// RemoveBulk - removes the objects read from the channel with
+// multi-object deletes of up to 1000 objects each. Objects have to be
+// in the bucket of the client, versions are removed if VersionID is
+// set. The result of every object is sent on the returned channel,
+// with Err set if it could not be removed.
+func (c *s3Client) RemoveBulk(contentCh <-chan *ClientContent) <-chan *ClientContent {
+	resultCh := make(chan *ClientContent)
+	go func() {
+		defer close(resultCh)
+		bucket, _ := c.url2BucketAndObject()
+		batch := make([]*ClientContent, 0, s3MaxDeleteKeys)
+		for content := range contentCh {
+			contentBucket, object := c.splitURL(&content.URL)
+			if contentBucket != bucket || object == "" {
+				resultCh <- &ClientContent{URL: content.URL, Err: errInvalidArgument().Trace(content.URL.String())}
+				continue
+			}
+			batch = append(batch, content)
+			if len(batch) == s3MaxDeleteKeys {
+				c.removeObjects(bucket, batch, resultCh)
+				batch = batch[:0]
+			}
+		}
+		if len(batch) > 0 {
+			c.removeObjects(bucket, batch, resultCh)
+		}
+	}()
+	return resultCh
+}
+
+// removeObjects - removes a batch of objects with a single request.
+func (c *s3Client) removeObjects(bucket string, batch []*ClientContent, resultCh chan<- *ClientContent) {
+	request := deleteRequest{Quiet: true}
+	for _, content := range batch {
+		_, object := c.splitURL(&content.URL)
+		request.Objects = append(request.Objects, deleteObject{Key: object, VersionID: content.VersionID})
+	}
+	result, err := c.deleteObjects(bucket, request)
+	failed := make(map[deleteObject]*probe.Error)
+	for _, e := range result.Errors {
+		failed[deleteObject{Key: e.Key, VersionID: e.VersionID}] = probe.NewError(minio.ErrorResponse{
+			Code:       e.Code,
+			Message:    e.Message,
+			BucketName: bucket,
+			Key:        e.Key,
+		})
+	}
+	for i, content := range batch {
+		removed := &ClientContent{URL: content.URL, VersionID: content.VersionID}
+		if err != nil {
+			removed.Err = err.Trace(content.URL.String())
+		} else if failed[request.Objects[i]] != nil {
+			removed.Err = failed[request.Objects[i]].Trace(content.URL.String())
+		}
+		resultCh <- removed
+	}
+}
+
+// deleteObjects - sends a multi-object delete request.
+func (c *s3Client) deleteObjects(bucket string, request deleteRequest) (deleteResult, *probe.Error) {
+	body, e := xml.Marshal(request)
+	if e != nil {
+		return deleteResult{}, probe.NewError(e)
+	}
+	header := make(http.Header)
+	header.Set("Content-Md5", sumMD5Base64(body))
+	for _, object := range request.Objects {
+		if object.VersionID != "" {
+			c.setMFAHeader(header)
+			break
+		}
+	}
+
+	resp, err := c.executeMethod("POST", s3RequestMetadata{
+		bucketName:   bucket,
+		queryValues:  url.Values{"delete": []string{""}},
+		header:       header,
+		contentBytes: body,
+	})
+	if err != nil {
+		return deleteResult{}, err.Trace(bucket)
+	}
+	defer resp.Body.Close()
+
+	result := deleteResult{}
+	if e = xml.NewDecoder(resp.Body).Decode(&result); e != nil {
+		return deleteResult{}, probe.NewError(e)
+	}
+	return result, nil
+}
@@ -0,0 +1,337 @@
+/*
+ * Minio Client (C) 2016 Minio, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha1"
+	"encoding/base64"
+	"encoding/xml"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/minio/minio/pkg/probe"
+	"github.com/ricoharisin91/minio-go"
+)
+
+// s3RequestMetadata - parameters of a request which is not covered
+// by the minio-go API, e.g. requests needing custom headers.
+type s3RequestMetadata struct {
+	bucketName  string
+	objectName  string
+	queryValues url.Values
+	header      http.Header
+
+	// Body of the request, content length of -1 sends the request
+	// chunked. Payload is signed only if contentBytes are set.
+	contentBody   io.Reader
+	contentLength int64
+	contentBytes  []byte
+
+	// Context of the request, requests without one are sent within
+	// the context of the client.
+	ctx context.Context
+}
+
+// Sub-resources which are part of the canonicalized resource of
+// signature V2.
+var v2SubResources = []string{
+	"acl",
+	"delete",
+	"legal-hold",
+	"lifecycle",
+	"location",
+	"logging",
+	"notification",
+	"partNumber",
+	"policy",
+	"requestPayment",
+	"response-cache-control",
+	"response-content-disposition",
+	"response-content-encoding",
+	"response-content-language",
+	"response-content-type",
+	"response-expires",
+	"restore",
+	"retention",
+	"select",
+	"select-type",
+	"tagging",
+	"torrent",
+	"uploadId",
+	"uploads",
+	"versionId",
+	"versioning",
+	"versions",
+	"website",
+}
+
+// newRequest - builds a path style request against the endpoint of
+// the bucket.
+func (c *s3Client) newRequest(method string, metadata s3RequestMetadata) (*http.Request, string, *probe.Error) {
+	region := "us-east-1"
+	if metadata.bucketName != "" && !isLocationRequest(metadata) {
+		location, err := c.bucketLocation(metadata.ctx, metadata.bucketName)
+		if err != nil {
+			return nil, "", err.Trace(metadata.bucketName)
+		}
+		region = location
+	}
+
+	host := c.hostURL.Host
+	if isAmazon(host) && region != "us-east-1" {
+		host = "s3." + region + ".amazonaws.com"
+	}
+	urlStr := c.hostURL.Scheme + "://" + host + "/"
+	if metadata.bucketName != "" {
+		urlStr += metadata.bucketName + "/" + s3EncodePath(metadata.objectName)
+	}
+	if len(metadata.queryValues) > 0 {
+		urlStr += "?" + strings.Replace(metadata.queryValues.Encode(), "+", "%20", -1)
+	}
+	targetURL, e := url.Parse(urlStr)
+	if e != nil {
+		return nil, "", probe.NewError(e)
+	}
+
+	body := metadata.contentBody
+	if metadata.contentBytes != nil {
+		body = bytes.NewReader(metadata.contentBytes)
+		metadata.contentLength = int64(len(metadata.contentBytes))
+	}
+	req, e := http.NewRequest(method, targetURL.String(), body)
+	if e != nil {
+		return nil, "", probe.NewError(e)
+	}
+	// Keep the URL as escaped by us.
+	req.URL = targetURL
+	if ctx := metadata.ctx; ctx != nil {
+		req = req.WithContext(ctx)
+	} else if c.ctx != nil {
+		req = req.WithContext(c.ctx)
+	}
+	for k, v := range metadata.header {
+		req.Header[k] = v
+	}
+	req.Header.Set("User-Agent", "Minio ("+c.config.AppName+"/"+c.config.AppVersion+")")
+	if body != nil {
+		req.ContentLength = metadata.contentLength
+	}
+	return req, region, nil
+}
+
+// isLocationRequest - reports whether the request looks up the region
+// of the bucket, such requests are always signed for us-east-1.
+func isLocationRequest(metadata s3RequestMetadata) bool {
+	_, ok := metadata.queryValues["location"]
+	return ok && metadata.objectName == "" && len(metadata.queryValues) == 1
+}
+
+// bucketLocation - looks up the region of the bucket within the
+// context of the request. Regions once known are answered by the
+// region transport without asking the host again.
+func (c *s3Client) bucketLocation(ctx context.Context, bucket string) (string, *probe.Error) {
+	if strings.HasSuffix(c.hostURL.Host, "amazonaws.com.cn") {
+		return "cn-north-1", nil
+	}
+	resp, err := c.executeMethod("GET", s3RequestMetadata{
+		bucketName:  bucket,
+		queryValues: url.Values{"location": []string{""}},
+		ctx:         ctx,
+	})
+	if err != nil {
+		// Anonymous requests may not look up the region, let the
+		// request itself succeed or fail depending on the policy.
+		errResp := minio.ToErrorResponse(err.ToGoError())
+		if errResp.Code == "AccessDenied" && strings.Contains(errResp.Message, "Access Denied") {
+			return "us-east-1", nil
+		}
+		return "", err.Trace(bucket)
+	}
+	defer resp.Body.Close()
+	var location string
+	if e := xml.NewDecoder(resp.Body).Decode(&location); e != nil {
+		return "", probe.NewError(e)
+	}
+	switch location {
+	case "":
+		location = "us-east-1"
+	case "EU":
+		location = "eu-west-1"
+	}
+	return location, nil
+}
+
+// executeMethod - signs and sends the request, error responses are
+// returned as minio.ErrorResponse so that they can be handled just
+// like errors returned by the minio-go API.
+func (c *s3Client) executeMethod(method string, metadata s3RequestMetadata) (*http.Response, *probe.Error) {
+	req, region, err := c.newRequest(method, metadata)
+	if err != nil {
+		return nil, err.Trace(metadata.bucketName, metadata.objectName)
+	}
+
+	creds := c.config.credentials()
+	if strings.ToUpper(c.config.Signature) == "S3V2" {
+		s3SignV2(req, creds)
+	} else {
+		if metadata.contentBytes != nil {
+			req.Header.Set("X-Amz-Content-Sha256", sum256Hex(metadata.contentBytes))
+		} else if metadata.contentBody == nil {
+			req.Header.Set("X-Amz-Content-Sha256", emptySHA256)
+		} else {
+			req.Header.Set("X-Amz-Content-Sha256", unsignedPayload)
+		}
+		s3SignV4(req, creds, region, "s3")
+	}
+
+	resp, e := c.httpClient.Do(req)
+	if e != nil {
+		return nil, probe.NewError(e).Trace(metadata.bucketName, metadata.objectName)
+	}
+	if resp.StatusCode/100 != 2 {
+		defer resp.Body.Close()
+		return nil, probe.NewError(toS3ErrorResponse(resp, metadata.bucketName, metadata.objectName))
+	}
+	return resp, nil
+}
+
+// toS3ErrorResponse - parses an error response, falling back to the
+// status code if there is no body, e.g. for HEAD requests.
+func toS3ErrorResponse(resp *http.Response, bucketName, objectName string) minio.ErrorResponse {
+	errResp := minio.ErrorResponse{}
+	body, _ := ioutil.ReadAll(resp.Body)
+	if xml.Unmarshal(body, &errResp) == nil && errResp.Code != "" {
+		return errResp
+	}
+	errResp = minio.ErrorResponse{
+		Code:       resp.Status,
+		Message:    resp.Status,
+		BucketName: bucketName,
+		Key:        objectName,
+		RequestID:  resp.Header.Get("x-amz-request-id"),
+		HostID:     resp.Header.Get("x-amz-id-2"),
+	}
+	switch resp.StatusCode {
+	case http.StatusNotFound:
+		if objectName == "" {
+			errResp.Code = "NoSuchBucket"
+			errResp.Message = "The specified bucket does not exist."
+		} else {
+			errResp.Code = "NoSuchKey"
+			errResp.Message = "The specified key does not exist."
+		}
+	case http.StatusForbidden:
+		errResp.Code = "AccessDenied"
+		errResp.Message = "Access Denied."
+	case http.StatusBadRequest:
+		errResp.Code = "BadRequest"
+		errResp.Message = "Bad Request."
+	case http.StatusRequestedRangeNotSatisfiable:
+		errResp.Code = "InvalidRange"
+		errResp.Message = "The requested range is not satisfiable."
+	}
+	return errResp
+}
+
+// s3SignV2 - signs the request in place with signature V2.
+func s3SignV2(req *http.Request, creds s3Credentials) {
+	if creds.AccessKey == "" || creds.SecretKey == "" {
+		return
+	}
+	req.Header.Set("Date", time.Now().UTC().Format(http.TimeFormat))
+	if creds.SessionToken != "" {
+		req.Header.Set("X-Amz-Security-Token", creds.SessionToken)
+	}
+	signature := signatureV2(req, req.Header.Get("Date"), creds)
+	req.Header.Set("Authorization", "AWS "+creds.AccessKey+":"+signature)
+}
+
+// s3PreSignV2 - presigns the request with signature V2 by adding the
+// signature to its query. Content-Type, if set, has to be sent along
+// by the user of the URL.
+func s3PreSignV2(req *http.Request, creds s3Credentials, expires time.Duration) {
+	if creds.AccessKey == "" || creds.SecretKey == "" {
+		return
+	}
+	expiresAt := strconv.FormatInt(time.Now().UTC().Add(expires).Unix(), 10)
+	signature := signatureV2(req, expiresAt, creds)
+
+	query := req.URL.Query()
+	query.Set("AWSAccessKeyId", creds.AccessKey)
+	query.Set("Expires", expiresAt)
+	query.Set("Signature", signature)
+	req.URL.RawQuery = strings.Replace(query.Encode(), "+", "%20", -1)
+}
+
+// signatureV2 - signature V2 of the request, date is either the date
+// of the request or the expiry of a presigned request.
+func signatureV2(req *http.Request, date string, creds s3Credentials) string {
+	var buf bytes.Buffer
+	buf.WriteString(req.Method + "\n")
+	buf.WriteString(req.Header.Get("Content-Md5") + "\n")
+	buf.WriteString(req.Header.Get("Content-Type") + "\n")
+	buf.WriteString(date + "\n")
+
+	// Canonicalized amz headers.
+	var amzHeaders []string
+	vals := make(map[string][]string)
+	for k, vv := range req.Header {
+		lk := strings.ToLower(k)
+		if strings.HasPrefix(lk, "x-amz") {
+			amzHeaders = append(amzHeaders, lk)
+			vals[lk] = vv
+		}
+	}
+	sort.Strings(amzHeaders)
+	for _, k := range amzHeaders {
+		buf.WriteString(k + ":" + strings.Join(vals[k], ",") + "\n")
+	}
+
+	// Canonicalized resource.
+	buf.WriteString(s3EncodePath(req.URL.Path))
+	query := req.URL.Query()
+	n := 0
+	for _, resource := range v2SubResources {
+		vv, ok := query[resource]
+		if !ok {
+			continue
+		}
+		if n == 0 {
+			buf.WriteByte('?')
+		} else {
+			buf.WriteByte('&')
+		}
+		n++
+		buf.WriteString(resource)
+		if len(vv) > 0 && vv[0] != "" {
+			buf.WriteString("=" + vv[0])
+		}
+	}
+
+	hm := hmac.New(sha1.New, []byte(creds.SecretKey))
+	hm.Write(buf.Bytes())
+	return base64.StdEncoding.EncodeToString(hm.Sum(nil))
+}
@@ -0,0 +1,137 @@
+/*
+ * Minio Client (C) 2016 Minio, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"encoding/xml"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/minio/minio/pkg/probe"
+	"github.com/ricoharisin91/minio-go"
+)
+
+// Retrieval tiers of archived objects, faster tiers cost more.
+const (
+	restoreTierExpedited = "Expedited"
+	restoreTierStandard  = "Standard"
+	restoreTierBulk      = "Bulk"
+)
+
+// restoreRequest - request body of restore object.
+type restoreRequest struct {
+	XMLName xml.Name `xml:"RestoreRequest"`
+	Days    int      `xml:"Days"`
+	Tier    string   `xml:"GlacierJobParameters>Tier"`
+}
+
+// ObjectRestoreStatus - state of the restore of an archived object.
+type ObjectRestoreStatus struct {
+	Requested  bool      `json:"requested"`
+	Ongoing    bool      `json:"ongoing"`
+	ExpiryDate time.Time `json:"expiryDate,omitempty"`
+}
+
+// isRestored - restored copy of the object can be read.
+func (s ObjectRestoreStatus) isRestored() bool {
+	return s.Requested && !s.Ongoing
+}
+
+// RestoreObject - requests a temporary copy of an archived object to
+// be kept for the number of days, restoring it again changes the
+// number of days. A restore already in progress is not an error.
+func (c *s3Client) RestoreObject(days int, tier string) *probe.Error {
+	bucket, object := c.url2BucketAndObject()
+	if object == "" {
+		return probe.NewError(ObjectMissing{})
+	}
+	body, e := xml.Marshal(restoreRequest{Days: days, Tier: tier})
+	if e != nil {
+		return probe.NewError(e)
+	}
+	header := make(http.Header)
+	header.Set("Content-Md5", sumMD5Base64(body))
+
+	resp, err := c.executeMethod("POST", s3RequestMetadata{
+		bucketName:   bucket,
+		objectName:   object,
+		queryValues:  url.Values{"restore": []string{""}},
+		header:       header,
+		contentBytes: body,
+	})
+	if err != nil {
+		if minio.ToErrorResponse(err.ToGoError()).Code == "RestoreAlreadyInProgress" {
+			return nil
+		}
+		return c.objectError(err, bucket)
+	}
+	resp.Body.Close()
+	return nil
+}
+
+// RestoreStatus - state of the restore of the object, read from the
+// ‘x-amz-restore’ header.
+func (c *s3Client) RestoreStatus(sse EncryptOpts) (ObjectRestoreStatus, *probe.Error) {
+	bucket, object := c.url2BucketAndObject()
+	if object == "" {
+		return ObjectRestoreStatus{}, probe.NewError(ObjectMissing{})
+	}
+	header := make(http.Header)
+	sse.setGetHeaders(header)
+	resp, err := c.executeMethod("HEAD", s3RequestMetadata{
+		bucketName: bucket,
+		objectName: object,
+		header:     header,
+	})
+	if err != nil {
+		return ObjectRestoreStatus{}, c.objectError(err, bucket)
+	}
+	resp.Body.Close()
+	return parseRestoreHeader(resp.Header.Get("X-Amz-Restore")), nil
+}
+
+// parseRestoreHeader - parses values like
+//
+//	ongoing-request="false", expiry-date="Fri, 23 Dec 2016 00:00:00 GMT"
+//
+// an empty value means no restore was requested.
+func parseRestoreHeader(value string) ObjectRestoreStatus {
+	status := ObjectRestoreStatus{}
+	if value == "" {
+		return status
+	}
+	status.Requested = true
+	for _, field := range strings.Split(value, "\",") {
+		i := strings.Index(field, "=")
+		if i < 0 {
+			continue
+		}
+		key := strings.TrimSpace(field[:i])
+		val := strings.Trim(strings.TrimSpace(field[i+1:]), "\"")
+		switch key {
+		case "ongoing-request":
+			status.Ongoing = val == "true"
+		case "expiry-date":
+			if t, e := time.Parse(http.TimeFormat, val); e == nil {
+				status.ExpiryDate = t
+			}
+		}
+	}
+	return status
+}
@@ -0,0 +1,183 @@
+/*
+ * Minio Client (C) 2016 Minio, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"encoding/xml"
+	"net/http"
+	"net/url"
+	"time"
+
+	"github.com/minio/minio/pkg/probe"
+	"github.com/ricoharisin91/minio-go"
+)
+
+// Retention modes of locked objects.
+const (
+	retentionGovernance = "GOVERNANCE"
+	retentionCompliance = "COMPLIANCE"
+)
+
+// Legal hold states of objects.
+const (
+	legalHoldOn  = "ON"
+	legalHoldOff = "OFF"
+)
+
+// objectRetention - retention of a locked object, zero value means no
+// retention.
+type objectRetention struct {
+	XMLName         xml.Name   `xml:"Retention" json:"-"`
+	Mode            string     `xml:"Mode,omitempty" json:"mode,omitempty"`
+	RetainUntilDate *time.Time `xml:"RetainUntilDate,omitempty" json:"retainUntilDate,omitempty"`
+}
+
+// objectLegalHold - legal hold of a locked object.
+type objectLegalHold struct {
+	XMLName xml.Name `xml:"LegalHold"`
+	Status  string   `xml:"Status"`
+}
+
+// lockQueryValues - query of a lock sub-resource, optionally of a
+// specific version.
+func lockQueryValues(resource, versionID string) url.Values {
+	queryValues := url.Values{}
+	queryValues.Set(resource, "")
+	if versionID != "" {
+		queryValues.Set("versionId", versionID)
+	}
+	return queryValues
+}
+
+// PutObjectRetention - set retention of the object, an empty retention
+// clears it. Governance retention can only be shortened or cleared by
+// bypassing it.
+func (c *s3Client) PutObjectRetention(versionID string, retention objectRetention, bypassGovernance bool) *probe.Error {
+	bucket, object := c.url2BucketAndObject()
+	if object == "" {
+		return probe.NewError(ObjectMissing{})
+	}
+	body, e := xml.Marshal(retention)
+	if e != nil {
+		return probe.NewError(e)
+	}
+	header := make(http.Header)
+	header.Set("Content-Md5", sumMD5Base64(body))
+	if bypassGovernance {
+		header.Set("X-Amz-Bypass-Governance-Retention", "true")
+	}
+	resp, err := c.executeMethod("PUT", s3RequestMetadata{
+		bucketName:   bucket,
+		objectName:   object,
+		queryValues:  lockQueryValues("retention", versionID),
+		header:       header,
+		contentBytes: body,
+	})
+	if err != nil {
+		return c.lockError(err, bucket, versionID)
+	}
+	resp.Body.Close()
+	return nil
+}
+
+// GetObjectRetention - get retention of the object.
+func (c *s3Client) GetObjectRetention(versionID string) (objectRetention, *probe.Error) {
+	bucket, object := c.url2BucketAndObject()
+	if object == "" {
+		return objectRetention{}, probe.NewError(ObjectMissing{})
+	}
+	resp, err := c.executeMethod("GET", s3RequestMetadata{
+		bucketName:  bucket,
+		objectName:  object,
+		queryValues: lockQueryValues("retention", versionID),
+	})
+	if err != nil {
+		if minio.ToErrorResponse(err.ToGoError()).Code == "NoSuchObjectLockConfiguration" {
+			return objectRetention{}, nil
+		}
+		return objectRetention{}, c.lockError(err, bucket, versionID)
+	}
+	defer resp.Body.Close()
+
+	retention := objectRetention{}
+	if e := xml.NewDecoder(resp.Body).Decode(&retention); e != nil {
+		return objectRetention{}, probe.NewError(e)
+	}
+	return retention, nil
+}
+
+// PutObjectLegalHold - turn legal hold of the object on or off.
+func (c *s3Client) PutObjectLegalHold(versionID, status string) *probe.Error {
+	bucket, object := c.url2BucketAndObject()
+	if object == "" {
+		return probe.NewError(ObjectMissing{})
+	}
+	body, e := xml.Marshal(objectLegalHold{Status: status})
+	if e != nil {
+		return probe.NewError(e)
+	}
+	header := make(http.Header)
+	header.Set("Content-Md5", sumMD5Base64(body))
+	resp, err := c.executeMethod("PUT", s3RequestMetadata{
+		bucketName:   bucket,
+		objectName:   object,
+		queryValues:  lockQueryValues("legal-hold", versionID),
+		header:       header,
+		contentBytes: body,
+	})
+	if err != nil {
+		return c.lockError(err, bucket, versionID)
+	}
+	resp.Body.Close()
+	return nil
+}
+
+// GetObjectLegalHold - get legal hold status of the object, objects
+// never put on hold are reported as off.
+func (c *s3Client) GetObjectLegalHold(versionID string) (string, *probe.Error) {
+	bucket, object := c.url2BucketAndObject()
+	if object == "" {
+		return "", probe.NewError(ObjectMissing{})
+	}
+	resp, err := c.executeMethod("GET", s3RequestMetadata{
+		bucketName:  bucket,
+		objectName:  object,
+		queryValues: lockQueryValues("legal-hold", versionID),
+	})
+	if err != nil {
+		if minio.ToErrorResponse(err.ToGoError()).Code == "NoSuchObjectLockConfiguration" {
+			return legalHoldOff, nil
+		}
+		return "", c.lockError(err, bucket, versionID)
+	}
+	defer resp.Body.Close()
+
+	legalHold := objectLegalHold{}
+	if e := xml.NewDecoder(resp.Body).Decode(&legalHold); e != nil {
+		return "", probe.NewError(e)
+	}
+	return legalHold.Status, nil
+}
+
+// lockError - converts errors of retention and legal hold requests to
+// typed errors.
+func (c *s3Client) lockError(err *probe.Error, bucket, versionID string) *probe.Error {
+	if versionID != "" {
+		return c.versionError(err, bucket, versionID)
+	}
+	return c.objectError(err, bucket)
+}
@@ -0,0 +1,253 @@
+/*
+ * Minio Client (C) 2016 Minio, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"bytes"
+	"encoding/binary"
+	"encoding/xml"
+	"errors"
+	"hash/crc32"
+	"io"
+	"net/http"
+	"net/url"
+
+	"github.com/minio/minio/pkg/probe"
+	"github.com/ricoharisin91/minio-go"
+)
+
+// selectCSVInput - format of CSV objects to query.
+type selectCSVInput struct {
+	FileHeaderInfo  string `xml:"FileHeaderInfo,omitempty"`
+	RecordDelimiter string `xml:"RecordDelimiter,omitempty"`
+	FieldDelimiter  string `xml:"FieldDelimiter,omitempty"`
+	QuoteCharacter  string `xml:"QuoteCharacter,omitempty"`
+}
+
+// selectJSONInput - format of JSON objects to query, either a single
+// DOCUMENT or LINES of documents.
+type selectJSONInput struct {
+	Type string `xml:"Type"`
+}
+
+// selectInputSerialization - format of objects to query, exactly one
+// of CSV, JSON and Parquet is set.
+type selectInputSerialization struct {
+	CompressionType string           `xml:"CompressionType,omitempty"`
+	CSV             *selectCSVInput  `xml:"CSV,omitempty"`
+	JSON            *selectJSONInput `xml:"JSON,omitempty"`
+	Parquet         *struct{}        `xml:"Parquet,omitempty"`
+}
+
+// selectCSVOutput - format of CSV query results.
+type selectCSVOutput struct {
+	RecordDelimiter string `xml:"RecordDelimiter,omitempty"`
+	FieldDelimiter  string `xml:"FieldDelimiter,omitempty"`
+}
+
+// selectJSONOutput - format of JSON query results.
+type selectJSONOutput struct {
+	RecordDelimiter string `xml:"RecordDelimiter,omitempty"`
+}
+
+// selectOutputSerialization - format of query results, exactly one of
+// CSV and JSON is set.
+type selectOutputSerialization struct {
+	CSV  *selectCSVOutput  `xml:"CSV,omitempty"`
+	JSON *selectJSONOutput `xml:"JSON,omitempty"`
+}
+
+// SelectObjectOpts - serialization of a query's input and output.
+type SelectObjectOpts struct {
+	Input  selectInputSerialization
+	Output selectOutputSerialization
+}
+
+// selectObjectContentRequest - request body of select object content.
+type selectObjectContentRequest struct {
+	XMLName             xml.Name                  `xml:"SelectObjectContentRequest"`
+	Expression          string                    `xml:"Expression"`
+	ExpressionType      string                    `xml:"ExpressionType"`
+	InputSerialization  selectInputSerialization  `xml:"InputSerialization"`
+	OutputSerialization selectOutputSerialization `xml:"OutputSerialization"`
+}
+
+// SelectObjectContent - runs an SQL expression against the object on
+// the server, the returned reader streams the matching records.
+func (c *s3Client) SelectObjectContent(expression string, sse EncryptOpts, opts SelectObjectOpts) (io.ReadCloser, *probe.Error) {
+	bucket, object := c.url2BucketAndObject()
+	if object == "" {
+		return nil, probe.NewError(ObjectMissing{})
+	}
+	body, e := xml.Marshal(selectObjectContentRequest{
+		Expression:          expression,
+		ExpressionType:      "SQL",
+		InputSerialization:  opts.Input,
+		OutputSerialization: opts.Output,
+	})
+	if e != nil {
+		return nil, probe.NewError(e)
+	}
+	header := make(http.Header)
+	sse.setGetHeaders(header)
+
+	queryValues := url.Values{}
+	queryValues.Set("select", "")
+	queryValues.Set("select-type", "2")
+	resp, err := c.executeMethod("POST", s3RequestMetadata{
+		bucketName:   bucket,
+		objectName:   object,
+		queryValues:  queryValues,
+		header:       header,
+		contentBytes: body,
+	})
+	if err != nil {
+		if err := encryptionError(minio.ToErrorResponse(err.ToGoError()), c.targetURL.String(), sse); err != nil {
+			return nil, err
+		}
+		return nil, c.objectError(err, bucket)
+	}
+	return &selectReader{body: resp.Body}, nil
+}
+
+// objectError - converts errors of object requests to typed errors.
+func (c *s3Client) objectError(err *probe.Error, bucket string) *probe.Error {
+	switch minio.ToErrorResponse(err.ToGoError()).Code {
+	case "AccessDenied":
+		return probe.NewError(PathInsufficientPermission{Path: c.targetURL.String()})
+	case "NoSuchBucket":
+		return probe.NewError(BucketDoesNotExist{Bucket: bucket})
+	case "NoSuchKey":
+		return probe.NewError(ObjectMissing{})
+	}
+	return err.Trace(c.targetURL.String())
+}
+
+// selectReader - decodes the event stream of a select response, only
+// the payload of records events is returned.
+type selectReader struct {
+	body    io.ReadCloser
+	payload []byte
+	done    bool
+}
+
+func (r *selectReader) Read(p []byte) (int, error) {
+	for len(r.payload) == 0 {
+		if r.done {
+			return 0, io.EOF
+		}
+		if e := r.nextEvent(); e != nil {
+			return 0, e
+		}
+	}
+	n := copy(p, r.payload)
+	r.payload = r.payload[n:]
+	return n, nil
+}
+
+func (r *selectReader) Close() error {
+	return r.body.Close()
+}
+
+// nextEvent - reads the next message of the event stream. A message is
+// laid out as
+//
+//	total length (4) | headers length (4) | prelude crc (4) |
+//	headers | payload | message crc (4)
+//
+// where lengths are big endian and checksums are CRC32.
+func (r *selectReader) nextEvent() error {
+	prelude := make([]byte, 12)
+	if _, e := io.ReadFull(r.body, prelude); e != nil {
+		if e == io.EOF {
+			// Stream ended before the end event.
+			return io.ErrUnexpectedEOF
+		}
+		return e
+	}
+	totalLength := binary.BigEndian.Uint32(prelude[0:4])
+	headersLength := binary.BigEndian.Uint32(prelude[4:8])
+	if crc32.ChecksumIEEE(prelude[0:8]) != binary.BigEndian.Uint32(prelude[8:12]) {
+		return errors.New("Select response prelude checksum mismatch.")
+	}
+	if totalLength < 16+headersLength {
+		return errors.New("Select response message is malformed.")
+	}
+
+	message := make([]byte, totalLength-12)
+	if _, e := io.ReadFull(r.body, message); e != nil {
+		if e == io.EOF {
+			return io.ErrUnexpectedEOF
+		}
+		return e
+	}
+	checksum := crc32.Update(crc32.ChecksumIEEE(prelude), crc32.IEEETable, message[:len(message)-4])
+	if checksum != binary.BigEndian.Uint32(message[len(message)-4:]) {
+		return errors.New("Select response message checksum mismatch.")
+	}
+
+	headers, e := parseEventHeaders(message[:headersLength])
+	if e != nil {
+		return e
+	}
+	payload := message[headersLength : len(message)-4]
+
+	switch headers[":message-type"] {
+	case "error":
+		return minio.ErrorResponse{
+			Code:    headers[":error-code"],
+			Message: headers[":error-message"],
+		}
+	case "event":
+		switch headers[":event-type"] {
+		case "Records":
+			r.payload = payload
+		case "End":
+			r.done = true
+		}
+		// Stats, Progress and Cont events carry no records.
+	}
+	return nil
+}
+
+// parseEventHeaders - parses headers of an event stream message, only
+// string values are used by select.
+func parseEventHeaders(data []byte) (map[string]string, error) {
+	headers := make(map[string]string)
+	buf := bytes.NewBuffer(data)
+	for buf.Len() > 0 {
+		nameLength, e := buf.ReadByte()
+		if e != nil {
+			return nil, e
+		}
+		name := buf.Next(int(nameLength))
+		valueType, e := buf.ReadByte()
+		if e != nil {
+			return nil, e
+		}
+		// Value type 7 is a string with a 2 byte length.
+		if valueType != 7 || buf.Len() < 2 {
+			return nil, errors.New("Select response header is malformed.")
+		}
+		valueLength := binary.BigEndian.Uint16(buf.Next(2))
+		if buf.Len() < int(valueLength) {
+			return nil, errors.New("Select response header is malformed.")
+		}
+		headers[string(name)] = string(buf.Next(int(valueLength)))
+	}
+	return headers, nil
+}
@@ -0,0 +1,293 @@
+/*
+ * Minio Client (C) 2016 Minio, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"bytes"
+	"crypto/hmac"
+	"crypto/md5"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"net/http"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/minio/minio/pkg/probe"
+)
+
+// Signature V4 related constants.
+const (
+	signV4Algorithm   = "AWS4-HMAC-SHA256"
+	iso8601DateFormat = "20060102T150405Z"
+	yyyymmdd          = "20060102"
+	unsignedPayload   = "UNSIGNED-PAYLOAD"
+	emptySHA256       = "e3b0c44298fc1c149afbf4c8996fb92427ae41e4649b934ca495991b7852b855"
+)
+
+// s3Credentials - set of credentials used to sign a request, session
+// token is only set for temporary credentials.
+type s3Credentials struct {
+	AccessKey    string
+	SecretKey    string
+	SessionToken string
+}
+
+// Headers which are never part of the signature, see minio-go
+// request-signature-v4.go for reasoning.
+var v4IgnoredHeaders = map[string]bool{
+	"Authorization":  true,
+	"Content-Type":   true,
+	"Content-Length": true,
+	"User-Agent":     true,
+}
+
+func sumHMAC(key []byte, data []byte) []byte {
+	hash := hmac.New(sha256.New, key)
+	hash.Write(data)
+	return hash.Sum(nil)
+}
+
+func sum256Hex(data []byte) string {
+	hash := sha256.New()
+	hash.Write(data)
+	return hex.EncodeToString(hash.Sum(nil))
+}
+
+// sumMD5Base64 - value of the Content-Md5 header, mandatory for
+// requests configuring buckets or objects.
+func sumMD5Base64(data []byte) string {
+	sum := md5.Sum(data)
+	return base64.StdEncoding.EncodeToString(sum[:])
+}
+
+// getScopeV4 - date, region and service scope of a signature.
+func getScopeV4(region, service string, t time.Time) string {
+	return strings.Join([]string{t.Format(yyyymmdd), region, service, "aws4_request"}, "/")
+}
+
+// getSigningKeyV4 - hmac seed to calculate the final signature.
+func getSigningKeyV4(secret, region, service string, t time.Time) []byte {
+	date := sumHMAC([]byte("AWS4"+secret), []byte(t.Format(yyyymmdd)))
+	location := sumHMAC(date, []byte(region))
+	svc := sumHMAC(location, []byte(service))
+	return sumHMAC(svc, []byte("aws4_request"))
+}
+
+// getSignedHeadersV4 - lexically sorted list of lower cased header names.
+func getSignedHeadersV4(req *http.Request) []string {
+	headers := []string{"host"}
+	for k := range req.Header {
+		if v4IgnoredHeaders[http.CanonicalHeaderKey(k)] {
+			continue
+		}
+		headers = append(headers, strings.ToLower(k))
+	}
+	sort.Strings(headers)
+	return headers
+}
+
+// getCanonicalRequestV4 - canonical form of the request.
+func getCanonicalRequestV4(req *http.Request, signedHeaders []string) string {
+	req.URL.RawQuery = strings.Replace(req.URL.Query().Encode(), "+", "%20", -1)
+
+	var headers bytes.Buffer
+	for _, k := range signedHeaders {
+		headers.WriteString(k)
+		headers.WriteByte(':')
+		if k == "host" {
+			headers.WriteString(req.URL.Host)
+		} else {
+			headers.WriteString(strings.Join(req.Header[http.CanonicalHeaderKey(k)], ","))
+		}
+		headers.WriteByte('\n')
+	}
+
+	hashedPayload := req.Header.Get("X-Amz-Content-Sha256")
+	if hashedPayload == "" {
+		hashedPayload = unsignedPayload
+	}
+
+	return strings.Join([]string{
+		req.Method,
+		s3EncodePath(req.URL.Path),
+		req.URL.RawQuery,
+		headers.String(),
+		strings.Join(signedHeaders, ";"),
+		hashedPayload,
+	}, "\n")
+}
+
+// s3SignV4 - signs the request in place for the given region and
+// service. Previous signature, if any, is replaced. Anonymous
+// credentials leave the request untouched.
+func s3SignV4(req *http.Request, creds s3Credentials, region, service string) {
+	if creds.AccessKey == "" || creds.SecretKey == "" {
+		return
+	}
+
+	t := time.Now().UTC()
+	req.Header.Del("Authorization")
+	req.Header.Set("X-Amz-Date", t.Format(iso8601DateFormat))
+	if creds.SessionToken != "" {
+		req.Header.Set("X-Amz-Security-Token", creds.SessionToken)
+	}
+
+	signedHeaders := getSignedHeadersV4(req)
+	canonicalRequest := getCanonicalRequestV4(req, signedHeaders)
+
+	scope := getScopeV4(region, service, t)
+	stringToSign := signV4Algorithm + "\n" + t.Format(iso8601DateFormat) + "\n" +
+		scope + "\n" + sum256Hex([]byte(canonicalRequest))
+	signingKey := getSigningKeyV4(creds.SecretKey, region, service, t)
+	signature := hex.EncodeToString(sumHMAC(signingKey, []byte(stringToSign)))
+
+	req.Header.Set("Authorization", signV4Algorithm+" Credential="+creds.AccessKey+"/"+scope+
+		", SignedHeaders="+strings.Join(signedHeaders, ";")+", Signature="+signature)
+}
+
+// s3PreSignV4 - presigns the request for the given region by adding
+// the signature to its query. Besides the host only the given headers
+// are signed, they have to be sent along by the user of the URL.
+func s3PreSignV4(req *http.Request, creds s3Credentials, region string, expires time.Duration, header http.Header) {
+	if creds.AccessKey == "" || creds.SecretKey == "" {
+		return
+	}
+
+	t := time.Now().UTC()
+	req.Header = make(http.Header)
+	signedHeaders := []string{"host"}
+	for k := range header {
+		req.Header.Set(k, header.Get(k))
+		signedHeaders = append(signedHeaders, strings.ToLower(k))
+	}
+	sort.Strings(signedHeaders)
+
+	scope := getScopeV4(region, "s3", t)
+	query := req.URL.Query()
+	query.Set("X-Amz-Algorithm", signV4Algorithm)
+	query.Set("X-Amz-Credential", creds.AccessKey+"/"+scope)
+	query.Set("X-Amz-Date", t.Format(iso8601DateFormat))
+	query.Set("X-Amz-Expires", strconv.FormatInt(int64(expires/time.Second), 10))
+	query.Set("X-Amz-SignedHeaders", strings.Join(signedHeaders, ";"))
+	if creds.SessionToken != "" {
+		query.Set("X-Amz-Security-Token", creds.SessionToken)
+	}
+	req.URL.RawQuery = query.Encode()
+
+	canonicalRequest := getCanonicalRequestV4(req, signedHeaders)
+	stringToSign := signV4Algorithm + "\n" + t.Format(iso8601DateFormat) + "\n" +
+		scope + "\n" + sum256Hex([]byte(canonicalRequest))
+	signingKey := getSigningKeyV4(creds.SecretKey, region, "s3", t)
+	signature := hex.EncodeToString(sumHMAC(signingKey, []byte(stringToSign)))
+
+	req.URL.RawQuery += "&X-Amz-Signature=" + signature
+}
+
+// s3PresignPostPolicyV4 - signs the JSON policy of an upload form for
+// the given region, returns the fields of the form. Session tokens are
+// added to the form and to the conditions of the policy.
+func s3PresignPostPolicyV4(policy string, formData map[string]string, creds s3Credentials, region string) (map[string]string, *probe.Error) {
+	var document struct {
+		Expiration string        `json:"expiration"`
+		Conditions []interface{} `json:"conditions"`
+	}
+	if e := json.Unmarshal([]byte(policy), &document); e != nil {
+		return nil, probe.NewError(e)
+	}
+
+	t := time.Now().UTC()
+	formData["x-amz-algorithm"] = signV4Algorithm
+	formData["x-amz-credential"] = creds.AccessKey + "/" + getScopeV4(region, "s3", t)
+	formData["x-amz-date"] = t.Format(iso8601DateFormat)
+	if creds.SessionToken != "" {
+		formData["x-amz-security-token"] = creds.SessionToken
+	}
+	for _, field := range []string{"x-amz-date", "x-amz-algorithm", "x-amz-credential", "x-amz-security-token"} {
+		if value, ok := formData[field]; ok {
+			document.Conditions = append(document.Conditions, []string{"eq", "$" + field, value})
+		}
+	}
+	policyBytes, e := json.Marshal(document)
+	if e != nil {
+		return nil, probe.NewError(e)
+	}
+	formData["policy"] = base64.StdEncoding.EncodeToString(policyBytes)
+	signingKey := getSigningKeyV4(creds.SecretKey, region, "s3", t)
+	formData["x-amz-signature"] = hex.EncodeToString(sumHMAC(signingKey, []byte(formData["policy"])))
+	return formData, nil
+}
+
+// regionFromAuthV4 - extracts the region from the credential scope of
+// an already signed request, returns empty string if the request is
+// not signed with signature V4.
+func regionFromAuthV4(req *http.Request) string {
+	auth := req.Header.Get("Authorization")
+	if !strings.HasPrefix(auth, signV4Algorithm) {
+		return ""
+	}
+	credIndex := strings.Index(auth, "Credential=")
+	if credIndex < 0 {
+		return ""
+	}
+	credential := strings.SplitN(auth[credIndex+len("Credential="):], ",", 2)[0]
+	// accessKey/yyyymmdd/region/service/aws4_request
+	scope := strings.Split(credential, "/")
+	if len(scope) < 5 {
+		return ""
+	}
+	return scope[len(scope)-3]
+}
+
+var s3ReservedPath = regexp.MustCompile("^[a-zA-Z0-9-_.~/]+$")
+
+// s3EncodePath - encodes the path as expected by S3 signature
+// calculation, unreserved characters are left as is.
+func s3EncodePath(pathName string) string {
+	if s3ReservedPath.MatchString(pathName) {
+		return pathName
+	}
+	var encodedPathname bytes.Buffer
+	for _, s := range pathName {
+		if 'A' <= s && s <= 'Z' || 'a' <= s && s <= 'z' || '0' <= s && s <= '9' {
+			encodedPathname.WriteRune(s)
+			continue
+		}
+		switch s {
+		case '-', '_', '.', '~', '/':
+			encodedPathname.WriteRune(s)
+			continue
+		default:
+			runeLen := utf8.RuneLen(s)
+			if runeLen < 0 {
+				// if utf8 cannot convert return the same string as is
+				return pathName
+			}
+			u := make([]byte, runeLen)
+			utf8.EncodeRune(u, s)
+			for _, r := range u {
+				encodedPathname.WriteString("%" + strings.ToUpper(hex.EncodeToString([]byte{r})))
+			}
+		}
+	}
+	return encodedPathname.String()
+}
@@ -0,0 +1,95 @@
+/*
+ * Minio Client (C) 2016 Minio, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"net/http"
+	"strings"
+
+	"github.com/minio/minio/pkg/probe"
+	"github.com/ricoharisin91/minio-go"
+)
+
+// copyObject - server side copy where source or target are encrypted
+// or metadata is added. Metadata of the source is retained, but the
+// server only copies it if no metadata is sent, it is then read and
+// sent along.
+func (c *s3Client) copyObject(bucket, object, source string, opts CopyOpts) *probe.Error {
+	header := make(http.Header)
+	header.Set("X-Amz-Copy-Source", s3EncodePath(source))
+	opts.SrcSSE.setCopySourceHeaders(header)
+	opts.TgtSSE.setPutHeaders(header)
+	setCopyConditionHeaders(header, opts)
+	if len(opts.Metadata) > 0 {
+		srcBucket, srcObject := sourceBucketAndObject(source)
+		metadata, err := c.statMetadata(srcBucket, srcObject, opts.SrcSSE)
+		if err != nil {
+			return err.Trace(source)
+		}
+		header.Set("X-Amz-Metadata-Directive", "REPLACE")
+		setMetadataHeaders(header, mergeMetadata(metadata, opts.Metadata))
+	}
+
+	resp, err := c.executeMethod("PUT", s3RequestMetadata{
+		bucketName: bucket,
+		objectName: object,
+		header:     header,
+	})
+	if err != nil {
+		return err.Trace(bucket, object, source)
+	}
+	defer resp.Body.Close()
+	return s3ResponseError(resp.Body)
+}
+
+// Codes of errors of requests with customer provided keys, other errors
+// like AccessDenied are not caused by the key.
+var sseCustomerErrorCodes = map[string]bool{
+	"InvalidSSECustomerKey":        true,
+	"InvalidSSECustomerAlgorithm":  true,
+	"InvalidSSECustomerParameters": true,
+	"MissingSSECustomerKey":        true,
+	"MissingSSECustomerKeyMD5":     true,
+	"SSECustomerKeyMD5Mismatch":    true,
+	"ObjectTampered":               true,
+}
+
+// encryptionError - maps failures caused by server side encryption to
+// typed errors, nil is returned for unrelated errors.
+func encryptionError(errResp minio.ErrorResponse, object string, sse EncryptOpts) *probe.Error {
+	switch {
+	case sse.Type == sseCustomer && sseCustomerErrorCodes[errResp.Code]:
+		return probe.NewError(ObjectDecryptionFailed{Object: object})
+	case sse.Type != sseCustomer && strings.Contains(errResp.Message, "Server Side Encryption"):
+		return probe.NewError(ObjectEncrypted{Object: object})
+	}
+	return nil
+}
+
+// setCopyConditionHeaders - sets headers of the preconditions on the
+// copy source.
+func setCopyConditionHeaders(h http.Header, opts CopyOpts) {
+	if opts.IfMatch != "" {
+		h.Set("X-Amz-Copy-Source-If-Match", opts.IfMatch)
+	}
+	if opts.IfNoneMatch != "" {
+		h.Set("X-Amz-Copy-Source-If-None-Match", opts.IfNoneMatch)
+	}
+	if !opts.IfUnmodifiedSince.IsZero() {
+		h.Set("X-Amz-Copy-Source-If-Unmodified-Since", opts.IfUnmodifiedSince.UTC().Format(http.TimeFormat))
+	}
+}
@@ -0,0 +1,215 @@
+/*
+ * Minio Client (C) 2016 Minio, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"bytes"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/minio/minio/pkg/probe"
+)
+
+const (
+	// Default global STS endpoint.
+	stsEndpoint = "https://sts.amazonaws.com"
+	// Region used to sign requests against the global STS endpoint.
+	stsRegion = "us-east-1"
+	// Lifetime requested for temporary credentials.
+	stsDuration = time.Hour
+	// Credentials are refreshed this long before they expire, so
+	// that requests in flight never carry an expired token.
+	stsRefreshWindow = 5 * time.Minute
+)
+
+// assumeRoleResponse - XML response of STS AssumeRole.
+type assumeRoleResponse struct {
+	XMLName xml.Name `xml:"AssumeRoleResponse"`
+	Result  struct {
+		Credentials struct {
+			AccessKeyID     string    `xml:"AccessKeyId"`
+			SecretAccessKey string    `xml:"SecretAccessKey"`
+			SessionToken    string    `xml:"SessionToken"`
+			Expiration      time.Time `xml:"Expiration"`
+		} `xml:"Credentials"`
+	} `xml:"AssumeRoleResult"`
+}
+
+// stsErrorResponse - XML error response of STS.
+type stsErrorResponse struct {
+	XMLName xml.Name `xml:"ErrorResponse"`
+	Error   struct {
+		Code    string `xml:"Code"`
+		Message string `xml:"Message"`
+	} `xml:"Error"`
+}
+
+// stsProvider - hands out temporary credentials obtained by assuming
+// a role, refreshing them shortly before they expire.
+type stsProvider struct {
+	mutex      *sync.Mutex
+	endpoint   string
+	roleARN    string
+	externalID string
+	// Long term credentials used to call AssumeRole.
+	baseCreds s3Credentials
+	transport http.RoundTripper
+
+	creds      s3Credentials
+	expiration time.Time
+}
+
+// newSTSProvider - instantiates a new role based credentials provider.
+func newSTSProvider(accessKey, secretKey, roleARN, externalID string, transport http.RoundTripper) *stsProvider {
+	return &stsProvider{
+		mutex:      &sync.Mutex{},
+		endpoint:   stsEndpoint,
+		roleARN:    roleARN,
+		externalID: externalID,
+		baseCreds:  s3Credentials{AccessKey: accessKey, SecretKey: secretKey},
+		transport:  transport,
+	}
+}
+
+// Get - returns valid temporary credentials, assuming the role again
+// if the current ones are about to expire.
+func (p *stsProvider) Get() (s3Credentials, *probe.Error) {
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+
+	if p.creds.AccessKey != "" && time.Now().UTC().Add(stsRefreshWindow).Before(p.expiration) {
+		return p.creds, nil
+	}
+	creds, expiration, err := p.assumeRole()
+	if err != nil {
+		return s3Credentials{}, err.Trace(p.roleARN)
+	}
+	p.creds = creds
+	p.expiration = expiration
+	return p.creds, nil
+}
+
+// assumeRole - calls STS AssumeRole with the long term credentials.
+func (p *stsProvider) assumeRole() (s3Credentials, time.Time, *probe.Error) {
+	params := url.Values{}
+	params.Set("Action", "AssumeRole")
+	params.Set("Version", "2011-06-15")
+	params.Set("RoleArn", p.roleARN)
+	params.Set("RoleSessionName", fmt.Sprintf("mc-%d", time.Now().UnixNano()))
+	params.Set("DurationSeconds", strconv.Itoa(int(stsDuration.Seconds())))
+	if p.externalID != "" {
+		params.Set("ExternalId", p.externalID)
+	}
+	body := []byte(params.Encode())
+
+	req, e := http.NewRequest("POST", p.endpoint, bytes.NewReader(body))
+	if e != nil {
+		return s3Credentials{}, time.Time{}, probe.NewError(e)
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	req.Header.Set("X-Amz-Content-Sha256", sum256Hex(body))
+	s3SignV4(req, p.baseCreds, stsRegion, "sts")
+
+	resp, e := (&http.Client{Transport: p.transport}).Do(req)
+	if e != nil {
+		return s3Credentials{}, time.Time{}, probe.NewError(e)
+	}
+	defer resp.Body.Close()
+
+	respBody, e := ioutil.ReadAll(resp.Body)
+	if e != nil {
+		return s3Credentials{}, time.Time{}, probe.NewError(e)
+	}
+	if resp.StatusCode != http.StatusOK {
+		errResp := stsErrorResponse{}
+		if xml.Unmarshal(respBody, &errResp) == nil && errResp.Error.Code != "" {
+			return s3Credentials{}, time.Time{}, probe.NewError(errors.New(errResp.Error.Code + ": " + errResp.Error.Message))
+		}
+		return s3Credentials{}, time.Time{}, probe.NewError(errors.New("AssumeRole failed with " + resp.Status))
+	}
+
+	roleResp := assumeRoleResponse{}
+	if e = xml.Unmarshal(respBody, &roleResp); e != nil {
+		return s3Credentials{}, time.Time{}, probe.NewError(e)
+	}
+	creds := roleResp.Result.Credentials
+	return s3Credentials{
+		AccessKey:    creds.AccessKeyID,
+		SecretKey:    creds.SecretAccessKey,
+		SessionToken: creds.SessionToken,
+	}, creds.Expiration, nil
+}
+
+// credentialsProvider - source of the credentials requests are signed
+// with.
+type credentialsProvider interface {
+	Get() (s3Credentials, *probe.Error)
+}
+
+// staticProvider - hands out fixed credentials, e.g. temporary ones
+// obtained outside of mc.
+type staticProvider s3Credentials
+
+// Get - returns the credentials.
+func (p staticProvider) Get() (s3Credentials, *probe.Error) {
+	return s3Credentials(p), nil
+}
+
+// stsTransport - re-signs every outgoing request with the current
+// temporary credentials of the provider.
+type stsTransport struct {
+	provider  credentialsProvider
+	transport http.RoundTripper
+}
+
+// RoundTrip - implements http.RoundTripper.
+func (t stsTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	region := regionFromAuthV4(req)
+	isV2 := strings.HasPrefix(req.Header.Get("Authorization"), "AWS ")
+	if region == "" && !isV2 {
+		// Not signed, nothing to replace.
+		return t.transport.RoundTrip(req)
+	}
+	creds, err := t.provider.Get()
+	if err != nil {
+		return nil, err.ToGoError()
+	}
+
+	// Never modify the original request.
+	newReq := new(http.Request)
+	*newReq = *req
+	newURL := *req.URL
+	newReq.URL = &newURL
+	newReq.Header = make(http.Header, len(req.Header))
+	for k, v := range req.Header {
+		newReq.Header[k] = v
+	}
+	if isV2 {
+		s3SignV2(newReq, creds)
+	} else {
+		s3SignV4(newReq, creds, region, "s3")
+	}
+	return t.transport.RoundTrip(newReq)
+}
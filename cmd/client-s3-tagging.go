@@ -0,0 +1,138 @@
+/*
+ * Minio Client (C) 2016 Minio, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"crypto/md5"
+	"encoding/base64"
+	"encoding/xml"
+	"net/http"
+	"net/url"
+	"sort"
+
+	"github.com/minio/minio/pkg/probe"
+	"github.com/ricoharisin91/minio-go"
+)
+
+// objectTag - a single key value pair of an object's tag set.
+type objectTag struct {
+	Key   string
+	Value string
+}
+
+// objectTagging - tag set of an object.
+type objectTagging struct {
+	XMLName xml.Name    `xml:"Tagging"`
+	Tags    []objectTag `xml:"TagSet>Tag"`
+}
+
+// GetObjectTagging - get tags of the object.
+func (c *s3Client) GetObjectTagging() (map[string]string, *probe.Error) {
+	bucket, object := c.url2BucketAndObject()
+	if object == "" {
+		return nil, probe.NewError(ObjectMissing{})
+	}
+	resp, err := c.executeMethod("GET", s3RequestMetadata{
+		bucketName:  bucket,
+		objectName:  object,
+		queryValues: url.Values{"tagging": []string{""}},
+	})
+	if err != nil {
+		return nil, c.taggingError(err, bucket)
+	}
+	defer resp.Body.Close()
+
+	tagging := objectTagging{}
+	if e := xml.NewDecoder(resp.Body).Decode(&tagging); e != nil {
+		return nil, probe.NewError(e)
+	}
+	tags := make(map[string]string)
+	for _, tag := range tagging.Tags {
+		tags[tag.Key] = tag.Value
+	}
+	return tags, nil
+}
+
+// PutObjectTagging - replace tags of the object.
+func (c *s3Client) PutObjectTagging(tags map[string]string) *probe.Error {
+	bucket, object := c.url2BucketAndObject()
+	if object == "" {
+		return probe.NewError(ObjectMissing{})
+	}
+	// Keep the request stable for the same tags.
+	var keys []string
+	for key := range tags {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	tagging := objectTagging{}
+	for _, key := range keys {
+		tagging.Tags = append(tagging.Tags, objectTag{Key: key, Value: tags[key]})
+	}
+
+	body, e := xml.Marshal(tagging)
+	if e != nil {
+		return probe.NewError(e)
+	}
+	sum := md5.Sum(body)
+	header := make(http.Header)
+	header.Set("Content-Md5", base64.StdEncoding.EncodeToString(sum[:]))
+
+	resp, err := c.executeMethod("PUT", s3RequestMetadata{
+		bucketName:   bucket,
+		objectName:   object,
+		queryValues:  url.Values{"tagging": []string{""}},
+		header:       header,
+		contentBytes: body,
+	})
+	if err != nil {
+		return c.taggingError(err, bucket)
+	}
+	resp.Body.Close()
+	return nil
+}
+
+// DeleteObjectTagging - remove all tags of the object.
+func (c *s3Client) DeleteObjectTagging() *probe.Error {
+	bucket, object := c.url2BucketAndObject()
+	if object == "" {
+		return probe.NewError(ObjectMissing{})
+	}
+	resp, err := c.executeMethod("DELETE", s3RequestMetadata{
+		bucketName:  bucket,
+		objectName:  object,
+		queryValues: url.Values{"tagging": []string{""}},
+	})
+	if err != nil {
+		return c.taggingError(err, bucket)
+	}
+	resp.Body.Close()
+	return nil
+}
+
+// taggingError - converts errors of tagging requests to typed errors.
+func (c *s3Client) taggingError(err *probe.Error, bucket string) *probe.Error {
+	switch minio.ToErrorResponse(err.ToGoError()).Code {
+	case "AccessDenied":
+		return probe.NewError(PathInsufficientPermission{Path: c.targetURL.String()})
+	case "NoSuchBucket":
+		return probe.NewError(BucketDoesNotExist{Bucket: bucket})
+	case "NoSuchKey":
+		return probe.NewError(ObjectMissing{})
+	}
+	return err.Trace(c.targetURL.String())
+}
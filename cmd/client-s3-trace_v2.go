@@ -22,7 +22,6 @@ import (
 	"net/http/httputil"
 	"strings"
 
-	"github.com/minio/mc/pkg/console"
 	"github.com/minio/mc/pkg/httptracer"
 )
 
@@ -48,7 +47,7 @@ func (t traceV2) Request(req *http.Request) (err error) {
 		var reqTrace []byte
 		reqTrace, err = httputil.DumpRequestOut(req, false) // Only display header
 		if err == nil {
-			console.Debug(string(reqTrace))
+			logDebug("HTTP request trace.", "id", requestIDOf(req), "trace", string(reqTrace))
 		}
 
 		// Undo
@@ -85,7 +84,7 @@ func (t traceV2) Response(resp *http.Response) (err error) {
 		}
 	}
 	if err == nil {
-		console.Debug(string(respTrace))
+		logDebug("HTTP response trace.", "id", requestIDOf(resp.Request), "trace", string(respTrace))
 	}
 
 	if globalInsecure && resp.TLS != nil {
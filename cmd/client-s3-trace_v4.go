@@ -23,7 +23,6 @@ import (
 	"regexp"
 	"strings"
 
-	"github.com/minio/mc/pkg/console"
 	"github.com/minio/mc/pkg/httptracer"
 )
 
@@ -57,7 +56,7 @@ func (t traceV4) Request(req *http.Request) (err error) {
 		var reqTrace []byte
 		reqTrace, err = httputil.DumpRequestOut(req, false) // Only display header
 		if err == nil {
-			console.Debug(string(reqTrace))
+			logDebug("HTTP request trace.", "id", requestIDOf(req), "trace", string(reqTrace))
 		}
 
 		// Undo
@@ -94,7 +93,7 @@ func (t traceV4) Response(resp *http.Response) (err error) {
 		}
 	}
 	if err == nil {
-		console.Debug(string(respTrace))
+		logDebug("HTTP response trace.", "id", requestIDOf(resp.Request), "trace", string(respTrace))
 	}
 
 	if globalInsecure && resp.TLS != nil {
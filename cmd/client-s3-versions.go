@@ -0,0 +1,308 @@
+/*
+ * Minio Client (C) 2016 Minio, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"encoding/xml"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/minio/minio/pkg/probe"
+	"github.com/ricoharisin91/minio-go"
+)
+
+// objectVersion - a version or a delete marker of an object.
+type objectVersion struct {
+	Key          string
+	VersionID    string `xml:"VersionId"`
+	IsLatest     bool
+	LastModified time.Time
+	Size         int64
+}
+
+// listVersionsResult - response of list object versions.
+type listVersionsResult struct {
+	XMLName             xml.Name `xml:"ListVersionsResult"`
+	IsTruncated         bool
+	NextKeyMarker       string
+	NextVersionIDMarker string          `xml:"NextVersionIdMarker"`
+	Versions            []objectVersion `xml:"Version"`
+	DeleteMarkers       []objectVersion `xml:"DeleteMarker"`
+	CommonPrefixes      []struct {
+		Prefix string
+	}
+}
+
+// ListVersions - list all versions of objects at the path, delete
+// markers are listed as well.
+func (c *s3Client) ListVersions(recursive bool) <-chan *ClientContent {
+	contentCh := make(chan *ClientContent)
+	go c.listVersionsInRoutine(contentCh, recursive)
+	return contentCh
+}
+
+func (c *s3Client) listVersionsInRoutine(contentCh chan *ClientContent, recursive bool) {
+	defer close(contentCh)
+	bucket, object := c.url2BucketAndObject()
+	if bucket == "" {
+		contentCh <- &ClientContent{Err: probe.NewError(BucketNameEmpty{})}
+		return
+	}
+
+	queryValues := url.Values{}
+	queryValues.Set("versions", "")
+	queryValues.Set("prefix", object)
+	if !recursive {
+		queryValues.Set("delimiter", string(c.targetURL.Separator))
+	}
+	for {
+		resp, err := c.executeMethod("GET", s3RequestMetadata{
+			bucketName:  bucket,
+			queryValues: queryValues,
+		})
+		if err != nil {
+			contentCh <- &ClientContent{Err: err.Trace(bucket, object)}
+			return
+		}
+		result := listVersionsResult{}
+		e := xml.NewDecoder(resp.Body).Decode(&result)
+		resp.Body.Close()
+		if e != nil {
+			contentCh <- &ClientContent{Err: probe.NewError(e)}
+			return
+		}
+
+		// Versions and delete markers are listed apart, pages are
+		// listed in key order.
+		var contents []*ClientContent
+		for _, prefix := range result.CommonPrefixes {
+			content := &ClientContent{}
+			content.URL = c.objectURL(bucket, prefix.Prefix)
+			content.Time = time.Now()
+			content.Type = os.ModeDir
+			contents = append(contents, content)
+		}
+		for _, version := range result.Versions {
+			contents = append(contents, c.versionContent(bucket, version, false))
+		}
+		for _, marker := range result.DeleteMarkers {
+			contents = append(contents, c.versionContent(bucket, marker, true))
+		}
+		sort.Stable(byVersionOrder(contents))
+		for _, content := range contents {
+			contentCh <- content
+		}
+
+		if !result.IsTruncated {
+			return
+		}
+		queryValues.Set("key-marker", result.NextKeyMarker)
+		queryValues.Set("version-id-marker", result.NextVersionIDMarker)
+	}
+}
+
+// byVersionOrder - sorts versions by key, the versions of a key from the
+// latest to the oldest.
+type byVersionOrder []*ClientContent
+
+func (b byVersionOrder) Len() int      { return len(b) }
+func (b byVersionOrder) Swap(i, j int) { b[i], b[j] = b[j], b[i] }
+func (b byVersionOrder) Less(i, j int) bool {
+	if b[i].URL.Path != b[j].URL.Path {
+		return b[i].URL.Path < b[j].URL.Path
+	}
+	if b[i].IsLatest != b[j].IsLatest {
+		return b[i].IsLatest
+	}
+	return b[i].Time.After(b[j].Time)
+}
+
+// objectURL - URL of an object in the bucket.
+func (c *s3Client) objectURL(bucket, object string) ClientURL {
+	url := *c.targetURL
+	if c.virtualStyle {
+		url.Path = string(url.Separator) + object
+	} else {
+		url.Path = string(url.Separator) + bucket + string(url.Separator) + object
+	}
+	return url
+}
+
+func (c *s3Client) versionContent(bucket string, version objectVersion, isDeleteMarker bool) *ClientContent {
+	content := &ClientContent{}
+	content.URL = c.objectURL(bucket, version.Key)
+	content.URL.Path = filepath.Clean(content.URL.Path)
+	content.Size = version.Size
+	content.Time = version.LastModified
+	content.Type = os.FileMode(0664)
+	content.VersionID = version.VersionID
+	content.IsLatest = version.IsLatest
+	content.IsDeleteMarker = isDeleteMarker
+	return content
+}
+
+// GetVersion - get a specific version of the object.
+func (c *s3Client) GetVersion(versionID string) (io.Reader, *probe.Error) {
+	bucket, object := c.url2BucketAndObject()
+	resp, err := c.executeMethod("GET", s3RequestMetadata{
+		bucketName:  bucket,
+		objectName:  object,
+		queryValues: url.Values{"versionId": []string{versionID}},
+	})
+	if err != nil {
+		return nil, c.versionError(err, bucket, versionID)
+	}
+	return resp.Body, nil
+}
+
+// RemoveVersion - remove a specific version of the object, removing
+// a delete marker restores the previous version.
+func (c *s3Client) RemoveVersion(versionID string) *probe.Error {
+	bucket, object := c.url2BucketAndObject()
+	header := make(http.Header)
+	c.setMFAHeader(header)
+	resp, err := c.executeMethod("DELETE", s3RequestMetadata{
+		bucketName:  bucket,
+		objectName:  object,
+		queryValues: url.Values{"versionId": []string{versionID}},
+		header:      header,
+	})
+	if err != nil {
+		return c.versionError(err, bucket, versionID)
+	}
+	resp.Body.Close()
+	return nil
+}
+
+// versionError - converts errors of version requests to typed errors.
+func (c *s3Client) versionError(err *probe.Error, bucket, versionID string) *probe.Error {
+	if mfaErr := c.mfaError(err); mfaErr != nil {
+		return mfaErr
+	}
+	switch minio.ToErrorResponse(err.ToGoError()).Code {
+	case "AccessDenied":
+		return probe.NewError(PathInsufficientPermission{Path: c.targetURL.String()})
+	case "NoSuchBucket":
+		return probe.NewError(BucketDoesNotExist{Bucket: bucket})
+	case "NoSuchKey", "NoSuchVersion", "InvalidArgument":
+		return probe.NewError(ObjectVersionMissing{Object: c.targetURL.String(), VersionID: versionID})
+	}
+	return err.Trace(c.targetURL.String(), versionID)
+}
+
+// Versioning states of a bucket, a bucket never versioned has neither.
+const (
+	versioningEnabled   = "Enabled"
+	versioningSuspended = "Suspended"
+)
+
+// MFA delete states of a bucket.
+const (
+	mfaDeleteEnabled  = "Enabled"
+	mfaDeleteDisabled = "Disabled"
+)
+
+// versioningConfiguration - versioning configuration of a bucket.
+type versioningConfiguration struct {
+	XMLName   xml.Name `xml:"VersioningConfiguration" json:"-"`
+	Status    string   `xml:"Status,omitempty" json:"status,omitempty"`
+	MFADelete string   `xml:"MfaDelete,omitempty" json:"mfaDelete,omitempty"`
+}
+
+// isValidMFA - serial number and token of an MFA device separated by
+// white space.
+func isValidMFA(mfa string) bool {
+	return len(strings.Fields(mfa)) == 2
+}
+
+// setMFAHeader - sets the MFA of the config, if any. Only deletes of
+// versions and changes of versioning are sent with the MFA.
+func (c *s3Client) setMFAHeader(h http.Header) {
+	if c.config.MFA != "" {
+		h.Set("X-Amz-Mfa", c.config.MFA)
+	}
+}
+
+// GetVersioning - get versioning configuration of the bucket.
+func (c *s3Client) GetVersioning() (versioningConfiguration, *probe.Error) {
+	bucket, _ := c.url2BucketAndObject()
+	if bucket == "" {
+		return versioningConfiguration{}, probe.NewError(BucketNameEmpty{})
+	}
+	resp, err := c.executeMethod("GET", s3RequestMetadata{
+		bucketName:  bucket,
+		queryValues: url.Values{"versioning": []string{""}},
+	})
+	if err != nil {
+		return versioningConfiguration{}, c.objectError(err, bucket)
+	}
+	defer resp.Body.Close()
+
+	versioning := versioningConfiguration{}
+	if e := xml.NewDecoder(resp.Body).Decode(&versioning); e != nil {
+		return versioningConfiguration{}, probe.NewError(e)
+	}
+	return versioning, nil
+}
+
+// SetVersioning - enable or suspend versioning of the bucket. Turning
+// MFA delete on or off, or changing versioning of a bucket with MFA
+// delete enabled, requires the MFA of the config.
+func (c *s3Client) SetVersioning(versioning versioningConfiguration) *probe.Error {
+	bucket, _ := c.url2BucketAndObject()
+	if bucket == "" {
+		return probe.NewError(BucketNameEmpty{})
+	}
+	body, e := xml.Marshal(versioning)
+	if e != nil {
+		return probe.NewError(e)
+	}
+	header := make(http.Header)
+	header.Set("Content-Md5", sumMD5Base64(body))
+	c.setMFAHeader(header)
+
+	resp, err := c.executeMethod("PUT", s3RequestMetadata{
+		bucketName:   bucket,
+		queryValues:  url.Values{"versioning": []string{""}},
+		header:       header,
+		contentBytes: body,
+	})
+	if err != nil {
+		if mfaErr := c.mfaError(err); mfaErr != nil {
+			return mfaErr
+		}
+		return c.objectError(err, bucket)
+	}
+	resp.Body.Close()
+	return nil
+}
+
+// mfaError - maps requests denied for lack of a valid MFA to a typed
+// error, nil is returned for unrelated errors.
+func (c *s3Client) mfaError(err *probe.Error) *probe.Error {
+	errResp := minio.ToErrorResponse(err.ToGoError())
+	if errResp.Code == "AccessDenied" && strings.Contains(strings.ToLower(errResp.Message), "mfa") {
+		return probe.NewError(MFARequired{Path: c.targetURL.String()})
+	}
+	return nil
+}
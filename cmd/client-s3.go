@@ -17,9 +17,11 @@
 package cmd
 
 import (
-	"crypto/tls"
+	"bytes"
+	"context"
+	"encoding/xml"
 	"errors"
-	"hash/fnv"
+	"fmt"
 	"io"
 	"net/http"
 	"net/url"
@@ -27,6 +29,7 @@ import (
 	"path"
 	"path/filepath"
 	"regexp"
+	"strconv"
 	"strings"
 	"sync"
 	"time"
@@ -34,17 +37,26 @@ import (
 	"io/ioutil"
 
 	"github.com/minio/mc/pkg/httptracer"
+	"github.com/minio/minio/pkg/probe"
 	"github.com/ricoharisin91/minio-go"
 	"github.com/ricoharisin91/minio-go/pkg/policy"
-	"github.com/minio/minio/pkg/probe"
 )
 
 // S3 client
 type s3Client struct {
 	mutex        *sync.Mutex
-	targetURL    *clientURL
+	targetURL    *ClientURL
 	api          *minio.Client
 	virtualStyle bool
+	// Following are used for requests not covered by the minio-go API.
+	hostURL    *url.URL
+	config     *Config
+	httpClient *http.Client
+	// Keys requests are finally signed with, temporary keys of roles
+	// and credential processes are refreshed.
+	provider credentialsProvider
+	// Context requests of the client are sent within, if any.
+	ctx context.Context
 }
 
 const (
@@ -52,13 +64,93 @@ const (
 	googleHostName = "storage.googleapis.com"
 )
 
-// newFactory encloses New function with client cache.
-func newFactory() func(config *Config) (Client, *probe.Error) {
-	clientCache := make(map[uint32]*minio.Client)
+// Number of contents of a bucket listed ahead while ordered listings
+// of several buckets wait for an earlier bucket.
+const bucketListAhead = 1000
+
+// clientKey - settings clients are shared by, all settings which
+// change how requests are sent are part of the key.
+type clientKey struct {
+	host, scheme                              string
+	accessKey, secretKey, sessionToken        string
+	signature                                 string
+	credentialProcess, roleARN, externalID    string
+	region, lookup, payload, mfa, credentials string
+	caCert, cert, key                         string
+	insecure, debug                           bool
+	transport                                 TransportOpts
+}
+
+// newClientKey - key of the clients of the config sent to the host.
+func newClientKey(hostName, scheme string, config *Config) clientKey {
+	return clientKey{
+		host:              hostName,
+		scheme:            scheme,
+		accessKey:         config.AccessKey,
+		secretKey:         config.SecretKey,
+		sessionToken:      config.SessionToken,
+		signature:         strings.ToUpper(config.Signature),
+		credentialProcess: config.CredentialProcess,
+		roleARN:           config.RoleARN,
+		externalID:        config.ExternalID,
+		region:            config.Region,
+		lookup:            config.Lookup,
+		payload:           config.Payload,
+		mfa:               config.MFA,
+		credentials:       config.Credentials,
+		caCert:            config.CACert,
+		cert:              config.Cert,
+		key:               config.Key,
+		insecure:          config.Insecure,
+		debug:             config.Debug,
+		transport:         config.Transport.withDefaults(defaultTransportOpts),
+	}
+}
+
+// clientHostName - host requests to the target are sent to, virtual
+// host style URLs of Amazon and Google are sent to their main host.
+func clientHostName(targetURL *ClientURL) string {
+	hostName := targetURL.Host
+	if isVirtualHostStyle(hostName) {
+		// If Amazon URL replace it with 's3.amazonaws.com'
+		if isAmazon(hostName) {
+			hostName = amazonHostName
+		}
+		// If Google URL replace it with 'storage.googleapis.com'
+		if isGoogle(hostName) {
+			hostName = googleHostName
+		}
+	}
+	return hostName
+}
+
+// newFactory encloses New function with client cache, along with a
+// function dropping cached clients so that the next client of their
+// config is set up anew. Clients of all configs are dropped if the
+// config is nil.
+func newFactory() (func(config *Config) (Client, *probe.Error), func(config *Config)) {
+	clientCache := make(map[clientKey]*minio.Client)
+	httpClientCache := make(map[clientKey]*http.Client)
+	providerCache := make(map[clientKey]credentialsProvider)
 	mutex := &sync.Mutex{}
 
-	// Return New function.
-	return func(config *Config) (Client, *probe.Error) {
+	invalidate := func(config *Config) {
+		mutex.Lock()
+		defer mutex.Unlock()
+		if config == nil {
+			clientCache = make(map[clientKey]*minio.Client)
+			httpClientCache = make(map[clientKey]*http.Client)
+			providerCache = make(map[clientKey]credentialsProvider)
+			return
+		}
+		targetURL := newClientURL(config.HostURL)
+		key := newClientKey(clientHostName(targetURL), targetURL.Scheme, config)
+		delete(clientCache, key)
+		delete(httpClientCache, key)
+		delete(providerCache, key)
+	}
+
+	newClient := func(config *Config) (Client, *probe.Error) {
 		// Creates a parsed URL.
 		targetURL := newClientURL(config.HostURL)
 		// By default enable HTTPs.
@@ -75,137 +167,406 @@ func newFactory() func(config *Config) (Client, *probe.Error) {
 		s3Clnt.targetURL = targetURL
 
 		// Save if target supports virtual host style.
-		hostName := targetURL.Host
-		s3Clnt.virtualStyle = isVirtualHostStyle(hostName)
-
-		if s3Clnt.virtualStyle {
-			// If Amazon URL replace it with 's3.amazonaws.com'
-			if isAmazon(hostName) {
-				hostName = amazonHostName
-			}
-			// If Google URL replace it with 'storage.googleapis.com'
-			if isGoogle(hostName) {
-				hostName = googleHostName
+		s3Clnt.virtualStyle = isVirtualHostStyle(targetURL.Host)
+		hostName := clientHostName(targetURL)
+
+		// Keys printed by a credential process are part of the key,
+		// so that clients of different keys are not shared.
+		if config.CredentialProcess != "" {
+			creds, err := getProcessProvider(config.CredentialProcess).Get()
+			if err != nil {
+				return nil, err.Trace(config.HostURL)
 			}
+			config.AccessKey = creds.AccessKey
+			config.SecretKey = creds.SecretKey
+			config.SessionToken = creds.SessionToken
 		}
 
-		// Generate a hash out of s3Conf.
-		confHash := fnv.New32a()
-		confHash.Write([]byte(hostName + config.AccessKey + config.SecretKey))
-		confSum := confHash.Sum32()
+		key := newClientKey(hostName, targetURL.Scheme, config)
 
-		// Lookup previous cache by hash.
+		// Lookup previous cache by key.
 		mutex.Lock()
 		defer mutex.Unlock()
 		var api *minio.Client
 		var found bool
-		if api, found = clientCache[confSum]; !found {
+		if api, found = clientCache[key]; !found {
 			// Not found. Instantiate a new minio
-			var e error
-			if strings.ToUpper(config.Signature) == "S3V2" {
-				// if Signature version '2' use NewV2 directly.
-				api, e = minio.NewV2(hostName, config.AccessKey, config.SecretKey, secure)
-			} else {
-				// if Signature version '4' use NewV4 directly.
-				api, e = minio.NewV4(hostName, config.AccessKey, config.SecretKey, secure)
+			var err *probe.Error
+			if api, err = newMinioClient(hostName, config, secure); err != nil {
+				return nil, err.Trace(hostName)
 			}
-			if e != nil {
-				return nil, probe.NewError(e)
+			transport, err := newClientTransport(config)
+			if err != nil {
+				return nil, err.Trace(config.HostURL)
 			}
-			transport := http.DefaultTransport
-			if config.Insecure {
-				transport = &http.Transport{
-					TLSClientConfig: &tls.Config{InsecureSkipVerify: true},
-				}
+			if globalTraceRecorder != nil {
+				transport = globalTraceRecorder.Transport(transport)
 			}
 			if config.Debug {
+				// Requests are logged, traced at debug level.
+				var tracer httptracer.HTTPTracer
 				if config.Signature == "S3v4" {
-					transport = httptracer.GetNewTraceTransport(newTraceV4(), transport)
+					tracer = newTraceV4()
 				}
 				if config.Signature == "S3v2" {
-					transport = httptracer.GetNewTraceTransport(newTraceV2(), transport)
+					tracer = newTraceV2()
+				}
+				transport = newLogTransport(transport, tracer)
+			}
+			// Keys requests are finally signed with.
+			var provider credentialsProvider = staticProvider(config.credentials())
+			if config.RoleARN != "" {
+				if strings.ToUpper(config.Signature) == "S3V2" {
+					return nil, probe.NewError(errors.New("Role based credentials require signature ‘S3v4’.")).Trace(config.RoleARN)
+				}
+				if config.isAnonymous() {
+					return nil, probe.NewError(errors.New("Role based credentials require access and secret keys.")).Trace(config.RoleARN)
+				}
+				// Requests are re-signed with temporary credentials of the role.
+				stsProvider := newSTSProvider(config.AccessKey, config.SecretKey, config.RoleARN, config.ExternalID, transport)
+				stsProvider.baseCreds.SessionToken = config.SessionToken
+				if _, err := stsProvider.Get(); err != nil {
+					return nil, err.Trace(config.RoleARN)
+				}
+				provider = stsProvider
+			} else if config.CredentialProcess != "" {
+				// Requests are re-signed with the current keys of the
+				// process, which are refreshed before they expire.
+				provider = getProcessProvider(config.CredentialProcess)
+			}
+			if config.Payload != "" {
+				// Payloads of uploads are signed as configured, once
+				// requests got their final signature.
+				transport = payloadTransport{payload: config.Payload, provider: provider, transport: transport}
+			}
+			// minio-go does not send session tokens, requests are
+			// re-signed along with the token.
+			if config.RoleARN != "" || config.CredentialProcess != "" || (config.SessionToken != "" && !config.isAnonymous()) {
+				transport = stsTransport{provider: provider, transport: transport}
+			}
+			// Regions of buckets are looked up only once.
+			transport = regionTransport{
+				host:      hostName,
+				region:    config.Region,
+				regions:   globalBucketRegions,
+				transport: transport,
+			}
+			if config.Lookup != "" {
+				// Buckets are addressed as configured, whatever
+				// minio-go decided for the host.
+				transport = lookupTransport{
+					lookup:    config.Lookup,
+					creds:     config.credentials(),
+					transport: transport,
 				}
-				// Set custom transport.
 			}
 			api.SetCustomTransport(transport)
-			// Cache the new minio client with hash of config as key.
-			clientCache[confSum] = api
+			// Cache the new minio client with key of config.
+			clientCache[key] = api
+			httpClientCache[key] = &http.Client{Transport: transport}
+			providerCache[key] = provider
 		}
 		// Set app info.
 		api.SetAppInfo(config.AppName, config.AppVersion)
 
 		// Store the new api object.
 		s3Clnt.api = api
+		s3Clnt.config = config
+		s3Clnt.httpClient = httpClientCache[key]
+		s3Clnt.provider = providerCache[key]
+		s3Clnt.hostURL = &url.URL{Scheme: targetURL.Scheme, Host: hostName}
 
 		return s3Clnt, nil
 	}
+	return newClient, invalidate
+}
+
+// newMinioClient - minio client of the host, signing requests as
+// configured.
+func newMinioClient(hostName string, config *Config, secure bool) (*minio.Client, *probe.Error) {
+	var api *minio.Client
+	var e error
+	if strings.ToUpper(config.Signature) == "S3V2" {
+		// if Signature version '2' use NewV2 directly.
+		api, e = minio.NewV2(hostName, config.AccessKey, config.SecretKey, secure)
+	} else {
+		// if Signature version '4' use NewV4 directly.
+		api, e = minio.NewV4(hostName, config.AccessKey, config.SecretKey, secure)
+	}
+	if e != nil {
+		return nil, probe.NewError(e)
+	}
+	return api, nil
+}
+
+// withContext - copy of the client sending its requests within the
+// context, sharing the minio client and the transport of the host.
+// minio-go takes no contexts, calls taking one send their requests
+// themselves, which carry the context to the transport. Only listings
+// of incomplete uploads are left to minio-go, they stop between
+// uploads once the context is done.
+func (c *s3Client) withContext(ctx context.Context) *s3Client {
+	clnt := *c
+	clnt.ctx = ctx
+	return &clnt
 }
 
 // s3New returns an initialized s3Client structure. If debug is enabled,
-// it also enables an internal trace transport.
-var s3New = newFactory()
+// it also enables an internal trace transport. invalidateS3Clients
+// drops cached clients, e.g. once settings of a host changed.
+var s3New, invalidateS3Clients = newFactory()
 
 // GetURL get url.
-func (c *s3Client) GetURL() clientURL {
+func (c *s3Client) GetURL() ClientURL {
 	return *c.targetURL
 }
 
-// Add bucket notification
-func (c *s3Client) AddNotificationConfig(arn string, events []string, prefix, suffix string) *probe.Error {
+// Add bucket notification of each ARN, configurations of an ARN with
+// the same filters are updated with the events. Returns whether each
+// configuration was added, updated or left unchanged.
+func (c *s3Client) AddNotificationConfig(arns []string, events []string, prefix, suffix string) ([]string, *probe.Error) {
 	bucket, _ := c.url2BucketAndObject()
 	if err := isValidBucketName(bucket); err != nil {
-		return err
+		return nil, err
 	}
 
-	// Validate total fields in ARN.
-	fields := strings.Split(arn, ":")
-	if len(fields) != 6 {
-		return errInvalidArgument()
+	// Configure events
+	names, err := notificationEvents(events)
+	if err != nil {
+		return nil, err.Trace(events...)
+	}
+
+	var configs []minio.NotificationConfig
+	for _, arn := range arns {
+		nc, err := newNotificationConfig(arn, names, prefix, suffix)
+		if err != nil {
+			return nil, err.Trace(arn)
+		}
+		configs = append(configs, nc)
 	}
 
 	// Get any enabled notification.
 	mb, e := c.api.GetBucketNotification(bucket)
 	if e != nil {
-		return probe.NewError(e)
+		return nil, probe.NewError(e)
 	}
 
-	accountArn := minio.NewArn(fields[1], fields[2], fields[3], fields[4], fields[5])
-	nc := minio.NewNotificationConfig(accountArn)
+	changes := make([]string, len(configs))
+	changed := false
+	for i, nc := range configs {
+		changes[i] = mergeNotificationConfig(&mb, nc)
+		if changes[i] != notificationUnchanged {
+			changed = true
+		}
+	}
+	if !changed {
+		return changes, nil
+	}
 
-	// Configure events
-	for _, event := range events {
-		switch event {
-		case "put":
-			nc.AddEvents(minio.ObjectCreatedAll)
-		case "delete":
-			nc.AddEvents(minio.ObjectRemovedAll)
-		default:
-			return errInvalidArgument().Trace(events...)
+	// Set the new bucket configuration
+	if e := c.api.SetBucketNotification(bucket, mb); e != nil {
+		// Servers validate targets, S3 by sending them a test event.
+		if errResp := minio.ToErrorResponse(e); errResp.Code == "InvalidArgument" {
+			return nil, errNotificationRejected(strings.Join(arns, ", "), errResp.Message).Trace(arns...)
+		}
+		return nil, probe.NewError(e)
+	}
+	return changes, nil
+}
+
+// SendTestNotification - creates and removes an empty object matching
+// the filters, so that targets of notifications of created or removed
+// objects receive events. Returns the key of the object.
+func (c *s3Client) SendTestNotification(prefix, suffix string) (string, *probe.Error) {
+	bucket, _ := c.url2BucketAndObject()
+	if err := isValidBucketName(bucket); err != nil {
+		return "", err
+	}
+	key := fmt.Sprintf("%smc-notification-test-%d%s", prefix, time.Now().UnixNano(), suffix)
+	if _, e := c.api.PutObject(bucket, key, bytes.NewReader(nil), "application/octet-stream"); e != nil {
+		return "", probe.NewError(e).Trace(bucket, key)
+	}
+	if e := c.api.RemoveObject(bucket, key); e != nil {
+		return "", probe.NewError(e).Trace(bucket, key)
+	}
+	return key, nil
+}
+
+// Services notifications can be sent to.
+var notificationServices = []string{"sqs", "sns", "lambda"}
+
+// parseNotificationArn - parses ARNs like ‘arn:minio:sqs:us-east-1:1:webhook’,
+// errors name the field which is wrong.
+func parseNotificationArn(arn string) (minio.Arn, *probe.Error) {
+	fields := strings.Split(arn, ":")
+	if len(fields) != 6 {
+		return minio.Arn{}, errInvalidArn(arn, fmt.Sprintf("it has %d fields instead of 6 like ‘arn:partition:service:region:account-id:resource’", len(fields))).Trace(arn)
+	}
+	if fields[0] != "arn" {
+		return minio.Arn{}, errInvalidArn(arn, "it does not start with ‘arn’").Trace(arn)
+	}
+	if fields[1] == "" {
+		return minio.Arn{}, errInvalidArn(arn, "the partition is empty, e.g. ‘aws’ or ‘minio’").Trace(arn)
+	}
+	isService := false
+	for _, service := range notificationServices {
+		if fields[2] == service {
+			isService = true
 		}
 	}
+	if !isService {
+		return minio.Arn{}, errInvalidArn(arn, "the service ‘"+fields[2]+"’ is not one of ‘"+strings.Join(notificationServices, "’, ‘")+"’").Trace(arn)
+	}
+	// Servers without a region have ARNs without one.
+	if fields[4] == "" {
+		return minio.Arn{}, errInvalidArn(arn, "the account ID is empty").Trace(arn)
+	}
+	if fields[5] == "" {
+		return minio.Arn{}, errInvalidArn(arn, "the resource is empty, it names the queue, topic or function").Trace(arn)
+	}
+	return minio.NewArn(fields[1], fields[2], fields[3], fields[4], fields[5]), nil
+}
+
+// newNotificationConfig - notification of the events to the ARN.
+func newNotificationConfig(arn string, names []string, prefix, suffix string) (minio.NotificationConfig, *probe.Error) {
+	accountArn, err := parseNotificationArn(arn)
+	if err != nil {
+		return minio.NotificationConfig{}, err.Trace(arn)
+	}
+	nc := minio.NewNotificationConfig(accountArn)
+	for _, name := range names {
+		nc.AddEvents(minio.NotificationEventType(name))
+	}
 	if prefix != "" {
 		nc.AddFilterPrefix(prefix)
 	}
 	if suffix != "" {
 		nc.AddFilterSuffix(suffix)
 	}
+	return nc, nil
+}
 
-	switch fields[2] {
+// addNotificationConfig - adds the configuration to the list of its
+// service.
+func addNotificationConfig(mb *minio.BucketNotification, nc minio.NotificationConfig) {
+	switch nc.Arn.Service {
 	case "sns":
 		mb.AddTopic(nc)
 	case "sqs":
 		mb.AddQueue(nc)
 	case "lambda":
 		mb.AddLambda(nc)
-	default:
-		return errInvalidArgument().Trace(fields[2])
 	}
+}
 
-	// Set the new bucket configuration
-	if err := c.api.SetBucketNotification(bucket, mb); err != nil {
-		return probe.NewError(err)
+// removeNotificationConfigsByID - removes the configurations of the ID,
+// reports whether any was removed.
+func removeNotificationConfigsByID(mb *minio.BucketNotification, id string) bool {
+	removed := false
+	var topics []minio.TopicConfig
+	for _, topic := range mb.TopicConfigs {
+		if topic.Id == id {
+			removed = true
+			continue
+		}
+		topics = append(topics, topic)
 	}
-	return nil
+	var queues []minio.QueueConfig
+	for _, queue := range mb.QueueConfigs {
+		if queue.Id == id {
+			removed = true
+			continue
+		}
+		queues = append(queues, queue)
+	}
+	var lambdas []minio.LambdaConfig
+	for _, lambda := range mb.LambdaConfigs {
+		if lambda.Id == id {
+			removed = true
+			continue
+		}
+		lambdas = append(lambdas, lambda)
+	}
+	mb.TopicConfigs, mb.QueueConfigs, mb.LambdaConfigs = topics, queues, lambdas
+	return removed
+}
+
+// Changes of bucket notifications by mergeNotificationConfig.
+const (
+	notificationAdded     = "added"
+	notificationUpdated   = "updated"
+	notificationUnchanged = "unchanged"
+)
+
+// mergeNotificationConfig - adds the configuration to the bucket
+// notification, unless a configuration of the ARN with the same filters
+// exists, which then gets the events it lacks.
+func mergeNotificationConfig(mb *minio.BucketNotification, nc minio.NotificationConfig) string {
+	arn := nc.Arn.String()
+	var existing []*minio.NotificationConfig
+	switch nc.Arn.Service {
+	case "sns":
+		for i := range mb.TopicConfigs {
+			if mb.TopicConfigs[i].Topic == arn {
+				existing = append(existing, &mb.TopicConfigs[i].NotificationConfig)
+			}
+		}
+	case "sqs":
+		for i := range mb.QueueConfigs {
+			if mb.QueueConfigs[i].Queue == arn {
+				existing = append(existing, &mb.QueueConfigs[i].NotificationConfig)
+			}
+		}
+	case "lambda":
+		for i := range mb.LambdaConfigs {
+			if mb.LambdaConfigs[i].Lambda == arn {
+				existing = append(existing, &mb.LambdaConfigs[i].NotificationConfig)
+			}
+		}
+	}
+
+	for _, config := range existing {
+		if notificationFilter(*config) != notificationFilter(nc) {
+			continue
+		}
+		change := notificationUnchanged
+		for _, event := range nc.Events {
+			if !hasNotificationEvent(config.Events, event) {
+				config.Events = append(config.Events, event)
+				change = notificationUpdated
+			}
+		}
+		return change
+	}
+
+	addNotificationConfig(mb, nc)
+	return notificationAdded
+}
+
+// notificationFilter - prefix and suffix of a configuration.
+func notificationFilter(nc minio.NotificationConfig) (filter [2]string) {
+	if nc.Filter == nil {
+		return filter
+	}
+	for _, rule := range nc.Filter.S3Key.FilterRules {
+		switch rule.Name {
+		case "prefix":
+			filter[0] = rule.Value
+		case "suffix":
+			filter[1] = rule.Value
+		}
+	}
+	return filter
+}
+
+// hasNotificationEvent - reports whether the event is one of the events.
+func hasNotificationEvent(events []minio.NotificationEventType, event minio.NotificationEventType) bool {
+	for _, e := range events {
+		if e == event {
+			return true
+		}
+	}
+	return false
 }
 
 // Remove bucket notification
@@ -228,21 +589,18 @@ func (c *s3Client) RemoveNotificationConfig(arn string) *probe.Error {
 		return probe.NewError(e)
 	}
 
-	fields := strings.Split(arn, ":")
-	if len(fields) != 6 {
-		return errInvalidArgument().Trace(fields...)
+	accountArn, err := parseNotificationArn(arn)
+	if err != nil {
+		return err.Trace(arn)
 	}
-	accountArn := minio.NewArn(fields[1], fields[2], fields[3], fields[4], fields[5])
 
-	switch fields[2] {
+	switch accountArn.Service {
 	case "sns":
 		mb.RemoveTopicByArn(accountArn)
 	case "sqs":
 		mb.RemoveQueueByArn(accountArn)
 	case "lambda":
 		mb.RemoveLambdaByArn(accountArn)
-	default:
-		return errInvalidArgument().Trace(fields[2])
 	}
 
 	// Set the new bucket configuration
@@ -337,21 +695,125 @@ func (c *s3Client) ListNotificationConfigs(arn string) ([]notificationConfig, *p
 	return configs, nil
 }
 
+// ExportNotificationConfig - the whole notification configuration of
+// the bucket.
+func (c *s3Client) ExportNotificationConfig() (minio.BucketNotification, *probe.Error) {
+	bucket, _ := c.url2BucketAndObject()
+	if err := isValidBucketName(bucket); err != nil {
+		return minio.BucketNotification{}, err
+	}
+	mb, e := c.api.GetBucketNotification(bucket)
+	if e != nil {
+		return minio.BucketNotification{}, probe.NewError(e)
+	}
+	return mb, nil
+}
+
+// ImportNotificationConfig - replaces the notification configuration of
+// the bucket with the given one.
+func (c *s3Client) ImportNotificationConfig(mb minio.BucketNotification) *probe.Error {
+	bucket, _ := c.url2BucketAndObject()
+	if err := isValidBucketName(bucket); err != nil {
+		return err
+	}
+	if e := c.api.SetBucketNotification(bucket, mb); e != nil {
+		return probe.NewError(e)
+	}
+	return nil
+}
+
 // Unwatch de-registers all bucket notification events for a given accountID.
-func (c *s3Client) Unwatch(params watchParams) *probe.Error {
+func (c *s3Client) Unwatch(params WatchParams) *probe.Error {
 	// Extract bucket and object.
 	bucket, _ := c.url2BucketAndObject()
 	if err := isValidBucketName(bucket); err != nil {
 		return err
 	}
+	// Only watches of an ARN registered a notification.
+	if params.ARN == "" {
+		return nil
+	}
+	// Watches are mostly unwatched once interrupted.
+	ctx, cancel := newCleanupContext()
+	defer cancel()
+	mb, err := c.getBucketNotification(ctx, bucket)
+	if err != nil {
+		return err.Trace(bucket)
+	}
+	if !removeNotificationConfigsByID(&mb, params.notificationID()) {
+		return nil
+	}
+	if err = c.setBucketNotification(ctx, bucket, mb); err != nil {
+		return err.Trace(bucket)
+	}
 	// Success.
 	return nil
 }
 
+// getBucketNotification - notification configuration of the bucket.
+func (c *s3Client) getBucketNotification(ctx context.Context, bucket string) (minio.BucketNotification, *probe.Error) {
+	resp, err := c.executeMethod("GET", s3RequestMetadata{
+		bucketName:  bucket,
+		queryValues: url.Values{"notification": []string{""}},
+		ctx:         ctx,
+	})
+	if err != nil {
+		return minio.BucketNotification{}, err.Trace(bucket)
+	}
+	defer resp.Body.Close()
+	mb := minio.BucketNotification{}
+	if e := xml.NewDecoder(resp.Body).Decode(&mb); e != nil {
+		return minio.BucketNotification{}, probe.NewError(e)
+	}
+	return mb, nil
+}
+
+// setBucketNotification - replaces the notification configuration of
+// the bucket.
+func (c *s3Client) setBucketNotification(ctx context.Context, bucket string, mb minio.BucketNotification) *probe.Error {
+	body, e := xml.Marshal(mb)
+	if e != nil {
+		return probe.NewError(e)
+	}
+	resp, err := c.executeMethod("PUT", s3RequestMetadata{
+		bucketName:   bucket,
+		queryValues:  url.Values{"notification": []string{""}},
+		contentBytes: body,
+		ctx:          ctx,
+	})
+	if err != nil {
+		return err.Trace(bucket)
+	}
+	resp.Body.Close()
+	return nil
+}
+
+// registerWatch - registers a notification of the watched events to the
+// ARN of the watch, removed again by Unwatch.
+func (c *s3Client) registerWatch(bucket string, params WatchParams, events []string) *probe.Error {
+	nc, err := newNotificationConfig(params.ARN, events, params.Prefix, params.Suffix)
+	if err != nil {
+		return err.Trace(params.ARN)
+	}
+	nc.Id = params.notificationID()
+	mb, e := c.api.GetBucketNotification(bucket)
+	if e != nil {
+		return probe.NewError(e).Trace(bucket)
+	}
+	// Replace the notification of an earlier watch of the same ID.
+	removeNotificationConfigsByID(&mb, nc.Id)
+	addNotificationConfig(&mb, nc)
+	if e = c.api.SetBucketNotification(bucket, mb); e != nil {
+		return probe.NewError(e).Trace(bucket)
+	}
+	return nil
+}
+
 // Start watching on all bucket events for a given account ID.
-func (c *s3Client) Watch(params watchParams) (*watchObject, *probe.Error) {
+func (c *s3Client) Watch(params WatchParams) (*WatchObject, *probe.Error) {
 	eventChan := make(chan Event)
 	errorChan := make(chan *probe.Error)
+	statusChan := make(chan WatchStatus)
 	doneChan := make(chan bool)
 
 	// Extract bucket and object.
@@ -361,91 +823,240 @@ func (c *s3Client) Watch(params watchParams) (*watchObject, *probe.Error) {
 	}
 
 	// Flag set to set the notification.
-	var events []string
-	for _, event := range params.events {
-		switch event {
-		case "put":
-			events = append(events, string(minio.ObjectCreatedAll))
-		case "delete":
-			events = append(events, string(minio.ObjectRemovedAll))
-		default:
-			return nil, errInvalidArgument().Trace(event)
-		}
+	events, err := notificationEvents(params.Events)
+	if err != nil {
+		return nil, err.Trace(params.Events...)
+	}
+	if object != "" && params.Prefix != "" {
+		return nil, errInvalidArgument().Trace(params.Prefix, object)
 	}
-	if object != "" && params.prefix != "" {
-		return nil, errInvalidArgument().Trace(params.prefix, object)
+	if object != "" && params.Prefix == "" {
+		params.Prefix = object
 	}
-	if object != "" && params.prefix == "" {
-		params.prefix = object
+	if params.ARN != "" {
+		if err = c.registerWatch(bucket, params, events); err != nil {
+			return nil, err.Trace(bucket)
+		}
 	}
 
 	doneCh := make(chan struct{})
 
-	// wait for doneChan to close the other channels
+	// wait for doneChan to stop listening
 	go func() {
 		<-doneChan
-
 		close(doneCh)
-		close(eventChan)
-		close(errorChan)
 	}()
 
-	// Start listening on all bucket events.
-	eventsCh := c.api.ListenBucketNotification(bucket, params.prefix, params.suffix, events, doneCh)
-
-	// wait for events to occur and sent them through the eventChan and errorChan
+	// Listen on bucket events and send them through the eventChan and
+	// errorChan, reconnecting streams dropped by the server. Events
+	// replayed by the server are only sent once.
 	go func() {
-		for notificationInfo := range eventsCh {
-			if notificationInfo.Err != nil {
-				errorChan <- probe.NewError(notificationInfo.Err)
-				continue
+		defer close(eventChan)
+		defer close(errorChan)
+		defer close(statusChan)
+
+		seen := newEventDeduper(watchDedupeSize)
+		delay := watchRetryDelay
+		for {
+			lastErr := c.listenBucket(bucket, params, events, seen, eventChan, errorChan, doneCh)
+			if lastErr == nil {
+				return
 			}
 
-			for _, record := range notificationInfo.Records {
-				bucketName := record.S3.Bucket.Name
-				key, e := url.QueryUnescape(record.S3.Object.Key)
-				if e != nil {
-					errorChan <- probe.NewError(e)
-					continue
+			// Wait until the server is back, with growing delays.
+			for lastErr != nil {
+				select {
+				case statusChan <- WatchStatus{Err: lastErr, Delay: delay}:
+				case <-doneCh:
+					return
 				}
-
-				u := *c.targetURL
-				u.Path = path.Join(string(u.Separator), bucketName, key)
-				if strings.HasPrefix(record.EventName, "s3:ObjectCreated:") {
-					eventChan <- Event{
-						Time:   record.EventTime,
-						Size:   record.S3.Object.Size,
-						Path:   u.String(),
-						Client: c,
-						Type:   EventCreate,
-					}
-				} else if strings.HasPrefix(record.EventName, "s3:ObjectRemoved:") {
-					eventChan <- Event{
-						Time:   record.EventTime,
-						Path:   u.String(),
-						Client: c,
-						Type:   EventRemove,
+				select {
+				case <-time.After(delay):
+				case <-doneCh:
+					return
+				}
+				if delay *= 2; delay > maxWatchRetryDelay {
+					delay = maxWatchRetryDelay
+				}
+				if _, e := c.api.BucketExists(bucket); e != nil {
+					if !isWatchErrorTemporary(e) {
+						select {
+						case errorChan <- probe.NewError(e):
+						case <-doneCh:
+						}
+						return
 					}
-				} else {
-					// ignore other events
+					lastErr = probe.NewError(e)
+					continue
 				}
+				lastErr = nil
+			}
+			delay = watchRetryDelay
+			select {
+			case statusChan <- WatchStatus{Connected: true}:
+			case <-doneCh:
+				return
 			}
 		}
 	}()
 
-	return &watchObject{
+	return &WatchObject{
 		events: eventChan,
 		errors: errorChan,
+		status: statusChan,
 		done:   doneChan,
 	}, nil
 }
 
+// listenBucket - sends events of the bucket until done, returns the
+// error which dropped the connection if it may be reconnected.
+func (c *s3Client) listenBucket(bucket string, params WatchParams, events []string, seen *eventDeduper, eventChan chan<- Event, errorChan chan<- *probe.Error, doneCh chan struct{}) *probe.Error {
+	eventsCh := c.api.ListenBucketNotification(bucket, params.Prefix, params.Suffix, events, doneCh)
+	for {
+		var notificationInfo minio.NotificationInfo
+		var ok bool
+		select {
+		case notificationInfo, ok = <-eventsCh:
+		case <-doneCh:
+			return nil
+		}
+		if !ok {
+			// Listening stops after errors only.
+			return errWatchDropped(bucket).Trace(bucket)
+		}
+		if notificationInfo.Err != nil {
+			if isWatchErrorTemporary(notificationInfo.Err) {
+				return probe.NewError(notificationInfo.Err)
+			}
+			select {
+			case errorChan <- probe.NewError(notificationInfo.Err):
+			case <-doneCh:
+			}
+			return nil
+		}
+
+		for _, record := range notificationInfo.Records {
+			bucketName := record.S3.Bucket.Name
+			key, e := url.QueryUnescape(record.S3.Object.Key)
+			if e != nil {
+				select {
+				case errorChan <- probe.NewError(e):
+				case <-doneCh:
+					return nil
+				}
+				continue
+			}
+
+			if !params.Matches(key, record.EventName) {
+				continue
+			}
+			// Events without a sequencer cannot be told apart.
+			if sequencer := record.S3.Object.Sequencer; sequencer != "" {
+				if seen.Seen(strings.Join([]string{record.EventName, bucketName, key, record.S3.Object.VersionID, sequencer}, "\x00")) {
+					continue
+				}
+			}
+
+			u := *c.targetURL
+			u.Path = path.Join(string(u.Separator), bucketName, key)
+			event := Event{
+				Time:      record.EventTime,
+				Path:      u.String(),
+				Client:    c,
+				Type:      eventNameType(record.EventName),
+				Name:      record.EventName,
+				Key:       key,
+				Bucket:    bucketName,
+				ETag:      record.S3.Object.ETag,
+				VersionID: record.S3.Object.VersionID,
+				SourceIP:  record.RequestParameters["sourceIPAddress"],
+				Sequencer: record.S3.Object.Sequencer,
+			}
+			// Removed objects have no size.
+			if event.Type != EventRemove {
+				event.Size = record.S3.Object.Size
+			}
+			select {
+			case eventChan <- event:
+			case <-doneCh:
+				return nil
+			}
+		}
+	}
+}
+
+// Codes of errors of servers which are unavailable for a while.
+var temporaryErrorCodes = map[string]bool{
+	"InternalError":              true,
+	"ServiceUnavailable":         true,
+	"SlowDown":                   true,
+	"RequestTimeout":             true,
+	"XMinioServerNotInitialized": true,
+}
+
+// isWatchErrorTemporary - reports whether listening may succeed again
+// after the error, errors of requests the server denied are final.
+func isWatchErrorTemporary(e error) bool {
+	code := minio.ToErrorResponse(e).Code
+	// Errors of connections have no code, errors of responses without
+	// a body have the HTTP status as code.
+	return code == "" || temporaryErrorCodes[code] || strings.HasPrefix(code, "5")
+}
+
 // Get - get object.
-func (c *s3Client) Get() (io.Reader, *probe.Error) {
+func (c *s3Client) Get(ctx context.Context, opts GetOpts) (io.Reader, *probe.Error) {
+	c = c.withContext(ctx)
 	bucket, object := c.url2BucketAndObject()
-	reader, e := c.api.GetObject(bucket, object)
+	if object == "" {
+		return nil, probe.NewError(ObjectMissing{})
+	}
+	if err := isValidBucketName(bucket); err != nil {
+		return nil, probe.NewError(BucketInvalid{Bucket: bucket})
+	}
+	sse := opts.SSE
+	var reader io.Reader
+	var e error
+	if sse.Type == sseCustomer || opts.Offset > 0 || opts.Length > 0 || opts.Checksum || opts.Metadata {
+		// Objects with a customer key, a range, the ETag or metadata
+		// are requested up front.
+		header := make(http.Header)
+		sse.setGetHeaders(header)
+		if opts.Checksum {
+			// SHA256 checksums are only returned if asked for.
+			header.Set("X-Amz-Checksum-Mode", "ENABLED")
+		}
+		if opts.Offset > 0 || opts.Length > 0 {
+			byteRange := fmt.Sprintf("bytes=%d-", opts.Offset)
+			if opts.Length > 0 {
+				byteRange += strconv.FormatInt(opts.Offset+opts.Length-1, 10)
+			}
+			header.Set("Range", byteRange)
+		}
+		resp, err := c.executeMethod("GET", s3RequestMetadata{
+			bucketName: bucket,
+			objectName: object,
+			header:     header,
+		})
+		if err != nil {
+			e = err.ToGoError()
+		} else if opts.Checksum && opts.Offset == 0 && opts.Length == 0 {
+			if reader, err = c.newChecksumReader(bucket, object, resp, sse); err != nil {
+				e = err.ToGoError()
+			}
+		} else {
+			reader = resp.Body
+		}
+		if e == nil && opts.Metadata {
+			reader = objectReader{Reader: reader, metadata: metadataFromHeader(resp.Header)}
+		}
+	} else {
+		reader = &s3ObjectReader{clnt: c, bucket: bucket, object: object}
+	}
 	if e != nil {
 		errResponse := minio.ToErrorResponse(e)
+		if err := encryptionError(errResponse, c.targetURL.String(), sse); err != nil {
+			return nil, err
+		}
 		if errResponse.Code == "AccessDenied" {
 			return nil, probe.NewError(PathInsufficientPermission{Path: c.targetURL.String()})
 		}
@@ -462,22 +1073,42 @@ func (c *s3Client) Get() (io.Reader, *probe.Error) {
 		if errResponse.Code == "NoSuchKey" || errResponse.Code == "InvalidArgument" {
 			return nil, probe.NewError(ObjectMissing{})
 		}
+		if errResponse.Code == "InvalidRange" {
+			return nil, probe.NewError(ObjectRangeInvalid{Object: c.targetURL.String(), Offset: opts.Offset})
+		}
+		if errResponse.Code == "InvalidObjectState" {
+			return nil, probe.NewError(ObjectOnGlacier{Object: c.targetURL.String()})
+		}
 		return nil, probe.NewError(e)
 	}
 	return reader, nil
 }
 
 // Copy - copy object
-func (c *s3Client) Copy(source string, size int64, progress io.Reader) *probe.Error {
+func (c *s3Client) Copy(ctx context.Context, source string, size int64, progress io.Reader, opts CopyOpts) *probe.Error {
+	c = c.withContext(ctx)
 	bucket, object := c.url2BucketAndObject()
 	if bucket == "" {
 		return probe.NewError(BucketNameEmpty{})
 	}
-	// Empty copy conditions
-	copyConds := minio.NewCopyConditions()
-	e := c.api.CopyObject(bucket, object, source, copyConds)
+	var e error
+	if size > s3MaxCopySize {
+		// Progress is updated with every copied part.
+		if err := c.copyObjectMultipart(bucket, object, source, size, progress, opts); err != nil {
+			e = err.ToGoError()
+		}
+		progress = nil
+	} else if err := c.copyObject(bucket, object, source, opts); err != nil {
+		e = err.ToGoError()
+	}
 	if e != nil {
 		errResponse := minio.ToErrorResponse(e)
+		if err := encryptionError(errResponse, source, opts.SrcSSE); err != nil {
+			return err
+		}
+		if errResponse.Code == "PreconditionFailed" {
+			return probe.NewError(ObjectPreconditionFailed{Object: source})
+		}
 		if errResponse.Code == "AccessDenied" {
 			return probe.NewError(PathInsufficientPermission{
 				Path: c.targetURL.String(),
@@ -508,10 +1139,10 @@ func (c *s3Client) Copy(source string, size int64, progress io.Reader) *probe.Er
 }
 
 // Put - put object.
-func (c *s3Client) Put(reader io.Reader, size int64, contentType string, progress io.Reader) (int64, *probe.Error) {
-	// md5 is purposefully ignored since AmazonS3 does not return proper md5sum
-	// for a multipart upload and there is no need to cross verify,
-	// invidual parts are properly verified fully in transit and also upon completion
+func (c *s3Client) Put(ctx context.Context, reader io.Reader, size int64, contentType string, progress io.Reader, opts PutOpts) (int64, *probe.Error) {
+	c = c.withContext(ctx)
+	// md5 is only cross verified with opts.Checksum, invidual parts are
+	// otherwise properly verified fully in transit and also upon completion
 	// of the multipart request.
 	bucket, object := c.url2BucketAndObject()
 	if contentType == "" {
@@ -520,15 +1151,28 @@ func (c *s3Client) Put(reader io.Reader, size int64, contentType string, progres
 	if bucket == "" {
 		return 0, probe.NewError(BucketNameEmpty{})
 	}
-	n, e := c.api.PutObjectWithProgress(bucket, object, reader, contentType, progress)
-	if e != nil {
+	n, err := c.putObject(bucket, object, reader, size, contentType, progress, opts)
+	if err != nil {
+		e := err.ToGoError()
+		switch e.(type) {
+		case UnexpectedEOF, ObjectChecksumMismatch:
+			return n, probe.NewError(e)
+		}
 		errResponse := minio.ToErrorResponse(e)
+		if err := encryptionError(errResponse, c.targetURL.String(), opts.SSE); err != nil {
+			return n, err
+		}
 		if errResponse.Code == "UnexpectedEOF" || e == io.EOF {
 			return n, probe.NewError(UnexpectedEOF{
 				TotalSize:    size,
 				TotalWritten: n,
 			})
 		}
+		if errResponse.Code == "BadDigest" || errResponse.Code == "XAmzContentSHA256Mismatch" {
+			return n, probe.NewError(ObjectChecksumMismatch{
+				Object: c.targetURL.String(),
+			})
+		}
 		if errResponse.Code == "AccessDenied" {
 			return n, probe.NewError(PathInsufficientPermission{
 				Path: c.targetURL.String(),
@@ -563,20 +1207,34 @@ func (c *s3Client) Put(reader io.Reader, size int64, contentType string, progres
 }
 
 // Remove - remove object or bucket.
-func (c *s3Client) Remove(incomplete bool) *probe.Error {
+func (c *s3Client) Remove(ctx context.Context, incomplete bool) *probe.Error {
+	c = c.withContext(ctx)
 	bucket, object := c.url2BucketAndObject()
 	// Remove only incomplete object.
 	if incomplete && object != "" {
-		e := c.api.RemoveIncompleteUpload(bucket, object)
-		return probe.NewError(e)
+		uploadIDs, err := c.listUploadIDs(bucket, object)
+		if err != nil {
+			return err.Trace(bucket, object)
+		}
+		for _, uploadID := range uploadIDs {
+			if err = c.abortMultipartUpload(bucket, object, uploadID); err != nil {
+				return err.Trace(bucket, object)
+			}
+		}
+		return nil
 	}
-	var e error
-	if object == "" {
-		e = c.api.RemoveBucket(bucket)
-	} else {
-		e = c.api.RemoveObject(bucket, object)
+	if err := isValidBucketName(bucket); err != nil {
+		return err.Trace(bucket)
+	}
+	resp, err := c.executeMethod("DELETE", s3RequestMetadata{
+		bucketName: bucket,
+		objectName: object,
+	})
+	if err != nil {
+		return err.Trace(bucket, object)
 	}
-	return probe.NewError(e)
+	resp.Body.Close()
+	return nil
 }
 
 // We support '.' with bucket names but we fallback to using path
@@ -658,19 +1316,180 @@ func (c *s3Client) SetAccess(bucketPolicy string) *probe.Error {
 	return nil
 }
 
-// listObjectWrapper - select ObjectList version depending on the target hostname
-func (c *s3Client) listObjectWrapper(bucket, object string, isRecursive bool, doneCh chan struct{}) <-chan minio.ObjectInfo {
-	if c.targetURL.Host == amazonHostName {
-		return c.api.ListObjectsV2(bucket, object, isRecursive, doneCh)
+// listObjectsResult - response of ListObjects and ListObjectsV2.
+type listObjectsResult struct {
+	Contents       []minio.ObjectInfo
+	CommonPrefixes []struct {
+		Prefix string
+	}
+	IsTruncated           bool
+	NextMarker            string
+	NextContinuationToken string
+}
+
+// listObjectWrapper - lists the objects below the prefix with requests
+// sent within the context of the client, with ListObjectsV2 on hosts
+// supporting it. Common prefixes of each page follow its objects,
+// errors end the listing, as does the context once it is done.
+func (c *s3Client) listObjectWrapper(bucket, object string, isRecursive bool) <-chan minio.ObjectInfo {
+	objectCh := make(chan minio.ObjectInfo, 1)
+	var doneCh <-chan struct{}
+	if c.ctx != nil {
+		doneCh = c.ctx.Done()
+	}
+	send := func(objectInfo minio.ObjectInfo) bool {
+		select {
+		case objectCh <- objectInfo:
+			return true
+		case <-doneCh:
+			return false
+		}
+	}
+	go func() {
+		defer close(objectCh)
+		if err := isValidBucketName(bucket); err != nil {
+			send(minio.ObjectInfo{Err: minio.ErrInvalidBucketName(err.ToGoError().Error())})
+			return
+		}
+		listV2 := c.targetURL.Host == amazonHostName || c.supportsListV2(bucket)
+		queryValues := url.Values{}
+		if listV2 {
+			queryValues.Set("list-type", "2")
+			queryValues.Set("fetch-owner", "true")
+		}
+		if object != "" {
+			queryValues.Set("prefix", object)
+		}
+		if !isRecursive {
+			queryValues.Set("delimiter", "/")
+		}
+		queryValues.Set("max-keys", "1000")
+		for {
+			resp, err := c.executeMethod("GET", s3RequestMetadata{
+				bucketName:  bucket,
+				queryValues: queryValues,
+			})
+			if err != nil {
+				send(minio.ObjectInfo{Err: err.ToGoError()})
+				return
+			}
+			result := listObjectsResult{}
+			e := xml.NewDecoder(resp.Body).Decode(&result)
+			resp.Body.Close()
+			if e != nil {
+				send(minio.ObjectInfo{Err: e})
+				return
+			}
+			marker := ""
+			for _, objectInfo := range result.Contents {
+				marker = objectInfo.Key
+				if !send(objectInfo) {
+					return
+				}
+			}
+			for _, prefix := range result.CommonPrefixes {
+				if !send(minio.ObjectInfo{Key: prefix.Prefix}) {
+					return
+				}
+			}
+			if !result.IsTruncated {
+				return
+			}
+			if listV2 {
+				queryValues.Set("continuation-token", result.NextContinuationToken)
+				continue
+			}
+			// NextMarker is only returned for delimited listings.
+			if result.NextMarker != "" {
+				marker = result.NextMarker
+			}
+			queryValues.Set("marker", marker)
+		}
+	}()
+	return objectCh
+}
+
+// listBuckets - lists all buckets of the host within the context of
+// the client.
+func (c *s3Client) listBuckets() ([]minio.BucketInfo, error) {
+	resp, err := c.executeMethod("GET", s3RequestMetadata{})
+	if err != nil {
+		return nil, err.ToGoError()
+	}
+	defer resp.Body.Close()
+	result := struct {
+		Buckets struct {
+			Bucket []minio.BucketInfo
+		}
+	}{}
+	if e := xml.NewDecoder(resp.Body).Decode(&result); e != nil {
+		return nil, e
 	}
-	return c.api.ListObjects(bucket, object, isRecursive, doneCh)
+	return result.Buckets.Bucket, nil
+}
+
+// Hosts known to support or not to support ListObjectsV2.
+var listV2Hosts = struct {
+	sync.Mutex
+	supported map[string]bool
+}{supported: make(map[string]bool)}
+
+// supportsListV2 - reports whether the host lists objects with
+// ListObjectsV2, detected once per host by listing a single object of
+// the bucket. Hosts not supporting it ignore the list type and answer
+// without a key count, and would list the first page over and over.
+// Failed probes are not remembered, the listing falls back to
+// ListObjects and the next one probes again.
+func (c *s3Client) supportsListV2(bucket string) bool {
+	host := c.hostURL.Host
+	listV2Hosts.Lock()
+	supported, ok := listV2Hosts.supported[host]
+	listV2Hosts.Unlock()
+	if ok {
+		return supported
+	}
+
+	// Concurrent first listings of a host probe it alike.
+	supported, ok = c.probeListV2(bucket)
+	if !ok {
+		return false
+	}
+	listV2Hosts.Lock()
+	listV2Hosts.supported[host] = supported
+	listV2Hosts.Unlock()
+	return supported
+}
+
+// probeListV2 - lists a single object of the bucket with ListObjectsV2,
+// reports whether the answer holds a key count, and whether there was
+// a listing to tell from.
+func (c *s3Client) probeListV2(bucket string) (supported bool, ok bool) {
+	resp, err := c.executeMethod("GET", s3RequestMetadata{
+		bucketName:  bucket,
+		queryValues: url.Values{"list-type": {"2"}, "max-keys": {"1"}},
+	})
+	if err != nil {
+		return false, false
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return false, false
+	}
+	result := struct {
+		XMLName  xml.Name `xml:"ListBucketResult"`
+		KeyCount *int
+	}{}
+	if e := xml.NewDecoder(resp.Body).Decode(&result); e != nil {
+		return false, false
+	}
+	return result.KeyCount != nil, true
 }
 
 // Stat - send a 'HEAD' on a bucket or object to fetch its metadata.
-func (c *s3Client) Stat() (*clientContent, *probe.Error) {
+func (c *s3Client) Stat() (*ClientContent, *probe.Error) {
 	c.mutex.Lock()
 	defer c.mutex.Unlock()
-	objectMetadata := &clientContent{}
+	objectMetadata := &ClientContent{}
 	bucket, object := c.url2BucketAndObject()
 	// Bucket name cannot be empty, stat on URL has no meaning.
 	if bucket == "" {
@@ -683,11 +1502,30 @@ func (c *s3Client) Stat() (*clientContent, *probe.Error) {
 		if !exists {
 			return nil, probe.NewError(BucketDoesNotExist{Bucket: bucket})
 		}
-		bucketMetadata := &clientContent{}
+		bucketMetadata := &ClientContent{}
 		bucketMetadata.URL = *c.targetURL
 		bucketMetadata.Type = os.ModeDir
 		return bucketMetadata, nil
 	}
+
+	// Objects are read with a HEAD request, listing is only needed for
+	// prefixes. Objects encrypted with customer keys can not be read
+	// without their key and are listed as well.
+	if !strings.HasSuffix(object, string(c.targetURL.Separator)) {
+		info, err := c.StatObject("", EncryptOpts{})
+		if err == nil {
+			objectMetadata.URL = *c.targetURL
+			objectMetadata.Time = info.LastModified
+			objectMetadata.Size = info.Size
+			objectMetadata.Type = os.FileMode(0664)
+			objectMetadata.StorageClass = info.StorageClass
+			return objectMetadata, nil
+		}
+		if _, ok := err.ToGoError().(ObjectMissing); !ok && minio.ToErrorResponse(err.ToGoError()).Code != "BadRequest" {
+			return nil, err.Trace(c.targetURL.String())
+		}
+	}
+
 	isRecursive := false
 
 	// Remove trailing slashes needed for the following ListObjects call.
@@ -695,7 +1533,7 @@ func (c *s3Client) Stat() (*clientContent, *probe.Error) {
 	// facilitate the work of the upper layers
 	object = strings.TrimRight(object, string(c.targetURL.Separator))
 
-	for objectStat := range c.listObjectWrapper(bucket, object, isRecursive, nil) {
+	for objectStat := range c.listObjectWrapper(bucket, object, isRecursive) {
 		if objectStat.Err != nil {
 			return nil, probe.NewError(objectStat.Err)
 		}
@@ -704,6 +1542,7 @@ func (c *s3Client) Stat() (*clientContent, *probe.Error) {
 			objectMetadata.Time = objectStat.LastModified
 			objectMetadata.Size = objectStat.Size
 			objectMetadata.Type = os.FileMode(0664)
+			objectMetadata.StorageClass = objectStat.StorageClass
 			return objectMetadata, nil
 		}
 		if strings.HasSuffix(objectStat.Key, string(c.targetURL.Separator)) {
@@ -733,7 +1572,12 @@ func isVirtualHostStyle(host string) bool {
 
 // url2BucketAndObject gives bucketName and objectName from URL path.
 func (c *s3Client) url2BucketAndObject() (bucketName, objectName string) {
-	path := c.targetURL.Path
+	return c.splitURL(c.targetURL)
+}
+
+// splitURL - bucket and object of an URL on the host of the client.
+func (c *s3Client) splitURL(u *ClientURL) (bucketName, objectName string) {
+	path := u.Path
 	// Convert any virtual host styled requests.
 	//
 	// For the time being this check is introduced for S3,
@@ -741,16 +1585,16 @@ func (c *s3Client) url2BucketAndObject() (bucketName, objectName string) {
 	// List them below.
 	if c.virtualStyle {
 		var bucket string
-		hostIndex := strings.Index(c.targetURL.Host, "s3")
+		hostIndex := strings.Index(u.Host, "s3")
 		if hostIndex == -1 {
-			hostIndex = strings.Index(c.targetURL.Host, "storage.googleapis")
+			hostIndex = strings.Index(u.Host, "storage.googleapis")
 		}
 		if hostIndex > 0 {
-			bucket = c.targetURL.Host[:hostIndex-1]
-			path = string(c.targetURL.Separator) + bucket + c.targetURL.Path
+			bucket = u.Host[:hostIndex-1]
+			path = string(u.Separator) + bucket + u.Path
 		}
 	}
-	splits := strings.SplitN(path, string(c.targetURL.Separator), 3)
+	splits := strings.SplitN(path, string(u.Separator), 3)
 	switch len(splits) {
 	case 0, 1:
 		bucketName = ""
@@ -768,11 +1612,12 @@ func (c *s3Client) url2BucketAndObject() (bucketName, objectName string) {
 /// Bucket API operations.
 
 // List - list at delimited path, if not recursive.
-func (c *s3Client) List(recursive, incomplete bool) <-chan *clientContent {
+func (c *s3Client) List(ctx context.Context, recursive, incomplete bool) <-chan *ClientContent {
 	c.mutex.Lock()
 	defer c.mutex.Unlock()
 
-	contentCh := make(chan *clientContent)
+	contentCh := make(chan *ClientContent)
+	c = c.withContext(ctx)
 	if incomplete {
 		if recursive {
 			go c.listIncompleteRecursiveInRoutine(contentCh)
@@ -789,29 +1634,29 @@ func (c *s3Client) List(recursive, incomplete bool) <-chan *clientContent {
 	return contentCh
 }
 
-func (c *s3Client) listIncompleteInRoutine(contentCh chan *clientContent) {
+func (c *s3Client) listIncompleteInRoutine(contentCh chan *ClientContent) {
 	defer close(contentCh)
 	// get bucket and object from URL.
 	b, o := c.url2BucketAndObject()
 	switch {
 	case b == "" && o == "":
-		buckets, err := c.api.ListBuckets()
+		buckets, err := c.listBuckets()
 		if err != nil {
-			contentCh <- &clientContent{
+			contentCh <- &ClientContent{
 				Err: probe.NewError(err),
 			}
 			return
 		}
 		isRecursive := false
 		for _, bucket := range buckets {
-			for object := range c.api.ListIncompleteUploads(bucket.Name, o, isRecursive, nil) {
+			for object := range c.api.ListIncompleteUploads(bucket.Name, o, isRecursive, c.ctx.Done()) {
 				if object.Err != nil {
-					contentCh <- &clientContent{
+					contentCh <- &ClientContent{
 						Err: probe.NewError(object.Err),
 					}
 					return
 				}
-				content := &clientContent{}
+				content := &ClientContent{}
 				url := *c.targetURL
 				// Join bucket with - incoming object key.
 				url.Path = filepath.Join(string(url.Separator), bucket.Name, object.Key)
@@ -835,14 +1680,14 @@ func (c *s3Client) listIncompleteInRoutine(contentCh chan *clientContent) {
 		}
 	default:
 		isRecursive := false
-		for object := range c.api.ListIncompleteUploads(b, o, isRecursive, nil) {
+		for object := range c.api.ListIncompleteUploads(b, o, isRecursive, c.ctx.Done()) {
 			if object.Err != nil {
-				contentCh <- &clientContent{
+				contentCh <- &ClientContent{
 					Err: probe.NewError(object.Err),
 				}
 				return
 			}
-			content := &clientContent{}
+			content := &ClientContent{}
 			url := *c.targetURL
 			// Join bucket with - incoming object key.
 			url.Path = filepath.Join(string(url.Separator), b, object.Key)
@@ -866,31 +1711,31 @@ func (c *s3Client) listIncompleteInRoutine(contentCh chan *clientContent) {
 	}
 }
 
-func (c *s3Client) listIncompleteRecursiveInRoutine(contentCh chan *clientContent) {
+func (c *s3Client) listIncompleteRecursiveInRoutine(contentCh chan *ClientContent) {
 	defer close(contentCh)
 	// get bucket and object from URL.
 	b, o := c.url2BucketAndObject()
 	switch {
 	case b == "" && o == "":
-		buckets, err := c.api.ListBuckets()
+		buckets, err := c.listBuckets()
 		if err != nil {
-			contentCh <- &clientContent{
+			contentCh <- &ClientContent{
 				Err: probe.NewError(err),
 			}
 			return
 		}
 		isRecursive := true
 		for _, bucket := range buckets {
-			for object := range c.api.ListIncompleteUploads(bucket.Name, o, isRecursive, nil) {
+			for object := range c.api.ListIncompleteUploads(bucket.Name, o, isRecursive, c.ctx.Done()) {
 				if object.Err != nil {
-					contentCh <- &clientContent{
+					contentCh <- &ClientContent{
 						Err: probe.NewError(object.Err),
 					}
 					return
 				}
 				url := *c.targetURL
 				url.Path = filepath.Join(url.Path, bucket.Name, object.Key)
-				content := &clientContent{}
+				content := &ClientContent{}
 				content.URL = url
 				content.Size = object.Size
 				content.Time = object.Initiated
@@ -900,9 +1745,9 @@ func (c *s3Client) listIncompleteRecursiveInRoutine(contentCh chan *clientConten
 		}
 	default:
 		isRecursive := true
-		for object := range c.api.ListIncompleteUploads(b, o, isRecursive, nil) {
+		for object := range c.api.ListIncompleteUploads(b, o, isRecursive, c.ctx.Done()) {
 			if object.Err != nil {
-				contentCh <- &clientContent{
+				contentCh <- &ClientContent{
 					Err: probe.NewError(object.Err),
 				}
 				return
@@ -913,7 +1758,7 @@ func (c *s3Client) listIncompleteRecursiveInRoutine(contentCh chan *clientConten
 			if c.virtualStyle {
 				url.Path = filepath.Join(string(url.Separator), object.Key)
 			}
-			content := &clientContent{}
+			content := &ClientContent{}
 			content.URL = url
 			content.Size = object.Size
 			content.Time = object.Initiated
@@ -923,15 +1768,15 @@ func (c *s3Client) listIncompleteRecursiveInRoutine(contentCh chan *clientConten
 	}
 }
 
-func (c *s3Client) listInRoutine(contentCh chan *clientContent) {
+func (c *s3Client) listInRoutine(contentCh chan *ClientContent) {
 	defer close(contentCh)
 	// get bucket and object from URL.
 	b, o := c.url2BucketAndObject()
 	switch {
 	case b == "" && o == "":
-		buckets, e := c.api.ListBuckets()
+		buckets, e := c.listBuckets()
 		if e != nil {
-			contentCh <- &clientContent{
+			contentCh <- &ClientContent{
 				Err: probe.NewError(e),
 			}
 			return
@@ -939,7 +1784,7 @@ func (c *s3Client) listInRoutine(contentCh chan *clientContent) {
 		for _, bucket := range buckets {
 			url := *c.targetURL
 			url.Path = filepath.Join(url.Path, bucket.Name)
-			content := &clientContent{}
+			content := &ClientContent{}
 			content.URL = url
 			content.Size = 0
 			content.Time = bucket.CreationDate
@@ -947,15 +1792,15 @@ func (c *s3Client) listInRoutine(contentCh chan *clientContent) {
 			contentCh <- content
 		}
 	case b != "" && !strings.HasSuffix(c.targetURL.Path, string(c.targetURL.Separator)) && o == "":
-		buckets, e := c.api.ListBuckets()
+		buckets, e := c.listBuckets()
 		if e != nil {
-			contentCh <- &clientContent{
+			contentCh <- &ClientContent{
 				Err: probe.NewError(e),
 			}
 		}
 		for _, bucket := range buckets {
 			if bucket.Name == b {
-				content := &clientContent{}
+				content := &ClientContent{}
 				content.URL = *c.targetURL
 				content.Size = 0
 				content.Time = bucket.CreationDate
@@ -966,14 +1811,14 @@ func (c *s3Client) listInRoutine(contentCh chan *clientContent) {
 		}
 	default:
 		isRecursive := false
-		for object := range c.listObjectWrapper(b, o, isRecursive, nil) {
+		for object := range c.listObjectWrapper(b, o, isRecursive) {
 			if object.Err != nil {
-				contentCh <- &clientContent{
+				contentCh <- &ClientContent{
 					Err: probe.NewError(object.Err),
 				}
 				return
 			}
-			content := &clientContent{}
+			content := &ClientContent{}
 			url := *c.targetURL
 			// Join bucket and incoming object key.
 			url.Path = filepath.Join(string(url.Separator), b, object.Key)
@@ -991,6 +1836,8 @@ func (c *s3Client) listInRoutine(contentCh chan *clientContent) {
 				content.Size = object.Size
 				content.Time = object.LastModified
 				content.Type = os.FileMode(0664)
+				content.StorageClass = object.StorageClass
+				content.ETag = object.ETag
 			}
 			contentCh <- content
 		}
@@ -1007,60 +1854,35 @@ const (
 	// Reduced redundancy access.
 	// s3StorageClassRedundancy = "REDUCED_REDUNDANCY"
 	// Archive access.
-	s3StorageClassGlacier = "GLACIER"
+	s3StorageClassGlacier     = "GLACIER"
+	s3StorageClassDeepArchive = "DEEP_ARCHIVE"
 )
 
-func (c *s3Client) listRecursiveInRoutine(contentCh chan *clientContent) {
+// isArchived - objects of archive storage classes have to be restored
+// before they can be read.
+func isArchived(storageClass string) bool {
+	return storageClass == s3StorageClassGlacier || storageClass == s3StorageClassDeepArchive
+}
+
+func (c *s3Client) listRecursiveInRoutine(contentCh chan *ClientContent) {
 	defer close(contentCh)
 	// get bucket and object from URL.
 	b, o := c.url2BucketAndObject()
 	switch {
 	case b == "" && o == "":
-		buckets, err := c.api.ListBuckets()
+		buckets, err := c.listBuckets()
 		if err != nil {
-			contentCh <- &clientContent{
+			contentCh <- &ClientContent{
 				Err: probe.NewError(err),
 			}
 			return
 		}
-		for _, bucket := range buckets {
-			bucketURL := *c.targetURL
-			bucketURL.Path = filepath.Join(bucketURL.Path, bucket.Name)
-			contentCh <- &clientContent{
-				URL:  bucketURL,
-				Type: os.ModeDir,
-				Time: bucket.CreationDate,
-			}
-			isRecursive := true
-			for object := range c.listObjectWrapper(bucket.Name, o, isRecursive, nil) {
-				// Return error if we encountered glacier object and continue.
-				if object.StorageClass == s3StorageClassGlacier {
-					contentCh <- &clientContent{
-						Err: probe.NewError(ObjectOnGlacier{object.Key}),
-					}
-					continue
-				}
-				if object.Err != nil {
-					contentCh <- &clientContent{
-						Err: probe.NewError(object.Err),
-					}
-					continue
-				}
-				content := &clientContent{}
-				objectURL := *c.targetURL
-				objectURL.Path = filepath.Join(objectURL.Path, bucket.Name, object.Key)
-				content.URL = objectURL
-				content.Size = object.Size
-				content.Time = object.LastModified
-				content.Type = os.FileMode(0664)
-				contentCh <- content
-			}
-		}
+		c.listBucketsRecursive(buckets, contentCh)
 	default:
 		isRecursive := true
-		for object := range c.listObjectWrapper(b, o, isRecursive, nil) {
+		for object := range c.listObjectWrapper(b, o, isRecursive) {
 			if object.Err != nil {
-				contentCh <- &clientContent{
+				contentCh <- &ClientContent{
 					Err: probe.NewError(object.Err),
 				}
 				continue
@@ -1069,7 +1891,7 @@ func (c *s3Client) listRecursiveInRoutine(contentCh chan *clientContent) {
 			if object.Size == 0 && strings.HasSuffix(object.Key, "/") {
 				continue
 			}
-			content := &clientContent{}
+			content := &ClientContent{}
 			url := *c.targetURL
 			// Join bucket and incoming object key.
 			url.Path = filepath.Join(string(url.Separator), b, object.Key)
@@ -1081,38 +1903,152 @@ func (c *s3Client) listRecursiveInRoutine(contentCh chan *clientContent) {
 			content.Size = object.Size
 			content.Time = object.LastModified
 			content.Type = os.FileMode(0664)
+			content.StorageClass = object.StorageClass
+			content.ETag = object.ETag
 			contentCh <- content
 		}
 	}
 }
 
+// listBucketsRecursive - lists the contents of all buckets, as many
+// buckets at once as configured. Contents of ordered listings are sent
+// bucket by bucket, later buckets are listed ahead up to a limit.
+func (c *s3Client) listBucketsRecursive(buckets []minio.BucketInfo, contentCh chan<- *ClientContent) {
+	opts := c.config.BucketListing
+	if opts.Parallel <= 1 || len(buckets) <= 1 {
+		for _, bucket := range buckets {
+			c.listBucketRecursive(bucket, contentCh)
+		}
+		return
+	}
+
+	// Channels of the buckets in the order of listing, nil for
+	// interleaved listings where all buckets send to contentCh.
+	var bucketChs []chan *ClientContent
+	if !opts.Interleaved {
+		bucketChs = make([]chan *ClientContent, len(buckets))
+		for i := range bucketChs {
+			bucketChs[i] = make(chan *ClientContent, bucketListAhead)
+		}
+	}
+
+	bucketIndexCh := make(chan int)
+	go func() {
+		defer close(bucketIndexCh)
+		for i := range buckets {
+			bucketIndexCh <- i
+		}
+	}()
+
+	var wg sync.WaitGroup
+	for n := 0; n < opts.Parallel && n < len(buckets); n++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range bucketIndexCh {
+				if bucketChs == nil {
+					c.listBucketRecursive(buckets[i], contentCh)
+					continue
+				}
+				c.listBucketRecursive(buckets[i], bucketChs[i])
+				close(bucketChs[i])
+			}
+		}()
+	}
+
+	if bucketChs == nil {
+		wg.Wait()
+		return
+	}
+	for _, bucketCh := range bucketChs {
+		for content := range bucketCh {
+			contentCh <- content
+		}
+	}
+	wg.Wait()
+}
+
+// listBucketRecursive - lists the bucket followed by all its objects.
+func (c *s3Client) listBucketRecursive(bucket minio.BucketInfo, contentCh chan<- *ClientContent) {
+	bucketURL := *c.targetURL
+	bucketURL.Path = filepath.Join(bucketURL.Path, bucket.Name)
+	contentCh <- &ClientContent{
+		URL:  bucketURL,
+		Type: os.ModeDir,
+		Time: bucket.CreationDate,
+	}
+	isRecursive := true
+	for object := range c.listObjectWrapper(bucket.Name, "", isRecursive) {
+		if object.Err != nil {
+			contentCh <- &ClientContent{
+				Err: probe.NewError(object.Err),
+			}
+			continue
+		}
+		content := &ClientContent{}
+		objectURL := *c.targetURL
+		objectURL.Path = filepath.Join(objectURL.Path, bucket.Name, object.Key)
+		content.URL = objectURL
+		content.Size = object.Size
+		content.Time = object.LastModified
+		content.Type = os.FileMode(0664)
+		content.StorageClass = object.StorageClass
+		content.ETag = object.ETag
+		contentCh <- content
+	}
+}
+
 // ShareDownload - get a usable presigned object url to share.
-func (c *s3Client) ShareDownload(expires time.Duration) (string, *probe.Error) {
+func (c *s3Client) ShareDownload(expires time.Duration, reqParams url.Values) (string, *probe.Error) {
 	bucket, object := c.url2BucketAndObject()
-	// No additional request parameters are set for the time being.
-	reqParams := make(url.Values)
-	presignedURL, e := c.api.PresignedGetObject(bucket, object, expires, reqParams)
-	if e != nil {
-		return "", probe.NewError(e)
+	if object == "" {
+		return "", probe.NewError(ObjectMissing{})
 	}
-	return presignedURL.String(), nil
+	creds, err := c.presignCredentials()
+	if err != nil {
+		return "", err.Trace(bucket, object)
+	}
+	req, region, err := c.newRequest("GET", s3RequestMetadata{
+		bucketName:  bucket,
+		objectName:  object,
+		queryValues: reqParams,
+	})
+	if err != nil {
+		return "", err.Trace(bucket, object)
+	}
+	if strings.ToUpper(c.config.Signature) == "S3V2" {
+		req.Header = make(http.Header)
+		s3PreSignV2(req, creds, expires)
+	} else {
+		s3PreSignV4(req, creds, region, expires, nil)
+	}
+	return req.URL.String(), nil
 }
 
 // ShareUpload - get data for presigned post http form upload.
-func (c *s3Client) ShareUpload(isRecursive bool, expires time.Duration, contentType string) (map[string]string, *probe.Error) {
+func (c *s3Client) ShareUpload(opts ShareUploadOpts) (map[string]string, *probe.Error) {
 	bucket, object := c.url2BucketAndObject()
+	creds, err := c.presignCredentials()
+	if err != nil {
+		return nil, err.Trace(bucket, object)
+	}
 	p := minio.NewPostPolicy()
-	if e := p.SetExpires(time.Now().UTC().Add(expires)); e != nil {
+	if e := p.SetExpires(time.Now().UTC().Add(opts.Expires)); e != nil {
 		return nil, probe.NewError(e)
 	}
-	if strings.TrimSpace(contentType) != "" || contentType != "" {
+	if strings.TrimSpace(opts.ContentType) != "" || opts.ContentType != "" {
 		// No need to verify for error here, since we have stripped out spaces.
-		p.SetContentType(contentType)
+		p.SetContentType(opts.ContentType)
+	}
+	if opts.MaxSize > 0 {
+		if e := p.SetContentLengthRange(opts.MinSize, opts.MaxSize); e != nil {
+			return nil, probe.NewError(e)
+		}
 	}
 	if e := p.SetBucket(bucket); e != nil {
 		return nil, probe.NewError(e)
 	}
-	if isRecursive {
+	if opts.Recursive {
 		if e := p.SetKeyStartsWith(object); e != nil {
 			return nil, probe.NewError(e)
 		}
@@ -1121,6 +2057,89 @@ func (c *s3Client) ShareUpload(isRecursive bool, expires time.Duration, contentT
 			return nil, probe.NewError(e)
 		}
 	}
-	_, m, e := c.api.PresignedPostPolicy(p)
-	return m, probe.NewError(e)
+	// Keys of the config are signed by minio-go, which does not sign
+	// session tokens.
+	if creds == c.config.credentials() && creds.SessionToken == "" {
+		_, m, e := c.api.PresignedPostPolicy(p)
+		return m, probe.NewError(e)
+	}
+	if strings.ToUpper(c.config.Signature) == "S3V2" {
+		return nil, probe.NewError(APINotImplemented{
+			API:     "Upload forms with temporary credentials",
+			APIType: "signature V2",
+		})
+	}
+	_, region, err := c.newRequest("POST", s3RequestMetadata{bucketName: bucket})
+	if err != nil {
+		return nil, err.Trace(bucket, object)
+	}
+	formData := map[string]string{"bucket": bucket, "key": object}
+	if opts.ContentType != "" {
+		formData["Content-Type"] = opts.ContentType
+	}
+	return s3PresignPostPolicyV4(p.String(), formData, creds, region)
+}
+
+// presignCredentials - current keys URLs and forms are signed with,
+// anonymous hosts cannot share objects.
+func (c *s3Client) presignCredentials() (s3Credentials, *probe.Error) {
+	if c.provider == nil {
+		return s3Credentials{}, errAnonymousPresign(c.targetURL.String())
+	}
+	creds, err := c.provider.Get()
+	if err != nil {
+		return s3Credentials{}, err.Trace(c.targetURL.String())
+	}
+	if creds.AccessKey == "" || creds.SecretKey == "" {
+		return s3Credentials{}, errAnonymousPresign(c.targetURL.String())
+	}
+	return creds, nil
+}
+
+// ShareUploadURL - get a presigned url to upload the object by a PUT
+// request, content type and exact size of the upload are signed.
+func (c *s3Client) ShareUploadURL(opts ShareUploadOpts) (string, *probe.Error) {
+	bucket, object := c.url2BucketAndObject()
+	if object == "" {
+		return "", probe.NewError(ObjectMissing{})
+	}
+	creds, err := c.presignCredentials()
+	if err != nil {
+		return "", err.Trace(bucket, object)
+	}
+	if opts.Recursive || opts.MinSize != opts.MaxSize {
+		return "", probe.NewError(APINotImplemented{
+			API:     "Restricting uploads to a prefix or size range",
+			APIType: "presigned PUT URLs",
+		})
+	}
+	header := make(http.Header)
+	if opts.ContentType != "" {
+		header.Set("Content-Type", opts.ContentType)
+	}
+	if opts.MaxSize > 0 {
+		header.Set("Content-Length", strconv.FormatInt(opts.MaxSize, 10))
+	}
+
+	req, region, err := c.newRequest("PUT", s3RequestMetadata{
+		bucketName: bucket,
+		objectName: object,
+	})
+	if err != nil {
+		return "", err.Trace(bucket, object)
+	}
+	if strings.ToUpper(c.config.Signature) == "S3V2" {
+		// Signature V2 does not cover the content length.
+		if opts.MaxSize > 0 {
+			return "", probe.NewError(APINotImplemented{
+				API:     "Restricting the size of uploads",
+				APIType: "presigned PUT URLs with signature V2",
+			})
+		}
+		req.Header = header
+		s3PreSignV2(req, creds, opts.Expires)
+	} else {
+		s3PreSignV4(req, creds, region, opts.Expires, header)
+	}
+	return req.URL.String(), nil
 }
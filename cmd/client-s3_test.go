@@ -19,12 +19,36 @@ package cmd
 // bucketHandler is an http.Handler that verifies bucket responses and validates incoming requests
 import (
 	"bytes"
+	"context"
+	"crypto/md5"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/binary"
+	"encoding/hex"
+	"encoding/pem"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"hash/crc32"
 	"io"
+	"io/ioutil"
 	"net/http"
 	"net/http/httptest"
+	"net/url"
+	"os"
+	"path/filepath"
+	"runtime"
+	"sort"
 	"strconv"
+	"strings"
+	"sync"
 	"time"
 
+	"github.com/minio/minio/pkg/probe"
+	"github.com/ricoharisin91/minio-go"
 	. "gopkg.in/check.v1"
 )
 
@@ -181,7 +205,7 @@ func (s *TestSuite) TestBucketOperations(c *C) {
 	s3c, err = s3New(conf)
 	c.Assert(err, IsNil)
 
-	for content := range s3c.List(false, false) {
+	for content := range s3c.List(context.Background(), false, false) {
 		c.Assert(content.Err, IsNil)
 		c.Assert(content.Type.IsDir(), Equals, true)
 	}
@@ -190,7 +214,7 @@ func (s *TestSuite) TestBucketOperations(c *C) {
 	s3c, err = s3New(conf)
 	c.Assert(err, IsNil)
 
-	for content := range s3c.List(false, false) {
+	for content := range s3c.List(context.Background(), false, false) {
 		c.Assert(content.Err, IsNil)
 		c.Assert(content.Type.IsDir(), Equals, true)
 	}
@@ -199,7 +223,7 @@ func (s *TestSuite) TestBucketOperations(c *C) {
 	s3c, err = s3New(conf)
 	c.Assert(err, IsNil)
 
-	for content := range s3c.List(false, false) {
+	for content := range s3c.List(context.Background(), false, false) {
 		c.Assert(content.Err, IsNil)
 		c.Assert(content.Type.IsRegular(), Equals, true)
 	}
@@ -224,11 +248,11 @@ func (s *TestSuite) TestObjectOperations(c *C) {
 
 	var reader io.Reader
 	reader = bytes.NewReader(object.data)
-	n, err := s3c.Put(reader, int64(len(object.data)), "application/octet-stream", nil)
+	n, err := s3c.Put(context.Background(), reader, int64(len(object.data)), "application/octet-stream", nil, PutOpts{})
 	c.Assert(err, IsNil)
 	c.Assert(n, Equals, int64(len(object.data)))
 
-	reader, err = s3c.Get()
+	reader, err = s3c.Get(context.Background(), GetOpts{})
 	c.Assert(err, IsNil)
 	var buffer bytes.Buffer
 	{
@@ -237,3 +261,3156 @@ func (s *TestSuite) TestObjectOperations(c *C) {
 		c.Assert(buffer.Bytes(), DeepEquals, object.data)
 	}
 }
+
+// stsHandler is an http.Handler which hands out temporary credentials.
+type stsHandler struct {
+	calls      *int
+	expiration time.Time
+}
+
+func (h stsHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	*h.calls++
+	if r.FormValue("Action") != "AssumeRole" || r.FormValue("RoleArn") == "" {
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+	w.Write([]byte(`<AssumeRoleResponse><AssumeRoleResult><Credentials>` +
+		`<AccessKeyId>ASIAJ` + strconv.Itoa(*h.calls) + `</AccessKeyId>` +
+		`<SecretAccessKey>wJalrXUtnFEMI/K7MDENG/bPxRfiCYzEXAMPLEKEY</SecretAccessKey>` +
+		`<SessionToken>token` + strconv.Itoa(*h.calls) + `</SessionToken>` +
+		`<Expiration>` + h.expiration.Format(time.RFC3339) + `</Expiration>` +
+		`</Credentials></AssumeRoleResult></AssumeRoleResponse>`))
+}
+
+// Test temporary credentials are cached and refreshed before expiry.
+func (s *TestSuite) TestSTSProvider(c *C) {
+	calls := 0
+	handler := stsHandler{calls: &calls, expiration: time.Now().UTC().Add(time.Hour)}
+	server := httptest.NewServer(handler)
+	defer server.Close()
+
+	provider := newSTSProvider("WLGDGYAQYIGI833EV05A", "BYvgJM101sHngl2uzjXS/OBF/aMxAN06JrJ3qJlF",
+		"arn:aws:iam::123456789012:role/mc", "external", http.DefaultTransport)
+	provider.endpoint = server.URL
+
+	creds, err := provider.Get()
+	c.Assert(err, IsNil)
+	c.Assert(creds.AccessKey, Equals, "ASIAJ1")
+	c.Assert(creds.SessionToken, Equals, "token1")
+
+	// Still valid, served from cache.
+	creds, err = provider.Get()
+	c.Assert(err, IsNil)
+	c.Assert(creds.AccessKey, Equals, "ASIAJ1")
+	c.Assert(calls, Equals, 1)
+
+	// About to expire, role is assumed again.
+	provider.expiration = time.Now().UTC().Add(time.Minute)
+	creds, err = provider.Get()
+	c.Assert(err, IsNil)
+	c.Assert(creds.AccessKey, Equals, "ASIAJ2")
+	c.Assert(creds.SessionToken, Equals, "token2")
+	c.Assert(calls, Equals, 2)
+
+	c.Assert(isValidRoleARN("arn:aws:iam::123456789012:role/mc"), Equals, true)
+	c.Assert(isValidRoleARN("arn:aws:iam::role/mc"), Equals, false)
+}
+
+// sseHandler is an http.Handler which requires objects to be read
+// and written with a customer provided key.
+type sseHandler struct {
+	objectHandler
+	keyMD5 string
+}
+
+func (h sseHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.URL.Path == h.resource && (r.Method == "GET" || r.Method == "PUT") {
+		switch r.Header.Get("X-Amz-Server-Side-Encryption-Customer-Key-Md5") {
+		case h.keyMD5:
+		case "":
+			w.WriteHeader(http.StatusBadRequest)
+			w.Write([]byte("<Error><Code>InvalidRequest</Code><Message>The object was stored using a form of Server Side Encryption. The correct parameters must be provided to retrieve the object.</Message></Error>"))
+			return
+		default:
+			w.WriteHeader(http.StatusBadRequest)
+			w.Write([]byte("<Error><Code>InvalidSSECustomerKey</Code><Message>The provided key does not match the key of the object.</Message></Error>"))
+			return
+		}
+	}
+	h.objectHandler.ServeHTTP(w, r)
+}
+
+// Test objects encrypted with customer provided keys.
+func (s *TestSuite) TestObjectEncryption(c *C) {
+	encKeys, err := parseEncryptKeys("s3/bucket=32byteslongsecretkeymustbegiven1,s3/bucket/other=MzJieXRlc2xvbmdzZWNyZXRrZXltdXN0YmVnaXZlbjI=", "s3/archive,s3/kms=mykey")
+	c.Assert(err, IsNil)
+	c.Assert(getEncryptOpts(encKeys, "s3/bucket/object").Key, DeepEquals, []byte("32byteslongsecretkeymustbegiven1"))
+	c.Assert(getEncryptOpts(encKeys, "s3/bucket/other/object").Key, DeepEquals, []byte("32byteslongsecretkeymustbegiven2"))
+	c.Assert(getEncryptOpts(encKeys, "s3/archive/object").Type, Equals, sseS3)
+	c.Assert(getEncryptOpts(encKeys, "s3/kms/object").KMSKeyID, Equals, "mykey")
+	c.Assert(getEncryptOpts(encKeys, "s3/plain/object").isEmpty(), Equals, true)
+	_, err = parseEncryptKeys("s3/bucket=short", "")
+	c.Assert(err, Not(IsNil))
+	_, err = parseEncryptKeys("s3/bucket=32byteslongsecretkeymustbegiven1", "s3/bucket")
+	c.Assert(err, Not(IsNil))
+
+	sse := getEncryptOpts(encKeys, "s3/bucket/object")
+	header := make(http.Header)
+	sse.setGetHeaders(header)
+
+	object := sseHandler{
+		objectHandler: objectHandler{
+			resource: "/bucket/object",
+			data:     []byte("Hello, World"),
+		},
+		keyMD5: header.Get("X-Amz-Server-Side-Encryption-Customer-Key-Md5"),
+	}
+	server := httptest.NewServer(object)
+	defer server.Close()
+
+	conf := new(Config)
+	conf.HostURL = server.URL + object.resource
+	conf.AccessKey = "WLGDGYAQYIGI833EV05A"
+	conf.SecretKey = "BYvgJM101sHngl2uzjXS/OBF/aMxAN06JrJ3qJlF"
+	conf.Signature = "S3v4"
+	s3c, err := s3New(conf)
+	c.Assert(err, IsNil)
+
+	n, err := s3c.Put(context.Background(), bytes.NewReader(object.data), int64(len(object.data)), "application/octet-stream", nil, PutOpts{SSE: sse})
+	c.Assert(err, IsNil)
+	c.Assert(n, Equals, int64(len(object.data)))
+
+	reader, err := s3c.Get(context.Background(), GetOpts{SSE: sse})
+	c.Assert(err, IsNil)
+	var buffer bytes.Buffer
+	_, e := io.Copy(&buffer, reader)
+	c.Assert(e, IsNil)
+	c.Assert(buffer.Bytes(), DeepEquals, object.data)
+
+	_, err = s3c.Get(context.Background(), GetOpts{SSE: getEncryptOpts(encKeys, "s3/bucket/other/object")})
+	c.Assert(err, Not(IsNil))
+	_, ok := err.ToGoError().(ObjectDecryptionFailed)
+	c.Assert(ok, Equals, true)
+
+	// Denied requests are not reported as failures to decrypt.
+	c.Assert(encryptionError(minio.ErrorResponse{Code: "AccessDenied"}, "s3/bucket/object", sse), IsNil)
+}
+
+// versionsHandler is an http.Handler which serves object versions.
+type versionsHandler struct {
+	removed *string
+}
+
+func (h versionsHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	_, isLocation := r.URL.Query()["location"]
+	switch {
+	case isLocation:
+		w.Write([]byte("<LocationConstraint xmlns=\"http://doc.s3.amazonaws.com/2006-03-01\"></LocationConstraint>"))
+	case r.Method == "GET" && r.URL.Path == "/bucket/" && r.URL.Query().Get("prefix") == "object":
+		w.Write([]byte(`<ListVersionsResult><IsTruncated>false</IsTruncated>` +
+			`<Version><Key>object</Key><VersionId>v2</VersionId><IsLatest>false</IsLatest><LastModified>2016-09-01T10:00:00.000Z</LastModified><Size>12</Size></Version>` +
+			`<Version><Key>object1</Key><VersionId>v5</VersionId><IsLatest>true</IsLatest><LastModified>2016-08-01T10:00:00.000Z</LastModified><Size>1</Size></Version>` +
+			`<DeleteMarker><Key>object</Key><VersionId>v3</VersionId><IsLatest>true</IsLatest><LastModified>2016-09-02T10:00:00.000Z</LastModified></DeleteMarker>` +
+			`</ListVersionsResult>`))
+	case r.Method == "GET" && r.URL.Path == "/bucket/object" && r.URL.Query().Get("versionId") == "v2":
+		w.Write([]byte("Hello, World"))
+	case r.Method == "DELETE" && r.URL.Path == "/bucket/object":
+		*h.removed = r.URL.Query().Get("versionId")
+		w.WriteHeader(http.StatusNoContent)
+	default:
+		w.WriteHeader(http.StatusNotFound)
+		w.Write([]byte("<Error><Code>NoSuchVersion</Code><Message>The specified version does not exist.</Message></Error>"))
+	}
+}
+
+// Test listing, reading and removing object versions.
+func (s *TestSuite) TestObjectVersions(c *C) {
+	var removed string
+	server := httptest.NewServer(versionsHandler{removed: &removed})
+	defer server.Close()
+
+	conf := new(Config)
+	conf.HostURL = server.URL + "/bucket/object"
+	conf.AccessKey = "WLGDGYAQYIGI833EV05A"
+	conf.SecretKey = "BYvgJM101sHngl2uzjXS/OBF/aMxAN06JrJ3qJlF"
+	conf.Signature = "S3v4"
+	s3c, err := s3New(conf)
+	c.Assert(err, IsNil)
+
+	var versions []*ClientContent
+	for content := range s3c.ListVersions(false) {
+		c.Assert(content.Err, IsNil)
+		versions = append(versions, content)
+	}
+	// Versions are listed by key, the latest first.
+	c.Assert(len(versions), Equals, 3)
+	c.Assert(versions[0].VersionID, Equals, "v3")
+	c.Assert(versions[0].IsLatest, Equals, true)
+	c.Assert(versions[0].IsDeleteMarker, Equals, true)
+	c.Assert(versions[1].VersionID, Equals, "v2")
+	c.Assert(versions[1].Size, Equals, int64(12))
+	c.Assert(versions[2].VersionID, Equals, "v5")
+
+	reader, err := s3c.GetVersion("v2")
+	c.Assert(err, IsNil)
+	var buffer bytes.Buffer
+	_, e := io.Copy(&buffer, reader)
+	c.Assert(e, IsNil)
+	c.Assert(buffer.String(), Equals, "Hello, World")
+
+	_, err = s3c.GetVersion("v1")
+	c.Assert(err, Not(IsNil))
+	_, ok := err.ToGoError().(ObjectVersionMissing)
+	c.Assert(ok, Equals, true)
+
+	err = s3c.RemoveVersion("v3")
+	c.Assert(err, IsNil)
+	c.Assert(removed, Equals, "v3")
+}
+
+// lifecycleHandler is an http.Handler which stores a lifecycle configuration.
+type lifecycleHandler struct {
+	config *[]byte
+}
+
+func (h lifecycleHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	_, isLocation := r.URL.Query()["location"]
+	_, isLifecycle := r.URL.Query()["lifecycle"]
+	switch {
+	case isLocation:
+		w.Write([]byte("<LocationConstraint xmlns=\"http://doc.s3.amazonaws.com/2006-03-01\"></LocationConstraint>"))
+	case !isLifecycle:
+		w.WriteHeader(http.StatusBadRequest)
+	case r.Method == "PUT":
+		if r.Header.Get("Content-Md5") == "" {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		*h.config, _ = ioutil.ReadAll(r.Body)
+	case r.Method == "DELETE":
+		*h.config = nil
+		w.WriteHeader(http.StatusNoContent)
+	case *h.config == nil:
+		w.WriteHeader(http.StatusNotFound)
+		w.Write([]byte("<Error><Code>NoSuchLifecycleConfiguration</Code><Message>The lifecycle configuration does not exist.</Message></Error>"))
+	default:
+		w.Write(*h.config)
+	}
+}
+
+// Test setting, getting and removing bucket lifecycle.
+func (s *TestSuite) TestBucketLifecycle(c *C) {
+	var config []byte
+	server := httptest.NewServer(lifecycleHandler{config: &config})
+	defer server.Close()
+
+	conf := new(Config)
+	conf.HostURL = server.URL + "/bucket"
+	conf.AccessKey = "WLGDGYAQYIGI833EV05A"
+	conf.SecretKey = "BYvgJM101sHngl2uzjXS/OBF/aMxAN06JrJ3qJlF"
+	conf.Signature = "S3v4"
+	s3c, err := s3New(conf)
+	c.Assert(err, IsNil)
+	s3Clnt := s3c.(*s3Client)
+
+	lifecycle, err := s3Clnt.GetLifecycle()
+	c.Assert(err, IsNil)
+	c.Assert(len(lifecycle.Rules), Equals, 0)
+
+	err = s3Clnt.SetLifecycle(lifecycleConfiguration{Rules: []lifecycleRule{{
+		ID:         "logs",
+		Filter:     lifecycleFilter{Prefix: "logs/"},
+		Status:     "Enabled",
+		Expiration: &lifecycleExpiration{Days: 30},
+	}}})
+	c.Assert(err, IsNil)
+
+	lifecycle, err = s3Clnt.GetLifecycle()
+	c.Assert(err, IsNil)
+	c.Assert(len(lifecycle.Rules), Equals, 1)
+	c.Assert(lifecycle.Rules[0].ID, Equals, "logs")
+	c.Assert(lifecycle.Rules[0].Filter.Prefix, Equals, "logs/")
+	c.Assert(lifecycle.Rules[0].Expiration.Days, Equals, 30)
+
+	// Rules without a filter carry the prefix directly.
+	config = []byte("<LifecycleConfiguration><Rule><ID>old</ID><Prefix>tmp/</Prefix><Status>Enabled</Status><Expiration><Days>1</Days></Expiration></Rule></LifecycleConfiguration>")
+	lifecycle, err = s3Clnt.GetLifecycle()
+	c.Assert(err, IsNil)
+	c.Assert(lifecycle.Rules[0].Filter.Prefix, Equals, "tmp/")
+
+	err = s3Clnt.SetLifecycle(lifecycleConfiguration{})
+	c.Assert(err, IsNil)
+	c.Assert(config, IsNil)
+}
+
+// taggingHandler is an http.Handler which stores tags of an object.
+type taggingHandler struct {
+	tagging *[]byte
+}
+
+func (h taggingHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	_, isLocation := r.URL.Query()["location"]
+	_, isTagging := r.URL.Query()["tagging"]
+	switch {
+	case isLocation:
+		w.Write([]byte("<LocationConstraint xmlns=\"http://doc.s3.amazonaws.com/2006-03-01\"></LocationConstraint>"))
+	case !isTagging || r.URL.Path != "/bucket/object":
+		w.WriteHeader(http.StatusNotFound)
+		w.Write([]byte("<Error><Code>NoSuchKey</Code><Message>The specified key does not exist.</Message></Error>"))
+	case r.Method == "PUT":
+		*h.tagging, _ = ioutil.ReadAll(r.Body)
+	case r.Method == "DELETE":
+		*h.tagging = []byte("<Tagging><TagSet></TagSet></Tagging>")
+		w.WriteHeader(http.StatusNoContent)
+	default:
+		w.Write(*h.tagging)
+	}
+}
+
+// Test setting, getting and removing object tags.
+func (s *TestSuite) TestObjectTagging(c *C) {
+	tagging := []byte("<Tagging><TagSet></TagSet></Tagging>")
+	server := httptest.NewServer(taggingHandler{tagging: &tagging})
+	defer server.Close()
+
+	conf := new(Config)
+	conf.HostURL = server.URL + "/bucket/object"
+	conf.AccessKey = "WLGDGYAQYIGI833EV05A"
+	conf.SecretKey = "BYvgJM101sHngl2uzjXS/OBF/aMxAN06JrJ3qJlF"
+	conf.Signature = "S3v4"
+	s3c, err := s3New(conf)
+	c.Assert(err, IsNil)
+	s3Clnt := s3c.(*s3Client)
+
+	err = s3Clnt.PutObjectTagging(map[string]string{"project": "apollo", "costcenter": "1024"})
+	c.Assert(err, IsNil)
+	c.Assert(string(tagging), Equals, "<Tagging><TagSet><Tag><Key>costcenter</Key><Value>1024</Value></Tag><Tag><Key>project</Key><Value>apollo</Value></Tag></TagSet></Tagging>")
+
+	tags, err := s3Clnt.GetObjectTagging()
+	c.Assert(err, IsNil)
+	c.Assert(tags, DeepEquals, map[string]string{"project": "apollo", "costcenter": "1024"})
+
+	err = s3Clnt.DeleteObjectTagging()
+	c.Assert(err, IsNil)
+	tags, err = s3Clnt.GetObjectTagging()
+	c.Assert(err, IsNil)
+	c.Assert(len(tags), Equals, 0)
+
+	conf.HostURL = server.URL + "/bucket/missing"
+	s3c, err = s3New(conf)
+	c.Assert(err, IsNil)
+	_, err = s3c.(*s3Client).GetObjectTagging()
+	_, ok := err.ToGoError().(ObjectMissing)
+	c.Assert(ok, Equals, true)
+}
+
+// Test parsing of tag arguments.
+func (s *TestSuite) TestParseTags(c *C) {
+	tags, err := parseTags([]string{"project=apollo", "empty=", "url=a=b"})
+	c.Assert(err, IsNil)
+	c.Assert(tags, DeepEquals, map[string]string{"project": "apollo", "empty": "", "url": "a=b"})
+
+	_, err = parseTags([]string{"=value"})
+	c.Assert(err, Not(IsNil))
+	_, err = parseTags([]string{"project=a", "project=b"})
+	c.Assert(err, Not(IsNil))
+}
+
+// selectEvent encodes a select response event stream message.
+func selectEvent(headers [][2]string, payload string) []byte {
+	var hdrs bytes.Buffer
+	for _, header := range headers {
+		hdrs.WriteByte(byte(len(header[0])))
+		hdrs.WriteString(header[0])
+		hdrs.WriteByte(7)
+		binary.Write(&hdrs, binary.BigEndian, uint16(len(header[1])))
+		hdrs.WriteString(header[1])
+	}
+	var msg bytes.Buffer
+	binary.Write(&msg, binary.BigEndian, uint32(16+hdrs.Len()+len(payload)))
+	binary.Write(&msg, binary.BigEndian, uint32(hdrs.Len()))
+	binary.Write(&msg, binary.BigEndian, crc32.ChecksumIEEE(msg.Bytes()))
+	msg.Write(hdrs.Bytes())
+	msg.WriteString(payload)
+	binary.Write(&msg, binary.BigEndian, crc32.ChecksumIEEE(msg.Bytes()))
+	return msg.Bytes()
+}
+
+// selectHandler is an http.Handler which answers select requests.
+type selectHandler struct{}
+
+func (h selectHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	_, isLocation := r.URL.Query()["location"]
+	body, _ := ioutil.ReadAll(r.Body)
+	switch {
+	case isLocation:
+		w.Write([]byte("<LocationConstraint xmlns=\"http://doc.s3.amazonaws.com/2006-03-01\"></LocationConstraint>"))
+	case r.Method != "POST" || r.URL.Query().Get("select-type") != "2" || !bytes.Contains(body, []byte("<CSV><FileHeaderInfo>USE</FileHeaderInfo></CSV>")):
+		w.WriteHeader(http.StatusBadRequest)
+	case r.URL.Path == "/bucket/people.csv":
+		w.Write(selectEvent([][2]string{{":message-type", "event"}, {":event-type", "Records"}}, "alice,30\n"))
+		w.Write(selectEvent([][2]string{{":message-type", "event"}, {":event-type", "Stats"}}, "<Stats></Stats>"))
+		w.Write(selectEvent([][2]string{{":message-type", "event"}, {":event-type", "Records"}}, "bob,42\n"))
+		w.Write(selectEvent([][2]string{{":message-type", "event"}, {":event-type", "End"}}, ""))
+	case r.URL.Path == "/bucket/broken.csv":
+		w.Write(selectEvent([][2]string{{":message-type", "event"}, {":event-type", "Records"}}, "alice,30\n"))
+		w.Write(selectEvent([][2]string{{":message-type", "error"}, {":error-code", "CSVParsingError"}, {":error-message", "Unable to parse line 2."}}, ""))
+	default:
+		w.WriteHeader(http.StatusNotFound)
+		w.Write([]byte("<Error><Code>NoSuchKey</Code><Message>The specified key does not exist.</Message></Error>"))
+	}
+}
+
+// Test querying objects with select.
+func (s *TestSuite) TestSelectObjectContent(c *C) {
+	server := httptest.NewServer(selectHandler{})
+	defer server.Close()
+
+	conf := new(Config)
+	conf.AccessKey = "WLGDGYAQYIGI833EV05A"
+	conf.SecretKey = "BYvgJM101sHngl2uzjXS/OBF/aMxAN06JrJ3qJlF"
+	conf.Signature = "S3v4"
+	opts := SelectObjectOpts{}
+	opts.Input.CSV = &selectCSVInput{FileHeaderInfo: "USE"}
+	opts.Output.CSV = &selectCSVOutput{}
+
+	conf.HostURL = server.URL + "/bucket/people.csv"
+	s3c, err := s3New(conf)
+	c.Assert(err, IsNil)
+	reader, err := s3c.SelectObjectContent("select * from S3Object", EncryptOpts{}, opts)
+	c.Assert(err, IsNil)
+	data, e := ioutil.ReadAll(reader)
+	c.Assert(e, IsNil)
+	c.Assert(string(data), Equals, "alice,30\nbob,42\n")
+	c.Assert(reader.Close(), IsNil)
+
+	conf.HostURL = server.URL + "/bucket/broken.csv"
+	s3c, err = s3New(conf)
+	c.Assert(err, IsNil)
+	reader, err = s3c.SelectObjectContent("select * from S3Object", EncryptOpts{}, opts)
+	c.Assert(err, IsNil)
+	data, e = ioutil.ReadAll(reader)
+	c.Assert(string(data), Equals, "alice,30\n")
+	c.Assert(minio.ToErrorResponse(e).Code, Equals, "CSVParsingError")
+
+	conf.HostURL = server.URL + "/bucket/missing.csv"
+	s3c, err = s3New(conf)
+	c.Assert(err, IsNil)
+	_, err = s3c.SelectObjectContent("select * from S3Object", EncryptOpts{}, opts)
+	_, ok := err.ToGoError().(ObjectMissing)
+	c.Assert(ok, Equals, true)
+}
+
+// Test guessing format and compression of objects to query.
+func (s *TestSuite) TestGuessSelectFormat(c *C) {
+	testCases := []struct {
+		object      string
+		format      string
+		compression string
+	}{
+		{"s3/bucket/people.csv", "csv", "none"},
+		{"s3/bucket/people.CSV.gz", "csv", "gzip"},
+		{"s3/bucket/people.json.bz2", "json", "bzip2"},
+		{"s3/bucket/people.parquet", "parquet", "none"},
+		{"s3/bucket/people", "csv", "none"},
+	}
+	for _, testCase := range testCases {
+		format, compression := guessSelectFormat(testCase.object)
+		c.Assert(format, Equals, testCase.format)
+		c.Assert(compression, Equals, testCase.compression)
+	}
+}
+
+// lockHandler is an http.Handler which stores retention and legal hold
+// of an object.
+type lockHandler struct {
+	retention *[]byte
+	legalHold *[]byte
+	bypass    *bool
+}
+
+func (h lockHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	_, isLocation := r.URL.Query()["location"]
+	_, isRetention := r.URL.Query()["retention"]
+	_, isLegalHold := r.URL.Query()["legal-hold"]
+	stored := h.retention
+	if isLegalHold {
+		stored = h.legalHold
+	}
+	switch {
+	case isLocation:
+		w.Write([]byte("<LocationConstraint xmlns=\"http://doc.s3.amazonaws.com/2006-03-01\"></LocationConstraint>"))
+	case !isRetention && !isLegalHold:
+		w.WriteHeader(http.StatusBadRequest)
+	case r.Method == "PUT":
+		*h.bypass = r.Header.Get("X-Amz-Bypass-Governance-Retention") == "true"
+		*stored, _ = ioutil.ReadAll(r.Body)
+	case *stored == nil:
+		w.WriteHeader(http.StatusNotFound)
+		w.Write([]byte("<Error><Code>NoSuchObjectLockConfiguration</Code><Message>The specified object does not have a ObjectLock configuration.</Message></Error>"))
+	default:
+		w.Write(*stored)
+	}
+}
+
+// Test setting and getting retention and legal hold of objects.
+func (s *TestSuite) TestObjectLock(c *C) {
+	var retention, legalHold []byte
+	var bypass bool
+	server := httptest.NewServer(lockHandler{retention: &retention, legalHold: &legalHold, bypass: &bypass})
+	defer server.Close()
+
+	conf := new(Config)
+	conf.HostURL = server.URL + "/bucket/object"
+	conf.AccessKey = "WLGDGYAQYIGI833EV05A"
+	conf.SecretKey = "BYvgJM101sHngl2uzjXS/OBF/aMxAN06JrJ3qJlF"
+	conf.Signature = "S3v4"
+	s3c, err := s3New(conf)
+	c.Assert(err, IsNil)
+	s3Clnt := s3c.(*s3Client)
+
+	r, err := s3Clnt.GetObjectRetention("")
+	c.Assert(err, IsNil)
+	c.Assert(r.Mode, Equals, "")
+	status, err := s3Clnt.GetObjectLegalHold("")
+	c.Assert(err, IsNil)
+	c.Assert(status, Equals, legalHoldOff)
+
+	until := time.Date(2017, 12, 31, 0, 0, 0, 0, time.UTC)
+	err = s3Clnt.PutObjectRetention("", objectRetention{Mode: retentionGovernance, RetainUntilDate: &until}, false)
+	c.Assert(err, IsNil)
+	c.Assert(bypass, Equals, false)
+	r, err = s3Clnt.GetObjectRetention("")
+	c.Assert(err, IsNil)
+	c.Assert(r.Mode, Equals, retentionGovernance)
+	c.Assert(r.RetainUntilDate.Equal(until), Equals, true)
+
+	err = s3Clnt.PutObjectRetention("", objectRetention{}, true)
+	c.Assert(err, IsNil)
+	c.Assert(bypass, Equals, true)
+	c.Assert(string(retention), Equals, "<Retention></Retention>")
+
+	err = s3Clnt.PutObjectLegalHold("", legalHoldOn)
+	c.Assert(err, IsNil)
+	status, err = s3Clnt.GetObjectLegalHold("")
+	c.Assert(err, IsNil)
+	c.Assert(status, Equals, legalHoldOn)
+}
+
+// Test computing the date until which objects are retained.
+func (s *TestSuite) TestRetentionUntil(c *C) {
+	now := time.Date(2016, 10, 1, 12, 0, 0, 0, time.UTC)
+
+	until, err := retentionUntil(30, "", now)
+	c.Assert(err, IsNil)
+	c.Assert(until, Equals, time.Date(2016, 10, 31, 12, 0, 0, 0, time.UTC))
+
+	until, err = retentionUntil(0, "2017-01-01", now)
+	c.Assert(err, IsNil)
+	c.Assert(until, Equals, time.Date(2017, 1, 1, 0, 0, 0, 0, time.UTC))
+
+	_, err = retentionUntil(0, "", now)
+	c.Assert(err, Not(IsNil))
+	_, err = retentionUntil(30, "2017-01-01", now)
+	c.Assert(err, Not(IsNil))
+	_, err = retentionUntil(0, "2016-01-01", now)
+	c.Assert(err, Not(IsNil))
+	_, err = retentionUntil(-1, "", now)
+	c.Assert(err, Not(IsNil))
+}
+
+// multipartHandler is an http.Handler which assembles multipart uploads.
+type multipartHandler struct {
+	mutex  *sync.Mutex
+	parts  map[int][]byte
+	object *[]byte
+
+	// Part failing once, zero for none.
+	failPart *int
+	// Number of uploaded parts.
+	uploaded *int
+}
+
+func (h multipartHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	_, isLocation := r.URL.Query()["location"]
+	_, isUploads := r.URL.Query()["uploads"]
+	uploadID := r.URL.Query().Get("uploadId")
+	switch {
+	case isLocation:
+		w.Write([]byte("<LocationConstraint xmlns=\"http://doc.s3.amazonaws.com/2006-03-01\"></LocationConstraint>"))
+	case r.Method == "POST" && isUploads:
+		w.Write([]byte("<InitiateMultipartUploadResult><Bucket>bucket</Bucket><Key>object</Key><UploadId>upload1</UploadId></InitiateMultipartUploadResult>"))
+	case uploadID != "upload1":
+		w.WriteHeader(http.StatusNotFound)
+		w.Write([]byte("<Error><Code>NoSuchUpload</Code><Message>The specified upload does not exist.</Message></Error>"))
+	case r.Method == "GET":
+		w.Write([]byte("<ListPartsResult><UploadId>upload1</UploadId></ListPartsResult>"))
+	case r.Method == "PUT":
+		partNumber, _ := strconv.Atoi(r.URL.Query().Get("partNumber"))
+		data, _ := ioutil.ReadAll(r.Body)
+		h.mutex.Lock()
+		defer h.mutex.Unlock()
+		if h.failPart != nil && *h.failPart == partNumber {
+			*h.failPart = 0
+			w.WriteHeader(http.StatusInternalServerError)
+			w.Write([]byte("<Error><Code>InternalError</Code><Message>We encountered an internal error, please try again.</Message></Error>"))
+			return
+		}
+		if h.uploaded != nil {
+			*h.uploaded++
+		}
+		h.parts[partNumber] = data
+		w.Header().Set("ETag", "\"etag-"+strconv.Itoa(partNumber)+"\"")
+	case r.Method == "POST":
+		complete := completeMultipartUpload{}
+		if e := xml.NewDecoder(r.Body).Decode(&complete); e != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		h.mutex.Lock()
+		defer h.mutex.Unlock()
+		*h.object = nil
+		for i, part := range complete.Parts {
+			if part.PartNumber != i+1 || part.ETag != "\"etag-"+strconv.Itoa(i+1)+"\"" {
+				w.Write([]byte("<Error><Code>InvalidPartOrder</Code><Message>The list of parts was not in ascending order.</Message></Error>"))
+				return
+			}
+			*h.object = append(*h.object, h.parts[part.PartNumber]...)
+		}
+		w.Write([]byte("<CompleteMultipartUploadResult><Key>object</Key></CompleteMultipartUploadResult>"))
+	default:
+		w.WriteHeader(http.StatusNoContent)
+	}
+}
+
+// Test uploading parts of an object in parallel.
+func (s *TestSuite) TestConcurrentMultipartUpload(c *C) {
+	var object []byte
+	server := httptest.NewServer(multipartHandler{mutex: &sync.Mutex{}, parts: make(map[int][]byte), object: &object})
+	defer server.Close()
+
+	conf := new(Config)
+	conf.HostURL = server.URL + "/bucket/object"
+	conf.AccessKey = "WLGDGYAQYIGI833EV05A"
+	conf.SecretKey = "BYvgJM101sHngl2uzjXS/OBF/aMxAN06JrJ3qJlF"
+	conf.Signature = "S3v4"
+	s3c, err := s3New(conf)
+	c.Assert(err, IsNil)
+	s3Clnt := s3c.(*s3Client)
+
+	data := []byte("The quick brown fox jumps over the lazy dog.")
+	for _, size := range []int64{int64(len(data)), -1} {
+		n, err := s3Clnt.putObjectMultipart("bucket", "object", bytes.NewReader(data), size, nil, nil, nil, 5, PutOpts{ConcurrentParts: 3})
+		c.Assert(err, IsNil)
+		c.Assert(n, Equals, int64(len(data)))
+		c.Assert(object, DeepEquals, data)
+	}
+
+	_, err = s3Clnt.putObjectMultipart("bucket", "object", bytes.NewReader(data), 100, nil, nil, nil, 5, PutOpts{ConcurrentParts: 3})
+	c.Assert(err, Not(IsNil))
+	_, ok := err.ToGoError().(UnexpectedEOF)
+	c.Assert(ok, Equals, true)
+
+	// Parts are uploaded one at a time while only one fits the
+	// memory limit, all buffers are returned once uploaded.
+	globalBufferPool = newBufferPool(8)
+	defer func() { globalBufferPool = nil }()
+	n, err := s3Clnt.putObjectMultipart("bucket", "object", bytes.NewReader(data), int64(len(data)), nil, nil, nil, 5, PutOpts{ConcurrentParts: 3})
+	c.Assert(err, IsNil)
+	c.Assert(n, Equals, int64(len(data)))
+	c.Assert(object, DeepEquals, data)
+	c.Assert(globalBufferPool.used, Equals, int64(0))
+
+	// Streams of unknown size fail beyond the maximum number of parts.
+	stream := bytes.Repeat([]byte("x"), s3MaxPartsCount+1)
+	_, err = s3Clnt.putObjectMultipart("bucket", "object", bytes.NewReader(stream), -1, nil, nil, nil, 1, PutOpts{ConcurrentParts: 16})
+	c.Assert(err, Not(IsNil))
+	c.Assert(strings.Contains(err.ToGoError().Error(), "maximum of 10000 parts"), Equals, true)
+}
+
+// Test part size selection and parsing of upload options.
+func (s *TestSuite) TestPutOpts(c *C) {
+	partSize, err := optimalPartSize(-1, 0)
+	c.Assert(err, IsNil)
+	c.Assert(partSize, Equals, int64(s3MinPartSize))
+	partSize, err = optimalPartSize(1024, 16*1024*1024)
+	c.Assert(err, IsNil)
+	c.Assert(partSize, Equals, int64(16*1024*1024))
+	// Part size grows in steps of the requested size to fit in 10000 parts.
+	partSize, err = optimalPartSize(200*1024*1024*1024, 16*1024*1024)
+	c.Assert(err, IsNil)
+	c.Assert(partSize, Equals, int64(32*1024*1024))
+	// Parts are at most 5GiB, larger objects do not fit.
+	partSize, err = optimalPartSize(s3PartSizeUpperLimit*s3MaxPartsCount-1, 3*1024*1024*1024)
+	c.Assert(err, IsNil)
+	c.Assert(partSize, Equals, int64(s3PartSizeUpperLimit))
+	_, err = optimalPartSize(s3PartSizeUpperLimit*s3MaxPartsCount+1, 0)
+	c.Assert(err, NotNil)
+
+	opts, err := parsePutOpts("128MiB", 4)
+	c.Assert(err, IsNil)
+	c.Assert(opts.PartSize, Equals, int64(128*1024*1024))
+	c.Assert(opts.ConcurrentParts, Equals, 4)
+
+	opts, err = parsePutOpts("", 0)
+	c.Assert(err, IsNil)
+	c.Assert(opts, DeepEquals, PutOpts{})
+
+	_, err = parsePutOpts("1MiB", 0)
+	c.Assert(err, Not(IsNil))
+	_, err = parsePutOpts("6GiB", 0)
+	c.Assert(err, Not(IsNil))
+	_, err = parsePutOpts("lots", 0)
+	c.Assert(err, Not(IsNil))
+	_, err = parsePutOpts("", -1)
+	c.Assert(err, Not(IsNil))
+}
+
+// Test parsing the number of parallel transfers.
+func (s *TestSuite) TestParseParallel(c *C) {
+	parallel, err := parseParallel(0)
+	c.Assert(err, IsNil)
+	c.Assert(parallel, Equals, 1)
+	parallel, err = parseParallel(8)
+	c.Assert(err, IsNil)
+	c.Assert(parallel, Equals, 8)
+	_, err = parseParallel(-1)
+	c.Assert(err, Not(IsNil))
+}
+
+// Test resuming an interrupted multipart upload.
+func (s *TestSuite) TestResumeMultipartUpload(c *C) {
+	var object []byte
+	failPart, uploaded := 3, 0
+	server := httptest.NewServer(multipartHandler{
+		mutex:    &sync.Mutex{},
+		parts:    make(map[int][]byte),
+		object:   &object,
+		failPart: &failPart,
+		uploaded: &uploaded,
+	})
+	defer server.Close()
+
+	conf := new(Config)
+	conf.HostURL = server.URL + "/bucket/object"
+	conf.AccessKey = "WLGDGYAQYIGI833EV05A"
+	conf.SecretKey = "BYvgJM101sHngl2uzjXS/OBF/aMxAN06JrJ3qJlF"
+	conf.Signature = "S3v4"
+	s3c, err := s3New(conf)
+	c.Assert(err, IsNil)
+	s3Clnt := s3c.(*s3Client)
+
+	data := []byte("The quick brown fox jumps over the lazy dog.")
+	size := int64(len(data))
+	opts := PutOpts{ResumeID: "source/fox.txt:" + strconv.FormatInt(time.Now().UnixNano(), 10)}
+	key := s3Clnt.uploadStateKey("bucket", "object", opts.ResumeID)
+	defer removeUploadState(key)
+
+	_, err = s3Clnt.putObjectMultipart("bucket", "object", bytes.NewReader(data), size, nil, nil, nil, 5, opts)
+	c.Assert(err, Not(IsNil))
+	c.Assert(uploaded, Equals, 2)
+	state, err := loadUploadState(key)
+	c.Assert(err, IsNil)
+	c.Assert(state.UploadID, Equals, "upload1")
+	c.Assert(len(state.completedParts()), Equals, 2)
+
+	// Resumed upload skips the parts uploaded earlier.
+	n, err := s3Clnt.putObjectMultipart("bucket", "object", bytes.NewReader(data), size, nil, nil, nil, 5, opts)
+	c.Assert(err, IsNil)
+	c.Assert(n, Equals, size)
+	c.Assert(object, DeepEquals, data)
+	c.Assert(uploaded, Equals, 9)
+
+	state, err = loadUploadState(key)
+	c.Assert(err, IsNil)
+	c.Assert(state, IsNil)
+}
+
+// rangeHandler - serves ranged reads of an object.
+type rangeHandler struct {
+	data []byte
+}
+
+func (h rangeHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if _, ok := r.URL.Query()["location"]; ok {
+		w.Write([]byte("<LocationConstraint xmlns=\"http://doc.s3.amazonaws.com/2006-03-01\"></LocationConstraint>"))
+		return
+	}
+	var start, end int64
+	if _, e := fmt.Sscanf(r.Header.Get("Range"), "bytes=%d-%d", &start, &end); e != nil {
+		if _, e = fmt.Sscanf(r.Header.Get("Range"), "bytes=%d-", &start); e != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		end = int64(len(h.data)) - 1
+	}
+	if start >= int64(len(h.data)) {
+		w.WriteHeader(http.StatusRequestedRangeNotSatisfiable)
+		w.Write([]byte("<Error><Code>InvalidRange</Code><Message>The requested range is not satisfiable</Message></Error>"))
+		return
+	}
+	if end >= int64(len(h.data)) {
+		end = int64(len(h.data)) - 1
+	}
+	w.Header().Set("Content-Length", strconv.FormatInt(end-start+1, 10))
+	w.WriteHeader(http.StatusPartialContent)
+	w.Write(h.data[start : end+1])
+}
+
+// Test range reads of objects.
+func (s *TestSuite) TestGetObjectRange(c *C) {
+	server := httptest.NewServer(rangeHandler{data: []byte("hello world")})
+	defer server.Close()
+
+	conf := new(Config)
+	conf.HostURL = server.URL + "/bucket/object"
+	conf.AccessKey = "WLGDGYAQYIGI833EV05A"
+	conf.SecretKey = "BYvgJM101sHngl2uzjXS/OBF/aMxAN06JrJ3qJlF"
+	conf.Signature = "S3v4"
+	s3c, err := s3New(conf)
+	c.Assert(err, IsNil)
+
+	reader, err := s3c.Get(context.Background(), GetOpts{Offset: 6, Length: 3})
+	c.Assert(err, IsNil)
+	data, e := ioutil.ReadAll(reader)
+	c.Assert(e, IsNil)
+	c.Assert(string(data), Equals, "wor")
+
+	reader, err = s3c.Get(context.Background(), GetOpts{Offset: 6})
+	c.Assert(err, IsNil)
+	data, e = ioutil.ReadAll(reader)
+	c.Assert(e, IsNil)
+	c.Assert(string(data), Equals, "world")
+
+	_, err = s3c.Get(context.Background(), GetOpts{Offset: 20})
+	c.Assert(err, Not(IsNil))
+	_, ok := err.ToGoError().(ObjectRangeInvalid)
+	c.Assert(ok, Equals, true)
+}
+
+// Test reading objects at offsets, e.g. to resume downloads.
+func (s *TestSuite) TestObjectReaderReadAt(c *C) {
+	data := []byte("0123456789")
+	var mutex sync.Mutex
+	var gets int
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if len(r.URL.Query()["location"]) == 1 {
+			w.Write([]byte("<LocationConstraint xmlns=\"http://doc.s3.amazonaws.com/2006-03-01\"></LocationConstraint>"))
+			return
+		}
+		mutex.Lock()
+		gets++
+		mutex.Unlock()
+		http.ServeContent(w, r, "object", time.Time{}, bytes.NewReader(data))
+	}))
+	defer server.Close()
+
+	conf := new(Config)
+	conf.HostURL = server.URL + "/bucket/object"
+	conf.AccessKey = "WLGDGYAQYIGI833EV05A"
+	conf.SecretKey = "BYvgJM101sHngl2uzjXS/OBF/aMxAN06JrJ3qJlF"
+	conf.Signature = "S3v4"
+	conf.Lookup = "path"
+	s3c, err := s3New(conf)
+	c.Assert(err, IsNil)
+
+	reader, err := s3c.Get(context.Background(), GetOpts{})
+	c.Assert(err, IsNil)
+	readerAt, ok := reader.(io.ReaderAt)
+	c.Assert(ok, Equals, true)
+	// Nothing is requested before reading.
+	c.Assert(gets, Equals, 0)
+
+	buf := make([]byte, 4)
+	n, e := readerAt.ReadAt(buf, 2)
+	c.Assert(e, IsNil)
+	c.Assert(string(buf[:n]), Equals, "2345")
+	n, e = readerAt.ReadAt(buf, 8)
+	c.Assert(e, Equals, io.EOF)
+	c.Assert(string(buf[:n]), Equals, "89")
+	n, e = readerAt.ReadAt(buf, 10)
+	c.Assert(e, Equals, io.EOF)
+	c.Assert(n, Equals, 0)
+
+	got, e := ioutil.ReadAll(reader)
+	c.Assert(e, IsNil)
+	c.Assert(got, DeepEquals, data)
+	c.Assert(reader.(io.Closer).Close(), IsNil)
+	c.Assert(gets, Equals, 4)
+}
+
+// Test cancelling requests of operations along with their context.
+func (s *TestSuite) TestClientContext(c *C) {
+	releaseCh := make(chan struct{})
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		<-releaseCh
+	}))
+	defer server.Close()
+	defer close(releaseCh)
+
+	conf := new(Config)
+	conf.HostURL = server.URL + "/bucket/object"
+	conf.AccessKey = "WLGDGYAQYIGI833EV05A"
+	conf.SecretKey = "BYvgJM101sHngl2uzjXS/OBF/aMxAN06JrJ3qJlF"
+	conf.Signature = "S3v4"
+	conf.Lookup = "path"
+	s3c, err := s3New(conf)
+	c.Assert(err, IsNil)
+
+	// Hanging bucket location lookups are cancelled as well.
+	operations := []func(ctx context.Context) *probe.Error{
+		func(ctx context.Context) *probe.Error {
+			_, err := s3c.Put(ctx, bytes.NewReader([]byte("data")), 4, "", nil, PutOpts{})
+			return err
+		},
+		func(ctx context.Context) *probe.Error {
+			_, err := s3c.Put(ctx, bytes.NewReader([]byte("data")), 4, "", nil, PutOpts{Checksum: true})
+			return err
+		},
+		func(ctx context.Context) *probe.Error {
+			for content := range s3c.List(ctx, true, false) {
+				if content.Err != nil {
+					return content.Err
+				}
+			}
+			return nil
+		},
+		func(ctx context.Context) *probe.Error {
+			_, err := s3c.Get(ctx, GetOpts{Offset: 1})
+			return err
+		},
+		func(ctx context.Context) *probe.Error {
+			// The object is requested on the first read.
+			reader, err := s3c.Get(ctx, GetOpts{})
+			if err != nil {
+				return err
+			}
+			if _, e := ioutil.ReadAll(reader); e != nil {
+				return probe.NewError(e)
+			}
+			return nil
+		},
+		func(ctx context.Context) *probe.Error {
+			return s3c.Copy(ctx, "bucket/source", 4, nil, CopyOpts{})
+		},
+		func(ctx context.Context) *probe.Error {
+			return s3c.Remove(ctx, false)
+		},
+		func(ctx context.Context) *probe.Error {
+			return s3c.Remove(ctx, true)
+		},
+	}
+	for i, operation := range operations {
+		ctx, cancel := context.WithCancel(context.Background())
+		errCh := make(chan *probe.Error, 1)
+		go func(operation func(ctx context.Context) *probe.Error) {
+			errCh <- operation(ctx)
+		}(operation)
+		time.Sleep(10 * time.Millisecond)
+		cancel()
+		select {
+		case err = <-errCh:
+			c.Assert(err, NotNil, Commentf("operation %d", i))
+		case <-time.After(5 * time.Second):
+			c.Fatalf("Operation %d was not cancelled.", i)
+		}
+	}
+
+	// Contexts are carried by the requests, the host keeps its client.
+	clnt := s3c.(*s3Client)
+	c.Assert(clnt.withContext(context.Background()).api, Equals, clnt.api)
+	c.Assert(clnt.withContext(context.Background()).httpClient, Equals, clnt.httpClient)
+}
+
+// Test parsing of byte ranges and reading leading lines.
+func (s *TestSuite) TestByteRangeAndHeadLines(c *C) {
+	opts, err := parseByteRange("1KiB", "10")
+	c.Assert(err, IsNil)
+	c.Assert(opts.Offset, Equals, int64(1024))
+	c.Assert(opts.Length, Equals, int64(10))
+
+	opts, err = parseByteRange("", "")
+	c.Assert(err, IsNil)
+	c.Assert(opts, DeepEquals, GetOpts{})
+
+	_, err = parseByteRange("", "0")
+	c.Assert(err, Not(IsNil))
+	_, err = parseByteRange("abc", "")
+	c.Assert(err, Not(IsNil))
+
+	reader, err := headLines(bytes.NewReader([]byte("a\nb\nc\n")), 2)
+	c.Assert(err, IsNil)
+	data, e := ioutil.ReadAll(reader)
+	c.Assert(e, IsNil)
+	c.Assert(string(data), Equals, "a\nb\n")
+
+	reader, err = headLines(bytes.NewReader([]byte("a\nb")), 5)
+	c.Assert(err, IsNil)
+	data, e = ioutil.ReadAll(reader)
+	c.Assert(e, IsNil)
+	c.Assert(string(data), Equals, "a\nb")
+}
+
+// copyHandler - serves server side copies of a source with a fixed ETag.
+type copyHandler struct {
+	etag string
+}
+
+func (h copyHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if _, ok := r.URL.Query()["location"]; ok {
+		w.Write([]byte("<LocationConstraint xmlns=\"http://doc.s3.amazonaws.com/2006-03-01\"></LocationConstraint>"))
+		return
+	}
+	if r.Method != "PUT" || r.Header.Get("X-Amz-Copy-Source") == "" {
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+	ifMatch := r.Header.Get("X-Amz-Copy-Source-If-Match")
+	ifNoneMatch := r.Header.Get("X-Amz-Copy-Source-If-None-Match")
+	if (ifMatch != "" && ifMatch != h.etag) || (ifNoneMatch != "" && ifNoneMatch == h.etag) {
+		w.WriteHeader(http.StatusPreconditionFailed)
+		w.Write([]byte("<Error><Code>PreconditionFailed</Code><Message>At least one of the pre-conditions you specified did not hold</Message></Error>"))
+		return
+	}
+	w.Write([]byte("<CopyObjectResult><LastModified>2017-06-01T10:00:00.000Z</LastModified><ETag>\"" + h.etag + "\"</ETag></CopyObjectResult>"))
+}
+
+// Test server side copies with conditions on the source.
+func (s *TestSuite) TestCopyConditions(c *C) {
+	server := httptest.NewServer(copyHandler{etag: "9af2f8218b150c351ad802c6f3d66abe"})
+	defer server.Close()
+
+	conf := new(Config)
+	conf.HostURL = server.URL + "/bucket/object"
+	conf.AccessKey = "WLGDGYAQYIGI833EV05A"
+	conf.SecretKey = "BYvgJM101sHngl2uzjXS/OBF/aMxAN06JrJ3qJlF"
+	conf.Signature = "S3v4"
+	s3c, err := s3New(conf)
+	c.Assert(err, IsNil)
+
+	err = s3c.Copy(context.Background(), "/bucket/source", 0, nil, CopyOpts{IfMatch: "9af2f8218b150c351ad802c6f3d66abe"})
+	c.Assert(err, IsNil)
+
+	err = s3c.Copy(context.Background(), "/bucket/source", 0, nil, CopyOpts{IfMatch: "d41d8cd98f00b204e9800998ecf8427e"})
+	c.Assert(err, Not(IsNil))
+	_, ok := err.ToGoError().(ObjectPreconditionFailed)
+	c.Assert(ok, Equals, true)
+
+	// Conditions are sent along with encryption headers as well.
+	sse := EncryptOpts{Type: sseCustomer, Key: []byte("32byteslongsecretkeymustbegiven1")}
+	err = s3c.Copy(context.Background(), "/bucket/source", 0, nil, CopyOpts{SrcSSE: sse, IfNoneMatch: "9af2f8218b150c351ad802c6f3d66abe"})
+	c.Assert(err, Not(IsNil))
+	_, ok = err.ToGoError().(ObjectPreconditionFailed)
+	c.Assert(ok, Equals, true)
+
+	opts, err := parseCopyOpts("", "", "2017-06-01")
+	c.Assert(err, IsNil)
+	c.Assert(opts.IfUnmodifiedSince.Equal(time.Date(2017, 6, 1, 0, 0, 0, 0, time.UTC)), Equals, true)
+	c.Assert(opts.hasConditions(), Equals, true)
+	opts, err = parseCopyOpts("", "", "2017-06-01T10:00:00Z")
+	c.Assert(err, IsNil)
+	c.Assert(opts.IfUnmodifiedSince.Hour(), Equals, 10)
+	_, err = parseCopyOpts("", "", "yesterday")
+	c.Assert(err, Not(IsNil))
+	opts, err = parseCopyOpts("", "", "")
+	c.Assert(err, IsNil)
+	c.Assert(opts.hasConditions(), Equals, false)
+}
+
+// copyPartHandler - serves multipart copies, recording the copied ranges.
+type copyPartHandler struct {
+	etag      string
+	mutex     *sync.Mutex
+	ranges    *[]string
+	completed *bool
+}
+
+func (h copyPartHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+	switch {
+	case r.Method == "GET" && len(query["location"]) == 1:
+		w.Write([]byte("<LocationConstraint xmlns=\"http://doc.s3.amazonaws.com/2006-03-01\"></LocationConstraint>"))
+	case r.Method == "HEAD":
+		w.Header().Set("Content-Type", "video/mp4")
+		w.Header().Set("ETag", h.etag)
+		w.WriteHeader(http.StatusOK)
+	case r.Method == "POST" && len(query["uploads"]) == 1:
+		if r.Header.Get("Content-Type") != "video/mp4" {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		w.Write([]byte("<InitiateMultipartUploadResult><Bucket>bucket</Bucket><Key>object</Key><UploadId>copy</UploadId></InitiateMultipartUploadResult>"))
+	case r.Method == "PUT" && query.Get("uploadId") == "copy":
+		if r.Header.Get("X-Amz-Copy-Source-If-Match") != h.etag {
+			w.WriteHeader(http.StatusPreconditionFailed)
+			w.Write([]byte("<Error><Code>PreconditionFailed</Code><Message>At least one of the pre-conditions you specified did not hold</Message></Error>"))
+			return
+		}
+		h.mutex.Lock()
+		*h.ranges = append(*h.ranges, r.Header.Get("X-Amz-Copy-Source-Range"))
+		h.mutex.Unlock()
+		w.Write([]byte("<CopyPartResult><LastModified>2017-06-01T10:00:00.000Z</LastModified><ETag>\"etag-" + query.Get("partNumber") + "\"</ETag></CopyPartResult>"))
+	case r.Method == "POST" && query.Get("uploadId") == "copy":
+		h.mutex.Lock()
+		*h.completed = true
+		h.mutex.Unlock()
+		w.Write([]byte("<CompleteMultipartUploadResult><Bucket>bucket</Bucket><Key>object</Key><ETag>\"etag-12\"</ETag></CompleteMultipartUploadResult>"))
+	default:
+		w.WriteHeader(http.StatusBadRequest)
+	}
+}
+
+// Test server side copies of objects larger than a single copy.
+func (s *TestSuite) TestCopyObjectMultipart(c *C) {
+	var ranges []string
+	var completed bool
+	handler := copyPartHandler{
+		etag:      "\"9af2f8218b150c351ad802c6f3d66abe\"",
+		mutex:     &sync.Mutex{},
+		ranges:    &ranges,
+		completed: &completed,
+	}
+	server := httptest.NewServer(handler)
+	defer server.Close()
+
+	conf := new(Config)
+	conf.HostURL = server.URL + "/bucket/object"
+	conf.AccessKey = "WLGDGYAQYIGI833EV05A"
+	conf.SecretKey = "BYvgJM101sHngl2uzjXS/OBF/aMxAN06JrJ3qJlF"
+	conf.Signature = "S3v4"
+	s3c, err := s3New(conf)
+	c.Assert(err, IsNil)
+
+	size := int64(s3MaxCopySize + s3CopyPartSize/2)
+	err = s3c.Copy(context.Background(), "/bucket/source", size, nil, CopyOpts{})
+	c.Assert(err, IsNil)
+	c.Assert(completed, Equals, true)
+	c.Assert(len(ranges), Equals, 11)
+	c.Assert(ranges[0], Equals, "bytes=0-"+strconv.Itoa(s3CopyPartSize-1))
+	c.Assert(ranges[10], Equals, "bytes="+strconv.Itoa(10*s3CopyPartSize)+"-"+strconv.FormatInt(size-1, 10))
+
+	// Parts are copied only while the source keeps its ETag.
+	ranges, completed = nil, false
+	err = s3c.Copy(context.Background(), "/bucket/source", size, nil, CopyOpts{IfMatch: "\"d41d8cd98f00b204e9800998ecf8427e\""})
+	c.Assert(err, Not(IsNil))
+	_, ok := err.ToGoError().(ObjectPreconditionFailed)
+	c.Assert(ok, Equals, true)
+	c.Assert(completed, Equals, false)
+
+	bucket, object := sourceBucketAndObject("/bucket/dir/source")
+	c.Assert(bucket, Equals, "bucket")
+	c.Assert(object, Equals, "dir/source")
+}
+
+// metadataHandler - serves an object with metadata, recording headers
+// of uploads and copies.
+type metadataHandler struct {
+	header *http.Header
+}
+
+func (h metadataHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	switch {
+	case r.Method == "GET" && len(r.URL.Query()["location"]) == 1:
+		w.Write([]byte("<LocationConstraint xmlns=\"http://doc.s3.amazonaws.com/2006-03-01\"></LocationConstraint>"))
+	case r.Method == "HEAD":
+		w.Header().Set("Content-Type", "text/html")
+		w.Header().Set("Cache-Control", "max-age=3600")
+		w.Header().Set("X-Amz-Meta-Project", "apollo")
+		w.Header().Set("ETag", "\"9af2f8218b150c351ad802c6f3d66abe\"")
+		w.WriteHeader(http.StatusOK)
+	case r.Method == "PUT":
+		*h.header = r.Header
+		ioutil.ReadAll(r.Body)
+		if r.Header.Get("X-Amz-Copy-Source") != "" {
+			w.Write([]byte("<CopyObjectResult><LastModified>2017-06-01T10:00:00.000Z</LastModified><ETag>\"9af2f8218b150c351ad802c6f3d66abe\"</ETag></CopyObjectResult>"))
+			return
+		}
+		w.Header().Set("ETag", "\"9af2f8218b150c351ad802c6f3d66abe\"")
+	default:
+		w.WriteHeader(http.StatusBadRequest)
+	}
+}
+
+// Test metadata of uploads and copies.
+func (s *TestSuite) TestObjectMetadata(c *C) {
+	var header http.Header
+	server := httptest.NewServer(metadataHandler{header: &header})
+	defer server.Close()
+
+	conf := new(Config)
+	conf.HostURL = server.URL + "/bucket/object"
+	conf.AccessKey = "WLGDGYAQYIGI833EV05A"
+	conf.SecretKey = "BYvgJM101sHngl2uzjXS/OBF/aMxAN06JrJ3qJlF"
+	conf.Signature = "S3v4"
+	s3c, err := s3New(conf)
+	c.Assert(err, IsNil)
+	s3Clnt := s3c.(*s3Client)
+
+	metadata, err := s3Clnt.GetMetadata(EncryptOpts{})
+	c.Assert(err, IsNil)
+	c.Assert(metadata, DeepEquals, map[string]string{
+		"Content-Type":       "text/html",
+		"Cache-Control":      "max-age=3600",
+		"X-Amz-Meta-Project": "apollo",
+	})
+
+	// Metadata of the source is retained, given entries replace it.
+	attrs, err := parseAttr("project=phoenix;Content-Disposition=attachment")
+	c.Assert(err, IsNil)
+	err = s3c.Copy(context.Background(), "/bucket/source", 5, nil, CopyOpts{Metadata: attrs})
+	c.Assert(err, IsNil)
+	c.Assert(header.Get("X-Amz-Metadata-Directive"), Equals, "REPLACE")
+	c.Assert(header.Get("X-Amz-Meta-Project"), Equals, "phoenix")
+	c.Assert(header.Get("Content-Disposition"), Equals, "attachment")
+	c.Assert(header.Get("Cache-Control"), Equals, "max-age=3600")
+	c.Assert(header.Get("Content-Type"), Equals, "text/html")
+
+	n, err := s3c.Put(context.Background(), bytes.NewReader([]byte("hello")), 5, "text/plain", nil, PutOpts{Metadata: map[string]string{"Cache-Control": "no-cache"}})
+	c.Assert(err, IsNil)
+	c.Assert(n, Equals, int64(5))
+	c.Assert(header.Get("Cache-Control"), Equals, "no-cache")
+	c.Assert(header.Get("Content-Type"), Equals, "text/plain")
+}
+
+// Test parsing of ‘--attr’.
+func (s *TestSuite) TestParseAttr(c *C) {
+	attrs, err := parseAttr("Cache-Control=max-age=0, no-cache; color=blue;x-amz-meta-size=large;")
+	c.Assert(err, IsNil)
+	c.Assert(attrs, DeepEquals, map[string]string{
+		"Cache-Control":    "max-age=0, no-cache",
+		"X-Amz-Meta-Color": "blue",
+		"X-Amz-Meta-Size":  "large",
+	})
+
+	attrs, err = parseAttr("")
+	c.Assert(err, IsNil)
+	c.Assert(len(attrs), Equals, 0)
+
+	_, err = parseAttr("color")
+	c.Assert(err, Not(IsNil))
+	_, err = parseAttr("=blue")
+	c.Assert(err, Not(IsNil))
+}
+
+// restoreHandler - serves an archived object, restores complete on the
+// second status request.
+type restoreHandler struct {
+	mutex   *sync.Mutex
+	request *restoreRequest
+	heads   *int
+}
+
+func (h restoreHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	h.mutex.Lock()
+	defer h.mutex.Unlock()
+	switch {
+	case r.Method == "GET" && len(r.URL.Query()["location"]) == 1:
+		w.Write([]byte("<LocationConstraint xmlns=\"http://doc.s3.amazonaws.com/2006-03-01\"></LocationConstraint>"))
+	case r.Method == "POST" && len(r.URL.Query()["restore"]) == 1:
+		if h.request.Days > 0 {
+			w.WriteHeader(http.StatusConflict)
+			w.Write([]byte("<Error><Code>RestoreAlreadyInProgress</Code><Message>Object restore is already in progress</Message></Error>"))
+			return
+		}
+		if e := xml.NewDecoder(r.Body).Decode(h.request); e != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		w.WriteHeader(http.StatusAccepted)
+	case r.Method == "HEAD":
+		if h.request.Days > 0 {
+			*h.heads++
+			if *h.heads == 1 {
+				w.Header().Set("X-Amz-Restore", "ongoing-request=\"true\"")
+			} else {
+				w.Header().Set("X-Amz-Restore", "ongoing-request=\"false\", expiry-date=\"Fri, 23 Dec 2016 00:00:00 GMT\"")
+			}
+		}
+		w.WriteHeader(http.StatusOK)
+	case r.Method == "GET":
+		w.WriteHeader(http.StatusForbidden)
+		w.Write([]byte("<Error><Code>InvalidObjectState</Code><Message>The operation is not valid for the object's storage class</Message></Error>"))
+	default:
+		w.WriteHeader(http.StatusBadRequest)
+	}
+}
+
+// Test restore of archived objects.
+func (s *TestSuite) TestObjectRestore(c *C) {
+	var request restoreRequest
+	var heads int
+	server := httptest.NewServer(restoreHandler{mutex: &sync.Mutex{}, request: &request, heads: &heads})
+	defer server.Close()
+
+	conf := new(Config)
+	conf.HostURL = server.URL + "/bucket/object"
+	conf.AccessKey = "WLGDGYAQYIGI833EV05A"
+	conf.SecretKey = "BYvgJM101sHngl2uzjXS/OBF/aMxAN06JrJ3qJlF"
+	conf.Signature = "S3v4"
+	s3c, err := s3New(conf)
+	c.Assert(err, IsNil)
+	s3Clnt := s3c.(*s3Client)
+
+	// Archived objects can not be read before they are restored.
+	_, err = s3c.Get(context.Background(), GetOpts{Offset: 1})
+	c.Assert(err, Not(IsNil))
+	_, ok := err.ToGoError().(ObjectOnGlacier)
+	c.Assert(ok, Equals, true)
+
+	status, err := s3Clnt.RestoreStatus(EncryptOpts{})
+	c.Assert(err, IsNil)
+	c.Assert(status.Requested, Equals, false)
+
+	err = s3Clnt.RestoreObject(7, restoreTierBulk)
+	c.Assert(err, IsNil)
+	c.Assert(request.Days, Equals, 7)
+	c.Assert(request.Tier, Equals, restoreTierBulk)
+	// Requesting an ongoing restore again is not an error.
+	err = s3Clnt.RestoreObject(7, restoreTierBulk)
+	c.Assert(err, IsNil)
+
+	status, err = s3Clnt.RestoreStatus(EncryptOpts{})
+	c.Assert(err, IsNil)
+	c.Assert(status.isRestored(), Equals, false)
+	status, err = s3Clnt.RestoreStatus(EncryptOpts{})
+	c.Assert(err, IsNil)
+	c.Assert(status.isRestored(), Equals, true)
+	c.Assert(status.ExpiryDate.Equal(time.Date(2016, 12, 23, 0, 0, 0, 0, time.UTC)), Equals, true)
+}
+
+// Test parsing of restore states and tiers.
+func (s *TestSuite) TestParseRestore(c *C) {
+	c.Assert(parseRestoreHeader(""), DeepEquals, ObjectRestoreStatus{})
+	c.Assert(parseRestoreHeader("ongoing-request=\"true\""), DeepEquals, ObjectRestoreStatus{Requested: true, Ongoing: true})
+	status := parseRestoreHeader("ongoing-request=\"false\", expiry-date=\"Fri, 23 Dec 2016 00:00:00 GMT\"")
+	c.Assert(status.isRestored(), Equals, true)
+	c.Assert(status.ExpiryDate.Day(), Equals, 23)
+
+	tier, ok := restoreTier("bulk")
+	c.Assert(ok, Equals, true)
+	c.Assert(tier, Equals, restoreTierBulk)
+	_, ok = restoreTier("fast")
+	c.Assert(ok, Equals, false)
+
+	c.Assert(isArchived(s3StorageClassGlacier), Equals, true)
+	c.Assert(isArchived("STANDARD"), Equals, false)
+}
+
+// deleteHandler - serves multi-object deletes, removal of ‘locked’
+// objects fails.
+type deleteHandler struct {
+	mutex    *sync.Mutex
+	requests *[]int
+}
+
+func (h deleteHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	switch {
+	case r.Method == "GET" && len(r.URL.Query()["location"]) == 1:
+		w.Write([]byte("<LocationConstraint xmlns=\"http://doc.s3.amazonaws.com/2006-03-01\"></LocationConstraint>"))
+	case r.Method == "POST" && len(r.URL.Query()["delete"]) == 1:
+		if r.Header.Get("Content-Md5") == "" {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		request := deleteRequest{}
+		if e := xml.NewDecoder(r.Body).Decode(&request); e != nil || !request.Quiet {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		h.mutex.Lock()
+		*h.requests = append(*h.requests, len(request.Objects))
+		h.mutex.Unlock()
+		var buf bytes.Buffer
+		buf.WriteString("<DeleteResult>")
+		for _, object := range request.Objects {
+			if object.Key == "locked" && object.VersionID == "" {
+				buf.WriteString("<Error><Key>locked</Key><Code>AccessDenied</Code><Message>Access Denied</Message></Error>")
+			}
+		}
+		buf.WriteString("</DeleteResult>")
+		w.Write(buf.Bytes())
+	default:
+		w.WriteHeader(http.StatusBadRequest)
+	}
+}
+
+// Test removal of objects in bulk with multi-object deletes.
+func (s *TestSuite) TestRemoveObjectsBulk(c *C) {
+	var requests []int
+	server := httptest.NewServer(deleteHandler{mutex: &sync.Mutex{}, requests: &requests})
+	defer server.Close()
+
+	conf := new(Config)
+	conf.HostURL = server.URL + "/bucket/"
+	conf.AccessKey = "WLGDGYAQYIGI833EV05A"
+	conf.SecretKey = "BYvgJM101sHngl2uzjXS/OBF/aMxAN06JrJ3qJlF"
+	conf.Signature = "S3v4"
+	s3c, err := s3New(conf)
+	c.Assert(err, IsNil)
+
+	contentCh := make(chan *ClientContent)
+	go func() {
+		defer close(contentCh)
+		for i := 0; i < 1500; i++ {
+			contentCh <- &ClientContent{URL: *newClientURL(server.URL + "/bucket/dir/object" + strconv.Itoa(i))}
+		}
+		contentCh <- &ClientContent{URL: *newClientURL(server.URL + "/bucket/locked")}
+		contentCh <- &ClientContent{URL: *newClientURL(server.URL + "/bucket/locked"), VersionID: "v1"}
+		contentCh <- &ClientContent{URL: *newClientURL(server.URL + "/other/object")}
+	}()
+
+	var removed int
+	var failed, versions []string
+	for result := range s3c.RemoveBulk(contentCh) {
+		if result.Err != nil {
+			failed = append(failed, result.URL.Path)
+			continue
+		}
+		if result.VersionID != "" {
+			versions = append(versions, result.VersionID)
+		}
+		removed++
+	}
+	c.Assert(removed, Equals, 1501)
+	c.Assert(failed, DeepEquals, []string{"/other/object", "/bucket/locked"})
+	c.Assert(versions, DeepEquals, []string{"v1"})
+	c.Assert(requests, DeepEquals, []int{1000, 502})
+}
+
+// aclHandler is an http.Handler which serves a fixed access control
+// list and records the grants of the last update.
+type aclHandler struct {
+	header *http.Header
+}
+
+func (h aclHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+	switch {
+	case len(query["location"]) == 1:
+		w.Write([]byte("<LocationConstraint xmlns=\"http://doc.s3.amazonaws.com/2006-03-01\"></LocationConstraint>"))
+	case len(query["acl"]) != 1 || r.URL.Path != "/bucket/object":
+		w.WriteHeader(http.StatusNotFound)
+		w.Write([]byte("<Error><Code>NoSuchKey</Code><Message>The specified key does not exist.</Message></Error>"))
+	case r.Method == "PUT":
+		*h.header = r.Header
+	default:
+		w.Write([]byte(`<AccessControlPolicy xmlns="http://s3.amazonaws.com/doc/2006-03-01/">` +
+			`<Owner><ID>owner-id</ID><DisplayName>owner</DisplayName></Owner><AccessControlList>` +
+			`<Grant><Grantee xmlns:xsi="http://www.w3.org/2001/XMLSchema-instance" xsi:type="CanonicalUser"><ID>owner-id</ID><DisplayName>owner</DisplayName></Grantee><Permission>FULL_CONTROL</Permission></Grant>` +
+			`<Grant><Grantee xmlns:xsi="http://www.w3.org/2001/XMLSchema-instance" xsi:type="Group"><URI>http://acs.amazonaws.com/groups/global/AllUsers</URI></Grantee><Permission>READ</Permission></Grant>` +
+			`</AccessControlList></AccessControlPolicy>`))
+	}
+}
+
+// Test getting and setting access control lists.
+func (s *TestSuite) TestObjectACL(c *C) {
+	header := make(http.Header)
+	server := httptest.NewServer(aclHandler{header: &header})
+	defer server.Close()
+
+	conf := new(Config)
+	conf.HostURL = server.URL + "/bucket/object"
+	conf.AccessKey = "WLGDGYAQYIGI833EV05A"
+	conf.SecretKey = "BYvgJM101sHngl2uzjXS/OBF/aMxAN06JrJ3qJlF"
+	conf.Signature = "S3v4"
+	s3c, err := s3New(conf)
+	c.Assert(err, IsNil)
+	s3Clnt := s3c.(*s3Client)
+
+	acl, err := s3Clnt.GetACL()
+	c.Assert(err, IsNil)
+	c.Assert(acl.Owner, Equals, aclOwner{ID: "owner-id", DisplayName: "owner"})
+	c.Assert(acl.Grants, DeepEquals, []aclGrant{
+		{Grantee: aclGrantee{Type: aclGranteeCanonicalUser, ID: "owner-id", DisplayName: "owner"}, Permission: aclPermissionFullControl},
+		{Grantee: aclGrantee{Type: aclGranteeGroup, URI: "http://acs.amazonaws.com/groups/global/AllUsers"}, Permission: aclPermissionRead},
+	})
+
+	err = s3Clnt.SetACL("", []aclGrant{
+		{Grantee: aclGrantee{Type: aclGranteeCanonicalUser, ID: "owner-id"}, Permission: aclPermissionFullControl},
+		{Grantee: aclGrantee{Type: aclGranteeCanonicalUser, ID: "reader-id"}, Permission: aclPermissionRead},
+		{Grantee: aclGrantee{Type: aclGranteeEmail, EmailAddress: "reader@example.com"}, Permission: aclPermissionRead},
+	})
+	c.Assert(err, IsNil)
+	c.Assert(header.Get("X-Amz-Grant-Full-Control"), Equals, `id="owner-id"`)
+	c.Assert(header.Get("X-Amz-Grant-Read"), Equals, `id="reader-id", emailAddress="reader@example.com"`)
+	c.Assert(header.Get("X-Amz-Acl"), Equals, "")
+
+	err = s3Clnt.SetACL("public-read", nil)
+	c.Assert(err, IsNil)
+	c.Assert(header.Get("X-Amz-Acl"), Equals, "public-read")
+	c.Assert(header.Get("X-Amz-Grant-Read"), Equals, "")
+
+	conf.HostURL = server.URL + "/bucket/missing"
+	s3c, err = s3New(conf)
+	c.Assert(err, IsNil)
+	_, err = s3c.(*s3Client).GetACL()
+	_, ok := err.ToGoError().(ObjectMissing)
+	c.Assert(ok, Equals, true)
+}
+
+// Test parsing of grant arguments.
+func (s *TestSuite) TestParseACLGrants(c *C) {
+	grants, err := parseACLGrants([]string{"full-control=id:owner-id", "read=uri:http://acs.amazonaws.com/groups/global/AllUsers", "write-acp=email:admin@example.com"})
+	c.Assert(err, IsNil)
+	c.Assert(grants, DeepEquals, []aclGrant{
+		{Grantee: aclGrantee{Type: aclGranteeCanonicalUser, ID: "owner-id"}, Permission: aclPermissionFullControl},
+		{Grantee: aclGrantee{Type: aclGranteeGroup, URI: "http://acs.amazonaws.com/groups/global/AllUsers"}, Permission: aclPermissionRead},
+		{Grantee: aclGrantee{Type: aclGranteeEmail, EmailAddress: "admin@example.com"}, Permission: aclPermissionWriteACP},
+	})
+
+	for _, grant := range []string{"read", "=id:owner-id", "list=id:owner-id", "read=owner-id", "read=id:", "read=name:owner"} {
+		_, err = parseACLGrants([]string{grant})
+		c.Assert(err, Not(IsNil))
+	}
+
+	_, err = parseCannedACL("public-read")
+	c.Assert(err, IsNil)
+	_, err = parseCannedACL("public")
+	c.Assert(err, Not(IsNil))
+}
+
+// presignedHandler is an http.Handler which records requests made
+// with presigned URLs.
+type presignedHandler struct {
+	request **http.Request
+}
+
+func (h presignedHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if len(r.URL.Query()["location"]) == 1 {
+		w.Write([]byte("<LocationConstraint xmlns=\"http://doc.s3.amazonaws.com/2006-03-01\"></LocationConstraint>"))
+		return
+	}
+	*h.request = r
+}
+
+// Test presigned PUT URLs of shared uploads.
+func (s *TestSuite) TestShareUploadURL(c *C) {
+	var request *http.Request
+	server := httptest.NewServer(presignedHandler{request: &request})
+	defer server.Close()
+
+	conf := new(Config)
+	conf.HostURL = server.URL + "/bucket/object"
+	conf.AccessKey = "WLGDGYAQYIGI833EV05A"
+	conf.SecretKey = "BYvgJM101sHngl2uzjXS/OBF/aMxAN06JrJ3qJlF"
+	conf.Signature = "S3v4"
+	s3c, err := s3New(conf)
+	c.Assert(err, IsNil)
+
+	opts := ShareUploadOpts{Expires: time.Hour, ContentType: "image/png", MinSize: 5, MaxSize: 5}
+	shareURL, err := s3c.ShareUploadURL(opts)
+	c.Assert(err, IsNil)
+	u, e := url.Parse(shareURL)
+	c.Assert(e, IsNil)
+	c.Assert(u.Path, Equals, "/bucket/object")
+	c.Assert(u.Query().Get("X-Amz-Expires"), Equals, "3600")
+	c.Assert(u.Query().Get("X-Amz-SignedHeaders"), Equals, "content-length;content-type;host")
+	c.Assert(strings.HasPrefix(u.Query().Get("X-Amz-Credential"), conf.AccessKey+"/"), Equals, true)
+	c.Assert(u.Query().Get("X-Amz-Signature"), Not(Equals), "")
+
+	req, e := http.NewRequest("PUT", shareURL, strings.NewReader("image"))
+	c.Assert(e, IsNil)
+	req.Header.Set("Content-Type", "image/png")
+	resp, e := http.DefaultClient.Do(req)
+	c.Assert(e, IsNil)
+	resp.Body.Close()
+	c.Assert(request.Method, Equals, "PUT")
+	c.Assert(request.URL.Query().Get("X-Amz-Signature"), Equals, u.Query().Get("X-Amz-Signature"))
+
+	// Prefixes and size ranges can only be restricted by upload forms.
+	_, err = s3c.ShareUploadURL(ShareUploadOpts{Expires: time.Hour, Recursive: true})
+	_, ok := err.ToGoError().(APINotImplemented)
+	c.Assert(ok, Equals, true)
+	_, err = s3c.ShareUploadURL(ShareUploadOpts{Expires: time.Hour, MaxSize: 5})
+	_, ok = err.ToGoError().(APINotImplemented)
+	c.Assert(ok, Equals, true)
+
+	conf.Signature = "S3v2"
+	s3c, err = s3New(conf)
+	c.Assert(err, IsNil)
+	shareURL, err = s3c.ShareUploadURL(ShareUploadOpts{Expires: time.Hour, ContentType: "image/png"})
+	c.Assert(err, IsNil)
+	u, e = url.Parse(shareURL)
+	c.Assert(e, IsNil)
+	c.Assert(u.Query().Get("AWSAccessKeyId"), Equals, conf.AccessKey)
+	c.Assert(u.Query().Get("Expires"), Not(Equals), "")
+	c.Assert(u.Query().Get("Signature"), Not(Equals), "")
+	_, err = s3c.ShareUploadURL(opts)
+	_, ok = err.ToGoError().(APINotImplemented)
+	c.Assert(ok, Equals, true)
+}
+
+// Test presigned download URLs with response header overrides.
+func (s *TestSuite) TestShareDownload(c *C) {
+	var request *http.Request
+	server := httptest.NewServer(presignedHandler{request: &request})
+	defer server.Close()
+
+	conf := new(Config)
+	conf.HostURL = server.URL + "/bucket/object"
+	conf.AccessKey = "WLGDGYAQYIGI833EV05A"
+	conf.SecretKey = "BYvgJM101sHngl2uzjXS/OBF/aMxAN06JrJ3qJlF"
+	conf.Signature = "S3v4"
+	s3c, err := s3New(conf)
+	c.Assert(err, IsNil)
+
+	reqParams, err := parseShareHeaders([]string{"content-disposition=attachment; filename=report.pdf", "Content-Type=application/pdf", "x-id=42"})
+	c.Assert(err, IsNil)
+	shareURL, err := s3c.ShareDownload(time.Hour, reqParams)
+	c.Assert(err, IsNil)
+
+	resp, e := http.Get(shareURL)
+	c.Assert(e, IsNil)
+	resp.Body.Close()
+	query := request.URL.Query()
+	c.Assert(request.URL.Path, Equals, "/bucket/object")
+	c.Assert(query.Get("response-content-disposition"), Equals, "attachment; filename=report.pdf")
+	c.Assert(query.Get("response-content-type"), Equals, "application/pdf")
+	c.Assert(query.Get("x-id"), Equals, "42")
+	c.Assert(query.Get("X-Amz-SignedHeaders"), Equals, "host")
+	c.Assert(query.Get("X-Amz-Signature"), Not(Equals), "")
+
+	_, err = parseShareHeaders([]string{"=inline"})
+	c.Assert(err, Not(IsNil))
+	_, err = parseShareHeaders([]string{"Content-Type"})
+	c.Assert(err, Not(IsNil))
+}
+
+// anonymousHandler is an http.Handler which serves a public object and
+// denies all signed requests.
+type anonymousHandler struct{}
+
+func (h anonymousHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Header.Get("Authorization") != "" || r.URL.Query().Get("X-Amz-Signature") != "" {
+		w.WriteHeader(http.StatusForbidden)
+		w.Write([]byte("<Error><Code>AccessDenied</Code><Message>Access Denied</Message></Error>"))
+		return
+	}
+	switch {
+	case len(r.URL.Query()["location"]) == 1:
+		w.Write([]byte("<LocationConstraint xmlns=\"http://doc.s3.amazonaws.com/2006-03-01\"></LocationConstraint>"))
+	case len(r.URL.Query()["tagging"]) == 1:
+		w.Write([]byte("<Tagging><TagSet><Tag><Key>public</Key><Value>true</Value></Tag></TagSet></Tagging>"))
+	default:
+		w.Header().Set("Content-Length", "6")
+		w.Write([]byte("public"))
+	}
+}
+
+// Test unsigned requests of clients without keys.
+func (s *TestSuite) TestAnonymousClient(c *C) {
+	server := httptest.NewServer(anonymousHandler{})
+	defer server.Close()
+
+	for _, signature := range []string{"S3v4", "S3v2"} {
+		conf := new(Config)
+		conf.HostURL = server.URL + "/bucket/object"
+		conf.Signature = signature
+		c.Assert(conf.isAnonymous(), Equals, true)
+		s3c, err := s3New(conf)
+		c.Assert(err, IsNil)
+
+		reader, err := s3c.Get(context.Background(), GetOpts{})
+		c.Assert(err, IsNil)
+		data, e := ioutil.ReadAll(reader)
+		c.Assert(e, IsNil)
+		c.Assert(string(data), Equals, "public")
+
+		tags, err := s3c.(*s3Client).GetObjectTagging()
+		c.Assert(err, IsNil)
+		c.Assert(tags, DeepEquals, map[string]string{"public": "true"})
+
+		// Shared URLs can not be presigned without keys.
+		_, err = s3c.ShareDownload(time.Hour, nil)
+		c.Assert(err, Not(IsNil))
+		_, err = s3c.ShareUploadURL(ShareUploadOpts{Expires: time.Hour})
+		c.Assert(err, Not(IsNil))
+	}
+}
+
+// locationHandler is an http.Handler which counts bucket location
+// requests.
+type locationHandler struct {
+	mutex    *sync.Mutex
+	requests *int
+}
+
+func (h locationHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if len(r.URL.Query()["location"]) != 1 {
+		w.WriteHeader(http.StatusNotFound)
+		return
+	}
+	h.mutex.Lock()
+	*h.requests++
+	h.mutex.Unlock()
+	w.Write([]byte("<LocationConstraint xmlns=\"http://doc.s3.amazonaws.com/2006-03-01\">eu-west-1</LocationConstraint>"))
+}
+
+// Test lookup, caching and persistence of bucket regions.
+func (s *TestSuite) TestBucketRegions(c *C) {
+	requests := 0
+	server := httptest.NewServer(locationHandler{mutex: &sync.Mutex{}, requests: &requests})
+	defer server.Close()
+
+	root, e := ioutil.TempDir(os.TempDir(), "cmd-")
+	c.Assert(e, IsNil)
+	defer os.RemoveAll(root)
+	file := filepath.Join(root, "regions.json")
+
+	regions := newBucketRegions()
+	c.Assert(regions.Load(file), IsNil)
+	u, e := url.Parse(server.URL)
+	c.Assert(e, IsNil)
+	httpClient := &http.Client{Transport: regionTransport{
+		host:      u.Host,
+		regions:   regions,
+		transport: http.DefaultTransport,
+	}}
+	for i := 0; i < 2; i++ {
+		resp, e := httpClient.Get(server.URL + "/bucket/?location=")
+		c.Assert(e, IsNil)
+		body, e := ioutil.ReadAll(resp.Body)
+		resp.Body.Close()
+		c.Assert(e, IsNil)
+		var region string
+		c.Assert(xml.Unmarshal(body, &region), IsNil)
+		c.Assert(region, Equals, "eu-west-1")
+	}
+	c.Assert(requests, Equals, 1)
+
+	// Regions are read back by the next invocation.
+	regions = newBucketRegions()
+	c.Assert(regions.Load(file), IsNil)
+	region, ok := regions.Get(u.Host, "bucket")
+	c.Assert(ok, Equals, true)
+	c.Assert(region, Equals, "eu-west-1")
+	_, ok = regions.Get(u.Host, "other")
+	c.Assert(ok, Equals, false)
+
+	// Region configured for the host is never looked up.
+	conf := new(Config)
+	conf.HostURL = server.URL + "/other/object"
+	conf.AccessKey = "WLGDGYAQYIGI833EV05A"
+	conf.SecretKey = "BYvgJM101sHngl2uzjXS/OBF/aMxAN06JrJ3qJlF"
+	conf.Signature = "S3v4"
+	conf.Region = "ap-south-1"
+	s3c, err := s3New(conf)
+	c.Assert(err, IsNil)
+	location, e := s3c.(*s3Client).api.GetBucketLocation("other")
+	c.Assert(e, IsNil)
+	c.Assert(location, Equals, "ap-south-1")
+	c.Assert(requests, Equals, 1)
+}
+
+// Test parsing of content length ranges.
+func (s *TestSuite) TestParseContentLengthRange(c *C) {
+	testCases := []struct {
+		lengthRange string
+		minSize     int64
+		maxSize     int64
+		success     bool
+	}{
+		{"", 0, 0, true},
+		{"1KiB-10MiB", 1024, 10 * 1024 * 1024, true},
+		{"0-100", 0, 100, true},
+		{"2MiB", 2 * 1024 * 1024, 2 * 1024 * 1024, true},
+		{"10-1", 0, 0, false},
+		{"0", 0, 0, false},
+		{"a-b", 0, 0, false},
+	}
+	for _, testCase := range testCases {
+		minSize, maxSize, err := parseContentLengthRange(testCase.lengthRange)
+		c.Assert(err == nil, Equals, testCase.success)
+		c.Assert(minSize, Equals, testCase.minSize)
+		c.Assert(maxSize, Equals, testCase.maxSize)
+	}
+}
+
+// checksumHandler - stores a single object, ETags are computed like S3
+// does unless the handler corrupts them. SHA256 checksums of uploads
+// are kept if set, sizes of parts are not reported if parts are
+// ignored.
+type checksumHandler struct {
+	mutex       *sync.Mutex
+	data        *[]byte
+	etag        *string
+	sha256      *string
+	parts       map[int][]byte
+	corrupt     bool
+	ignoreParts bool
+}
+
+func (h checksumHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+	h.mutex.Lock()
+	defer h.mutex.Unlock()
+	switch {
+	case r.Method == "GET" && len(query["location"]) == 1:
+		w.Write([]byte("<LocationConstraint xmlns=\"http://doc.s3.amazonaws.com/2006-03-01\"></LocationConstraint>"))
+	case r.Method == "POST" && len(query["uploads"]) == 1:
+		w.Write([]byte("<InitiateMultipartUploadResult><Bucket>bucket</Bucket><Key>object</Key><UploadId>sum</UploadId></InitiateMultipartUploadResult>"))
+	case r.Method == "PUT" && query.Get("uploadId") == "sum":
+		data, _ := ioutil.ReadAll(r.Body)
+		if r.Header.Get("Content-Md5") != sumMD5Base64(data) {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		partNumber, _ := strconv.Atoi(query.Get("partNumber"))
+		h.parts[partNumber] = data
+		w.Header().Set("ETag", "\""+fmt.Sprintf("%x", md5.Sum(data))+"\"")
+	case r.Method == "POST" && query.Get("uploadId") == "sum":
+		var data, sums []byte
+		for i := 1; i <= len(h.parts); i++ {
+			data = append(data, h.parts[i]...)
+			sum := md5.Sum(h.parts[i])
+			sums = append(sums, sum[:]...)
+		}
+		*h.data = data
+		*h.etag = fmt.Sprintf("%x", md5.Sum(sums)) + "-" + strconv.Itoa(len(h.parts))
+		if h.corrupt {
+			*h.data = append([]byte{'x'}, data[1:]...)
+		}
+		w.Write([]byte("<CompleteMultipartUploadResult><ETag>\"" + *h.etag + "\"</ETag></CompleteMultipartUploadResult>"))
+	case r.Method == "PUT":
+		data, _ := ioutil.ReadAll(r.Body)
+		if h.corrupt && h.sha256 != nil {
+			data = append([]byte{'x'}, data[1:]...)
+		}
+		if sha := r.Header.Get("X-Amz-Content-Sha256"); sha != unsignedPayload && sha != sum256Hex(data) {
+			w.WriteHeader(http.StatusBadRequest)
+			w.Write([]byte("<Error><Code>XAmzContentSHA256Mismatch</Code></Error>"))
+			return
+		}
+		if h.sha256 != nil {
+			*h.sha256 = r.Header.Get("X-Amz-Checksum-Sha256")
+		}
+		*h.data = data
+		*h.etag = fmt.Sprintf("%x", md5.Sum(data))
+		if h.corrupt {
+			*h.etag = fmt.Sprintf("%x", md5.Sum(append(data, 'x')))
+		}
+		w.Header().Set("ETag", "\""+*h.etag+"\"")
+	case r.Method == "HEAD" && query.Get("partNumber") != "" && !h.ignoreParts:
+		partNumber, _ := strconv.Atoi(query.Get("partNumber"))
+		w.Header().Set("Content-Length", strconv.Itoa(len(h.parts[partNumber])))
+	case r.Method == "HEAD":
+		w.Header().Set("ETag", "\""+*h.etag+"\"")
+		w.Header().Set("Content-Length", strconv.Itoa(len(*h.data)))
+	case r.Method == "GET":
+		if h.sha256 != nil && r.Header.Get("X-Amz-Checksum-Mode") == "ENABLED" {
+			w.Header().Set("X-Amz-Checksum-Sha256", *h.sha256)
+		}
+		w.Header().Set("ETag", "\""+*h.etag+"\"")
+		w.Header().Set("Content-Length", strconv.Itoa(len(*h.data)))
+		w.Write(*h.data)
+	default:
+		w.WriteHeader(http.StatusBadRequest)
+	}
+}
+
+// Test verification of uploads and downloads against ETags.
+func (s *TestSuite) TestObjectChecksum(c *C) {
+	var data []byte
+	var etag string
+	handler := checksumHandler{
+		mutex: &sync.Mutex{},
+		data:  &data,
+		etag:  &etag,
+		parts: make(map[int][]byte),
+	}
+	server := httptest.NewServer(handler)
+	defer server.Close()
+
+	conf := new(Config)
+	conf.HostURL = server.URL + "/bucket/object"
+	conf.AccessKey = "WLGDGYAQYIGI833EV05A"
+	conf.SecretKey = "BYvgJM101sHngl2uzjXS/OBF/aMxAN06JrJ3qJlF"
+	conf.Signature = "S3v4"
+	s3c, err := s3New(conf)
+	c.Assert(err, IsNil)
+
+	object := bytes.Repeat([]byte("checksum"), 320)
+	for _, partSize := range []int64{0, 1024} {
+		n, err := s3c.Put(context.Background(), bytes.NewReader(object), int64(len(object)), "", nil, PutOpts{PartSize: partSize, Checksum: true})
+		c.Assert(err, IsNil)
+		c.Assert(n, Equals, int64(len(object)))
+
+		reader, err := s3c.Get(context.Background(), GetOpts{Checksum: true})
+		c.Assert(err, IsNil)
+		got, e := ioutil.ReadAll(reader)
+		c.Assert(e, IsNil)
+		c.Assert(got, DeepEquals, object)
+	}
+	c.Assert(etag, Equals, newETagHashOf(1024, object))
+
+	// Mismatches fail uploads and downloads.
+	handler.corrupt = true
+	server.Config.Handler = handler
+	for _, partSize := range []int64{0, 1024} {
+		_, err = s3c.Put(context.Background(), bytes.NewReader(object), int64(len(object)), "", nil, PutOpts{PartSize: partSize, Checksum: true})
+		if partSize == 0 {
+			c.Assert(err, Not(IsNil))
+			_, ok := err.ToGoError().(ObjectChecksumMismatch)
+			c.Assert(ok, Equals, true)
+			continue
+		}
+		// The multipart ETag matches, the stored data does not.
+		c.Assert(err, IsNil)
+		reader, err := s3c.Get(context.Background(), GetOpts{Checksum: true})
+		c.Assert(err, IsNil)
+		_, e := ioutil.ReadAll(reader)
+		_, ok := e.(ObjectChecksumMismatch)
+		c.Assert(ok, Equals, true)
+	}
+}
+
+// Test verification of objects uploaded in parts of different sizes.
+func (s *TestSuite) TestObjectChecksumParts(c *C) {
+	object := bytes.Repeat([]byte("checksum"), 320)
+	parts := map[int][]byte{1: object[:1024], 2: object[1024:1536], 3: object[1536:]}
+	var sums []byte
+	for i := 1; i <= len(parts); i++ {
+		sum := md5.Sum(parts[i])
+		sums = append(sums, sum[:]...)
+	}
+	etag := fmt.Sprintf("%x", md5.Sum(sums)) + "-3"
+	handler := checksumHandler{
+		mutex: &sync.Mutex{},
+		data:  &object,
+		etag:  &etag,
+		parts: parts,
+	}
+	server := httptest.NewServer(handler)
+	defer server.Close()
+
+	conf := new(Config)
+	conf.HostURL = server.URL + "/bucket/object"
+	conf.AccessKey = "WLGDGYAQYIGI833EV05A"
+	conf.SecretKey = "BYvgJM101sHngl2uzjXS/OBF/aMxAN06JrJ3qJlF"
+	conf.Signature = "S3v4"
+	s3c, err := s3New(conf)
+	c.Assert(err, IsNil)
+
+	// Downloads are verified against the sizes of all parts.
+	reader, err := s3c.Get(context.Background(), GetOpts{Checksum: true})
+	c.Assert(err, IsNil)
+	got, e := ioutil.ReadAll(reader)
+	c.Assert(e, IsNil)
+	c.Assert(got, DeepEquals, object)
+
+	// The ETag matches no part size, it is computed from the data.
+	sum, partSize, err := s3c.Checksum(-1)
+	c.Assert(err, IsNil)
+	c.Assert(sum, Equals, newETagHashOf(0, object))
+	c.Assert(partSize, Equals, int64(0))
+
+	// Without sizes of parts downloads cannot be verified.
+	handler.ignoreParts = true
+	server.Config.Handler = handler
+	_, err = s3c.Get(context.Background(), GetOpts{Checksum: true})
+	c.Assert(err, NotNil)
+	_, ok := err.ToGoError().(ObjectChecksumUnverifiable)
+	c.Assert(ok, Equals, true)
+
+	c.Assert(uniformPartSize(nil), Equals, int64(0))
+	c.Assert(uniformPartSize([]int64{0}), Equals, int64(1))
+	c.Assert(uniformPartSize([]int64{1024, 1024, 512}), Equals, int64(1024))
+	c.Assert(uniformPartSize([]int64{1024, 512, 1024}), Equals, int64(-1))
+	c.Assert(uniformPartSize([]int64{512, 1024}), Equals, int64(-1))
+}
+
+// Test verification of uploads and downloads against SHA256 checksums.
+func (s *TestSuite) TestObjectChecksumSHA256(c *C) {
+	var data []byte
+	var etag, checksum string
+	handler := checksumHandler{
+		mutex:  &sync.Mutex{},
+		data:   &data,
+		etag:   &etag,
+		sha256: &checksum,
+		parts:  make(map[int][]byte),
+	}
+	server := httptest.NewServer(handler)
+	defer server.Close()
+
+	conf := new(Config)
+	conf.HostURL = server.URL + "/bucket/object"
+	conf.AccessKey = "WLGDGYAQYIGI833EV05A"
+	conf.SecretKey = "BYvgJM101sHngl2uzjXS/OBF/aMxAN06JrJ3qJlF"
+	conf.Signature = "S3v4"
+	s3c, err := s3New(conf)
+	c.Assert(err, IsNil)
+
+	object := bytes.Repeat([]byte("checksum"), 320)
+	_, err = s3c.Put(context.Background(), bytes.NewReader(object), int64(len(object)), "", nil, PutOpts{Checksum: true})
+	c.Assert(err, IsNil)
+	sum := sha256.Sum256(object)
+	c.Assert(checksum, Equals, base64.StdEncoding.EncodeToString(sum[:]))
+
+	reader, err := s3c.Get(context.Background(), GetOpts{Checksum: true})
+	c.Assert(err, IsNil)
+	got, e := ioutil.ReadAll(reader)
+	c.Assert(e, IsNil)
+	c.Assert(got, DeepEquals, object)
+
+	// The SHA256 is verified even if the ETag is not derived from the
+	// data.
+	etag = "not-an-md5"
+	data[0] = 'C'
+	reader, err = s3c.Get(context.Background(), GetOpts{Checksum: true})
+	c.Assert(err, IsNil)
+	_, e = ioutil.ReadAll(reader)
+	_, ok := e.(ObjectChecksumMismatch)
+	c.Assert(ok, Equals, true)
+
+	// Data received corrupted by the host fails the upload.
+	handler.corrupt = true
+	server.Config.Handler = handler
+	_, err = s3c.Put(context.Background(), bytes.NewReader(object), int64(len(object)), "", nil, PutOpts{Checksum: true})
+	c.Assert(err, NotNil)
+	_, ok = err.ToGoError().(ObjectChecksumMismatch)
+	c.Assert(ok, Equals, true)
+}
+
+// Test comparing checksums of objects and files.
+func (s *TestSuite) TestObjectChecksumCompare(c *C) {
+	var data []byte
+	var etag string
+	handler := checksumHandler{
+		mutex: &sync.Mutex{},
+		data:  &data,
+		etag:  &etag,
+		parts: make(map[int][]byte),
+	}
+	server := httptest.NewServer(handler)
+	defer server.Close()
+
+	conf := new(Config)
+	conf.HostURL = server.URL + "/bucket/object"
+	conf.AccessKey = "WLGDGYAQYIGI833EV05A"
+	conf.SecretKey = "BYvgJM101sHngl2uzjXS/OBF/aMxAN06JrJ3qJlF"
+	conf.Signature = "S3v4"
+	s3c, err := s3New(conf)
+	c.Assert(err, IsNil)
+
+	object := bytes.Repeat([]byte("checksum"), 320)
+	_, err = s3c.Put(context.Background(), bytes.NewReader(object), int64(len(object)), "", nil, PutOpts{PartSize: 1024, Checksum: true})
+	c.Assert(err, IsNil)
+
+	// The ETag of the object is returned with the size of its parts.
+	sum, partSize, err := s3c.Checksum(-1)
+	c.Assert(err, IsNil)
+	c.Assert(sum, Equals, newETagHashOf(1024, object))
+	c.Assert(partSize, Equals, int64(1024))
+
+	// Other part sizes are computed from the data.
+	sum, partSize, err = s3c.Checksum(0)
+	c.Assert(err, IsNil)
+	c.Assert(sum, Equals, newETagHashOf(0, object))
+	c.Assert(partSize, Equals, int64(0))
+
+	root, e := ioutil.TempDir(os.TempDir(), "checksum-")
+	c.Assert(e, IsNil)
+	defer os.RemoveAll(root)
+	filePath := filepath.Join(root, "object")
+	c.Assert(ioutil.WriteFile(filePath, object, 0644), IsNil)
+	fsc, err := fsNew(filePath)
+	c.Assert(err, IsNil)
+	sum, _, err = fsc.Checksum(1024)
+	c.Assert(err, IsNil)
+	c.Assert(sum, Equals, etag)
+
+	// Same size, different contents.
+	changed := bytes.Repeat([]byte("checkSUM"), 320)
+	c.Assert(ioutil.WriteFile(filePath, changed, 0644), IsNil)
+	sum, _, err = fsc.Checksum(1024)
+	c.Assert(err, IsNil)
+	c.Assert(sum, Not(Equals), etag)
+}
+
+// newETagHashOf - ETag of data uploaded in parts of the given size.
+func newETagHashOf(partSize int64, data []byte) string {
+	h := newETagHash(partSize)
+	h.Write(data)
+	return h.ETag()
+}
+
+// Test ETags computed for single and multipart uploads.
+func (s *TestSuite) TestETagHash(c *C) {
+	c.Assert(newETagHashOf(0, nil), Equals, "d41d8cd98f00b204e9800998ecf8427e")
+	c.Assert(newETagHashOf(0, []byte("hello")), Equals, "5d41402abc4b2a76b9719d911017c592")
+
+	// md5(md5("hel") + md5("lo")) with two parts.
+	sum1 := md5.Sum([]byte("hel"))
+	sum2 := md5.Sum([]byte("lo"))
+	c.Assert(newETagHashOf(3, []byte("hello")), Equals, fmt.Sprintf("%x", md5.Sum(append(sum1[:], sum2[:]...)))+"-2")
+
+	// Data split across writes is hashed alike.
+	h := newETagHash(3)
+	h.Write([]byte("h"))
+	h.Write([]byte("ell"))
+	h.Write([]byte("o"))
+	c.Assert(h.ETag(), Equals, newETagHashOf(3, []byte("hello")))
+
+	// A multiple of the part size has no trailing empty part.
+	c.Assert(etagPartsCount(newETagHashOf(3, []byte("hellos"))), Equals, 2)
+	c.Assert(etagPartsCount("\"5d41402abc4b2a76b9719d911017c592\""), Equals, 0)
+
+	c.Assert(isChecksumETag("\"5d41402abc4b2a76b9719d911017c592-2\"", http.Header{}), Equals, true)
+	c.Assert(isChecksumETag("\"5d41402abc4b2a76b9719d911017c592\"", http.Header{"X-Amz-Server-Side-Encryption": []string{"aws:kms"}}), Equals, false)
+	c.Assert(isChecksumETag("\"etag-12\"", http.Header{}), Equals, false)
+}
+
+// versioningHandler - serves versioning of a bucket with MFA delete,
+// requests changing it or deleting versions require the MFA, all
+// others must be sent without it.
+type versioningHandler struct {
+	mfa        string
+	versioning *versioningConfiguration
+}
+
+func (h versioningHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+	mfaDenied := func() bool {
+		if h.versioning.MFADelete == mfaDeleteEnabled && r.Header.Get("X-Amz-Mfa") != h.mfa {
+			w.WriteHeader(http.StatusForbidden)
+			w.Write([]byte("<Error><Code>AccessDenied</Code><Message>Mfa Authentication must be used for this request</Message></Error>"))
+			return true
+		}
+		return false
+	}
+	switch {
+	case r.Method == "GET" && len(query["location"]) == 1:
+		w.Write([]byte("<LocationConstraint xmlns=\"http://doc.s3.amazonaws.com/2006-03-01\"></LocationConstraint>"))
+	case r.Method == "GET" && len(query["versioning"]) == 1:
+		body, _ := xml.Marshal(h.versioning)
+		w.Write(body)
+	case r.Method == "PUT" && len(query["versioning"]) == 1:
+		if mfaDenied() {
+			return
+		}
+		// Turning MFA delete on or off always requires the MFA.
+		versioning := versioningConfiguration{}
+		xml.NewDecoder(r.Body).Decode(&versioning)
+		if versioning.MFADelete != "" && r.Header.Get("X-Amz-Mfa") != h.mfa {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		*h.versioning = versioning
+	case r.Method == "DELETE" && query.Get("versionId") != "":
+		if mfaDenied() {
+			return
+		}
+		w.WriteHeader(http.StatusNoContent)
+	case r.Method == "GET" && query.Get("versionId") != "":
+		if r.Header.Get("X-Amz-Mfa") != "" {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		w.Write([]byte("version"))
+	case r.Method == "POST" && len(query["delete"]) == 1:
+		request := deleteRequest{}
+		xml.NewDecoder(r.Body).Decode(&request)
+		versions := false
+		for _, object := range request.Objects {
+			versions = versions || object.VersionID != ""
+		}
+		if versions && mfaDenied() {
+			return
+		}
+		if !versions && r.Header.Get("X-Amz-Mfa") != "" {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		w.Write([]byte("<DeleteResult></DeleteResult>"))
+	default:
+		w.WriteHeader(http.StatusBadRequest)
+	}
+}
+
+// Test versioning and version deletes of buckets with MFA delete.
+func (s *TestSuite) TestVersioningMFA(c *C) {
+	versioning := versioningConfiguration{}
+	handler := versioningHandler{
+		mfa:        "arn:aws:iam::123456789012:mfa/root-account-mfa-device 123456",
+		versioning: &versioning,
+	}
+	server := httptest.NewServer(handler)
+	defer server.Close()
+
+	newClient := func(urlPath, mfa string) *s3Client {
+		conf := new(Config)
+		conf.HostURL = server.URL + urlPath
+		conf.AccessKey = "WLGDGYAQYIGI833EV05A"
+		conf.SecretKey = "BYvgJM101sHngl2uzjXS/OBF/aMxAN06JrJ3qJlF"
+		conf.Signature = "S3v4"
+		conf.MFA = mfa
+		clnt, err := s3New(conf)
+		c.Assert(err, IsNil)
+		return clnt.(*s3Client)
+	}
+
+	bucket := newClient("/bucket", handler.mfa)
+	got, err := bucket.GetVersioning()
+	c.Assert(err, IsNil)
+	c.Assert(got.Status, Equals, "")
+
+	err = bucket.SetVersioning(versioningConfiguration{Status: versioningEnabled, MFADelete: mfaDeleteEnabled})
+	c.Assert(err, IsNil)
+	got, err = bucket.GetVersioning()
+	c.Assert(err, IsNil)
+	c.Assert(got, DeepEquals, versioningConfiguration{XMLName: xml.Name{Local: "VersioningConfiguration"}, Status: versioningEnabled, MFADelete: mfaDeleteEnabled})
+
+	// Without MFA neither versions are removed nor versioning changed.
+	err = newClient("/bucket", "").SetVersioning(versioningConfiguration{Status: versioningSuspended})
+	c.Assert(err, Not(IsNil))
+	_, ok := err.ToGoError().(MFARequired)
+	c.Assert(ok, Equals, true)
+	err = newClient("/bucket/object", "").RemoveVersion("v1")
+	c.Assert(err, Not(IsNil))
+	_, ok = err.ToGoError().(MFARequired)
+	c.Assert(ok, Equals, true)
+
+	err = newClient("/bucket/object", handler.mfa).RemoveVersion("v1")
+	c.Assert(err, IsNil)
+
+	// Bulk deletes only send the MFA along with versions, reads never.
+	_, err = bucket.deleteObjects("bucket", deleteRequest{Objects: []deleteObject{{Key: "object", VersionID: "v1"}}})
+	c.Assert(err, IsNil)
+	_, err = newClient("/bucket", "").deleteObjects("bucket", deleteRequest{Objects: []deleteObject{{Key: "object", VersionID: "v1"}}})
+	c.Assert(err, Not(IsNil))
+	_, err = bucket.deleteObjects("bucket", deleteRequest{Objects: []deleteObject{{Key: "object"}}})
+	c.Assert(err, IsNil)
+	reader, err := newClient("/bucket/object", handler.mfa).GetVersion("v1")
+	c.Assert(err, IsNil)
+	data, e := ioutil.ReadAll(reader)
+	c.Assert(e, IsNil)
+	c.Assert(string(data), Equals, "version")
+
+	// The MFA is read from the config unless set in the environment.
+	hostCfg := hostConfigV8{URL: server.URL, AccessKey: "WLGDGYAQYIGI833EV05A", SecretKey: "BYvgJM101sHngl2uzjXS/OBF/aMxAN06JrJ3qJlF", API: "S3v4", MFA: handler.mfa}
+	clnt, err := newClientFromHostConfig("mfa", server.URL+"/bucket", &hostCfg)
+	c.Assert(err, IsNil)
+	c.Assert(clnt.(*s3Client).config.MFA, Equals, handler.mfa)
+	os.Setenv("MC_MFA_mfa", "serial  654321")
+	defer os.Unsetenv("MC_MFA_mfa")
+	clnt, err = newClientFromHostConfig("mfa", server.URL+"/bucket", &hostCfg)
+	c.Assert(err, IsNil)
+	c.Assert(clnt.(*s3Client).config.MFA, Equals, "serial 654321")
+	hostCfg.MFA = "123456"
+	os.Unsetenv("MC_MFA_mfa")
+	_, err = newClientFromHostConfig("mfa", server.URL+"/bucket", &hostCfg)
+	c.Assert(err, Not(IsNil))
+
+	parsed, err := parseVersioning("SUSPENDED", "disabled")
+	c.Assert(err, IsNil)
+	c.Assert(parsed, DeepEquals, versioningConfiguration{Status: versioningSuspended, MFADelete: mfaDeleteDisabled})
+	_, err = parseVersioning("on", "")
+	c.Assert(err, Not(IsNil))
+}
+
+// roundTripFunc - transport recording requests in tests.
+type roundTripFunc func(req *http.Request) (*http.Response, error)
+
+func (f roundTripFunc) RoundTrip(req *http.Request) (*http.Response, error) {
+	return f(req)
+}
+
+// Test addressing buckets as configured.
+func (s *TestSuite) TestBucketLookup(c *C) {
+	testCases := []struct {
+		lookup string
+		urlStr string
+		host   string
+		path   string
+		ok     bool
+	}{
+		{"dns", "http://minio.example.com/bucket/dir/object", "bucket.minio.example.com", "/dir/object", true},
+		{"dns", "http://minio.example.com/bucket/", "bucket.minio.example.com", "/", true},
+		{"dns", "http://minio.example.com/my.bucket/object", "my.bucket.minio.example.com", "/object", true},
+		// Dotted buckets do not match certificates, invalid names are no labels.
+		{"dns", "https://minio.example.com/my.bucket/object", "", "", false},
+		{"dns", "http://minio.example.com/My_Bucket/object", "", "", false},
+		{"dns", "http://minio.example.com/", "", "", false},
+		{"dns", "http://minio.example.com/bucket/?location=", "", "", false},
+		{"dns", "http://minio.example.com/bucket/object?X-Amz-Signature=abc", "", "", false},
+		{"path", "https://mys3bucket.s3-eu-west-1.amazonaws.com/dir/object", "s3-eu-west-1.amazonaws.com", "/mys3bucket/dir/object", true},
+		{"path", "https://bucket.storage.googleapis.com/object", "storage.googleapis.com", "/bucket/object", true},
+		{"path", "http://minio.example.com/bucket/object", "", "", false},
+	}
+	for i, testCase := range testCases {
+		req, e := http.NewRequest("GET", testCase.urlStr, nil)
+		c.Assert(e, IsNil)
+		host, path, ok := lookupTransport{lookup: testCase.lookup}.rewrite(req)
+		c.Assert(ok, Equals, testCase.ok, Commentf("Test %d", i+1))
+		c.Assert(host, Equals, testCase.host, Commentf("Test %d", i+1))
+		c.Assert(path, Equals, testCase.path, Commentf("Test %d", i+1))
+	}
+
+	// Rewritten requests are signed again for their new host.
+	var got *http.Request
+	transport := lookupTransport{
+		lookup: "dns",
+		creds:  s3Credentials{AccessKey: "WLGDGYAQYIGI833EV05A", SecretKey: "BYvgJM101sHngl2uzjXS/OBF/aMxAN06JrJ3qJlF"},
+		transport: roundTripFunc(func(req *http.Request) (*http.Response, error) {
+			got = req
+			return &http.Response{StatusCode: http.StatusOK, Body: ioutil.NopCloser(bytes.NewReader(nil))}, nil
+		}),
+	}
+	req, e := http.NewRequest("GET", "http://minio.example.com/bucket/a+b", nil)
+	c.Assert(e, IsNil)
+	s3SignV4(req, transport.creds, "us-east-1", "s3")
+	auth := req.Header.Get("Authorization")
+	_, e = transport.RoundTrip(req)
+	c.Assert(e, IsNil)
+	c.Assert(got.Host, Equals, "bucket.minio.example.com")
+	c.Assert(got.URL.EscapedPath(), Equals, s3EncodePath("/a+b"))
+	c.Assert(got.Header.Get("Authorization"), Not(Equals), auth)
+	c.Assert(regionFromAuthV4(got), Equals, "us-east-1")
+	c.Assert(req.Host, Equals, "minio.example.com")
+}
+
+// Test sending session tokens of temporary keys.
+func (s *TestSuite) TestSessionToken(c *C) {
+	var tokens []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		tokens = append(tokens, r.Header.Get("X-Amz-Security-Token"))
+		if !strings.Contains(r.Header.Get("Authorization"), "ASIAJ1") {
+			w.WriteHeader(http.StatusForbidden)
+			return
+		}
+	}))
+	defer server.Close()
+
+	for _, signature := range []string{"S3v4", "S3v2"} {
+		tokens = nil
+		conf := new(Config)
+		conf.HostURL = server.URL + "/bucket"
+		conf.AccessKey = "ASIAJ1"
+		conf.SecretKey = "BYvgJM101sHngl2uzjXS/OBF/aMxAN06JrJ3qJlF"
+		conf.SessionToken = "token-" + signature
+		conf.Signature = signature
+		conf.Region = "us-east-1"
+		clnt, err := s3New(conf)
+		c.Assert(err, IsNil)
+		ok, e := clnt.(*s3Client).api.BucketExists("bucket")
+		c.Assert(e, IsNil)
+		c.Assert(ok, Equals, true)
+		c.Assert(tokens, DeepEquals, []string{"token-" + signature})
+	}
+
+	// Clients of other sessions against the same host are not shared.
+	conf := &Config{HostURL: server.URL, AccessKey: "ASIAJ1", SecretKey: "BYvgJM101sHngl2uzjXS/OBF/aMxAN06JrJ3qJlF", Signature: "S3v4", SessionToken: "token-1"}
+	clnt1, err := s3New(conf)
+	c.Assert(err, IsNil)
+	conf2 := *conf
+	conf2.SessionToken = "token-2"
+	clnt2, err := s3New(&conf2)
+	c.Assert(err, IsNil)
+	c.Assert(clnt1.(*s3Client).api == clnt2.(*s3Client).api, Equals, false)
+}
+
+// Test credentials printed by a credential process.
+func (s *TestSuite) TestCredentialProcess(c *C) {
+	if runtime.GOOS == "windows" {
+		c.Skip("Commands of the test are written for a POSIX shell.")
+	}
+	root, e := ioutil.TempDir(os.TempDir(), "cmd-")
+	c.Assert(e, IsNil)
+	defer os.RemoveAll(root)
+
+	// Process counts its runs, its keys expire in a minute.
+	runs := filepath.Join(root, "runs")
+	expiration := time.Now().UTC().Add(time.Minute).Format(time.RFC3339)
+	command := "echo run >> " + runs + "; echo '{\"Version\": 1, \"AccessKeyId\": \"ASIAJ1\", \"SecretAccessKey\": \"BYvgJM101sHngl2uzjXS/OBF/aMxAN06JrJ3qJlF\", \"SessionToken\": \"token1\", \"Expiration\": \"" + expiration + "\"}'"
+	provider := getProcessProvider(command)
+	c.Assert(getProcessProvider(command), Equals, provider)
+	creds, err := provider.Get()
+	c.Assert(err, IsNil)
+	c.Assert(creds, DeepEquals, s3Credentials{AccessKey: "ASIAJ1", SecretKey: "BYvgJM101sHngl2uzjXS/OBF/aMxAN06JrJ3qJlF", SessionToken: "token1"})
+
+	// About to expire, process runs again.
+	_, err = provider.Get()
+	c.Assert(err, IsNil)
+	out, e := ioutil.ReadFile(runs)
+	c.Assert(e, IsNil)
+	c.Assert(strings.Count(string(out), "run"), Equals, 2)
+
+	var tokens []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		tokens = append(tokens, r.Header.Get("X-Amz-Security-Token"))
+		if !strings.Contains(r.Header.Get("Authorization"), "ASIAJ1") {
+			w.WriteHeader(http.StatusForbidden)
+		}
+	}))
+	defer server.Close()
+	conf := &Config{HostURL: server.URL + "/bucket", Signature: "S3v4", Region: "us-east-1", CredentialProcess: command}
+	clnt, err := s3New(conf)
+	c.Assert(err, IsNil)
+	ok, e := clnt.(*s3Client).api.BucketExists("bucket")
+	c.Assert(e, IsNil)
+	c.Assert(ok, Equals, true)
+	c.Assert(tokens, DeepEquals, []string{"token1"})
+
+	// Shared URLs and forms are signed with the keys of the process.
+	clnt, err = s3New(&Config{HostURL: server.URL + "/bucket/object", Signature: "S3v4", Region: "us-east-1", CredentialProcess: command})
+	c.Assert(err, IsNil)
+	shareURL, err := clnt.ShareDownload(time.Hour, nil)
+	c.Assert(err, IsNil)
+	u, e := url.Parse(shareURL)
+	c.Assert(e, IsNil)
+	c.Assert(strings.HasPrefix(u.Query().Get("X-Amz-Credential"), "ASIAJ1/"), Equals, true)
+	c.Assert(u.Query().Get("X-Amz-Security-Token"), Equals, "token1")
+	shareURL, err = clnt.ShareUploadURL(ShareUploadOpts{Expires: time.Hour})
+	c.Assert(err, IsNil)
+	u, e = url.Parse(shareURL)
+	c.Assert(e, IsNil)
+	c.Assert(strings.HasPrefix(u.Query().Get("X-Amz-Credential"), "ASIAJ1/"), Equals, true)
+	c.Assert(u.Query().Get("X-Amz-Security-Token"), Equals, "token1")
+	formData, err := clnt.ShareUpload(ShareUploadOpts{Expires: time.Hour, ContentType: "image/png", MinSize: 1, MaxSize: 5})
+	c.Assert(err, IsNil)
+	c.Assert(formData["key"], Equals, "object")
+	c.Assert(strings.HasPrefix(formData["x-amz-credential"], "ASIAJ1/"), Equals, true)
+	c.Assert(formData["x-amz-security-token"], Equals, "token1")
+	policy, e := base64.StdEncoding.DecodeString(formData["policy"])
+	c.Assert(e, IsNil)
+	c.Assert(strings.Contains(string(policy), `["eq","$x-amz-security-token","token1"]`), Equals, true)
+	c.Assert(strings.Contains(string(policy), `["content-length-range",1,5]`), Equals, true)
+	date, e := time.Parse(iso8601DateFormat, formData["x-amz-date"])
+	c.Assert(e, IsNil)
+	signingKey := getSigningKeyV4("BYvgJM101sHngl2uzjXS/OBF/aMxAN06JrJ3qJlF", "us-east-1", "s3", date)
+	c.Assert(formData["x-amz-signature"], Equals, hex.EncodeToString(sumHMAC(signingKey, []byte(formData["policy"]))))
+
+	for _, command := range []string{"exit 1", "echo '{\"Version\": 2}'", "echo '{\"Version\": 1}'", "echo nothing"} {
+		_, err = getProcessProvider(command).Get()
+		c.Assert(err, Not(IsNil), Commentf("Command %s", command))
+	}
+}
+
+// Test hosts verified by a CA bundle, requiring a client certificate.
+func (s *TestSuite) TestClientCertificates(c *C) {
+	server := httptest.NewUnstartedServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	server.TLS = &tls.Config{ClientAuth: tls.RequireAnyClientCert}
+	server.StartTLS()
+	defer server.Close()
+
+	root, e := ioutil.TempDir(os.TempDir(), "cmd-")
+	c.Assert(e, IsNil)
+	defer os.RemoveAll(root)
+
+	// Certificate of the server is its own CA, and is presented as
+	// client certificate as well.
+	certificate := server.TLS.Certificates[0]
+	caCert := filepath.Join(root, "ca.pem")
+	certPEM := pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: certificate.Certificate[0]})
+	c.Assert(ioutil.WriteFile(caCert, certPEM, 0600), IsNil)
+	key := filepath.Join(root, "key.pem")
+	keyPEM := pem.EncodeToMemory(&pem.Block{Type: "RSA PRIVATE KEY", Bytes: x509.MarshalPKCS1PrivateKey(certificate.PrivateKey.(*rsa.PrivateKey))})
+	c.Assert(ioutil.WriteFile(key, keyPEM, 0600), IsNil)
+
+	testCases := []struct {
+		caCert  string
+		cert    string
+		key     string
+		success bool
+	}{
+		// Server is not trusted.
+		{"", "", "", false},
+		// Server requires a client certificate.
+		{caCert, "", "", false},
+		{caCert, caCert, key, true},
+	}
+	for i, testCase := range testCases {
+		conf := &Config{HostURL: server.URL + "/bucket", Signature: "S3v4", Region: "us-east-1",
+			AccessKey: "WLGDGYAQYIGI833EV05A", SecretKey: "BYvgJM101sHngl2uzjXS/OBF/aMxAN06JrJ3qJlF",
+			CACert: testCase.caCert, Cert: testCase.cert, Key: testCase.key}
+		clnt, err := s3New(conf)
+		c.Assert(err, IsNil)
+		_, e := clnt.(*s3Client).api.BucketExists("bucket")
+		c.Assert(e == nil, Equals, testCase.success, Commentf("Test %d: %v", i+1, e))
+	}
+
+	// Certificate and key are set together, CA bundles hold certificates.
+	_, err := loadTLSConfig("", caCert, "", false)
+	c.Assert(err, Not(IsNil))
+	_, err = loadTLSConfig(key, "", "", false)
+	c.Assert(err, Not(IsNil))
+	tlsConfig, err := loadTLSConfig("", "", "", false)
+	c.Assert(err, IsNil)
+	c.Assert(tlsConfig, IsNil)
+}
+
+// Test signing of payloads of uploads.
+func (s *TestSuite) TestPayloadSigning(c *C) {
+	// Example of the AWS documentation of streaming payloads.
+	t, e := time.Parse(iso8601DateFormat, "20130524T000000Z")
+	c.Assert(e, IsNil)
+	data := bytes.Repeat([]byte("a"), 66560)
+	signingKey := getSigningKeyV4("wJalrXUtnFEMI/K7MDENG/bPxRfiCYEXAMPLEKEY", "us-east-1", "s3", t)
+	reader := newStreamingReader(ioutil.NopCloser(bytes.NewReader(data)), int64(len(data)), signingKey,
+		"20130524T000000Z", getScopeV4("us-east-1", "s3", t), "4f232c4386841ef735655705268965c44a0e4690baa4adea153f7db9fa80a0a9")
+	encoded, e := ioutil.ReadAll(reader)
+	c.Assert(e, IsNil)
+	c.Assert(int64(len(encoded)), Equals, streamingContentLength(int64(len(data))))
+	c.Assert(len(encoded), Equals, 66824)
+	c.Assert(string(encoded[:88]), Equals, "10000;chunk-signature=ad80c730a21e5b8d04586a2213dd63b9a0e99e0e2307b0ade35a65485a288648\r\n")
+	c.Assert(strings.Contains(string(encoded), "400;chunk-signature=0055627c9e194cb4542bae2aa5492e3c1575bbb81b612b7d234b86a503ef5497\r\n"), Equals, true)
+	c.Assert(strings.HasSuffix(string(encoded), "0;chunk-signature=b6c6ea8a5354eaf15b3cb7646744f4275b71ea724fed81ceb9323e279d449df9\r\n\r\n"), Equals, true)
+
+	var header http.Header
+	var body []byte
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		header = r.Header
+		body, _ = ioutil.ReadAll(r.Body)
+	}))
+	defer server.Close()
+	creds := s3Credentials{AccessKey: "WLGDGYAQYIGI833EV05A", SecretKey: "BYvgJM101sHngl2uzjXS/OBF/aMxAN06JrJ3qJlF"}
+	for _, payload := range validPayloads {
+		req, e := http.NewRequest("PUT", server.URL+"/bucket/object", bytes.NewReader(data))
+		c.Assert(e, IsNil)
+		req.Header.Set("X-Amz-Content-Sha256", sum256Hex(data))
+		s3SignV4(req, creds, "us-east-1", "s3")
+		transport := payloadTransport{payload: payload, provider: staticProvider(creds), transport: http.DefaultTransport}
+		resp, e := transport.RoundTrip(req)
+		c.Assert(e, IsNil)
+		resp.Body.Close()
+		if payload == payloadUnsigned {
+			c.Assert(header.Get("X-Amz-Content-Sha256"), Equals, unsignedPayload)
+			c.Assert(body, DeepEquals, data)
+			continue
+		}
+		c.Assert(header.Get("X-Amz-Content-Sha256"), Equals, streamingPayload)
+		c.Assert(header.Get("Content-Encoding"), Equals, "aws-chunked")
+		c.Assert(header.Get("X-Amz-Decoded-Content-Length"), Equals, "66560")
+		c.Assert(int64(len(body)), Equals, streamingContentLength(int64(len(data))))
+		// Chunks hold the payload.
+		var decoded []byte
+		for len(body) > 0 {
+			i := bytes.Index(body, []byte("\r\n"))
+			size, e := strconv.ParseInt(strings.Split(string(body[:i]), ";")[0], 16, 64)
+			c.Assert(e, IsNil)
+			decoded = append(decoded, body[i+2:i+2+int(size)]...)
+			body = body[i+2+int(size)+2:]
+		}
+		c.Assert(decoded, DeepEquals, data)
+	}
+}
+
+// Test metadata of objects read with HEAD requests.
+func (s *TestSuite) TestS3StatObject(c *C) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if _, ok := r.URL.Query()["location"]; ok {
+			w.Write([]byte("<LocationConstraint xmlns=\"http://doc.s3.amazonaws.com/2006-03-01\"></LocationConstraint>"))
+			return
+		}
+		// Prefixes are only found by listing.
+		if r.Method == "GET" && r.URL.Path == "/bucket/" && r.URL.Query().Get("prefix") == "dir" {
+			w.Write([]byte("<ListBucketResult><IsTruncated>false</IsTruncated><CommonPrefixes><Prefix>dir/</Prefix></CommonPrefixes></ListBucketResult>"))
+			return
+		}
+		if r.Method != "HEAD" || r.URL.Path != "/bucket/object" {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		if r.URL.Query().Get("versionId") == "missing" {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		w.Header().Set("ETag", "\"9af2f8218b150c351ad802c6f3d66abe\"")
+		w.Header().Set("Last-Modified", "Thu, 01 Jun 2017 10:00:00 GMT")
+		w.Header().Set("Content-Length", "5")
+		w.Header().Set("Content-Type", "text/plain")
+		w.Header().Set("X-Amz-Meta-Project", "apollo")
+		w.Header().Set("X-Amz-Version-Id", "v1")
+		w.Header().Set("X-Amz-Server-Side-Encryption", "aws:kms")
+		w.Header().Set("X-Amz-Server-Side-Encryption-Aws-Kms-Key-Id", "key-1")
+	}))
+	defer server.Close()
+
+	conf := new(Config)
+	conf.HostURL = server.URL + "/bucket/object"
+	conf.AccessKey = "WLGDGYAQYIGI833EV05A"
+	conf.SecretKey = "BYvgJM101sHngl2uzjXS/OBF/aMxAN06JrJ3qJlF"
+	conf.Signature = "S3v4"
+	s3c, err := s3New(conf)
+	c.Assert(err, IsNil)
+
+	info, err := s3c.(*s3Client).StatObject("", EncryptOpts{})
+	c.Assert(err, IsNil)
+	c.Assert(info, DeepEquals, &objectInfo{
+		Size:         5,
+		LastModified: time.Date(2017, 6, 1, 10, 0, 0, 0, time.UTC),
+		ETag:         "9af2f8218b150c351ad802c6f3d66abe",
+		ContentType:  "text/plain",
+		StorageClass: "STANDARD",
+		VersionID:    "v1",
+		Encryption:   "aws:kms",
+		KMSKeyID:     "key-1",
+		Metadata:     map[string]string{"X-Amz-Meta-Project": "apollo"},
+	})
+
+	_, err = s3c.(*s3Client).StatObject("missing", EncryptOpts{})
+	c.Assert(err, NotNil)
+	_, ok := err.ToGoError().(ObjectVersionMissing)
+	c.Assert(ok, Equals, true)
+
+	// Objects are stated with a HEAD request, prefixes by listing them.
+	content, err := s3c.Stat()
+	c.Assert(err, IsNil)
+	c.Assert(content.Size, Equals, int64(5))
+	c.Assert(content.Time, Equals, time.Date(2017, 6, 1, 10, 0, 0, 0, time.UTC))
+	c.Assert(content.Type.IsRegular(), Equals, true)
+
+	conf.HostURL = server.URL + "/bucket/dir"
+	s3c, err = s3New(conf)
+	c.Assert(err, IsNil)
+	content, err = s3c.Stat()
+	c.Assert(err, IsNil)
+	c.Assert(content.Type.IsDir(), Equals, true)
+}
+
+// bucketsHandler is an http.Handler which serves buckets holding two
+// objects each, earlier buckets are listed slower.
+type bucketsHandler struct {
+	buckets []string
+}
+
+func (h bucketsHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	_, isLocation := r.URL.Query()["location"]
+	switch {
+	case isLocation:
+		w.Write([]byte("<LocationConstraint xmlns=\"http://doc.s3.amazonaws.com/2006-03-01\"></LocationConstraint>"))
+	case r.Method == "GET" && r.URL.Path == "/":
+		var buckets bytes.Buffer
+		for _, bucket := range h.buckets {
+			buckets.WriteString("<Bucket><Name>" + bucket + "</Name><CreationDate>2016-09-01T10:00:00.000Z</CreationDate></Bucket>")
+		}
+		w.Write([]byte("<ListAllMyBucketsResult><Buckets>" + buckets.String() + "</Buckets></ListAllMyBucketsResult>"))
+	case r.Method == "GET":
+		bucket := strings.Trim(r.URL.Path, "/")
+		for i, b := range h.buckets {
+			if b == bucket {
+				time.Sleep(time.Duration(len(h.buckets)-i) * 10 * time.Millisecond)
+			}
+		}
+		w.Write([]byte("<ListBucketResult><Name>" + bucket + "</Name><IsTruncated>false</IsTruncated>" +
+			"<Contents><Key>a</Key><LastModified>2016-09-01T10:00:00.000Z</LastModified><Size>1</Size></Contents>" +
+			"<Contents><Key>b</Key><LastModified>2016-09-01T10:00:00.000Z</LastModified><Size>2</Size></Contents>" +
+			"</ListBucketResult>"))
+	default:
+		w.WriteHeader(http.StatusNotFound)
+	}
+}
+
+// Test listing all buckets recursively, several buckets at once.
+func (s *TestSuite) TestListBucketsRecursive(c *C) {
+	handler := bucketsHandler{buckets: []string{"bucket1", "bucket2", "bucket3", "bucket4", "bucket5"}}
+	server := httptest.NewServer(handler)
+	defer server.Close()
+
+	var expected []string
+	for _, bucket := range handler.buckets {
+		expected = append(expected, "/"+bucket, "/"+bucket+"/a", "/"+bucket+"/b")
+	}
+
+	list := func(opts BucketListOpts) []string {
+		conf := new(Config)
+		conf.HostURL = server.URL
+		conf.AccessKey = "WLGDGYAQYIGI833EV05A"
+		conf.SecretKey = "BYvgJM101sHngl2uzjXS/OBF/aMxAN06JrJ3qJlF"
+		conf.Signature = "S3v4"
+		conf.BucketListing = opts
+		s3c, err := s3New(conf)
+		c.Assert(err, IsNil)
+		var paths []string
+		for content := range s3c.List(context.Background(), true, false) {
+			c.Assert(content.Err, IsNil)
+			paths = append(paths, filepath.ToSlash(content.URL.Path))
+		}
+		return paths
+	}
+
+	c.Assert(list(BucketListOpts{}), DeepEquals, expected)
+	c.Assert(list(BucketListOpts{Parallel: 3}), DeepEquals, expected)
+
+	// Interleaved listings send the contents of the faster later
+	// buckets first.
+	paths := list(BucketListOpts{Parallel: 5, Interleaved: true})
+	c.Assert(paths, Not(DeepEquals), expected)
+	sort.Strings(paths)
+	c.Assert(paths, DeepEquals, expected)
+}
+
+// listTypeHandler is an http.Handler which lists a bucket holding a
+// single object, hosts without ListObjectsV2 ignore the list type or
+// deny it.
+type listTypeHandler struct {
+	supportsV2 bool
+	deniesV2   bool
+	mutex      *sync.Mutex
+	listTypes  *[]string
+}
+
+func (h listTypeHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	_, isLocation := r.URL.Query()["location"]
+	switch {
+	case isLocation:
+		w.Write([]byte("<LocationConstraint xmlns=\"http://doc.s3.amazonaws.com/2006-03-01\"></LocationConstraint>"))
+	case r.Method == "GET" && r.URL.Path == "/bucket/":
+		listType := r.URL.Query().Get("list-type")
+		h.mutex.Lock()
+		*h.listTypes = append(*h.listTypes, listType)
+		h.mutex.Unlock()
+		if h.deniesV2 && listType == "2" {
+			w.WriteHeader(http.StatusForbidden)
+			w.Write([]byte("<Error><Code>AccessDenied</Code><Message>Access Denied.</Message></Error>"))
+			return
+		}
+		keyCount := ""
+		if h.supportsV2 && listType == "2" {
+			keyCount = "<KeyCount>1</KeyCount>"
+		}
+		w.Write([]byte("<ListBucketResult><Name>bucket</Name>" + keyCount + "<IsTruncated>false</IsTruncated>" +
+			"<Contents><Key>object</Key><LastModified>2016-09-01T10:00:00.000Z</LastModified><Size>1</Size></Contents>" +
+			"</ListBucketResult>"))
+	default:
+		w.WriteHeader(http.StatusNotFound)
+	}
+}
+
+// endlessListHandler is an http.Handler listing pages of objects of
+// "bucket" with ListObjects until ended, counting the pages listed.
+type endlessListHandler struct {
+	sync.Mutex
+	pageSize int
+	pages    int
+	ended    bool
+}
+
+func (h *endlessListHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if _, ok := r.URL.Query()["location"]; ok || r.Method != "GET" || r.URL.Path != "/bucket/" {
+		w.Write([]byte("<LocationConstraint></LocationConstraint>"))
+		return
+	}
+	h.Lock()
+	h.pages++
+	page, ended := h.pages, h.ended
+	h.Unlock()
+	if ended {
+		w.Write([]byte("<ListBucketResult><Name>bucket</Name><IsTruncated>false</IsTruncated></ListBucketResult>"))
+		return
+	}
+	var body bytes.Buffer
+	body.WriteString("<ListBucketResult><Name>bucket</Name><IsTruncated>true</IsTruncated>")
+	for i := 0; i < h.pageSize; i++ {
+		fmt.Fprintf(&body, "<Contents><Key>%06d-%04d</Key><Size>1</Size><LastModified>2015-05-21T18:24:21.097Z</LastModified></Contents>", page, i)
+	}
+	fmt.Fprintf(&body, "<NextMarker>%06d-%04d</NextMarker></ListBucketResult>", page, h.pageSize-1)
+	w.Write(body.Bytes())
+}
+
+// listed - number of pages listed so far.
+func (h *endlessListHandler) listed() int {
+	h.Lock()
+	defer h.Unlock()
+	return h.pages
+}
+
+// end - ends the listings with an empty page.
+func (h *endlessListHandler) end() {
+	h.Lock()
+	defer h.Unlock()
+	h.ended = true
+}
+
+// Test listing objects with ListObjectsV2 on hosts supporting it.
+func (s *TestSuite) TestListObjectsV2Detection(c *C) {
+	testCases := []struct {
+		supportsV2 bool
+		deniesV2   bool
+		listTypes  []string
+	}{
+		{true, false, []string{"2", "2", "2"}},
+		{false, false, []string{"2", "", ""}},
+		// Failed probes are not remembered.
+		{false, true, []string{"2", "", "2", ""}},
+	}
+	for i, testCase := range testCases {
+		var listTypes []string
+		server := httptest.NewServer(listTypeHandler{supportsV2: testCase.supportsV2, deniesV2: testCase.deniesV2, mutex: &sync.Mutex{}, listTypes: &listTypes})
+
+		conf := new(Config)
+		conf.HostURL = server.URL + "/bucket/"
+		conf.AccessKey = "WLGDGYAQYIGI833EV05A"
+		conf.SecretKey = "BYvgJM101sHngl2uzjXS/OBF/aMxAN06JrJ3qJlF"
+		conf.Signature = "S3v4"
+		s3c, err := s3New(conf)
+		c.Assert(err, IsNil)
+
+		// The host is only probed until it answered.
+		for n := 0; n < 2; n++ {
+			var contents []*ClientContent
+			for content := range s3c.List(context.Background(), true, false) {
+				c.Assert(content.Err, IsNil)
+				contents = append(contents, content)
+			}
+			c.Assert(len(contents), Equals, 1, Commentf("Test %d", i+1))
+		}
+		c.Assert(listTypes, DeepEquals, testCase.listTypes, Commentf("Test %d", i+1))
+		server.Close()
+	}
+}
+
+// Test sharing clients of equal settings.
+func (s *TestSuite) TestClientCache(c *C) {
+	newClient, invalidate := newFactory()
+	conf := &Config{HostURL: "http://localhost:9000/bucket", AccessKey: "WLGDGYAQYIGI833EV05A", SecretKey: "BYvgJM101sHngl2uzjXS/OBF/aMxAN06JrJ3qJlF", Signature: "S3v4"}
+	api := func(conf *Config) *minio.Client {
+		clnt, err := newClient(conf)
+		c.Assert(err, IsNil)
+		return clnt.(*s3Client).api
+	}
+	shared := api(conf)
+
+	// Targets of the same host share the client.
+	other := *conf
+	other.HostURL = "http://localhost:9000/other"
+	c.Assert(api(&other) == shared, Equals, true)
+
+	changes := []func(conf *Config){
+		func(conf *Config) { conf.HostURL = "https://localhost:9000/bucket" },
+		func(conf *Config) { conf.Signature = "S3v2" },
+		func(conf *Config) { conf.Insecure = true },
+		func(conf *Config) { conf.Debug = true },
+		func(conf *Config) { conf.Transport.DialTimeout = time.Second },
+		func(conf *Config) { conf.Region = "eu-west-1" },
+	}
+	for i, change := range changes {
+		changed := *conf
+		change(&changed)
+		c.Assert(api(&changed) == shared, Equals, false, Commentf("Test %d", i+1))
+	}
+
+	// Dropped clients are set up anew.
+	invalidate(conf)
+	c.Assert(api(conf) == shared, Equals, false)
+	shared = api(conf)
+	invalidate(nil)
+	c.Assert(api(conf) == shared, Equals, false)
+}
+
+// Test merging of bucket notifications.
+func (s *TestSuite) TestMergeNotificationConfig(c *C) {
+	queue := minio.NewArn("aws", "sqs", "us-east-1", "1", "queue")
+	newConfig := func(arn minio.Arn, prefix string, events ...minio.NotificationEventType) minio.NotificationConfig {
+		nc := minio.NewNotificationConfig(arn)
+		nc.AddEvents(events...)
+		if prefix != "" {
+			nc.AddFilterPrefix(prefix)
+		}
+		return nc
+	}
+
+	// Configurations of the server have no parsed ARN.
+	existing := newConfig(minio.Arn{}, "photos/", minio.ObjectCreatedAll)
+	mb := minio.BucketNotification{QueueConfigs: []minio.QueueConfig{{NotificationConfig: existing, Queue: queue.String()}}}
+
+	c.Assert(mergeNotificationConfig(&mb, newConfig(queue, "photos/", minio.ObjectCreatedAll)), Equals, notificationUnchanged)
+	c.Assert(len(mb.QueueConfigs), Equals, 1)
+
+	c.Assert(mergeNotificationConfig(&mb, newConfig(queue, "photos/", minio.ObjectCreatedAll, minio.ObjectRemovedAll)), Equals, notificationUpdated)
+	c.Assert(len(mb.QueueConfigs), Equals, 1)
+	c.Assert(mb.QueueConfigs[0].Events, DeepEquals, []minio.NotificationEventType{minio.ObjectCreatedAll, minio.ObjectRemovedAll})
+
+	// Other filters and ARNs are added.
+	c.Assert(mergeNotificationConfig(&mb, newConfig(queue, "videos/", minio.ObjectCreatedAll)), Equals, notificationAdded)
+	c.Assert(len(mb.QueueConfigs), Equals, 2)
+	topic := minio.NewArn("aws", "sns", "us-east-1", "1", "topic")
+	c.Assert(mergeNotificationConfig(&mb, newConfig(topic, "photos/", minio.ObjectCreatedAll)), Equals, notificationAdded)
+	c.Assert(len(mb.TopicConfigs), Equals, 1)
+	c.Assert(mb.TopicConfigs[0].Topic, Equals, topic.String())
+}
+
+// notificationHandler - drops the first stream of bucket notifications,
+// the following streams replay events before sending new ones.
+type notificationHandler struct {
+	mutex   *sync.Mutex
+	streams *int
+	// Ends the last stream.
+	quit chan struct{}
+}
+
+func (h notificationHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	_, isLocation := r.URL.Query()["location"]
+	_, isEvents := r.URL.Query()["events"]
+	record := func(key, sequencer string) string {
+		return `{"Records":[{"eventName":"s3:ObjectCreated:Put","requestParameters":{"sourceIPAddress":"10.0.0.1"},` +
+			`"s3":{"bucket":{"name":"bucket"},"object":{"key":"` + key + `","size":1,"eTag":"etag-` + key + `","sequencer":"` + sequencer + `"}}}]}` + "\n"
+	}
+	switch {
+	case isLocation:
+		w.Write([]byte("<LocationConstraint xmlns=\"http://doc.s3.amazonaws.com/2006-03-01\"></LocationConstraint>"))
+	case r.Method == "HEAD":
+	case r.Method == "GET" && isEvents:
+		h.mutex.Lock()
+		*h.streams++
+		stream := *h.streams
+		h.mutex.Unlock()
+		switch stream {
+		case 1:
+			w.WriteHeader(http.StatusGatewayTimeout)
+		case 2:
+			w.Write([]byte(record("a", "1")))
+		default:
+			w.Write([]byte(record("a", "1") + record("b", "2")))
+			w.(http.Flusher).Flush()
+			<-h.quit
+		}
+	default:
+		w.WriteHeader(http.StatusNotFound)
+	}
+}
+
+// Test reconnecting dropped watches without repeating events.
+func (s *TestSuite) TestWatchReconnect(c *C) {
+	retryDelay := watchRetryDelay
+	watchRetryDelay = time.Millisecond
+	defer func() { watchRetryDelay = retryDelay }()
+
+	streams := 0
+	quit := make(chan struct{})
+	server := httptest.NewServer(notificationHandler{mutex: &sync.Mutex{}, streams: &streams, quit: quit})
+	defer server.Close()
+	defer close(quit)
+
+	conf := new(Config)
+	conf.HostURL = server.URL + "/bucket"
+	conf.AccessKey = "WLGDGYAQYIGI833EV05A"
+	conf.SecretKey = "BYvgJM101sHngl2uzjXS/OBF/aMxAN06JrJ3qJlF"
+	conf.Signature = "S3v4"
+	s3c, err := s3New(conf)
+	c.Assert(err, IsNil)
+
+	wo, err := s3c.Watch(WatchParams{Events: []string{"put"}})
+	c.Assert(err, IsNil)
+	defer wo.Close()
+
+	status := <-wo.Status()
+	c.Assert(status.Connected, Equals, false)
+	c.Assert(status.Err, NotNil)
+	c.Assert(status.Delay, Equals, time.Millisecond)
+	status = <-wo.Status()
+	c.Assert(status.Connected, Equals, true)
+
+	var keys []string
+	for len(keys) < 2 {
+		select {
+		case event := <-wo.Events():
+			if len(keys) == 0 {
+				// Details of the record are kept.
+				c.Assert(event.Bucket, Equals, "bucket")
+				c.Assert(event.Key, Equals, "a")
+				c.Assert(event.Size, Equals, int64(1))
+				c.Assert(event.ETag, Equals, "etag-a")
+				c.Assert(event.Name, Equals, "s3:ObjectCreated:Put")
+				c.Assert(event.SourceIP, Equals, "10.0.0.1")
+				c.Assert(event.Sequencer, Equals, "1")
+			}
+			keys = append(keys, event.Path)
+		case err := <-wo.Errors():
+			c.Fatal(err)
+		case <-time.After(5 * time.Second):
+			c.Fatal("Timed out waiting for events.")
+		}
+	}
+	c.Assert(keys, DeepEquals, []string{server.URL + "/bucket/a", server.URL + "/bucket/b"})
+
+	// Final errors are not retried.
+	c.Assert(isWatchErrorTemporary(minio.ErrorResponse{Code: "NoSuchBucket"}), Equals, false)
+	c.Assert(isWatchErrorTemporary(minio.ErrorResponse{Code: "503 Service Unavailable"}), Equals, true)
+	c.Assert(isWatchErrorTemporary(errors.New("connection refused")), Equals, true)
+}
+
+// bucketNotificationHandler - stores the notification configuration of
+// a bucket, listening on events is not supported.
+type bucketNotificationHandler struct {
+	mutex  *sync.Mutex
+	config *string
+	puts   *int
+}
+
+func (h bucketNotificationHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	_, isLocation := r.URL.Query()["location"]
+	_, isNotification := r.URL.Query()["notification"]
+	h.mutex.Lock()
+	defer h.mutex.Unlock()
+	switch {
+	case isLocation:
+		w.Write([]byte("<LocationConstraint xmlns=\"http://doc.s3.amazonaws.com/2006-03-01\"></LocationConstraint>"))
+	case isNotification && r.Method == "GET":
+		w.Write([]byte(*h.config))
+	case isNotification && r.Method == "PUT":
+		body, _ := ioutil.ReadAll(r.Body)
+		*h.config = string(body)
+		*h.puts++
+	default:
+		w.WriteHeader(http.StatusNotImplemented)
+		w.Write([]byte("<Error><Code>NotImplemented</Code></Error>"))
+	}
+}
+
+// Test removing notifications registered by watches.
+func (s *TestSuite) TestUnwatch(c *C) {
+	config := "<NotificationConfiguration><QueueConfiguration><Id>user</Id><Queue>arn:minio:sqs:us-east-1:1:webhook</Queue>" +
+		"<Event>s3:ObjectRemoved:*</Event></QueueConfiguration></NotificationConfiguration>"
+	puts := 0
+	mutex := &sync.Mutex{}
+	server := httptest.NewServer(bucketNotificationHandler{mutex: mutex, config: &config, puts: &puts})
+	defer server.Close()
+
+	conf := new(Config)
+	conf.HostURL = server.URL + "/bucket"
+	conf.AccessKey = "WLGDGYAQYIGI833EV05A"
+	conf.SecretKey = "BYvgJM101sHngl2uzjXS/OBF/aMxAN06JrJ3qJlF"
+	conf.Signature = "S3v4"
+	s3c, err := s3New(conf)
+	c.Assert(err, IsNil)
+
+	params := WatchParams{AccountID: "42", Events: []string{"put"}, ARN: "arn:minio:sqs:us-east-1:1:webhook"}
+	wo, err := s3c.Watch(params)
+	c.Assert(err, IsNil)
+	defer wo.Close()
+
+	queues := func() []minio.QueueConfig {
+		mutex.Lock()
+		defer mutex.Unlock()
+		mb := minio.BucketNotification{}
+		c.Assert(xml.Unmarshal([]byte(config), &mb), IsNil)
+		return mb.QueueConfigs
+	}
+	c.Assert(len(queues()), Equals, 2)
+	c.Assert(queues()[1].Id, Equals, "mc-watch-42")
+	c.Assert(queues()[1].Events, DeepEquals, []minio.NotificationEventType{minio.ObjectCreatedAll})
+
+	// Only the notification of the watch is removed, and only once.
+	c.Assert(s3c.Unwatch(params), IsNil)
+	c.Assert(len(queues()), Equals, 1)
+	c.Assert(queues()[0].Id, Equals, "user")
+	c.Assert(s3c.Unwatch(params), IsNil)
+	c.Assert(puts, Equals, 2)
+}
+
+// Test round trips of notification configurations through export and
+// import.
+func (s *TestSuite) TestNotificationConfigExportImport(c *C) {
+	config := "<NotificationConfiguration><QueueConfiguration><Id>queue</Id><Queue>arn:minio:sqs:us-east-1:1:webhook</Queue>" +
+		"<Event>s3:ObjectCreated:*</Event><Filter><S3Key><FilterRule><Name>prefix</Name><Value>photos/</Value></FilterRule></S3Key></Filter>" +
+		"</QueueConfiguration><TopicConfiguration><Id>topic</Id><Topic>arn:aws:sns:us-east-1:1:topic</Topic>" +
+		"<Event>s3:ObjectRemoved:*</Event></TopicConfiguration></NotificationConfiguration>"
+	imported := "<NotificationConfiguration></NotificationConfiguration>"
+	puts := 0
+	var servers []*httptest.Server
+	defer func() {
+		for _, server := range servers {
+			server.Close()
+		}
+	}()
+	newBucket := func(config *string) *s3Client {
+		server := httptest.NewServer(bucketNotificationHandler{mutex: &sync.Mutex{}, config: config, puts: &puts})
+		servers = append(servers, server)
+		conf := new(Config)
+		conf.HostURL = server.URL + "/bucket"
+		conf.AccessKey = "WLGDGYAQYIGI833EV05A"
+		conf.SecretKey = "BYvgJM101sHngl2uzjXS/OBF/aMxAN06JrJ3qJlF"
+		conf.Signature = "S3v4"
+		s3c, err := s3New(conf)
+		c.Assert(err, IsNil)
+		return s3c.(*s3Client)
+	}
+	source, target := newBucket(&config), newBucket(&imported)
+
+	mb, err := source.ExportNotificationConfig()
+	c.Assert(err, IsNil)
+	for _, format := range []string{"json", "xml"} {
+		msg := eventsExportMessage{Config: newBucketNotificationJSON(mb), notification: mb, format: format}
+		parsed, err := parseBucketNotification([]byte(msg.String()))
+		c.Assert(err, IsNil)
+		c.Assert(target.ImportNotificationConfig(parsed), IsNil)
+
+		got, err := target.ExportNotificationConfig()
+		c.Assert(err, IsNil)
+		c.Assert(len(got.QueueConfigs), Equals, 1)
+		c.Assert(got.QueueConfigs[0].Id, Equals, "queue")
+		c.Assert(got.QueueConfigs[0].Queue, Equals, "arn:minio:sqs:us-east-1:1:webhook")
+		c.Assert(got.QueueConfigs[0].Events, DeepEquals, []minio.NotificationEventType{minio.ObjectCreatedAll})
+		c.Assert(notificationFilter(got.QueueConfigs[0].NotificationConfig), Equals, [2]string{"photos/", ""})
+		c.Assert(len(got.TopicConfigs), Equals, 1)
+		c.Assert(got.TopicConfigs[0].Topic, Equals, "arn:aws:sns:us-east-1:1:topic")
+		c.Assert(got.TopicConfigs[0].Filter, IsNil)
+	}
+	c.Assert(puts, Equals, 2)
+
+	// Configurations without events or of malformed ARNs are rejected.
+	_, err = parseBucketNotification([]byte(`{"QueueConfigurations":[{"QueueArn":"arn:minio:sqs:us-east-1:1:webhook"}]}`))
+	c.Assert(err, NotNil)
+	_, err = parseBucketNotification([]byte(`{"TopicConfigurations":[{"TopicArn":"topic","Events":["s3:ObjectCreated:*"]}]}`))
+	c.Assert(err, NotNil)
+	_, err = parseBucketNotification([]byte(`not a configuration`))
+	c.Assert(err, NotNil)
+	mb, err = parseBucketNotification([]byte(`{}`))
+	c.Assert(err, IsNil)
+	c.Assert(notificationConfigsCount(mb), Equals, 0)
+}
+
+// Test errors of ARNs naming the wrong field and of targets rejected by
+// servers.
+func (s *TestSuite) TestNotificationArn(c *C) {
+	arn, err := parseNotificationArn("arn:minio:sqs::1:webhook")
+	c.Assert(err, IsNil)
+	c.Assert(arn, Equals, minio.NewArn("minio", "sqs", "", "1", "webhook"))
+
+	testCases := []struct {
+		arn    string
+		reason string
+	}{
+		{"arn:minio:sqs:us-east-1:webhook", "5 fields"},
+		{"urn:minio:sqs:us-east-1:1:webhook", "start with ‘arn’"},
+		{"arn::sqs:us-east-1:1:webhook", "partition"},
+		{"arn:minio:s3:us-east-1:1:webhook", "service ‘s3’"},
+		{"arn:minio:sqs:us-east-1::webhook", "account ID"},
+		{"arn:minio:sqs:us-east-1:1:", "resource"},
+	}
+	for _, testCase := range testCases {
+		_, err := parseNotificationArn(testCase.arn)
+		c.Assert(err, NotNil)
+		c.Assert(strings.Contains(err.ToGoError().Error(), testCase.reason), Equals, true, Commentf("%s: %s", testCase.arn, err))
+	}
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, isLocation := r.URL.Query()["location"]
+		switch {
+		case isLocation:
+			w.Write([]byte("<LocationConstraint xmlns=\"http://doc.s3.amazonaws.com/2006-03-01\"></LocationConstraint>"))
+		case r.Method == "GET":
+			w.Write([]byte("<NotificationConfiguration></NotificationConfiguration>"))
+		default:
+			w.WriteHeader(http.StatusBadRequest)
+			w.Write([]byte("<Error><Code>InvalidArgument</Code><Message>A specified destination ARN does not exist.</Message></Error>"))
+		}
+	}))
+	defer server.Close()
+
+	conf := new(Config)
+	conf.HostURL = server.URL + "/bucket"
+	conf.AccessKey = "WLGDGYAQYIGI833EV05A"
+	conf.SecretKey = "BYvgJM101sHngl2uzjXS/OBF/aMxAN06JrJ3qJlF"
+	conf.Signature = "S3v4"
+	s3c, err := s3New(conf)
+	c.Assert(err, IsNil)
+
+	_, err = s3c.(*s3Client).AddNotificationConfig([]string{"arn:minio:sqs:us-east-1:1:webhook"}, []string{"put"}, "", "")
+	c.Assert(err, NotNil)
+	c.Assert(strings.Contains(err.ToGoError().Error(), "‘arn:minio:sqs:us-east-1:1:webhook’ was rejected: A specified destination ARN does not exist."), Equals, true)
+	_, err = s3c.(*s3Client).AddNotificationConfig([]string{"arn:minio:sqs:us-east-1:webhook"}, []string{"put"}, "", "")
+	c.Assert(err, NotNil)
+}
@@ -0,0 +1,67 @@
+/*
+ * Minio Client (C) 2016 Minio, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"strings"
+	"sync"
+
+	"github.com/minio/minio/pkg/probe"
+)
+
+// ClientFactory - creates the client of a URL.
+type ClientFactory func(urlStr string) (Client, *probe.Error)
+
+// Clients of URLs without an alias by scheme, URLs of these schemes
+// name a host, e.g. ‘sftp://user@host/path’. Built in schemes are
+// registered on first use.
+var (
+	clientSchemes     map[string]ClientFactory
+	clientSchemesOnce sync.Once
+	clientSchemesMu   sync.RWMutex
+)
+
+// initClientSchemes - registers the built in schemes.
+func initClientSchemes() {
+	clientSchemesOnce.Do(func() {
+		clientSchemes = map[string]ClientFactory{
+			"http":  httpNew,
+			"https": httpNew,
+			"sftp":  sftpNew,
+			"bench": benchNew,
+		}
+	})
+}
+
+// RegisterClientScheme - serves URLs of the scheme without an alias
+// with clients of the factory, replacing an earlier registration.
+// Programs importing this package register their own clients before
+// running the commands.
+func RegisterClientScheme(scheme string, factory ClientFactory) {
+	initClientSchemes()
+	clientSchemesMu.Lock()
+	defer clientSchemesMu.Unlock()
+	clientSchemes[strings.ToLower(scheme)] = factory
+}
+
+// lookupClientScheme - factory of the scheme, nil if not registered.
+func lookupClientScheme(scheme string) ClientFactory {
+	initClientSchemes()
+	clientSchemesMu.RLock()
+	defer clientSchemesMu.RUnlock()
+	return clientSchemes[strings.ToLower(scheme)]
+}
@@ -0,0 +1,586 @@
+/*
+ * Minio Client (C) 2016 Minio, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"context"
+	"io"
+	"net/url"
+	"os"
+	"path"
+	"strings"
+	"time"
+
+	"github.com/minio/mc/pkg/hookreader"
+	"github.com/minio/minio/pkg/probe"
+)
+
+// SFTP client, URLs are of the form ‘sftp://user@host:port/path’.
+// Paths starting with ‘/~/’ are relative to the home directory of the
+// user.
+type sftpClient struct {
+	targetURL *ClientURL
+	conn      *sftpConn
+}
+
+// Name of the API in errors of unsupported operations.
+const sftpAPIType = "SFTP"
+
+// sftpNew returns an initialized sftpClient, sharing the session with
+// other clients of the host.
+func sftpNew(urlStr string) (Client, *probe.Error) {
+	targetURL := newClientURL(urlStr)
+	if targetURL.Host == "" {
+		return nil, errInvalidArgument().Trace(urlStr)
+	}
+	conn, err := sftpDial(targetURL.Host)
+	if err != nil {
+		return nil, err.Trace(urlStr)
+	}
+	return &sftpClient{targetURL: targetURL, conn: conn}, nil
+}
+
+// GetURL get url.
+func (c *sftpClient) GetURL() ClientURL {
+	return *c.targetURL
+}
+
+// remotePath - path of the URL path on the server.
+func (c *sftpClient) remotePath(urlPath string) string {
+	if urlPath == "/~" || urlPath == "/~/" {
+		return "."
+	}
+	if strings.HasPrefix(urlPath, "/~/") {
+		return urlPath[len("/~/"):]
+	}
+	return urlPath
+}
+
+// path - path of the target on the server.
+func (c *sftpClient) path() string {
+	return c.remotePath(c.targetURL.Path)
+}
+
+// withPath - client of another path on the same host.
+func (c *sftpClient) withPath(urlPath string) *sftpClient {
+	targetURL := *c.targetURL
+	targetURL.Path = urlPath
+	return &sftpClient{targetURL: &targetURL, conn: c.conn}
+}
+
+// toClientError - translates status errors of the server.
+func (c *sftpClient) toClientError(e error, fpath string) *probe.Error {
+	if status, ok := e.(sftpStatusError); ok {
+		switch status.Code {
+		case sftpStatusNoSuchFile:
+			return probe.NewError(PathNotFound{Path: fpath})
+		case sftpStatusPermissionDenied:
+			return probe.NewError(PathInsufficientPermission{Path: fpath})
+		}
+	}
+	return probe.NewError(e)
+}
+
+// notImplemented - error of operations SFTP does not support.
+func (c *sftpClient) notImplemented(api string) *probe.Error {
+	return probe.NewError(APINotImplemented{
+		API:     api,
+		APIType: sftpAPIType,
+	})
+}
+
+// content - listed content of the URL path.
+func (c *sftpClient) content(urlPath string, attrs sftpAttrs) *ClientContent {
+	contentURL := *c.targetURL
+	contentURL.Path = urlPath
+	content := &ClientContent{
+		URL:  contentURL,
+		Time: attrs.modTime(),
+		Type: attrs.mode(),
+	}
+	if !content.Type.IsDir() {
+		content.Size = int64(attrs.Size)
+	}
+	return content
+}
+
+// Stat - attributes of the file or directory, links are followed.
+func (c *sftpClient) Stat() (*ClientContent, *probe.Error) {
+	fpath := c.path()
+	attrs, e := c.conn.stat(fpath)
+	if e != nil {
+		return nil, c.toClientError(e, fpath).Trace(fpath)
+	}
+	return c.content(c.targetURL.Path, attrs), nil
+}
+
+// List - list files and directories, incomplete uploads are not
+// listed. Like on the filesystem a path which does not exist is a
+// prefix of the names in its directory, and a directory without a
+// trailing separator lists only itself unless listed recursively.
+func (c *sftpClient) List(ctx context.Context, recursive, incomplete bool) <-chan *ClientContent {
+	contentCh := make(chan *ClientContent)
+	if incomplete {
+		close(contentCh)
+		return contentCh
+	}
+	go c.listInRoutine(ctx, contentCh, recursive)
+	return contentCh
+}
+
+func (c *sftpClient) listInRoutine(ctx context.Context, contentCh chan<- *ClientContent, recursive bool) {
+	defer close(contentCh)
+	urlPath := c.targetURL.Path
+	attrs, e := c.conn.stat(c.path())
+	if e != nil {
+		err := c.toClientError(e, urlPath)
+		if _, ok := err.ToGoError().(PathNotFound); !ok {
+			contentCh <- &ClientContent{Err: err.Trace(urlPath)}
+			return
+		}
+		// List the names of the directory with the prefix.
+		dir, prefix := path.Split(urlPath)
+		entries, e := c.conn.readDir(c.remotePath(dir))
+		if e != nil {
+			contentCh <- &ClientContent{Err: c.toClientError(e, dir).Trace(dir)}
+			return
+		}
+		for _, entry := range entries {
+			if strings.HasPrefix(entry.Name, prefix) {
+				c.listEntry(ctx, contentCh, dir+entry.Name, entry.Attrs, recursive)
+			}
+		}
+		return
+	}
+	if !attrs.mode().IsDir() {
+		contentCh <- c.content(urlPath, attrs)
+		return
+	}
+	if !recursive && !strings.HasSuffix(urlPath, "/") {
+		contentCh <- c.content(urlPath, attrs)
+		return
+	}
+	c.listDir(ctx, contentCh, urlPath, recursive)
+}
+
+// listDir - lists the entries of the directory, and those of its
+// subdirectories if recursive. Listings stop once the context is done.
+func (c *sftpClient) listDir(ctx context.Context, contentCh chan<- *ClientContent, urlPath string, recursive bool) {
+	if e := ctx.Err(); e != nil {
+		contentCh <- &ClientContent{Err: probe.NewError(e).Trace(urlPath)}
+		return
+	}
+	dir := strings.TrimSuffix(urlPath, "/") + "/"
+	entries, e := c.conn.readDir(c.remotePath(dir))
+	if e != nil {
+		contentCh <- &ClientContent{Err: c.toClientError(e, urlPath).Trace(urlPath)}
+		return
+	}
+	for _, entry := range entries {
+		c.listEntry(ctx, contentCh, dir+entry.Name, entry.Attrs, recursive)
+	}
+}
+
+// listEntry - lists an entry of a directory, links are followed.
+// Recursive listings only hold files.
+func (c *sftpClient) listEntry(ctx context.Context, contentCh chan<- *ClientContent, urlPath string, attrs sftpAttrs, recursive bool) {
+	if attrs.mode()&os.ModeSymlink != 0 {
+		var e error
+		if attrs, e = c.conn.stat(c.remotePath(urlPath)); e != nil {
+			contentCh <- &ClientContent{Err: c.toClientError(e, urlPath).Trace(urlPath)}
+			return
+		}
+	}
+	if recursive && attrs.mode().IsDir() {
+		c.listDir(ctx, contentCh, urlPath, recursive)
+		return
+	}
+	contentCh <- c.content(urlPath, attrs)
+}
+
+// mkdirAll - creates the directory along with its parents.
+func (c *sftpClient) mkdirAll(dirPath string) error {
+	if attrs, e := c.conn.stat(dirPath); e == nil {
+		if !attrs.mode().IsDir() {
+			return sftpStatusError{Code: sftpStatusFailure, Message: "Not a directory: " + dirPath}
+		}
+		return nil
+	}
+	if parent := path.Dir(dirPath); parent != dirPath && parent != "." && parent != "/" {
+		if e := c.mkdirAll(parent); e != nil {
+			return e
+		}
+	}
+	if e := c.conn.mkdir(dirPath); e != nil {
+		// Created concurrently.
+		if attrs, se := c.conn.stat(dirPath); se == nil && attrs.mode().IsDir() {
+			return nil
+		}
+		return e
+	}
+	return nil
+}
+
+// MakeBucket - creates the directory along with its parents.
+func (c *sftpClient) MakeBucket(region string) *probe.Error {
+	fpath := c.path()
+	if e := c.mkdirAll(strings.TrimSuffix(fpath, "/")); e != nil {
+		return c.toClientError(e, fpath).Trace(fpath)
+	}
+	return nil
+}
+
+// Get - reads the file, data is requested ahead of reading.
+func (c *sftpClient) Get(ctx context.Context, opts GetOpts) (io.Reader, *probe.Error) {
+	if !opts.SSE.isEmpty() {
+		return nil, c.notImplemented("Encryption")
+	}
+	fpath := c.path()
+	handle, e := c.conn.open(fpath, sftpOpenRead)
+	if e != nil {
+		return nil, c.toClientError(e, fpath).Trace(fpath)
+	}
+	remaining := int64(-1)
+	if opts.Length > 0 {
+		remaining = opts.Length
+	}
+	return &sftpFileReader{
+		ctx:       ctx,
+		conn:      c.conn,
+		handle:    handle,
+		offset:    opts.Offset,
+		remaining: remaining,
+	}, nil
+}
+
+// sftpReadRequest - pending read of a range of the file.
+type sftpReadRequest struct {
+	offset  int64
+	length  int
+	replyCh <-chan sftpPacket
+}
+
+// sftpFileReader - reads an opened file, several reads are pending at
+// once to hide the latency of the server.
+type sftpFileReader struct {
+	// Reads fail once the context is done.
+	ctx    context.Context
+	conn   *sftpConn
+	handle string
+	// Offset of the next range to request, and the number of bytes
+	// left to request, negative until the end of the file.
+	offset    int64
+	remaining int64
+	pending   []sftpReadRequest
+	buf       []byte
+	err       error
+}
+
+// requestAhead - requests ranges until enough reads are pending.
+func (r *sftpFileReader) requestAhead() {
+	for len(r.pending) < sftpMaxPendingReqs && r.remaining != 0 {
+		length := sftpChunkSize
+		if r.remaining > 0 && r.remaining < int64(length) {
+			length = int(r.remaining)
+		}
+		r.pending = append(r.pending, sftpReadRequest{
+			offset:  r.offset,
+			length:  length,
+			replyCh: r.conn.sendRead(r.handle, r.offset, length),
+		})
+		r.offset += int64(length)
+		if r.remaining > 0 {
+			r.remaining -= int64(length)
+		}
+	}
+}
+
+func (r *sftpFileReader) Read(p []byte) (int, error) {
+	for len(r.buf) == 0 {
+		if r.err != nil {
+			return 0, r.err
+		}
+		if e := r.ctx.Err(); e != nil {
+			return 0, e
+		}
+		r.requestAhead()
+		if len(r.pending) == 0 {
+			r.err = io.EOF
+			continue
+		}
+		request := r.pending[0]
+		r.pending = r.pending[1:]
+		data, e := readData(<-request.replyCh)
+		if e != nil {
+			r.err = e
+			continue
+		}
+		r.buf = data
+		// Servers may return less data than requested, ranges
+		// requested later are discarded and requested again.
+		if len(data) < request.length {
+			r.pending = nil
+			if r.remaining >= 0 {
+				r.remaining += r.offset - request.offset - int64(len(data))
+			}
+			r.offset = request.offset + int64(len(data))
+		}
+	}
+	n := copy(p, r.buf)
+	r.buf = r.buf[n:]
+	return n, nil
+}
+
+// Close - closes the file, pending reads are dropped.
+func (r *sftpFileReader) Close() error {
+	r.pending = nil
+	if r.err == nil {
+		r.err = io.ErrClosedPipe
+	}
+	return r.conn.close(r.handle)
+}
+
+// Put - writes the file to a part file renamed once complete, writes
+// are pending at once like reads.
+func (c *sftpClient) Put(ctx context.Context, reader io.Reader, size int64, contentType string, progress io.Reader, opts PutOpts) (int64, *probe.Error) {
+	if !opts.SSE.isEmpty() {
+		return 0, c.notImplemented("Encryption")
+	}
+	fpath := c.path()
+	if strings.HasSuffix(fpath, "/") {
+		return 0, probe.NewError(PathIsNotRegular{Path: fpath})
+	}
+	if dir := path.Dir(fpath); dir != "." && dir != "/" {
+		if e := c.mkdirAll(dir); e != nil {
+			return 0, c.toClientError(e, dir).Trace(dir)
+		}
+	}
+	partPath := fpath + partSuffix
+	handle, e := c.conn.open(partPath, sftpOpenWrite|sftpOpenCreate|sftpOpenTrunc)
+	if e != nil {
+		return 0, c.toClientError(e, fpath).Trace(fpath)
+	}
+	n, e := c.writeFile(handle, newContextReader(ctx, hookreader.NewHook(reader, progress)))
+	if ce := c.conn.close(handle); e == nil {
+		e = ce
+	}
+	if e == nil && size >= 0 && n != size {
+		e = UnexpectedEOF{TotalSize: size, TotalWritten: n}
+	}
+	if e == nil {
+		// Renames do not replace existing files.
+		if re := c.conn.remove(fpath); re != nil {
+			if status, ok := re.(sftpStatusError); !ok || status.Code != sftpStatusNoSuchFile {
+				e = re
+			}
+		}
+	}
+	if e == nil {
+		e = c.conn.rename(partPath, fpath)
+	}
+	if e != nil {
+		c.conn.remove(partPath)
+		return n, c.toClientError(e, fpath).Trace(fpath)
+	}
+	return n, nil
+}
+
+// writeFile - writes the data read to an opened file.
+func (c *sftpClient) writeFile(handle string, reader io.Reader) (int64, error) {
+	var offset int64
+	var pending []<-chan sftpPacket
+	buf := make([]byte, sftpChunkSize)
+	for {
+		n, e := io.ReadFull(reader, buf)
+		if n > 0 {
+			if len(pending) == sftpMaxPendingReqs {
+				if we := (<-pending[0]).status(); we != nil {
+					return offset, we
+				}
+				pending = pending[1:]
+			}
+			pending = append(pending, c.conn.sendWrite(handle, offset, buf[:n]))
+			offset += int64(n)
+		}
+		if e == io.EOF || e == io.ErrUnexpectedEOF {
+			break
+		}
+		if e != nil {
+			return offset, e
+		}
+	}
+	for _, replyCh := range pending {
+		if e := (<-replyCh).status(); e != nil {
+			return offset, e
+		}
+	}
+	return offset, nil
+}
+
+// Copy - copies a file of the same host, data is streamed through the
+// client. Files have no ETags, only the modification time can be
+// checked.
+func (c *sftpClient) Copy(ctx context.Context, source string, size int64, progress io.Reader, opts CopyOpts) *probe.Error {
+	if opts.IfMatch != "" || opts.IfNoneMatch != "" {
+		return c.notImplemented("CopyConditions")
+	}
+	sourceClnt := c.withPath(source)
+	if !opts.IfUnmodifiedSince.IsZero() {
+		content, err := sourceClnt.Stat()
+		if err != nil {
+			return err.Trace(source)
+		}
+		if content.Time.After(opts.IfUnmodifiedSince) {
+			return probe.NewError(ObjectPreconditionFailed{Object: source})
+		}
+	}
+	reader, err := sourceClnt.Get(ctx, GetOpts{})
+	if err != nil {
+		return err.Trace(source)
+	}
+	defer reader.(io.Closer).Close()
+	if _, err = c.Put(ctx, reader, size, "", progress, PutOpts{}); err != nil {
+		return err.Trace(source)
+	}
+	return nil
+}
+
+// Checksum - ETag computed from the data of the file.
+func (c *sftpClient) Checksum(partSize int64) (string, int64, *probe.Error) {
+	if partSize < 0 {
+		partSize = 0
+	}
+	reader, err := c.Get(context.Background(), GetOpts{})
+	if err != nil {
+		return "", 0, err.Trace(c.path())
+	}
+	defer reader.(io.Closer).Close()
+	etag, err := readETag(reader, partSize)
+	if err != nil {
+		return "", 0, err.Trace(c.path())
+	}
+	return etag, partSize, nil
+}
+
+// Remove - removes the file or empty directory, incomplete uploads are
+// not kept.
+func (c *sftpClient) Remove(ctx context.Context, incomplete bool) *probe.Error {
+	if incomplete {
+		return nil
+	}
+	fpath := strings.TrimSuffix(c.path(), "/")
+	if e := ctx.Err(); e != nil {
+		return probe.NewError(e).Trace(fpath)
+	}
+	attrs, e := c.conn.stat(fpath)
+	if e == nil {
+		if attrs.mode().IsDir() {
+			e = c.conn.rmdir(fpath)
+		} else {
+			e = c.conn.remove(fpath)
+		}
+	}
+	if e != nil {
+		return c.toClientError(e, fpath).Trace(fpath)
+	}
+	return nil
+}
+
+// RemoveBulk - removes the files read from the channel one by one.
+func (c *sftpClient) RemoveBulk(contentCh <-chan *ClientContent) <-chan *ClientContent {
+	resultCh := make(chan *ClientContent)
+	go func() {
+		defer close(resultCh)
+		for content := range contentCh {
+			resultCh <- &ClientContent{URL: content.URL, Err: c.withPath(content.URL.Path).Remove(context.Background(), false)}
+		}
+	}()
+	return resultCh
+}
+
+// GetAccess - access policies not implemented.
+func (c *sftpClient) GetAccess() (string, *probe.Error) {
+	return "", c.notImplemented("GetAccess")
+}
+
+// GetAccessRules - access policies not implemented.
+func (c *sftpClient) GetAccessRules() (map[string]string, *probe.Error) {
+	return nil, c.notImplemented("GetAccessRules")
+}
+
+// SetAccess - access policies not implemented.
+func (c *sftpClient) SetAccess(access string) *probe.Error {
+	return c.notImplemented("SetAccess")
+}
+
+// SelectObjectContent - queries not implemented.
+func (c *sftpClient) SelectObjectContent(expression string, sse EncryptOpts, opts SelectObjectOpts) (io.ReadCloser, *probe.Error) {
+	return nil, c.notImplemented("SelectObjectContent")
+}
+
+// ShareDownload - share download not implemented.
+func (c *sftpClient) ShareDownload(expires time.Duration, reqParams url.Values) (string, *probe.Error) {
+	return "", c.notImplemented("ShareDownload")
+}
+
+// ShareUpload - share upload not implemented.
+func (c *sftpClient) ShareUpload(opts ShareUploadOpts) (map[string]string, *probe.Error) {
+	return nil, c.notImplemented("ShareUpload")
+}
+
+// ShareUploadURL - share upload not implemented.
+func (c *sftpClient) ShareUploadURL(opts ShareUploadOpts) (string, *probe.Error) {
+	return "", c.notImplemented("ShareUploadURL")
+}
+
+// Watch - watching events not implemented.
+func (c *sftpClient) Watch(params WatchParams) (*WatchObject, *probe.Error) {
+	return nil, c.notImplemented("Watch")
+}
+
+// Unwatch - watching events not implemented.
+func (c *sftpClient) Unwatch(params WatchParams) *probe.Error {
+	return c.notImplemented("Unwatch")
+}
+
+// RestoreObject - archiving not implemented.
+func (c *sftpClient) RestoreObject(days int, tier string) *probe.Error {
+	return c.notImplemented("RestoreObject")
+}
+
+// RestoreStatus - archiving not implemented.
+func (c *sftpClient) RestoreStatus(sse EncryptOpts) (ObjectRestoreStatus, *probe.Error) {
+	return ObjectRestoreStatus{}, c.notImplemented("RestoreStatus")
+}
+
+// ListVersions - versioning not implemented.
+func (c *sftpClient) ListVersions(recursive bool) <-chan *ClientContent {
+	contentCh := make(chan *ClientContent, 1)
+	contentCh <- &ClientContent{Err: c.notImplemented("ListVersions")}
+	close(contentCh)
+	return contentCh
+}
+
+// GetVersion - versioning not implemented.
+func (c *sftpClient) GetVersion(versionID string) (io.Reader, *probe.Error) {
+	return nil, c.notImplemented("GetVersion")
+}
+
+// RemoveVersion - versioning not implemented.
+func (c *sftpClient) RemoveVersion(versionID string) *probe.Error {
+	return c.notImplemented("RemoveVersion")
+}
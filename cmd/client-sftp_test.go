@@ -0,0 +1,281 @@
+/*
+ * Minio Client (C) 2016 Minio, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+
+	. "gopkg.in/check.v1"
+)
+
+// sftpTestServer - serves the local filesystem, reads return at most
+// sftpTestReadSize bytes to exercise short reads.
+type sftpTestServer struct {
+	r       io.Reader
+	w       io.Writer
+	handles map[string]*os.File
+	dirs    map[string]bool
+	next    int
+}
+
+const sftpTestReadSize = 1000
+
+// serve - answers requests until the client goes away.
+func (s *sftpTestServer) serve() {
+	if packetType, _, e := readSFTPPacket(s.r); e != nil || packetType != sftpPacketInit {
+		return
+	}
+	s.reply(sftpPacketVersion, (&sftpEncoder{}).uint32(sftpProtocolVersion))
+	for {
+		packetType, data, e := readSFTPPacket(s.r)
+		if e != nil {
+			return
+		}
+		d := &sftpDecoder{b: data}
+		id := d.uint32()
+		s.handle(id, packetType, d)
+	}
+}
+
+func (s *sftpTestServer) reply(packetType byte, payload *sftpEncoder) {
+	packet := &sftpEncoder{}
+	packet.uint32(uint32(len(payload.b) + 1))
+	packet.b = append(packet.b, packetType)
+	packet.b = append(packet.b, payload.b...)
+	s.w.Write(packet.b)
+}
+
+func (s *sftpTestServer) status(id uint32, e error) {
+	code := uint32(sftpStatusOK)
+	switch {
+	case e == io.EOF:
+		code = sftpStatusEOF
+	case os.IsNotExist(e):
+		code = sftpStatusNoSuchFile
+	case e != nil:
+		code = sftpStatusFailure
+	}
+	s.reply(sftpPacketStatus, (&sftpEncoder{}).uint32(id).uint32(code).string("").string(""))
+}
+
+func sftpTestAttrs(fi os.FileInfo) sftpAttrs {
+	perm := uint32(fi.Mode().Perm())
+	if fi.IsDir() {
+		perm |= 0040000
+	} else {
+		perm |= 0100000
+	}
+	mtime := uint32(fi.ModTime().Unix())
+	return sftpAttrs{
+		Flags: sftpAttrSize | sftpAttrPermissions | sftpAttrACModTime,
+		Size:  uint64(fi.Size()),
+		Perm:  perm,
+		Atime: mtime,
+		Mtime: mtime,
+	}
+}
+
+func (s *sftpTestServer) handle(id uint32, packetType byte, d *sftpDecoder) {
+	switch packetType {
+	case sftpPacketOpen:
+		fpath, flags := d.string(), d.uint32()
+		mode := os.O_RDONLY
+		if flags&sftpOpenWrite != 0 {
+			mode = os.O_WRONLY | os.O_CREATE | os.O_TRUNC
+		}
+		file, e := os.OpenFile(fpath, mode, 0644)
+		if e != nil {
+			s.status(id, e)
+			return
+		}
+		s.next++
+		handle := string(rune('a' + s.next))
+		s.handles[handle] = file
+		s.reply(sftpPacketHandle, (&sftpEncoder{}).uint32(id).string(handle))
+	case sftpPacketOpendir:
+		fpath := d.string()
+		if _, e := ioutil.ReadDir(fpath); e != nil {
+			s.status(id, e)
+			return
+		}
+		s.next++
+		handle := string(rune('a' + s.next))
+		s.dirs[handle] = false
+		s.handles[handle], _ = os.Open(fpath)
+		s.reply(sftpPacketHandle, (&sftpEncoder{}).uint32(id).string(handle))
+	case sftpPacketReaddir:
+		handle := d.string()
+		if s.dirs[handle] {
+			s.status(id, io.EOF)
+			return
+		}
+		s.dirs[handle] = true
+		fis, e := ioutil.ReadDir(s.handles[handle].Name())
+		if e != nil {
+			s.status(id, e)
+			return
+		}
+		payload := (&sftpEncoder{}).uint32(id).uint32(uint32(len(fis) + 1))
+		payload.string(".").string(".").attrs(sftpAttrs{})
+		for _, fi := range fis {
+			payload.string(fi.Name()).string(fi.Name()).attrs(sftpTestAttrs(fi))
+		}
+		s.reply(sftpPacketName, payload)
+	case sftpPacketClose:
+		handle := d.string()
+		file := s.handles[handle]
+		delete(s.handles, handle)
+		delete(s.dirs, handle)
+		s.status(id, file.Close())
+	case sftpPacketRead:
+		file, offset, length := s.handles[d.string()], d.uint64(), d.uint32()
+		if length > sftpTestReadSize {
+			length = sftpTestReadSize
+		}
+		buf := make([]byte, length)
+		n, e := file.ReadAt(buf, int64(offset))
+		if n == 0 {
+			s.status(id, e)
+			return
+		}
+		s.reply(sftpPacketData, (&sftpEncoder{}).uint32(id).bytes(buf[:n]))
+	case sftpPacketWrite:
+		file, offset, data := s.handles[d.string()], d.uint64(), d.bytes()
+		_, e := file.WriteAt(data, int64(offset))
+		s.status(id, e)
+	case sftpPacketStat:
+		fi, e := os.Stat(d.string())
+		if e != nil {
+			s.status(id, e)
+			return
+		}
+		s.reply(sftpPacketAttrs, (&sftpEncoder{}).uint32(id).attrs(sftpTestAttrs(fi)))
+	case sftpPacketMkdir:
+		s.status(id, os.Mkdir(d.string(), 0755))
+	case sftpPacketRemove:
+		s.status(id, os.Remove(d.string()))
+	case sftpPacketRmdir:
+		s.status(id, os.Remove(d.string()))
+	case sftpPacketRename:
+		oldPath, newPath := d.string(), d.string()
+		if _, e := os.Stat(newPath); e == nil {
+			s.status(id, os.ErrExist)
+			return
+		}
+		s.status(id, os.Rename(oldPath, newPath))
+	default:
+		s.status(id, os.ErrInvalid)
+	}
+}
+
+func (s *TestSuite) TestSFTPClient(c *C) {
+	root, e := ioutil.TempDir(os.TempDir(), "sftp-")
+	c.Assert(e, IsNil)
+	defer os.RemoveAll(root)
+	root = filepath.ToSlash(root)
+
+	// Sessions of the host are taken from the cache, no ssh is started.
+	clientR, serverW := io.Pipe()
+	serverR, clientW := io.Pipe()
+	server := &sftpTestServer{r: serverR, w: serverW, handles: make(map[string]*os.File), dirs: make(map[string]bool)}
+	go server.serve()
+	conn, e := newSFTPConn(clientR, clientW)
+	c.Assert(e, IsNil)
+	defer clientW.Close()
+	sftpConnsMu.Lock()
+	sftpConns["user@sftp.test:2222"] = conn
+	sftpConnsMu.Unlock()
+
+	urlPrefix := "sftp://user@sftp.test:2222" + root
+	clnt, err := sftpNew(urlPrefix + "/dir/file")
+	c.Assert(err, IsNil)
+	c.Assert(clnt.GetURL().Host, Equals, "user@sftp.test:2222")
+	c.Assert(clnt.GetURL().Path, Equals, root+"/dir/file")
+
+	// Directories are created along.
+	data := bytes.Repeat([]byte("0123456789abcdef"), 20*1024)
+	n, err := clnt.Put(context.Background(), bytes.NewReader(data), int64(len(data)), "", nil, PutOpts{})
+	c.Assert(err, IsNil)
+	c.Assert(n, Equals, int64(len(data)))
+	written, e := ioutil.ReadFile(filepath.Join(root, "dir", "file"))
+	c.Assert(e, IsNil)
+	c.Assert(bytes.Equal(written, data), Equals, true)
+
+	content, err := clnt.Stat()
+	c.Assert(err, IsNil)
+	c.Assert(content.Size, Equals, int64(len(data)))
+	c.Assert(content.Type.IsRegular(), Equals, true)
+
+	// Short reads are requested again.
+	reader, err := clnt.Get(context.Background(), GetOpts{})
+	c.Assert(err, IsNil)
+	read, e := ioutil.ReadAll(reader)
+	c.Assert(e, IsNil)
+	c.Assert(bytes.Equal(read, data), Equals, true)
+	c.Assert(reader.(io.Closer).Close(), IsNil)
+
+	reader, err = clnt.Get(context.Background(), GetOpts{Offset: 100000, Length: 50000})
+	c.Assert(err, IsNil)
+	read, e = ioutil.ReadAll(reader)
+	c.Assert(e, IsNil)
+	c.Assert(bytes.Equal(read, data[100000:150000]), Equals, true)
+	c.Assert(reader.(io.Closer).Close(), IsNil)
+
+	etag, _, err := clnt.Checksum(0)
+	c.Assert(err, IsNil)
+	expected, err := readETag(bytes.NewReader(data), 0)
+	c.Assert(err, IsNil)
+	c.Assert(etag, Equals, expected)
+
+	// Files of the same host are copied.
+	copyClnt, err := sftpNew(urlPrefix + "/dir/sub/copy")
+	c.Assert(err, IsNil)
+	c.Assert(copyClnt.Copy(context.Background(), root+"/dir/file", int64(len(data)), nil, CopyOpts{}), IsNil)
+
+	var listed []string
+	dirClnt, err := sftpNew(urlPrefix + "/dir")
+	c.Assert(err, IsNil)
+	for content := range dirClnt.List(context.Background(), true, false) {
+		c.Assert(content.Err, IsNil)
+		c.Assert(content.Size, Equals, int64(len(data)))
+		listed = append(listed, content.URL.String())
+	}
+	c.Assert(listed, DeepEquals, []string{urlPrefix + "/dir/file", urlPrefix + "/dir/sub/copy"})
+
+	// Without a trailing separator only the directory itself is listed.
+	listed = nil
+	for content := range dirClnt.List(context.Background(), false, false) {
+		c.Assert(content.Err, IsNil)
+		c.Assert(content.Type.IsDir(), Equals, true)
+		listed = append(listed, content.URL.Path)
+	}
+	c.Assert(listed, DeepEquals, []string{root + "/dir"})
+
+	c.Assert(clnt.Remove(context.Background(), false), IsNil)
+	_, err = clnt.Stat()
+	c.Assert(err, NotNil)
+	_, ok := err.ToGoError().(PathNotFound)
+	c.Assert(ok, Equals, true)
+
+	_, err = clnt.ShareDownload(0, nil)
+	c.Assert(err, NotNil)
+}
@@ -0,0 +1,231 @@
+/*
+ * Minio Client (C) 2016 Minio, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"io/ioutil"
+	"net"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/minio/minio/pkg/probe"
+)
+
+// loadTLSConfig - TLS config verifying hosts against the CA bundle,
+// presenting the client certificate if set. Nil if neither is set.
+func loadTLSConfig(caCert, cert, key string, insecure bool) (*tls.Config, *probe.Error) {
+	if caCert == "" && cert == "" && !insecure {
+		return nil, nil
+	}
+	tlsConfig := &tls.Config{InsecureSkipVerify: insecure}
+	if caCert != "" {
+		pemBytes, e := ioutil.ReadFile(caCert)
+		if e != nil {
+			return nil, probe.NewError(e).Trace(caCert)
+		}
+		tlsConfig.RootCAs = x509.NewCertPool()
+		if !tlsConfig.RootCAs.AppendCertsFromPEM(pemBytes) {
+			return nil, errInvalidCACert(caCert).Trace(caCert)
+		}
+	}
+	if cert != "" || key != "" {
+		if cert == "" || key == "" {
+			return nil, errInvalidClientCert(cert, key).Trace(cert, key)
+		}
+		certificate, e := tls.LoadX509KeyPair(cert, key)
+		if e != nil {
+			return nil, probe.NewError(e).Trace(cert, key)
+		}
+		tlsConfig.Certificates = []tls.Certificate{certificate}
+	}
+	return tlsConfig, nil
+}
+
+// TransportOpts - tuning of HTTP connections to hosts, zero values
+// keep the defaults.
+type TransportOpts struct {
+	DialTimeout           time.Duration
+	ResponseHeaderTimeout time.Duration
+	TLSHandshakeTimeout   time.Duration
+	MaxIdleConnsPerHost   int
+}
+
+// Defaults of the tuning, more idle connections per host than
+// net/http keeps so that parallel transfers reuse their connections.
+var defaultTransportOpts = TransportOpts{
+	DialTimeout:         30 * time.Second,
+	TLSHandshakeTimeout: 10 * time.Second,
+	MaxIdleConnsPerHost: 100,
+}
+
+// withDefaults - options with zero values replaced by those of defaults.
+func (o TransportOpts) withDefaults(defaults TransportOpts) TransportOpts {
+	if o.DialTimeout == 0 {
+		o.DialTimeout = defaults.DialTimeout
+	}
+	if o.ResponseHeaderTimeout == 0 {
+		o.ResponseHeaderTimeout = defaults.ResponseHeaderTimeout
+	}
+	if o.TLSHandshakeTimeout == 0 {
+		o.TLSHandshakeTimeout = defaults.TLSHandshakeTimeout
+	}
+	if o.MaxIdleConnsPerHost == 0 {
+		o.MaxIdleConnsPerHost = defaults.MaxIdleConnsPerHost
+	}
+	return o
+}
+
+// validate - timeouts and connections can not be negative.
+func (o TransportOpts) validate() *probe.Error {
+	if o.DialTimeout < 0 || o.ResponseHeaderTimeout < 0 || o.TLSHandshakeTimeout < 0 || o.MaxIdleConnsPerHost < 0 {
+		return errInvalidTransportOpts().Trace(o.DialTimeout.String(), o.ResponseHeaderTimeout.String(), o.TLSHandshakeTimeout.String())
+	}
+	return nil
+}
+
+// parseTransportConfig - options set in the config file.
+func parseTransportConfig(t *transportConfigV8) (TransportOpts, *probe.Error) {
+	opts := TransportOpts{MaxIdleConnsPerHost: t.MaxIdleConnsPerHost}
+	timeouts := []struct {
+		value    string
+		duration *time.Duration
+	}{
+		{t.DialTimeout, &opts.DialTimeout},
+		{t.ResponseHeaderTimeout, &opts.ResponseHeaderTimeout},
+		{t.TLSHandshakeTimeout, &opts.TLSHandshakeTimeout},
+	}
+	for _, timeout := range timeouts {
+		if timeout.value == "" {
+			continue
+		}
+		d, e := time.ParseDuration(timeout.value)
+		if e != nil {
+			return TransportOpts{}, probe.NewError(e).Trace(timeout.value)
+		}
+		*timeout.duration = d
+	}
+	if err := opts.validate(); err != nil {
+		return TransportOpts{}, err.Trace()
+	}
+	return opts, nil
+}
+
+// getTransportOpts - tuning of HTTP connections, flags override the
+// config file.
+func getTransportOpts() (TransportOpts, *probe.Error) {
+	// Config is only loaded once mc started.
+	if loadMcConfig == nil {
+		return globalTransport, nil
+	}
+	conf, err := loadMcConfig()
+	if err != nil {
+		return TransportOpts{}, err.Trace()
+	}
+	if conf.Transport == nil {
+		return globalTransport, nil
+	}
+	fileOpts, err := parseTransportConfig(conf.Transport)
+	if err != nil {
+		return TransportOpts{}, err.Trace()
+	}
+	return globalTransport.withDefaults(fileOpts), nil
+}
+
+// tlsKey - settings TLS configs are shared by.
+type tlsKey struct {
+	caCert, cert, key string
+	insecure          bool
+}
+
+// transportKey - settings transports are shared by.
+type transportKey struct {
+	tls  tlsKey
+	opts TransportOpts
+}
+
+// Maximum number of idle connections of a transport to all hosts, so
+// that mirroring between many hosts does not keep too many sockets.
+const maxIdleConns = 256
+
+var (
+	// Transports of equal settings share their idle connections,
+	// transports of equal TLS settings share the TLS config and the
+	// sessions it resumes.
+	transports     = make(map[transportKey]*http.Transport)
+	tlsConfigs     = make(map[tlsKey]*tls.Config)
+	transportsLock = &sync.Mutex{}
+)
+
+// sharedTLSConfig - TLS config of the settings, loaded only once.
+// Nil if the defaults of net/http are used. The caller holds
+// transportsLock.
+func sharedTLSConfig(key tlsKey) (*tls.Config, *probe.Error) {
+	if tlsConfig, ok := tlsConfigs[key]; ok {
+		return tlsConfig, nil
+	}
+	tlsConfig, err := loadTLSConfig(key.caCert, key.cert, key.key, key.insecure)
+	if err != nil {
+		return nil, err.Trace()
+	}
+	if tlsConfig != nil {
+		tlsConfig.ClientSessionCache = tls.NewLRUClientSessionCache(0)
+	}
+	tlsConfigs[key] = tlsConfig
+	return tlsConfig, nil
+}
+
+// newClientTransport - transport of requests to the host of the
+// config, shared by all configs of equal TLS and tuning settings.
+func newClientTransport(config *Config) (http.RoundTripper, *probe.Error) {
+	key := transportKey{
+		tls:  tlsKey{config.CACert, config.Cert, config.Key, config.Insecure},
+		opts: config.Transport.withDefaults(defaultTransportOpts),
+	}
+
+	transportsLock.Lock()
+	defer transportsLock.Unlock()
+	if transport, ok := transports[key]; ok {
+		return transport, nil
+	}
+	tlsConfig, err := sharedTLSConfig(key.tls)
+	if err != nil {
+		return nil, err.Trace(config.HostURL)
+	}
+	idleConns := maxIdleConns
+	if key.opts.MaxIdleConnsPerHost > idleConns {
+		idleConns = key.opts.MaxIdleConnsPerHost
+	}
+	transport := &http.Transport{
+		Proxy: http.ProxyFromEnvironment,
+		DialContext: (&net.Dialer{
+			Timeout:   key.opts.DialTimeout,
+			KeepAlive: 30 * time.Second,
+		}).DialContext,
+		TLSClientConfig:       tlsConfig,
+		TLSHandshakeTimeout:   key.opts.TLSHandshakeTimeout,
+		ResponseHeaderTimeout: key.opts.ResponseHeaderTimeout,
+		MaxIdleConns:          idleConns,
+		MaxIdleConnsPerHost:   key.opts.MaxIdleConnsPerHost,
+		IdleConnTimeout:       90 * time.Second,
+		ExpectContinueTimeout: 1 * time.Second,
+	}
+	transports[key] = transport
+	return transport, nil
+}
@@ -0,0 +1,81 @@
+/*
+ * Minio Client (C) 2016 Minio, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"time"
+
+	. "gopkg.in/check.v1"
+)
+
+// Test tuning of HTTP transports.
+func (s *TestSuite) TestTransportOpts(c *C) {
+	testCases := []struct {
+		config  transportConfigV8
+		opts    TransportOpts
+		success bool
+	}{
+		{transportConfigV8{}, TransportOpts{}, true},
+		{transportConfigV8{DialTimeout: "5s", ResponseHeaderTimeout: "1m", MaxIdleConnsPerHost: 16}, TransportOpts{DialTimeout: 5 * time.Second, ResponseHeaderTimeout: time.Minute, MaxIdleConnsPerHost: 16}, true},
+		{transportConfigV8{TLSHandshakeTimeout: "5"}, TransportOpts{}, false},
+		{transportConfigV8{DialTimeout: "-5s"}, TransportOpts{}, false},
+		{transportConfigV8{MaxIdleConnsPerHost: -1}, TransportOpts{}, false},
+	}
+	for i, testCase := range testCases {
+		opts, err := parseTransportConfig(&testCase.config)
+		c.Assert(err == nil, Equals, testCase.success, Commentf("Test %d", i+1))
+		c.Assert(opts, Equals, testCase.opts, Commentf("Test %d", i+1))
+	}
+
+	// Flags override the config file, which overrides the defaults.
+	opts := TransportOpts{DialTimeout: time.Second}.withDefaults(TransportOpts{DialTimeout: time.Minute, MaxIdleConnsPerHost: 16}).withDefaults(defaultTransportOpts)
+	c.Assert(opts, Equals, TransportOpts{DialTimeout: time.Second, TLSHandshakeTimeout: 10 * time.Second, MaxIdleConnsPerHost: 16})
+
+	// Hosts of equal settings share a transport.
+	transport1, err := newClientTransport(&Config{HostURL: "https://s3.amazonaws.com"})
+	c.Assert(err, IsNil)
+	transport2, err := newClientTransport(&Config{HostURL: "https://storage.googleapis.com"})
+	c.Assert(err, IsNil)
+	c.Assert(transport1, Equals, transport2)
+	transport3, err := newClientTransport(&Config{HostURL: "https://s3.amazonaws.com", Transport: TransportOpts{MaxIdleConnsPerHost: 4}})
+	c.Assert(err, IsNil)
+	c.Assert(transport3.(*http.Transport).MaxIdleConnsPerHost, Equals, 4)
+	c.Assert(transport3.(*http.Transport).MaxIdleConns, Equals, maxIdleConns)
+
+	// Transports of equal TLS settings share their TLS config.
+	transport4, err := newClientTransport(&Config{HostURL: "https://s3.amazonaws.com", Insecure: true})
+	c.Assert(err, IsNil)
+	transport5, err := newClientTransport(&Config{HostURL: "https://s3.amazonaws.com", Insecure: true, Transport: TransportOpts{MaxIdleConnsPerHost: 4}})
+	c.Assert(err, IsNil)
+	c.Assert(transport4, Not(Equals), transport5)
+	tlsConfig := transport4.(*http.Transport).TLSClientConfig
+	c.Assert(tlsConfig, NotNil)
+	c.Assert(tlsConfig.ClientSessionCache, NotNil)
+	c.Assert(transport5.(*http.Transport).TLSClientConfig, Equals, tlsConfig)
+
+	// Hosts slow to respond time out.
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		time.Sleep(100 * time.Millisecond)
+	}))
+	defer server.Close()
+	transport, err := newClientTransport(&Config{HostURL: server.URL, Transport: TransportOpts{ResponseHeaderTimeout: 10 * time.Millisecond}})
+	c.Assert(err, IsNil)
+	_, e := (&http.Client{Transport: transport}).Get(server.URL)
+	c.Assert(e, Not(IsNil))
+}
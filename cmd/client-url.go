@@ -28,8 +28,8 @@ import (
 )
 
 // url client url structure
-type clientURL struct {
-	Type            clientURLType
+type ClientURL struct {
+	Type            ClientURLType
 	Scheme          string
 	Host            string
 	Path            string
@@ -37,13 +37,13 @@ type clientURL struct {
 	Separator       rune
 }
 
-// clientURLType - enum of different url types
-type clientURLType int
+// ClientURLType - enum of different url types
+type ClientURLType int
 
 // enum types
 const (
-	objectStorage = iota // Minio and S3 compatible cloud storage
-	fileSystem           // POSIX compatible file systems
+	ObjectStorage = iota // Minio and S3 compatible cloud storage
+	FileSystem           // POSIX compatible file systems
 )
 
 // Maybe rawurl is of the form scheme:path. (Scheme must be [a-zA-Z][a-zA-Z0-9+-.]*)
@@ -91,7 +91,7 @@ func getHost(authority string) (host string) {
 }
 
 // newClientURL returns an abstracted URL for filesystems and object storage.
-func newClientURL(urlStr string) *clientURL {
+func newClientURL(urlStr string) *ClientURL {
 	scheme, rest := getScheme(urlStr)
 	if strings.HasPrefix(rest, "//") {
 		// if rest has '//' prefix, skip them
@@ -101,10 +101,15 @@ func newClientURL(urlStr string) *clientURL {
 			rest = "/"
 		}
 		host := getHost(authority)
-		if host != "" && (scheme == "http" || scheme == "https") {
-			return &clientURL{
+		// Hosts of other schemes than http(s) may carry the name of
+		// the user, e.g. of SFTP.
+		if scheme != "http" && scheme != "https" {
+			host = authority
+		}
+		if host != "" && lookupClientScheme(scheme) != nil {
+			return &ClientURL{
 				Scheme:          scheme,
-				Type:            objectStorage,
+				Type:            ObjectStorage,
 				Host:            host,
 				Path:            rest,
 				SchemeSeparator: "://",
@@ -112,15 +117,15 @@ func newClientURL(urlStr string) *clientURL {
 			}
 		}
 	}
-	return &clientURL{
-		Type:      fileSystem,
+	return &ClientURL{
+		Type:      FileSystem,
 		Path:      rest,
 		Separator: filepath.Separator,
 	}
 }
 
 // joinURLs join two input urls and returns a url
-func joinURLs(url1, url2 *clientURL) *clientURL {
+func joinURLs(url1, url2 *ClientURL) *ClientURL {
 	var url1Path, url2Path string
 	url1Path = filepath.ToSlash(url1.Path)
 	url2Path = filepath.ToSlash(url2.Path)
@@ -133,14 +138,14 @@ func joinURLs(url1, url2 *clientURL) *clientURL {
 }
 
 // String convert URL into its canonical form.
-func (u clientURL) String() string {
+func (u ClientURL) String() string {
 	var buf bytes.Buffer
-	// if fileSystem no translation needed, return as is.
-	if u.Type == fileSystem {
+	// if FileSystem no translation needed, return as is.
+	if u.Type == FileSystem {
 		return u.Path
 	}
-	// if objectStorage convert from any non standard paths to a supported URL path style.
-	if u.Type == objectStorage {
+	// if ObjectStorage convert from any non standard paths to a supported URL path style.
+	if u.Type == ObjectStorage {
 		buf.WriteString(u.Scheme)
 		buf.WriteByte(':')
 		buf.WriteString("//")
@@ -177,7 +182,7 @@ func urlJoinPath(url1, url2 string) string {
 }
 
 // url2Stat returns stat info for URL.
-func url2Stat(urlStr string) (client Client, content *clientContent, err *probe.Error) {
+func url2Stat(urlStr string) (client Client, content *ClientContent, err *probe.Error) {
 	client, err = newClient(urlStr)
 	if err != nil {
 		return nil, nil, err.Trace(urlStr)
@@ -221,7 +226,7 @@ func isURLPrefixExists(urlPrefix string, incomplete bool) bool {
 	}
 	isRecursive := false
 	isIncomplete := incomplete
-	for entry := range clnt.List(isRecursive, isIncomplete) {
+	for entry := range clnt.List(globalContext, isRecursive, isIncomplete) {
 		return entry.Err == nil
 	}
 	return false
@@ -51,3 +51,16 @@ func (s *TestSuite) TestURLJoinPath(c *C) {
 	url = urlJoinPath(url1, url2)
 	c.Assert(url, Equals, "http://s3.mycompany.io/dev/mybucket/bin/")
 }
+
+// TestURLRegisteredScheme - tests urls of registered schemes.
+func (s *TestSuite) TestURLRegisteredScheme(c *C) {
+	url := newClientURL("memtest://user@host/mybucket/object")
+	c.Assert(url.Type == ObjectStorage, Equals, false)
+
+	RegisterClientScheme("memtest", benchNew)
+	url = newClientURL("memtest://user@host/mybucket/object")
+	c.Assert(url.Type == ObjectStorage, Equals, true)
+	c.Assert(url.Host, Equals, "user@host")
+	c.Assert(url.Path, Equals, "/mybucket/object")
+	c.Assert(lookupClientScheme("MEMTEST"), NotNil)
+}
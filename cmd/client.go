@@ -17,7 +17,9 @@
 package cmd
 
 import (
+	"context"
 	"io"
+	"net/url"
 	"os"
 	"time"
 
@@ -26,9 +28,10 @@ import (
 
 // Client - client interface
 type Client interface {
-	// Common operations
-	Stat() (content *clientContent, err *probe.Error)
-	List(recursive, incomplete bool) <-chan *clientContent
+	// Common operations, requests of operations taking a context are
+	// cancelled along with it.
+	Stat() (content *ClientContent, err *probe.Error)
+	List(ctx context.Context, recursive, incomplete bool) <-chan *ClientContent
 
 	// Bucket operations
 	MakeBucket(region string) *probe.Error
@@ -38,33 +41,148 @@ type Client interface {
 	GetAccessRules() (policyRules map[string]string, error *probe.Error)
 	SetAccess(access string) *probe.Error
 
-	// I/O operations
-	Get() (reader io.Reader, err *probe.Error)
-	Put(reader io.Reader, size int64, contentType string, progress io.Reader) (n int64, err *probe.Error)
-	Copy(source string, size int64, progress io.Reader) *probe.Error
+	// I/O operations, encryption options and metadata are honored only
+	// by object storage.
+	Get(ctx context.Context, opts GetOpts) (reader io.Reader, err *probe.Error)
+	Put(ctx context.Context, reader io.Reader, size int64, contentType string, progress io.Reader, opts PutOpts) (n int64, err *probe.Error)
+	Copy(ctx context.Context, source string, size int64, progress io.Reader, opts CopyOpts) *probe.Error
+	// ETag of the content along with the size of the parts it was
+	// computed from, files are hashed in parts of the given size.
+	Checksum(partSize int64) (etag string, etagPartSize int64, err *probe.Error)
+
+	// Query operations, the reader streams records matching the
+	// expression.
+	SelectObjectContent(expression string, sse EncryptOpts, opts SelectObjectOpts) (io.ReadCloser, *probe.Error)
 
 	// I/O operations with expiration
-	ShareDownload(expires time.Duration) (string, *probe.Error)
-	ShareUpload(bool, time.Duration, string) (map[string]string, *probe.Error)
+	// Query parameters of the download URL, e.g. to override response
+	// headers, are signed along.
+	ShareDownload(expires time.Duration, reqParams url.Values) (string, *probe.Error)
+	ShareUpload(opts ShareUploadOpts) (map[string]string, *probe.Error)
+	// Presigned PUT URL of the object, simpler to use than the upload
+	// form but unable to restrict the size of uploads to a range.
+	ShareUploadURL(opts ShareUploadOpts) (string, *probe.Error)
 
 	// Watch events
-	Watch(params watchParams) (*watchObject, *probe.Error)
-	Unwatch(params watchParams) *probe.Error
+	Watch(params WatchParams) (*WatchObject, *probe.Error)
+	Unwatch(params WatchParams) *probe.Error
+
+	// Versioning operations
+	ListVersions(recursive bool) <-chan *ClientContent
+	GetVersion(versionID string) (reader io.Reader, err *probe.Error)
+	RemoveVersion(versionID string) *probe.Error
+
+	// Archive operations, archived objects have to be restored before
+	// they can be read.
+	RestoreObject(days int, tier string) *probe.Error
+	RestoreStatus(sse EncryptOpts) (ObjectRestoreStatus, *probe.Error)
 
 	// Delete operations
-	Remove(incomplete bool) *probe.Error
+	Remove(ctx context.Context, incomplete bool) *probe.Error
+	// Removes the objects read from the channel, the result of every
+	// object is sent on the returned channel.
+	RemoveBulk(contentCh <-chan *ClientContent) <-chan *ClientContent
 
 	// GetURL returns back internal url
-	GetURL() clientURL
+	GetURL() ClientURL
 }
 
 // Content container for content metadata
-type clientContent struct {
-	URL  clientURL
+type ClientContent struct {
+	URL  ClientURL
 	Time time.Time
 	Size int64
 	Type os.FileMode
 	Err  *probe.Error
+
+	// Storage class of objects, archived objects have to be restored
+	// before they can be read.
+	StorageClass string
+
+	// ETag of objects, set only by listings of object storage.
+	ETag string
+
+	// Set only while listing versions.
+	VersionID      string
+	IsLatest       bool
+	IsDeleteMarker bool
+
+	// Target of a symbolic link, set only while listing files with
+	// preserved links.
+	Symlink string
+}
+
+// GetOpts - options of a download, zero value reads the whole object.
+type GetOpts struct {
+	SSE EncryptOpts
+	// Range of the object to read, zero length reads until the end.
+	Offset int64
+	Length int64
+	// Verify the data read against the checksum of the object, only
+	// whole objects are verified.
+	Checksum bool
+	// Read the metadata returned along with the data, readers of
+	// clients returning it implement metadataReader.
+	Metadata bool
+}
+
+// PutOpts - options of an upload, zero value uploads with defaults.
+type PutOpts struct {
+	SSE EncryptOpts
+	// Size of a part of multipart uploads, zero picks one fitting the
+	// object.
+	PartSize int64
+	// Number of parts of a single object uploaded in parallel.
+	ConcurrentParts int
+	// Identifies the source of an upload, e.g. by its URL, size and
+	// modification time. Progress of multipart uploads with an ID is
+	// persisted, so that an interrupted upload of the same source is
+	// resumed.
+	ResumeID string
+	// Metadata of the object keyed by header name, either a standard
+	// header like ‘Cache-Control’ or a user defined ‘X-Amz-Meta-’ one.
+	Metadata map[string]string
+	// Verify the checksum of the object returned by the server against
+	// the data read.
+	Checksum bool
+	// Store mode, owner and modification time of files as metadata of
+	// the objects, and restore them when copying objects to files.
+	Preserve bool
+	// Skip trailing zero blocks of files, the size of the file is
+	// stored as metadata to restore them when copying objects to files.
+	Sparse bool
+}
+
+// CopyOpts - options of a server side copy, zero value copies
+// unconditionally.
+type CopyOpts struct {
+	SrcSSE EncryptOpts
+	TgtSSE EncryptOpts
+	// Preconditions on the source, the copy fails if any of them is
+	// not met.
+	IfMatch           string
+	IfNoneMatch       string
+	IfUnmodifiedSince time.Time
+	// Metadata added to the metadata of the source, entries replace
+	// those of the source with the same key.
+	Metadata map[string]string
+}
+
+// hasConditions - any precondition on the source is set.
+func (o CopyOpts) hasConditions() bool {
+	return o.IfMatch != "" || o.IfNoneMatch != "" || !o.IfUnmodifiedSince.IsZero()
+}
+
+// ShareUploadOpts - constraints of a shared upload, zero value allows
+// uploads of any size and content type.
+type ShareUploadOpts struct {
+	Expires time.Duration
+	// Allow uploads of any object starting with the object name.
+	Recursive   bool
+	ContentType string
+	// Accepted size of uploads, no limit if MaxSize is zero.
+	MinSize int64
+	MaxSize int64
 }
 
 // Config - see http://docs.amazonwebservices.com/AmazonS3/latest/dev/index.html?RESTAuthentication.html
@@ -78,4 +196,103 @@ type Config struct {
 	AppComments []string
 	Debug       bool
 	Insecure    bool
+	// Token of temporary credentials, sent along with every request.
+	SessionToken string
+	// Command printing credentials as JSON, replacing the keys above.
+	CredentialProcess string
+	// Role to assume through STS, long term credentials above are
+	// only used to obtain temporary credentials for this role.
+	RoleARN    string
+	ExternalID string
+	// Region of all buckets of the host, looked up per bucket if empty.
+	Region string
+	// Serial number and current token of an MFA device separated by a
+	// space, sent along with deletes of versions and changes of the
+	// versioning of buckets with MFA delete enabled.
+	MFA string
+	// Key file of a Google service account, used by the ‘GCS’ API
+	// instead of access and secret keys.
+	Credentials string
+	// Style of addressing buckets, ‘dns’ as host and ‘path’ as first
+	// segment of the path. Decided by host if empty.
+	Lookup string
+	// Signing of payloads of uploads with signature V4, ‘unsigned’ or
+	// ‘streaming’. Decided by minio-go if empty.
+	Payload string
+	// PEM files of CAs verifying the host, and of the certificate and
+	// key presented to the host.
+	CACert string
+	Cert   string
+	Key    string
+	// Tuning of the HTTP connections to the host.
+	Transport TransportOpts
+	// Recursive listing of all buckets of the host.
+	BucketListing BucketListOpts
+}
+
+// BucketListOpts - options of recursive listings of all buckets, zero
+// value lists the buckets one after the other.
+type BucketListOpts struct {
+	// Number of buckets listed at once.
+	Parallel int
+	// Send contents as they are listed instead of bucket by bucket.
+	Interleaved bool
+}
+
+// credentials - credentials requests are signed with.
+func (c *Config) credentials() s3Credentials {
+	return s3Credentials{AccessKey: c.AccessKey, SecretKey: c.SecretKey, SessionToken: c.SessionToken}
+}
+
+// isAnonymous - requests are sent unsigned if access or secret key is
+// blank, e.g. to read public buckets.
+func (c *Config) isAnonymous() bool {
+	return c.AccessKey == "" || c.SecretKey == ""
+}
+
+// contextReader - reader failing once its context is done, transfers
+// of clients sending no requests are cancelled by it.
+type contextReader struct {
+	ctx    context.Context
+	reader io.Reader
+}
+
+// newContextReader - reader cancelled along with the context.
+func newContextReader(ctx context.Context, reader io.Reader) io.Reader {
+	if ctx.Done() == nil {
+		return reader
+	}
+	return contextReader{ctx: ctx, reader: reader}
+}
+
+func (r contextReader) Read(p []byte) (int, error) {
+	if e := r.ctx.Err(); e != nil {
+		return 0, e
+	}
+	return r.reader.Read(p)
+}
+
+// metadataReader - reader of an object holding the metadata returned
+// along with its data.
+type metadataReader interface {
+	io.Reader
+	Metadata() map[string]string
+}
+
+// objectReader - reader of an object and its metadata, closing the
+// reader of the data if it is closable.
+type objectReader struct {
+	io.Reader
+	metadata map[string]string
+}
+
+func (r objectReader) Metadata() map[string]string {
+	return r.metadata
+}
+
+func (r objectReader) Close() error {
+	if closer, ok := r.Reader.(io.Closer); ok {
+		return closer.Close()
+	}
+	return nil
 }
@@ -17,14 +17,17 @@
 package cmd
 
 import (
+	"context"
 	"io"
 	"os"
 	"path/filepath"
 	"regexp"
 	"runtime"
+	"strconv"
 	"strings"
+	"time"
 
-	"github.com/minio/mc/pkg/console"
+	"github.com/dustin/go-humanize"
 	"github.com/minio/minio/pkg/probe"
 )
 
@@ -53,36 +56,88 @@ func isTargetURLDir(targetURL string) bool {
 }
 
 // getSource gets a reader from URL.
-func getSourceStream(urlStr string) (reader io.Reader, err *probe.Error) {
+func getSourceStream(ctx context.Context, urlStr string, opts GetOpts) (reader io.Reader, err *probe.Error) {
 	alias, urlStrFull, _, err := expandAlias(urlStr)
 	if err != nil {
 		return nil, err.Trace(urlStr)
 	}
-	return getSourceStreamFromAlias(alias, urlStrFull)
+	return getSourceStreamFromAlias(ctx, alias, urlStrFull, opts)
 }
 
 // getSourceStreamFromAlias gets a reader from URL.
-func getSourceStreamFromAlias(alias string, urlStr string) (reader io.Reader, err *probe.Error) {
+func getSourceStreamFromAlias(ctx context.Context, alias string, urlStr string, opts GetOpts) (reader io.Reader, err *probe.Error) {
 	sourceClnt, err := newClientFromAlias(alias, urlStr)
 	if err != nil {
 		return nil, err.Trace(alias, urlStr)
 	}
-	reader, err = sourceClnt.Get()
+	reader, err = sourceClnt.Get(ctx, opts)
 	if err != nil {
 		return nil, err.Trace(alias, urlStr)
 	}
 	return reader, nil
 }
 
+// Interval of checking whether the restore of an archived object has
+// completed.
+const restorePollInterval = time.Minute
+
+// waitRestoreFromAlias - archived objects can only be read once
+// restored, optionally waits for a requested restore to complete.
+func waitRestoreFromAlias(alias string, urlStr string, sse EncryptOpts, wait bool) *probe.Error {
+	sourceClnt, err := newClientFromAlias(alias, urlStr)
+	if err != nil {
+		return err.Trace(alias, urlStr)
+	}
+	for {
+		status, err := sourceClnt.RestoreStatus(sse)
+		if err != nil {
+			// Nothing is archived on backends without archives.
+			if _, ok := err.ToGoError().(APINotImplemented); ok {
+				return nil
+			}
+			return err.Trace(alias, urlStr)
+		}
+		if status.isRestored() {
+			return nil
+		}
+		if !wait || !status.Requested {
+			return probe.NewError(ObjectOnGlacier{Object: urlStr})
+		}
+		time.Sleep(restorePollInterval)
+	}
+}
+
+// getSourceMetadataFromAlias - metadata of an object, for streamed
+// copies to retain it. Files have no metadata.
+func getSourceMetadataFromAlias(alias string, urlStr string, sse EncryptOpts) (map[string]string, *probe.Error) {
+	sourceClnt, err := newClientFromAlias(alias, urlStr)
+	if err != nil {
+		return nil, err.Trace(alias, urlStr)
+	}
+	var metadata map[string]string
+	switch clnt := sourceClnt.(type) {
+	case *s3Client:
+		metadata, err = clnt.GetMetadata(sse)
+	case *gcsClient:
+		metadata, err = clnt.GetMetadata(sse)
+	default:
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err.Trace(alias, urlStr)
+	}
+	return metadata, nil
+}
+
 // putTargetStreamFromAlias writes to URL from Reader.
-func putTargetStreamFromAlias(alias string, urlStr string, reader io.Reader, size int64, progress io.Reader) (int64, *probe.Error) {
+func putTargetStreamFromAlias(ctx context.Context, alias string, urlStr string, reader io.Reader, size int64, progress io.Reader, opts PutOpts) (int64, *probe.Error) {
 	targetClnt, err := newClientFromAlias(alias, urlStr)
 	if err != nil {
 		return 0, err.Trace(alias, urlStr)
 	}
 	contentType := guessURLContentType(urlStr)
 	var n int64
-	n, err = targetClnt.Put(reader, size, contentType, progress)
+	n, err = targetClnt.Put(ctx, reader, size, contentType, progress, opts)
 	if err != nil {
 		return n, err.Trace(alias, urlStr)
 	}
@@ -90,21 +145,77 @@ func putTargetStreamFromAlias(alias string, urlStr string, reader io.Reader, siz
 }
 
 // putTargetStream writes to URL from reader. If length=-1, read until EOF.
-func putTargetStream(urlStr string, reader io.Reader, size int64) (int64, *probe.Error) {
+func putTargetStream(ctx context.Context, urlStr string, reader io.Reader, size int64, opts PutOpts) (int64, *probe.Error) {
 	alias, urlStrFull, _, err := expandAlias(urlStr)
 	if err != nil {
 		return 0, err.Trace(alias, urlStr)
 	}
-	return putTargetStreamFromAlias(alias, urlStrFull, reader, size, nil)
+	return putTargetStreamFromAlias(ctx, alias, urlStrFull, reader, size, nil, opts)
+}
+
+// parsePutOpts - multipart options of uploads from ‘--part-size’ and
+// ‘--concurrent-parts’, empty values keep the defaults.
+func parsePutOpts(partSize string, concurrentParts int) (PutOpts, *probe.Error) {
+	opts := PutOpts{ConcurrentParts: concurrentParts}
+	if partSize != "" {
+		size, e := humanize.ParseBytes(partSize)
+		if e != nil {
+			return PutOpts{}, probe.NewError(e)
+		}
+		if size < s3PartSizeLowerLimit || size > s3PartSizeUpperLimit {
+			return PutOpts{}, errInvalidPartSize(partSize).Trace(partSize)
+		}
+		opts.PartSize = int64(size)
+	}
+	if concurrentParts < 0 {
+		return PutOpts{}, errInvalidArgument().Trace(strconv.Itoa(concurrentParts))
+	}
+	return opts, nil
+}
+
+// parseParallel - number of objects transferred in parallel from
+// ‘--parallel’, sessions saved without it transfer one at a time.
+func parseParallel(parallel int) (int, *probe.Error) {
+	if parallel < 0 {
+		return 0, errInvalidArgument().Trace(strconv.Itoa(parallel))
+	}
+	if parallel == 0 {
+		return 1, nil
+	}
+	return parallel, nil
+}
+
+// parseCopyOpts - preconditions of server side copies from
+// ‘--if-match’, ‘--if-none-match’ and ‘--if-unmodified-since’. The
+// date is accepted as RFC3339 time or as a plain date.
+func parseCopyOpts(ifMatch, ifNoneMatch, ifUnmodifiedSince string) (CopyOpts, *probe.Error) {
+	opts := CopyOpts{IfMatch: ifMatch, IfNoneMatch: ifNoneMatch}
+	if ifUnmodifiedSince != "" {
+		t, e := time.Parse(time.RFC3339, ifUnmodifiedSince)
+		if e != nil {
+			if t, e = time.Parse(ilmDateFormat, ifUnmodifiedSince); e != nil {
+				return CopyOpts{}, probe.NewError(e)
+			}
+		}
+		opts.IfUnmodifiedSince = t
+	}
+	return opts, nil
+}
+
+// uploadResumeID - identifies a source for resumable uploads, a
+// modified source is uploaded afresh.
+func uploadResumeID(alias string, content *ClientContent) string {
+	return filepath.ToSlash(filepath.Join(alias, content.URL.Path)) + ":" +
+		strconv.FormatInt(content.Size, 10) + ":" + strconv.FormatInt(content.Time.UnixNano(), 10)
 }
 
 // copyTargetStreamFromAlias copies to URL from source.
-func copySourceStreamFromAlias(alias string, urlStr string, source string, size int64, progress io.Reader) *probe.Error {
+func copySourceStreamFromAlias(ctx context.Context, alias string, urlStr string, source string, size int64, progress io.Reader, opts CopyOpts) *probe.Error {
 	targetClnt, err := newClientFromAlias(alias, urlStr)
 	if err != nil {
 		return err.Trace(alias, urlStr)
 	}
-	err = targetClnt.Copy(source, size, progress)
+	err = targetClnt.Copy(ctx, source, size, progress, opts)
 	if err != nil {
 		return err.Trace(alias, urlStr)
 	}
@@ -113,9 +224,18 @@ func copySourceStreamFromAlias(alias string, urlStr string, source string, size
 
 // newClientFromAlias gives a new client interface for matching
 // alias entry in the mc config file. If no matching host config entry
-// is found, fs client is returned.
+// is found, URLs of registered schemes are served by their clients and
+// fs client is returned otherwise.
 func newClientFromAlias(alias string, urlStr string) (Client, *probe.Error) {
 	hostCfg := mustGetHostConfig(alias)
+	if u := newClientURL(urlStr); hostCfg == nil && u.Type == ObjectStorage {
+		// newClientURL only names hosts of registered schemes.
+		clnt, err := lookupClientScheme(u.Scheme)(urlStr)
+		if err != nil {
+			return nil, err.Trace(alias, urlStr)
+		}
+		return clnt, nil
+	}
 	if hostCfg == nil {
 		// No matching host config. So we treat it like a
 		// filesystem.
@@ -125,34 +245,53 @@ func newClientFromAlias(alias string, urlStr string) (Client, *probe.Error) {
 		}
 		return fsClient, nil
 	}
+	return newClientFromHostConfig(alias, urlStr, hostCfg)
+}
 
+// newClientFromHostConfig gives a new object storage client of the
+// host config, which need not be saved in the config file yet.
+func newClientFromHostConfig(alias string, urlStr string, hostCfg *hostConfigV8) (Client, *probe.Error) {
 	// We have a valid alias and hostConfig. We populate the
 	// credentials from the match found in the config file.
 	s3Config := new(Config)
 
 	// secretKey retrieved from the environement overrides the one
-	// present in the config file
+	// present in the config file, optionally followed by a session
+	// token of temporary keys.
 	keysPairEnv := os.Getenv("MC_SECRET_" + alias)
 	keysPairArray := strings.Split(keysPairEnv, ":")
-	var accessKeyEnv, secretKeyEnv string
+	var accessKeyEnv, secretKeyEnv, sessionTokenEnv string
 	if len(keysPairArray) >= 1 {
 		accessKeyEnv = keysPairArray[0]
 	}
 	if len(keysPairArray) >= 2 {
 		secretKeyEnv = keysPairArray[1]
 	}
+	if len(keysPairArray) >= 3 {
+		sessionTokenEnv = keysPairArray[2]
+	}
 	if len(keysPairEnv) > 0 &&
 		isValidAccessKey(accessKeyEnv) && isValidSecretKey(secretKeyEnv) {
 		s3Config.AccessKey = accessKeyEnv
 		s3Config.SecretKey = secretKeyEnv
+		s3Config.SessionToken = sessionTokenEnv
 	} else {
 		if len(keysPairEnv) > 0 {
-			console.Errorln("Access/Secret keys associated to `" + alias + "' " +
-				"are found in your environment but not suitable for use. " +
-				"Falling back to the standard config.")
+			logWarn("Access/Secret keys found in the environment are not suitable for use, falling back to the standard config.", "alias", alias)
 		}
 		s3Config.AccessKey = hostCfg.AccessKey
 		s3Config.SecretKey = hostCfg.SecretKey
+		s3Config.SessionToken = hostCfg.SessionToken
+		s3Config.CredentialProcess = hostCfg.CredentialProcess
+	}
+	// Amazon S3 hosts without keys fall back to the keys of the AWS
+	// environment.
+	if (s3Config.AccessKey == "" || s3Config.AccessKey == defaultAccessKey) && s3Config.CredentialProcess == "" && isAmazonHostURL(hostCfg.URL) {
+		if accessKey, secretKey, ok := awsEnvKeys(); ok {
+			s3Config.AccessKey = accessKey
+			s3Config.SecretKey = secretKey
+			s3Config.SessionToken = os.Getenv("AWS_SESSION_TOKEN")
+		}
 	}
 
 	s3Config.Signature = hostCfg.API
@@ -160,8 +299,44 @@ func newClientFromAlias(alias string, urlStr string) (Client, *probe.Error) {
 	s3Config.AppVersion = Version
 	s3Config.AppComments = []string{os.Args[0], runtime.GOOS, runtime.GOARCH}
 	s3Config.HostURL = urlStr
-	s3Config.Debug = globalDebug
+	s3Config.Debug = globalLogger.enabled(logLevelInfo)
 	s3Config.Insecure = globalInsecure
+	s3Config.RoleARN = hostCfg.RoleARN
+	s3Config.Region = hostCfg.Region
+	s3Config.ExternalID = hostCfg.ExternalID
+	s3Config.Lookup = hostCfg.Lookup
+	s3Config.Payload = hostCfg.Payload
+	s3Config.CACert = hostCfg.CACert
+	s3Config.Cert = hostCfg.Cert
+	s3Config.Key = hostCfg.Key
+	transport, err := getTransportOpts()
+	if err != nil {
+		return nil, err.Trace(alias, urlStr)
+	}
+	s3Config.Transport = transport
+	s3Config.BucketListing = globalBucketListing
+
+	// MFA tokens are only valid once, the environment overrides the
+	// token of the config file.
+	mfa := hostCfg.MFA
+	if envMFA := os.Getenv("MC_MFA_" + alias); envMFA != "" {
+		mfa = envMFA
+	}
+	if mfa != "" {
+		if !isValidMFA(mfa) {
+			return nil, errInvalidMFA(alias).Trace(alias, urlStr)
+		}
+		s3Config.MFA = strings.Join(strings.Fields(mfa), " ")
+	}
+	// Google Cloud Storage is accessed natively with a service account.
+	if strings.ToUpper(hostCfg.API) == "GCS" {
+		s3Config.Credentials = hostCfg.Credentials
+		gcsClnt, err := gcsNew(s3Config)
+		if err != nil {
+			return nil, err.Trace(alias, urlStr)
+		}
+		return gcsClnt, nil
+	}
 	s3Client, err := s3New(s3Config)
 	if err != nil {
 		return nil, err.Trace(alias, urlStr)
@@ -174,14 +349,25 @@ var urlRgx = regexp.MustCompile("^https?://")
 
 // newClient gives a new client interface
 func newClient(aliasedURL string) (Client, *probe.Error) {
-	alias, urlStrFull, hostCfg, err := expandAlias(aliasedURL)
+	alias, urlStrFull, _, err := expandAlias(aliasedURL)
 	if err != nil {
 		return nil, err.Trace(aliasedURL)
 	}
-	// Verify if the aliasedURL is a real URL, fail in those cases
-	// indicating the user to add alias.
-	if hostCfg == nil && urlRgx.MatchString(aliasedURL) {
-		return nil, errInvalidAliasedURL(aliasedURL).Trace(aliasedURL)
-	}
+	// Real URLs without an alias are only read, other operations
+	// fail indicating the user to add alias.
 	return newClientFromAlias(alias, urlStrFull)
 }
+
+// newS3Client gives a new object storage client, for features which
+// are not available on the filesystem.
+func newS3Client(aliasedURL string) (*s3Client, *probe.Error) {
+	client, err := newClient(aliasedURL)
+	if err != nil {
+		return nil, err.Trace(aliasedURL)
+	}
+	s3Client, ok := client.(*s3Client)
+	if !ok {
+		return nil, errInvalidArgument().Trace(aliasedURL)
+	}
+	return s3Client, nil
+}
@@ -0,0 +1,110 @@
+/*
+ * Minio Client (C) 2016 Minio, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"strings"
+
+	"github.com/minio/cli"
+	"github.com/minio/mc/pkg/console"
+)
+
+var (
+	completionFlags = []cli.Flag{
+		cli.BoolFlag{
+			Name:  "help, h",
+			Usage: "Help of completion.",
+		},
+		cli.BoolFlag{
+			Name:  "buckets",
+			Usage: "Complete bucket names of aliases as well, buckets are listed again after 5 minutes.",
+		},
+		cli.BoolFlag{
+			Name:  "list",
+			Usage: "Print aliased URLs completing the argument, used by the completion scripts.",
+		},
+	}
+)
+
+// Generate shell completion scripts.
+var completionCmd = cli.Command{
+	Name:   "completion",
+	Usage:  "Generate completion scripts for bash, zsh and fish.",
+	Action: mainCompletion,
+	Flags:  append(completionFlags, globalFlags...),
+	CustomHelpTemplate: `NAME:
+   mc {{.Name}} - {{.Usage}}
+
+USAGE:
+   mc {{.Name}} [FLAGS] SHELL
+
+   Commands, flags and configured aliases are completed, local paths are completed by the shell.
+
+FLAGS:
+  {{range .Flags}}{{.}}
+  {{end}}
+EXAMPLES:
+   1. Enable completion in the current bash shell.
+      $ source <(mc {{.Name}} bash)
+
+   2. Enable completion of zsh along with bucket names of aliases.
+      $ mc {{.Name}} --buckets zsh > ~/.mc-completion.zsh && echo "source ~/.mc-completion.zsh" >> ~/.zshrc
+
+   3. Enable completion of fish.
+      $ mc {{.Name}} fish > ~/.config/fish/completions/mc.fish
+`,
+}
+
+// checkCompletionSyntax - validate all the passed arguments
+func checkCompletionSyntax(ctx *cli.Context) {
+	if ctx.Bool("list") {
+		if len(ctx.Args()) > 1 {
+			cli.ShowCommandHelpAndExit(ctx, "completion", 1) // last argument is exit code
+		}
+		return
+	}
+	if len(ctx.Args()) != 1 {
+		cli.ShowCommandHelpAndExit(ctx, "completion", 1) // last argument is exit code
+	}
+	shell := ctx.Args().First()
+	for _, s := range completionShells {
+		if s == shell {
+			return
+		}
+	}
+	fatalIf(errInvalidArgument().Trace(shell), "Unable to generate completion of ‘"+shell+"’, shells supported are "+strings.Join(completionShells, ", ")+".")
+}
+
+// mainCompletion - is a handler for mc completion command
+func mainCompletion(ctx *cli.Context) {
+	// Set global flags from context.
+	setGlobalsFromContext(ctx)
+
+	// check 'completion' cli arguments.
+	checkCompletionSyntax(ctx)
+
+	isBuckets := ctx.Bool("buckets")
+	if ctx.Bool("list") {
+		printCompletionCandidates(ctx.Args().First(), isBuckets)
+		return
+	}
+
+	data := newCompletionData(commands, globalFlags, isBuckets)
+	script, err := completionScript(ctx.Args().First(), data)
+	fatalIf(err.Trace(ctx.Args().First()), "Unable to generate completion script.")
+	console.Print(script)
+}
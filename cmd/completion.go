@@ -0,0 +1,397 @@
+/*
+ * Minio Client (C) 2016 Minio, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"bytes"
+	"encoding/json"
+	"io/ioutil"
+	"path/filepath"
+	"sort"
+	"strings"
+	"text/template"
+	"time"
+
+	"github.com/minio/cli"
+	"github.com/minio/mc/pkg/console"
+	"github.com/minio/minio/pkg/probe"
+)
+
+const bucketCompletionVersion = "1"
+
+// Buckets listed for completion are listed again after this time.
+const bucketCompletionTTL = 5 * time.Minute
+
+// Shells completion scripts are generated for.
+var completionShells = []string{"bash", "zsh", "fish"}
+
+// bucketCompletionCache - buckets of aliases listed for completion,
+// persisted so that every key press does not list them again.
+type bucketCompletionCache struct {
+	Version string                           `json:"version"`
+	Aliases map[string]bucketCompletionEntry `json:"aliases"`
+}
+
+// bucketCompletionEntry - buckets of an alias and when they were listed.
+type bucketCompletionEntry struct {
+	Updated time.Time `json:"updated"`
+	Buckets []string  `json:"buckets"`
+}
+
+// getBucketCompletionFile - file of the cached buckets.
+func getBucketCompletionFile() string {
+	return filepath.Join(mustGetMcConfigDir(), "completion.json")
+}
+
+// loadBucketCompletionCache - loads the cached buckets, an unreadable
+// file is replaced by an empty cache.
+func loadBucketCompletionCache(file string) *bucketCompletionCache {
+	cache := &bucketCompletionCache{}
+	if data, e := ioutil.ReadFile(file); e == nil {
+		json.Unmarshal(data, cache)
+	}
+	if cache.Version != bucketCompletionVersion || cache.Aliases == nil {
+		cache = &bucketCompletionCache{
+			Version: bucketCompletionVersion,
+			Aliases: make(map[string]bucketCompletionEntry),
+		}
+	}
+	return cache
+}
+
+// save - saves the cached buckets.
+func (b *bucketCompletionCache) save(file string) *probe.Error {
+	data, e := json.Marshal(b)
+	if e != nil {
+		return probe.NewError(e)
+	}
+	if e = ioutil.WriteFile(file, data, 0600); e != nil {
+		return probe.NewError(e)
+	}
+	return nil
+}
+
+// listAliasBuckets - names of the buckets of an alias.
+func listAliasBuckets(alias string) ([]string, *probe.Error) {
+	hostCfg := mustGetHostConfig(alias)
+	if hostCfg == nil {
+		return nil, errInvalidAliasedURL(alias).Trace(alias)
+	}
+	clnt, err := newClientFromAlias(alias, hostCfg.URL)
+	if err != nil {
+		return nil, err.Trace(alias)
+	}
+	var buckets []string
+	for content := range clnt.List(globalContext, false, false) {
+		if content.Err != nil {
+			return nil, content.Err.Trace(alias)
+		}
+		bucket := strings.Trim(filepath.ToSlash(content.URL.Path), "/")
+		buckets = append(buckets, bucket[strings.LastIndex(bucket, "/")+1:])
+	}
+	return buckets, nil
+}
+
+// cachedAliasBuckets - buckets of an alias, listed again once the
+// cached ones expired.
+func cachedAliasBuckets(alias string) []string {
+	file := getBucketCompletionFile()
+	cache := loadBucketCompletionCache(file)
+	if entry, ok := cache.Aliases[alias]; ok && time.Since(entry.Updated) < bucketCompletionTTL {
+		return entry.Buckets
+	}
+	buckets, err := listAliasBuckets(alias)
+	if err != nil {
+		// Completion does not print errors into the command line.
+		return nil
+	}
+	cache.Aliases[alias] = bucketCompletionEntry{Updated: time.Now().UTC(), Buckets: buckets}
+	cache.save(file)
+	return buckets
+}
+
+// completionCandidates - aliased URLs completing the word, aliases are
+// completed by their names and with listBuckets their buckets as well.
+func completionCandidates(word string, aliases []string, listBuckets func(alias string) []string) []string {
+	var candidates []string
+	i := strings.Index(word, "/")
+	if i < 0 {
+		for _, alias := range aliases {
+			if strings.HasPrefix(alias, word) {
+				candidates = append(candidates, alias+"/")
+			}
+		}
+		return candidates
+	}
+	alias, prefix := word[:i], word[i+1:]
+	if listBuckets == nil || strings.Contains(prefix, "/") {
+		return nil
+	}
+	for _, known := range aliases {
+		if known != alias {
+			continue
+		}
+		for _, bucket := range listBuckets(alias) {
+			if strings.HasPrefix(bucket, prefix) {
+				candidates = append(candidates, alias+"/"+bucket+"/")
+			}
+		}
+	}
+	return candidates
+}
+
+// configuredAliases - sorted aliases of the config file, patterns
+// are no aliases.
+func configuredAliases() []string {
+	conf, err := loadMcConfig()
+	if err != nil {
+		return nil
+	}
+	var aliases []string
+	for alias := range conf.Hosts {
+		if !isAliasPattern(alias) {
+			aliases = append(aliases, alias)
+		}
+	}
+	sort.Strings(aliases)
+	return aliases
+}
+
+// completionFlag - a flag offered for completion.
+type completionFlag struct {
+	Names []string
+	Usage string
+}
+
+// newCompletionFlag - names and usage of a flag as printed by help,
+// like ‘--recursive, -r<TAB>List recursively.’. Flags taking several
+// values repeat their names.
+func newCompletionFlag(flag cli.Flag) completionFlag {
+	var f completionFlag
+	fields := strings.SplitN(flag.String(), "\t", 2)
+	seen := make(map[string]bool)
+	for _, name := range strings.Fields(fields[0]) {
+		name = strings.Trim(name, ",[")
+		if strings.HasPrefix(name, "-") && !seen[name] {
+			seen[name] = true
+			f.Names = append(f.Names, name)
+		}
+	}
+	if len(fields) > 1 {
+		f.Usage = fields[1]
+	}
+	return f
+}
+
+// Long is the name of the flag without dashes, fish names long and
+// short flags separately.
+func (f completionFlag) Long() string {
+	for _, name := range f.Names {
+		if strings.HasPrefix(name, "--") {
+			return strings.TrimPrefix(name, "--")
+		}
+	}
+	return ""
+}
+
+// Short is the single letter name of the flag, if any.
+func (f completionFlag) Short() string {
+	for _, name := range f.Names {
+		if !strings.HasPrefix(name, "--") {
+			return strings.TrimPrefix(name, "-")
+		}
+	}
+	return ""
+}
+
+// completionCommand - a command offered for completion.
+type completionCommand struct {
+	Name        string
+	Usage       string
+	Flags       []completionFlag
+	Subcommands []string
+}
+
+// FlagNames - all names of the flags of the command.
+func (c completionCommand) FlagNames() string {
+	var names []string
+	for _, f := range c.Flags {
+		names = append(names, f.Names...)
+	}
+	return strings.Join(names, " ")
+}
+
+// completionData - commands and flags completed by the scripts.
+type completionData struct {
+	Commands    []completionCommand
+	GlobalFlags []completionFlag
+	Buckets     bool
+}
+
+// CommandNames - names of all commands.
+func (d completionData) CommandNames() string {
+	var names []string
+	for _, c := range d.Commands {
+		names = append(names, c.Name)
+	}
+	return strings.Join(names, " ")
+}
+
+// GlobalFlagNames - all names of the global flags.
+func (d completionData) GlobalFlagNames() string {
+	var names []string
+	for _, f := range d.GlobalFlags {
+		names = append(names, f.Names...)
+	}
+	return strings.Join(names, " ")
+}
+
+// newCompletionData - commands and flags of the application.
+func newCompletionData(cmds []cli.Command, globals []cli.Flag, buckets bool) completionData {
+	data := completionData{Buckets: buckets}
+	for _, flag := range globals {
+		data.GlobalFlags = append(data.GlobalFlags, newCompletionFlag(flag))
+	}
+	for _, cmd := range cmds {
+		if cmd.Hide {
+			continue
+		}
+		c := completionCommand{Name: cmd.Name, Usage: cmd.Usage}
+		for _, flag := range cmd.Flags {
+			c.Flags = append(c.Flags, newCompletionFlag(flag))
+		}
+		for _, sub := range cmd.Subcommands {
+			if !sub.Hide {
+				c.Subcommands = append(c.Subcommands, sub.Name)
+			}
+		}
+		data.Commands = append(data.Commands, c)
+	}
+	return data
+}
+
+// Completion of bash, commands and subcommands are completed by name,
+// other arguments by aliased URLs and local files.
+const bashCompletionTemplate = `# bash completion of mc, generated by ‘mc completion bash’.
+_mc_completion() {
+    local cur="${COMP_WORDS[COMP_CWORD]}"
+    local cmd="" cmdIndex=0 i
+    for ((i = 1; i < COMP_CWORD; i++)); do
+        case "${COMP_WORDS[i]}" in
+            -*) ;;
+            *) cmd="${COMP_WORDS[i]}"; cmdIndex=$i; break ;;
+        esac
+    done
+    COMPREPLY=()
+    if [[ -z "$cmd" ]]; then
+        if [[ "$cur" == -* ]]; then
+            COMPREPLY=($(compgen -W "{{.GlobalFlagNames}}" -- "$cur"))
+        else
+            COMPREPLY=($(compgen -W "{{.CommandNames}}" -- "$cur"))
+        fi
+        return
+    fi
+    case "$cmd" in
+{{- range .Commands}}
+        {{.Name}})
+            if [[ "$cur" == -* ]]; then
+                COMPREPLY=($(compgen -W "{{.FlagNames}}" -- "$cur"))
+                return
+            fi
+{{- if .Subcommands}}
+            if ((COMP_CWORD == cmdIndex + 1)); then
+                COMPREPLY=($(compgen -W "{{join .Subcommands " "}}" -- "$cur"))
+                return
+            fi
+{{- end}}
+            ;;
+{{- end}}
+    esac
+    compopt -o nospace 2>/dev/null
+    COMPREPLY=($(mc completion --list{{if .Buckets}} --buckets{{end}} "$cur" 2>/dev/null) $(compgen -f -- "$cur"))
+}
+complete -F _mc_completion mc
+`
+
+// Completion of zsh runs the bash completion.
+const zshCompletionTemplate = `# zsh completion of mc, generated by ‘mc completion zsh’.
+autoload -U +X compinit && compinit
+autoload -U +X bashcompinit && bashcompinit
+` + bashCompletionTemplate
+
+// Completion of fish, with descriptions of commands and flags.
+const fishCompletionTemplate = `# fish completion of mc, generated by ‘mc completion fish’.
+complete -c mc -f
+{{- range .GlobalFlags}}
+complete -c mc -n "__fish_use_subcommand"{{if .Long}} -l {{.Long}}{{end}}{{if .Short}} -s {{.Short}}{{end}} -d {{quote .Usage}}
+{{- end}}
+{{- range .Commands}}
+complete -c mc -n "__fish_use_subcommand" -a {{.Name}} -d {{quote .Usage}}
+{{- $name := .Name}}
+{{- range .Subcommands}}
+complete -c mc -n "__fish_seen_subcommand_from {{$name}}" -a {{.}}
+{{- end}}
+{{- range .Flags}}
+complete -c mc -n "__fish_seen_subcommand_from {{$name}}"{{if .Long}} -l {{.Long}}{{end}}{{if .Short}} -s {{.Short}}{{end}} -d {{quote .Usage}}
+{{- end}}
+{{- end}}
+complete -c mc -n "not __fish_use_subcommand" -a "(mc completion --list{{if .Buckets}} --buckets{{end}} (commandline -ct) 2>/dev/null)"
+complete -c mc -n "not __fish_use_subcommand" -F
+`
+
+// completionScript - completion script of the shell.
+func completionScript(shell string, data completionData) (string, *probe.Error) {
+	var text string
+	switch shell {
+	case "bash":
+		text = bashCompletionTemplate
+	case "zsh":
+		text = zshCompletionTemplate
+	case "fish":
+		text = fishCompletionTemplate
+	default:
+		return "", errInvalidArgument().Trace(shell)
+	}
+	funcs := template.FuncMap{
+		"join": strings.Join,
+		// Descriptions are single quoted for the shell.
+		"quote": func(s string) string {
+			return "'" + strings.Replace(s, "'", `\'`, -1) + "'"
+		},
+	}
+	t, e := template.New(shell).Funcs(funcs).Parse(text)
+	if e != nil {
+		return "", probe.NewError(e)
+	}
+	var script bytes.Buffer
+	if e = t.Execute(&script, data); e != nil {
+		return "", probe.NewError(e)
+	}
+	return script.String(), nil
+}
+
+// printCompletionCandidates - prints the candidates completing the
+// word, one per line.
+func printCompletionCandidates(word string, buckets bool) {
+	var listBuckets func(string) []string
+	if buckets {
+		listBuckets = cachedAliasBuckets
+	}
+	for _, candidate := range completionCandidates(word, configuredAliases(), listBuckets) {
+		console.Println(candidate)
+	}
+}
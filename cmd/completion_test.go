@@ -0,0 +1,76 @@
+/*
+ * Minio Client (C) 2016 Minio, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"strings"
+
+	"github.com/minio/cli"
+	. "gopkg.in/check.v1"
+)
+
+// Test completion of aliases and buckets.
+func (s *TestSuite) TestCompletionCandidates(c *C) {
+	aliases := []string{"gcs", "play", "s3"}
+	listBuckets := func(alias string) []string {
+		c.Assert(alias, Equals, "play")
+		return []string{"photos", "private", "reports"}
+	}
+	testCases := []struct {
+		word        string
+		listBuckets func(string) []string
+		candidates  []string
+	}{
+		{"", nil, []string{"gcs/", "play/", "s3/"}},
+		{"p", nil, []string{"play/"}},
+		{"x", nil, nil},
+		// Buckets are only completed if enabled.
+		{"play/p", nil, nil},
+		{"play/p", listBuckets, []string{"play/photos/", "play/private/"}},
+		{"play/", listBuckets, []string{"play/photos/", "play/private/", "play/reports/"}},
+		// Unknown aliases and objects are not completed.
+		{"minio/p", listBuckets, nil},
+		{"play/photos/2017", listBuckets, nil},
+	}
+	for i, testCase := range testCases {
+		candidates := completionCandidates(testCase.word, aliases, testCase.listBuckets)
+		c.Assert(candidates, DeepEquals, testCase.candidates, Commentf("Test %d", i+1))
+	}
+}
+
+// Test scripts complete commands and their flags.
+func (s *TestSuite) TestCompletionScript(c *C) {
+	cmds := []cli.Command{
+		{Name: "ls", Usage: "List files and folders.", Flags: []cli.Flag{
+			cli.BoolFlag{Name: "recursive, r", Usage: "List recursively."},
+			cli.StringSliceFlag{Name: "include", Value: &cli.StringSlice{}, Usage: "Only list files matching the pattern."},
+		}},
+		{Name: "config", Usage: "Manage configuration file.", Subcommands: []cli.Command{{Name: "host"}, {Name: "alias", Hide: true}}},
+	}
+	data := newCompletionData(cmds, []cli.Flag{cli.BoolFlag{Name: "quiet, q", Usage: "Suppress chatty console output."}}, true)
+	c.Assert(data.Commands[0].FlagNames(), Equals, "--recursive -r --include")
+	c.Assert(data.Commands[1].Subcommands, DeepEquals, []string{"host"})
+
+	for _, shell := range completionShells {
+		script, err := completionScript(shell, data)
+		c.Assert(err, IsNil, Commentf("%s", shell))
+		c.Assert(strings.Contains(script, "completion --list --buckets"), Equals, true, Commentf("%s", shell))
+		c.Assert(strings.Contains(script, "recursive"), Equals, true, Commentf("%s", shell))
+	}
+	_, err := completionScript("tcsh", data)
+	c.Assert(err, NotNil)
+}
@@ -0,0 +1,219 @@
+/*
+ * Minio Client (C) 2016 Minio, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"bufio"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/minio/go-homedir"
+	"github.com/minio/minio/pkg/probe"
+)
+
+// URL of Amazon S3, used by profiles without an endpoint.
+const awsDefaultURL = "https://s3.amazonaws.com"
+
+// awsProfiles - settings of AWS CLI profiles by name, merged from the
+// shared credentials and config files.
+type awsProfiles map[string]map[string]string
+
+// loadAWSProfiles - reads the profiles of the AWS CLI, files are
+// located as by the AWS CLI. Missing files hold no profiles.
+func loadAWSProfiles() (awsProfiles, *probe.Error) {
+	homeDir, e := homedir.Dir()
+	if e != nil {
+		return nil, probe.NewError(e)
+	}
+	credentialsFile := os.Getenv("AWS_SHARED_CREDENTIALS_FILE")
+	if credentialsFile == "" {
+		credentialsFile = filepath.Join(homeDir, ".aws", "credentials")
+	}
+	configFile := os.Getenv("AWS_CONFIG_FILE")
+	if configFile == "" {
+		configFile = filepath.Join(homeDir, ".aws", "config")
+	}
+
+	profiles := make(awsProfiles)
+	for _, file := range []string{configFile, credentialsFile} {
+		f, e := os.Open(file)
+		if os.IsNotExist(e) {
+			continue
+		}
+		if e != nil {
+			return nil, probe.NewError(e).Trace(file)
+		}
+		// Sections of the config file are named ‘profile NAME’,
+		// except ‘default’.
+		err := profiles.parse(bufio.NewScanner(f), file == configFile)
+		f.Close()
+		if err != nil {
+			return nil, err.Trace(file)
+		}
+	}
+	return profiles, nil
+}
+
+// parse - merges the profiles of an INI file. Settings nested below a
+// setting without value, e.g. ‘s3 =’, are prefixed by its name.
+func (p awsProfiles) parse(scanner *bufio.Scanner, isConfig bool) *probe.Error {
+	var profile map[string]string
+	var parent string
+	for scanner.Scan() {
+		line := scanner.Text()
+		trimmed := strings.TrimSpace(line)
+		if trimmed == "" || strings.HasPrefix(trimmed, "#") || strings.HasPrefix(trimmed, ";") {
+			continue
+		}
+		if strings.HasPrefix(trimmed, "[") && strings.HasSuffix(trimmed, "]") {
+			name := strings.TrimSpace(trimmed[1 : len(trimmed)-1])
+			if isConfig && name != "default" {
+				if !strings.HasPrefix(name, "profile ") {
+					// Other sections, e.g. of SSO sessions.
+					profile = nil
+					continue
+				}
+				name = strings.TrimSpace(strings.TrimPrefix(name, "profile "))
+			}
+			if p[name] == nil {
+				p[name] = make(map[string]string)
+			}
+			profile = p[name]
+			parent = ""
+			continue
+		}
+		if profile == nil {
+			continue
+		}
+		i := strings.Index(trimmed, "=")
+		if i < 0 {
+			return errInvalidAWSConfigLine(line).Trace(line)
+		}
+		key := strings.TrimSpace(trimmed[:i])
+		value := strings.TrimSpace(trimmed[i+1:])
+		if parent != "" && line != strings.TrimLeft(line, " \t") {
+			profile[parent+"."+key] = value
+			continue
+		}
+		parent = ""
+		if value == "" {
+			parent = key
+			continue
+		}
+		profile[key] = value
+	}
+	if e := scanner.Err(); e != nil {
+		return probe.NewError(e)
+	}
+	return nil
+}
+
+// names - sorted names of all profiles.
+func (p awsProfiles) names() []string {
+	var names []string
+	for name := range p {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// hostConfig - host config equivalent to the profile. Keys of profiles
+// assuming a role are those of their ‘source_profile’ chain.
+func (p awsProfiles) hostConfig(name string) (hostConfigV8, *probe.Error) {
+	profile, ok := p[name]
+	if !ok {
+		return hostConfigV8{}, errAWSProfileNotFound(name).Trace(name)
+	}
+	hostCfg := hostConfigV8{
+		URL:        awsDefaultURL,
+		API:        "S3v4",
+		RoleARN:    profile["role_arn"],
+		ExternalID: profile["external_id"],
+		Region:     profile["region"],
+		CACert:     profile["ca_bundle"],
+	}
+	if endpoint := profile["s3.endpoint_url"]; endpoint != "" {
+		hostCfg.URL = endpoint
+	} else if endpoint := profile["endpoint_url"]; endpoint != "" {
+		hostCfg.URL = endpoint
+	}
+	if profile["s3.addressing_style"] == "virtual" {
+		hostCfg.Lookup = "dns"
+	} else if profile["s3.addressing_style"] == "path" {
+		hostCfg.Lookup = "path"
+	}
+
+	// Follow the chain of source profiles to the keys.
+	visited := map[string]bool{name: true}
+	source := profile
+	for source["source_profile"] != "" {
+		sourceName := source["source_profile"]
+		if visited[sourceName] {
+			return hostConfigV8{}, errAWSProfileUnsupported(name, "source profiles form a loop").Trace(name, sourceName)
+		}
+		visited[sourceName] = true
+		if source, ok = p[sourceName]; !ok {
+			return hostConfigV8{}, errAWSProfileNotFound(sourceName).Trace(name, sourceName)
+		}
+		if source["role_arn"] != "" {
+			return hostConfigV8{}, errAWSProfileUnsupported(name, "roles assumed by roles are not supported").Trace(name, sourceName)
+		}
+		if hostCfg.Region == "" {
+			hostCfg.Region = source["region"]
+		}
+	}
+	for _, key := range []string{"credential_source", "sso_start_url", "sso_session"} {
+		if source[key] != "" {
+			return hostConfigV8{}, errAWSProfileUnsupported(name, "‘"+key+"’ is not supported").Trace(name)
+		}
+	}
+	hostCfg.AccessKey = source["aws_access_key_id"]
+	hostCfg.SecretKey = source["aws_secret_access_key"]
+	hostCfg.SessionToken = source["aws_session_token"]
+	hostCfg.CredentialProcess = source["credential_process"]
+	if hostCfg.CredentialProcess == "" && (hostCfg.AccessKey == "" || hostCfg.SecretKey == "") {
+		return hostConfigV8{}, errAWSProfileUnsupported(name, "no access and secret keys").Trace(name)
+	}
+	return hostCfg, nil
+}
+
+// awsProfileAlias - alias of a profile, ‘aws’ for the default profile
+// and ‘aws-NAME’ for others.
+func awsProfileAlias(name string) string {
+	if name == "default" {
+		return "aws"
+	}
+	return "aws-" + strings.Trim(regexp.MustCompile("[^a-zA-Z0-9-]+").ReplaceAllString(name, "-"), "-")
+}
+
+// isAmazonHostURL - reports whether the URL is of Amazon S3.
+func isAmazonHostURL(urlStr string) bool {
+	host := newClientURL(urlStr).Host
+	return strings.HasSuffix(host, "amazonaws.com") || strings.HasSuffix(host, "amazonaws.com.cn")
+}
+
+// awsEnvKeys - keys of the AWS environment, if both are set. Session
+// token is read separately from ‘AWS_SESSION_TOKEN’.
+func awsEnvKeys() (accessKey, secretKey string, ok bool) {
+	accessKey = os.Getenv("AWS_ACCESS_KEY_ID")
+	secretKey = os.Getenv("AWS_SECRET_ACCESS_KEY")
+	return accessKey, secretKey, accessKey != "" && secretKey != ""
+}
@@ -0,0 +1,187 @@
+/*
+ * Minio Client (C) 2016 Minio, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"encoding/json"
+	"os"
+
+	"github.com/fatih/color"
+	"github.com/minio/cli"
+	"github.com/minio/mc/pkg/console"
+	"github.com/minio/minio/pkg/probe"
+)
+
+var (
+	configEncryptFlags = []cli.Flag{
+		cli.BoolFlag{
+			Name:  "help, h",
+			Usage: "Help of config encrypt",
+		},
+	}
+)
+
+var configEncryptCmd = cli.Command{
+	Name:   "encrypt",
+	Usage:  "Encrypt secret keys in configuration file with a passphrase.",
+	Flags:  append(configEncryptFlags, globalFlags...),
+	Action: mainConfigEncrypt,
+	CustomHelpTemplate: `NAME:
+   mc config {{.Name}} - {{.Usage}}
+
+USAGE:
+   mc config {{.Name}}
+
+   Secret keys and session tokens of all hosts are encrypted with a key derived from
+   the passphrase. Whenever a host is used, the passphrase is read from MC_CONFIG_PASSPHRASE
+   or prompted for on the terminal. Set MC_CONFIG_PASSPHRASE from an OS keychain to
+   unlock without prompts.
+
+FLAGS:
+  {{range .Flags}}{{.}}
+  {{end}}
+EXAMPLES:
+   1. Encrypt secret keys, prompting for the passphrase.
+      $ mc config {{.Name}}
+
+   2. Use hosts of an encrypted config, with the passphrase stored in the macOS keychain.
+      $ export MC_CONFIG_PASSPHRASE=$(security find-generic-password -w -s mc)
+      $ mc ls s3/mybucket
+`,
+}
+
+var configDecryptCmd = cli.Command{
+	Name:   "decrypt",
+	Usage:  "Decrypt secret keys in configuration file.",
+	Flags:  append(configEncryptFlags, globalFlags...),
+	Action: mainConfigDecrypt,
+	CustomHelpTemplate: `NAME:
+   mc config {{.Name}} - {{.Usage}}
+
+USAGE:
+   mc config {{.Name}}
+
+FLAGS:
+  {{range .Flags}}{{.}}
+  {{end}}
+EXAMPLES:
+   1. Store secret keys in plain text again, prompting for the passphrase.
+      $ mc config {{.Name}}
+`,
+}
+
+// configEncryptMessage container for encrypt and decrypt messages.
+type configEncryptMessage struct {
+	op     string
+	Status string `json:"status"`
+	Hosts  int    `json:"hosts"`
+}
+
+// String colorized encrypt message.
+func (m configEncryptMessage) String() string {
+	if m.op == "decrypt" {
+		return console.Colorize("EncryptMessage", "Decrypted secrets of config successfully.")
+	}
+	return console.Colorize("EncryptMessage", "Encrypted secrets of config successfully.")
+}
+
+// JSON jsonified encrypt message.
+func (m configEncryptMessage) JSON() string {
+	m.Status = "success"
+	jsonMessageBytes, e := json.Marshal(m)
+	fatalIf(probe.NewError(e), "Unable to marshal into JSON.")
+
+	return string(jsonMessageBytes)
+}
+
+// checkConfigEncryptSyntax - validate all the passed arguments.
+func checkConfigEncryptSyntax(ctx *cli.Context, name string) {
+	if ctx.Args().Present() {
+		cli.ShowCommandHelpAndExit(ctx, name, 1) // last argument is exit code
+	}
+}
+
+func mainConfigEncrypt(ctx *cli.Context) {
+	// Set global flags from context.
+	setGlobalsFromContext(ctx)
+
+	// check 'config encrypt' cli arguments.
+	checkConfigEncryptSyntax(ctx, "encrypt")
+
+	console.SetColor("EncryptMessage", color.New(color.FgGreen))
+
+	conf, err := loadMcConfig()
+	fatalIf(err.Trace(globalMCConfigVersion), "Unable to load config ‘"+mustGetMcConfigPath()+"’.")
+	if conf.Encryption != nil {
+		fatalIf(errDummy().Trace(), "Config ‘"+mustGetMcConfigPath()+"’ is already encrypted.")
+	}
+
+	passphrase, err := readConfigPassphrase("Enter new passphrase of the config: ")
+	fatalIf(err.Trace(), "Unable to read passphrase.")
+	// Typos can not be recovered from, prompted passphrases are confirmed.
+	if os.Getenv("MC_CONFIG_PASSPHRASE") == "" {
+		confirm, err := readConfigPassphrase("Confirm passphrase: ")
+		fatalIf(err.Trace(), "Unable to read passphrase.")
+		if confirm != passphrase {
+			fatalIf(errDummy().Trace(), "Passphrases do not match.")
+		}
+	}
+
+	enc, key, err := newConfigEncryption(passphrase)
+	fatalIf(err.Trace(), "Unable to derive key of the config.")
+	for alias, hostCfg := range conf.Hosts {
+		hostCfg, err = encryptHostConfig(key, hostCfg)
+		fatalIf(err.Trace(alias), "Unable to encrypt host ‘"+alias+"’.")
+		conf.Hosts[alias] = hostCfg
+	}
+	conf.Encryption = enc
+
+	err = saveMcConfig(conf)
+	fatalIf(err.Trace(), "Unable to save config ‘"+mustGetMcConfigPath()+"’.")
+
+	printMsg(configEncryptMessage{op: "encrypt", Hosts: len(conf.Hosts)})
+}
+
+func mainConfigDecrypt(ctx *cli.Context) {
+	// Set global flags from context.
+	setGlobalsFromContext(ctx)
+
+	// check 'config decrypt' cli arguments.
+	checkConfigEncryptSyntax(ctx, "decrypt")
+
+	console.SetColor("EncryptMessage", color.New(color.FgGreen))
+
+	conf, err := loadMcConfig()
+	fatalIf(err.Trace(globalMCConfigVersion), "Unable to load config ‘"+mustGetMcConfigPath()+"’.")
+	if conf.Encryption == nil {
+		fatalIf(errDummy().Trace(), "Config ‘"+mustGetMcConfigPath()+"’ is not encrypted.")
+	}
+
+	key, err := unlockConfig(conf.Encryption)
+	fatalIf(err.Trace(), "Unable to unlock config ‘"+mustGetMcConfigPath()+"’.")
+	for alias, hostCfg := range conf.Hosts {
+		hostCfg, err = decryptHostConfig(key, hostCfg)
+		fatalIf(err.Trace(alias), "Unable to decrypt host ‘"+alias+"’.")
+		conf.Hosts[alias] = hostCfg
+	}
+	conf.Encryption = nil
+
+	err = saveMcConfig(conf)
+	fatalIf(err.Trace(), "Unable to save config ‘"+mustGetMcConfigPath()+"’.")
+
+	printMsg(configEncryptMessage{op: "decrypt", Hosts: len(conf.Hosts)})
+}
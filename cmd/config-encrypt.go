@@ -0,0 +1,255 @@
+/*
+ * Minio Client (C) 2016 Minio, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"bufio"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"runtime"
+	"strings"
+	"sync"
+
+	"github.com/mattn/go-isatty"
+	"github.com/minio/minio/pkg/probe"
+)
+
+const (
+	// Prefix of encrypted values in the config file.
+	configEncryptedPrefix = "enc:"
+	// Iterations of PBKDF2 deriving the key from the passphrase.
+	configKeyIterations = 100000
+	// Plain text of the value checking the passphrase.
+	configCheckText = "mc"
+)
+
+// configEncryptionV8 - parameters of the key secret keys of hosts are
+// encrypted with. The key is derived from a passphrase.
+type configEncryptionV8 struct {
+	Salt       string `json:"salt"`
+	Iterations int    `json:"iterations"`
+	// Encrypted known text, to tell wrong passphrases.
+	Check string `json:"check"`
+}
+
+// Key of the config, derived once per invocation.
+var (
+	configKey   []byte
+	configKeyMu sync.Mutex
+)
+
+// pbkdf2SHA256 - derives a key of the given length from the password,
+// see RFC 2898.
+func pbkdf2SHA256(password, salt []byte, iterations, keyLen int) []byte {
+	prf := hmac.New(sha256.New, password)
+	var key []byte
+	for block := uint32(1); len(key) < keyLen; block++ {
+		prf.Reset()
+		prf.Write(salt)
+		var counter [4]byte
+		binary.BigEndian.PutUint32(counter[:], block)
+		prf.Write(counter[:])
+		u := prf.Sum(nil)
+		t := append([]byte(nil), u...)
+		for i := 1; i < iterations; i++ {
+			prf.Reset()
+			prf.Write(u)
+			u = prf.Sum(u[:0])
+			for j := range t {
+				t[j] ^= u[j]
+			}
+		}
+		key = append(key, t...)
+	}
+	return key[:keyLen]
+}
+
+// newConfigEncryption - parameters of a new key derived from the
+// passphrase, which is returned along.
+func newConfigEncryption(passphrase string) (*configEncryptionV8, []byte, *probe.Error) {
+	salt := make([]byte, 32)
+	if _, e := io.ReadFull(rand.Reader, salt); e != nil {
+		return nil, nil, probe.NewError(e)
+	}
+	enc := &configEncryptionV8{
+		Salt:       base64.StdEncoding.EncodeToString(salt),
+		Iterations: configKeyIterations,
+	}
+	key := pbkdf2SHA256([]byte(passphrase), salt, enc.Iterations, 32)
+	check, err := encryptConfigValue(key, configCheckText)
+	if err != nil {
+		return nil, nil, err.Trace()
+	}
+	enc.Check = check
+	return enc, key, nil
+}
+
+// deriveKey - key of the passphrase, fails if the passphrase is wrong.
+func (enc *configEncryptionV8) deriveKey(passphrase string) ([]byte, *probe.Error) {
+	salt, e := base64.StdEncoding.DecodeString(enc.Salt)
+	if e != nil {
+		return nil, probe.NewError(e)
+	}
+	key := pbkdf2SHA256([]byte(passphrase), salt, enc.Iterations, 32)
+	if check, err := decryptConfigValue(key, enc.Check); err != nil || check != configCheckText {
+		return nil, errWrongConfigPassphrase().Trace()
+	}
+	return key, nil
+}
+
+// isEncryptedConfigValue - reports whether the value is encrypted.
+func isEncryptedConfigValue(value string) bool {
+	return strings.HasPrefix(value, configEncryptedPrefix)
+}
+
+// encryptConfigValue - encrypts the value with AES-GCM, empty values
+// are kept as is.
+func encryptConfigValue(key []byte, value string) (string, *probe.Error) {
+	if value == "" || isEncryptedConfigValue(value) {
+		return value, nil
+	}
+	block, e := aes.NewCipher(key)
+	if e != nil {
+		return "", probe.NewError(e)
+	}
+	aead, e := cipher.NewGCM(block)
+	if e != nil {
+		return "", probe.NewError(e)
+	}
+	nonce := make([]byte, aead.NonceSize())
+	if _, e = io.ReadFull(rand.Reader, nonce); e != nil {
+		return "", probe.NewError(e)
+	}
+	sealed := aead.Seal(nonce, nonce, []byte(value), nil)
+	return configEncryptedPrefix + base64.StdEncoding.EncodeToString(sealed), nil
+}
+
+// decryptConfigValue - decrypts a value, values which are not encrypted
+// are returned as is.
+func decryptConfigValue(key []byte, value string) (string, *probe.Error) {
+	if !isEncryptedConfigValue(value) {
+		return value, nil
+	}
+	sealed, e := base64.StdEncoding.DecodeString(strings.TrimPrefix(value, configEncryptedPrefix))
+	if e != nil {
+		return "", probe.NewError(e)
+	}
+	block, e := aes.NewCipher(key)
+	if e != nil {
+		return "", probe.NewError(e)
+	}
+	aead, e := cipher.NewGCM(block)
+	if e != nil {
+		return "", probe.NewError(e)
+	}
+	if len(sealed) < aead.NonceSize() {
+		return "", errWrongConfigPassphrase().Trace()
+	}
+	plain, e := aead.Open(nil, sealed[:aead.NonceSize()], sealed[aead.NonceSize():], nil)
+	if e != nil {
+		return "", errWrongConfigPassphrase().Trace()
+	}
+	return string(plain), nil
+}
+
+// encryptHostConfig - encrypts the secrets of the host.
+func encryptHostConfig(key []byte, hostCfg hostConfigV8) (hostConfigV8, *probe.Error) {
+	var err *probe.Error
+	if hostCfg.SecretKey, err = encryptConfigValue(key, hostCfg.SecretKey); err != nil {
+		return hostCfg, err.Trace()
+	}
+	if hostCfg.SessionToken, err = encryptConfigValue(key, hostCfg.SessionToken); err != nil {
+		return hostCfg, err.Trace()
+	}
+	return hostCfg, nil
+}
+
+// decryptHostConfig - decrypts the secrets of the host.
+func decryptHostConfig(key []byte, hostCfg hostConfigV8) (hostConfigV8, *probe.Error) {
+	var err *probe.Error
+	if hostCfg.SecretKey, err = decryptConfigValue(key, hostCfg.SecretKey); err != nil {
+		return hostCfg, err.Trace()
+	}
+	if hostCfg.SessionToken, err = decryptConfigValue(key, hostCfg.SessionToken); err != nil {
+		return hostCfg, err.Trace()
+	}
+	return hostCfg, nil
+}
+
+// unlockConfig - key of the encrypted config, the passphrase is read
+// from ‘MC_CONFIG_PASSPHRASE’ or prompted for on the terminal.
+func unlockConfig(enc *configEncryptionV8) ([]byte, *probe.Error) {
+	configKeyMu.Lock()
+	defer configKeyMu.Unlock()
+	if configKey != nil {
+		return configKey, nil
+	}
+	passphrase, err := readConfigPassphrase("Enter passphrase of the config: ")
+	if err != nil {
+		return nil, err.Trace()
+	}
+	key, err := enc.deriveKey(passphrase)
+	if err != nil {
+		return nil, err.Trace()
+	}
+	configKey = key
+	return configKey, nil
+}
+
+// readConfigPassphrase - passphrase of ‘MC_CONFIG_PASSPHRASE’, or read
+// from the terminal without echo.
+func readConfigPassphrase(prompt string) (string, *probe.Error) {
+	if passphrase := os.Getenv("MC_CONFIG_PASSPHRASE"); passphrase != "" {
+		return passphrase, nil
+	}
+	if !isatty.IsTerminal(os.Stdin.Fd()) {
+		return "", errConfigLocked().Trace()
+	}
+	fmt.Fprint(os.Stderr, prompt)
+	// Keys typed are not echoed, where stty is available.
+	if runtime.GOOS != "windows" {
+		stty := exec.Command("stty", "-echo")
+		stty.Stdin = os.Stdin
+		if stty.Run() == nil {
+			defer func() {
+				stty := exec.Command("stty", "echo")
+				stty.Stdin = os.Stdin
+				stty.Run()
+				fmt.Fprintln(os.Stderr)
+			}()
+		}
+	}
+	line, e := bufio.NewReader(os.Stdin).ReadString('\n')
+	if e != nil && e != io.EOF {
+		return "", probe.NewError(e)
+	}
+	passphrase := strings.TrimRight(line, "\r\n")
+	if passphrase == "" {
+		return "", probe.NewError(errors.New("Passphrase can not be empty."))
+	}
+	return passphrase, nil
+}
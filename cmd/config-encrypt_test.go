@@ -0,0 +1,70 @@
+/*
+ * Minio Client (C) 2016 Minio, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"encoding/hex"
+
+	. "gopkg.in/check.v1"
+)
+
+// Test encrypting secrets of the config.
+func (s *TestSuite) TestConfigEncryption(c *C) {
+	// Test vectors of PBKDF2-HMAC-SHA256.
+	key := pbkdf2SHA256([]byte("password"), []byte("salt"), 1, 32)
+	c.Assert(hex.EncodeToString(key), Equals, "120fb6cffcf8b32c43e7225256c4f837a86548c92ccc35480805987cb70be17b")
+	key = pbkdf2SHA256([]byte("password"), []byte("salt"), 2, 32)
+	c.Assert(hex.EncodeToString(key), Equals, "ae4d0c95af6b46d32d0adff928f06dd02a303f8ef3c251dfd6e2d85a95474c43")
+
+	enc, key, err := newConfigEncryption("passphrase")
+	c.Assert(err, IsNil)
+	derived, err := enc.deriveKey("passphrase")
+	c.Assert(err, IsNil)
+	c.Assert(derived, DeepEquals, key)
+	_, err = enc.deriveKey("wrong")
+	c.Assert(err, Not(IsNil))
+
+	hostCfg := hostConfigV8{
+		URL:          "https://s3.amazonaws.com",
+		AccessKey:    "BKIKJAA5BMMU2RHO6IBB",
+		SecretKey:    "V8f1CwQqAcwo80UEIJEjc5gVQUSSx5ohQ9GSrr12",
+		SessionToken: "token",
+		API:          "S3v4",
+	}
+	encrypted, err := encryptHostConfig(key, hostCfg)
+	c.Assert(err, IsNil)
+	c.Assert(isEncryptedConfigValue(encrypted.SecretKey), Equals, true)
+	c.Assert(isEncryptedConfigValue(encrypted.SessionToken), Equals, true)
+	c.Assert(encrypted.AccessKey, Equals, hostCfg.AccessKey)
+
+	// Encrypted values are not encrypted twice.
+	again, err := encryptHostConfig(key, encrypted)
+	c.Assert(err, IsNil)
+	c.Assert(again, DeepEquals, encrypted)
+
+	decrypted, err := decryptHostConfig(key, encrypted)
+	c.Assert(err, IsNil)
+	c.Assert(decrypted, DeepEquals, hostCfg)
+
+	_, err = decryptHostConfig(pbkdf2SHA256([]byte("wrong"), []byte("salt"), 1, 32), encrypted)
+	c.Assert(err, Not(IsNil))
+
+	// Anonymous hosts have nothing to encrypt.
+	anonymous, err := encryptHostConfig(key, hostConfigV8{URL: "http://localhost:9000"})
+	c.Assert(err, IsNil)
+	c.Assert(anonymous.SecretKey, Equals, "")
+}
@@ -20,7 +20,6 @@ import (
 	"fmt"
 	"strings"
 
-	"github.com/minio/mc/pkg/console"
 	"github.com/minio/minio/pkg/probe"
 	"github.com/minio/minio/pkg/quick"
 )
@@ -107,7 +106,7 @@ func fixConfigV3() {
 		e = mcNewConfigV3.Save(mustGetMcConfigPath())
 		fatalIf(probe.NewError(e), "Unable to save config version ‘3’.")
 
-		console.Infof("Successfully fixed %s broken config for version ‘3’.\n", mustGetMcConfigPath())
+		printInfo(fmt.Sprintf("Successfully fixed %s broken config for version ‘3’.", mustGetMcConfigPath()))
 	}
 }
 
@@ -132,7 +131,7 @@ func fixConfigV6ForHosts() {
 		newCfgV6.Aliases[k] = v
 	}
 
-	url := &clientURL{}
+	url := &ClientURL{}
 	// Copy hosts.
 	for host, hostCfgV6 := range brokenMcCfgV6.Data().(*configV6).Hosts {
 		// Already fixed - Copy and move on.
@@ -145,7 +144,7 @@ func fixConfigV6ForHosts() {
 		if host == "s3.amazonaws.com" || host == "storage.googleapis.com" ||
 			host == "localhost:9000" || host == "127.0.0.1:9000" ||
 			host == "play.minio.io:9000" || host == "dl.minio.io:9000" {
-			console.Infoln("Found broken host entries, replacing " + host + " with https://" + host + ".")
+			printInfo("Found broken host entries, replacing " + host + " with https://" + host + ".")
 			url.Host = host
 			url.Scheme = "https"
 			url.SchemeSeparator = "://"
@@ -192,37 +191,37 @@ func fixConfigV6() {
 				fmt.Sprintf("Glob style ‘*’ pattern matching is no longer supported. Please fix ‘%s’ entry manually.", host))
 		}
 		if strings.Contains(host, "*s3*") || strings.Contains(host, "*.s3*") {
-			console.Infoln("Found glob url, replacing " + host + " with s3.amazonaws.com")
+			printInfo("Found glob url, replacing " + host + " with s3.amazonaws.com")
 			newConfig.Hosts["s3.amazonaws.com"] = hostCfg
 			isMutated = true
 			continue
 		}
 		if strings.Contains(host, "s3*") {
-			console.Infoln("Found glob url, replacing " + host + " with s3.amazonaws.com")
+			printInfo("Found glob url, replacing " + host + " with s3.amazonaws.com")
 			newConfig.Hosts["s3.amazonaws.com"] = hostCfg
 			isMutated = true
 			continue
 		}
 		if strings.Contains(host, "*amazonaws.com") || strings.Contains(host, "*.amazonaws.com") {
-			console.Infoln("Found glob url, replacing " + host + " with s3.amazonaws.com")
+			printInfo("Found glob url, replacing " + host + " with s3.amazonaws.com")
 			newConfig.Hosts["s3.amazonaws.com"] = hostCfg
 			isMutated = true
 			continue
 		}
 		if strings.Contains(host, "*storage.googleapis.com") {
-			console.Infoln("Found glob url, replacing " + host + " with storage.googleapis.com")
+			printInfo("Found glob url, replacing " + host + " with storage.googleapis.com")
 			newConfig.Hosts["storage.googleapis.com"] = hostCfg
 			isMutated = true
 			continue
 		}
 		if strings.Contains(host, "localhost:*") {
-			console.Infoln("Found glob url, replacing " + host + " with localhost:9000")
+			printInfo("Found glob url, replacing " + host + " with localhost:9000")
 			newConfig.Hosts["localhost:9000"] = hostCfg
 			isMutated = true
 			continue
 		}
 		if strings.Contains(host, "127.0.0.1:*") {
-			console.Infoln("Found glob url, replacing " + host + " with 127.0.0.1:9000")
+			printInfo("Found glob url, replacing " + host + " with 127.0.0.1:9000")
 			newConfig.Hosts["127.0.0.1:9000"] = hostCfg
 			isMutated = true
 			continue
@@ -237,6 +236,6 @@ func fixConfigV6() {
 
 		e = newConf.Save(mustGetMcConfigPath())
 		fatalIf(probe.NewError(e).Trace(mustGetMcConfigPath()), "Unable to save newly fixed config path.")
-		console.Infof("Successfully fixed %s broken config for version ‘6’.\n", mustGetMcConfigPath())
+		printInfo(fmt.Sprintf("Successfully fixed %s broken config for version ‘6’.", mustGetMcConfigPath()))
 	}
 }
@@ -0,0 +1,145 @@
+/*
+ * Minio Client (C) 2016 Minio, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"encoding/json"
+	"strings"
+
+	"github.com/fatih/color"
+	"github.com/minio/cli"
+	"github.com/minio/mc/pkg/console"
+	"github.com/minio/minio/pkg/probe"
+)
+
+var (
+	configImportFlags = []cli.Flag{
+		cli.BoolFlag{
+			Name:  "help, h",
+			Usage: "Help of config import",
+		},
+		cli.StringFlag{
+			Name:  "profile",
+			Usage: "Import only this profile, all profiles are imported by default.",
+		},
+	}
+)
+
+var configImportCmd = cli.Command{
+	Name:   "import",
+	Usage:  "Import hosts from the configuration of other tools.",
+	Flags:  append(configImportFlags, globalFlags...),
+	Action: mainConfigImport,
+	CustomHelpTemplate: `NAME:
+   mc config {{.Name}} - {{.Usage}}
+
+USAGE:
+   mc config {{.Name}} [FLAGS] SOURCE
+
+SOURCE:
+   aws    Profiles of the AWS CLI in ~/.aws/credentials and ~/.aws/config, or the files
+          set by AWS_SHARED_CREDENTIALS_FILE and AWS_CONFIG_FILE. The default profile is
+          imported as alias "aws", others as "aws-PROFILE".
+
+FLAGS:
+  {{range .Flags}}{{.}}
+  {{end}}
+EXAMPLES:
+   1. Import all profiles of the AWS CLI.
+      $ mc config {{.Name}} aws
+
+   2. Import the "prod" profile of the AWS CLI as alias "aws-prod".
+      $ mc config {{.Name}} --profile prod aws
+`,
+}
+
+// configImportMessage container for an imported profile.
+type configImportMessage struct {
+	Status  string `json:"status"`
+	Profile string `json:"profile"`
+	Alias   string `json:"alias"`
+	URL     string `json:"URL"`
+}
+
+// String colorized import message.
+func (i configImportMessage) String() string {
+	return console.Colorize("ImportMessage", "Imported profile ‘"+i.Profile+"’ as ‘"+i.Alias+"’ for ‘"+i.URL+"’.")
+}
+
+// JSON jsonified import message.
+func (i configImportMessage) JSON() string {
+	i.Status = "success"
+	jsonMessageBytes, e := json.Marshal(i)
+	fatalIf(probe.NewError(e), "Unable to marshal into JSON.")
+
+	return string(jsonMessageBytes)
+}
+
+// checkConfigImportSyntax - validate all the passed arguments.
+func checkConfigImportSyntax(ctx *cli.Context) {
+	if len(ctx.Args()) != 1 || strings.TrimSpace(ctx.Args().First()) != "aws" {
+		cli.ShowCommandHelpAndExit(ctx, "import", 1) // last argument is exit code
+	}
+}
+
+func mainConfigImport(ctx *cli.Context) {
+	// Set global flags from context.
+	setGlobalsFromContext(ctx)
+
+	// check 'config import' cli arguments.
+	checkConfigImportSyntax(ctx)
+
+	console.SetColor("ImportMessage", color.New(color.FgGreen))
+
+	profiles, err := loadAWSProfiles()
+	fatalIf(err.Trace(), "Unable to load profiles of the AWS CLI.")
+
+	names := profiles.names()
+	if profile := ctx.String("profile"); profile != "" {
+		names = []string{profile}
+	}
+	if len(names) == 0 {
+		fatalIf(errDummy().Trace(), "No profiles of the AWS CLI found.")
+	}
+
+	conf, err := loadMcConfig()
+	fatalIf(err.Trace(globalMCConfigVersion), "Unable to load config ‘"+mustGetMcConfigPath()+"’.")
+
+	var imported []configImportMessage
+	for _, name := range names {
+		hostCfg, err := profiles.hostConfig(name)
+		if err != nil {
+			// Importing a single profile fails, others are skipped.
+			if len(names) == 1 {
+				fatalIf(err.Trace(name), "Unable to import profile ‘"+name+"’.")
+			}
+			errorIf(err.Trace(name), "Skipping profile ‘"+name+"’.")
+			continue
+		}
+		alias := awsProfileAlias(name)
+		err = conf.addHost(alias, hostCfg)
+		fatalIf(err.Trace(alias), "Unable to add host ‘"+alias+"’.")
+		imported = append(imported, configImportMessage{Profile: name, Alias: alias, URL: hostCfg.URL})
+	}
+
+	err = saveMcConfig(conf)
+	fatalIf(err.Trace(), "Unable to update hosts in config ‘"+mustGetMcConfigPath()+"’.")
+
+	for _, msg := range imported {
+		printMsg(msg)
+	}
+}
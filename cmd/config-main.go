@@ -45,6 +45,9 @@ var configCmd = cli.Command{
 	Flags:  append(configFlags, globalFlags...),
 	Subcommands: []cli.Command{
 		configHostCmd,
+		configImportCmd,
+		configEncryptCmd,
+		configDecryptCmd,
 	},
 	CustomHelpTemplate: `NAME:
    {{.Name}} - {{.Usage}}
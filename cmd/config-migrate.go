@@ -20,7 +20,6 @@ import (
 	"fmt"
 	"strings"
 
-	"github.com/minio/mc/pkg/console"
 	"github.com/minio/minio/pkg/probe"
 	"github.com/minio/minio/pkg/quick"
 )
@@ -99,7 +98,7 @@ func migrateConfigV1ToV101() {
 	e = mcCfgV101.Save(mustGetMcConfigPath())
 	fatalIf(probe.NewError(e), "Unable to save config version ‘1.0.1’.")
 
-	console.Infof("Successfully migrated %s from version ‘1.0.0’ to version ‘1.0.1’.\n", mustGetMcConfigPath())
+	printInfo(fmt.Sprintf("Successfully migrated %s from version ‘1.0.0’ to version ‘1.0.1’.", mustGetMcConfigPath()))
 }
 
 // Migrate from config ‘1.0.1’ to ‘2’. Drop semantic versioning and move to integer versioning. No other changes.
@@ -136,7 +135,7 @@ func migrateConfigV101ToV2() {
 	e = mcCfgV2.Save(mustGetMcConfigPath())
 	fatalIf(probe.NewError(e), "Unable to save config version ‘2’.")
 
-	console.Infof("Successfully migrated %s from version ‘1.0.1’ to version ‘2’.\n", mustGetMcConfigPath())
+	printInfo(fmt.Sprintf("Successfully migrated %s from version ‘1.0.1’ to version ‘2’.", mustGetMcConfigPath()))
 }
 
 // Migrate from config ‘2’ to ‘3’. Use ‘-’ separated names for
@@ -176,7 +175,7 @@ func migrateConfigV2ToV3() {
 	e = mcNewCfgV3.Save(mustGetMcConfigPath())
 	fatalIf(probe.NewError(e), "Unable to save config version ‘3’.")
 
-	console.Infof("Successfully migrated %s from version ‘2’ to version ‘3’.\n", mustGetMcConfigPath())
+	printInfo(fmt.Sprintf("Successfully migrated %s from version ‘2’ to version ‘3’.", mustGetMcConfigPath()))
 }
 
 // Migrate from config version ‘3’ to ‘4’. Introduce API Signature
@@ -215,7 +214,7 @@ func migrateConfigV3ToV4() {
 	e = mcNewCfgV4.Save(mustGetMcConfigPath())
 	fatalIf(probe.NewError(e), "Unable to save config version ‘4’.")
 
-	console.Infof("Successfully migrated %s from version ‘3’ to version ‘4’.\n", mustGetMcConfigPath())
+	printInfo(fmt.Sprintf("Successfully migrated %s from version ‘3’ to version ‘4’.", mustGetMcConfigPath()))
 
 }
 
@@ -250,7 +249,7 @@ func migrateConfigV4ToV5() {
 	e = mcNewCfgV5.Save(mustGetMcConfigPath())
 	fatalIf(probe.NewError(e), "Unable to save config version ‘5’.")
 
-	console.Infof("Successfully migrated %s from version ‘4’ to version ‘5’.\n", mustGetMcConfigPath())
+	printInfo(fmt.Sprintf("Successfully migrated %s from version ‘4’ to version ‘5’.", mustGetMcConfigPath()))
 }
 
 // Migrate config version ‘5’ to ‘6’. Add google cloud storage servers
@@ -316,7 +315,7 @@ func migrateConfigV5ToV6() {
 	e = mcNewCfgV6.Save(mustGetMcConfigPath())
 	fatalIf(probe.NewError(e), "Unable to save config version ‘6’.")
 
-	console.Infof("Successfully migrated %s from version ‘5’ to version ‘6’.\n", mustGetMcConfigPath())
+	printInfo(fmt.Sprintf("Successfully migrated %s from version ‘5’ to version ‘6’.", mustGetMcConfigPath()))
 }
 
 // Migrate config version ‘6’ to ‘7'. Remove alias map and introduce
@@ -395,7 +394,7 @@ func migrateConfigV6ToV7() {
 	e = mcNewCfgV7.Save(mustGetMcConfigPath())
 	fatalIf(probe.NewError(e), "Unable to save config version ‘7’.")
 
-	console.Infof("Successfully migrated %s from version ‘6’ to version ‘7’.\n", mustGetMcConfigPath())
+	printInfo(fmt.Sprintf("Successfully migrated %s from version ‘6’ to version ‘7’.", mustGetMcConfigPath()))
 }
 
 // Migrate config version ‘7’ to ‘8'. Remove hosts
@@ -434,5 +433,5 @@ func migrateConfigV7ToV8() {
 	e = mcNewCfgV8.Save(mustGetMcConfigPath())
 	fatalIf(probe.NewError(e), "Unable to save config version ‘8’.")
 
-	console.Infof("Successfully migrated %s from version ‘7’ to version ‘8’.\n", mustGetMcConfigPath())
+	printInfo(fmt.Sprintf("Successfully migrated %s from version ‘7’ to version ‘8’.", mustGetMcConfigPath()))
 }
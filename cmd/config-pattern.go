@@ -0,0 +1,150 @@
+/*
+ * Minio Client (C) 2016 Minio, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"path"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/minio/minio/pkg/probe"
+)
+
+// Aliases of hosts are patterns like ‘s3-*’ if they hold any of the
+// wildcards of path.Match. Patterns have no URL, their settings are
+// shared by all hosts they match which do not set them, ‘*’ is the
+// default of all hosts.
+
+var aliasPatternRgx = regexp.MustCompile(`^[a-zA-Z0-9*?\[\]^-]+$`)
+
+// isAliasPattern - reports whether the alias is a pattern.
+func isAliasPattern(alias string) bool {
+	return strings.ContainsAny(alias, "*?[")
+}
+
+// isValidAliasPattern - reports whether the alias is a valid pattern.
+func isValidAliasPattern(alias string) bool {
+	if !isAliasPattern(alias) || !aliasPatternRgx.MatchString(alias) {
+		return false
+	}
+	_, e := path.Match(alias, "")
+	return e == nil
+}
+
+// byPatternSpecificity - sorts longer patterns first, they are more
+// specific than the shorter patterns matching the same alias.
+type byPatternSpecificity []string
+
+func (p byPatternSpecificity) Len() int      { return len(p) }
+func (p byPatternSpecificity) Swap(i, j int) { p[i], p[j] = p[j], p[i] }
+func (p byPatternSpecificity) Less(i, j int) bool {
+	if len(p[i]) != len(p[j]) {
+		return len(p[i]) > len(p[j])
+	}
+	return p[i] < p[j]
+}
+
+// matchingPatterns - patterns of the config matching the alias, most
+// specific first.
+func (c *configV8) matchingPatterns(alias string) []string {
+	var patterns []string
+	for pattern := range c.Hosts {
+		if !isAliasPattern(pattern) {
+			continue
+		}
+		if ok, _ := path.Match(pattern, alias); ok {
+			patterns = append(patterns, pattern)
+		}
+	}
+	sort.Sort(byPatternSpecificity(patterns))
+	return patterns
+}
+
+// hasMatchingPattern - reports whether any pattern of the config
+// matches the alias.
+func hasMatchingPattern(alias string) bool {
+	conf, err := loadMcConfig()
+	return err == nil && len(conf.matchingPatterns(alias)) > 0
+}
+
+// resolveHost - host config of the alias with the settings it does
+// not set taken from the matching patterns, decrypted if the config
+// is encrypted.
+func (c *configV8) resolveHost(alias string, hostCfg hostConfigV8) (hostConfigV8, *probe.Error) {
+	var key []byte
+	if c.Encryption != nil {
+		var err *probe.Error
+		if key, err = unlockConfig(c.Encryption); err != nil {
+			return hostConfigV8{}, err.Trace(alias)
+		}
+		if hostCfg, err = decryptHostConfig(key, hostCfg); err != nil {
+			return hostConfigV8{}, err.Trace(alias)
+		}
+	}
+	for _, pattern := range c.matchingPatterns(alias) {
+		patternCfg := c.Hosts[pattern]
+		if key != nil {
+			var err *probe.Error
+			if patternCfg, err = decryptHostConfig(key, patternCfg); err != nil {
+				return hostConfigV8{}, err.Trace(alias, pattern)
+			}
+		}
+		hostCfg = inheritHostConfig(hostCfg, patternCfg)
+	}
+	if hostCfg.API == "" {
+		hostCfg.API = "S3v4"
+	}
+	return hostCfg, nil
+}
+
+// inheritHostConfig - host config with the settings it does not set
+// taken from the pattern. Keys, roles and client certificates are
+// only taken as a whole.
+func inheritHostConfig(hostCfg, pattern hostConfigV8) hostConfigV8 {
+	if hostCfg.AccessKey == "" && hostCfg.SecretKey == "" && hostCfg.CredentialProcess == "" && hostCfg.Credentials == "" {
+		hostCfg.AccessKey = pattern.AccessKey
+		hostCfg.SecretKey = pattern.SecretKey
+		hostCfg.SessionToken = pattern.SessionToken
+		hostCfg.CredentialProcess = pattern.CredentialProcess
+		hostCfg.Credentials = pattern.Credentials
+	}
+	if hostCfg.API == "" {
+		hostCfg.API = pattern.API
+	}
+	if hostCfg.RoleARN == "" {
+		hostCfg.RoleARN = pattern.RoleARN
+		hostCfg.ExternalID = pattern.ExternalID
+	}
+	if hostCfg.Region == "" {
+		hostCfg.Region = pattern.Region
+	}
+	if hostCfg.Lookup == "" {
+		hostCfg.Lookup = pattern.Lookup
+	}
+	if hostCfg.Payload == "" {
+		hostCfg.Payload = pattern.Payload
+	}
+	if hostCfg.CACert == "" {
+		hostCfg.CACert = pattern.CACert
+	}
+	if hostCfg.Cert == "" {
+		hostCfg.Cert = pattern.Cert
+		hostCfg.Key = pattern.Key
+	}
+	return hostCfg
+}
@@ -0,0 +1,69 @@
+/*
+ * Minio Client (C) 2016 Minio, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	. "gopkg.in/check.v1"
+)
+
+// Test settings of hosts shared by patterns.
+func (s *TestSuite) TestAliasPatterns(c *C) {
+	c.Check(isValidAliasPattern("s3-*"), Equals, true)
+	c.Check(isValidAliasPattern("*"), Equals, true)
+	c.Check(isValidAliasPattern("s3-[ae]?"), Equals, true)
+	c.Check(isValidAliasPattern("s3"), Equals, false)
+	c.Check(isValidAliasPattern("s3-[a"), Equals, false)
+	c.Check(isValidAliasPattern("s3/*"), Equals, false)
+
+	conf := newConfigV8()
+	conf.Hosts["*"] = hostConfigV8{Lookup: "path", Region: "us-east-1"}
+	conf.Hosts["s3-*"] = hostConfigV8{AccessKey: "WLGDGYAQYIGI833EV05A", SecretKey: "BYvgJM101sHngl2uzjXS/OBF/aMxAN06JrJ3qJlF", API: "S3v2"}
+	conf.Hosts["s3-eu*"] = hostConfigV8{Region: "eu-west-1"}
+	conf.Hosts["s3-eu"] = hostConfigV8{URL: "https://s3.eu-west-1.amazonaws.com"}
+	conf.Hosts["play"] = hostConfigV8{URL: "https://play.minio.io:9000", AccessKey: "Q3AM3UQ867SPQQA43P2F", SecretKey: "zuf+tfteSlswRu7BJ86wekitnifILbZam1KYY3TG", Lookup: "dns"}
+
+	c.Assert(conf.matchingPatterns("s3-eu"), DeepEquals, []string{"s3-eu*", "s3-*", "*"})
+	c.Assert(conf.matchingPatterns("play"), DeepEquals, []string{"*"})
+
+	hostCfg, err := conf.resolveHost("s3-eu", conf.Hosts["s3-eu"])
+	c.Assert(err, IsNil)
+	c.Assert(hostCfg, Equals, hostConfigV8{
+		URL:       "https://s3.eu-west-1.amazonaws.com",
+		AccessKey: "WLGDGYAQYIGI833EV05A",
+		SecretKey: "BYvgJM101sHngl2uzjXS/OBF/aMxAN06JrJ3qJlF",
+		API:       "S3v2",
+		Region:    "eu-west-1",
+		Lookup:    "path",
+	})
+
+	// Settings of the host are kept.
+	hostCfg, err = conf.resolveHost("play", conf.Hosts["play"])
+	c.Assert(err, IsNil)
+	c.Assert(hostCfg, Equals, hostConfigV8{
+		URL:       "https://play.minio.io:9000",
+		AccessKey: "Q3AM3UQ867SPQQA43P2F",
+		SecretKey: "zuf+tfteSlswRu7BJ86wekitnifILbZam1KYY3TG",
+		API:       "S3v4",
+		Region:    "us-east-1",
+		Lookup:    "dns",
+	})
+
+	ok, _ := validateConfigHost(conf.Hosts["s3-*"], true)
+	c.Assert(ok, Equals, true)
+	ok, _ = validateConfigHost(conf.Hosts["s3-*"], false)
+	c.Assert(ok, Equals, false)
+}
@@ -21,7 +21,10 @@ import (
 	"strings"
 )
 
-var validAPIs = []string{"S3v4", "S3v2"}
+var validAPIs = []string{"S3v4", "S3v2", "GCS"}
+
+// Styles of addressing buckets, empty lets the client decide by host.
+var validLookups = []string{"dns", "path"}
 
 // isValidSecretKey - validate secret key.
 func isValidSecretKey(secretKey string) bool {
@@ -59,9 +62,35 @@ func isValidHostURL(hostURL string) bool {
 // isValidAPI - Validates if API signature string of supported type.
 func isValidAPI(api string) bool {
 	switch strings.ToLower(api) {
-	case "s3v2", "s3v4":
+	case "s3v2", "s3v4", "gcs":
+		return true
+	default:
+		return false
+	}
+}
+
+// canonicalAPI - API as spelled in the config, e.g. ‘S3v4’ for ‘s3V4’.
+func canonicalAPI(api string) string {
+	for _, validAPI := range validAPIs {
+		if strings.EqualFold(api, validAPI) {
+			return validAPI
+		}
+	}
+	return api
+}
+
+// isValidLookup - validate bucket lookup style, i.e dns or path.
+func isValidLookup(lookup string) bool {
+	switch lookup {
+	case "", "dns", "path":
 		return true
 	default:
 		return false
 	}
 }
+
+// isValidRoleARN - validate IAM role ARN, i.e arn:aws:iam::123456789012:role/name
+func isValidRoleARN(roleARN string) bool {
+	regex := regexp.MustCompile(`^arn:[a-z-]+:iam::[0-9]{12}:role/.+$`)
+	return regex.MatchString(roleARN)
+}
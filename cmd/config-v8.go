@@ -41,12 +41,47 @@ type hostConfigV8 struct {
 	AccessKey string `json:"accessKey"`
 	SecretKey string `json:"secretKey"`
 	API       string `json:"api"`
+	// Optional token of temporary keys.
+	SessionToken string `json:"sessionToken,omitempty"`
+	// Optional command printing keys as JSON, instead of the keys above.
+	CredentialProcess string `json:"credentialProcess,omitempty"`
+	// Optional role assumed through STS.
+	RoleARN    string `json:"roleARN,omitempty"`
+	ExternalID string `json:"externalID,omitempty"`
+	// Optional region of all buckets of the host.
+	Region string `json:"region,omitempty"`
+	// Key file of a Google service account, only for API ‘GCS’.
+	Credentials string `json:"credentials,omitempty"`
+	// Optional style of addressing buckets, ‘dns’ or ‘path’.
+	Lookup string `json:"lookup,omitempty"`
+	// Optional signing of payloads, ‘unsigned’ or ‘streaming’.
+	Payload string `json:"payload,omitempty"`
+	// Optional PEM files of a CA bundle and of a client certificate.
+	CACert string `json:"caCert,omitempty"`
+	Cert   string `json:"cert,omitempty"`
+	Key    string `json:"key,omitempty"`
+	// Optional serial number and current token of an MFA device,
+	// overridden by ‘MC_MFA_<ALIAS>’.
+	MFA string `json:"mfa,omitempty"`
 }
 
 // configV8 config version.
 type configV8 struct {
 	Version string                  `json:"version"`
 	Hosts   map[string]hostConfigV8 `json:"hosts"`
+	// Secrets of hosts are encrypted if set.
+	Encryption *configEncryptionV8 `json:"encryption,omitempty"`
+	// Tuning of HTTP connections to all hosts.
+	Transport *transportConfigV8 `json:"transport,omitempty"`
+}
+
+// transportConfigV8 - timeouts like ‘30s’ and idle connections kept
+// per host, defaults are used for unset values.
+type transportConfigV8 struct {
+	DialTimeout           string `json:"dialTimeout,omitempty"`
+	ResponseHeaderTimeout string `json:"responseHeaderTimeout,omitempty"`
+	TLSHandshakeTimeout   string `json:"tlsHandshakeTimeout,omitempty"`
+	MaxIdleConnsPerHost   int    `json:"maxIdleConnsPerHost,omitempty"`
 }
 
 // newConfigV8 - new config version.
@@ -64,6 +99,22 @@ func (c *configV8) setHost(alias string, cfg hostConfigV8) {
 	}
 }
 
+// addHost - adds or replaces a host, secrets of encrypted configs are
+// encrypted.
+func (c *configV8) addHost(alias string, cfg hostConfigV8) *probe.Error {
+	if c.Encryption != nil {
+		key, err := unlockConfig(c.Encryption)
+		if err != nil {
+			return err.Trace(alias)
+		}
+		if cfg, err = encryptHostConfig(key, cfg); err != nil {
+			return err.Trace(alias)
+		}
+	}
+	c.Hosts[alias] = cfg
+	return nil
+}
+
 // load default values for missing entries.
 func (c *configV8) loadDefaults() {
 	// Minio server running locally.
@@ -40,9 +40,15 @@ func validateConfigFile(config *configV8) (bool, []string) {
 		validationSuccessful = false
 		errors = append(errors, err)
 	}
+	if config.Transport != nil {
+		if _, err := parseTransportConfig(config.Transport); err != nil {
+			validationSuccessful = false
+			errors = append(errors, fmt.Sprintf("Transport of the config is not valid. %s\n", err.ToGoError()))
+		}
+	}
 	hosts := config.Hosts
-	for _, hostConfig := range hosts {
-		hostConfigHealthOk, hostErrors := validateConfigHost(hostConfig)
+	for alias, hostConfig := range hosts {
+		hostConfigHealthOk, hostErrors := validateConfigHost(hostConfig, isAliasPattern(alias))
 		if !hostConfigHealthOk {
 			validationSuccessful = false
 			errors = append(errors, hostErrors...)
@@ -51,11 +57,12 @@ func validateConfigFile(config *configV8) (bool, []string) {
 	return validationSuccessful, errors
 }
 
-func validateConfigHost(host hostConfigV8) (bool, []string) {
+// Patterns have no URL, their hosts default to API ‘S3v4’.
+func validateConfigHost(host hostConfigV8, isPattern bool) (bool, []string) {
 	var validationSuccessful = true
 	var hostErrors []string
 	api := host.API
-	validAPI := isValidAPI(strings.ToLower(api))
+	validAPI := api == "" || isValidAPI(strings.ToLower(api))
 	if !validAPI {
 		var errorMsg bytes.Buffer
 		errorMsg.WriteString(fmt.Sprintf(
@@ -67,8 +74,21 @@ func validateConfigHost(host hostConfigV8) (bool, []string) {
 		validationSuccessful = false
 		hostErrors = append(hostErrors, errorMsg.String())
 	}
+	if !isValidLookup(host.Lookup) {
+		validationSuccessful = false
+		msg := fmt.Sprintf("Lookup %s for host %s is not valid. It is not one of %s.\n", host.Lookup, host.URL, strings.Join(validLookups, ", "))
+		hostErrors = append(hostErrors, msg)
+	}
+	if !isValidPayload(host.Payload) {
+		validationSuccessful = false
+		msg := fmt.Sprintf("Payload %s for host %s is not valid. It is not one of %s.\n", host.Payload, host.URL, strings.Join(validPayloads, ", "))
+		hostErrors = append(hostErrors, msg)
+	}
 	url := host.URL
 	validURL := isValidHostURL(url)
+	if isPattern {
+		validURL = url == ""
+	}
 	if !validURL {
 		validationSuccessful = false
 		msg := fmt.Sprintf("URL %s for host %s is not valid. Could not parse it.\n", url, host.URL)
@@ -21,6 +21,7 @@ import (
 	"path/filepath"
 	"regexp"
 	"runtime"
+	"strings"
 
 	"github.com/minio/go-homedir"
 	"github.com/minio/minio/pkg/probe"
@@ -159,9 +160,17 @@ func getHostConfig(alias string) (*hostConfigV8, *probe.Error) {
 		return nil, err.Trace(alias)
 	}
 
-	// if host is exact return quickly.
-	if _, ok := mcCfg.Hosts[alias]; ok {
-		hostCfg := mcCfg.Hosts[alias]
+	// Host set via command line is not saved in the config file.
+	if alias == endpointAlias && globalEndpoint != nil {
+		hostCfg := *globalEndpoint
+		return &hostCfg, nil
+	}
+
+	// if host is exact return quickly, patterns only hold settings
+	// of other hosts.
+	if hostCfg, ok := mcCfg.Hosts[alias]; ok && !isAliasPattern(alias) {
+		hostCfg, err = mcCfg.resolveHost(alias, hostCfg)
+		fatalIf(err.Trace(alias), "Unable to load host ‘"+alias+"’ of config ‘"+mustGetMcConfigPath()+"’.")
 		return &hostCfg, nil
 	}
 
@@ -169,6 +178,51 @@ func getHostConfig(alias string) (*hostConfigV8, *probe.Error) {
 	return nil, errNoMatchingHost(alias).Trace(alias)
 }
 
+// Alias of the host set with ‘--endpoint-url’, which is not valid as
+// alias of the config file.
+const endpointAlias = "endpoint_url"
+
+// newEndpointHostConfig - host config of ‘--endpoint-url’ and its
+// keys, nil if no host is set.
+func newEndpointHostConfig(urlStr, accessKey, secretKey string) (*hostConfigV8, *probe.Error) {
+	if urlStr == "" {
+		if accessKey != "" || secretKey != "" {
+			return nil, errInvalidEndpoint("access and secret keys require ‘--endpoint-url’").Trace(accessKey)
+		}
+		return nil, nil
+	}
+	if !isValidHostURL(urlStr) {
+		return nil, errInvalidEndpoint("invalid URL ‘" + urlStr + "’").Trace(urlStr)
+	}
+	if (accessKey == "") != (secretKey == "") {
+		return nil, errInvalidEndpoint("access and secret keys are set together").Trace(urlStr, accessKey)
+	}
+	if accessKey != "" && (!isValidAccessKey(accessKey) || !isValidSecretKey(secretKey)) {
+		return nil, errInvalidEndpoint("invalid access or secret key").Trace(urlStr, accessKey)
+	}
+	return &hostConfigV8{URL: urlStr, AccessKey: accessKey, SecretKey: secretKey, API: "S3v4"}, nil
+}
+
+// isEndpointPath - reports whether the aliased URL names a path of the
+// host set with ‘--endpoint-url’. Configured aliases take precedence,
+// existing paths and paths starting with a separator or a dot or ‘~’
+// are local, as are URLs and Windows drives.
+func isEndpointPath(aliasedURL string) bool {
+	if globalEndpoint == nil || aliasedURL == "" {
+		return false
+	}
+	slashPath := filepath.ToSlash(aliasedURL)
+	if strings.HasPrefix(slashPath, "/") || strings.HasPrefix(slashPath, ".") || strings.HasPrefix(slashPath, "~") {
+		return false
+	}
+	alias, _ := url2Alias(aliasedURL)
+	if strings.Contains(alias, ":") || mustGetHostConfig(alias) != nil {
+		return false
+	}
+	_, e := os.Lstat(aliasedURL)
+	return e != nil
+}
+
 // mustGetHostConfig retrieves host specific configuration such as access keys, signature type.
 func mustGetHostConfig(alias string) *hostConfigV8 {
 	hostCfg, _ := getHostConfig(alias)
@@ -184,6 +238,11 @@ func expandAlias(aliasedURL string) (alias string, urlStr string, hostCfg *hostC
 	if hostCfg = mustGetHostConfig(alias); hostCfg != nil {
 		return alias, urlJoinPath(hostCfg.URL, path), hostCfg, nil
 	}
+	// Other paths name buckets of the host set via command line.
+	if isEndpointPath(aliasedURL) {
+		hostCfg = mustGetHostConfig(endpointAlias)
+		return endpointAlias, urlJoinPath(hostCfg.URL, filepath.ToSlash(aliasedURL)), hostCfg, nil
+	}
 	return "", aliasedURL, nil, nil // No matching entry found. Return original URL as is.
 }
 
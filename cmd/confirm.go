@@ -0,0 +1,110 @@
+/*
+ * Minio Client (C) 2016 Minio, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/dustin/go-humanize"
+	"github.com/mattn/go-isatty"
+	"github.com/minio/cli"
+)
+
+// yesFlag - confirms destructive operations without asking.
+var yesFlag = cli.BoolFlag{
+	Name:  "yes, y",
+	Usage: "Do not ask for confirmation of destructive operations, for scripts.",
+}
+
+// Objects counted for the impact of removals, larger numbers are not
+// counted exactly.
+const maxConfirmObjects = 10000
+
+// isInteractive - reports whether the user can be asked to confirm,
+// answers are read from a terminal.
+func isInteractive() bool {
+	return isatty.IsTerminal(os.Stdin.Fd())
+}
+
+// readConfirmation - reports whether the answer confirms, only ‘y’ and
+// ‘yes’ or their translations do.
+func readConfirmation(r io.Reader) bool {
+	answer, _ := bufio.NewReader(r).ReadString('\n')
+	switch strings.ToLower(strings.TrimSpace(answer)) {
+	case "y", "yes", tr("y"), tr("yes"):
+		return true
+	}
+	return false
+}
+
+// confirmOperation - asks on stderr to confirm the operation of the
+// impact, so that the output stays parseable.
+func confirmOperation(impact string) bool {
+	fmt.Fprint(os.Stderr, trf("%s Continue? [y/N]: ", impact))
+	return readConfirmation(os.Stdin)
+}
+
+// mustConfirm - asks to confirm the operation unless it was confirmed
+// with ‘--yes’ or nobody can be asked, exits if it was declined.
+func mustConfirm(ctx *cli.Context, impact string) {
+	if ctx.Bool("yes") || !isInteractive() {
+		return
+	}
+	if !confirmOperation(impact) {
+		fatalIf(errNotConfirmed().Trace(ctx.Args()...), "Nothing was changed.")
+	}
+}
+
+// describeObjects - number and size of the objects below the URL, like
+// ‘12 objects (1.5MiB)’, as impact of removing them.
+func describeObjects(url string, isIncomplete bool) string {
+	clnt, err := newClient(url)
+	if err != nil {
+		return tr("all objects")
+	}
+	// Listings stopped early are cancelled and drained, so that they
+	// end instead of listing on.
+	ctx, cancel := context.WithCancel(globalContext)
+	defer cancel()
+	contentCh := clnt.List(ctx, true, isIncomplete)
+	defer func() {
+		go func() {
+			for range contentCh {
+			}
+		}()
+	}()
+	var objects, size int64
+	for content := range contentCh {
+		if content.Err != nil {
+			return tr("all objects")
+		}
+		if content.Type.IsDir() {
+			continue
+		}
+		if objects == maxConfirmObjects {
+			return trf("more than %s objects", humanize.Comma(maxConfirmObjects))
+		}
+		objects++
+		size += content.Size
+	}
+	return trf("%s objects (%s)", humanize.Comma(objects), humanize.IBytes(uint64(size)))
+}
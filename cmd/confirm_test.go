@@ -0,0 +1,74 @@
+/*
+ * Minio Client (C) 2016 Minio, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"net/http/httptest"
+	"strings"
+	"time"
+
+	"github.com/minio/minio/pkg/probe"
+	. "gopkg.in/check.v1"
+)
+
+func (s *TestSuite) TestReadConfirmation(c *C) {
+	testCases := []struct {
+		answer    string
+		confirmed bool
+	}{
+		{"y\n", true},
+		{"yes\n", true},
+		{" YES \n", true},
+		{"Y", true},
+		{"n\n", false},
+		{"no\n", false},
+		{"\n", false},
+		{"", false},
+		{"yess\n", false},
+	}
+	for _, testCase := range testCases {
+		c.Assert(readConfirmation(strings.NewReader(testCase.answer)), Equals, testCase.confirmed, Commentf("answer %q", testCase.answer))
+	}
+}
+
+// Test that counting objects to confirm removals stops listing once
+// enough objects were counted.
+func (s *TestSuite) TestDescribeObjects(c *C) {
+	handler := &endlessListHandler{pageSize: 1000}
+	server := httptest.NewServer(handler)
+	defer server.Close()
+
+	defer func(load func() (*configV8, *probe.Error)) { loadMcConfig = load }(loadMcConfig)
+	loadMcConfig = func() (*configV8, *probe.Error) {
+		conf := newMcConfig()
+		conf.Hosts["s3"] = hostConfigV8{
+			URL:       server.URL,
+			AccessKey: "WLGDGYAQYIGI833EV05A",
+			SecretKey: "BYvgJM101sHngl2uzjXS/OBF/aMxAN06JrJ3qJlF",
+			API:       "S3v4",
+			Lookup:    "path",
+		}
+		return conf, nil
+	}
+
+	c.Assert(describeObjects("s3/bucket/", false), Equals, "more than 10,000 objects")
+	time.Sleep(100 * time.Millisecond)
+	listed := handler.listed()
+	time.Sleep(100 * time.Millisecond)
+	c.Assert(handler.listed(), Equals, listed)
+	handler.end()
+}
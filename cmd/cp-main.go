@@ -18,11 +18,15 @@ package cmd
 
 import (
 	"bufio"
+	"context"
 	"encoding/json"
 	"fmt"
 	"io"
+	"io/ioutil"
 	"os"
 	"path/filepath"
+	"sort"
+	"strconv"
 	"strings"
 	"sync"
 	"syscall"
@@ -45,6 +49,109 @@ var (
 			Name:  "recursive, r",
 			Usage: "Copy recursively.",
 		},
+		cli.BoolFlag{
+			Name:  "newer",
+			Usage: "Only copy files missing on the target, modified after their copy on the target, or of another size at the same time.",
+		},
+		cli.StringFlag{
+			Name:  "encrypt-key",
+			Usage: "Encrypt/decrypt objects with customer provided keys, as comma separated list of ‘ALIAS/PREFIX=KEY’.",
+		},
+		cli.StringFlag{
+			Name:  "encrypt",
+			Usage: "Encrypt objects with server managed keys (SSE-S3), as comma separated list of ‘ALIAS/PREFIX’. Use ‘ALIAS/PREFIX=KMS-KEY-ID’ for SSE-KMS.",
+		},
+		cli.StringFlag{
+			Name:  "part-size",
+			Usage: "Part size of multipart uploads, e.g. ‘128MiB’. Between 5MiB and 5GiB.",
+		},
+		cli.IntFlag{
+			Name:  "concurrent-parts",
+			Usage: "Number of parts of an object uploaded in parallel. Each part is buffered in memory.",
+		},
+		cli.IntFlag{
+			Name:  "parallel",
+			Value: 1,
+			Usage: "Number of objects copied in parallel, each with its own concurrent parts.",
+		},
+		cli.StringFlag{
+			Name:  "if-match",
+			Usage: "Copy only if the ETag of the source matches. Server side copies only.",
+		},
+		cli.StringFlag{
+			Name:  "if-none-match",
+			Usage: "Copy only if the ETag of the source does not match. Server side copies only.",
+		},
+		cli.StringFlag{
+			Name:  "if-unmodified-since",
+			Usage: "Copy only if the source was not modified since the given time, e.g. ‘2017-06-01’ or ‘2017-06-01T10:00:00Z’. Server side copies only.",
+		},
+		cli.BoolFlag{
+			Name:  "wait-restore",
+			Usage: "Wait for requested restores of archived objects to complete before copying them.",
+		},
+		cli.StringFlag{
+			Name:  "attr",
+			Usage: "Set metadata of the copied objects, as semicolon separated list of ‘KEY=VALUE’. Metadata of the source is retained.",
+		},
+		cli.BoolFlag{
+			Name:  "checksum",
+			Usage: "Verify downloaded and uploaded data against the SHA256 or the ETag of objects. Server side copies are not verified.",
+		},
+		cli.StringSliceFlag{
+			Name:  "include",
+			Value: &cli.StringSlice{},
+			Usage: "Only copy files of folders matching the gitignore style pattern, may be repeated.",
+		},
+		cli.StringSliceFlag{
+			Name:  "exclude",
+			Value: &cli.StringSlice{},
+			Usage: "Skip files and folders matching the gitignore style pattern, may be repeated.",
+		},
+		cli.StringFlag{
+			Name:  "newer-than",
+			Usage: "Only copy files modified within the given age, e.g. ‘12h’ or ‘7d’.",
+		},
+		cli.StringFlag{
+			Name:  "older-than",
+			Usage: "Only copy files modified before the given age, e.g. ‘30d’.",
+		},
+		cli.StringFlag{
+			Name:  "larger-than",
+			Usage: "Only copy files larger than the given size, e.g. ‘10MiB’.",
+		},
+		cli.StringFlag{
+			Name:  "smaller-than",
+			Usage: "Only copy files smaller than the given size, e.g. ‘1KiB’.",
+		},
+		cli.StringFlag{
+			Name:  "staging-prefix",
+			Usage: "Upload objects under the given prefix of the target bucket, and copy them to their names only once all objects were copied.",
+		},
+		cli.StringFlag{
+			Name:  "failure-manifest",
+			Usage: "Write objects which failed to copy to the given file, as one JSON object with source, target and error per line.",
+		},
+		cli.BoolFlag{
+			Name:  "follow-symlinks",
+			Usage: "Follow links to folders and copy the files in them, loops are reported.",
+		},
+		cli.BoolFlag{
+			Name:  "skip-symlinks",
+			Usage: "Skip links to files and folders.",
+		},
+		cli.BoolFlag{
+			Name:  "preserve-links",
+			Usage: "Copy links as empty objects holding the target of the link as metadata, and such objects back to links.",
+		},
+		cli.BoolFlag{
+			Name:  "preserve",
+			Usage: "Store mode, owner and modification time of files as metadata, and restore them when copying back to files.",
+		},
+		cli.BoolFlag{
+			Name:  "sparse",
+			Usage: "Skip uploading trailing zero blocks of files, they are restored as holes when copying back to files with ‘--sparse’.",
+		},
 	}
 )
 
@@ -81,6 +188,61 @@ EXAMPLES:
 
    6. Copy a local folder with space separated characters to Amazon S3 cloud storage.
       $ mc {{.Name}} --recursive 'workdir/documents/May 2014/' s3/miniocloud
+
+   7. Copy a folder to Amazon S3 cloud storage, encrypting it with a customer provided key.
+      $ mc {{.Name}} --recursive --encrypt-key "s3/documents=32byteslongsecretkeymustbegiven1" backup/ s3/documents/
+
+   8. Copy an object between buckets encrypted with a customer provided key and a KMS key.
+      $ mc {{.Name}} --encrypt-key "s3/secret=32byteslongsecretkeymustbegiven1" --encrypt "s3/archive=arn:aws:kms:us-east-1:123456789012:key/mc" s3/secret/report.pdf s3/archive/
+
+   9. Copy an object between buckets only if it was not replaced since its ETag was read.
+      $ mc {{.Name}} --if-match "9af2f8218b150c351ad802c6f3d66abe" s3/reports/q1.pdf s3/archive/
+
+  10. Copy a folder to Amazon S3 cloud storage with custom metadata and cache control.
+      $ mc {{.Name}} --recursive --attr "Cache-Control=max-age=86400;Project=Phoenix" website/ s3/static/
+
+  11. Copy a folder to Amazon S3 cloud storage, verifying the checksum of every uploaded object.
+      $ mc {{.Name}} --recursive --checksum backup/ s3/documents/
+
+  12. Copy a project folder to Amazon S3 cloud storage, skipping temporary files and the git repository.
+      $ mc {{.Name}} --recursive --exclude "*.tmp" --exclude ".git/" project/ s3/backups/
+
+  13. Copy logs written in the last day to Amazon S3 cloud storage.
+      $ mc {{.Name}} --recursive --newer-than 1d /var/log/app/ s3/logs/
+
+  14. Copy a folder of many small files to Amazon S3 cloud storage, eight files at a time.
+      $ mc {{.Name}} --recursive --parallel 8 ~/Photos/ s3/photos/
+
+  15. Copy a large object from Minio server to Amazon S3 cloud storage, streaming four parts at a time.
+      $ mc {{.Name}} --concurrent-parts 4 play/backups/disk.img s3/backups/
+
+  16. Copy a dataset to Amazon S3 cloud storage, so that readers never see only part of it.
+      $ mc {{.Name}} --recursive --staging-prefix .staging/ dataset/ s3/datalake/dataset/
+
+  17. Copy a folder to Amazon S3 cloud storage, printing a summary as JSON and listing failed objects in a manifest.
+      $ mc {{.Name}} --recursive --json --failure-manifest failed.json backup/ s3/documents/
+
+  18. Copy a folder with links to Amazon S3 cloud storage and back, keeping the links.
+      $ mc {{.Name}} --recursive --preserve-links backup/ s3/documents/
+      $ mc {{.Name}} --recursive --preserve-links s3/documents/ restore/
+
+  19. Back up a folder to Amazon S3 cloud storage and restore it with permissions, owners and modification times.
+      $ mc {{.Name}} --recursive --preserve /home/user/ s3/backups/home/
+      $ mc {{.Name}} --recursive --preserve s3/backups/home/ /home/user/
+
+  20. Copy a virtual machine image to Amazon S3 cloud storage and back, without its trailing zero blocks.
+      $ mc {{.Name}} --sparse vm/disk.img s3/images/
+      $ mc {{.Name}} --sparse s3/images/disk.img vm/
+
+  21. Migrate files from an SFTP server to Amazon S3 cloud storage, authenticating with a key, or with a password set in MC_SFTP_PASSWORD.
+      $ MC_SFTP_IDENTITY=~/.ssh/id_rsa mc {{.Name}} --recursive sftp://user@sftp.example.com/drop/ s3/archive/drop/
+      $ MC_SFTP_PASSWORD=secret mc {{.Name}} --recursive sftp://user@sftp.example.com:2222/~/outgoing/ s3/archive/outgoing/
+
+  22. Download a file from a web server directly to Amazon S3 cloud storage.
+      $ mc {{.Name}} https://cdimage.debian.org/debian-cd/current/amd64/iso-cd/debian-amd64-netinst.iso s3/images/
+
+  23. Copy only photos which were added or edited since the last copy to Amazon S3 cloud storage.
+      $ mc {{.Name}} --recursive --newer ~/Photos/ s3/photos/
 `,
 }
 
@@ -112,11 +274,6 @@ type copyStatMessage struct {
 	Speed       float64
 }
 
-const (
-	// 5GiB.
-	fiveGB = 5 * 1024 * 1024 * 1024
-)
-
 // copyStatMessage copy accounting message
 func (c copyStatMessage) String() string {
 	speedBox := pb.Format(int64(c.Speed)).To(pb.U_BYTES).String()
@@ -131,16 +288,12 @@ func (c copyStatMessage) String() string {
 }
 
 // doCopy - Copy a singe file from source to destination
-func doCopy(cpURLs URLs, progressReader *progressBar, accountingReader *accounter) URLs {
+func doCopy(ctx context.Context, cpURLs URLs, progressReader *progressBar, accountingReader *accounter, encKeys map[string]EncryptOpts, uploadOpts PutOpts, copyConds CopyOpts, waitRestore bool) URLs {
 	if cpURLs.Error != nil {
 		cpURLs.Error = cpURLs.Error.Trace()
 		return cpURLs
 	}
 
-	if !globalQuiet && !globalJSON {
-		progressReader = progressReader.SetCaption(cpURLs.SourceContent.URL.String() + ": ")
-	}
-
 	sourceAlias := cpURLs.SourceAlias
 	sourceURL := cpURLs.SourceContent.URL
 	targetAlias := cpURLs.TargetAlias
@@ -148,47 +301,113 @@ func doCopy(cpURLs URLs, progressReader *progressBar, accountingReader *accounte
 	length := cpURLs.SourceContent.Size
 
 	var progress io.Reader
-	if globalQuiet || globalJSON {
-		sourcePath := filepath.ToSlash(filepath.Join(sourceAlias, sourceURL.Path))
-		targetPath := filepath.ToSlash(filepath.Join(targetAlias, targetURL.Path))
-		printMsg(copyMessage{
-			Source: sourcePath,
-			Target: targetPath,
-		})
+	if globalNoProgress || globalJSON {
+		// Quiet mode only prints errors and the summary.
+		if !globalQuiet || globalJSON {
+			sourcePath := filepath.ToSlash(filepath.Join(sourceAlias, sourceURL.Path))
+			targetPath := filepath.ToSlash(filepath.Join(targetAlias, targetURL.Path))
+			printMsg(copyMessage{
+				Source: sourcePath,
+				Target: targetPath,
+			})
+		}
 		// Proxy reader to accounting reader only during quiet mode.
-		if globalQuiet || globalJSON {
+		if globalNoProgress || globalJSON {
 			progress = accountingReader
 		}
 	} else {
-		// Set up progress reader.
-		progress = progressReader.ProgressBar
+		// Set up progress reader of the file.
+		file := progressReader.startFile(sourceURL.String(), length)
+		defer progressReader.finishFile(file)
+		progress = file
 	}
-	// If source size is <= 5GB and operation is across same server type try to use Copy.
-	if length <= fiveGB && (sourceURL.Type == targetURL.Type) {
+
+	// Encryption options are looked up by aliased URL.
+	srcSSE := getEncryptOpts(encKeys, filepath.ToSlash(filepath.Join(sourceAlias, sourceURL.Path)))
+	tgtSSE := getEncryptOpts(encKeys, filepath.ToSlash(filepath.Join(targetAlias, targetURL.Path)))
+	uploadOpts.SSE = tgtSSE
+	uploadOpts.ResumeID = uploadResumeID(sourceAlias, cpURLs.SourceContent)
+	// Links listed with preserved links are copied as links.
+	if cpURLs.SourceContent.Symlink != "" {
+		if err := copySymlink(ctx, cpURLs.SourceContent.Symlink, targetAlias, targetURL, progress, uploadOpts); err != nil {
+			cpURLs.Error = err.Trace(sourceURL.String())
+			return cpURLs
+		}
+		cpURLs.Error = nil
+		return cpURLs
+	}
+	// Archived objects can only be copied once restored.
+	if sourceURL.Type == ObjectStorage && isArchived(cpURLs.SourceContent.StorageClass) {
+		if err := waitRestoreFromAlias(sourceAlias, sourceURL.String(), srcSSE, waitRestore); err != nil {
+			cpURLs.Error = err.Trace(sourceURL.String())
+			return cpURLs
+		}
+	}
+	// Attributes of files are stored as metadata of the objects.
+	if uploadOpts.Preserve && targetURL.Type == ObjectStorage {
+		attrs, err := sourceFileAttrs(sourceAlias, sourceURL)
+		if err != nil {
+			cpURLs.Error = err.Trace(sourceURL.String())
+			return cpURLs
+		}
+		uploadOpts.Metadata = mergeMetadata(attrs, uploadOpts.Metadata)
+	}
+	copyConds.SrcSSE = srcSSE
+	copyConds.TgtSSE = tgtSSE
+	copyConds.Metadata = uploadOpts.Metadata
+	isServerSideCopy := sourceURL.Type == targetURL.Type &&
+		(sourceURL.Type == FileSystem || (sourceAlias == targetAlias && sourceURL.Host == targetURL.Host))
+	// Conditions are evaluated by the server, streamed copies can't honor them.
+	if copyConds.hasConditions() && !isServerSideCopy {
+		cpURLs.Error = errCopyConditionsUnsupported(sourceURL.String()).Trace(sourceURL.String())
+		return cpURLs
+	}
+	// Operations across the same server type use Copy, object storage
+	// copies objects of any size without transferring data.
+	if sourceURL.Type == targetURL.Type {
 		// FS -> FS Copy includes alias in path.
-		if sourceURL.Type == fileSystem {
+		if sourceURL.Type == FileSystem {
 			sourcePath := filepath.ToSlash(filepath.Join(sourceAlias, sourceURL.Path))
-			err := copySourceStreamFromAlias(targetAlias, targetURL.String(), sourcePath, length, progress)
+			err := copySourceStreamFromAlias(ctx, targetAlias, targetURL.String(), sourcePath, length, progress, copyConds)
 			if err != nil {
 				cpURLs.Error = err.Trace(sourceURL.String())
 				return cpURLs
 			}
-		} else if sourceURL.Type == objectStorage {
-			// If source/target are object storage their aliases must be the same.
-			if sourceAlias == targetAlias {
+		} else if sourceURL.Type == ObjectStorage {
+			// If source/target are object storage their aliases must be the same,
+			// SFTP URLs have no alias but their hosts must match.
+			if sourceAlias == targetAlias && sourceURL.Host == targetURL.Host {
 				// Do not include alias inside path for ObjStore -> ObjStore.
-				err := copySourceStreamFromAlias(targetAlias, targetURL.String(), sourceURL.Path, length, progress)
+				err := copySourceStreamFromAlias(ctx, targetAlias, targetURL.String(), sourceURL.Path, length, progress, copyConds)
 				if err != nil {
 					cpURLs.Error = err.Trace(sourceURL.String())
 					return cpURLs
 				}
 			} else {
-				reader, err := getSourceStreamFromAlias(sourceAlias, sourceURL.String())
+				// Metadata of the source is retained.
+				metadata, err := getSourceMetadataFromAlias(sourceAlias, sourceURL.String(), srcSSE)
 				if err != nil {
 					cpURLs.Error = err.Trace(sourceURL.String())
 					return cpURLs
 				}
-				_, err = putTargetStreamFromAlias(targetAlias, targetURL.String(), reader, length, progress)
+				uploadOpts.Metadata = mergeMetadata(metadata, uploadOpts.Metadata)
+				// Data is streamed from one endpoint to the other,
+				// large objects are read in ranges of the part size
+				// while parts are uploaded.
+				partSize, err := optimalPartSize(length, uploadOpts.PartSize)
+				if err != nil {
+					cpURLs.Error = err.Trace(sourceURL.String())
+					return cpURLs
+				}
+				reader, err := getSourceRangesFromAlias(ctx, sourceAlias, sourceURL.String(), length, partSize, uploadOpts.ConcurrentParts, GetOpts{SSE: srcSSE, Checksum: uploadOpts.Checksum})
+				if err != nil {
+					cpURLs.Error = err.Trace(sourceURL.String())
+					return cpURLs
+				}
+				if closer, ok := reader.(io.Closer); ok {
+					defer closer.Close()
+				}
+				_, err = putTargetStreamFromAlias(ctx, targetAlias, targetURL.String(), reader, length, progress, uploadOpts)
 				if err != nil {
 					cpURLs.Error = err.Trace(targetURL.String())
 					return cpURLs
@@ -196,26 +415,87 @@ func doCopy(cpURLs URLs, progressReader *progressBar, accountingReader *accounte
 			}
 		}
 	} else {
-		// Standard GET/PUT for size > 5GB.
-		reader, err := getSourceStreamFromAlias(sourceAlias, sourceURL.String())
+		// Objects uploaded from links are restored as links.
+		isSymlink, err := restoreSymlink(sourceAlias, cpURLs.SourceContent, targetURL, srcSSE)
+		if err != nil {
+			cpURLs.Error = err.Trace(sourceURL.String())
+			return cpURLs
+		}
+		if isSymlink {
+			cpURLs.Error = nil
+			return cpURLs
+		}
+		// Standard GET/PUT across server types.
+		// Downloads with ‘--sparse’ read the size of the file uploaded
+		// from the metadata.
+		reader, err := getSourceStreamFromAlias(ctx, sourceAlias, sourceURL.String(), GetOpts{
+			SSE:      srcSSE,
+			Checksum: uploadOpts.Checksum,
+			Metadata: uploadOpts.Sparse && sourceURL.Type == ObjectStorage,
+		})
 		if err != nil {
 			cpURLs.Error = err.Trace(sourceURL.String())
 			return cpURLs
 		}
-		_, err = putTargetStreamFromAlias(targetAlias, targetURL.String(), reader, length, progress)
+		// Trailing zero blocks of files are not uploaded.
+		var trimmed int64
+		if uploadOpts.Sparse && sourceURL.Type == FileSystem {
+			if closer, ok := reader.(io.Closer); ok {
+				defer closer.Close()
+			}
+			reader, length, trimmed, err = trimSparseTail(reader, length)
+			if err != nil {
+				cpURLs.Error = err.Trace(sourceURL.String())
+				return cpURLs
+			}
+			if trimmed > 0 {
+				uploadOpts.Metadata = mergeMetadata(uploadOpts.Metadata, map[string]string{
+					sparseSizeMetadataKey: strconv.FormatInt(length+trimmed, 10),
+				})
+			}
+		}
+		_, err = putTargetStreamFromAlias(ctx, targetAlias, targetURL.String(), reader, length, progress, uploadOpts)
 		if err != nil {
 			cpURLs.Error = err.Trace(targetURL.String())
 			return cpURLs
 		}
+		// Notify the progress bar of the trimmed blocks.
+		if trimmed > 0 && progress != nil {
+			if _, e := io.CopyN(ioutil.Discard, progress, trimmed); e != nil {
+				cpURLs.Error = probe.NewError(e).Trace(sourceURL.String())
+				return cpURLs
+			}
+		}
+		// Objects uploaded with ‘--sparse’ are restored to their full
+		// size by downloads with ‘--sparse’.
+		if err = restoreSparseTail(reader, length, targetURL); err != nil {
+			cpURLs.Error = err.Trace(targetURL.String())
+			return cpURLs
+		}
+	}
+	if uploadOpts.Preserve {
+		if err := restoreTargetAttrs(sourceAlias, sourceURL, targetURL, srcSSE); err != nil {
+			cpURLs.Error = err.Trace(targetURL.String())
+			return cpURLs
+		}
 	}
 	cpURLs.Error = nil // just for safety
 	return cpURLs
 }
 
+// copyStatus - result of a copy, numbered in the order of the session
+// data. Objects copied before resuming are not copied again.
+type copyStatus struct {
+	URLs
+	index    int
+	isCopied bool
+}
+
 // doCopyFake - Perform a fake copy to update the progress bar appropriately.
 func doCopyFake(cpURLs URLs, progressReader *progressBar) URLs {
-	if !globalQuiet && !globalJSON {
+	if !globalNoProgress && !globalJSON {
 		progressReader.ProgressBar.Add64(cpURLs.SourceContent.Size)
+		progressReader.finishFile(nil)
 	}
 	return cpURLs
 }
@@ -232,16 +512,24 @@ func doPrepareCopyURLs(session *sessionV8, trapCh <-chan bool) {
 
 	// Access recursive flag inside the session header.
 	isRecursive := session.Header.CommandBoolFlags["recursive"]
+	filter, err := newContentFilter(filterOptsFromSession(session))
+	if err != nil {
+		session.Delete()
+		fatalIf(err.Trace(), "Unable to parse filters.")
+	}
 
 	// Create a session data file to store the processed URLs.
 	dataFP := session.NewDataWriter()
 
 	var scanBar scanBarFunc
-	if !globalQuiet && !globalJSON { // set up progress bar
+	if !globalNoProgress && !globalJSON { // set up progress bar
 		scanBar = scanBarFactory()
 	}
 
-	URLsCh := prepareCopyURLs(sourceURLs, targetURL, isRecursive)
+	URLsCh := prepareCopyURLs(sourceURLs, targetURL, isRecursive, filter)
+	if session.Header.CommandBoolFlags["newer"] {
+		URLsCh = skipUpToDateURLs(URLsCh)
+	}
 	done := false
 	for !done {
 		select {
@@ -252,7 +540,7 @@ func doPrepareCopyURLs(session *sessionV8, trapCh <-chan bool) {
 			}
 			if cpURLs.Error != nil {
 				// Print in new line and adjust to top so that we don't print over the ongoing scan bar
-				if !globalQuiet && !globalJSON {
+				if !globalNoProgress && !globalJSON {
 					console.Eraseline()
 				}
 				if strings.Contains(cpURLs.Error.ToGoError().Error(), " is a folder.") {
@@ -269,7 +557,7 @@ func doPrepareCopyURLs(session *sessionV8, trapCh <-chan bool) {
 				fatalIf(probe.NewError(e), "Unable to prepare URL for copying. Error in JSON marshaling.")
 			}
 			fmt.Fprintln(dataFP, string(jsonData))
-			if !globalQuiet && !globalJSON {
+			if !globalNoProgress && !globalJSON {
 				scanBar(cpURLs.SourceContent.URL.String())
 			}
 
@@ -277,7 +565,7 @@ func doPrepareCopyURLs(session *sessionV8, trapCh <-chan bool) {
 			totalObjects++
 		case <-trapCh:
 			// Print in new line and adjust to top so that we don't print over the ongoing scan bar
-			if !globalQuiet && !globalJSON {
+			if !globalNoProgress && !globalJSON {
 				console.Eraseline()
 			}
 			session.Delete() // If we are interrupted during the URL scanning, we drop the session.
@@ -286,16 +574,54 @@ func doPrepareCopyURLs(session *sessionV8, trapCh <-chan bool) {
 	}
 	session.Header.TotalBytes = totalBytes
 	session.Header.TotalObjects = totalObjects
+	session.Header.Prepared = true
 	session.Save()
 }
 
 func doCopySession(session *sessionV8) {
 	trapCh := signalTrap(os.Interrupt, syscall.SIGTERM)
 
+	// Links are listed by the mode of the session.
+	var err *probe.Error
+	if fsSymlinks, err = symlinkModeFromSession(session); err != nil {
+		session.Delete()
+		fatalIf(err.Trace(), "Unable to parse symlink flags.")
+	}
+
 	if !session.HasData() {
 		doPrepareCopyURLs(session, trapCh)
 	}
 
+	// Encryption keys are part of the session, so that resumed
+	// sessions continue with the same keys.
+	encKeys, err := parseEncryptKeys(session.Header.CommandStringFlags["encrypt-key"], session.Header.CommandStringFlags["encrypt"])
+	if err != nil {
+		session.Delete()
+		fatalIf(err.Trace(), "Unable to parse encryption keys.")
+	}
+	uploadOpts, err := parsePutOpts(session.Header.CommandStringFlags["part-size"], session.Header.CommandIntFlags["concurrent-parts"])
+	if err != nil {
+		session.Delete()
+		fatalIf(err.Trace(), "Unable to parse upload options.")
+	}
+	copyConds, err := parseCopyOpts(session.Header.CommandStringFlags["if-match"], session.Header.CommandStringFlags["if-none-match"], session.Header.CommandStringFlags["if-unmodified-since"])
+	if err != nil {
+		session.Delete()
+		fatalIf(err.Trace(), "Unable to parse copy conditions.")
+	}
+	if uploadOpts.Metadata, err = parseAttr(session.Header.CommandStringFlags["attr"]); err != nil {
+		session.Delete()
+		fatalIf(err.Trace(), "Unable to parse attributes.")
+	}
+	uploadOpts.Checksum = session.Header.CommandBoolFlags["checksum"]
+	uploadOpts.Preserve = session.Header.CommandBoolFlags["preserve"]
+	uploadOpts.Sparse = session.Header.CommandBoolFlags["sparse"]
+	parallel, err := parseParallel(session.Header.CommandIntFlags["parallel"])
+	if err != nil {
+		session.Delete()
+		fatalIf(err.Trace(), "Unable to parse number of parallel transfers.")
+	}
+
 	// Enable accounting reader by default.
 	accntReader := newAccounter(session.Header.TotalBytes)
 
@@ -304,15 +630,33 @@ func doCopySession(session *sessionV8) {
 	// isCopied returns true if an object has been already copied
 	// or not. This is useful when we resume from a session.
 	isCopied := isLastFactory(session.Header.LastCopied)
+	// Objects copied after the last copied one are skipped as well.
+	copied := make(map[int]bool)
+	for _, index := range session.Header.Copied {
+		copied[index] = true
+	}
 
 	// Enable progress bar reader only during default mode.
 	var progressReader *progressBar
-	if !globalQuiet && !globalJSON { // set up progress bar
+	if !globalNoProgress && !globalJSON { // set up progress bar
 		progressReader = newProgressBar(session.Header.TotalBytes)
+		progressReader.setTotalObjects(int64(session.Header.TotalObjects))
 	}
 
-	// Wait on status of doCopy() operation.
-	var statusCh = make(chan URLs)
+	// Objects are staged and committed once all were copied.
+	stagingPrefix := session.Header.CommandStringFlags["staging-prefix"]
+	isFailed := false
+
+	// Objects and bytes are counted for the summary.
+	report := newTransferReport()
+
+	// Versions written to versioned buckets are recorded, so that
+	// overwritten objects can be restored.
+	journal := newUndoJournal("cp", session.Header.CommandArgs)
+
+	// Wait on status of doCopy() operation, copies are numbered in
+	// the order of the session data.
+	var statusCh = make(chan copyStatus)
 
 	// Add a wait group.
 	var wg = new(sync.WaitGroup)
@@ -321,38 +665,65 @@ func doCopySession(session *sessionV8) {
 	// Go routine to monitor signal traps if any.
 	go func() {
 		defer wg.Done()
+		// Parallel copies finish out of order, the session records
+		// the last copied object only once all objects before it are
+		// done, so that resuming does not skip unfinished copies.
+		done := make(map[int]string)
+		next := 0
 		for {
 			select {
 			case <-trapCh:
 				// Receive interrupt notification.
-				if !globalQuiet && !globalJSON {
+				if !globalNoProgress && !globalJSON {
 					console.Eraseline()
 				}
 				session.CloseAndDie()
-			case cpURLs, ok := <-statusCh:
+			case status, ok := <-statusCh:
 				// Status channel is closed, we should return.
 				if !ok {
 					return
 				}
+				cpURLs := status.URLs
 				if cpURLs.Error == nil {
-					session.Header.LastCopied = cpURLs.SourceContent.URL.String()
+					if status.isCopied {
+						report.Transferred(cpURLs.SourceContent.Size)
+					}
+					done[status.index] = cpURLs.SourceContent.URL.String()
+					for lastCopied, ok := done[next]; ok; lastCopied, ok = done[next] {
+						session.Header.LastCopied = lastCopied
+						delete(done, next)
+						next++
+					}
+					session.Header.Copied = session.Header.Copied[:0]
+					for index := range done {
+						session.Header.Copied = append(session.Header.Copied, index)
+					}
+					sort.Ints(session.Header.Copied)
 					session.Save()
 				} else {
 					// Print in new line and adjust to top so that we
 					// don't print over the ongoing progress bar.
-					if !globalQuiet && !globalJSON {
+					if !globalNoProgress && !globalJSON {
 						console.Eraseline()
 					}
+					// Copies cancelled by an interrupt are resumed.
+					if globalContext.Err() != nil {
+						session.CloseAndDie()
+					}
 					errorIf(cpURLs.Error.Trace(cpURLs.SourceContent.URL.String()),
 						fmt.Sprintf("Failed to copy ‘%s’.", cpURLs.SourceContent.URL.String()))
+					isFailed = true
+					report.Failed(cpURLs)
 					// For all non critical errors we can continue for the
 					// remaining files.
 					switch cpURLs.Error.ToGoError().(type) {
 					// Handle this specifically for filesystem related errors.
 					case BrokenSymlink, TooManyLevelsSymlink, PathNotFound, PathInsufficientPermission:
+						done[status.index] = cpURLs.SourceContent.URL.String()
 						continue
 					// Handle these specifically for object storage related errors.
-					case BucketNameEmpty, ObjectMissing, ObjectAlreadyExists, BucketDoesNotExist, BucketInvalid, ObjectOnGlacier:
+					case BucketNameEmpty, ObjectMissing, ObjectAlreadyExists, BucketDoesNotExist, BucketInvalid, ObjectOnGlacier, ObjectEncrypted, ObjectDecryptionFailed, ObjectPreconditionFailed:
+						done[status.index] = cpURLs.SourceContent.URL.String()
 						continue
 					}
 					// For critical errors we should exit. Session
@@ -364,32 +735,62 @@ func doCopySession(session *sessionV8) {
 		}
 	}()
 
+	// Copy workers, up to ‘--parallel’ objects are copied at once.
+	copyCh := make(chan copyStatus)
+	var wgCopy = new(sync.WaitGroup)
+	for i := 0; i < parallel; i++ {
+		wgCopy.Add(1)
+		go func() {
+			defer wgCopy.Done()
+			for status := range copyCh {
+				if stagingPrefix != "" {
+					status.URLs = stageURLs(status.URLs, stagingPrefix)
+				}
+				status.URLs = doCopy(globalContext, status.URLs, progressReader, accntReader, encKeys, uploadOpts, copyConds, session.Header.CommandBoolFlags["wait-restore"])
+				status.isCopied = true
+				if status.URLs.Error == nil && stagingPrefix == "" {
+					targetAlias := status.URLs.TargetAlias
+					targetURL := status.URLs.TargetContent.URL
+					if err := journal.recordPut(targetAlias, targetURL.String()); err != nil {
+						errorIf(err.Trace(targetURL.String()), "Unable to record copy to ‘"+targetURL.String()+"’ for undo.")
+					}
+				}
+				statusCh <- status
+			}
+		}()
+	}
+
 	// Loop through all urls.
-	for urlScanner.Scan() {
+	for index := 0; urlScanner.Scan(); index++ {
 		var cpURLs URLs
 		// Unmarshal copyURLs from each line.
 		json.Unmarshal([]byte(urlScanner.Text()), &cpURLs)
 
 		// Verify if previously copied, notify progress bar.
-		if isCopied(cpURLs.SourceContent.URL.String()) {
-			statusCh <- doCopyFake(cpURLs, progressReader)
+		if isCopied(cpURLs.SourceContent.URL.String()) || copied[index] {
+			report.Skipped()
+			statusCh <- copyStatus{index: index, URLs: doCopyFake(cpURLs, progressReader)}
 		} else {
-			statusCh <- doCopy(cpURLs, progressReader, accntReader)
+			copyCh <- copyStatus{index: index, URLs: cpURLs}
 		}
 	}
 
+	// Wait for the copies to finish.
+	close(copyCh)
+	wgCopy.Wait()
+
 	// Close the goroutine.
 	close(statusCh)
 
 	// Wait for the goroutines to finish.
 	wg.Wait()
 
-	if !globalQuiet && !globalJSON {
+	if !globalNoProgress && !globalJSON {
 		if progressReader.ProgressBar.Get() > 0 {
-			progressReader.ProgressBar.Finish()
+			progressReader.Finish()
 		}
 	} else {
-		if !globalJSON && globalQuiet {
+		if !globalJSON && globalNoProgress {
 			accntStat := accntReader.Stat()
 			cpStatMessage := copyStatMessage{
 				Total:       accntStat.Total,
@@ -399,6 +800,43 @@ func doCopySession(session *sessionV8) {
 			console.Println(console.Colorize("Copy", cpStatMessage.String()))
 		}
 	}
+
+	if stagingPrefix != "" {
+		if isFailed {
+			errorIf(errStagingNotCommitted(stagingPrefix).Trace(stagingPrefix), "Unable to commit staged objects.")
+		} else {
+			commitStagedObjects(stagedCopies(session, stagingPrefix), encKeys)
+		}
+	}
+
+	errorIf(journal.Close().Trace(), "Unable to save copies for undo.")
+	printMsg(report.Summary())
+	manifestPath := session.Header.CommandStringFlags["failure-manifest"]
+	if err := report.WriteFailures(manifestPath); err != nil {
+		errorIf(err.Trace(manifestPath), "Unable to write failure manifest.")
+	}
+}
+
+// stagedCopies - objects staged by the copies of a session.
+func stagedCopies(session *sessionV8, prefix string) []stagedObject {
+	var objects []stagedObject
+	urlScanner := bufio.NewScanner(session.NewDataReader())
+	for urlScanner.Scan() {
+		var cpURLs URLs
+		json.Unmarshal([]byte(urlScanner.Text()), &cpURLs)
+		stagedURLs := stageURLs(cpURLs, prefix)
+		if stagedURLs.Error != nil {
+			errorIf(stagedURLs.Error.Trace(), "Unable to commit staged objects.")
+			continue
+		}
+		objects = append(objects, stagedObject{
+			Alias:     cpURLs.TargetAlias,
+			StagedURL: stagedURLs.TargetContent.URL.String(),
+			FinalURL:  cpURLs.TargetContent.URL.String(),
+			Size:      cpURLs.SourceContent.Size,
+		})
+	}
+	return objects
 }
 
 // mainCopy is the entry point for cp command.
@@ -411,10 +849,31 @@ func mainCopy(ctx *cli.Context) {
 
 	// Additional command speific theme customization.
 	console.SetColor("Copy", color.New(color.FgGreen, color.Bold))
+	console.SetColor("Summary", color.New(color.FgGreen))
 
 	session := newSessionV8()
 	session.Header.CommandType = "cp"
 	session.Header.CommandBoolFlags["recursive"] = ctx.Bool("recursive")
+	session.Header.CommandBoolFlags["newer"] = ctx.Bool("newer")
+	session.Header.CommandBoolFlags["wait-restore"] = ctx.Bool("wait-restore")
+	session.Header.CommandBoolFlags["checksum"] = ctx.Bool("checksum")
+	session.Header.CommandBoolFlags["follow-symlinks"] = ctx.Bool("follow-symlinks")
+	session.Header.CommandBoolFlags["skip-symlinks"] = ctx.Bool("skip-symlinks")
+	session.Header.CommandBoolFlags["preserve-links"] = ctx.Bool("preserve-links")
+	session.Header.CommandBoolFlags["preserve"] = ctx.Bool("preserve")
+	session.Header.CommandBoolFlags["sparse"] = ctx.Bool("sparse")
+	session.Header.CommandStringFlags["encrypt-key"] = ctx.String("encrypt-key")
+	session.Header.CommandStringFlags["encrypt"] = ctx.String("encrypt")
+	session.Header.CommandStringFlags["part-size"] = ctx.String("part-size")
+	session.Header.CommandIntFlags["concurrent-parts"] = ctx.Int("concurrent-parts")
+	session.Header.CommandIntFlags["parallel"] = ctx.Int("parallel")
+	session.Header.CommandStringFlags["if-match"] = ctx.String("if-match")
+	session.Header.CommandStringFlags["if-none-match"] = ctx.String("if-none-match")
+	session.Header.CommandStringFlags["if-unmodified-since"] = ctx.String("if-unmodified-since")
+	session.Header.CommandStringFlags["attr"] = ctx.String("attr")
+	session.Header.CommandStringFlags["staging-prefix"] = ctx.String("staging-prefix")
+	session.Header.CommandStringFlags["failure-manifest"] = ctx.String("failure-manifest")
+	setSessionFilterOpts(session, filterOptsFromContext(ctx))
 
 	var e error
 	if session.Header.RootPath, e = os.Getwd(); e != nil {
@@ -37,6 +37,30 @@ func checkCopySyntax(ctx *cli.Context) {
 	tgtURL := URLs[len(URLs)-1]
 	isRecursive := ctx.Bool("recursive")
 
+	// Verify encryption keys before doing anything.
+	if _, err := parseEncryptKeys(ctx.String("encrypt-key"), ctx.String("encrypt")); err != nil {
+		fatalIf(err.Trace(), "Unable to parse encryption keys.")
+	}
+	if _, err := parsePutOpts(ctx.String("part-size"), ctx.Int("concurrent-parts")); err != nil {
+		fatalIf(err.Trace(), "Unable to parse upload options.")
+	}
+	if _, err := parseParallel(ctx.Int("parallel")); err != nil {
+		fatalIf(err.Trace(), "Unable to parse number of parallel transfers.")
+	}
+	if _, err := parseCopyOpts(ctx.String("if-match"), ctx.String("if-none-match"), ctx.String("if-unmodified-since")); err != nil {
+		fatalIf(err.Trace(), "Unable to parse copy conditions.")
+	}
+	if _, err := parseAttr(ctx.String("attr")); err != nil {
+		fatalIf(err.Trace(), "Unable to parse attributes.")
+	}
+	if _, err := newContentFilter(filterOptsFromContext(ctx)); err != nil {
+		fatalIf(err.Trace(), "Unable to parse filters.")
+	}
+	checkStagingSyntax(tgtURL, ctx.String("staging-prefix"))
+	if _, err := parseSymlinkMode(ctx.Bool("follow-symlinks"), ctx.Bool("skip-symlinks"), ctx.Bool("preserve-links")); err != nil {
+		fatalIf(err.Trace(), "Unable to parse symlink flags.")
+	}
+
 	/****** Generic Invalid Rules *******/
 	// Verify if source(s) exists.
 	for _, srcURL := range srcURLs {
@@ -48,7 +48,7 @@ const (
 	copyURLsTypeD
 )
 
-// guessCopyURLType guesses the type of clientURL. This approach all allows prepareURL
+// guessCopyURLType guesses the type of ClientURL. This approach all allows prepareURL
 // functions to accurately report failure causes.
 func guessCopyURLType(sourceURLs []string, targetURL string, isRecursive bool) (copyURLsType, *probe.Error) {
 	if len(sourceURLs) == 1 { // 1 Source, 1 Target
@@ -83,9 +83,9 @@ func guessCopyURLType(sourceURLs []string, targetURL string, isRecursive bool) (
 // SINGLE SOURCE - Type A: copy(f, f) -> copy(f, f)
 // prepareCopyURLsTypeA - prepares target and source clientURLs for copying.
 func prepareCopyURLsTypeA(sourceURL string, targetURL string) URLs {
-	// Extract alias before fiddling with the clientURL.
+	// Extract alias before fiddling with the ClientURL.
 	sourceAlias, _, _ := mustExpandAlias(sourceURL)
-	// Find alias and expanded clientURL.
+	// Find alias and expanded ClientURL.
 	targetAlias, targetURL, _ := mustExpandAlias(targetURL)
 
 	_, sourceContent, err := url2Stat(sourceURL)
@@ -107,21 +107,21 @@ func prepareCopyURLsTypeA(sourceURL string, targetURL string) URLs {
 }
 
 // prepareCopyContentTypeA - makes CopyURLs content for copying.
-func makeCopyContentTypeA(sourceAlias string, sourceContent *clientContent, targetAlias string, targetURL string) URLs {
+func makeCopyContentTypeA(sourceAlias string, sourceContent *ClientContent, targetAlias string, targetURL string) URLs {
 	return URLs{
 		SourceAlias:   sourceAlias,
 		SourceContent: sourceContent,
 		TargetAlias:   targetAlias,
-		TargetContent: &clientContent{URL: *newClientURL(targetURL)},
+		TargetContent: &ClientContent{URL: *newClientURL(targetURL)},
 	}
 }
 
 // SINGLE SOURCE - Type B: copy(f, d) -> copy(f, d/f) -> A
 // prepareCopyURLsTypeB - prepares target and source clientURLs for copying.
 func prepareCopyURLsTypeB(sourceURL string, targetURL string) URLs {
-	// Extract alias before fiddling with the clientURL.
+	// Extract alias before fiddling with the ClientURL.
 	sourceAlias, _, _ := mustExpandAlias(sourceURL)
-	// Find alias and expanded clientURL.
+	// Find alias and expanded ClientURL.
 	targetAlias, targetURL, _ := mustExpandAlias(targetURL)
 
 	_, sourceContent, err := url2Stat(sourceURL)
@@ -143,7 +143,7 @@ func prepareCopyURLsTypeB(sourceURL string, targetURL string) URLs {
 }
 
 // makeCopyContentTypeB - CopyURLs content for copying.
-func makeCopyContentTypeB(sourceAlias string, sourceContent *clientContent, targetAlias string, targetURL string) URLs {
+func makeCopyContentTypeB(sourceAlias string, sourceContent *ClientContent, targetAlias string, targetURL string) URLs {
 	// All OK.. We can proceed. Type B: source is a file, target is a folder and exists.
 	targetURLParse := newClientURL(targetURL)
 	targetURLParse.Path = filepath.ToSlash(filepath.Join(targetURLParse.Path, filepath.Base(sourceContent.URL.Path)))
@@ -152,10 +152,10 @@ func makeCopyContentTypeB(sourceAlias string, sourceContent *clientContent, targ
 
 // SINGLE SOURCE - Type C: copy(d1..., d2) -> []copy(d1/f, d1/d2/f) -> []A
 // prepareCopyRecursiveURLTypeC - prepares target and source clientURLs for copying.
-func prepareCopyURLsTypeC(sourceURL, targetURL string, isRecursive bool) <-chan URLs {
-	// Extract alias before fiddling with the clientURL.
+func prepareCopyURLsTypeC(sourceURL, targetURL string, isRecursive bool, filter *contentFilter) <-chan URLs {
+	// Extract alias before fiddling with the ClientURL.
 	sourceAlias, _, _ := mustExpandAlias(sourceURL)
-	// Find alias and expanded clientURL.
+	// Find alias and expanded ClientURL.
 	targetAlias, targetURL, _ := mustExpandAlias(targetURL)
 
 	copyURLsCh := make(chan URLs)
@@ -168,7 +168,7 @@ func prepareCopyURLsTypeC(sourceURL, targetURL string, isRecursive bool) <-chan
 			return
 		}
 
-		for sourceContent := range sourceClient.List(isRecursive, false) {
+		for sourceContent := range filter.Filter(sourceClient.List(globalContext, isRecursive, false), sourceClient.GetURL().Path) {
 			if sourceContent.Err != nil {
 				// Listing failed.
 				copyURLsCh <- URLs{Error: sourceContent.Err.Trace(sourceClient.GetURL().String())}
@@ -188,7 +188,7 @@ func prepareCopyURLsTypeC(sourceURL, targetURL string, isRecursive bool) <-chan
 }
 
 // makeCopyContentTypeC - CopyURLs content for copying.
-func makeCopyContentTypeC(sourceAlias string, sourceURL clientURL, sourceContent *clientContent, targetAlias string, targetURL string) URLs {
+func makeCopyContentTypeC(sourceAlias string, sourceURL ClientURL, sourceContent *ClientContent, targetAlias string, targetURL string) URLs {
 	newSourceURL := sourceContent.URL
 	pathSeparatorIndex := strings.LastIndex(sourceURL.Path, string(sourceURL.Separator))
 	newSourceSuffix := filepath.ToSlash(newSourceURL.Path)
@@ -202,12 +202,12 @@ func makeCopyContentTypeC(sourceAlias string, sourceURL clientURL, sourceContent
 
 // MULTI-SOURCE - Type D: copy([](f|d...), d) -> []B
 // prepareCopyURLsTypeE - prepares target and source clientURLs for copying.
-func prepareCopyURLsTypeD(sourceURLs []string, targetURL string, isRecursive bool) <-chan URLs {
+func prepareCopyURLsTypeD(sourceURLs []string, targetURL string, isRecursive bool, filter *contentFilter) <-chan URLs {
 	copyURLsCh := make(chan URLs)
 	go func(sourceURLs []string, targetURL string, copyURLsCh chan URLs) {
 		defer close(copyURLsCh)
 		for _, sourceURL := range sourceURLs {
-			for cpURLs := range prepareCopyURLsTypeC(sourceURL, targetURL, isRecursive) {
+			for cpURLs := range prepareCopyURLsTypeC(sourceURL, targetURL, isRecursive, filter) {
 				copyURLsCh <- cpURLs
 			}
 		}
@@ -216,7 +216,9 @@ func prepareCopyURLsTypeD(sourceURLs []string, targetURL string, isRecursive boo
 }
 
 // prepareCopyURLs - prepares target and source clientURLs for copying.
-func prepareCopyURLs(sourceURLs []string, targetURL string, isRecursive bool) <-chan URLs {
+// Contents of listed folders are filtered, explicitly given files are
+// always copied.
+func prepareCopyURLs(sourceURLs []string, targetURL string, isRecursive bool, filter *contentFilter) <-chan URLs {
 	copyURLsCh := make(chan URLs)
 	go func(sourceURLs []string, targetURL string, copyURLsCh chan URLs) {
 		defer close(copyURLsCh)
@@ -229,11 +231,11 @@ func prepareCopyURLs(sourceURLs []string, targetURL string, isRecursive bool) <-
 		case copyURLsTypeB:
 			copyURLsCh <- prepareCopyURLsTypeB(sourceURLs[0], targetURL)
 		case copyURLsTypeC:
-			for cURLs := range prepareCopyURLsTypeC(sourceURLs[0], targetURL, isRecursive) {
+			for cURLs := range prepareCopyURLsTypeC(sourceURLs[0], targetURL, isRecursive, filter) {
 				copyURLsCh <- cURLs
 			}
 		case copyURLsTypeD:
-			for cURLs := range prepareCopyURLsTypeD(sourceURLs, targetURL, isRecursive) {
+			for cURLs := range prepareCopyURLsTypeD(sourceURLs, targetURL, isRecursive, filter) {
 				copyURLsCh <- cURLs
 			}
 		default:
@@ -243,3 +245,48 @@ func prepareCopyURLs(sourceURLs []string, targetURL string, isRecursive bool) <-
 
 	return copyURLsCh
 }
+
+// isTargetOutdated - reports whether the target of a copy is missing or
+// older than its source. Targets of the same modification time but of
+// another size are outdated as well, newer targets are kept.
+func isTargetOutdated(cpURLs URLs) (bool, *probe.Error) {
+	targetURL := cpURLs.TargetContent.URL.String()
+	targetClnt, err := newClientFromAlias(cpURLs.TargetAlias, targetURL)
+	if err != nil {
+		return false, err.Trace(targetURL)
+	}
+	targetContent, err := targetClnt.Stat()
+	if err != nil {
+		switch err.ToGoError().(type) {
+		case PathNotFound, ObjectMissing:
+			return true, nil
+		}
+		return false, err.Trace(targetURL)
+	}
+	sourceTime := cpURLs.SourceContent.Time
+	if sourceTime.Equal(targetContent.Time) {
+		return cpURLs.SourceContent.Size != targetContent.Size, nil
+	}
+	return sourceTime.After(targetContent.Time), nil
+}
+
+// skipUpToDateURLs - passes on copies whose target is outdated, every
+// target is stated. Errors are passed along.
+func skipUpToDateURLs(URLsCh <-chan URLs) <-chan URLs {
+	outdatedURLsCh := make(chan URLs)
+	go func() {
+		defer close(outdatedURLsCh)
+		for cpURLs := range URLsCh {
+			if cpURLs.Error == nil {
+				isOutdated, err := isTargetOutdated(cpURLs)
+				if err != nil {
+					cpURLs = URLs{Error: err.Trace(cpURLs.SourceContent.URL.String())}
+				} else if !isOutdated {
+					continue
+				}
+			}
+			outdatedURLsCh <- cpURLs
+		}
+	}()
+	return outdatedURLsCh
+}
@@ -0,0 +1,91 @@
+/*
+ * Minio Client (C) 2016 Minio, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/minio/minio/pkg/probe"
+	. "gopkg.in/check.v1"
+)
+
+// Test skipping copies of targets which are up to date.
+func (s *TestSuite) TestSkipUpToDateURLs(c *C) {
+	defer func(load func() (*configV8, *probe.Error)) { loadMcConfig = load }(loadMcConfig)
+	loadMcConfig = func() (*configV8, *probe.Error) { return newMcConfig(), nil }
+
+	root, e := ioutil.TempDir(os.TempDir(), "cp-newer-")
+	c.Assert(e, IsNil)
+	defer os.RemoveAll(root)
+
+	modTime := time.Now().Add(-time.Hour).Truncate(time.Second)
+	testCases := []struct {
+		name       string
+		sourceTime time.Time
+		sourceSize int64
+		// Target of the copy, missing if nil.
+		target   []byte
+		outdated bool
+	}{
+		{"missing", modTime, 4, nil, true},
+		{"newer-source", modTime.Add(time.Minute), 4, []byte("data"), true},
+		{"older-source", modTime.Add(-time.Minute), 4, []byte("data"), false},
+		{"same", modTime, 4, []byte("data"), false},
+		{"same-time-other-size", modTime, 5, []byte("data"), true},
+		{"older-source-other-size", modTime.Add(-time.Minute), 5, []byte("data"), false},
+	}
+
+	var cpURLsList []URLs
+	for _, testCase := range testCases {
+		target := filepath.Join(root, testCase.name)
+		if testCase.target != nil {
+			c.Assert(ioutil.WriteFile(target, testCase.target, 0600), IsNil)
+			c.Assert(os.Chtimes(target, modTime, modTime), IsNil)
+		}
+		cpURLs := URLs{
+			SourceContent: &ClientContent{URL: *newClientURL(filepath.Join(root, "source")), Time: testCase.sourceTime, Size: testCase.sourceSize},
+			TargetContent: &ClientContent{URL: *newClientURL(target)},
+		}
+		outdated, err := isTargetOutdated(cpURLs)
+		c.Assert(err, IsNil)
+		c.Assert(outdated, Equals, testCase.outdated, Commentf("%s", testCase.name))
+		cpURLsList = append(cpURLsList, cpURLs)
+	}
+	// Errors are passed along.
+	cpURLsList = append(cpURLsList, URLs{Error: errDummy()})
+
+	URLsCh := make(chan URLs)
+	go func() {
+		defer close(URLsCh)
+		for _, cpURLs := range cpURLsList {
+			URLsCh <- cpURLs
+		}
+	}()
+
+	var copied []string
+	for cpURLs := range skipUpToDateURLs(URLsCh) {
+		if cpURLs.Error != nil {
+			copied = append(copied, "error")
+			continue
+		}
+		copied = append(copied, filepath.Base(cpURLs.TargetContent.URL.Path))
+	}
+	c.Assert(copied, DeepEquals, []string{"missing", "newer-source", "same-time-other-size", "error"})
+}
@@ -34,6 +34,16 @@ var (
 			Name:  "help, h",
 			Usage: "Help of diff.",
 		},
+		cli.StringFlag{
+			Name:  "compare",
+			Value: compareSize,
+			Usage: "Compare files of the same name by ‘size’, or by ‘checksum’ to notice files of the same size which differ in contents.",
+		},
+		cli.BoolFlag{
+			Name:  "newer",
+			Usage: "Report files of the same size and contents which were modified later in first or in second.",
+		},
+		outputFlag,
 	}
 )
 
@@ -41,9 +51,9 @@ var (
 var diffCmd = cli.Command{
 	Name:        "diff",
 	Usage:       "Compute differences between two folders.",
-	Description: "Diff only lists missing objects or objects with size differences. It *DOES NOT* compare contents. i.e. Objects of same name and size, but differ in contents are not noticed.",
+	Description: "Diff only lists missing objects or objects with size differences. Contents are only compared with ‘--compare checksum’, otherwise objects of same name and size, but differ in contents are not noticed. Modification times are only compared with ‘--newer’. With ‘--json’ every difference is printed as a JSON object, nothing is printed if both folders are alike.",
 	Action:      mainDiff,
-	Flags:       append(diffFlags, globalFlags...),
+	Flags:       append(append(diffFlags, listingCacheFlags...), globalFlags...),
 	CustomHelpTemplate: `NAME:
    mc {{.Name}} - {{.Usage}}
 
@@ -62,6 +72,18 @@ EXAMPLES:
 
    2. Compare two different folders on a local filesystem.
       $ mc {{.Name}} ~/Photos /Media/Backup/Photos
+
+   3. Compare a local folder with a folder on Amazon S3 cloud storage, including the contents of files of the same size.
+      $ mc {{.Name}} --compare checksum ~/Photos s3/MyBucket/Photos
+
+   4. Compare two buckets on Amazon S3 cloud storage, reporting objects modified later in either of them as JSON.
+      $ mc {{.Name}} --newer --json s3/MyBucket s3/MyBackup
+
+   5. Compare two buckets on Amazon S3 cloud storage, saving the differences as CSV.
+      $ mc {{.Name}} --output csv s3/MyBucket s3/MyBackup > differences.csv
+
+   6. Compare two buckets on Amazon S3 cloud storage, reusing their listings for ten minutes.
+      $ mc {{.Name}} --cache-ttl 10m s3/MyBucket s3/MyBackup
 `,
 }
 
@@ -72,8 +94,8 @@ type diffMessage struct {
 	SecondURL     string       `json:"second"`
 	Diff          differType   `json:"diff"`
 	Error         *probe.Error `json:"error,omitempty"`
-	firstContent  *clientContent
-	secondContent *clientContent
+	firstContent  *ClientContent
+	secondContent *ClientContent
 }
 
 // String colorized diff message
@@ -92,6 +114,15 @@ func (d diffMessage) String() string {
 	case differInSize:
 		msg = console.Colorize("DiffMessage",
 			"‘"+d.FirstURL+"’"+" and "+"‘"+d.SecondURL+"’") + console.Colorize("DiffSize", " - differ in size.")
+	case differInChecksum:
+		msg = console.Colorize("DiffMessage",
+			"‘"+d.FirstURL+"’"+" and "+"‘"+d.SecondURL+"’") + console.Colorize("DiffChecksum", " - differ in checksum.")
+	case differInNewerFirst:
+		msg = console.Colorize("DiffMessage",
+			"‘"+d.FirstURL+"’") + console.Colorize("DiffTime", " - newer in first.")
+	case differInNewerSecond:
+		msg = console.Colorize("DiffMessage",
+			"‘"+d.SecondURL+"’") + console.Colorize("DiffTime", " - newer in second.")
 	default:
 		fatalIf(errDummy().Trace(d.FirstURL, d.SecondURL),
 			"Unhandled difference between ‘"+d.FirstURL+"’ and ‘"+d.SecondURL+"’.")
@@ -105,10 +136,20 @@ func (d diffMessage) JSON() string {
 	d.Status = "success"
 	diffJSONBytes, e := json.Marshal(d)
 	fatalIf(probe.NewError(e),
-		"Unable to marshal diff message ‘"+d.FirstURL+"’, ‘"+d.SecondURL+"’ and ‘"+string(d.Diff)+"’.")
+		"Unable to marshal diff message ‘"+d.FirstURL+"’, ‘"+d.SecondURL+"’ and ‘"+d.Diff.String()+"’.")
 	return string(diffJSONBytes)
 }
 
+// Columns names the columns of diff messages printed as rows.
+func (d diffMessage) Columns() []string {
+	return []string{"first", "second", "diff"}
+}
+
+// Row diff message as a row of a table.
+func (d diffMessage) Row() []string {
+	return []string{d.FirstURL, d.SecondURL, d.Diff.String()}
+}
+
 func checkDiffSyntax(ctx *cli.Context) {
 	if len(ctx.Args()) != 2 {
 		cli.ShowCommandHelpAndExit(ctx, "diff", 1) // last argument is exit code
@@ -118,6 +159,13 @@ func checkDiffSyntax(ctx *cli.Context) {
 			fatalIf(errInvalidArgument().Trace(ctx.Args()...), "Unable to validate empty argument.")
 		}
 	}
+	if _, err := parseCompareMode(ctx.String("compare")); err != nil {
+		fatalIf(err.Trace(), "Unable to parse compare mode.")
+	}
+	if _, err := parseCacheTTL(ctx.String("cache-ttl"), ctx.Bool("no-cache")); err != nil {
+		fatalIf(err.Trace(), "Unable to parse cache TTL.")
+	}
+	checkOutputSyntax(ctx)
 	URLs := ctx.Args()
 	firstURL := URLs[0]
 	secondURL := URLs[1]
@@ -148,7 +196,7 @@ func checkDiffSyntax(ctx *cli.Context) {
 }
 
 // doDiffMain runs the diff.
-func doDiffMain(firstURL, secondURL string) {
+func doDiffMain(firstURL, secondURL string, isChecksum, isNewer bool, cache *listingCache, printer *messagePrinter) {
 	// Source and targets are always directories
 	sourceSeparator := string(newClientURL(firstURL).Separator)
 	if !strings.HasSuffix(firstURL, sourceSeparator) {
@@ -176,9 +224,18 @@ func doDiffMain(firstURL, secondURL string) {
 	}
 
 	// Diff first and second urls.
-	for diffMsg := range objectDifference(firstClient, secondClient, firstURL, secondURL) {
-		printMsg(diffMsg)
+	compare := compareOpts{
+		Checksum:    isChecksum,
+		Time:        isNewer,
+		SourceAlias: firstAlias,
+		TargetAlias: secondAlias,
+		SourceCache: cache,
+		TargetCache: cache,
 	}
+	for diffMsg := range objectDifference(firstClient, secondClient, firstURL, secondURL, compare) {
+		printer.Print(diffMsg)
+	}
+	printer.Flush()
 }
 
 // mainDiff main for 'diff'.
@@ -194,11 +251,18 @@ func mainDiff(ctx *cli.Context) {
 	console.SetColor("DiffOnlyInFirst", color.New(color.FgRed, color.Bold))
 	console.SetColor("DiffType", color.New(color.FgYellow, color.Bold))
 	console.SetColor("DiffSize", color.New(color.FgMagenta, color.Bold))
+	console.SetColor("DiffChecksum", color.New(color.FgMagenta, color.Bold))
 	console.SetColor("DiffTime", color.New(color.FgYellow, color.Bold))
 
 	URLs := ctx.Args()
 	firstURL := URLs[0]
 	secondURL := URLs[1]
+	isChecksum, _ := parseCompareMode(ctx.String("compare"))
+
+	isNewer := ctx.Bool("newer")
+	format, _ := parseOutputFormat(ctx.String("output"), globalJSON)
+	cache, err := newListingCache(ctx.String("cache-ttl"), ctx.Bool("no-cache"))
+	fatalIf(err.Trace(), "Unable to initialize listing cache.")
 
-	doDiffMain(firstURL, secondURL)
+	doDiffMain(firstURL, secondURL, isChecksum, isNewer, cache, newMessagePrinter(format))
 }
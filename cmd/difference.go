@@ -17,21 +17,57 @@
 package cmd
 
 import (
+	"encoding/json"
 	"fmt"
 	"strings"
+
+	"github.com/minio/minio/pkg/probe"
 )
 
 // differType difference in type.
 type differType int
 
 const (
-	differInNone   differType = iota // does not differ
-	differInSize                     // differs in size
-	differInType                     // only in source
-	differInFirst                    // only in target
-	differInSecond                   // differs in type, exfile/directory
+	differInNone        differType = iota // does not differ
+	differInSize                          // differs in size
+	differInType                          // only in source
+	differInFirst                         // only in target
+	differInSecond                        // differs in type, exfile/directory
+	differInChecksum                      // differs in checksum, same size
+	differInNewerFirst                    // same size, modified later in source
+	differInNewerSecond                   // same size, modified later in target
+)
+
+// Modes of comparing files of the same name.
+const (
+	compareSize     = "size"
+	compareChecksum = "checksum"
+)
+
+const (
+	// Number of files compared by checksum at once.
+	checksumWorkers = 16
+	// Number of differences kept in order while files are compared,
+	// enough to keep the workers busy. Listings are not read further
+	// ahead of the differences passed on.
+	maxPendingDifferences = 2 * checksumWorkers
 )
 
+// compareOpts - files of the same size are compared by checksum if
+// set, by ETags known from the listings if ETag is set, and by
+// modification time if Time is set. Clients of the files are created
+// with the aliases of the compared folders. Folders are listed through
+// their caches, which may be nil.
+type compareOpts struct {
+	Checksum    bool
+	ETag        bool
+	Time        bool
+	SourceAlias string
+	TargetAlias string
+	SourceCache *listingCache
+	TargetCache *listingCache
+}
+
 func (d differType) String() string {
 	switch d {
 	case differInNone:
@@ -44,27 +80,138 @@ func (d differType) String() string {
 		return "only-in-first"
 	case differInSecond:
 		return "only-in-second"
+	case differInChecksum:
+		return "checksum"
+	case differInNewerFirst:
+		return "newer-in-first"
+	case differInNewerSecond:
+		return "newer-in-second"
 	}
 	return "unknown"
 }
 
+// MarshalJSON - differences are named in JSON output.
+func (d differType) MarshalJSON() ([]byte, error) {
+	return json.Marshal(d.String())
+}
+
+// timeDiffers - classifies files of the same size by which of them was
+// modified later, files of unknown modification time do not differ.
+func timeDiffers(srcCtnt, tgtCtnt *ClientContent) differType {
+	if srcCtnt.Time.IsZero() || tgtCtnt.Time.IsZero() {
+		return differInNone
+	}
+	if srcCtnt.Time.After(tgtCtnt.Time) {
+		return differInNewerFirst
+	}
+	if tgtCtnt.Time.After(srcCtnt.Time) {
+		return differInNewerSecond
+	}
+	return differInNone
+}
+
+// parseCompareMode - files are compared by checksum for the checksum
+// mode, by size otherwise.
+func parseCompareMode(mode string) (bool, *probe.Error) {
+	switch mode {
+	case "", compareSize:
+		return false, nil
+	case compareChecksum:
+		return true, nil
+	}
+	return false, errInvalidCompareMode(mode).Trace(mode)
+}
+
+// checksumDiffers - compares the checksums of two files of the same
+// size. Objects report their ETag without being read, files are hashed
+// in parts of the size of the object they are compared to.
+func checksumDiffers(sourceAlias string, srcCtnt *ClientContent, targetAlias string, tgtCtnt *ClientContent) (bool, *probe.Error) {
+	// Objects listed with equal ETags are alike without being asked.
+	if srcCtnt.ETag != "" && trimETag(srcCtnt.ETag) == trimETag(tgtCtnt.ETag) {
+		return false, nil
+	}
+	first, err := newClientFromAlias(sourceAlias, srcCtnt.URL.String())
+	if err != nil {
+		return false, err.Trace(sourceAlias, srcCtnt.URL.String())
+	}
+	second, err := newClientFromAlias(targetAlias, tgtCtnt.URL.String())
+	if err != nil {
+		return false, err.Trace(targetAlias, tgtCtnt.URL.String())
+	}
+	if mustGetHostConfig(sourceAlias) == nil {
+		first, second = second, first
+	}
+	firstSum, partSize, err := first.Checksum(-1)
+	if err != nil {
+		return false, err.Trace(first.GetURL().String())
+	}
+	secondSum, _, err := second.Checksum(partSize)
+	if err != nil {
+		return false, err.Trace(second.GetURL().String())
+	}
+	return firstSum != secondSum, nil
+}
+
 // objectDifference function finds the difference between all objects
 // recursively in sorted order from source and target.
-func objectDifference(sourceClnt, targetClnt Client, sourceURL, targetURL string) (diffCh chan diffMessage) {
+func objectDifference(sourceClnt, targetClnt Client, sourceURL, targetURL string, compare compareOpts) (diffCh chan diffMessage) {
+	// Listings are always recursive and without incomplete objects.
+	srcCh := compare.SourceCache.List(globalContext, sourceClnt, compare.SourceAlias)
+	tgtCh := compare.TargetCache.List(globalContext, targetClnt, compare.TargetAlias)
+	return listingDifference(srcCh, tgtCh, sourceURL, targetURL, compare)
+}
+
+// listingDifference - finds the difference between the recursive
+// listings of source and target, both in sorted order. Files are
+// compared by checksum by several workers at once, differences are
+// passed in order nevertheless. Differences are passed as they are
+// found, the listings wait for them to be received.
+func listingDifference(srcCh, tgtCh <-chan *ClientContent, sourceURL, targetURL string, compare compareOpts) (diffCh chan diffMessage) {
 	var (
 		srcEOF, tgtEOF       bool
 		srcOk, tgtOk         bool
-		srcCtnt, tgtCtnt     *clientContent
+		srcCtnt, tgtCtnt     *ClientContent
 		srcSuffix, tgtSuffix string
 	)
 
-	// Set default values for listing.
-	isRecursive := true   // recursive is always true for diff.
-	isIncomplete := false // we will not compare any incomplete objects.
-	srcCh := sourceClnt.List(isRecursive, isIncomplete)
-	tgtCh := targetClnt.List(isRecursive, isIncomplete)
+	diffCh = make(chan diffMessage)
 
-	diffCh = make(chan diffMessage, 1000)
+	// Differences pending in order, each holding at most one message
+	// once the files were compared.
+	pendingCh := make(chan chan diffMessage, maxPendingDifferences)
+	go func() {
+		defer close(diffCh)
+		for resultCh := range pendingCh {
+			for diffMsg := range resultCh {
+				diffCh <- diffMsg
+			}
+		}
+	}()
+	send := func(diffMsg diffMessage) {
+		resultCh := make(chan diffMessage, 1)
+		resultCh <- diffMsg
+		close(resultCh)
+		pendingCh <- resultCh
+	}
+	workers := make(chan struct{}, checksumWorkers)
+	sendCompared := func(srcCtnt, tgtCtnt *ClientContent) {
+		resultCh := make(chan diffMessage, 1)
+		pendingCh <- resultCh
+		workers <- struct{}{}
+		go func() {
+			defer func() { <-workers }()
+			defer close(resultCh)
+			if diff := sameSizeDiffers(srcCtnt, tgtCtnt, sourceURL, targetURL, compare); diff != differInNone {
+				resultCh <- diffMessage{
+					FirstURL:      srcCtnt.URL.String(),
+					SecondURL:     tgtCtnt.URL.String(),
+					Diff:          diff,
+					firstContent:  srcCtnt,
+					secondContent: tgtCtnt,
+				}
+			}
+		}()
+	}
 
 	go func() {
 
@@ -77,7 +224,7 @@ func objectDifference(sourceClnt, targetClnt Client, sourceURL, targetURL string
 
 			// No objects from source AND target: Finish
 			if srcEOF && tgtEOF {
-				close(diffCh)
+				close(pendingCh)
 				break
 			}
 
@@ -113,22 +260,22 @@ func objectDifference(sourceClnt, targetClnt Client, sourceURL, targetURL string
 
 			// If source doesn't have objects anymore, comparison becomes obvious
 			if srcEOF {
-				diffCh <- diffMessage{
+				send(diffMessage{
 					SecondURL:     tgtCtnt.URL.String(),
 					Diff:          differInSecond,
 					secondContent: tgtCtnt,
-				}
+				})
 				tgtCtnt, tgtOk = <-tgtCh
 				continue
 			}
 
 			// The same for target
 			if tgtEOF {
-				diffCh <- diffMessage{
+				send(diffMessage{
 					FirstURL:     srcCtnt.URL.String(),
 					Diff:         differInFirst,
 					firstContent: srcCtnt,
-				}
+				})
 				srcCtnt, srcOk = <-srcCh
 				continue
 			}
@@ -140,11 +287,11 @@ func objectDifference(sourceClnt, targetClnt Client, sourceURL, targetURL string
 			expected := urlJoinPath(targetURL, tgtSuffix)
 
 			if expected > current {
-				diffCh <- diffMessage{
+				send(diffMessage{
 					FirstURL:     srcCtnt.URL.String(),
 					Diff:         differInFirst,
 					firstContent: srcCtnt,
-				}
+				})
 				srcCtnt, srcOk = <-srcCh
 				continue
 			}
@@ -154,21 +301,35 @@ func objectDifference(sourceClnt, targetClnt Client, sourceURL, targetURL string
 				if srcType.IsRegular() && !tgtType.IsRegular() ||
 					!srcType.IsRegular() && tgtType.IsRegular() {
 					// Type differes. Source is never a directory.
-					diffCh <- diffMessage{
+					send(diffMessage{
 						FirstURL:      srcCtnt.URL.String(),
 						SecondURL:     tgtCtnt.URL.String(),
 						Diff:          differInType,
 						firstContent:  srcCtnt,
 						secondContent: tgtCtnt,
-					}
+					})
 				} else if (srcType.IsRegular() && tgtType.IsRegular()) && srcSize != tgtSize {
 					// Regular files differing in size.
-					diffCh <- diffMessage{
+					send(diffMessage{
 						FirstURL:      srcCtnt.URL.String(),
 						SecondURL:     tgtCtnt.URL.String(),
 						Diff:          differInSize,
 						firstContent:  srcCtnt,
 						secondContent: tgtCtnt,
+					})
+				} else if srcType.IsRegular() && tgtType.IsRegular() {
+					// Regular files of the same size, compared by
+					// the workers if checksums are to be read.
+					if compare.Checksum {
+						sendCompared(srcCtnt, tgtCtnt)
+					} else if diff := sameSizeDiffers(srcCtnt, tgtCtnt, sourceURL, targetURL, compare); diff != differInNone {
+						send(diffMessage{
+							FirstURL:      srcCtnt.URL.String(),
+							SecondURL:     tgtCtnt.URL.String(),
+							Diff:          diff,
+							firstContent:  srcCtnt,
+							secondContent: tgtCtnt,
+						})
 					}
 				}
 				// No differ
@@ -177,11 +338,11 @@ func objectDifference(sourceClnt, targetClnt Client, sourceURL, targetURL string
 				continue
 			}
 			// Differ in second
-			diffCh <- diffMessage{
+			send(diffMessage{
 				SecondURL:     tgtCtnt.URL.String(),
 				Diff:          differInSecond,
 				secondContent: tgtCtnt,
-			}
+			})
 			tgtCtnt, tgtOk = <-tgtCh
 			continue
 		}
@@ -189,3 +350,26 @@ func objectDifference(sourceClnt, targetClnt Client, sourceURL, targetURL string
 
 	return diffCh
 }
+
+// sameSizeDiffers - compares regular files of the same size by their
+// contents and then by their modification times.
+func sameSizeDiffers(srcCtnt, tgtCtnt *ClientContent, sourceURL, targetURL string, compare compareOpts) differType {
+	diff := differInNone
+	var err *probe.Error
+	if compare.ETag && srcCtnt.ETag != "" && tgtCtnt.ETag != "" && srcCtnt.ETag != tgtCtnt.ETag {
+		diff = differInChecksum
+	}
+	if compare.Checksum && diff == differInNone {
+		var differs bool
+		differs, err = checksumDiffers(compare.SourceAlias, srcCtnt, compare.TargetAlias, tgtCtnt)
+		if err != nil {
+			errorIf(err.Trace(sourceURL, targetURL), fmt.Sprintf("Unable to compare checksums of '%s'", srcCtnt.URL.String()))
+		} else if differs {
+			diff = differInChecksum
+		}
+	}
+	if err == nil && diff == differInNone && compare.Time {
+		diff = timeDiffers(srcCtnt, tgtCtnt)
+	}
+	return diff
+}
@@ -0,0 +1,176 @@
+/*
+ * Minio Client (C) 2016 Minio, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/minio/minio/pkg/probe"
+	. "gopkg.in/check.v1"
+)
+
+// Test classifying files of two folders.
+func (s *TestSuite) TestObjectDifference(c *C) {
+	root, e := ioutil.TempDir(os.TempDir(), "diff-")
+	c.Assert(e, IsNil)
+	defer os.RemoveAll(root)
+
+	first := filepath.Join(root, "first") + string(filepath.Separator)
+	second := filepath.Join(root, "second") + string(filepath.Separator)
+	modTime := time.Now().Add(-time.Hour)
+	files := []struct {
+		path    string
+		data    string
+		modTime time.Time
+	}{
+		{first + "only-first", "a", modTime},
+		{second + "only-second", "a", modTime},
+		{first + "size", "a", modTime},
+		{second + "size", "ab", modTime},
+		{first + "newer-first", "a", modTime.Add(time.Minute)},
+		{second + "newer-first", "a", modTime},
+		{first + "newer-second", "a", modTime},
+		{second + "newer-second", "a", modTime.Add(time.Minute)},
+		{first + "same", "a", modTime},
+		{second + "same", "a", modTime},
+	}
+	for _, file := range files {
+		c.Assert(os.MkdirAll(filepath.Dir(file.path), 0700), IsNil)
+		c.Assert(ioutil.WriteFile(file.path, []byte(file.data), 0600), IsNil)
+		c.Assert(os.Chtimes(file.path, file.modTime, file.modTime), IsNil)
+	}
+
+	firstClnt, err := fsNew(first)
+	c.Assert(err, IsNil)
+	secondClnt, err := fsNew(second)
+	c.Assert(err, IsNil)
+
+	diffs := map[string]differType{}
+	for diffMsg := range objectDifference(firstClnt, secondClnt, first, second, compareOpts{Time: true}) {
+		url := diffMsg.FirstURL
+		if url == "" {
+			url = diffMsg.SecondURL
+		}
+		diffs[filepath.Base(url)] = diffMsg.Diff
+	}
+	c.Assert(diffs, DeepEquals, map[string]differType{
+		"only-first":   differInFirst,
+		"only-second":  differInSecond,
+		"size":         differInSize,
+		"newer-first":  differInNewerFirst,
+		"newer-second": differInNewerSecond,
+	})
+
+	// Times are not compared unless asked for.
+	firstClnt, err = fsNew(first)
+	c.Assert(err, IsNil)
+	secondClnt, err = fsNew(second)
+	c.Assert(err, IsNil)
+	for diffMsg := range objectDifference(firstClnt, secondClnt, first, second, compareOpts{}) {
+		c.Assert(diffMsg.Diff, Not(Equals), differInNewerFirst)
+		c.Assert(diffMsg.Diff, Not(Equals), differInNewerSecond)
+	}
+
+	// Differences are named in JSON.
+	var msg struct {
+		Diff string `json:"diff"`
+	}
+	c.Assert(json.Unmarshal([]byte(diffMessage{Diff: differInNewerFirst}.JSON()), &msg), IsNil)
+	c.Assert(msg.Diff, Equals, "newer-in-first")
+}
+
+// Test comparing listings by checksum in order.
+func (s *TestSuite) TestListingDifference(c *C) {
+	modTime := time.Now().Add(-time.Hour)
+	listing := func(root string, n int, newer bool) <-chan *ClientContent {
+		contentCh := make(chan *ClientContent)
+		go func() {
+			defer close(contentCh)
+			for i := 0; i < n; i++ {
+				content := &ClientContent{
+					URL:  *newClientURL(fmt.Sprintf("%s%04d", root, i)),
+					Size: 1,
+					Time: modTime,
+					Type: os.FileMode(0664),
+					ETag: fmt.Sprintf("\"%d\"", i),
+				}
+				// Every third object of the source was modified
+				// later, with the same contents.
+				if newer && i%3 == 0 {
+					content.Time = modTime.Add(time.Minute)
+				}
+				contentCh <- content
+			}
+		}()
+		return contentCh
+	}
+
+	// Objects listed with equal ETags are compared without being
+	// read, the later ones are only in the source.
+	first, second := "http://localhost:9000/first/", "http://localhost:9000/second/"
+	compare := compareOpts{Checksum: true, Time: true}
+	var diffs []string
+	for diffMsg := range listingDifference(listing(first, 100, true), listing(second, 90, false), first, second, compare) {
+		url := diffMsg.FirstURL
+		diffs = append(diffs, url[len(url)-4:]+" "+diffMsg.Diff.String())
+	}
+	var expected []string
+	for i := 0; i < 100; i++ {
+		switch {
+		case i >= 90:
+			expected = append(expected, fmt.Sprintf("%04d only-in-first", i))
+		case i%3 == 0:
+			expected = append(expected, fmt.Sprintf("%04d newer-in-first", i))
+		}
+	}
+	c.Assert(diffs, DeepEquals, expected)
+}
+
+// Test that differences are printed as they are found.
+func (s *TestSuite) TestDiffStreamed(c *C) {
+	root, e := ioutil.TempDir(os.TempDir(), "diff-")
+	c.Assert(e, IsNil)
+	defer os.RemoveAll(root)
+
+	handler := &endlessListHandler{pageSize: 100}
+	server := httptest.NewServer(handler)
+	defer server.Close()
+
+	defer func(load func() (*configV8, *probe.Error)) { loadMcConfig = load }(loadMcConfig)
+	loadMcConfig = func() (*configV8, *probe.Error) {
+		conf := newMcConfig()
+		conf.Hosts["s3"] = hostConfigV8{
+			URL:       server.URL,
+			AccessKey: "WLGDGYAQYIGI833EV05A",
+			SecretKey: "BYvgJM101sHngl2uzjXS/OBF/aMxAN06JrJ3qJlF",
+			API:       "S3v4",
+			Lookup:    "path",
+		}
+		return conf, nil
+	}
+
+	// All objects are only in the first folder.
+	assertStreamed(c, handler, func() {
+		doDiffMain("s3/bucket/", root, false, false, nil, newMessagePrinter(""))
+	})
+}
@@ -0,0 +1,218 @@
+/*
+ * Minio Client (C) 2016 Minio, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/dustin/go-humanize"
+	"github.com/fatih/color"
+	"github.com/minio/cli"
+	"github.com/minio/mc/pkg/console"
+	"github.com/minio/minio/pkg/probe"
+)
+
+// du specific flags.
+var (
+	duFlags = []cli.Flag{
+		cli.BoolFlag{
+			Name:  "help, h",
+			Usage: "Help of du.",
+		},
+		cli.IntFlag{
+			Name:  "depth, d",
+			Usage: "Summarize prefixes up to this depth below the target, 0 only summarizes the target.",
+		},
+		outputFlag,
+	}
+)
+
+// summarize disk usage.
+var duCmd = cli.Command{
+	Name:   "du",
+	Usage:  "Summarize disk usage of folders and prefixes.",
+	Action: mainDU,
+	Flags:  append(duFlags, globalFlags...),
+	CustomHelpTemplate: `NAME:
+   mc {{.Name}} - {{.Usage}}
+
+USAGE:
+   mc {{.Name}} [FLAGS] TARGET [TARGET ...]
+
+FLAGS:
+  {{range .Flags}}{{.}}
+  {{end}}
+EXAMPLES:
+   1. Summarize disk usage of a bucket on Amazon S3 cloud storage.
+      $ mc {{.Name}} s3/mybucket
+
+   2. Summarize disk usage of a bucket and of every prefix directly below it.
+      $ mc {{.Name}} --depth 1 s3/mybucket
+
+   3. Summarize disk usage of all buckets on Amazon S3 cloud storage.
+      $ mc {{.Name}} --depth 1 s3
+
+   4. Summarize disk usage of a local folder.
+      $ mc {{.Name}} /var/log/
+
+   5. Summarize disk usage of all buckets as a table.
+      $ mc {{.Name}} --depth 1 --output table s3
+`,
+}
+
+// checkDUSyntax - validate all the passed arguments
+func checkDUSyntax(ctx *cli.Context) {
+	if !ctx.Args().Present() {
+		cli.ShowCommandHelpAndExit(ctx, "du", 1) // last argument is exit code
+	}
+	for _, arg := range ctx.Args() {
+		if strings.TrimSpace(arg) == "" {
+			fatalIf(errInvalidArgument().Trace(ctx.Args()...), "Unable to validate empty argument.")
+		}
+	}
+	if ctx.Int("depth") < 0 {
+		fatalIf(errInvalidArgument().Trace(ctx.String("depth")), "Depth cannot be negative.")
+	}
+	checkOutputSyntax(ctx)
+}
+
+// duMessage container for disk usage of a prefix.
+type duMessage struct {
+	Status  string `json:"status"`
+	Prefix  string `json:"prefix"`
+	Size    int64  `json:"size"`
+	Objects int64  `json:"objects"`
+}
+
+// String colorized disk usage message.
+func (d duMessage) String() string {
+	return console.Colorize("Size", fmt.Sprintf("%7s ", humanize.IBytes(uint64(d.Size)))) +
+		console.Colorize("Objects", fmt.Sprintf("%9d objects ", d.Objects)) +
+		console.Colorize("Prefix", d.Prefix)
+}
+
+// JSON jsonified disk usage message.
+func (d duMessage) JSON() string {
+	d.Status = "success"
+	duMessageJSONBytes, e := json.Marshal(d)
+	fatalIf(probe.NewError(e), "Unable to marshal into JSON.")
+	return string(duMessageJSONBytes)
+}
+
+// Columns names the columns of disk usage messages printed as rows.
+func (d duMessage) Columns() []string {
+	return []string{"size", "objects", "prefix"}
+}
+
+// Row disk usage message as a row of a table.
+func (d duMessage) Row() []string {
+	return []string{strconv.FormatInt(d.Size, 10), strconv.FormatInt(d.Objects, 10), d.Prefix}
+}
+
+// doDU - walks all objects below the client URL and summarizes their
+// usage at every prefix up to depth, prefixes are named relative to
+// targetURL. Summaries are sorted by prefix, the total comes last.
+func doDU(clnt Client, targetURL string, depth int) ([]duMessage, *probe.Error) {
+	clntURL := clnt.GetURL()
+	separator := string(clntURL.Separator)
+
+	total := duMessage{Prefix: targetURL}
+	usage := make(map[string]*duMessage)
+	for content := range clnt.List(globalContext, true, false) {
+		if content.Err != nil {
+			return nil, content.Err.Trace(clntURL.String())
+		}
+		if content.Type.IsDir() {
+			continue
+		}
+		total.Size += content.Size
+		total.Objects++
+
+		// Objects are summarized at the prefixes of their parents.
+		dirs := strings.Split(strings.TrimPrefix(content.URL.Path, clntURL.Path), separator)
+		dirs = dirs[:len(dirs)-1]
+		for i := 1; i <= depth && i <= len(dirs); i++ {
+			prefix := targetURL + strings.Join(dirs[:i], separator) + separator
+			if usage[prefix] == nil {
+				usage[prefix] = &duMessage{Prefix: prefix}
+			}
+			usage[prefix].Size += content.Size
+			usage[prefix].Objects++
+		}
+	}
+
+	var prefixes []string
+	for prefix := range usage {
+		prefixes = append(prefixes, prefix)
+	}
+	sort.Strings(prefixes)
+	var msgs []duMessage
+	for _, prefix := range prefixes {
+		msgs = append(msgs, *usage[prefix])
+	}
+	return append(msgs, total), nil
+}
+
+// mainDU - is a handler for mc du command
+func mainDU(ctx *cli.Context) {
+	// Additional command specific theme customization.
+	console.SetColor("Size", color.New(color.FgYellow))
+	console.SetColor("Objects", color.New(color.FgGreen))
+	console.SetColor("Prefix", color.New(color.FgCyan, color.Bold))
+
+	// Set global flags from context.
+	setGlobalsFromContext(ctx)
+
+	// check 'du' cli arguments.
+	checkDUSyntax(ctx)
+
+	depth := ctx.Int("depth")
+	format, _ := parseOutputFormat(ctx.String("output"), globalJSON)
+	printer := newMessagePrinter(format)
+	for _, targetURL := range ctx.Args() {
+		clnt, err := newClient(targetURL)
+		fatalIf(err.Trace(targetURL), "Unable to initialize target ‘"+targetURL+"’.")
+
+		// Folders, buckets and aliases are summarized by their contents.
+		separator := string(clnt.GetURL().Separator)
+		st, err := clnt.Stat()
+		if err != nil {
+			if _, ok := err.ToGoError().(BucketNameEmpty); !ok {
+				fatalIf(err.Trace(targetURL), "Unable to initialize target ‘"+targetURL+"’.")
+			}
+		}
+		if (err != nil || st.Type.IsDir()) && !strings.HasSuffix(targetURL, separator) {
+			targetURL = targetURL + separator
+			clnt, err = newClient(targetURL)
+			fatalIf(err.Trace(targetURL), "Unable to initialize target ‘"+targetURL+"’.")
+		}
+
+		msgs, err := doDU(clnt, targetURL, depth)
+		if err != nil {
+			errorIf(err.Trace(targetURL), "Unable to summarize disk usage of ‘"+targetURL+"’.")
+			continue
+		}
+		for _, msg := range msgs {
+			printer.Print(msg)
+		}
+	}
+	printer.Flush()
+}
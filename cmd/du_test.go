@@ -0,0 +1,67 @@
+/*
+ * Minio Client (C) 2016 Minio, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"context"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+
+	. "gopkg.in/check.v1"
+)
+
+// Test summarizing disk usage at prefixes of a folder.
+func (s *TestSuite) TestDiskUsage(c *C) {
+	root, e := ioutil.TempDir(os.TempDir(), "du-")
+	c.Assert(e, IsNil)
+	defer os.RemoveAll(root)
+
+	objects := map[string]string{
+		"a/x":     "x",
+		"a/b/y":   "yy",
+		"a/b/c/z": "zzz",
+		"d/w":     "wwww",
+		"v":       "vvvvv",
+	}
+	for name, data := range objects {
+		objectPath := filepath.Join(root, filepath.FromSlash(name))
+		fsClient, err := fsNew(objectPath)
+		c.Assert(err, IsNil)
+		_, err = fsClient.Put(context.Background(), strings.NewReader(data), int64(len(data)), "application/octet-stream", nil, PutOpts{})
+		c.Assert(err, IsNil)
+	}
+
+	targetURL := root + string(filepath.Separator)
+	clnt, err := fsNew(targetURL)
+	c.Assert(err, IsNil)
+
+	msgs, err := doDU(clnt, targetURL, 0)
+	c.Assert(err, IsNil)
+	c.Assert(msgs, DeepEquals, []duMessage{{Prefix: targetURL, Size: 15, Objects: 5}})
+
+	msgs, err = doDU(clnt, targetURL, 2)
+	c.Assert(err, IsNil)
+	sep := string(filepath.Separator)
+	c.Assert(msgs, DeepEquals, []duMessage{
+		{Prefix: targetURL + "a" + sep, Size: 6, Objects: 3},
+		{Prefix: targetURL + "a" + sep + "b" + sep, Size: 5, Objects: 2},
+		{Prefix: targetURL + "d" + sep, Size: 4, Objects: 1},
+		{Prefix: targetURL, Size: 15, Objects: 5},
+	})
+}
@@ -0,0 +1,165 @@
+/*
+ * Minio Client (C) 2016 Minio, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"crypto/md5"
+	"encoding/base64"
+	"net/http"
+	"strings"
+
+	"github.com/minio/minio/pkg/probe"
+)
+
+// Server side encryption types.
+const (
+	sseCustomer = "SSE-C"
+	sseS3       = "SSE-S3"
+	sseKMS      = "SSE-KMS"
+)
+
+// Length of a customer provided key in bytes.
+const sseKeyLength = 32
+
+// EncryptOpts - server side encryption of an object, zero value means
+// no encryption.
+type EncryptOpts struct {
+	Type     string
+	Key      []byte
+	KMSKeyID string
+}
+
+// isEmpty - no encryption requested.
+func (e EncryptOpts) isEmpty() bool {
+	return e.Type == ""
+}
+
+// setCustomerKeyHeaders - sets SSE-C headers with the given prefix.
+func (e EncryptOpts) setCustomerKeyHeaders(h http.Header, prefix string) {
+	keyMD5 := md5.Sum(e.Key)
+	h.Set(prefix+"-Algorithm", "AES256")
+	h.Set(prefix+"-Key", base64.StdEncoding.EncodeToString(e.Key))
+	h.Set(prefix+"-Key-Md5", base64.StdEncoding.EncodeToString(keyMD5[:]))
+}
+
+// setPutHeaders - sets headers needed to encrypt an uploaded object.
+func (e EncryptOpts) setPutHeaders(h http.Header) {
+	switch e.Type {
+	case sseCustomer:
+		e.setCustomerKeyHeaders(h, "X-Amz-Server-Side-Encryption-Customer")
+	case sseS3:
+		h.Set("X-Amz-Server-Side-Encryption", "AES256")
+	case sseKMS:
+		h.Set("X-Amz-Server-Side-Encryption", "aws:kms")
+		if e.KMSKeyID != "" {
+			h.Set("X-Amz-Server-Side-Encryption-Aws-Kms-Key-Id", e.KMSKeyID)
+		}
+	}
+}
+
+// setGetHeaders - sets headers needed to read an encrypted object,
+// only customer provided keys have to be sent.
+func (e EncryptOpts) setGetHeaders(h http.Header) {
+	if e.Type == sseCustomer {
+		e.setCustomerKeyHeaders(h, "X-Amz-Server-Side-Encryption-Customer")
+	}
+}
+
+// setCopySourceHeaders - sets headers needed to read an encrypted
+// copy source.
+func (e EncryptOpts) setCopySourceHeaders(h http.Header) {
+	if e.Type == sseCustomer {
+		e.setCustomerKeyHeaders(h, "X-Amz-Copy-Source-Server-Side-Encryption-Customer")
+	}
+}
+
+// parseSSECustomerKey - keys are accepted either as 32 raw bytes or
+// base64 encoded.
+func parseSSECustomerKey(key string) ([]byte, bool) {
+	if len(key) == sseKeyLength {
+		return []byte(key), true
+	}
+	decodedKey, e := base64.StdEncoding.DecodeString(key)
+	if e != nil || len(decodedKey) != sseKeyLength {
+		return nil, false
+	}
+	return decodedKey, true
+}
+
+// parseEncryptKeys - parses the values of ‘--encrypt-key’ and
+// ‘--encrypt’ into encryption options per aliased URL prefix.
+//
+//	--encrypt-key "ALIAS/BUCKET/PREFIX=KEY,..."  SSE-C with the given key.
+//	--encrypt "ALIAS/BUCKET/PREFIX,..."          SSE-S3.
+//	--encrypt "ALIAS/BUCKET/PREFIX=KMS-KEY-ID"   SSE-KMS with the given key id.
+func parseEncryptKeys(sseKeys, sse string) (map[string]EncryptOpts, *probe.Error) {
+	encKeys := make(map[string]EncryptOpts)
+	for _, field := range splitEncryptValues(sseKeys) {
+		// Base64 encoded keys may end with ‘=’, split at the first one.
+		i := strings.Index(field, "=")
+		if i <= 0 {
+			return nil, errInvalidEncryptKey(field).Trace(field)
+		}
+		prefix := field[:i]
+		key, ok := parseSSECustomerKey(field[i+1:])
+		if !ok {
+			return nil, errInvalidEncryptKey(prefix).Trace(prefix)
+		}
+		encKeys[prefix] = EncryptOpts{Type: sseCustomer, Key: key}
+	}
+	for _, field := range splitEncryptValues(sse) {
+		prefix := field
+		opts := EncryptOpts{Type: sseS3}
+		if i := strings.Index(field, "="); i >= 0 {
+			prefix = field[:i]
+			opts = EncryptOpts{Type: sseKMS, KMSKeyID: field[i+1:]}
+		}
+		if prefix == "" {
+			return nil, errInvalidEncryptKey(field).Trace(field)
+		}
+		if _, ok := encKeys[prefix]; ok {
+			return nil, errConflictingEncryption(prefix).Trace(prefix)
+		}
+		encKeys[prefix] = opts
+	}
+	return encKeys, nil
+}
+
+func splitEncryptValues(value string) (fields []string) {
+	for _, field := range strings.Split(value, ",") {
+		if field = strings.TrimSpace(field); field != "" {
+			fields = append(fields, field)
+		}
+	}
+	return fields
+}
+
+// getEncryptOpts - returns the encryption options of the longest
+// prefix matching the aliased URL.
+func getEncryptOpts(encKeys map[string]EncryptOpts, aliasedURL string) EncryptOpts {
+	aliasedURL = strings.TrimPrefix(aliasedURL, "/")
+	var match string
+	for prefix := range encKeys {
+		if strings.HasPrefix(aliasedURL, prefix) && len(prefix) > len(match) {
+			match = prefix
+		}
+	}
+	if match == "" {
+		return EncryptOpts{}
+	}
+	return encKeys[match]
+}
@@ -0,0 +1,137 @@
+/*
+ * Minio Client (C) 2016 Minio, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"net"
+	"sync/atomic"
+
+	"github.com/minio/minio/pkg/probe"
+	"github.com/ricoharisin91/minio-go"
+)
+
+// Exit statuses of mc, so that scripts can branch on the cause of a
+// failure. These are part of the interface of mc, new causes are only
+// ever appended.
+const (
+	exitStatusSuccess           = 0
+	exitStatusFailure           = 1 // Unclassified failures and invalid usage.
+	exitStatusBucketNotFound    = 3
+	exitStatusObjectNotFound    = 4
+	exitStatusAccessDenied      = 5
+	exitStatusAlreadyExists     = 6
+	exitStatusInvalidName       = 7
+	exitStatusEncrypted         = 8
+	exitStatusPreconditionFails = 9
+	exitStatusChecksumMismatch  = 10
+	exitStatusArchived          = 11
+	exitStatusNotImplemented    = 12
+	exitStatusNetwork           = 13
+)
+
+// errorCode - machine readable cause of an error, along with the exit
+// status of mc failing with it.
+type errorCode struct {
+	Code       string
+	ExitStatus int
+}
+
+// Causes of errors returned by object storage, keyed by their S3 error
+// code.
+var s3ErrorCodes = map[string]errorCode{
+	"NoSuchBucket":            {"BucketDoesNotExist", exitStatusBucketNotFound},
+	"NoSuchKey":               {"ObjectMissing", exitStatusObjectNotFound},
+	"NoSuchVersion":           {"ObjectVersionMissing", exitStatusObjectNotFound},
+	"AccessDenied":            {"AccessDenied", exitStatusAccessDenied},
+	"InvalidAccessKeyId":      {"AccessDenied", exitStatusAccessDenied},
+	"SignatureDoesNotMatch":   {"AccessDenied", exitStatusAccessDenied},
+	"BucketAlreadyExists":     {"BucketExists", exitStatusAlreadyExists},
+	"BucketAlreadyOwnedByYou": {"BucketExists", exitStatusAlreadyExists},
+	"InvalidBucketName":       {"BucketInvalid", exitStatusInvalidName},
+	"PreconditionFailed":      {"ObjectPreconditionFailed", exitStatusPreconditionFails},
+	"InvalidObjectState":      {"ObjectOnGlacier", exitStatusArchived},
+	"NotImplemented":          {"APINotImplemented", exitStatusNotImplemented},
+}
+
+// errorCodeOf - cause of an error, unclassified errors are reported
+// with the code ‘Error’.
+func errorCodeOf(err *probe.Error) errorCode {
+	switch e := err.ToGoError().(type) {
+	case BucketDoesNotExist:
+		return errorCode{"BucketDoesNotExist", exitStatusBucketNotFound}
+	case ObjectMissing:
+		return errorCode{"ObjectMissing", exitStatusObjectNotFound}
+	case ObjectVersionMissing:
+		return errorCode{"ObjectVersionMissing", exitStatusObjectNotFound}
+	case PathNotFound:
+		return errorCode{"PathNotFound", exitStatusObjectNotFound}
+	case PathInsufficientPermission:
+		return errorCode{"PathInsufficientPermission", exitStatusAccessDenied}
+	case MFARequired:
+		return errorCode{"MFARequired", exitStatusAccessDenied}
+	case BucketExists:
+		return errorCode{"BucketExists", exitStatusAlreadyExists}
+	case ObjectAlreadyExists:
+		return errorCode{"ObjectAlreadyExists", exitStatusAlreadyExists}
+	case ObjectAlreadyExistsAsDirectory:
+		return errorCode{"ObjectAlreadyExistsAsDirectory", exitStatusAlreadyExists}
+	case BucketNameEmpty:
+		return errorCode{"BucketNameEmpty", exitStatusInvalidName}
+	case BucketInvalid:
+		return errorCode{"BucketInvalid", exitStatusInvalidName}
+	case BucketNameTopLevel:
+		return errorCode{"BucketNameTopLevel", exitStatusInvalidName}
+	case EmptyPath:
+		return errorCode{"EmptyPath", exitStatusInvalidName}
+	case ObjectEncrypted:
+		return errorCode{"ObjectEncrypted", exitStatusEncrypted}
+	case ObjectDecryptionFailed:
+		return errorCode{"ObjectDecryptionFailed", exitStatusEncrypted}
+	case ObjectPreconditionFailed:
+		return errorCode{"ObjectPreconditionFailed", exitStatusPreconditionFails}
+	case ObjectChecksumMismatch:
+		return errorCode{"ObjectChecksumMismatch", exitStatusChecksumMismatch}
+	case ObjectChecksumUnverifiable:
+		return errorCode{"ObjectChecksumUnverifiable", exitStatusChecksumMismatch}
+	case ObjectOnGlacier:
+		return errorCode{"ObjectOnGlacier", exitStatusArchived}
+	case APINotImplemented:
+		return errorCode{"APINotImplemented", exitStatusNotImplemented}
+	case minio.ErrorResponse:
+		if code, ok := s3ErrorCodes[e.Code]; ok {
+			return code
+		}
+	case net.Error:
+		return errorCode{"NetworkError", exitStatusNetwork}
+	}
+	return errorCode{"Error", exitStatusFailure}
+}
+
+// globalExitStatus - exit status of the first error reported by a
+// command which carried on after it, zero while there was none.
+var globalExitStatus int32
+
+// setExitStatus - records the exit status of an error, the first one
+// is kept.
+func setExitStatus(status int) {
+	atomic.CompareAndSwapInt32(&globalExitStatus, exitStatusSuccess, int32(status))
+}
+
+// getExitStatus - exit status of mc once the command is done.
+func getExitStatus() int {
+	return int(atomic.LoadInt32(&globalExitStatus))
+}
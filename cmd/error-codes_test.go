@@ -0,0 +1,49 @@
+/*
+ * Minio Client (C) 2016 Minio, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"errors"
+	"net"
+
+	"github.com/minio/minio/pkg/probe"
+	"github.com/ricoharisin91/minio-go"
+	. "gopkg.in/check.v1"
+)
+
+// Test causes and exit statuses of errors.
+func (s *TestSuite) TestErrorCodeOf(c *C) {
+	testCases := []struct {
+		err  error
+		code errorCode
+	}{
+		{BucketDoesNotExist{Bucket: "bucket"}, errorCode{"BucketDoesNotExist", exitStatusBucketNotFound}},
+		{ObjectMissing{}, errorCode{"ObjectMissing", exitStatusObjectNotFound}},
+		{PathNotFound{Path: "/tmp/missing"}, errorCode{"PathNotFound", exitStatusObjectNotFound}},
+		{PathInsufficientPermission{Path: "/root"}, errorCode{"PathInsufficientPermission", exitStatusAccessDenied}},
+		{ObjectEncrypted{Object: "object"}, errorCode{"ObjectEncrypted", exitStatusEncrypted}},
+		{minio.ErrorResponse{Code: "NoSuchKey"}, errorCode{"ObjectMissing", exitStatusObjectNotFound}},
+		{minio.ErrorResponse{Code: "AccessDenied"}, errorCode{"AccessDenied", exitStatusAccessDenied}},
+		{minio.ErrorResponse{Code: "InternalError"}, errorCode{"Error", exitStatusFailure}},
+		{&net.OpError{Op: "dial", Err: errors.New("connection refused")}, errorCode{"NetworkError", exitStatusNetwork}},
+		{errors.New("unknown"), errorCode{"Error", exitStatusFailure}},
+	}
+	for i, testCase := range testCases {
+		code := errorCodeOf(probe.NewError(testCase.err).Trace())
+		c.Assert(code, Equals, testCase.code, Commentf("Test %d", i+1))
+	}
+}
@@ -19,6 +19,7 @@ package cmd
 import (
 	"encoding/json"
 	"fmt"
+	"os"
 
 	"github.com/minio/mc/pkg/console"
 	"github.com/minio/minio/pkg/probe"
@@ -30,24 +31,31 @@ type causeMessage struct {
 	Error   error  `json:"error"`
 }
 
-// errorMessage container for error messages
+// errorMessage container for error messages, Code and ExitStatus
+// tell the cause of the error as listed in error-codes.go.
 type errorMessage struct {
-	Message   string             `json:"message"`
-	Cause     causeMessage       `json:"cause"`
-	Type      string             `json:"type"`
-	CallTrace []probe.TracePoint `json:"trace,omitempty"`
-	SysInfo   map[string]string  `json:"sysinfo"`
+	Message    string             `json:"message"`
+	Cause      causeMessage       `json:"cause"`
+	Type       string             `json:"type"`
+	Code       string             `json:"code"`
+	ExitStatus int                `json:"exitStatus"`
+	CallTrace  []probe.TracePoint `json:"trace,omitempty"`
+	SysInfo    map[string]string  `json:"sysinfo"`
 }
 
-// fatalIf wrapper function which takes error and selectively prints stack frames if available on debug
+// fatalIf wrapper function which takes error and selectively prints stack frames if available on debug,
+// mc exits with the status of the cause of the error.
 func fatalIf(err *probe.Error, msg string) {
 	if err == nil {
 		return
 	}
+	code := errorCodeOf(err)
 	if globalJSON {
 		errorMsg := errorMessage{
-			Message: msg,
-			Type:    "fatal",
+			Message:    msg,
+			Type:       "fatal",
+			Code:       code.Code,
+			ExitStatus: code.ExitStatus,
 			Cause: causeMessage{
 				Message: err.ToGoError().Error(),
 				Error:   err.ToGoError(),
@@ -68,23 +76,30 @@ func fatalIf(err *probe.Error, msg string) {
 			console.Fatalln(probe.NewError(e))
 		}
 		console.Println(string(json))
-		console.Fatalln()
+		os.Exit(code.ExitStatus)
 	}
 	if !globalDebug {
-		console.Fatalln(fmt.Sprintf("%s %s", msg, err.ToGoError()))
+		console.Errorln(fmt.Sprintf("%s %s", tr(msg), tr(err.ToGoError().Error())))
+		os.Exit(code.ExitStatus)
 	}
-	console.Fatalln(fmt.Sprintf("%s %s", msg, err))
+	console.Errorln(fmt.Sprintf("%s %s", tr(msg), err))
+	os.Exit(code.ExitStatus)
 }
 
-// errorIf synonymous with fatalIf but doesn't exit on error != nil
+// errorIf synonymous with fatalIf but doesn't exit on error != nil, mc
+// exits with the status of the first error once the command is done.
 func errorIf(err *probe.Error, msg string) {
 	if err == nil {
 		return
 	}
+	code := errorCodeOf(err)
+	setExitStatus(code.ExitStatus)
 	if globalJSON {
 		errorMsg := errorMessage{
-			Message: msg,
-			Type:    "error",
+			Message:    msg,
+			Type:       "error",
+			Code:       code.Code,
+			ExitStatus: code.ExitStatus,
 			Cause: causeMessage{
 				Message: err.ToGoError().Error(),
 				Error:   err.ToGoError(),
@@ -108,8 +123,8 @@ func errorIf(err *probe.Error, msg string) {
 		return
 	}
 	if !globalDebug {
-		console.Errorln(fmt.Sprintf("%s %s", msg, err.ToGoError()))
+		console.Errorln(fmt.Sprintf("%s %s", tr(msg), tr(err.ToGoError().Error())))
 		return
 	}
-	console.Errorln(fmt.Sprintf("%s %s", msg, err))
+	console.Errorln(fmt.Sprintf("%s %s", tr(msg), err))
 }
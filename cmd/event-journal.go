@@ -0,0 +1,171 @@
+/*
+ * Minio Client (C) 2016 Minio, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"bufio"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/minio/minio/pkg/probe"
+)
+
+// eventJournalEntry - an event recorded by watch, keyed by the path of
+// the object relative to the watched folder.
+type eventJournalEntry struct {
+	// Time the event was received.
+	Time time.Time `json:"time"`
+	Type EventType `json:"type"`
+	Name string    `json:"name,omitempty"`
+	Key  string    `json:"key"`
+	Size int64     `json:"size,omitempty"`
+}
+
+// eventJournal - events received by a watch of a folder, one entry per
+// line appended to a file of the folder in the mc config folder. A nil
+// journal records nothing.
+type eventJournal struct {
+	f    *os.File
+	root string
+	sep  string
+}
+
+// getEventJournalDir - get event journal directory.
+func getEventJournalDir() (string, *probe.Error) {
+	configDir, err := getMcConfigDir()
+	if err != nil {
+		return "", err.Trace()
+	}
+	return filepath.Join(configDir, globalEventJournalDir), nil
+}
+
+// eventJournalPath - file of the journal of the folder.
+func eventJournalPath(alias, urlStr string) (string, *probe.Error) {
+	dir, err := getEventJournalDir()
+	if err != nil {
+		return "", err.Trace()
+	}
+	u := newClientURL(urlStr)
+	return filepath.Join(dir, hashKey(alias, strings.TrimSuffix(urlStr, string(u.Separator)))+".json"), nil
+}
+
+// newEventJournal - journal of the watched folder, nil unless enabled.
+func newEventJournal(alias, urlStr string, enabled bool) (*eventJournal, *probe.Error) {
+	if !enabled {
+		return nil, nil
+	}
+	path, err := eventJournalPath(alias, urlStr)
+	if err != nil {
+		return nil, err.Trace(alias, urlStr)
+	}
+	if e := os.MkdirAll(filepath.Dir(path), 0700); e != nil {
+		return nil, probe.NewError(e)
+	}
+	f, e := os.OpenFile(path, os.O_WRONLY|os.O_APPEND|os.O_CREATE, 0600)
+	if e != nil {
+		return nil, probe.NewError(e)
+	}
+	// Events of files carry absolute paths.
+	u := newClientURL(urlStr)
+	root := u.Path
+	if u.Type == FileSystem {
+		if abs, e := filepath.Abs(urlStr); e == nil {
+			root = filepath.ToSlash(abs)
+		}
+	}
+	sep := string(u.Separator)
+	if u.Type == FileSystem {
+		sep = "/"
+	}
+	return &eventJournal{f: f, root: strings.TrimSuffix(root, sep), sep: sep}, nil
+}
+
+// Record - appends the event to the journal.
+func (j *eventJournal) Record(event Event) *probe.Error {
+	if j == nil {
+		return nil
+	}
+	eventPath := newClientURL(event.Path).Path
+	if j.sep == "/" {
+		eventPath = filepath.ToSlash(eventPath)
+	}
+	entry := eventJournalEntry{
+		Time: time.Now().UTC(),
+		Type: event.Type,
+		Name: event.Name,
+		Key:  strings.TrimPrefix(strings.TrimPrefix(eventPath, j.root), j.sep),
+		Size: event.Size,
+	}
+	line, e := json.Marshal(entry)
+	if e != nil {
+		return probe.NewError(e)
+	}
+	// Entries are written at once, so that they survive crashes.
+	if _, e = j.f.Write(append(line, '\n')); e != nil {
+		return probe.NewError(e)
+	}
+	return nil
+}
+
+// Close - closes the journal.
+func (j *eventJournal) Close() *probe.Error {
+	if j == nil {
+		return nil
+	}
+	if e := j.f.Close(); e != nil {
+		return probe.NewError(e)
+	}
+	return nil
+}
+
+// readEventJournal - events of the journal of the folder received
+// since the time, in the order they were received.
+func readEventJournal(alias, urlStr string, since time.Time) ([]eventJournalEntry, *probe.Error) {
+	path, err := eventJournalPath(alias, urlStr)
+	if err != nil {
+		return nil, err.Trace(alias, urlStr)
+	}
+	f, e := os.Open(path)
+	if e != nil {
+		if os.IsNotExist(e) {
+			return nil, errNoEventJournal(urlStr).Trace(urlStr)
+		}
+		return nil, probe.NewError(e)
+	}
+	defer f.Close()
+
+	var entries []eventJournalEntry
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		var entry eventJournalEntry
+		// Lines cut short by crashes are skipped.
+		if json.Unmarshal(scanner.Bytes(), &entry) != nil {
+			continue
+		}
+		if entry.Time.Before(since) {
+			continue
+		}
+		entries = append(entries, entry)
+	}
+	if e = scanner.Err(); e != nil {
+		return nil, probe.NewError(e)
+	}
+	return entries, nil
+}
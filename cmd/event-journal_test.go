@@ -0,0 +1,95 @@
+/*
+ * Minio Client (C) 2016 Minio, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"time"
+
+	. "gopkg.in/check.v1"
+)
+
+func (s *TestSuite) TestEventJournalReplay(c *C) {
+	configDir, e := ioutil.TempDir("", "mc-journal-config-")
+	c.Assert(e, IsNil)
+	defer os.RemoveAll(configDir)
+	defer func(dir string) { mcCustomConfigDir = dir }(mcCustomConfigDir)
+	mcCustomConfigDir = configDir
+
+	source, e := ioutil.TempDir("", "mc-journal-source-")
+	c.Assert(e, IsNil)
+	defer os.RemoveAll(source)
+	target, e := ioutil.TempDir("", "mc-journal-target-")
+	c.Assert(e, IsNil)
+	defer os.RemoveAll(target)
+
+	// Nothing was recorded for the folder yet.
+	_, err := readEventJournal("", source, time.Time{})
+	c.Assert(err, NotNil)
+
+	journal, err := newEventJournal("", source, false)
+	c.Assert(err, IsNil)
+	c.Assert(journal, IsNil)
+	c.Assert(journal.Record(Event{Path: filepath.Join(source, "ignored")}), IsNil)
+
+	journal, err = newEventJournal("", source, true)
+	c.Assert(err, IsNil)
+	c.Assert(ioutil.WriteFile(filepath.Join(source, "kept"), []byte("kept"), 0600), IsNil)
+	c.Assert(ioutil.WriteFile(filepath.Join(target, "removed"), []byte("removed"), 0600), IsNil)
+	events := []Event{
+		{Path: filepath.Join(source, "kept"), Type: EventCreate, Size: 4},
+		{Path: filepath.Join(source, "removed"), Type: EventCreate, Size: 7},
+		{Path: filepath.Join(source, "removed"), Type: EventRemove},
+		{Path: filepath.Join(source, "gone"), Type: EventCreate, Size: 4},
+	}
+	for _, event := range events {
+		c.Assert(journal.Record(event), IsNil)
+	}
+	c.Assert(journal.Close(), IsNil)
+
+	entries, err := readEventJournal("", source+string(filepath.Separator), time.Time{})
+	c.Assert(err, IsNil)
+	c.Assert(len(entries), Equals, 4)
+	c.Assert(entries[0].Key, Equals, "kept")
+	entries, err = readEventJournal("", source, time.Now().UTC().Add(time.Hour))
+	c.Assert(err, IsNil)
+	c.Assert(len(entries), Equals, 0)
+
+	entries, err = readEventJournal("", source, time.Time{})
+	c.Assert(err, IsNil)
+	entries = compactEventJournal(entries)
+	c.Assert(len(entries), Equals, 3)
+	for _, entry := range entries {
+		sourceClnt, err := fsNew(filepath.Join(source, entry.Key))
+		c.Assert(err, IsNil)
+		targetClnt, err := fsNew(filepath.Join(target, entry.Key))
+		c.Assert(err, IsNil)
+		_, err = replayEvent(entry, sourceClnt, targetClnt)
+		c.Assert(err, IsNil)
+	}
+
+	data, e := ioutil.ReadFile(filepath.Join(target, "kept"))
+	c.Assert(e, IsNil)
+	c.Assert(string(data), Equals, "kept")
+	_, e = os.Stat(filepath.Join(target, "removed"))
+	c.Assert(os.IsNotExist(e), Equals, true)
+	// Objects removed since they were created are not copied.
+	_, e = os.Stat(filepath.Join(target, "gone"))
+	c.Assert(os.IsNotExist(e), Equals, true)
+}
@@ -31,7 +31,7 @@ var (
 		cli.StringFlag{
 			Name:  "events",
 			Value: "put,delete",
-			Usage: "Filter specific type of events. Defaults to all events.",
+			Usage: "Filter specific type of events, ‘put’, ‘delete’, ‘get’, ‘lifecycle’, ‘replication’ or names like ‘s3:ObjectRestore:Post’.",
 		},
 		cli.StringFlag{
 			Name:  "prefix",
@@ -41,6 +41,10 @@ var (
 			Name:  "suffix",
 			Usage: "Filter events associated to the specified suffix",
 		},
+		cli.BoolFlag{
+			Name:  "verify",
+			Usage: "Send test events to the ARNs by creating and removing an empty object matching the filters.",
+		},
 	}
 )
 
@@ -53,7 +57,7 @@ var eventsAddCmd = cli.Command{
    mc events {{.Name}} - {{.Usage}}
 
 USAGE:
-   mc events {{.Name}} ALIAS/BUCKET ARN [FLAGS]
+   mc events {{.Name}} ALIAS/BUCKET ARN [ARN...] [FLAGS]
 
 FLAGS:
   {{range .Flags}}{{.}}
@@ -63,19 +67,33 @@ EXAMPLES:
      $ mc events {{.Name}} myminio/mybucket arn:aws:sqs:us-west-2:444455556666:your-queue 
    2. Enable bucket notification with filters parameters
      $ mc events {{.Name}} s3/mybucket arn:aws:sqs:us-west-2:444455556666:your-queue --events put,delete --prefix photos/ --suffix .jpg
+   3. Enable bucket notification of reads and of restored objects
+     $ mc events {{.Name}} s3/mybucket arn:aws:sqs:us-west-2:444455556666:your-queue --events get,s3:ObjectRestore:Completed
+   4. Enable bucket notification for a queue and a topic, adding events to their existing configurations
+     $ mc events {{.Name}} s3/mybucket arn:aws:sqs:us-west-2:444455556666:your-queue arn:aws:sns:us-west-2:444455556666:your-topic --events put
+   5. Enable bucket notification and check that the queue receives events of new objects
+     $ mc events {{.Name}} myminio/mybucket arn:minio:sqs:us-east-1:1:webhook --events put --verify
 `,
 }
 
 // checkEventsAddSyntax - validate all the passed arguments
 func checkEventsAddSyntax(ctx *cli.Context) {
-	if len(ctx.Args()) != 2 {
+	if len(ctx.Args()) < 2 {
 		cli.ShowCommandHelpAndExit(ctx, "add", 1) // last argument is exit code
 	}
+	for _, arn := range ctx.Args()[1:] {
+		if _, err := parseNotificationArn(arn); err != nil {
+			fatalIf(err.Trace(), "Unable to parse ARN.")
+		}
+	}
 }
 
 // eventsAddMessage container
 type eventsAddMessage struct {
 	Status string `json:"status"`
+	ARN    string `json:"arn"`
+	// Either ‘added’, ‘updated’ or ‘unchanged’.
+	Change string `json:"change"`
 }
 
 // JSON jsonified update message.
@@ -87,8 +105,32 @@ func (u eventsAddMessage) JSON() string {
 }
 
 func (u eventsAddMessage) String() string {
-	msg := console.Colorize("Events", "Successfully accomplished.")
-	return msg
+	switch u.Change {
+	case notificationAdded:
+		return console.Colorize("Events", "Added notification of ‘"+u.ARN+"’.")
+	case notificationUpdated:
+		return console.Colorize("Events", "Updated events of notification of ‘"+u.ARN+"’.")
+	}
+	return console.Colorize("Events", "Notification of ‘"+u.ARN+"’ is unchanged.")
+}
+
+// eventsTestMessage container
+type eventsTestMessage struct {
+	Status string   `json:"status"`
+	Key    string   `json:"key"`
+	ARNs   []string `json:"arns"`
+}
+
+// JSON jsonified test message.
+func (u eventsTestMessage) JSON() string {
+	u.Status = "success"
+	eventsTestMessageJSONBytes, e := json.Marshal(u)
+	fatalIf(probe.NewError(e), "Unable to marshal into JSON.")
+	return string(eventsTestMessageJSONBytes)
+}
+
+func (u eventsTestMessage) String() string {
+	return console.Colorize("Events", "Created and removed ‘"+u.Key+"’, please check that ‘"+strings.Join(u.ARNs, "’, ‘")+"’ received its events.")
 }
 
 func mainEventsAdd(ctx *cli.Context) {
@@ -99,7 +141,7 @@ func mainEventsAdd(ctx *cli.Context) {
 
 	args := ctx.Args()
 	path := args[0]
-	arn := args[1]
+	arns := args[1:]
 
 	events := strings.Split(ctx.String("events"), ",")
 	prefix := ctx.String("prefix")
@@ -115,7 +157,15 @@ func mainEventsAdd(ctx *cli.Context) {
 		fatalIf(errDummy().Trace(), "The provided url doesn't point to a S3 server.")
 	}
 
-	err = s3Client.AddNotificationConfig(arn, events, prefix, suffix)
+	changes, err := s3Client.AddNotificationConfig(arns, events, prefix, suffix)
 	fatalIf(err, "Cannot enable notification on the specified bucket.")
-	printMsg(eventsAddMessage{})
+	for i, arn := range arns {
+		printMsg(eventsAddMessage{ARN: arn, Change: changes[i]})
+	}
+
+	if ctx.Bool("verify") {
+		key, err := s3Client.SendTestNotification(prefix, suffix)
+		fatalIf(err, "Unable to send test events.")
+		printMsg(eventsTestMessage{Key: key, ARNs: arns})
+	}
 }
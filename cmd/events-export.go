@@ -0,0 +1,216 @@
+/*
+ * Minio Client (C) 2016 Minio, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"encoding/json"
+	"encoding/xml"
+
+	"github.com/minio/cli"
+	"github.com/minio/minio/pkg/probe"
+	"github.com/ricoharisin91/minio-go"
+)
+
+var (
+	eventsExportFlags = []cli.Flag{
+		cli.StringFlag{
+			Name:  "format",
+			Value: "json",
+			Usage: "Format of the configuration, ‘json’ or ‘xml’.",
+		},
+	}
+)
+
+var eventsExportCmd = cli.Command{
+	Name:   "export",
+	Usage:  "Export the notification configuration of a bucket.",
+	Action: mainEventsExport,
+	Flags:  append(eventsExportFlags, globalFlags...),
+	CustomHelpTemplate: `NAME:
+   mc events {{.Name}} - {{.Usage}}
+
+USAGE:
+   mc events {{.Name}} ALIAS/BUCKET [FLAGS]
+
+FLAGS:
+  {{range .Flags}}{{.}}
+  {{end}}
+DESCRIPTION:
+   The configuration is printed in the format of the S3 API, it is applied
+   again by ‘mc events import’.
+
+EXAMPLES:
+   1. Copy the notification configuration of a bucket to another bucket.
+     $ mc events {{.Name}} myminio/mybucket > notification.json
+     $ mc events import s3/mybucket notification.json
+   2. Export the notification configuration as XML.
+     $ mc events {{.Name}} myminio/mybucket --format xml
+`,
+}
+
+// checkEventsExportSyntax - validate all the passed arguments
+func checkEventsExportSyntax(ctx *cli.Context) {
+	if len(ctx.Args()) != 1 {
+		cli.ShowCommandHelpAndExit(ctx, "export", 1) // last argument is exit code
+	}
+	switch ctx.String("format") {
+	case "json", "xml":
+	default:
+		fatalIf(errInvalidArgument().Trace(ctx.String("format")), "Format ‘"+ctx.String("format")+"’ is not supported.")
+	}
+}
+
+// notificationFilterJSON - filter of the keys of a notification.
+type notificationFilterJSON struct {
+	Key struct {
+		FilterRules []minio.FilterRule `json:"FilterRules"`
+	} `json:"Key"`
+}
+
+// notificationConfigJSON - a notification of the ARN of the kind of
+// its configuration, named as in the JSON of the S3 API.
+type notificationConfigJSON struct {
+	ID                string                  `json:"Id,omitempty"`
+	QueueArn          string                  `json:"QueueArn,omitempty"`
+	TopicArn          string                  `json:"TopicArn,omitempty"`
+	LambdaFunctionArn string                  `json:"LambdaFunctionArn,omitempty"`
+	Events            []string                `json:"Events"`
+	Filter            *notificationFilterJSON `json:"Filter,omitempty"`
+}
+
+// bucketNotificationJSON - notification configuration of a bucket, named
+// as in the JSON of the S3 API.
+type bucketNotificationJSON struct {
+	QueueConfigurations          []notificationConfigJSON `json:"QueueConfigurations,omitempty"`
+	TopicConfigurations          []notificationConfigJSON `json:"TopicConfigurations,omitempty"`
+	LambdaFunctionConfigurations []notificationConfigJSON `json:"LambdaFunctionConfigurations,omitempty"`
+}
+
+// newNotificationConfigJSON - JSON of the notification without its ARN.
+func newNotificationConfigJSON(nc minio.NotificationConfig) notificationConfigJSON {
+	n := notificationConfigJSON{ID: nc.Id}
+	for _, event := range nc.Events {
+		n.Events = append(n.Events, string(event))
+	}
+	if nc.Filter != nil && len(nc.Filter.S3Key.FilterRules) > 0 {
+		n.Filter = &notificationFilterJSON{}
+		n.Filter.Key.FilterRules = nc.Filter.S3Key.FilterRules
+	}
+	return n
+}
+
+// notificationConfig - the notification of the JSON without its ARN.
+func (n notificationConfigJSON) notificationConfig() minio.NotificationConfig {
+	nc := minio.NotificationConfig{Id: n.ID}
+	for _, event := range n.Events {
+		nc.Events = append(nc.Events, minio.NotificationEventType(event))
+	}
+	if n.Filter != nil && len(n.Filter.Key.FilterRules) > 0 {
+		nc.Filter = &minio.Filter{S3Key: minio.S3Key{FilterRules: n.Filter.Key.FilterRules}}
+	}
+	return nc
+}
+
+// newBucketNotificationJSON - JSON of the notification configuration.
+func newBucketNotificationJSON(mb minio.BucketNotification) bucketNotificationJSON {
+	b := bucketNotificationJSON{}
+	for _, queue := range mb.QueueConfigs {
+		n := newNotificationConfigJSON(queue.NotificationConfig)
+		n.QueueArn = queue.Queue
+		b.QueueConfigurations = append(b.QueueConfigurations, n)
+	}
+	for _, topic := range mb.TopicConfigs {
+		n := newNotificationConfigJSON(topic.NotificationConfig)
+		n.TopicArn = topic.Topic
+		b.TopicConfigurations = append(b.TopicConfigurations, n)
+	}
+	for _, lambda := range mb.LambdaConfigs {
+		n := newNotificationConfigJSON(lambda.NotificationConfig)
+		n.LambdaFunctionArn = lambda.Lambda
+		b.LambdaFunctionConfigurations = append(b.LambdaFunctionConfigurations, n)
+	}
+	return b
+}
+
+// BucketNotification - the notification configuration of the JSON.
+func (b bucketNotificationJSON) BucketNotification() minio.BucketNotification {
+	mb := minio.BucketNotification{}
+	for _, n := range b.QueueConfigurations {
+		mb.QueueConfigs = append(mb.QueueConfigs, minio.QueueConfig{NotificationConfig: n.notificationConfig(), Queue: n.QueueArn})
+	}
+	for _, n := range b.TopicConfigurations {
+		mb.TopicConfigs = append(mb.TopicConfigs, minio.TopicConfig{NotificationConfig: n.notificationConfig(), Topic: n.TopicArn})
+	}
+	for _, n := range b.LambdaFunctionConfigurations {
+		mb.LambdaConfigs = append(mb.LambdaConfigs, minio.LambdaConfig{NotificationConfig: n.notificationConfig(), Lambda: n.LambdaFunctionArn})
+	}
+	return mb
+}
+
+// eventsExportMessage container
+type eventsExportMessage struct {
+	Status string                 `json:"status"`
+	Config bucketNotificationJSON `json:"config"`
+
+	notification minio.BucketNotification
+	format       string
+}
+
+// JSON jsonified export message.
+func (u eventsExportMessage) JSON() string {
+	u.Status = "success"
+	eventsExportMessageJSONBytes, e := json.Marshal(u)
+	fatalIf(probe.NewError(e), "Unable to marshal into JSON.")
+	return string(eventsExportMessageJSONBytes)
+}
+
+// String - the configuration, as read by ‘mc events import’.
+func (u eventsExportMessage) String() string {
+	if u.format == "xml" {
+		configXMLBytes, e := xml.MarshalIndent(u.notification, "", "  ")
+		fatalIf(probe.NewError(e), "Unable to marshal into XML.")
+		return xml.Header + string(configXMLBytes)
+	}
+	configJSONBytes, e := json.MarshalIndent(u.Config, "", "  ")
+	fatalIf(probe.NewError(e), "Unable to marshal into JSON.")
+	return string(configJSONBytes)
+}
+
+func mainEventsExport(ctx *cli.Context) {
+	setGlobalsFromContext(ctx)
+	checkEventsExportSyntax(ctx)
+
+	path := ctx.Args().First()
+	client, err := newClient(path)
+	if err != nil {
+		fatalIf(err.Trace(), "Cannot parse the provided url.")
+	}
+
+	s3Client, ok := client.(*s3Client)
+	if !ok {
+		fatalIf(errDummy().Trace(), "The provided url doesn't point to a S3 server.")
+	}
+
+	mb, err := s3Client.ExportNotificationConfig()
+	fatalIf(err, "Cannot get notification configuration of ‘"+path+"’.")
+
+	printMsg(eventsExportMessage{
+		Config:       newBucketNotificationJSON(mb),
+		notification: mb,
+		format:       ctx.String("format"),
+	})
+}
@@ -0,0 +1,172 @@
+/*
+ * Minio Client (C) 2016 Minio, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"bytes"
+	"encoding/json"
+	"encoding/xml"
+	"fmt"
+	"io/ioutil"
+	"os"
+
+	"github.com/fatih/color"
+	"github.com/minio/cli"
+	"github.com/minio/mc/pkg/console"
+	"github.com/minio/minio/pkg/probe"
+	"github.com/ricoharisin91/minio-go"
+)
+
+var (
+	eventsImportFlags = []cli.Flag{}
+)
+
+var eventsImportCmd = cli.Command{
+	Name:   "import",
+	Usage:  "Replace the notification configuration of a bucket.",
+	Action: mainEventsImport,
+	Flags:  append(eventsImportFlags, globalFlags...),
+	CustomHelpTemplate: `NAME:
+   mc events {{.Name}} - {{.Usage}}
+
+USAGE:
+   mc events {{.Name}} ALIAS/BUCKET [FILE] [FLAGS]
+
+FLAGS:
+  {{range .Flags}}{{.}}
+  {{end}}
+DESCRIPTION:
+   FILE holds a configuration in JSON or XML, as exported by ‘mc events export’.
+   The configuration is read from standard input without FILE. Notifications
+   of the bucket missing in the configuration are removed.
+
+EXAMPLES:
+   1. Apply a versioned notification configuration to a bucket.
+     $ mc events {{.Name}} s3/mybucket notification.json
+   2. Copy the notification configuration of a bucket to another bucket.
+     $ mc events export myminio/mybucket | mc events {{.Name}} s3/mybucket
+`,
+}
+
+// checkEventsImportSyntax - validate all the passed arguments
+func checkEventsImportSyntax(ctx *cli.Context) {
+	if len(ctx.Args()) != 1 && len(ctx.Args()) != 2 {
+		cli.ShowCommandHelpAndExit(ctx, "import", 1) // last argument is exit code
+	}
+}
+
+// eventsImportMessage container
+type eventsImportMessage struct {
+	Status  string `json:"status"`
+	Bucket  string `json:"bucket"`
+	Configs int    `json:"configs"`
+}
+
+// JSON jsonified import message.
+func (u eventsImportMessage) JSON() string {
+	u.Status = "success"
+	eventsImportMessageJSONBytes, e := json.Marshal(u)
+	fatalIf(probe.NewError(e), "Unable to marshal into JSON.")
+	return string(eventsImportMessageJSONBytes)
+}
+
+func (u eventsImportMessage) String() string {
+	return console.Colorize("Events", fmt.Sprintf("Imported %d notification configurations to ‘%s’.", u.Configs, u.Bucket))
+}
+
+// parseBucketNotification - parses a notification configuration in JSON
+// or in XML.
+func parseBucketNotification(data []byte) (minio.BucketNotification, *probe.Error) {
+	mb := minio.BucketNotification{}
+	data = bytes.TrimSpace(data)
+	if bytes.HasPrefix(data, []byte("<")) {
+		if e := xml.Unmarshal(data, &mb); e != nil {
+			return minio.BucketNotification{}, probe.NewError(e)
+		}
+	} else {
+		b := bucketNotificationJSON{}
+		if e := json.Unmarshal(data, &b); e != nil {
+			return minio.BucketNotification{}, probe.NewError(e)
+		}
+		mb = b.BucketNotification()
+	}
+
+	var configs []minio.NotificationConfig
+	var arns []string
+	for _, queue := range mb.QueueConfigs {
+		configs, arns = append(configs, queue.NotificationConfig), append(arns, queue.Queue)
+	}
+	for _, topic := range mb.TopicConfigs {
+		configs, arns = append(configs, topic.NotificationConfig), append(arns, topic.Topic)
+	}
+	for _, lambda := range mb.LambdaConfigs {
+		configs, arns = append(configs, lambda.NotificationConfig), append(arns, lambda.Lambda)
+	}
+	for i, nc := range configs {
+		if _, err := parseNotificationArn(arns[i]); err != nil {
+			return minio.BucketNotification{}, err.Trace(arns[i])
+		}
+		if len(nc.Events) == 0 {
+			return minio.BucketNotification{}, errInvalidNotificationConfig("notification of ‘" + arns[i] + "’ has no events").Trace(arns[i])
+		}
+	}
+	return mb, nil
+}
+
+// notificationConfigsCount - number of notifications of a configuration.
+func notificationConfigsCount(mb minio.BucketNotification) int {
+	return len(mb.QueueConfigs) + len(mb.TopicConfigs) + len(mb.LambdaConfigs)
+}
+
+func mainEventsImport(ctx *cli.Context) {
+	console.SetColor("Events", color.New(color.FgGreen, color.Bold))
+
+	setGlobalsFromContext(ctx)
+	checkEventsImportSyntax(ctx)
+
+	args := ctx.Args()
+	path := args[0]
+	file := "stdin"
+
+	var data []byte
+	var e error
+	if len(args) > 1 {
+		file = args[1]
+		data, e = ioutil.ReadFile(file)
+	} else {
+		data, e = ioutil.ReadAll(os.Stdin)
+	}
+	fatalIf(probe.NewError(e).Trace(file), "Unable to read notification configuration.")
+
+	mb, err := parseBucketNotification(data)
+	fatalIf(err.Trace(file), "Unable to parse notification configuration.")
+
+	client, err := newClient(path)
+	if err != nil {
+		fatalIf(err.Trace(), "Cannot parse the provided url.")
+	}
+
+	s3Client, ok := client.(*s3Client)
+	if !ok {
+		fatalIf(errDummy().Trace(), "The provided url doesn't point to a S3 server.")
+	}
+
+	err = s3Client.ImportNotificationConfig(mb)
+	fatalIf(err, "Cannot set notification configuration of ‘"+path+"’.")
+
+	printMsg(eventsImportMessage{Bucket: path, Configs: notificationConfigsCount(mb)})
+}
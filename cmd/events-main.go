@@ -36,6 +36,9 @@ var eventsCmd = cli.Command{
 		eventsAddCmd,
 		eventsRemoveCmd,
 		eventsListCmd,
+		eventsReplayCmd,
+		eventsExportCmd,
+		eventsImportCmd,
 	},
 	CustomHelpTemplate: `NAME:
    {{.Name}} - {{.Usage}}
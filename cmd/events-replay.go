@@ -0,0 +1,193 @@
+/*
+ * Minio Client (C) 2016 Minio, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"encoding/json"
+	"io"
+	"time"
+
+	"github.com/fatih/color"
+	"github.com/minio/cli"
+	"github.com/minio/mc/pkg/console"
+	"github.com/minio/minio/pkg/probe"
+)
+
+var (
+	eventsReplayFlags = []cli.Flag{
+		cli.StringFlag{
+			Name:  "since",
+			Value: "24h",
+			Usage: "Replay events received within the age, e.g. ‘2h’ or ‘7d’.",
+		},
+		cli.StringFlag{
+			Name:  "target",
+			Usage: "Folder the events are replayed to.",
+		},
+	}
+)
+
+var eventsReplayCmd = cli.Command{
+	Name:   "replay",
+	Usage:  "Replay events recorded by ‘mc watch --journal’ to another folder.",
+	Action: mainEventsReplay,
+	Flags:  append(eventsReplayFlags, globalFlags...),
+	CustomHelpTemplate: `NAME:
+   mc events {{.Name}} - {{.Usage}}
+
+USAGE:
+   mc events {{.Name}} ALIAS/BUCKET --target TARGET [FLAGS]
+
+FLAGS:
+  {{range .Flags}}{{.}}
+  {{end}}
+DESCRIPTION:
+   Objects created in the watched folder are copied from it as they are now,
+   removed objects are removed from the target. Only the last event of each
+   object is replayed.
+
+EXAMPLES:
+   1. Catch up a bucket with the events of the last 2 hours of another bucket.
+     $ mc events {{.Name}} myminio/mybucket --since 2h --target s3/mybucket
+`,
+}
+
+// checkEventsReplaySyntax - validate all the passed arguments
+func checkEventsReplaySyntax(ctx *cli.Context) {
+	if len(ctx.Args()) != 1 || ctx.String("target") == "" {
+		cli.ShowCommandHelpAndExit(ctx, "replay", 1) // last argument is exit code
+	}
+	if _, err := parseFilterAge(ctx.String("since")); err != nil {
+		fatalIf(err.Trace(), "Unable to parse age of events.")
+	}
+}
+
+// eventsReplayMessage container
+type eventsReplayMessage struct {
+	Status string    `json:"status"`
+	Type   EventType `json:"type"`
+	Source string    `json:"source,omitempty"`
+	Target string    `json:"target"`
+}
+
+// JSON jsonified replay message.
+func (u eventsReplayMessage) JSON() string {
+	u.Status = "success"
+	eventsReplayMessageJSONBytes, e := json.Marshal(u)
+	fatalIf(probe.NewError(e), "Unable to marshal into JSON.")
+	return string(eventsReplayMessageJSONBytes)
+}
+
+func (u eventsReplayMessage) String() string {
+	if u.Type == EventRemove {
+		return console.Colorize("Events", "Removed ‘"+u.Target+"’.")
+	}
+	return console.Colorize("Events", "Copied ‘"+u.Source+"’ to ‘"+u.Target+"’.")
+}
+
+// compactEventJournal - the last event of each object, in the order of
+// these events.
+func compactEventJournal(entries []eventJournalEntry) []eventJournalEntry {
+	last := make(map[string]int, len(entries))
+	for i, entry := range entries {
+		last[entry.Key] = i
+	}
+	var compacted []eventJournalEntry
+	for i, entry := range entries {
+		if last[entry.Key] == i {
+			compacted = append(compacted, entry)
+		}
+	}
+	return compacted
+}
+
+// isContentMissing - reports whether the error is of an object or file
+// which does not exist.
+func isContentMissing(err *probe.Error) bool {
+	switch err.ToGoError().(type) {
+	case ObjectMissing, PathNotFound:
+		return true
+	}
+	return false
+}
+
+// replayEvent - applies the event of the object of the source client to
+// the object of the target client. Objects created but missing by now
+// were removed later.
+func replayEvent(entry eventJournalEntry, sourceClnt, targetClnt Client) (*eventsReplayMessage, *probe.Error) {
+	source := sourceClnt.GetURL().String()
+	target := targetClnt.GetURL().String()
+	switch entry.Type {
+	case EventCreate:
+		content, err := sourceClnt.Stat()
+		if err != nil {
+			if isContentMissing(err) {
+				return nil, nil
+			}
+			return nil, err.Trace(source)
+		}
+		reader, err := sourceClnt.Get(globalContext, GetOpts{})
+		if err != nil {
+			return nil, err.Trace(source)
+		}
+		if closer, ok := reader.(io.Closer); ok {
+			defer closer.Close()
+		}
+		if _, err = targetClnt.Put(globalContext, reader, content.Size, guessURLContentType(target), nil, PutOpts{}); err != nil {
+			return nil, err.Trace(target)
+		}
+		return &eventsReplayMessage{Type: entry.Type, Source: source, Target: target}, nil
+	case EventRemove:
+		if err := targetClnt.Remove(globalContext, false); err != nil && !isContentMissing(err) {
+			return nil, err.Trace(target)
+		}
+		return &eventsReplayMessage{Type: entry.Type, Target: target}, nil
+	}
+	// Other events do not change objects.
+	return nil, nil
+}
+
+func mainEventsReplay(ctx *cli.Context) {
+	console.SetColor("Events", color.New(color.FgGreen, color.Bold))
+
+	setGlobalsFromContext(ctx)
+	checkEventsReplaySyntax(ctx)
+
+	age, err := parseFilterAge(ctx.String("since"))
+	fatalIf(err, "Unable to parse age of events.")
+
+	sourceAlias, sourceURL, _ := mustExpandAlias(ctx.Args().First())
+	targetAlias, targetURL, _ := mustExpandAlias(ctx.String("target"))
+
+	entries, err := readEventJournal(sourceAlias, sourceURL, time.Now().UTC().Add(-age))
+	fatalIf(err, "Unable to read events of ‘"+ctx.Args().First()+"’.")
+
+	for _, entry := range compactEventJournal(entries) {
+		sourceClnt, err := newClientFromAlias(sourceAlias, urlJoinPath(sourceURL, entry.Key))
+		fatalIf(err, "Unable to initialize source ‘"+ctx.Args().First()+"’.")
+		targetClnt, err := newClientFromAlias(targetAlias, urlJoinPath(targetURL, entry.Key))
+		fatalIf(err, "Unable to initialize target ‘"+ctx.String("target")+"’.")
+		msg, err := replayEvent(entry, sourceClnt, targetClnt)
+		if err != nil {
+			errorIf(err, "Unable to replay event of ‘"+entry.Key+"’.")
+			continue
+		}
+		if msg != nil {
+			printMsg(*msg)
+		}
+	}
+}
@@ -0,0 +1,174 @@
+/*
+ * Minio Client (C) 2016 Minio, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"regexp"
+	"strings"
+	"sync"
+
+	"github.com/minio/cli"
+	"github.com/minio/minio/pkg/probe"
+)
+
+// Flags of running commands for events.
+var execHookFlags = []cli.Flag{
+	cli.StringFlag{
+		Name:  "exec",
+		Usage: "Run the command for every event, ‘{event}’, ‘{name}’, ‘{url}’, ‘{bucket}’, ‘{key}’, ‘{size}’, ‘{etag}’ and ‘{time}’ in it are replaced by details of the event.",
+	},
+	cli.IntFlag{
+		Name:  "exec-concurrency",
+		Value: 4,
+		Usage: "Number of commands running at once, watching waits while all of them run.",
+	},
+}
+
+// Placeholders of details of events in commands.
+var execHookPlaceholder = regexp.MustCompile(`\{[a-z]+\}`)
+
+// execHookValues - details of the event by placeholder.
+func execHookValues(event Event) map[string]string {
+	return map[string]string{
+		"{event}":  string(event.Type),
+		"{name}":   event.Name,
+		"{url}":    event.Path,
+		"{bucket}": event.Bucket,
+		"{key}":    event.Key,
+		"{size}":   fmt.Sprintf("%d", event.Size),
+		"{etag}":   event.ETag,
+		"{time}":   event.Time,
+	}
+}
+
+// execHook - runs a command for every event, the command is not run by
+// a shell. A nil hook runs nothing.
+type execHook struct {
+	args []string
+	// Holds a token for every running command.
+	running chan struct{}
+	wg      sync.WaitGroup
+}
+
+// newExecHook - hook of the command, nil if there is no command.
+func newExecHook(command string, concurrency int) (*execHook, *probe.Error) {
+	if command == "" {
+		return nil, nil
+	}
+	args, ok := splitExecCommand(command)
+	if !ok || len(args) == 0 {
+		return nil, errInvalidExecCommand(command, "arguments are not quoted properly").Trace(command)
+	}
+	values := execHookValues(Event{})
+	for _, arg := range args {
+		for _, placeholder := range execHookPlaceholder.FindAllString(arg, -1) {
+			if _, ok := values[placeholder]; !ok {
+				return nil, errInvalidExecCommand(command, "‘"+placeholder+"’ is not known").Trace(command)
+			}
+		}
+	}
+	if concurrency < 1 {
+		return nil, errInvalidArgument().Trace(fmt.Sprintf("%d", concurrency))
+	}
+	return &execHook{args: args, running: make(chan struct{}, concurrency)}, nil
+}
+
+// splitExecCommand - splits the command into arguments separated by
+// spaces, unless they are in single or double quotes.
+func splitExecCommand(command string) ([]string, bool) {
+	var args []string
+	var arg []rune
+	var quote rune
+	inArg := false
+	for _, r := range command {
+		switch {
+		case quote != 0:
+			if r == quote {
+				quote = 0
+			} else {
+				arg = append(arg, r)
+			}
+		case r == '\'' || r == '"':
+			quote = r
+			inArg = true
+		case r == ' ' || r == '\t' || r == '\n':
+			if inArg {
+				args = append(args, string(arg))
+				arg, inArg = nil, false
+			}
+		default:
+			arg = append(arg, r)
+			inArg = true
+		}
+	}
+	if quote != 0 {
+		return nil, false
+	}
+	if inArg {
+		args = append(args, string(arg))
+	}
+	return args, true
+}
+
+// Args - arguments of the command of the event.
+func (h *execHook) Args(event Event) []string {
+	values := execHookValues(event)
+	args := make([]string, len(h.args))
+	for i, arg := range h.args {
+		args[i] = execHookPlaceholder.ReplaceAllStringFunc(arg, func(placeholder string) string {
+			return values[placeholder]
+		})
+	}
+	return args
+}
+
+// Run - starts the command of the event once fewer commands than the
+// concurrency run. Commands are killed once interrupted.
+func (h *execHook) Run(event Event) {
+	if h == nil {
+		return
+	}
+	select {
+	case h.running <- struct{}{}:
+	case <-globalContext.Done():
+		return
+	}
+	h.wg.Add(1)
+	go func() {
+		defer h.wg.Done()
+		defer func() { <-h.running }()
+		args := h.Args(event)
+		cmd := exec.CommandContext(globalContext, args[0], args[1:]...)
+		cmd.Stdout = os.Stdout
+		cmd.Stderr = os.Stderr
+		if e := cmd.Run(); e != nil && globalContext.Err() == nil {
+			globalMetrics.Add(metricErrors, 1)
+			errorIf(probe.NewError(e).Trace(strings.Join(args, " ")), "Unable to run command for event of ‘"+event.Path+"’.")
+		}
+	}()
+}
+
+// Close - waits until running commands exit.
+func (h *execHook) Close() {
+	if h == nil {
+		return
+	}
+	h.wg.Wait()
+}
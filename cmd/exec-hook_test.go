@@ -0,0 +1,66 @@
+/*
+ * Minio Client (C) 2016 Minio, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+
+	. "gopkg.in/check.v1"
+)
+
+// Test running commands for events.
+func (s *TestSuite) TestExecHook(c *C) {
+	for _, command := range []string{"handler.sh {path}", "handler.sh 'unquoted", "  "} {
+		_, err := newExecHook(command, 1)
+		c.Assert(err, NotNil)
+	}
+	_, err := newExecHook("handler.sh {url}", 0)
+	c.Assert(err, NotNil)
+	hook, err := newExecHook("", 1)
+	c.Assert(err, IsNil)
+	c.Assert(hook, IsNil)
+	hook.Run(Event{})
+	hook.Close()
+
+	hook, err = newExecHook(`handler.sh "{event} of {key}" '{size}'B {etag}`, 1)
+	c.Assert(err, IsNil)
+	c.Assert(hook.Args(Event{Type: EventCreate, Key: "a b.txt", Size: 3}), DeepEquals,
+		[]string{"handler.sh", "ObjectCreated of a b.txt", "3B", ""})
+
+	dir, e := ioutil.TempDir("", "mc-exec-")
+	c.Assert(e, IsNil)
+	defer os.RemoveAll(dir)
+
+	hook, err = newExecHook("touch "+filepath.Join(dir, "{key}.{event}.{size}"), 2)
+	c.Assert(err, IsNil)
+	events := []Event{
+		{Type: EventCreate, Key: "a", Size: 1},
+		{Type: EventCreate, Key: "b", Size: 2},
+		{Type: EventRemove, Key: "a"},
+	}
+	for _, event := range events {
+		hook.Run(event)
+	}
+	hook.Close()
+
+	for _, name := range []string{"a.ObjectCreated.1", "b.ObjectCreated.2", "a.ObjectRemoved.0"} {
+		_, e = os.Stat(filepath.Join(dir, name))
+		c.Assert(e, IsNil)
+	}
+}
@@ -0,0 +1,125 @@
+/*
+ * Minio Client (C) 2016 Minio, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"os"
+	"path/filepath"
+	"strconv"
+	"time"
+
+	"github.com/minio/minio/pkg/probe"
+)
+
+// Metadata of objects uploaded with ‘--preserve’, holds the attributes
+// of the file.
+const (
+	fileModeMetadataKey  = "X-Amz-Meta-Mc-Mode"
+	fileUIDMetadataKey   = "X-Amz-Meta-Mc-Uid"
+	fileGIDMetadataKey   = "X-Amz-Meta-Mc-Gid"
+	fileMtimeMetadataKey = "X-Amz-Meta-Mc-Mtime"
+)
+
+// fileAttrsMetadata - mode, owner and modification time of a file as
+// metadata. The mode is octal, the modification time in seconds since
+// the epoch.
+func fileAttrsMetadata(fpath string) (map[string]string, *probe.Error) {
+	st, e := os.Stat(fpath)
+	if e != nil {
+		return nil, probe.NewError(e).Trace(fpath)
+	}
+	metadata := map[string]string{
+		fileModeMetadataKey:  strconv.FormatUint(uint64(st.Mode().Perm()), 8),
+		fileMtimeMetadataKey: strconv.FormatInt(st.ModTime().Unix(), 10),
+	}
+	if uid, gid, ok := fileOwner(st); ok {
+		metadata[fileUIDMetadataKey] = strconv.Itoa(uid)
+		metadata[fileGIDMetadataKey] = strconv.Itoa(gid)
+	}
+	return metadata, nil
+}
+
+// restoreFileAttrs - applies attributes stored as metadata to a file,
+// attributes missing from the metadata are left alone. Files are only
+// given away to their owner where permitted.
+func restoreFileAttrs(fpath string, metadata map[string]string) *probe.Error {
+	uid, uidOK := metadata[fileUIDMetadataKey]
+	gid, gidOK := metadata[fileGIDMetadataKey]
+	if uidOK && gidOK {
+		u, e := strconv.Atoi(uid)
+		if e != nil {
+			return probe.NewError(e).Trace(fpath, uid)
+		}
+		g, e := strconv.Atoi(gid)
+		if e != nil {
+			return probe.NewError(e).Trace(fpath, gid)
+		}
+		if err := chownFile(fpath, u, g); err != nil {
+			return err.Trace(fpath)
+		}
+	}
+	// Changing the owner may clear setuid bits, the mode follows.
+	if mode, ok := metadata[fileModeMetadataKey]; ok {
+		m, e := strconv.ParseUint(mode, 8, 32)
+		if e != nil {
+			return probe.NewError(e).Trace(fpath, mode)
+		}
+		if e = os.Chmod(fpath, os.FileMode(m)&os.ModePerm); e != nil {
+			return probe.NewError(e).Trace(fpath)
+		}
+	}
+	if mtime, ok := metadata[fileMtimeMetadataKey]; ok {
+		sec, e := strconv.ParseInt(mtime, 10, 64)
+		if e != nil {
+			return probe.NewError(e).Trace(fpath, mtime)
+		}
+		t := time.Unix(sec, 0)
+		if e = os.Chtimes(fpath, t, t); e != nil {
+			return probe.NewError(e).Trace(fpath)
+		}
+	}
+	return nil
+}
+
+// sourceFileAttrs - attributes of a file to be uploaded with
+// ‘--preserve’, nil for objects.
+func sourceFileAttrs(sourceAlias string, sourceURL ClientURL) (map[string]string, *probe.Error) {
+	if sourceURL.Type != FileSystem {
+		return nil, nil
+	}
+	return fileAttrsMetadata(filepath.Join(sourceAlias, sourceURL.Path))
+}
+
+// restoreTargetAttrs - applies the attributes of the source of a copy
+// with ‘--preserve’ to the file copied to, either those of the source
+// file or those stored as metadata of the source object.
+func restoreTargetAttrs(sourceAlias string, sourceURL ClientURL, targetURL ClientURL, sse EncryptOpts) *probe.Error {
+	if targetURL.Type != FileSystem {
+		return nil
+	}
+	metadata, err := sourceFileAttrs(sourceAlias, sourceURL)
+	if err != nil {
+		return err.Trace(sourceURL.String())
+	}
+	if sourceURL.Type == ObjectStorage {
+		metadata, err = getSourceMetadataFromAlias(sourceAlias, sourceURL.String(), sse)
+		if err != nil {
+			return err.Trace(sourceURL.String())
+		}
+	}
+	return restoreFileAttrs(targetURL.Path, metadata)
+}
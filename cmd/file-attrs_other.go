@@ -0,0 +1,44 @@
+// +build !windows
+
+/*
+ * Minio Client (C) 2016 Minio, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"os"
+	"syscall"
+
+	"github.com/minio/minio/pkg/probe"
+)
+
+// fileOwner - user and group owning a file.
+func fileOwner(st os.FileInfo) (uid, gid int, ok bool) {
+	stat, ok := st.Sys().(*syscall.Stat_t)
+	if !ok {
+		return 0, 0, false
+	}
+	return int(stat.Uid), int(stat.Gid), true
+}
+
+// chownFile - changes the owner of a file, owners may only be changed
+// by privileged users, other users keep owning the file.
+func chownFile(fpath string, uid, gid int) *probe.Error {
+	if e := os.Chown(fpath, uid, gid); e != nil && !os.IsPermission(e) {
+		return probe.NewError(e).Trace(fpath)
+	}
+	return nil
+}
@@ -0,0 +1,66 @@
+/*
+ * Minio Client (C) 2016 Minio, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strconv"
+	"time"
+
+	. "gopkg.in/check.v1"
+)
+
+func (s *TestSuite) TestFileAttrs(c *C) {
+	if runtime.GOOS == "windows" {
+		c.Skip("Modes of files are not kept on windows.")
+	}
+	root, e := ioutil.TempDir(os.TempDir(), "attrs-")
+	c.Assert(e, IsNil)
+	defer os.RemoveAll(root)
+
+	source := filepath.Join(root, "source")
+	target := filepath.Join(root, "target")
+	c.Assert(ioutil.WriteFile(source, []byte("hello"), 0600), IsNil)
+	c.Assert(ioutil.WriteFile(target, []byte("hello"), 0600), IsNil)
+	c.Assert(os.Chmod(source, 0751), IsNil)
+	mtime := time.Date(2016, 7, 1, 10, 30, 0, 0, time.UTC)
+	c.Assert(os.Chtimes(source, mtime, mtime), IsNil)
+
+	metadata, err := fileAttrsMetadata(source)
+	c.Assert(err, IsNil)
+	c.Assert(metadata[fileModeMetadataKey], Equals, "751")
+	c.Assert(metadata[fileMtimeMetadataKey], Equals, "1467369000")
+	c.Assert(metadata[fileUIDMetadataKey], Equals, strconv.Itoa(os.Getuid()))
+
+	// Owners are restored where permitted, the current user always is.
+	c.Assert(restoreFileAttrs(target, metadata), IsNil)
+	st, e := os.Stat(target)
+	c.Assert(e, IsNil)
+	c.Assert(st.Mode().Perm(), Equals, os.FileMode(0751))
+	c.Assert(st.ModTime().Equal(mtime), Equals, true)
+
+	// Missing attributes are left alone, invalid ones are reported.
+	c.Assert(restoreFileAttrs(target, map[string]string{fileModeMetadataKey: "640"}), IsNil)
+	st, e = os.Stat(target)
+	c.Assert(e, IsNil)
+	c.Assert(st.Mode().Perm(), Equals, os.FileMode(0640))
+	c.Assert(st.ModTime().Equal(mtime), Equals, true)
+	c.Assert(restoreFileAttrs(target, map[string]string{fileModeMetadataKey: "rwx"}), NotNil)
+}
@@ -0,0 +1,35 @@
+// +build windows
+
+/*
+ * Minio Client (C) 2016 Minio, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"os"
+
+	"github.com/minio/minio/pkg/probe"
+)
+
+// fileOwner - owners of files are not numeric on windows.
+func fileOwner(st os.FileInfo) (uid, gid int, ok bool) {
+	return 0, 0, false
+}
+
+// chownFile - owners of files are not restored on windows.
+func chownFile(fpath string, uid, gid int) *probe.Error {
+	return nil
+}
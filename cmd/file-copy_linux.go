@@ -0,0 +1,63 @@
+// +build linux
+
+/*
+ * Minio Client (C) 2016 Minio, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"os"
+	"syscall"
+)
+
+// ioctl request cloning a file on filesystems with reflinks, like
+// btrfs and xfs.
+const ficlone = 0x40049409
+
+// Maximum number of bytes copied by a single sendfile call.
+const maxSendfileChunk = 1 << 30
+
+// cloneFile - makes the target share the data of the source without
+// copying it, fails on filesystems without reflinks and across them.
+func cloneFile(target, source *os.File) error {
+	_, _, errno := syscall.Syscall(syscall.SYS_IOCTL, target.Fd(), ficlone, source.Fd())
+	if errno != 0 {
+		return errno
+	}
+	return nil
+}
+
+// sendFile - copies the source to the target within the kernel, until
+// the end of the source. Progress is called with every copied chunk.
+func sendFile(target, source *os.File, progress func(n int64) error) (int64, error) {
+	var written int64
+	for {
+		n, e := syscall.Sendfile(int(target.Fd()), int(source.Fd()), nil, maxSendfileChunk)
+		if e == syscall.EINTR || e == syscall.EAGAIN {
+			continue
+		}
+		if e != nil {
+			return written, e
+		}
+		if n == 0 {
+			return written, nil
+		}
+		written += int64(n)
+		if e = progress(int64(n)); e != nil {
+			return written, e
+		}
+	}
+}
@@ -0,0 +1,38 @@
+// +build !linux
+
+/*
+ * Minio Client (C) 2016 Minio, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"errors"
+	"os"
+)
+
+// errKernelCopyUnsupported - files are only copied within the kernel
+// on linux.
+var errKernelCopyUnsupported = errors.New("Copying files within the kernel is not supported.")
+
+// cloneFile - files are not cloned on this platform.
+func cloneFile(target, source *os.File) error {
+	return errKernelCopyUnsupported
+}
+
+// sendFile - files are not copied within the kernel on this platform.
+func sendFile(target, source *os.File, progress func(n int64) error) (int64, error) {
+	return 0, errKernelCopyUnsupported
+}
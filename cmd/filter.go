@@ -0,0 +1,352 @@
+/*
+ * Minio Client (C) 2016 Minio, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"path"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/dustin/go-humanize"
+	"github.com/minio/cli"
+	"github.com/minio/minio/pkg/probe"
+)
+
+// filterPattern - a gitignore style pattern. Patterns without a
+// separator match the name of a file or of any of its parent folders,
+// others match the path relative to the listed folder. A trailing
+// separator only matches folders, ‘**’ matches any number of folders.
+type filterPattern struct {
+	segments []string
+	anchored bool
+	dirOnly  bool
+}
+
+// parseFilterPattern - parses and validates a pattern.
+func parseFilterPattern(pattern string) (filterPattern, *probe.Error) {
+	p := filterPattern{}
+	s := filepath.ToSlash(pattern)
+	if strings.HasSuffix(s, "/") {
+		p.dirOnly = true
+		s = strings.TrimSuffix(s, "/")
+	}
+	p.anchored = strings.Contains(s, "/")
+	s = strings.TrimPrefix(s, "/")
+	if s == "" {
+		return filterPattern{}, errInvalidFilterPattern(pattern).Trace(pattern)
+	}
+	p.segments = strings.Split(s, "/")
+	for _, segment := range p.segments {
+		if _, e := path.Match(segment, ""); e != nil {
+			return filterPattern{}, errInvalidFilterPattern(pattern).Trace(pattern)
+		}
+	}
+	return p, nil
+}
+
+// match - reports whether the pattern matches the path given as its
+// segments, or any of its parent folders.
+func (p filterPattern) match(segments []string, isDir bool) bool {
+	// Number of leading segments naming folders.
+	dirs := len(segments)
+	if !isDir {
+		dirs--
+	}
+	if !p.anchored {
+		for i, segment := range segments {
+			if p.dirOnly && i >= dirs {
+				continue
+			}
+			if ok, _ := path.Match(p.segments[0], segment); ok {
+				return true
+			}
+		}
+		return false
+	}
+	for n := 1; n <= len(segments); n++ {
+		if p.dirOnly && n > dirs {
+			continue
+		}
+		if matchSegments(p.segments, segments[:n]) {
+			return true
+		}
+	}
+	return false
+}
+
+// matchSegments - matches path segments against pattern segments.
+func matchSegments(pattern, segments []string) bool {
+	if len(pattern) == 0 {
+		return len(segments) == 0
+	}
+	if pattern[0] == "**" {
+		for i := 0; i <= len(segments); i++ {
+			if matchSegments(pattern[1:], segments[i:]) {
+				return true
+			}
+		}
+		return false
+	}
+	if len(segments) == 0 {
+		return false
+	}
+	if ok, _ := path.Match(pattern[0], segments[0]); !ok {
+		return false
+	}
+	return matchSegments(pattern[1:], segments[1:])
+}
+
+// filterOpts - options of a content filter as given on the command
+// line, zero value filters nothing.
+type filterOpts struct {
+	Include []string
+	Exclude []string
+	// Ages like ‘12h’ or ‘7d’, relative to the time of filtering.
+	NewerThan string
+	OlderThan string
+	// Sizes like ‘10MiB’.
+	LargerThan  string
+	SmallerThan string
+}
+
+// filterOptsFromContext - filter options of the command line flags.
+func filterOptsFromContext(ctx *cli.Context) filterOpts {
+	return filterOpts{
+		Include:     ctx.StringSlice("include"),
+		Exclude:     ctx.StringSlice("exclude"),
+		NewerThan:   ctx.String("newer-than"),
+		OlderThan:   ctx.String("older-than"),
+		LargerThan:  ctx.String("larger-than"),
+		SmallerThan: ctx.String("smaller-than"),
+	}
+}
+
+// filterOptsFromSession - filter options stored in a session.
+func filterOptsFromSession(session *sessionV8) filterOpts {
+	flags := session.Header.CommandStringFlags
+	return filterOpts{
+		Include:     parseFilterPatternsFlag(flags["include"]),
+		Exclude:     parseFilterPatternsFlag(flags["exclude"]),
+		NewerThan:   flags["newer-than"],
+		OlderThan:   flags["older-than"],
+		LargerThan:  flags["larger-than"],
+		SmallerThan: flags["smaller-than"],
+	}
+}
+
+// setSessionFilterOpts - stores filter options in a session, so that
+// resumed sessions filter alike.
+func setSessionFilterOpts(session *sessionV8, opts filterOpts) {
+	flags := session.Header.CommandStringFlags
+	flags["include"] = filterPatternsFlag(opts.Include)
+	flags["exclude"] = filterPatternsFlag(opts.Exclude)
+	flags["newer-than"] = opts.NewerThan
+	flags["older-than"] = opts.OlderThan
+	flags["larger-than"] = opts.LargerThan
+	flags["smaller-than"] = opts.SmallerThan
+}
+
+// contentFilter - skips contents matching any exclude pattern, with
+// include patterns only files matching one of them are kept. Folders
+// are only excluded, whether they hold included files is not known
+// until they are listed. Files are also filtered by age and size.
+// A nil filter keeps all contents.
+type contentFilter struct {
+	include []filterPattern
+	exclude []filterPattern
+
+	// Bounds of the modification time, zero if unset.
+	newerThan time.Time
+	olderThan time.Time
+	// Bounds of the size, negative if unset.
+	largerThan  int64
+	smallerThan int64
+}
+
+// newContentFilter - filter of the given options, nil if there is
+// nothing to filter.
+func newContentFilter(opts filterOpts) (*contentFilter, *probe.Error) {
+	if len(opts.Include) == 0 && len(opts.Exclude) == 0 &&
+		opts.NewerThan == "" && opts.OlderThan == "" &&
+		opts.LargerThan == "" && opts.SmallerThan == "" {
+		return nil, nil
+	}
+	f := &contentFilter{largerThan: -1, smallerThan: -1}
+	for _, pattern := range opts.Include {
+		p, err := parseFilterPattern(pattern)
+		if err != nil {
+			return nil, err.Trace(pattern)
+		}
+		f.include = append(f.include, p)
+	}
+	for _, pattern := range opts.Exclude {
+		p, err := parseFilterPattern(pattern)
+		if err != nil {
+			return nil, err.Trace(pattern)
+		}
+		f.exclude = append(f.exclude, p)
+	}
+
+	now := time.Now().UTC()
+	if opts.NewerThan != "" {
+		age, err := parseFilterAge(opts.NewerThan)
+		if err != nil {
+			return nil, err.Trace(opts.NewerThan)
+		}
+		f.newerThan = now.Add(-age)
+	}
+	if opts.OlderThan != "" {
+		age, err := parseFilterAge(opts.OlderThan)
+		if err != nil {
+			return nil, err.Trace(opts.OlderThan)
+		}
+		f.olderThan = now.Add(-age)
+	}
+	if opts.LargerThan != "" {
+		size, e := humanize.ParseBytes(opts.LargerThan)
+		if e != nil {
+			return nil, errInvalidFilterSize(opts.LargerThan).Trace(opts.LargerThan)
+		}
+		f.largerThan = int64(size)
+	}
+	if opts.SmallerThan != "" {
+		size, e := humanize.ParseBytes(opts.SmallerThan)
+		if e != nil {
+			return nil, errInvalidFilterSize(opts.SmallerThan).Trace(opts.SmallerThan)
+		}
+		f.smallerThan = int64(size)
+	}
+	return f, nil
+}
+
+// parseFilterAge - parses ages like ‘90m’, ‘12h’, ‘7d’ or ‘1d12h’.
+func parseFilterAge(age string) (time.Duration, *probe.Error) {
+	if age == "" {
+		return 0, errInvalidFilterAge(age).Trace(age)
+	}
+	var days time.Duration
+	rest := age
+	if i := strings.Index(age, "d"); i >= 0 {
+		n, e := strconv.Atoi(age[:i])
+		if e != nil || n < 0 {
+			return 0, errInvalidFilterAge(age).Trace(age)
+		}
+		days = time.Duration(n) * 24 * time.Hour
+		rest = age[i+1:]
+	}
+	if rest == "" {
+		return days, nil
+	}
+	d, e := time.ParseDuration(rest)
+	if e != nil || d < 0 {
+		return 0, errInvalidFilterAge(age).Trace(age)
+	}
+	return days + d, nil
+}
+
+// Matches - reports whether the content at the path relative to the
+// listed folder is kept by the patterns.
+func (f *contentFilter) Matches(relPath string, isDir bool) bool {
+	if f == nil {
+		return true
+	}
+	segments := strings.Split(strings.Trim(filepath.ToSlash(relPath), "/"), "/")
+	for _, p := range f.exclude {
+		if p.match(segments, isDir) {
+			return false
+		}
+	}
+	if len(f.include) == 0 || isDir {
+		return true
+	}
+	for _, p := range f.include {
+		if p.match(segments, isDir) {
+			return true
+		}
+	}
+	return false
+}
+
+// MatchesContent - reports whether the content at the path relative to
+// the listed folder is kept, files of unknown modification time are
+// not filtered by age.
+func (f *contentFilter) MatchesContent(relPath string, content *ClientContent) bool {
+	if f == nil {
+		return true
+	}
+	isDir := content.Type.IsDir()
+	if !f.Matches(relPath, isDir) {
+		return false
+	}
+	if isDir {
+		return true
+	}
+	if !content.Time.IsZero() {
+		if !f.newerThan.IsZero() && !content.Time.After(f.newerThan) {
+			return false
+		}
+		if !f.olderThan.IsZero() && !content.Time.Before(f.olderThan) {
+			return false
+		}
+	}
+	if f.largerThan >= 0 && content.Size <= f.largerThan {
+		return false
+	}
+	if f.smallerThan >= 0 && content.Size >= f.smallerThan {
+		return false
+	}
+	return true
+}
+
+// Filter - filters contents listed from rootPath, paths are relative
+// to the folder of rootPath. Errors are passed along.
+func (f *contentFilter) Filter(contentCh <-chan *ClientContent, rootPath string) <-chan *ClientContent {
+	if f == nil {
+		return contentCh
+	}
+	rootPath = filepath.ToSlash(rootPath)
+	rootPath = rootPath[:strings.LastIndex(rootPath, "/")+1]
+	filteredCh := make(chan *ClientContent)
+	go func() {
+		defer close(filteredCh)
+		for content := range contentCh {
+			if content.Err == nil {
+				relPath := strings.TrimPrefix(filepath.ToSlash(content.URL.Path), rootPath)
+				if !f.MatchesContent(relPath, content) {
+					continue
+				}
+			}
+			filteredCh <- content
+		}
+	}()
+	return filteredCh
+}
+
+// filterPatternsFlag - patterns of a flag as stored in a session.
+func filterPatternsFlag(patterns []string) string {
+	return strings.Join(patterns, "\n")
+}
+
+// parseFilterPatternsFlag - patterns of a flag stored in a session.
+func parseFilterPatternsFlag(s string) []string {
+	if s == "" {
+		return nil
+	}
+	return strings.Split(s, "\n")
+}
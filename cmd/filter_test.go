@@ -0,0 +1,144 @@
+/*
+ * Minio Client (C) 2016 Minio, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"context"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	. "gopkg.in/check.v1"
+)
+
+// Test matching of gitignore style patterns.
+func (s *TestSuite) TestContentFilterMatches(c *C) {
+	filter, err := newContentFilter(filterOpts{Exclude: []string{"*.tmp", ".git/", "/build", "logs/**/*.gz"}})
+	c.Assert(err, IsNil)
+	testCases := []struct {
+		relPath string
+		isDir   bool
+		matches bool
+	}{
+		{"report.pdf", false, true},
+		{"report.tmp", false, false},
+		{"docs/draft.tmp", false, false},
+		{".git", true, false},
+		{".git/config", false, false},
+		{"src/.git/HEAD", false, false},
+		// Only folders are matched by a trailing separator.
+		{"notes/.git", false, true},
+		{"build/main.o", false, false},
+		// Patterns with a separator are relative to the listed folder.
+		{"src/build/main.o", false, true},
+		{"logs/app.gz", false, false},
+		{"logs/2017/06/app.gz", false, false},
+		{"logs/2017/06/app.log", false, true},
+	}
+	for i, testCase := range testCases {
+		c.Assert(filter.Matches(testCase.relPath, testCase.isDir), Equals, testCase.matches, Commentf("Test %d: %s", i+1, testCase.relPath))
+	}
+
+	// Folders are kept with include patterns, files have to match.
+	filter, err = newContentFilter(filterOpts{Include: []string{"*.parquet"}, Exclude: []string{"_temporary/"}})
+	c.Assert(err, IsNil)
+	c.Assert(filter.Matches("year=2017/part-0.parquet", false), Equals, true)
+	c.Assert(filter.Matches("year=2017/part-0.crc", false), Equals, false)
+	c.Assert(filter.Matches("year=2017", true), Equals, true)
+	c.Assert(filter.Matches("_temporary/part-0.parquet", false), Equals, false)
+
+	// No patterns, no filter.
+	filter, err = newContentFilter(filterOpts{})
+	c.Assert(err, IsNil)
+	c.Assert(filter.Matches("anything.tmp", false), Equals, true)
+
+	_, err = newContentFilter(filterOpts{Include: []string{"[a-"}})
+	c.Assert(err, Not(IsNil))
+	_, err = newContentFilter(filterOpts{Exclude: []string{"/"}})
+	c.Assert(err, Not(IsNil))
+
+	c.Assert(parseFilterPatternsFlag(filterPatternsFlag([]string{"*.tmp", ".git/"})), DeepEquals, []string{"*.tmp", ".git/"})
+	c.Assert(parseFilterPatternsFlag(filterPatternsFlag(nil)), IsNil)
+}
+
+// Test filtering by age and size.
+func (s *TestSuite) TestContentFilterAgeSize(c *C) {
+	for age, expected := range map[string]time.Duration{
+		"90m":   90 * time.Minute,
+		"7d":    7 * 24 * time.Hour,
+		"1d12h": 36 * time.Hour,
+	} {
+		d, err := parseFilterAge(age)
+		c.Assert(err, IsNil)
+		c.Assert(d, Equals, expected)
+	}
+	for _, age := range []string{"", "d", "7x", "-1d", "1d-1h"} {
+		_, err := parseFilterAge(age)
+		c.Assert(err, NotNil)
+	}
+
+	filter, err := newContentFilter(filterOpts{NewerThan: "30d", OlderThan: "1d", LargerThan: "1KiB", SmallerThan: "1MiB"})
+	c.Assert(err, IsNil)
+	now := time.Now().UTC()
+	file := func(age time.Duration, size int64) *ClientContent {
+		return &ClientContent{Time: now.Add(-age), Size: size}
+	}
+	c.Assert(filter.MatchesContent("a", file(7*24*time.Hour, 4096)), Equals, true)
+	c.Assert(filter.MatchesContent("a", file(60*24*time.Hour, 4096)), Equals, false)
+	c.Assert(filter.MatchesContent("a", file(time.Hour, 4096)), Equals, false)
+	c.Assert(filter.MatchesContent("a", file(7*24*time.Hour, 1024)), Equals, false)
+	c.Assert(filter.MatchesContent("a", file(7*24*time.Hour, 1024*1024)), Equals, false)
+	// Unknown modification time is not filtered by age.
+	c.Assert(filter.MatchesContent("a", &ClientContent{Size: 4096}), Equals, true)
+	// Folders are kept regardless of age and size.
+	c.Assert(filter.MatchesContent("a", &ClientContent{Type: os.ModeDir}), Equals, true)
+
+	_, err = newContentFilter(filterOpts{LargerThan: "1XB"})
+	c.Assert(err, NotNil)
+	_, err = newContentFilter(filterOpts{OlderThan: "week"})
+	c.Assert(err, NotNil)
+}
+
+// Test filtering of listed contents.
+func (s *TestSuite) TestContentFilterList(c *C) {
+	root, e := ioutil.TempDir(os.TempDir(), "filter-")
+	c.Assert(e, IsNil)
+	defer os.RemoveAll(root)
+
+	for _, name := range []string{"a.parquet", "b.tmp", ".git/config", "data/c.parquet", "data/d.csv"} {
+		objectPath := filepath.Join(root, filepath.FromSlash(name))
+		fsClient, err := fsNew(objectPath)
+		c.Assert(err, IsNil)
+		_, err = fsClient.Put(context.Background(), strings.NewReader(name), int64(len(name)), "application/octet-stream", nil, PutOpts{})
+		c.Assert(err, IsNil)
+	}
+
+	filter, err := newContentFilter(filterOpts{Include: []string{"*.parquet", "*.csv"}, Exclude: []string{".git/", "data/*.csv"}})
+	c.Assert(err, IsNil)
+	clnt, err := fsNew(root + string(filepath.Separator))
+	c.Assert(err, IsNil)
+	var listed []string
+	for content := range filter.Filter(clnt.List(context.Background(), true, false), clnt.GetURL().Path) {
+		c.Assert(content.Err, IsNil)
+		listed = append(listed, filepath.ToSlash(strings.TrimPrefix(content.URL.Path, root)))
+	}
+	sort.Strings(listed)
+	c.Assert(listed, DeepEquals, []string{"/a.parquet", "/data/c.parquet"})
+}
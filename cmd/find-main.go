@@ -0,0 +1,416 @@
+/*
+ * Minio Client (C) 2016 Minio, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/dustin/go-humanize"
+	"github.com/fatih/color"
+	"github.com/minio/cli"
+	"github.com/minio/mc/pkg/console"
+	"github.com/minio/minio/pkg/probe"
+)
+
+// find specific flags.
+var (
+	findFlags = []cli.Flag{
+		cli.BoolFlag{
+			Name:  "help, h",
+			Usage: "Help of find.",
+		},
+		cli.StringFlag{
+			Name:  "name",
+			Usage: "Find objects whose base name matches the wildcard pattern.",
+		},
+		cli.StringFlag{
+			Name:  "path",
+			Usage: "Find objects whose full path matches the wildcard pattern, wildcards match ‘/’ as well.",
+		},
+		cli.StringFlag{
+			Name:  "regex",
+			Usage: "Find objects whose full path matches the regular expression.",
+		},
+		cli.StringFlag{
+			Name:  "size",
+			Usage: "Find objects larger than ‘+SIZE’, smaller than ‘-SIZE’ or of exactly ‘SIZE’, e.g. ‘+10MiB’.",
+		},
+		cli.StringFlag{
+			Name:  "mtime",
+			Usage: "Find objects modified longer ago than ‘+AGE’ or within ‘-AGE’, e.g. ‘+7d’ or ‘-12h’.",
+		},
+		cli.IntFlag{
+			Name:  "maxdepth",
+			Usage: "Descend at most this many levels below the target, 1 only finds objects directly in it. Unlimited if not set.",
+		},
+		cli.StringFlag{
+			Name:  "print",
+			Usage: "Print matches in the format, ‘{}’ is replaced by the path, ‘{base}’, ‘{size}’ and ‘{time}’ by the base name, size and modification time.",
+		},
+		cli.StringFlag{
+			Name:  "exec",
+			Usage: "Run the command through the shell for every match, with the replacements of ‘--print’. Matches are only printed along with ‘--print’.",
+		},
+		outputFlag,
+	}
+)
+
+// find objects.
+var findCmd = cli.Command{
+	Name:   "find",
+	Usage:  "Find objects and files matching expressions.",
+	Action: mainFind,
+	Flags:  append(findFlags, globalFlags...),
+	CustomHelpTemplate: `NAME:
+   mc {{.Name}} - {{.Usage}}
+
+USAGE:
+   mc {{.Name}} [FLAGS] TARGET [TARGET ...]
+
+   All expressions have to match. Without ‘--print’ and ‘--exec’ the paths of the matches are printed.
+
+FLAGS:
+  {{range .Flags}}{{.}}
+  {{end}}
+EXAMPLES:
+   1. Find all JPEG images in a bucket on Amazon S3 cloud storage.
+      $ mc {{.Name}} --name "*.jpg" s3/photos
+
+   2. Find logs larger than 100MiB which were not modified for a week.
+      $ mc {{.Name}} --path "*/logs/*" --size +100MiB --mtime +7d s3/backups
+
+   3. Find objects directly in a bucket, printing their sizes.
+      $ mc {{.Name}} --maxdepth 1 --print "{size} {}" s3/photos
+
+   4. Find objects named by a date of 2017 and copy them to a local folder.
+      $ mc {{.Name}} --regex ".*/2017-[0-9]{2}-[0-9]{2}\.csv" --exec "mc cp {} /tmp/2017/" s3/reports
+
+   5. Find images larger than 10MiB, printing a table of their sizes and modification times.
+      $ mc {{.Name}} --name "*.png" --size +10MiB --output table s3/photos
+`,
+}
+
+// checkFindSyntax - validate all the passed arguments
+func checkFindSyntax(ctx *cli.Context) {
+	if !ctx.Args().Present() {
+		cli.ShowCommandHelpAndExit(ctx, "find", 1) // last argument is exit code
+	}
+	for _, arg := range ctx.Args() {
+		if strings.TrimSpace(arg) == "" {
+			fatalIf(errInvalidArgument().Trace(ctx.Args()...), "Unable to validate empty argument.")
+		}
+	}
+	if _, err := newFindOpts(ctx); err != nil {
+		fatalIf(err.Trace(ctx.Args()...), "Unable to parse find expressions.")
+	}
+	checkOutputSyntax(ctx)
+	if ctx.String("print") != "" && ctx.String("output") != "" {
+		fatalIf(errInvalidArgument().Trace(ctx.Args()...), "--print cannot be used with --output.")
+	}
+}
+
+// findOpts - expressions all matches of find have to match.
+type findOpts struct {
+	name  string
+	path  *regexp.Regexp
+	regex *regexp.Regexp
+
+	// Sign of the size comparison, negative for smaller, positive
+	// for larger and zero for exactly the size. Size is negative if
+	// unset.
+	sizeCmp int
+	size    int64
+	// Bounds of the modification time, zero if unset.
+	newerThan time.Time
+	olderThan time.Time
+	// Levels below the target, unlimited if zero.
+	maxDepth int
+}
+
+// newFindOpts - expressions of the command line flags.
+func newFindOpts(ctx *cli.Context) (findOpts, *probe.Error) {
+	opts := findOpts{name: ctx.String("name"), size: -1, maxDepth: ctx.Int("maxdepth")}
+	if opts.name != "" {
+		if _, e := path.Match(opts.name, ""); e != nil {
+			return findOpts{}, errInvalidFindExpression("--name", opts.name).Trace(opts.name)
+		}
+	}
+	if glob := ctx.String("path"); glob != "" {
+		re, e := globRegexp(glob)
+		if e != nil {
+			return findOpts{}, errInvalidFindExpression("--path", glob).Trace(glob)
+		}
+		opts.path = re
+	}
+	if expr := ctx.String("regex"); expr != "" {
+		re, e := regexp.Compile("^(?:" + expr + ")$")
+		if e != nil {
+			return findOpts{}, errInvalidFindExpression("--regex", expr).Trace(expr)
+		}
+		opts.regex = re
+	}
+	if size := ctx.String("size"); size != "" {
+		sign, value := parseFindSign(size)
+		n, e := humanize.ParseBytes(value)
+		if e != nil {
+			return findOpts{}, errInvalidFindExpression("--size", size).Trace(size)
+		}
+		opts.sizeCmp = sign
+		opts.size = int64(n)
+	}
+	if mtime := ctx.String("mtime"); mtime != "" {
+		sign, value := parseFindSign(mtime)
+		age, err := parseFilterAge(value)
+		if err != nil || sign == 0 {
+			return findOpts{}, errInvalidFindExpression("--mtime", mtime).Trace(mtime)
+		}
+		if sign > 0 {
+			opts.olderThan = time.Now().UTC().Add(-age)
+		} else {
+			opts.newerThan = time.Now().UTC().Add(-age)
+		}
+	}
+	if opts.maxDepth < 0 {
+		return findOpts{}, errInvalidFindExpression("--maxdepth", strconv.Itoa(opts.maxDepth)).Trace()
+	}
+	return opts, nil
+}
+
+// parseFindSign - sign of a ‘+’ or ‘-’ prefixed value like ‘+10MiB’,
+// zero if the value has no sign.
+func parseFindSign(value string) (int, string) {
+	switch {
+	case strings.HasPrefix(value, "+"):
+		return 1, value[1:]
+	case strings.HasPrefix(value, "-"):
+		return -1, value[1:]
+	}
+	return 0, value
+}
+
+// globRegexp - regular expression of the wildcard pattern, wildcards
+// match separators as well like patterns of ‘find -path’.
+func globRegexp(glob string) (*regexp.Regexp, error) {
+	var expr bytes.Buffer
+	expr.WriteString("^")
+	for i := 0; i < len(glob); i++ {
+		switch glob[i] {
+		case '*':
+			expr.WriteString(".*")
+		case '?':
+			expr.WriteString(".")
+		case '[':
+			end := strings.IndexByte(glob[i+1:], ']')
+			if end < 0 {
+				return nil, errInvalidFindExpression("--path", glob).ToGoError()
+			}
+			class := glob[i+1 : i+1+end]
+			if strings.HasPrefix(class, "!") || strings.HasPrefix(class, "^") {
+				class = "^" + class[1:]
+			}
+			expr.WriteString("[" + class + "]")
+			i += end + 1
+		case '\\':
+			if i+1 < len(glob) {
+				i++
+			}
+			expr.WriteString(regexp.QuoteMeta(glob[i : i+1]))
+		default:
+			expr.WriteString(regexp.QuoteMeta(glob[i : i+1]))
+		}
+	}
+	expr.WriteString("$")
+	return regexp.Compile(expr.String())
+}
+
+// matches - reports whether the object at the full path, relative
+// path below the target, matches all expressions.
+func (o findOpts) matches(fullPath, relPath string, content *ClientContent) bool {
+	slashPath := filepath.ToSlash(fullPath)
+	if o.maxDepth > 0 && len(strings.Split(strings.Trim(filepath.ToSlash(relPath), "/"), "/")) > o.maxDepth {
+		return false
+	}
+	if o.name != "" {
+		if ok, _ := path.Match(o.name, path.Base(slashPath)); !ok {
+			return false
+		}
+	}
+	if o.path != nil && !o.path.MatchString(slashPath) {
+		return false
+	}
+	if o.regex != nil && !o.regex.MatchString(slashPath) {
+		return false
+	}
+	if o.size >= 0 {
+		switch {
+		case o.sizeCmp > 0 && content.Size <= o.size:
+			return false
+		case o.sizeCmp < 0 && content.Size >= o.size:
+			return false
+		case o.sizeCmp == 0 && content.Size != o.size:
+			return false
+		}
+	}
+	if !o.newerThan.IsZero() && !content.Time.After(o.newerThan) {
+		return false
+	}
+	if !o.olderThan.IsZero() && !content.Time.Before(o.olderThan) {
+		return false
+	}
+	return true
+}
+
+// findMessage container for an object found.
+type findMessage struct {
+	Status string    `json:"status"`
+	Key    string    `json:"key"`
+	Size   int64     `json:"size"`
+	Time   time.Time `json:"lastModified"`
+
+	// Format of the printed message.
+	format string
+}
+
+// expand - format with placeholders replaced by fields of the match,
+// quoted by quote.
+func (f findMessage) expand(format string, quote func(string) string) string {
+	return strings.NewReplacer(
+		"{}", quote(f.Key),
+		"{base}", quote(path.Base(filepath.ToSlash(f.Key))),
+		"{size}", strconv.FormatInt(f.Size, 10),
+		"{time}", f.Time.Format(time.RFC3339),
+	).Replace(format)
+}
+
+// String colorized find message.
+func (f findMessage) String() string {
+	if f.format == "" {
+		return console.Colorize("Find", f.Key)
+	}
+	return console.Colorize("Find", f.expand(f.format, func(s string) string { return s }))
+}
+
+// JSON jsonified find message.
+func (f findMessage) JSON() string {
+	f.Status = "success"
+	findMessageJSONBytes, e := json.Marshal(f)
+	fatalIf(probe.NewError(e), "Unable to marshal into JSON.")
+	return string(findMessageJSONBytes)
+}
+
+// Columns names the columns of find messages printed as rows.
+func (f findMessage) Columns() []string {
+	return []string{"key", "size", "lastModified"}
+}
+
+// Row find message as a row of a table.
+func (f findMessage) Row() []string {
+	return []string{f.Key, strconv.FormatInt(f.Size, 10), f.Time.Format(time.RFC3339)}
+}
+
+// doFind - walks all objects below the client URL and passes those
+// matching the expressions to action, paths are named relative to
+// targetURL.
+func doFind(clnt Client, targetURL string, opts findOpts, action func(findMessage)) *probe.Error {
+	clntURL := clnt.GetURL()
+	for content := range clnt.List(globalContext, true, false) {
+		if content.Err != nil {
+			return content.Err.Trace(clntURL.String())
+		}
+		if content.Type.IsDir() {
+			continue
+		}
+		relPath := strings.TrimPrefix(content.URL.Path, clntURL.Path)
+		fullPath := targetURL + relPath
+		// Objects named by the target are found by their name.
+		if relPath == "" {
+			fullPath = targetURL
+			relPath = path.Base(filepath.ToSlash(targetURL))
+		}
+		if !opts.matches(fullPath, relPath, content) {
+			continue
+		}
+		action(findMessage{Key: fullPath, Size: content.Size, Time: content.Time})
+	}
+	return nil
+}
+
+// mainFind - is a handler for mc find command
+func mainFind(ctx *cli.Context) {
+	// Additional command specific theme customization.
+	console.SetColor("Find", color.New(color.FgCyan, color.Bold))
+
+	// Set global flags from context.
+	setGlobalsFromContext(ctx)
+
+	// check 'find' cli arguments.
+	checkFindSyntax(ctx)
+
+	opts, _ := newFindOpts(ctx)
+	printFormat := ctx.String("print")
+	execFormat := ctx.String("exec")
+	format, _ := parseOutputFormat(ctx.String("output"), globalJSON)
+	printer := newMessagePrinter(format)
+	action := func(msg findMessage) {
+		if execFormat == "" || printFormat != "" || format != "" {
+			msg.format = printFormat
+			printer.Print(msg)
+		}
+		if execFormat != "" {
+			command := msg.expand(execFormat, shellQuote)
+			cmd := shellCommand(command)
+			cmd.Stdin = os.Stdin
+			cmd.Stdout = os.Stdout
+			cmd.Stderr = os.Stderr
+			if e := cmd.Run(); e != nil {
+				errorIf(probe.NewError(e).Trace(command), fmt.Sprintf("Unable to run ‘%s’.", command))
+			}
+		}
+	}
+
+	for _, targetURL := range ctx.Args() {
+		clnt, err := newClient(targetURL)
+		fatalIf(err.Trace(targetURL), "Unable to initialize target ‘"+targetURL+"’.")
+
+		// Folders, buckets and aliases are searched by their contents.
+		separator := string(clnt.GetURL().Separator)
+		st, err := clnt.Stat()
+		if err != nil {
+			if _, ok := err.ToGoError().(BucketNameEmpty); !ok {
+				fatalIf(err.Trace(targetURL), "Unable to initialize target ‘"+targetURL+"’.")
+			}
+		}
+		if (err != nil || st.Type.IsDir()) && !strings.HasSuffix(targetURL, separator) {
+			targetURL = targetURL + separator
+			clnt, err = newClient(targetURL)
+			fatalIf(err.Trace(targetURL), "Unable to initialize target ‘"+targetURL+"’.")
+		}
+
+		err = doFind(clnt, targetURL, opts, action)
+		errorIf(err.Trace(targetURL), "Unable to find objects in ‘"+targetURL+"’.")
+	}
+	printer.Flush()
+}
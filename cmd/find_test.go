@@ -0,0 +1,119 @@
+/*
+ * Minio Client (C) 2016 Minio, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"context"
+	"io/ioutil"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/minio/minio/pkg/probe"
+	. "gopkg.in/check.v1"
+)
+
+// Test finding objects of a folder by expressions.
+func (s *TestSuite) TestFind(c *C) {
+	root, e := ioutil.TempDir(os.TempDir(), "find-")
+	c.Assert(e, IsNil)
+	defer os.RemoveAll(root)
+
+	objects := map[string]string{
+		"a/x.jpg":     "x",
+		"a/b/y.jpg":   "yy",
+		"a/b/c/z.txt": "zzz",
+		"d/w.txt":     "wwww",
+		"v.jpg":       "vvvvv",
+	}
+	for name, data := range objects {
+		objectPath := filepath.Join(root, filepath.FromSlash(name))
+		fsClient, err := fsNew(objectPath)
+		c.Assert(err, IsNil)
+		_, err = fsClient.Put(context.Background(), strings.NewReader(data), int64(len(data)), "application/octet-stream", nil, PutOpts{})
+		c.Assert(err, IsNil)
+	}
+
+	targetURL := root + string(filepath.Separator)
+	clnt, err := fsNew(targetURL)
+	c.Assert(err, IsNil)
+
+	find := func(opts findOpts) []string {
+		var found []string
+		err := doFind(clnt, targetURL, opts, func(msg findMessage) {
+			found = append(found, filepath.ToSlash(strings.TrimPrefix(msg.Key, targetURL)))
+		})
+		c.Assert(err, IsNil)
+		sort.Strings(found)
+		return found
+	}
+
+	pathRe, e := globRegexp("*/b/*")
+	c.Assert(e, IsNil)
+	testCases := []struct {
+		opts  findOpts
+		found []string
+	}{
+		{findOpts{size: -1}, []string{"a/b/c/z.txt", "a/b/y.jpg", "a/x.jpg", "d/w.txt", "v.jpg"}},
+		{findOpts{name: "*.jpg", size: -1}, []string{"a/b/y.jpg", "a/x.jpg", "v.jpg"}},
+		{findOpts{path: pathRe, size: -1}, []string{"a/b/c/z.txt", "a/b/y.jpg"}},
+		{findOpts{regex: regexp.MustCompile(".*/[a-c]/[xy].jpg$"), size: -1}, []string{"a/b/y.jpg", "a/x.jpg"}},
+		{findOpts{sizeCmp: 1, size: 3}, []string{"d/w.txt", "v.jpg"}},
+		{findOpts{sizeCmp: -1, size: 3}, []string{"a/b/y.jpg", "a/x.jpg"}},
+		{findOpts{sizeCmp: 0, size: 3}, []string{"a/b/c/z.txt"}},
+		{findOpts{maxDepth: 2, size: -1}, []string{"a/x.jpg", "d/w.txt", "v.jpg"}},
+		{findOpts{name: "*.txt", maxDepth: 1, size: -1}, nil},
+	}
+	for i, testCase := range testCases {
+		c.Assert(find(testCase.opts), DeepEquals, testCase.found, Commentf("Test %d", i+1))
+	}
+
+	msg := findMessage{Key: "s3/photos/my photo.jpg", Size: 42}
+	c.Assert(msg.expand("{size} {base}", func(s string) string { return s }), Equals, "42 my photo.jpg")
+	c.Assert(msg.expand("echo {}", shellQuote), Equals, "echo "+shellQuote("s3/photos/my photo.jpg"))
+}
+
+// Test that found objects are printed as they are listed.
+func (s *TestSuite) TestFindStreamed(c *C) {
+	handler := &endlessListHandler{pageSize: 100}
+	server := httptest.NewServer(handler)
+	defer server.Close()
+
+	defer func(load func() (*configV8, *probe.Error)) { loadMcConfig = load }(loadMcConfig)
+	loadMcConfig = func() (*configV8, *probe.Error) {
+		conf := newMcConfig()
+		conf.Hosts["s3"] = hostConfigV8{
+			URL:       server.URL,
+			AccessKey: "WLGDGYAQYIGI833EV05A",
+			SecretKey: "BYvgJM101sHngl2uzjXS/OBF/aMxAN06JrJ3qJlF",
+			API:       "S3v4",
+			Lookup:    "path",
+		}
+		return conf, nil
+	}
+
+	clnt, err := newClient("s3/bucket/")
+	c.Assert(err, IsNil)
+	assertStreamed(c, handler, func() {
+		printer := newMessagePrinter("")
+		err := doFind(clnt, "s3/bucket/", findOpts{size: -1}, func(msg findMessage) { printer.Print(msg) })
+		c.Check(err, IsNil)
+	})
+}
@@ -33,24 +33,84 @@ var globalFlags = []cli.Flag{
 	},
 	cli.BoolFlag{
 		Name:  "quiet, q",
-		Usage: "Suppress chatty console output.",
+		Usage: "Suppress chatty console output, only errors and summaries are printed.",
 	},
 	cli.BoolFlag{
 		Name:  "no-color",
 		Usage: "Disable color theme.",
 	},
+	cli.BoolFlag{
+		Name:  "no-progress",
+		Usage: "Disable progress bars, useful when output is logged.",
+	},
 	cli.BoolFlag{
 		Name:  "json",
-		Usage: "Enable json formatted output.",
+		Usage: "Print newline delimited JSON messages, one per object, event or error.",
+	},
+	cli.StringFlag{
+		Name:  "lang",
+		Usage: "Language of messages and help, e.g. ‘de’. Defaults to the language of the locale set in ‘LANG’.",
 	},
 	cli.BoolFlag{
 		Name:  "debug",
 		Usage: "Enable debugging output.",
 	},
+	cli.StringFlag{
+		Name:  "log-level",
+		Value: "warn",
+		Usage: "Level of logging the control path like HTTP requests, one of ‘debug’, ‘info’ and ‘warn’. ‘--debug’ implies ‘debug’.",
+	},
+	cli.StringFlag{
+		Name:  "log-file",
+		Usage: "Append the log to the file instead of printing it to standard error.",
+	},
+	cli.StringFlag{
+		Name:  "trace-file",
+		Usage: "Record HTTP requests and responses to the file for sharing with support, as HAR if it ends with ‘.har’ and as JSON lines otherwise.",
+	},
+	cli.StringFlag{
+		Name:  "trace-body-limit",
+		Usage: "Record bodies of requests and responses in the trace file up to the given size, e.g. ‘64KiB’. Bodies are not recorded by default.",
+	},
 	cli.BoolFlag{
 		Name:  "insecure",
 		Usage: "Skip SSL certificate verification.",
 	},
+	cli.StringFlag{
+		Name:   "endpoint-url",
+		Usage:  "Host of URLs without an alias, for one-off commands without adding an alias. Existing local paths and paths starting with ‘/’ or ‘.’ are local.",
+		EnvVar: "MC_ENDPOINT_URL",
+	},
+	cli.StringFlag{
+		Name:   "access-key",
+		Usage:  "Access key of the host set with ‘--endpoint-url’.",
+		EnvVar: "MC_ACCESS_KEY",
+	},
+	cli.StringFlag{
+		Name:   "secret-key",
+		Usage:  "Secret key of the host set with ‘--endpoint-url’.",
+		EnvVar: "MC_SECRET_KEY",
+	},
+	cli.DurationFlag{
+		Name:  "dial-timeout",
+		Usage: "Timeout of connecting to hosts, overrides ‘transport’ of the config file. Defaults to 30s.",
+	},
+	cli.DurationFlag{
+		Name:  "response-header-timeout",
+		Usage: "Timeout of waiting for the response headers of hosts after sending a request. No timeout by default.",
+	},
+	cli.DurationFlag{
+		Name:  "tls-handshake-timeout",
+		Usage: "Timeout of TLS handshakes with hosts. Defaults to 10s.",
+	},
+	cli.IntFlag{
+		Name:  "max-idle-conns-per-host",
+		Usage: "Idle connections kept open to every host for later requests. Defaults to 100.",
+	},
+	cli.StringFlag{
+		Name:  "memory-limit",
+		Usage: "Limit the memory buffering parts of uploads to the given size, e.g. ‘512MiB’. Uploads wait for memory instead of exceeding it. No limit by default.",
+	},
 }
 
 // registerCmd registers a cli command
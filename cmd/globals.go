@@ -18,8 +18,11 @@
 package cmd
 
 import (
+	"time"
+
 	"github.com/minio/cli"
 	"github.com/minio/mc/pkg/console"
+	"github.com/minio/mc/pkg/httptracer"
 )
 
 // mc configuration related constants.
@@ -40,24 +43,43 @@ const (
 
 	// Profile directory for dumping profiler outputs.
 	globalProfileDir = "profile"
+
+	// Listings of folders kept for reuse by diff and mirror.
+	globalListingCacheDir = "listing-cache"
+
+	// States of incrementally mirrored folders.
+	globalMirrorStateDir = "mirror-state"
+
+	// Events recorded by watches, replayed by events replay.
+	globalEventJournalDir = "event-journal"
 )
 
 var (
-	globalQuiet    = false // Quiet flag set via command line
-	globalJSON     = false // Json flag set via command line
-	globalDebug    = false // Debug flag set via command line
-	globalNoColor  = false // No Color flag set via command line
-	globalInsecure = false // Insecure flag set via command line
+	globalQuiet      = false // Quiet flag set via command line
+	globalJSON       = false // Json flag set via command line
+	globalDebug      = false // Debug flag set via command line
+	globalNoColor    = false // No Color flag set via command line
+	globalInsecure   = false // Insecure flag set via command line
+	globalNoProgress = false // No Progress flag set via command line, implied by quiet
+	// Tuning of HTTP connections set via command line, overriding the config file.
+	globalTransport = TransportOpts{}
+	// Recursive listing of all buckets, set by ls.
+	globalBucketListing = BucketListOpts{}
+	// Host of URLs without an alias set via command line, nil if unset.
+	globalEndpoint *hostConfigV8
+	// Recorder of HTTP requests set via command line, nil if unset.
+	globalTraceRecorder *httptracer.Recorder
 	// WHEN YOU ADD NEXT GLOBAL FLAG, MAKE SURE TO ALSO UPDATE SESSION CODE AND CODE BELOW.
 )
 
 // Set global states. NOTE: It is deliberately kept monolithic to ensure we dont miss out any flags.
-func setGlobals(quiet, debug, json, noColor, insecure bool) {
+func setGlobals(quiet, debug, json, noColor, insecure, noProgress bool) {
 	globalQuiet = quiet
 	globalDebug = debug
 	globalJSON = json
 	globalNoColor = noColor
 	globalInsecure = insecure
+	globalNoProgress = noProgress || quiet
 
 	// Enable debug messages if requested.
 	if globalDebug {
@@ -77,5 +99,54 @@ func setGlobalsFromContext(ctx *cli.Context) {
 	json := ctx.Bool("json") || ctx.GlobalBool("json")
 	noColor := ctx.Bool("no-color") || ctx.GlobalBool("no-color")
 	insecure := ctx.Bool("insecure") || ctx.GlobalBool("insecure")
-	setGlobals(quiet, debug, json, noColor, insecure)
+	noProgress := ctx.Bool("no-progress") || ctx.GlobalBool("no-progress")
+	setGlobals(quiet, debug, json, noColor, insecure, noProgress)
+
+	logLevel := ctx.String("log-level")
+	if !ctx.IsSet("log-level") && ctx.GlobalIsSet("log-level") {
+		logLevel = ctx.GlobalString("log-level")
+	}
+	fatalIf(setLogging(logLevel, stringFromContext(ctx, "log-file"), debug).Trace(), "Unable to set up logging.")
+	traceFile, traceBodyLimit := stringFromContext(ctx, "trace-file"), stringFromContext(ctx, "trace-body-limit")
+	if traceFile == "" && traceBodyLimit != "" {
+		fatalIf(errInvalidArgument().Trace(traceBodyLimit), "‘--trace-body-limit’ requires ‘--trace-file’.")
+	}
+	fatalIf(setTracing(traceFile, traceBodyLimit).Trace(traceFile), "Unable to set up tracing.")
+
+	globalTransport = TransportOpts{
+		DialTimeout:           durationFromContext(ctx, "dial-timeout"),
+		ResponseHeaderTimeout: durationFromContext(ctx, "response-header-timeout"),
+		TLSHandshakeTimeout:   durationFromContext(ctx, "tls-handshake-timeout"),
+		MaxIdleConnsPerHost:   intFromContext(ctx, "max-idle-conns-per-host"),
+	}
+	fatalIf(globalTransport.validate().Trace(), "Unable to parse HTTP transport flags.")
+	fatalIf(setMemoryLimit(stringFromContext(ctx, "memory-limit")).Trace(), "Unable to parse ‘--memory-limit’.")
+
+	endpoint, err := newEndpointHostConfig(stringFromContext(ctx, "endpoint-url"), stringFromContext(ctx, "access-key"), stringFromContext(ctx, "secret-key"))
+	fatalIf(err.Trace(), "Unable to parse ‘--endpoint-url’ flags.")
+	globalEndpoint = endpoint
+}
+
+// stringFromContext - string flag of the command, or else of mc.
+func stringFromContext(ctx *cli.Context, name string) string {
+	if s := ctx.String(name); s != "" {
+		return s
+	}
+	return ctx.GlobalString(name)
+}
+
+// durationFromContext - duration flag of the command, or else of mc.
+func durationFromContext(ctx *cli.Context, name string) time.Duration {
+	if d := ctx.Duration(name); d != 0 {
+		return d
+	}
+	return ctx.GlobalDuration(name)
+}
+
+// intFromContext - int flag of the command, or else of mc.
+func intFromContext(ctx *cli.Context, name string) int {
+	if n := ctx.Int(name); n != 0 {
+		return n
+	}
+	return ctx.GlobalInt(name)
 }
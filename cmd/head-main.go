@@ -0,0 +1,166 @@
+/*
+ * Minio Client (C) 2016 Minio, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"bufio"
+	"bytes"
+	"io"
+
+	"github.com/dustin/go-humanize"
+	"github.com/minio/cli"
+	"github.com/minio/mc/pkg/console"
+	"github.com/minio/minio/pkg/probe"
+)
+
+var (
+	headFlags = []cli.Flag{
+		cli.BoolFlag{
+			Name:  "help, h",
+			Usage: "Help of head.",
+		},
+		cli.IntFlag{
+			Name:  "lines, n",
+			Value: 10,
+			Usage: "Display the first number of lines.",
+		},
+		cli.StringFlag{
+			Name:  "bytes, c",
+			Usage: "Display the first number of bytes, e.g. ‘512’ or ‘1KiB’. Only this range is downloaded.",
+		},
+		cli.StringFlag{
+			Name:  "encrypt-key",
+			Usage: "Decrypt objects with customer provided keys, as comma separated list of ‘ALIAS/PREFIX=KEY’.",
+		},
+	}
+)
+
+// Display the beginning of files.
+var headCmd = cli.Command{
+	Name:   "head",
+	Usage:  "Display first part of files.",
+	Action: mainHead,
+	Flags:  append(headFlags, globalFlags...),
+	CustomHelpTemplate: `NAME:
+   mc {{.Name}} - {{.Usage}}
+
+USAGE:
+   mc {{.Name}} [FLAGS] SOURCE [SOURCE...]
+
+FLAGS:
+  {{range .Flags}}{{.}}
+  {{end}}
+EXAMPLES:
+   1. Display the first ten lines of a CSV object.
+      $ mc {{.Name}} s3/mybucket/people.csv
+
+   2. Display the first 512 bytes of a large object.
+      $ mc {{.Name}} -c 512 s3/mybucket/backup.tar
+
+`,
+}
+
+// checkHeadSyntax performs command-line input validation for head command.
+func checkHeadSyntax(ctx *cli.Context) {
+	if !ctx.Args().Present() {
+		cli.ShowCommandHelpAndExit(ctx, "head", 1) // last argument is exit code
+	}
+	if ctx.Int("lines") < 0 {
+		fatalIf(errInvalidArgument().Trace(ctx.Args()...), "Number of lines cannot be negative.")
+	}
+	if ctx.String("bytes") != "" {
+		if _, e := humanize.ParseBytes(ctx.String("bytes")); e != nil {
+			fatalIf(probe.NewError(e), "Unable to parse number of bytes.")
+		}
+	}
+}
+
+// headLines reads up to n lines from the reader.
+func headLines(r io.Reader, n int) (io.Reader, *probe.Error) {
+	var buf bytes.Buffer
+	br := bufio.NewReader(r)
+	for i := 0; i < n; i++ {
+		line, e := br.ReadBytes('\n')
+		buf.Write(line)
+		if e == io.EOF {
+			break
+		}
+		if e != nil {
+			return nil, probe.NewError(e)
+		}
+	}
+	return &buf, nil
+}
+
+// headURL displays the beginning of a URL to stdout, either the first
+// lines or, if byteCount is not negative, the first bytes.
+func headURL(sourceURL string, sse EncryptOpts, lines int, byteCount int64) *probe.Error {
+	if byteCount >= 0 {
+		if byteCount == 0 {
+			return nil
+		}
+		reader, err := getSourceStream(globalContext, sourceURL, GetOpts{SSE: sse, Length: byteCount})
+		if err != nil {
+			return err.Trace(sourceURL)
+		}
+		return catOut(reader).Trace(sourceURL)
+	}
+
+	if lines == 0 {
+		return nil
+	}
+	reader, err := getSourceStream(globalContext, sourceURL, GetOpts{SSE: sse})
+	if err != nil {
+		return err.Trace(sourceURL)
+	}
+	if reader, err = headLines(reader, lines); err != nil {
+		return err.Trace(sourceURL)
+	}
+	return catOut(reader).Trace(sourceURL)
+}
+
+// mainHead is the main entry point for head command.
+func mainHead(ctx *cli.Context) {
+	// Set global flags from context.
+	setGlobalsFromContext(ctx)
+
+	// check 'head' cli arguments.
+	checkHeadSyntax(ctx)
+
+	encKeys, err := parseEncryptKeys(ctx.String("encrypt-key"), "")
+	fatalIf(err, "Unable to parse encryption keys.")
+
+	// Lines are counted unless a number of bytes is asked for.
+	lines := ctx.Int("lines")
+	var byteCount int64 = -1
+	if ctx.String("bytes") != "" {
+		n, _ := humanize.ParseBytes(ctx.String("bytes"))
+		byteCount = int64(n)
+	}
+
+	args := ctx.Args()
+	for i, url := range args {
+		if len(args) > 1 {
+			if i > 0 {
+				console.Println()
+			}
+			console.Println("==> " + url + " <==")
+		}
+		err = headURL(url, getEncryptOpts(encKeys, url), lines, byteCount)
+		fatalIf(err.Trace(url), "Unable to read from ‘"+url+"’.")
+	}
+}
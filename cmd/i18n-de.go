@@ -0,0 +1,175 @@
+/*
+ * Minio Client (C) 2016 Minio, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+// catalogDE - German translations of messages, help and prompts.
+var catalogDE = messageCatalog{
+	// Help.
+	"NAME:":         "NAME:",
+	"Name:":         "Name:",
+	"USAGE:":        "AUFRUF:",
+	"FLAGS:":        "OPTIONEN:",
+	"GLOBAL FLAGS:": "GLOBALE OPTIONEN:",
+	"COMMANDS:":     "BEFEHLE:",
+	"EXAMPLES:":     "BEISPIELE:",
+	"VERSION:":      "VERSION:",
+
+	"Minio Client for cloud storage and filesystems.": "Minio Client für Cloud-Speicher und Dateisysteme.",
+
+	// Global flags.
+	"Show this help.":               "Diese Hilfe anzeigen.",
+	"Show help.":                    "Hilfe anzeigen.",
+	"Path to configuration folder.": "Pfad zum Konfigurationsordner.",
+	"Suppress chatty console output, only errors and summaries are printed.": "Gesprächige Ausgaben unterdrücken, nur Fehler und Zusammenfassungen werden ausgegeben.",
+	"Disable color theme.":                                                   "Farbige Ausgabe abschalten.",
+	"Disable progress bars, useful when output is logged.":                   "Fortschrittsbalken abschalten, nützlich wenn die Ausgabe protokolliert wird.",
+	"Print newline delimited JSON messages, one per object, event or error.": "Zeilenweise JSON-Meldungen ausgeben, eine je Objekt, Ereignis oder Fehler.",
+	"Enable debugging output.":                                               "Ausgaben zur Fehlersuche einschalten.",
+	"Skip SSL certificate verification.":                                     "Prüfung der SSL-Zertifikate überspringen.",
+	"Language of messages and help, e.g. ‘de’. Defaults to the language of the locale set in ‘LANG’.":                                                 "Sprache der Meldungen und der Hilfe, z.B. ‘de’. Standard ist die Sprache der in ‘LANG’ gesetzten Locale.",
+	"Host of URLs without an alias, for one-off commands without adding an alias. Existing local paths and paths starting with ‘/’ or ‘.’ are local.": "Host von URLs ohne Alias, für einzelne Befehle ohne einen Alias anzulegen. Vorhandene lokale Pfade und Pfade, die mit ‘/’ oder ‘.’ beginnen, sind lokal.",
+	"Access key of the host set with ‘--endpoint-url’.":                                                                                               "Access Key des mit ‘--endpoint-url’ gesetzten Hosts.",
+	"Secret key of the host set with ‘--endpoint-url’.":                                                                                               "Secret Key des mit ‘--endpoint-url’ gesetzten Hosts.",
+
+	"Timeout of connecting to hosts, overrides ‘transport’ of the config file. Defaults to 30s.":           "Timeout für Verbindungen zu Hosts, überschreibt ‘transport’ der Konfigurationsdatei. Standard ist 30s.",
+	"Timeout of waiting for the response headers of hosts after sending a request. No timeout by default.": "Timeout für das Warten auf die Antwort-Header von Hosts nach einer Anfrage. Standard ist kein Timeout.",
+	"Timeout of TLS handshakes with hosts. Defaults to 10s.":                                               "Timeout für TLS-Handshakes mit Hosts. Standard ist 10s.",
+	"Idle connections kept open to every host for later requests. Defaults to 100.":                        "Offen gehaltene unbenutzte Verbindungen je Host für spätere Anfragen. Standard ist 100.",
+
+	// Commands.
+	"List files and folders.":                                                                  "Dateien und Ordner auflisten.",
+	"Summarize disk usage of folders and prefixes.":                                            "Speicherbelegung von Ordnern und Präfixen zusammenfassen.",
+	"Find objects and files matching expressions.":                                             "Objekte und Dateien zu Ausdrücken finden.",
+	"Render prefixes of buckets and folders as a tree.":                                        "Präfixe von Buckets und Ordnern als Baum darstellen.",
+	"Display metadata of objects and files.":                                                   "Metadaten von Objekten und Dateien anzeigen.",
+	"Measure upload and download throughput of a target.":                                      "Durchsatz von Uploads und Downloads eines Ziels messen.",
+	"Make a bucket or folder.":                                                                 "Bucket oder Ordner anlegen.",
+	"Remove a bucket [WARNING: Use with care].":                                                "Bucket entfernen [WARNUNG: Mit Vorsicht verwenden].",
+	"Display contents of a file.":                                                              "Inhalt einer Datei anzeigen.",
+	"Display first part of files.":                                                             "Anfang von Dateien anzeigen.",
+	"Display last part of files.":                                                              "Ende von Dateien anzeigen.",
+	"Run SQL queries on objects.":                                                              "SQL-Abfragen auf Objekten ausführen.",
+	"Generate URL for sharing.":                                                                "URL zum Teilen erzeugen.",
+	"Copy one or more objects to a target.":                                                    "Ein oder mehrere Objekte zu einem Ziel kopieren.",
+	"Move files and objects.":                                                                  "Dateien und Objekte verschieben.",
+	"Rename objects within a bucket.":                                                          "Objekte innerhalb eines Buckets umbenennen.",
+	"Compute differences between two folders.":                                                 "Unterschiede zwischen zwei Ordnern ermitteln.",
+	"Remove file or bucket [WARNING: Use with care].":                                          "Datei oder Bucket entfernen [WARNUNG: Mit Vorsicht verwenden].",
+	"Restore objects removed or overwritten in versioned buckets.":                             "In versionierten Buckets entfernte oder überschriebene Objekte wiederherstellen.",
+	"Manage bucket notification.":                                                              "Benachrichtigungen von Buckets verwalten.",
+	"Manage bucket lifecycle.":                                                                 "Lebenszyklus von Buckets verwalten.",
+	"Manage tags of objects.":                                                                  "Tags von Objekten verwalten.",
+	"Manage retention of locked objects.":                                                      "Aufbewahrung gesperrter Objekte verwalten.",
+	"Manage legal hold of locked objects.":                                                     "Rechtliche Sperre gesperrter Objekte verwalten.",
+	"Restore archived objects for a number of days.":                                           "Archivierte Objekte für eine Anzahl von Tagen wiederherstellen.",
+	"Manage access control lists of buckets and objects.":                                      "Zugriffskontrolllisten von Buckets und Objekten verwalten.",
+	"Manage versioning of buckets.":                                                            "Versionierung von Buckets verwalten.",
+	"Watch for events on object storage and filesystem.":                                       "Ereignisse auf Objektspeicher und Dateisystem beobachten.",
+	"Set public policy on bucket or prefix.":                                                   "Öffentliche Richtlinie für Bucket oder Präfix setzen.",
+	"Manage saved sessions of cp and mirror operations.":                                       "Gespeicherte Sitzungen von cp und mirror verwalten.",
+	"Manage configuration file.":                                                               "Konfigurationsdatei verwalten.",
+	"Generate completion scripts for bash, zsh and fish.":                                      "Vervollständigungsskripte für bash, zsh und fish erzeugen.",
+	"Check for a new software update.":                                                         "Nach einer neuen Softwareversion suchen.",
+	"Print version.":                                                                           "Version ausgeben.",
+	"Mirror folders recursively from a single source to single destination.":                   "Ordner rekursiv von einer Quelle zu einem Ziel spiegeln.",
+	"Write contents of stdin to one target. When no target is specified, it writes to stdout.": "Standardeingabe in ein Ziel schreiben. Ohne Ziel wird in die Standardausgabe geschrieben.",
+
+	// Flags.
+	"Remove recursively.":                                                 "Rekursiv entfernen.",
+	"Force a dangerous remove operation.":                                 "Gefährliches Entfernen erzwingen.",
+	"Remove objects matching this prefix.":                                "Objekte mit diesem Präfix entfernen.",
+	"Remove an incomplete upload(s).":                                     "Unvollständige Uploads entfernen.",
+	"Perform a fake remove operation.":                                    "Entfernen nur simulieren.",
+	"Read object list from STDIN.":                                        "Liste der Objekte von der Standardeingabe lesen.",
+	"Remove object only if its created older than given time.":            "Objekt nur entfernen, wenn es älter als die angegebene Zeit ist.",
+	"Remove a specific version of the object.":                            "Eine bestimmte Version des Objekts entfernen.",
+	"Perform a fake mirror operation.":                                    "Spiegeln nur simulieren.",
+	"Remove extraneous file(s) on target.":                                "Überzählige Dateien im Ziel entfernen.",
+	"Do not ask for confirmation of destructive operations, for scripts.": "Nicht nach Bestätigung zerstörerischer Operationen fragen, für Skripte.",
+
+	// Confirmations.
+	"y":                               "j",
+	"yes":                             "ja",
+	"%s Continue? [y/N]: ":            "%s Fortfahren? [j/N]: ",
+	"%s objects (%s)":                 "%s Objekte (%s)",
+	"more than %s objects":            "mehr als %s Objekte",
+	"all objects":                     "alle Objekte",
+	"All %s of ‘%s’ will be removed.": "Alle %s von ‘%s’ werden entfernt.",
+	"All objects with prefix ‘%s’ will be removed.":          "Alle Objekte mit dem Präfix ‘%s’ werden entfernt.",
+	"Bucket ‘%s’ will be removed along with %s.":             "Bucket ‘%s’ wird samt %[2]s entfernt.",
+	"Objects of ‘%s’ missing in ‘%s’ will be removed.":       "Objekte von ‘%s’, die in ‘%s’ fehlen, werden entfernt.",
+	"Anonymous users will be allowed to %s objects of ‘%s’.": "Anonyme Benutzer erhalten Zugriff ‘%s’ auf Objekte von ‘%s’.",
+	"Nothing was changed.":                                   "Es wurde nichts geändert.",
+
+	// Messages.
+	"Removed ‘%s’.":                                                   "‘%s’ entfernt.",
+	"Removed ‘%s’ (version ‘%s’).":                                    "‘%s’ (Version ‘%s’) entfernt.",
+	"Removed bucket ‘%s’.":                                            "Bucket ‘%s’ entfernt.",
+	"Removed %d objects, %d incomplete uploads and bucket ‘%s’.":      "%d Objekte, %d unvollständige Uploads und Bucket ‘%s’ entfernt.",
+	"Would remove %d objects, %d incomplete uploads and bucket ‘%s’.": "Würde %d Objekte, %d unvollständige Uploads und Bucket ‘%s’ entfernen.",
+	"Bucket created successfully ‘%s’.":                               "Bucket ‘%s’ erfolgreich angelegt.",
+	"‘%s’ is not a mc command. See ‘mc --help’.":                      "‘%s’ ist kein mc-Befehl. Siehe ‘mc --help’.",
+	"Did you mean one of these?":                                      "Meinten Sie einen dieser Befehle?",
+
+	// Errors.
+	"Invalid arguments provided, cannot proceed.":            "Ungültige Argumente, Abbruch.",
+	"Operation was not confirmed.":                           "Die Operation wurde nicht bestätigt.",
+	"Unsupported language ‘%s’, supported languages are %s.": "Nicht unterstützte Sprache ‘%s’, unterstützt werden %s.",
+	"Unable to set language.":                                "Sprache kann nicht gesetzt werden.",
+	"Invalid source ‘%s’.":                                   "Ungültige Quelle ‘%s’.",
+	"Invalid target ‘%s’.":                                   "Ungültiges Ziel ‘%s’.",
+	"Source ‘%s’ is a folder.":                               "Quelle ‘%s’ ist ein Ordner.",
+	"Overwrite not allowed for ‘%s’. Use ‘--force’ to override this behavior.":  "Überschreiben von ‘%s’ ist nicht erlaubt. Mit ‘--force’ wird es erzwungen.",
+	"Delete not allowed for ‘%s’. Use ‘--force’ to override this behavior.":     "Löschen von ‘%s’ ist nicht erlaubt. Mit ‘--force’ wird es erzwungen.",
+	"No matching host found for the given URL ‘%s’.":                            "Kein passender Host für die URL ‘%s’ gefunden.",
+	"No objects match ‘%s’.":                                                    "Keine Objekte passen zu ‘%s’.",
+	"Wrong passphrase of the config.":                                           "Falsche Passphrase der Konfiguration.",
+	"Config is encrypted, please set its passphrase in ‘MC_CONFIG_PASSPHRASE’.": "Die Konfiguration ist verschlüsselt, bitte ihre Passphrase in ‘MC_CONFIG_PASSPHRASE’ setzen.",
+	"Bucket ‘%s’ does not exist.":                                               "Bucket ‘%s’ existiert nicht.",
+	"Bucket ‘%s’ exists.":                                                       "Bucket ‘%s’ existiert.",
+	"Bucket name cannot be empty.":                                              "Der Bucketname darf nicht leer sein.",
+	"Object ‘%s’ already exists.":                                               "Objekt ‘%s’ existiert bereits.",
+	"Requested file ‘%s’ not found":                                             "Angeforderte Datei ‘%s’ nicht gefunden",
+	"Insufficient permissions to access this file ‘%s’":                         "Unzureichende Berechtigungen für den Zugriff auf die Datei ‘%s’",
+	"Removal requires --force or --yes option. This operational is irreversible. Please review carefully before performing this *DANGEROUS* operation.": "Entfernen erfordert die Option --force oder --yes. Dies kann nicht rückgängig gemacht werden. Bitte vor dieser *GEFÄHRLICHEN* Operation sorgfältig prüfen.",
+	"Unable to marshal into JSON.":               "Umwandlung in JSON nicht möglich.",
+	"Unable to parse filters.":                   "Filter können nicht gelesen werden.",
+	"Unable to parse upload options.":            "Upload-Optionen können nicht gelesen werden.",
+	"Unable to parse encryption keys.":           "Schlüssel zur Verschlüsselung können nicht gelesen werden.",
+	"Unable to load config.":                     "Konfiguration kann nicht geladen werden.",
+	"Unable to get current working folder.":      "Aktueller Arbeitsordner kann nicht ermittelt werden.",
+	"Invalid number of source arguments.":        "Ungültige Anzahl von Quellargumenten.",
+	"Unable to initialize ‘%s’.":                 "‘%s’ kann nicht initialisiert werden.",
+	"Unable to initialize target ‘%s’.":          "Ziel ‘%s’ kann nicht initialisiert werden.",
+	"Unable to remove ‘%s’.":                     "‘%s’ kann nicht entfernt werden.",
+	"Unable to remove version ‘%s’ of ‘%s’.":     "Version ‘%s’ von ‘%s’ kann nicht entfernt werden.",
+	"Unable to remove bucket ‘%s’.":              "Bucket ‘%s’ kann nicht entfernt werden.",
+	"Unable to remove objects of ‘%s’.":          "Objekte von ‘%s’ können nicht entfernt werden.",
+	"Unable to stat ‘%s’.":                       "‘%s’ kann nicht abgefragt werden.",
+	"Unable to stat source ‘%s’.":                "Quelle ‘%s’ kann nicht abgefragt werden.",
+	"Unable to stat target ‘%s’.":                "Ziel ‘%s’ kann nicht abgefragt werden.",
+	"Unable to list ‘%s’.":                       "‘%s’ kann nicht aufgelistet werden.",
+	"Unable to read from ‘%s’.":                  "Von ‘%s’ kann nicht gelesen werden.",
+	"Unable to rename ‘%s’.":                     "‘%s’ kann nicht umbenannt werden.",
+	"Unable to restore ‘%s’.":                    "‘%s’ kann nicht wiederhergestellt werden.",
+	"Unable to load config ‘%s’.":                "Konfiguration ‘%s’ kann nicht geladen werden.",
+	"Unable to save config ‘%s’.":                "Konfiguration ‘%s’ kann nicht gespeichert werden.",
+	"Unable to load session ‘%s’.":               "Sitzung ‘%s’ kann nicht geladen werden.",
+	"Unable to add host ‘%s’.":                   "Host ‘%s’ kann nicht hinzugefügt werden.",
+	"Unable to make bucket ‘%s’.":                "Bucket ‘%s’ kann nicht angelegt werden.",
+	"Unable to record removal of ‘%s’ for undo.": "Entfernen von ‘%s’ kann nicht für ‘undo’ gespeichert werden.",
+}
@@ -0,0 +1,270 @@
+/*
+ * Minio Client (C) 2016 Minio, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"reflect"
+	"regexp"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/minio/cli"
+	"github.com/minio/minio/pkg/probe"
+)
+
+// Language of user facing messages, help and prompts of mc. Messages
+// are looked up in the catalog of the language by their English text,
+// messages missing in the catalog are printed in English. JSON output
+// is never translated, so that scripts can rely on it.
+var globalLang = langEnglish
+
+const langEnglish = "en"
+
+// messageCatalog - translations of messages keyed by their English
+// text. Keys may hold ‘%s’ and ‘%d’ verbs, which match any text of
+// messages built by concatenation and are reordered with ‘%[n]s’.
+type messageCatalog map[string]string
+
+// messageCatalogs - catalogs of the supported languages but English.
+var messageCatalogs = map[string]messageCatalog{
+	"de": catalogDE,
+}
+
+// supportedLangs - languages which can be set with ‘--lang’.
+func supportedLangs() []string {
+	langs := []string{langEnglish}
+	for lang := range messageCatalogs {
+		langs = append(langs, lang)
+	}
+	sort.Strings(langs[1:])
+	return langs
+}
+
+// parseLang - language of a locale like ‘de_DE.UTF-8’, empty if the
+// locale does not name a language.
+func parseLang(locale string) string {
+	if i := strings.IndexAny(locale, "_.@-"); i >= 0 {
+		locale = locale[:i]
+	}
+	locale = strings.ToLower(locale)
+	switch locale {
+	case "", "c", "posix":
+		return ""
+	}
+	return locale
+}
+
+// langFromArgs - value of ‘--lang’ in the command line, which has to be
+// known before the commands and their help are set up.
+func langFromArgs(args []string) (lang string, ok bool) {
+	for i, arg := range args {
+		if arg == "--" {
+			break
+		}
+		name := strings.TrimLeft(arg, "-")
+		if name == arg {
+			continue
+		}
+		if strings.HasPrefix(name, "lang=") {
+			lang, ok = strings.TrimPrefix(name, "lang="), true
+		} else if name == "lang" && i+1 < len(args) {
+			lang, ok = args[i+1], true
+		}
+	}
+	return lang, ok
+}
+
+// langFromEnv - language of the locale of the environment, variables
+// take precedence as for POSIX locales.
+func langFromEnv() string {
+	for _, env := range []string{"LC_ALL", "LC_MESSAGES", "LANG"} {
+		if locale := os.Getenv(env); locale != "" {
+			return parseLang(locale)
+		}
+	}
+	return ""
+}
+
+// setLang - sets the language of messages, ‘--lang’ has to name a
+// supported language while unsupported locales of the environment
+// fall back to English.
+func setLang(args []string) *probe.Error {
+	globalLang = langEnglish
+	if locale, ok := langFromArgs(args); ok {
+		lang := parseLang(locale)
+		if lang == "" || lang == langEnglish {
+			return nil
+		}
+		if _, ok := messageCatalogs[lang]; !ok {
+			return errUnsupportedLang(locale, supportedLangs()).Trace(locale)
+		}
+		globalLang = lang
+		return nil
+	}
+	if _, ok := messageCatalogs[langFromEnv()]; ok {
+		globalLang = langFromEnv()
+	}
+	return nil
+}
+
+// catalogPattern - a catalog key holding verbs, matching messages
+// built by concatenation.
+type catalogPattern struct {
+	re          *regexp.Regexp
+	translation string
+}
+
+// byLengthDesc - sorts longer strings first, alike strings by text.
+type byLengthDesc []string
+
+func (s byLengthDesc) Len() int      { return len(s) }
+func (s byLengthDesc) Swap(i, j int) { s[i], s[j] = s[j], s[i] }
+func (s byLengthDesc) Less(i, j int) bool {
+	if len(s[i]) != len(s[j]) {
+		return len(s[i]) > len(s[j])
+	}
+	return s[i] < s[j]
+}
+
+// Patterns of the catalogs, compiled once per language.
+var (
+	catalogPatterns   = map[string][]catalogPattern{}
+	catalogPatternsMu sync.Mutex
+)
+
+// Verbs of catalog keys and translations.
+var catalogVerbRe = regexp.MustCompile(`%(\[\d+\])?[sd]`)
+
+// patternsOf - patterns of the keys of the catalog of the language,
+// longer keys are tried first being more specific.
+func patternsOf(lang string) []catalogPattern {
+	catalogPatternsMu.Lock()
+	defer catalogPatternsMu.Unlock()
+	if patterns, ok := catalogPatterns[lang]; ok {
+		return patterns
+	}
+
+	var keys []string
+	for key := range messageCatalogs[lang] {
+		if catalogVerbRe.MatchString(key) {
+			keys = append(keys, key)
+		}
+	}
+	sort.Sort(byLengthDesc(keys))
+	patterns := []catalogPattern{}
+	for _, key := range keys {
+		parts := catalogVerbRe.Split(key, -1)
+		for i := range parts {
+			parts[i] = regexp.QuoteMeta(parts[i])
+		}
+		patterns = append(patterns, catalogPattern{
+			re:          regexp.MustCompile(`^(?s)` + strings.Join(parts, "(.*?)") + `$`),
+			translation: catalogVerbRe.ReplaceAllString(messageCatalogs[lang][key], "%${1}s"),
+		})
+	}
+	catalogPatterns[lang] = patterns
+	return patterns
+}
+
+// tr - translation of the message into the language of mc, the message
+// itself if there is none.
+func tr(msg string) string {
+	catalog, ok := messageCatalogs[globalLang]
+	if !ok || msg == "" {
+		return msg
+	}
+	if translation, ok := catalog[msg]; ok {
+		return translation
+	}
+	for _, pattern := range patternsOf(globalLang) {
+		if m := pattern.re.FindStringSubmatch(msg); m != nil {
+			args := make([]interface{}, len(m)-1)
+			for i, arg := range m[1:] {
+				args[i] = arg
+			}
+			return fmt.Sprintf(pattern.translation, args...)
+		}
+	}
+	return msg
+}
+
+// trf - formats the translation of the format.
+func trf(format string, args ...interface{}) string {
+	return fmt.Sprintf(tr(format), args...)
+}
+
+// Headings of help templates, translated as whole lines.
+var helpHeadings = []string{"NAME:", "Name:", "USAGE:", "FLAGS:", "GLOBAL FLAGS:", "COMMANDS:", "EXAMPLES:", "VERSION:"}
+
+// localizeHelpTemplate - translates the headings of a help template.
+func localizeHelpTemplate(template string) string {
+	lines := strings.Split(template, "\n")
+	for i, line := range lines {
+		for _, heading := range helpHeadings {
+			if line == heading {
+				lines[i] = tr(heading)
+			}
+		}
+	}
+	return strings.Join(lines, "\n")
+}
+
+// localizeFlags - flags with translated usage.
+func localizeFlags(flags []cli.Flag) []cli.Flag {
+	localized := make([]cli.Flag, len(flags))
+	for i, flag := range flags {
+		v := reflect.New(reflect.TypeOf(flag)).Elem()
+		v.Set(reflect.ValueOf(flag))
+		if v.Kind() == reflect.Struct {
+			if usage := v.FieldByName("Usage"); usage.IsValid() && usage.Kind() == reflect.String {
+				usage.SetString(tr(usage.String()))
+			}
+		}
+		localized[i] = v.Interface().(cli.Flag)
+	}
+	return localized
+}
+
+// localizeCommands - commands with translated help.
+func localizeCommands(cmds []cli.Command) []cli.Command {
+	if cmds == nil {
+		return nil
+	}
+	localized := make([]cli.Command, len(cmds))
+	for i, cmd := range cmds {
+		cmd.Usage = tr(cmd.Usage)
+		cmd.Flags = localizeFlags(cmd.Flags)
+		cmd.CustomHelpTemplate = localizeHelpTemplate(cmd.CustomHelpTemplate)
+		cmd.Subcommands = localizeCommands(cmd.Subcommands)
+		localized[i] = cmd
+	}
+	return localized
+}
+
+// localizeApp - translates the help of mc and all its commands.
+func localizeApp(app *cli.App) {
+	if globalLang == langEnglish {
+		return
+	}
+	app.Usage = tr(app.Usage)
+	app.Flags = localizeFlags(app.Flags)
+	app.Commands = localizeCommands(app.Commands)
+	app.CustomAppHelpTemplate = localizeHelpTemplate(app.CustomAppHelpTemplate)
+}
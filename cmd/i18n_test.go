@@ -0,0 +1,112 @@
+/*
+ * Minio Client (C) 2016 Minio, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"github.com/minio/cli"
+	. "gopkg.in/check.v1"
+)
+
+func (s *TestSuite) TestParseLang(c *C) {
+	testCases := []struct {
+		locale string
+		lang   string
+	}{
+		{"de_DE.UTF-8", "de"},
+		{"de", "de"},
+		{"DE", "de"},
+		{"en_US", "en"},
+		{"pt-BR", "pt"},
+		{"sr@latin", "sr"},
+		{"C", ""},
+		{"POSIX", ""},
+		{"", ""},
+	}
+	for _, testCase := range testCases {
+		c.Assert(parseLang(testCase.locale), Equals, testCase.lang, Commentf("locale %q", testCase.locale))
+	}
+}
+
+func (s *TestSuite) TestLangFromArgs(c *C) {
+	testCases := []struct {
+		args []string
+		lang string
+		ok   bool
+	}{
+		{[]string{"ls", "play"}, "", false},
+		{[]string{"--lang", "de", "ls"}, "de", true},
+		{[]string{"ls", "--lang=de", "play"}, "de", true},
+		{[]string{"-lang", "en", "ls"}, "en", true},
+		{[]string{"ls", "--", "--lang", "de"}, "", false},
+		{[]string{"ls", "--lang"}, "", false},
+	}
+	for _, testCase := range testCases {
+		lang, ok := langFromArgs(testCase.args)
+		c.Assert(lang, Equals, testCase.lang, Commentf("args %q", testCase.args))
+		c.Assert(ok, Equals, testCase.ok, Commentf("args %q", testCase.args))
+	}
+}
+
+func (s *TestSuite) TestSetLang(c *C) {
+	defer func() { globalLang = langEnglish }()
+
+	c.Assert(setLang([]string{"--lang", "de_DE.UTF-8"}), IsNil)
+	c.Assert(globalLang, Equals, "de")
+	c.Assert(setLang([]string{"--lang", "en"}), IsNil)
+	c.Assert(globalLang, Equals, langEnglish)
+	c.Assert(setLang([]string{"--lang", "xx"}), NotNil)
+	c.Assert(globalLang, Equals, langEnglish)
+}
+
+func (s *TestSuite) TestTranslate(c *C) {
+	defer func() { globalLang = langEnglish }()
+
+	globalLang = langEnglish
+	c.Assert(tr("Removed ‘a’."), Equals, "Removed ‘a’.")
+
+	globalLang = "de"
+	// Exact messages.
+	c.Assert(tr("Operation was not confirmed."), Equals, "Die Operation wurde nicht bestätigt.")
+	// Formats.
+	c.Assert(trf("Removed ‘%s’.", "play/bucket/a"), Equals, "‘play/bucket/a’ entfernt.")
+	c.Assert(trf("Removed %d objects, %d incomplete uploads and bucket ‘%s’.", 3, 1, "play/bucket"), Equals, "3 Objekte, 1 unvollständige Uploads und Bucket ‘play/bucket’ entfernt.")
+	// Messages built by concatenation.
+	c.Assert(tr("Unable to remove ‘play/bucket/a’."), Equals, "‘play/bucket/a’ kann nicht entfernt werden.")
+	c.Assert(tr("Removed 3 objects, 1 incomplete uploads and bucket ‘play/bucket’."), Equals, "3 Objekte, 1 unvollständige Uploads und Bucket ‘play/bucket’ entfernt.")
+	// Untranslated messages.
+	c.Assert(tr("Unknown message ‘a’."), Equals, "Unknown message ‘a’.")
+	c.Assert(tr(""), Equals, "")
+}
+
+func (s *TestSuite) TestLocalizeCommands(c *C) {
+	defer func() { globalLang = langEnglish }()
+	globalLang = "de"
+
+	cmds := []cli.Command{{
+		Name:               "rm",
+		Usage:              "Remove file or bucket [WARNING: Use with care].",
+		Flags:              []cli.Flag{cli.BoolFlag{Name: "recursive, r", Usage: "Remove recursively."}, cli.StringFlag{Name: "older", Usage: "Unknown."}},
+		CustomHelpTemplate: "NAME:\n   mc {{.Name}}\n\nFLAGS:\n  {{range .Flags}}{{.}}\n  {{end}}",
+	}}
+	localized := localizeCommands(cmds)
+	c.Assert(localized[0].Usage, Equals, "Datei oder Bucket entfernen [WARNUNG: Mit Vorsicht verwenden].")
+	c.Assert(localized[0].Flags[0].(cli.BoolFlag).Usage, Equals, "Rekursiv entfernen.")
+	c.Assert(localized[0].Flags[1].(cli.StringFlag).Usage, Equals, "Unknown.")
+	c.Assert(localized[0].CustomHelpTemplate, Equals, "NAME:\n   mc {{.Name}}\n\nOPTIONEN:\n  {{range .Flags}}{{.}}\n  {{end}}")
+	// Commands are copied, English help is kept.
+	c.Assert(cmds[0].Flags[0].(cli.BoolFlag).Usage, Equals, "Remove recursively.")
+}
@@ -0,0 +1,210 @@
+/*
+ * Minio Client (C) 2016 Minio, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"encoding/json"
+	"errors"
+	"time"
+
+	"github.com/fatih/color"
+	"github.com/minio/cli"
+	"github.com/minio/mc/pkg/console"
+	"github.com/minio/minio/pkg/probe"
+)
+
+var (
+	ilmAddFlags = []cli.Flag{
+		cli.StringFlag{
+			Name:  "id",
+			Usage: "ID of the rule, an existing rule with the same ID is replaced. Defaults to a random ID.",
+		},
+		cli.StringFlag{
+			Name:  "prefix",
+			Usage: "Apply the rule to objects with this prefix. Defaults to all objects.",
+		},
+		cli.IntFlag{
+			Name:  "expiry-days",
+			Usage: "Expire objects after this number of days.",
+		},
+		cli.StringFlag{
+			Name:  "expiry-date",
+			Usage: "Expire objects on this date, formatted as YYYY-MM-DD.",
+		},
+		cli.IntFlag{
+			Name:  "transition-days",
+			Usage: "Transition objects after this number of days.",
+		},
+		cli.StringFlag{
+			Name:  "transition-date",
+			Usage: "Transition objects on this date, formatted as YYYY-MM-DD.",
+		},
+		cli.StringFlag{
+			Name:  "storage-class",
+			Value: "GLACIER",
+			Usage: "Storage class objects are transitioned to.",
+		},
+		cli.BoolFlag{
+			Name:  "disable",
+			Usage: "Add the rule disabled.",
+		},
+	}
+)
+
+var ilmAddCmd = cli.Command{
+	Name:   "add",
+	Usage:  "Add a lifecycle rule to a bucket.",
+	Action: mainILMAdd,
+	Flags:  append(ilmAddFlags, globalFlags...),
+	CustomHelpTemplate: `NAME:
+   mc ilm {{.Name}} - {{.Usage}}
+
+USAGE:
+   mc ilm {{.Name}} ALIAS/BUCKET [FLAGS]
+
+FLAGS:
+  {{range .Flags}}{{.}}
+  {{end}}
+EXAMPLES:
+   1. Expire objects under logs/ after 90 days.
+     $ mc ilm {{.Name}} --prefix logs/ --expiry-days 90 s3/mybucket
+   2. Transition objects under backups/ to Glacier after 30 days, and expire them after a year.
+     $ mc ilm {{.Name}} --id backups --prefix backups/ --transition-days 30 --expiry-days 365 s3/mybucket
+   3. Expire all objects of a bucket on a date.
+     $ mc ilm {{.Name}} --expiry-date 2017-01-01 s3/mybucket
+`,
+}
+
+// Date format of lifecycle rules on the command line.
+const ilmDateFormat = "2006-01-02"
+
+// checkILMAddSyntax - validate all the passed arguments
+func checkILMAddSyntax(ctx *cli.Context) {
+	if len(ctx.Args()) != 1 {
+		cli.ShowCommandHelpAndExit(ctx, "add", 1) // last argument is exit code
+	}
+	if _, err := ilmRuleFromContext(ctx); err != nil {
+		fatalIf(err.Trace(ctx.Args()...), "Invalid lifecycle rule.")
+	}
+}
+
+// ilmDate - converts YYYY-MM-DD to the midnight UTC timestamp
+// required by lifecycle rules.
+func ilmDate(date string) (string, *probe.Error) {
+	t, e := time.Parse(ilmDateFormat, date)
+	if e != nil {
+		return "", probe.NewError(e)
+	}
+	return t.UTC().Format(time.RFC3339), nil
+}
+
+// ilmRuleFromContext - builds a lifecycle rule out of the flags.
+func ilmRuleFromContext(ctx *cli.Context) (lifecycleRule, *probe.Error) {
+	rule := lifecycleRule{
+		ID:     ctx.String("id"),
+		Filter: lifecycleFilter{Prefix: ctx.String("prefix")},
+		Status: "Enabled",
+	}
+	if rule.ID == "" {
+		rule.ID = newRandomID(20)
+	}
+	if ctx.Bool("disable") {
+		rule.Status = "Disabled"
+	}
+
+	expiryDays, expiryDate := ctx.Int("expiry-days"), ctx.String("expiry-date")
+	transitionDays, transitionDate := ctx.Int("transition-days"), ctx.String("transition-date")
+	if (expiryDays != 0 && expiryDate != "") || (transitionDays != 0 && transitionDate != "") {
+		return rule, probe.NewError(errors.New("Days and date are mutually exclusive."))
+	}
+	if expiryDays < 0 || transitionDays < 0 {
+		return rule, probe.NewError(errors.New("Days must be positive."))
+	}
+	if expiryDays == 0 && expiryDate == "" && transitionDays == 0 && transitionDate == "" {
+		return rule, probe.NewError(errors.New("Rule needs an expiry or a transition."))
+	}
+
+	if expiryDays != 0 || expiryDate != "" {
+		rule.Expiration = &lifecycleExpiration{Days: expiryDays}
+		if expiryDate != "" {
+			date, err := ilmDate(expiryDate)
+			if err != nil {
+				return rule, err.Trace(expiryDate)
+			}
+			rule.Expiration.Date = date
+		}
+	}
+	if transitionDays != 0 || transitionDate != "" {
+		rule.Transition = &lifecycleTransition{Days: transitionDays, StorageClass: ctx.String("storage-class")}
+		if transitionDate != "" {
+			date, err := ilmDate(transitionDate)
+			if err != nil {
+				return rule, err.Trace(transitionDate)
+			}
+			rule.Transition.Date = date
+		}
+	}
+	return rule, nil
+}
+
+// ilmAddMessage container
+type ilmAddMessage struct {
+	Status string `json:"status"`
+	ID     string `json:"id"`
+	Target string `json:"target"`
+}
+
+func (i ilmAddMessage) JSON() string {
+	i.Status = "success"
+	ilmAddMessageJSONBytes, e := json.Marshal(i)
+	fatalIf(probe.NewError(e), "Unable to marshal into JSON.")
+	return string(ilmAddMessageJSONBytes)
+}
+
+func (i ilmAddMessage) String() string {
+	return console.Colorize("ILM", "Lifecycle rule ‘"+i.ID+"’ added to ‘"+i.Target+"’.")
+}
+
+func mainILMAdd(ctx *cli.Context) {
+	console.SetColor("ILM", color.New(color.FgGreen, color.Bold))
+
+	setGlobalsFromContext(ctx)
+	checkILMAddSyntax(ctx)
+
+	path := ctx.Args().First()
+	rule, _ := ilmRuleFromContext(ctx)
+
+	client, err := newS3Client(path)
+	fatalIf(err, "Unable to initialize ‘"+path+"’, lifecycle is only supported on object storage.")
+
+	lifecycle, err := client.GetLifecycle()
+	fatalIf(err.Trace(path), "Unable to get lifecycle rules of ‘"+path+"’.")
+
+	// Replace a rule with the same ID, if any.
+	rules := []lifecycleRule{}
+	for _, r := range lifecycle.Rules {
+		if r.ID != rule.ID {
+			rules = append(rules, r)
+		}
+	}
+	lifecycle.Rules = append(rules, rule)
+
+	err = client.SetLifecycle(lifecycle)
+	fatalIf(err.Trace(path), "Unable to set lifecycle rules of ‘"+path+"’.")
+
+	printMsg(ilmAddMessage{ID: rule.ID, Target: path})
+}
@@ -0,0 +1,76 @@
+/*
+ * Minio Client (C) 2016 Minio, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/minio/cli"
+	"github.com/minio/minio/pkg/probe"
+)
+
+var (
+	ilmExportFlags = []cli.Flag{}
+)
+
+var ilmExportCmd = cli.Command{
+	Name:   "export",
+	Usage:  "Export lifecycle configuration of a bucket as JSON.",
+	Action: mainILMExport,
+	Flags:  append(ilmExportFlags, globalFlags...),
+	CustomHelpTemplate: `NAME:
+   mc ilm {{.Name}} - {{.Usage}}
+
+USAGE:
+   mc ilm {{.Name}} ALIAS/BUCKET [FLAGS]
+
+FLAGS:
+  {{range .Flags}}{{.}}
+  {{end}}
+EXAMPLES:
+   1. Save lifecycle configuration of a bucket to a file.
+     $ mc ilm {{.Name}} s3/mybucket > lifecycle.json
+`,
+}
+
+// checkILMExportSyntax - validate all the passed arguments
+func checkILMExportSyntax(ctx *cli.Context) {
+	if len(ctx.Args()) != 1 {
+		cli.ShowCommandHelpAndExit(ctx, "export", 1) // last argument is exit code
+	}
+}
+
+func mainILMExport(ctx *cli.Context) {
+	setGlobalsFromContext(ctx)
+	checkILMExportSyntax(ctx)
+
+	path := ctx.Args().First()
+	client, err := newS3Client(path)
+	fatalIf(err, "Unable to initialize ‘"+path+"’, lifecycle is only supported on object storage.")
+
+	lifecycle, err := client.GetLifecycle()
+	fatalIf(err.Trace(path), "Unable to get lifecycle rules of ‘"+path+"’.")
+
+	if lifecycle.Rules == nil {
+		lifecycle.Rules = []lifecycleRule{}
+	}
+	// Exported as is, so that it can be imported back.
+	lifecycleBytes, e := json.MarshalIndent(lifecycle, "", " ")
+	fatalIf(probe.NewError(e), "Unable to marshal into JSON.")
+	fmt.Println(string(lifecycleBytes))
+}
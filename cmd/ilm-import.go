@@ -0,0 +1,99 @@
+/*
+ * Minio Client (C) 2016 Minio, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"encoding/json"
+	"os"
+
+	"github.com/fatih/color"
+	"github.com/minio/cli"
+	"github.com/minio/mc/pkg/console"
+	"github.com/minio/minio/pkg/probe"
+)
+
+var (
+	ilmImportFlags = []cli.Flag{}
+)
+
+var ilmImportCmd = cli.Command{
+	Name:   "import",
+	Usage:  "Import lifecycle configuration of a bucket from JSON on standard input.",
+	Action: mainILMImport,
+	Flags:  append(ilmImportFlags, globalFlags...),
+	CustomHelpTemplate: `NAME:
+   mc ilm {{.Name}} - {{.Usage}}
+
+USAGE:
+   mc ilm {{.Name}} ALIAS/BUCKET [FLAGS]
+
+FLAGS:
+  {{range .Flags}}{{.}}
+  {{end}}
+EXAMPLES:
+   1. Replace lifecycle configuration of a bucket with the one saved in a file.
+     $ mc ilm {{.Name}} s3/mybucket < lifecycle.json
+   2. Copy lifecycle configuration from one bucket to another.
+     $ mc ilm export s3/mybucket | mc ilm {{.Name}} s3/otherbucket
+`,
+}
+
+// checkILMImportSyntax - validate all the passed arguments
+func checkILMImportSyntax(ctx *cli.Context) {
+	if len(ctx.Args()) != 1 {
+		cli.ShowCommandHelpAndExit(ctx, "import", 1) // last argument is exit code
+	}
+}
+
+// ilmImportMessage container
+type ilmImportMessage struct {
+	Status string `json:"status"`
+	Target string `json:"target"`
+	Rules  int    `json:"rules"`
+}
+
+func (i ilmImportMessage) JSON() string {
+	i.Status = "success"
+	ilmImportMessageJSONBytes, e := json.Marshal(i)
+	fatalIf(probe.NewError(e), "Unable to marshal into JSON.")
+	return string(ilmImportMessageJSONBytes)
+}
+
+func (i ilmImportMessage) String() string {
+	return console.Colorize("ILM", "Lifecycle configuration of ‘"+i.Target+"’ replaced.")
+}
+
+func mainILMImport(ctx *cli.Context) {
+	console.SetColor("ILM", color.New(color.FgGreen, color.Bold))
+
+	setGlobalsFromContext(ctx)
+	checkILMImportSyntax(ctx)
+
+	path := ctx.Args().First()
+
+	lifecycle := lifecycleConfiguration{}
+	e := json.NewDecoder(os.Stdin).Decode(&lifecycle)
+	fatalIf(probe.NewError(e), "Unable to parse lifecycle configuration from standard input.")
+
+	client, err := newS3Client(path)
+	fatalIf(err, "Unable to initialize ‘"+path+"’, lifecycle is only supported on object storage.")
+
+	err = client.SetLifecycle(lifecycle)
+	fatalIf(err.Trace(path), "Unable to set lifecycle rules of ‘"+path+"’.")
+
+	printMsg(ilmImportMessage{Target: path, Rules: len(lifecycle.Rules)})
+}
@@ -0,0 +1,126 @@
+/*
+ * Minio Client (C) 2016 Minio, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"strconv"
+
+	"github.com/fatih/color"
+	"github.com/minio/cli"
+	"github.com/minio/mc/pkg/console"
+	"github.com/minio/minio/pkg/probe"
+)
+
+var (
+	ilmListFlags = []cli.Flag{}
+)
+
+var ilmListCmd = cli.Command{
+	Name:   "list",
+	Usage:  "List lifecycle rules of a bucket.",
+	Action: mainILMList,
+	Flags:  append(ilmListFlags, globalFlags...),
+	CustomHelpTemplate: `NAME:
+   mc ilm {{.Name}} - {{.Usage}}
+
+USAGE:
+   mc ilm {{.Name}} ALIAS/BUCKET [FLAGS]
+
+FLAGS:
+  {{range .Flags}}{{.}}
+  {{end}}
+EXAMPLES:
+   1. List lifecycle rules of a bucket.
+     $ mc ilm {{.Name}} s3/mybucket
+   2. List lifecycle rules of a bucket in JSON form.
+     $ mc ilm {{.Name}} --json s3/mybucket
+`,
+}
+
+// checkILMListSyntax - validate all the passed arguments
+func checkILMListSyntax(ctx *cli.Context) {
+	if len(ctx.Args()) != 1 {
+		cli.ShowCommandHelpAndExit(ctx, "list", 1) // last argument is exit code
+	}
+}
+
+// Row format of the lifecycle rules table.
+const ilmRowFormat = "%-20s %-24s %-9s %-12s %s"
+
+// ilmListMessage container for a lifecycle rule.
+type ilmListMessage struct {
+	Status string        `json:"status"`
+	Rule   lifecycleRule `json:"rule"`
+}
+
+func (i ilmListMessage) JSON() string {
+	i.Status = "success"
+	ilmListMessageJSONBytes, e := json.Marshal(i)
+	fatalIf(probe.NewError(e), "Unable to marshal into JSON.")
+	return string(ilmListMessageJSONBytes)
+}
+
+func (i ilmListMessage) String() string {
+	expiry := "-"
+	if i.Rule.Expiration != nil {
+		expiry = ilmDaysOrDate(i.Rule.Expiration.Days, i.Rule.Expiration.Date)
+	}
+	transition := "-"
+	if i.Rule.Transition != nil {
+		transition = ilmDaysOrDate(i.Rule.Transition.Days, i.Rule.Transition.Date) + " -> " + i.Rule.Transition.StorageClass
+	}
+	prefix := i.Rule.Filter.Prefix
+	if prefix == "" {
+		prefix = "*"
+	}
+	return console.Colorize("ILMRule", fmt.Sprintf(ilmRowFormat, i.Rule.ID, prefix, i.Rule.Status, expiry, transition))
+}
+
+// ilmDaysOrDate - human readable form of days or date of a rule.
+func ilmDaysOrDate(days int, date string) string {
+	if date != "" {
+		if len(date) >= len(ilmDateFormat) {
+			return date[:len(ilmDateFormat)]
+		}
+		return date
+	}
+	return strconv.Itoa(days) + " days"
+}
+
+func mainILMList(ctx *cli.Context) {
+	console.SetColor("ILMHeader", color.New(color.Bold))
+	console.SetColor("ILMRule", color.New(color.FgCyan))
+
+	setGlobalsFromContext(ctx)
+	checkILMListSyntax(ctx)
+
+	path := ctx.Args().First()
+	client, err := newS3Client(path)
+	fatalIf(err, "Unable to initialize ‘"+path+"’, lifecycle is only supported on object storage.")
+
+	lifecycle, err := client.GetLifecycle()
+	fatalIf(err.Trace(path), "Unable to get lifecycle rules of ‘"+path+"’.")
+
+	if !globalJSON && len(lifecycle.Rules) > 0 {
+		console.Println(console.Colorize("ILMHeader", fmt.Sprintf(ilmRowFormat, "ID", "PREFIX", "STATUS", "EXPIRY", "TRANSITION")))
+	}
+	for _, rule := range lifecycle.Rules {
+		printMsg(ilmListMessage{Rule: rule})
+	}
+}
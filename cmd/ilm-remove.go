@@ -0,0 +1,131 @@
+/*
+ * Minio Client (C) 2016 Minio, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"encoding/json"
+
+	"github.com/fatih/color"
+	"github.com/minio/cli"
+	"github.com/minio/mc/pkg/console"
+	"github.com/minio/minio/pkg/probe"
+)
+
+var (
+	ilmRemoveFlags = []cli.Flag{
+		cli.StringFlag{
+			Name:  "id",
+			Usage: "ID of the rule to remove.",
+		},
+		cli.BoolFlag{
+			Name:  "all",
+			Usage: "Remove all lifecycle rules.",
+		},
+	}
+)
+
+var ilmRemoveCmd = cli.Command{
+	Name:   "remove",
+	Usage:  "Remove lifecycle rules of a bucket.",
+	Action: mainILMRemove,
+	Flags:  append(ilmRemoveFlags, globalFlags...),
+	CustomHelpTemplate: `NAME:
+   mc ilm {{.Name}} - {{.Usage}}
+
+USAGE:
+   mc ilm {{.Name}} ALIAS/BUCKET [FLAGS]
+
+FLAGS:
+  {{range .Flags}}{{.}}
+  {{end}}
+EXAMPLES:
+   1. Remove a lifecycle rule by its ID.
+     $ mc ilm {{.Name}} --id backups s3/mybucket
+   2. Remove all lifecycle rules of a bucket.
+     $ mc ilm {{.Name}} --all s3/mybucket
+`,
+}
+
+// checkILMRemoveSyntax - validate all the passed arguments
+func checkILMRemoveSyntax(ctx *cli.Context) {
+	if len(ctx.Args()) != 1 {
+		cli.ShowCommandHelpAndExit(ctx, "remove", 1) // last argument is exit code
+	}
+	if (ctx.String("id") == "") == !ctx.Bool("all") {
+		fatalIf(errInvalidArgument().Trace(ctx.Args()...), "Either --id or --all must be specified.")
+	}
+}
+
+// ilmRemoveMessage container
+type ilmRemoveMessage struct {
+	Status string `json:"status"`
+	ID     string `json:"id,omitempty"`
+	Target string `json:"target"`
+}
+
+func (i ilmRemoveMessage) JSON() string {
+	i.Status = "success"
+	ilmRemoveMessageJSONBytes, e := json.Marshal(i)
+	fatalIf(probe.NewError(e), "Unable to marshal into JSON.")
+	return string(ilmRemoveMessageJSONBytes)
+}
+
+func (i ilmRemoveMessage) String() string {
+	if i.ID == "" {
+		return console.Colorize("ILM", "All lifecycle rules removed from ‘"+i.Target+"’.")
+	}
+	return console.Colorize("ILM", "Lifecycle rule ‘"+i.ID+"’ removed from ‘"+i.Target+"’.")
+}
+
+func mainILMRemove(ctx *cli.Context) {
+	console.SetColor("ILM", color.New(color.FgGreen, color.Bold))
+
+	setGlobalsFromContext(ctx)
+	checkILMRemoveSyntax(ctx)
+
+	path := ctx.Args().First()
+	id := ctx.String("id")
+
+	client, err := newS3Client(path)
+	fatalIf(err, "Unable to initialize ‘"+path+"’, lifecycle is only supported on object storage.")
+
+	if ctx.Bool("all") {
+		err = client.RemoveLifecycle()
+		fatalIf(err.Trace(path), "Unable to remove lifecycle rules of ‘"+path+"’.")
+		printMsg(ilmRemoveMessage{Target: path})
+		return
+	}
+
+	lifecycle, err := client.GetLifecycle()
+	fatalIf(err.Trace(path), "Unable to get lifecycle rules of ‘"+path+"’.")
+
+	rules := []lifecycleRule{}
+	for _, rule := range lifecycle.Rules {
+		if rule.ID != id {
+			rules = append(rules, rule)
+		}
+	}
+	if len(rules) == len(lifecycle.Rules) {
+		fatalIf(errDummy().Trace(path, id), "Lifecycle rule ‘"+id+"’ not found on ‘"+path+"’.")
+	}
+	lifecycle.Rules = rules
+
+	err = client.SetLifecycle(lifecycle)
+	fatalIf(err.Trace(path), "Unable to set lifecycle rules of ‘"+path+"’.")
+
+	printMsg(ilmRemoveMessage{ID: id, Target: path})
+}
@@ -0,0 +1,77 @@
+/*
+ * Minio Client (C) 2016 Minio, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"github.com/fatih/color"
+	"github.com/minio/cli"
+	"github.com/minio/mc/pkg/console"
+)
+
+var (
+	legalHoldInfoFlags = []cli.Flag{
+		cli.StringFlag{
+			Name:  "version-id",
+			Usage: "Show legal hold of a specific version of the object.",
+		},
+	}
+)
+
+var legalHoldInfoCmd = cli.Command{
+	Name:   "info",
+	Usage:  "Show legal hold of an object.",
+	Action: mainLegalHoldInfo,
+	Flags:  append(legalHoldInfoFlags, globalFlags...),
+	CustomHelpTemplate: `NAME:
+   mc legalhold {{.Name}} - {{.Usage}}
+
+USAGE:
+   mc legalhold {{.Name}} ALIAS/BUCKET/OBJECT [FLAGS]
+
+FLAGS:
+  {{range .Flags}}{{.}}
+  {{end}}
+EXAMPLES:
+   1. Show legal hold of an object.
+     $ mc legalhold {{.Name}} s3/records/2016/ledger.csv
+`,
+}
+
+// checkLegalHoldInfoSyntax - validate all the passed arguments
+func checkLegalHoldInfoSyntax(ctx *cli.Context) {
+	if len(ctx.Args()) != 1 {
+		cli.ShowCommandHelpAndExit(ctx, "info", 1) // last argument is exit code
+	}
+}
+
+func mainLegalHoldInfo(ctx *cli.Context) {
+	console.SetColor("LegalHold", color.New(color.FgCyan, color.Bold))
+
+	setGlobalsFromContext(ctx)
+	checkLegalHoldInfoSyntax(ctx)
+
+	path := ctx.Args().First()
+	versionID := ctx.String("version-id")
+
+	client, err := newS3Client(path)
+	fatalIf(err, "Unable to initialize ‘"+path+"’, legal hold is only supported on object storage.")
+
+	status, err := client.GetObjectLegalHold(versionID)
+	fatalIf(err.Trace(path), "Unable to get legal hold of ‘"+path+"’.")
+
+	printMsg(legalHoldMessage{Target: path, VersionID: versionID, LegalHold: status})
+}
@@ -0,0 +1,108 @@
+/*
+ * Minio Client (C) 2016 Minio, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"encoding/json"
+	"strings"
+
+	"github.com/fatih/color"
+	"github.com/minio/cli"
+	"github.com/minio/mc/pkg/console"
+	"github.com/minio/minio/pkg/probe"
+)
+
+var (
+	legalHoldSetFlags = []cli.Flag{
+		cli.StringFlag{
+			Name:  "version-id",
+			Usage: "Set legal hold of a specific version of the object.",
+		},
+	}
+)
+
+var legalHoldSetCmd = cli.Command{
+	Name:   "set",
+	Usage:  "Turn legal hold of an object on or off.",
+	Action: mainLegalHoldSet,
+	Flags:  append(legalHoldSetFlags, globalFlags...),
+	CustomHelpTemplate: `NAME:
+   mc legalhold {{.Name}} - {{.Usage}}
+
+USAGE:
+   mc legalhold {{.Name}} ALIAS/BUCKET/OBJECT on|off [FLAGS]
+
+FLAGS:
+  {{range .Flags}}{{.}}
+  {{end}}
+EXAMPLES:
+   1. Put an object on legal hold.
+     $ mc legalhold {{.Name}} s3/records/2016/ledger.csv on
+   2. Release legal hold of an object.
+     $ mc legalhold {{.Name}} s3/records/2016/ledger.csv off
+`,
+}
+
+// checkLegalHoldSetSyntax - validate all the passed arguments
+func checkLegalHoldSetSyntax(ctx *cli.Context) {
+	if len(ctx.Args()) != 2 {
+		cli.ShowCommandHelpAndExit(ctx, "set", 1) // last argument is exit code
+	}
+	switch strings.ToUpper(ctx.Args().Get(1)) {
+	case legalHoldOn, legalHoldOff:
+	default:
+		fatalIf(errInvalidArgument().Trace(ctx.Args()...), "Legal hold can only be turned ‘on’ or ‘off’.")
+	}
+}
+
+// legalHoldMessage container, shared by set and info.
+type legalHoldMessage struct {
+	Status    string `json:"status"`
+	Target    string `json:"target"`
+	VersionID string `json:"versionId,omitempty"`
+	LegalHold string `json:"legalHold"`
+}
+
+func (l legalHoldMessage) JSON() string {
+	l.Status = "success"
+	legalHoldMessageJSONBytes, e := json.Marshal(l)
+	fatalIf(probe.NewError(e), "Unable to marshal into JSON.")
+	return string(legalHoldMessageJSONBytes)
+}
+
+func (l legalHoldMessage) String() string {
+	return console.Colorize("LegalHold", "Legal hold of ‘"+l.Target+"’ is "+strings.ToLower(l.LegalHold)+".")
+}
+
+func mainLegalHoldSet(ctx *cli.Context) {
+	console.SetColor("LegalHold", color.New(color.FgGreen, color.Bold))
+
+	setGlobalsFromContext(ctx)
+	checkLegalHoldSetSyntax(ctx)
+
+	path := ctx.Args().First()
+	status := strings.ToUpper(ctx.Args().Get(1))
+	versionID := ctx.String("version-id")
+
+	client, err := newS3Client(path)
+	fatalIf(err, "Unable to initialize ‘"+path+"’, legal hold is only supported on object storage.")
+
+	err = client.PutObjectLegalHold(versionID, status)
+	fatalIf(err.Trace(path), "Unable to set legal hold of ‘"+path+"’.")
+
+	printMsg(legalHoldMessage{Target: path, VersionID: versionID, LegalHold: status})
+}
@@ -0,0 +1,311 @@
+/*
+ * Minio Client (C) 2016 Minio, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"bufio"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/minio/cli"
+	"github.com/minio/minio/pkg/probe"
+)
+
+// Flags of commands reusing recent listings.
+var listingCacheFlags = []cli.Flag{
+	cli.StringFlag{
+		Name:   "cache-ttl",
+		Usage:  "Reuse listings of buckets taken by earlier runs within the given duration, e.g. ‘10m’.",
+		EnvVar: "MC_LIST_CACHE_TTL",
+	},
+	cli.BoolFlag{
+		Name:  "no-cache",
+		Usage: "Neither reuse nor keep listings, whatever the cache TTL.",
+	},
+}
+
+// Version of the format of listing snapshots, snapshots of other
+// versions are never read.
+const listingSnapshotVersion = "1"
+
+// listingCache - keeps recursive listings of folders on object
+// storage on disk, so that repeated runs of diff and mirror reuse
+// listings younger than the TTL. Snapshots are keyed by alias, URL and
+// the generation of the bucket, commands modifying a bucket bump its
+// generation, which retires all of its snapshots. A nil cache lists
+// every time.
+type listingCache struct {
+	dir string
+	ttl time.Duration
+}
+
+// listingSnapshotHeader - first line of a snapshot.
+type listingSnapshotHeader struct {
+	URL     string    `json:"url"`
+	Created time.Time `json:"created"`
+}
+
+// listingSnapshotEntry - a listed content, one per line of a snapshot.
+type listingSnapshotEntry struct {
+	URL          string      `json:"url"`
+	Time         time.Time   `json:"time"`
+	Size         int64       `json:"size"`
+	Type         os.FileMode `json:"type"`
+	StorageClass string      `json:"storageClass,omitempty"`
+	ETag         string      `json:"etag,omitempty"`
+}
+
+// getListingCacheDir - get listing cache directory.
+func getListingCacheDir() (string, *probe.Error) {
+	configDir, err := getMcConfigDir()
+	if err != nil {
+		return "", err.Trace()
+	}
+	return filepath.Join(configDir, globalListingCacheDir), nil
+}
+
+// parseCacheTTL - parses the TTL of listings like ‘10m’, listings are
+// not cached without a TTL or with noCache set.
+func parseCacheTTL(ttl string, noCache bool) (time.Duration, *probe.Error) {
+	if ttl == "" || noCache {
+		return 0, nil
+	}
+	d, e := time.ParseDuration(ttl)
+	if e != nil || d < 0 {
+		return 0, errInvalidCacheTTL(ttl).Trace(ttl)
+	}
+	return d, nil
+}
+
+// newListingCache - cache of listings in the mc config folder, nil if
+// listings are not cached.
+func newListingCache(ttl string, noCache bool) (*listingCache, *probe.Error) {
+	d, err := parseCacheTTL(ttl, noCache)
+	if err != nil || d == 0 {
+		return nil, err
+	}
+	dir, err := getListingCacheDir()
+	if err != nil {
+		return nil, err.Trace()
+	}
+	if e := os.MkdirAll(dir, 0700); e != nil {
+		return nil, probe.NewError(e)
+	}
+	return &listingCache{dir: dir, ttl: d}, nil
+}
+
+// listingBucket - host and bucket of a URL, the scope of generations.
+func listingBucket(alias string, u ClientURL) string {
+	bucket := strings.SplitN(strings.TrimPrefix(u.Path, string(u.Separator)), string(u.Separator), 2)[0]
+	return alias + "\x00" + u.Host + "\x00" + bucket
+}
+
+// hashKey - name of a file in the cache for the given key.
+func hashKey(parts ...string) string {
+	sum := sha256.Sum256([]byte(strings.Join(parts, "\x00")))
+	return hex.EncodeToString(sum[:])
+}
+
+// generationPath - file holding the generation of the bucket of u.
+func (c *listingCache) generationPath(alias string, u ClientURL) string {
+	return filepath.Join(c.dir, hashKey(listingBucket(alias, u))+".gen")
+}
+
+// generation - current generation of the bucket of u, zero for
+// buckets never modified.
+func (c *listingCache) generation(alias string, u ClientURL) uint64 {
+	data, e := ioutil.ReadFile(c.generationPath(alias, u))
+	if e != nil {
+		return 0
+	}
+	gen, e := strconv.ParseUint(strings.TrimSpace(string(data)), 10, 64)
+	if e != nil {
+		return 0
+	}
+	return gen
+}
+
+// snapshotPath - file holding the snapshot of u at the current
+// generation of its bucket.
+func (c *listingCache) snapshotPath(alias string, u ClientURL) string {
+	gen := strconv.FormatUint(c.generation(alias, u), 10)
+	return filepath.Join(c.dir, hashKey(listingSnapshotVersion, alias, u.String(), gen)+".json")
+}
+
+// Invalidate - bumps the generation of the bucket of urlStr, so that
+// no snapshot of it taken before is reused.
+func (c *listingCache) Invalidate(alias, urlStr string) *probe.Error {
+	if c == nil {
+		return nil
+	}
+	u := *newClientURL(urlStr)
+	gen := strconv.FormatUint(c.generation(alias, u)+1, 10)
+	if e := ioutil.WriteFile(c.generationPath(alias, u), []byte(gen), 0600); e != nil {
+		return probe.NewError(e)
+	}
+	return nil
+}
+
+// List - lists the folder of clnt recursively, from a snapshot if a
+// recent one exists. Complete listings without errors are stored as
+// snapshots, local folders are always listed.
+func (c *listingCache) List(ctx context.Context, clnt Client, alias string) <-chan *ClientContent {
+	isRecursive := true
+	isIncomplete := false
+	u := clnt.GetURL()
+	if c == nil || u.Type != ObjectStorage {
+		return clnt.List(ctx, isRecursive, isIncomplete)
+	}
+	snapshotPath := c.snapshotPath(alias, u)
+	if contentCh, ok := c.read(snapshotPath, u); ok {
+		return contentCh
+	}
+
+	listCh := clnt.List(ctx, isRecursive, isIncomplete)
+	contentCh := make(chan *ClientContent)
+	go func() {
+		defer close(contentCh)
+		w := c.newSnapshotWriter(snapshotPath, u)
+		for content := range listCh {
+			if content.Err != nil {
+				w.discard()
+			} else {
+				w.add(content)
+			}
+			contentCh <- content
+		}
+		w.commit()
+	}()
+	return contentCh
+}
+
+// read - streams the contents of the snapshot at snapshotPath, false
+// if there is no snapshot younger than the TTL.
+func (c *listingCache) read(snapshotPath string, u ClientURL) (<-chan *ClientContent, bool) {
+	f, e := os.Open(snapshotPath)
+	if e != nil {
+		return nil, false
+	}
+	reader := bufio.NewReader(f)
+	decoder := json.NewDecoder(reader)
+	var header listingSnapshotHeader
+	if e = decoder.Decode(&header); e != nil || header.URL != u.String() ||
+		time.Since(header.Created) > c.ttl {
+		f.Close()
+		return nil, false
+	}
+
+	contentCh := make(chan *ClientContent)
+	go func() {
+		defer close(contentCh)
+		defer f.Close()
+		for {
+			var entry listingSnapshotEntry
+			if e := decoder.Decode(&entry); e != nil {
+				break
+			}
+			contentCh <- &ClientContent{
+				URL:          *newClientURL(entry.URL),
+				Time:         entry.Time,
+				Size:         entry.Size,
+				Type:         entry.Type,
+				StorageClass: entry.StorageClass,
+				ETag:         entry.ETag,
+			}
+		}
+	}()
+	return contentCh, true
+}
+
+// snapshotWriter - writes a snapshot to a temporary file, which only
+// replaces the snapshot once the listing completed without errors.
+type snapshotWriter struct {
+	path    string
+	f       *os.File
+	w       *bufio.Writer
+	encoder *json.Encoder
+}
+
+// newSnapshotWriter - writer of the snapshot of u, snapshots are
+// silently not taken if the temporary file cannot be created.
+func (c *listingCache) newSnapshotWriter(snapshotPath string, u ClientURL) *snapshotWriter {
+	f, e := ioutil.TempFile(c.dir, "snapshot-")
+	if e != nil {
+		return &snapshotWriter{}
+	}
+	w := bufio.NewWriter(f)
+	s := &snapshotWriter{path: snapshotPath, f: f, w: w, encoder: json.NewEncoder(w)}
+	if e = s.encoder.Encode(listingSnapshotHeader{URL: u.String(), Created: time.Now().UTC()}); e != nil {
+		s.discard()
+	}
+	return s
+}
+
+// add - appends a listed content.
+func (s *snapshotWriter) add(content *ClientContent) {
+	if s.f == nil {
+		return
+	}
+	entry := listingSnapshotEntry{
+		URL:          content.URL.String(),
+		Time:         content.Time,
+		Size:         content.Size,
+		Type:         content.Type,
+		StorageClass: content.StorageClass,
+		ETag:         content.ETag,
+	}
+	if e := s.encoder.Encode(entry); e != nil {
+		s.discard()
+	}
+}
+
+// discard - drops the snapshot.
+func (s *snapshotWriter) discard() {
+	if s.f == nil {
+		return
+	}
+	s.f.Close()
+	os.Remove(s.f.Name())
+	s.f = nil
+}
+
+// commit - replaces the snapshot with the written one.
+func (s *snapshotWriter) commit() {
+	if s.f == nil {
+		return
+	}
+	name := s.f.Name()
+	e := s.w.Flush()
+	if ce := s.f.Close(); e == nil {
+		e = ce
+	}
+	s.f = nil
+	if e == nil {
+		e = os.Rename(name, s.path)
+	}
+	if e != nil {
+		os.Remove(name)
+	}
+}
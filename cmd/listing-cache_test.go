@@ -0,0 +1,120 @@
+/*
+ * Minio Client (C) 2016 Minio, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"context"
+	"io/ioutil"
+	"net/http/httptest"
+	"os"
+	"sync"
+	"time"
+
+	. "gopkg.in/check.v1"
+)
+
+// Test reusing recent listings of buckets.
+func (s *TestSuite) TestListingCache(c *C) {
+	dir, e := ioutil.TempDir(os.TempDir(), "listing-cache-")
+	c.Assert(e, IsNil)
+	defer os.RemoveAll(dir)
+
+	var listTypes []string
+	mutex := &sync.Mutex{}
+	server := httptest.NewServer(listTypeHandler{supportsV2: true, mutex: mutex, listTypes: &listTypes})
+	defer server.Close()
+
+	conf := new(Config)
+	conf.HostURL = server.URL + "/bucket/"
+	conf.AccessKey = "WLGDGYAQYIGI833EV05A"
+	conf.SecretKey = "BYvgJM101sHngl2uzjXS/OBF/aMxAN06JrJ3qJlF"
+	conf.Signature = "S3v4"
+	s3c, err := s3New(conf)
+	c.Assert(err, IsNil)
+
+	listings := func() int {
+		mutex.Lock()
+		defer mutex.Unlock()
+		return len(listTypes)
+	}
+	list := func(cache *listingCache) []*ClientContent {
+		var contents []*ClientContent
+		for content := range cache.List(context.Background(), s3c, "s3") {
+			c.Assert(content.Err, IsNil)
+			contents = append(contents, content)
+		}
+		return contents
+	}
+
+	cache := &listingCache{dir: dir, ttl: time.Hour}
+	listed := list(cache)
+	c.Assert(len(listed), Equals, 1)
+	n := listings()
+
+	// Recent listings are read from the snapshot.
+	cached := list(cache)
+	c.Assert(listings(), Equals, n)
+	c.Assert(len(cached), Equals, 1)
+	c.Assert(cached[0].URL.String(), Equals, listed[0].URL.String())
+	c.Assert(cached[0].Size, Equals, listed[0].Size)
+	c.Assert(cached[0].Time.Equal(listed[0].Time), Equals, true)
+	c.Assert(cached[0].Type, Equals, listed[0].Type)
+
+	// Modified buckets are listed again.
+	c.Assert(cache.Invalidate("s3", conf.HostURL), IsNil)
+	c.Assert(len(list(cache)), Equals, 1)
+	c.Assert(listings(), Equals, n+1)
+	list(cache)
+	c.Assert(listings(), Equals, n+1)
+
+	// Listings of other aliases are not shared.
+	var other []*ClientContent
+	for content := range cache.List(context.Background(), s3c, "other") {
+		other = append(other, content)
+	}
+	c.Assert(len(other), Equals, 1)
+	c.Assert(listings(), Equals, n+2)
+
+	// Snapshots older than the TTL are not reused.
+	list(&listingCache{dir: dir, ttl: time.Nanosecond})
+	c.Assert(listings(), Equals, n+3)
+
+	// Nil caches list every time.
+	list(nil)
+	c.Assert(listings(), Equals, n+4)
+}
+
+// Test parsing the TTL of cached listings.
+func (s *TestSuite) TestParseCacheTTL(c *C) {
+	testCases := []struct {
+		ttl     string
+		noCache bool
+		d       time.Duration
+		isErr   bool
+	}{
+		{"", false, 0, false},
+		{"10m", false, 10 * time.Minute, false},
+		{"10m", true, 0, false},
+		{"-1m", false, 0, true},
+		{"ten", false, 0, true},
+	}
+	for i, testCase := range testCases {
+		d, err := parseCacheTTL(testCase.ttl, testCase.noCache)
+		c.Assert(err != nil, Equals, testCase.isErr, Commentf("Test %d", i+1))
+		c.Assert(d, Equals, testCase.d, Commentf("Test %d", i+1))
+	}
+}
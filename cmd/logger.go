@@ -0,0 +1,284 @@
+/*
+ * Minio Client (C) 2016 Minio, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/dustin/go-humanize"
+	"github.com/minio/mc/pkg/httptracer"
+	"github.com/minio/minio/pkg/probe"
+)
+
+// logLevel - verbosity of the log of the control path of mc, like
+// HTTP requests, as opposed to the output of commands.
+type logLevel int
+
+const (
+	logLevelDebug logLevel = iota
+	logLevelInfo
+	logLevelWarn
+)
+
+// Names of the log levels as set with ‘--log-level’.
+var logLevelNames = []string{"debug", "info", "warn"}
+
+func (l logLevel) String() string {
+	return logLevelNames[l]
+}
+
+// parseLogLevel - parses the name of a log level.
+func parseLogLevel(name string) (logLevel, *probe.Error) {
+	for i, levelName := range logLevelNames {
+		if strings.ToLower(name) == levelName {
+			return logLevel(i), nil
+		}
+	}
+	return 0, errInvalidLogLevel(name).Trace(name)
+}
+
+// logger - writes records of a level and above as lines, as JSON in
+// JSON mode. Records are written to standard error unless a log file
+// is set.
+type logger struct {
+	mutex sync.Mutex
+	level logLevel
+	out   io.Writer
+	// Path of the log file, empty for standard error.
+	path string
+}
+
+// Logger of mc, only warnings are logged by default.
+var globalLogger = &logger{level: logLevelWarn, out: os.Stderr}
+
+// setLogging - sets the level and the file of the log, an existing log
+// file is appended to.
+func setLogging(levelName, path string, debug bool) *probe.Error {
+	level, err := parseLogLevel(levelName)
+	if err != nil {
+		return err.Trace(levelName)
+	}
+	// ‘--debug’ keeps tracing HTTP requests as before.
+	if debug {
+		level = logLevelDebug
+	}
+
+	globalLogger.mutex.Lock()
+	defer globalLogger.mutex.Unlock()
+	globalLogger.level = level
+	if path == globalLogger.path {
+		return nil
+	}
+	out := io.Writer(os.Stderr)
+	if path != "" {
+		file, e := os.OpenFile(path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0600)
+		if e != nil {
+			return probe.NewError(e).Trace(path)
+		}
+		out = file
+	}
+	if closer, ok := globalLogger.out.(io.Closer); ok && globalLogger.out != os.Stderr {
+		closer.Close()
+	}
+	globalLogger.out = out
+	globalLogger.path = path
+	return nil
+}
+
+// enabled - reports whether records of the level are logged.
+func (l *logger) enabled(level logLevel) bool {
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+	return level >= l.level
+}
+
+// log - logs the message with fields given as pairs of keys and values.
+// Values spanning multiple lines, like HTTP traces, follow the line of
+// the record unless logged as JSON.
+func (l *logger) log(level logLevel, msg string, keyvals ...interface{}) {
+	if !l.enabled(level) {
+		return
+	}
+	now := time.Now().UTC()
+
+	var line string
+	if globalJSON {
+		record := map[string]interface{}{
+			"time":    now.Format(time.RFC3339Nano),
+			"level":   level.String(),
+			"message": msg,
+		}
+		for i := 0; i+1 < len(keyvals); i += 2 {
+			record[fmt.Sprint(keyvals[i])] = fmt.Sprint(keyvals[i+1])
+		}
+		recordBytes, e := json.Marshal(record)
+		if e != nil {
+			return
+		}
+		line = string(recordBytes) + "\n"
+	} else {
+		var fields, blocks []string
+		for i := 0; i+1 < len(keyvals); i += 2 {
+			value := fmt.Sprint(keyvals[i+1])
+			if strings.Contains(value, "\n") {
+				blocks = append(blocks, strings.TrimRight(value, "\r\n"))
+				continue
+			}
+			if strings.ContainsAny(value, " \"") {
+				value = fmt.Sprintf("%q", value)
+			}
+			fields = append(fields, fmt.Sprintf("%v=%s", keyvals[i], value))
+		}
+		line = fmt.Sprintf("%s %-5s %s", now.Format("2006-01-02T15:04:05.000Z07:00"), strings.ToUpper(level.String()), msg)
+		if len(fields) > 0 {
+			line += " " + strings.Join(fields, " ")
+		}
+		line += "\n"
+		for _, block := range blocks {
+			line += block + "\n"
+		}
+	}
+
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+	io.WriteString(l.out, line)
+}
+
+// logDebug - logs details like HTTP traces.
+func logDebug(msg string, keyvals ...interface{}) {
+	globalLogger.log(logLevelDebug, msg, keyvals...)
+}
+
+// logInfo - logs the progress of the control path, like HTTP requests.
+func logInfo(msg string, keyvals ...interface{}) {
+	globalLogger.log(logLevelInfo, msg, keyvals...)
+}
+
+// logWarn - logs problems mc recovers from.
+func logWarn(msg string, keyvals ...interface{}) {
+	globalLogger.log(logLevelWarn, msg, keyvals...)
+}
+
+// setTracing - records HTTP requests to the trace file, with bodies up
+// to the limit. The trace file is only set up once.
+func setTracing(path, bodyLimit string) *probe.Error {
+	if path == "" || globalTraceRecorder != nil {
+		return nil
+	}
+	var limit uint64
+	if bodyLimit != "" {
+		var e error
+		if limit, e = humanize.ParseBytes(bodyLimit); e != nil {
+			return probe.NewError(e).Trace(bodyLimit)
+		}
+	}
+	recorder, e := httptracer.NewRecorder(path, int64(limit), "mc", Version)
+	if e != nil {
+		return probe.NewError(e).Trace(path)
+	}
+	globalTraceRecorder = recorder
+	return nil
+}
+
+// Prefix of the IDs of requests, telling runs of mc apart in a log
+// file, and the number of the last request.
+var (
+	requestIDPrefix = newRequestIDPrefix()
+	requestIDSeq    uint64
+)
+
+func newRequestIDPrefix() string {
+	b := make([]byte, 4)
+	if _, e := rand.Read(b); e != nil {
+		return fmt.Sprintf("%08x", os.Getpid())
+	}
+	return hex.EncodeToString(b)
+}
+
+// newRequestID - ID of a new HTTP request, like ‘3f2a91c0-12’.
+func newRequestID() string {
+	return fmt.Sprintf("%s-%d", requestIDPrefix, atomic.AddUint64(&requestIDSeq, 1))
+}
+
+type requestIDKey struct{}
+
+// requestIDOf - ID of the request, empty if it was not logged.
+func requestIDOf(req *http.Request) string {
+	if req == nil {
+		return ""
+	}
+	id, _ := req.Context().Value(requestIDKey{}).(string)
+	return id
+}
+
+// logTransport - logs HTTP requests with their ID, traces of requests
+// and responses are logged at debug level with the same ID.
+type logTransport struct {
+	transport http.RoundTripper
+	tracer    httptracer.HTTPTracer
+}
+
+// newLogTransport - transport logging the requests of the transport.
+func newLogTransport(transport http.RoundTripper, tracer httptracer.HTTPTracer) http.RoundTripper {
+	return logTransport{transport: transport, tracer: tracer}
+}
+
+// RoundTrip - sends the request, logging it once it is answered.
+func (t logTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	id := newRequestID()
+	req = req.WithContext(context.WithValue(req.Context(), requestIDKey{}, id))
+	// Queries of presigned requests hold signatures.
+	url := req.URL.Scheme + "://" + req.URL.Host + req.URL.Path
+
+	start := time.Now()
+	resp, e := t.transport.RoundTrip(req)
+	duration := time.Since(start)
+	if e != nil {
+		logWarn("HTTP request failed.", "id", id, "method", req.Method, "url", url, "duration", duration, "error", e)
+		return resp, e
+	}
+	if t.tracer != nil && globalLogger.enabled(logLevelDebug) {
+		if e = t.tracer.Request(req); e != nil {
+			return nil, e
+		}
+		if e = t.tracer.Response(resp); e != nil {
+			return nil, e
+		}
+	}
+	level := logLevelInfo
+	if resp.StatusCode >= http.StatusInternalServerError {
+		level = logLevelWarn
+	}
+	keyvals := []interface{}{"id", id, "method", req.Method, "url", url, "status", resp.StatusCode, "duration", duration}
+	// The request ID of S3 correlates the request with logs of the host.
+	if amzRequestID := resp.Header.Get("X-Amz-Request-Id"); amzRequestID != "" {
+		keyvals = append(keyvals, "amzRequestId", amzRequestID)
+	}
+	globalLogger.log(level, "HTTP request.", keyvals...)
+	return resp, nil
+}
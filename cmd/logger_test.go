@@ -0,0 +1,115 @@
+/*
+ * Minio Client (C) 2016 Minio, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+
+	. "gopkg.in/check.v1"
+)
+
+func (s *TestSuite) TestParseLogLevel(c *C) {
+	testCases := []struct {
+		name  string
+		level logLevel
+		ok    bool
+	}{
+		{"debug", logLevelDebug, true},
+		{"info", logLevelInfo, true},
+		{"WARN", logLevelWarn, true},
+		{"error", 0, false},
+		{"", 0, false},
+	}
+	for _, testCase := range testCases {
+		level, err := parseLogLevel(testCase.name)
+		c.Assert(err == nil, Equals, testCase.ok, Commentf("level %q", testCase.name))
+		if testCase.ok {
+			c.Assert(level, Equals, testCase.level)
+			c.Assert(level.String(), Equals, strings.ToLower(testCase.name))
+		}
+	}
+}
+
+func (s *TestSuite) TestLogger(c *C) {
+	defer func() { globalJSON = false }()
+
+	var buf bytes.Buffer
+	l := &logger{level: logLevelInfo, out: &buf}
+	l.log(logLevelDebug, "Hidden.")
+	c.Assert(buf.Len(), Equals, 0)
+
+	l.log(logLevelInfo, "HTTP request.", "id", "a-1", "url", "http://host/a b", "trace", "GET / HTTP/1.1\r\nHost: host\r\n\r\n")
+	lines := strings.Split(buf.String(), "\n")
+	c.Assert(lines[0], Matches, `\S+ INFO  HTTP request\. id=a-1 url="http://host/a b"`)
+	c.Assert(lines[1], Equals, "GET / HTTP/1.1\r")
+	c.Assert(lines[2], Equals, "Host: host")
+	c.Assert(lines[3], Equals, "")
+
+	buf.Reset()
+	globalJSON = true
+	l.log(logLevelWarn, "HTTP request failed.", "id", "a-2", "status", 503)
+	var record map[string]string
+	c.Assert(json.Unmarshal(buf.Bytes(), &record), IsNil)
+	c.Assert(record["level"], Equals, "warn")
+	c.Assert(record["message"], Equals, "HTTP request failed.")
+	c.Assert(record["id"], Equals, "a-2")
+	c.Assert(record["status"], Equals, "503")
+}
+
+// requestIDTracer - records request IDs seen by traces.
+type requestIDTracer struct {
+	ids *[]string
+}
+
+func (t requestIDTracer) Request(req *http.Request) error {
+	*t.ids = append(*t.ids, requestIDOf(req))
+	return nil
+}
+
+func (t requestIDTracer) Response(resp *http.Response) error {
+	*t.ids = append(*t.ids, requestIDOf(resp.Request))
+	return nil
+}
+
+func (s *TestSuite) TestLogTransport(c *C) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("X-Amz-Request-Id", "4442587FB7D0A2F9")
+	}))
+	defer server.Close()
+
+	out, level := globalLogger.out, globalLogger.level
+	defer func() { globalLogger.out, globalLogger.level = out, level }()
+	var buf bytes.Buffer
+	globalLogger.out, globalLogger.level = &buf, logLevelDebug
+
+	var ids []string
+	clnt := &http.Client{Transport: newLogTransport(http.DefaultTransport, requestIDTracer{&ids})}
+	resp, e := clnt.Get(server.URL + "/bucket/object?X-Amz-Signature=secret")
+	c.Assert(e, IsNil)
+	resp.Body.Close()
+
+	// Traces are logged with the ID of the request.
+	c.Assert(ids, HasLen, 2)
+	c.Assert(ids[0], Not(Equals), "")
+	c.Assert(ids[1], Equals, ids[0])
+	c.Assert(buf.String(), Matches, `\S+ INFO  HTTP request\. id=`+ids[0]+` method=GET url=`+server.URL+`/bucket/object status=200 duration=\S+ amzRequestId=4442587FB7D0A2F9\n`)
+	c.Assert(strings.Contains(buf.String(), "secret"), Equals, false)
+}
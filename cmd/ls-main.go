@@ -39,6 +39,72 @@ var (
 			Name:  "incomplete, I",
 			Usage: "List incomplete uploads.",
 		},
+		cli.BoolFlag{
+			Name:  "versions",
+			Usage: "List all versions of objects.",
+		},
+		cli.StringSliceFlag{
+			Name:  "include",
+			Value: &cli.StringSlice{},
+			Usage: "Only list files matching the gitignore style pattern, may be repeated.",
+		},
+		cli.StringSliceFlag{
+			Name:  "exclude",
+			Value: &cli.StringSlice{},
+			Usage: "Skip files and folders matching the gitignore style pattern, may be repeated.",
+		},
+		cli.StringFlag{
+			Name:  "newer-than",
+			Usage: "Only list files modified within the given age, e.g. ‘12h’ or ‘7d’.",
+		},
+		cli.StringFlag{
+			Name:  "older-than",
+			Usage: "Only list files modified before the given age, e.g. ‘30d’.",
+		},
+		cli.StringFlag{
+			Name:  "larger-than",
+			Usage: "Only list files larger than the given size, e.g. ‘10MiB’.",
+		},
+		cli.StringFlag{
+			Name:  "smaller-than",
+			Usage: "Only list files smaller than the given size, e.g. ‘1KiB’.",
+		},
+		cli.StringFlag{
+			Name:  "sort",
+			Usage: "Sort entries by ‘name’, ‘size’ or ‘time’, largest and newest first.",
+		},
+		cli.BoolFlag{
+			Name:  "reverse",
+			Usage: "Reverse the order of entries.",
+		},
+		cli.IntFlag{
+			Name:  "max-entries",
+			Usage: "Maximum number of entries listed of each target.",
+		},
+		cli.BoolFlag{
+			Name:  "summarize",
+			Usage: "Display the total number and size of listed objects.",
+		},
+		cli.BoolFlag{
+			Name:  "folder-summary",
+			Usage: "Display the number and size of objects of each folder, folders are only listed when printed.",
+		},
+		cli.IntFlag{
+			Name:  "summary-limit",
+			Value: 10000,
+			Usage: "Maximum number of objects counted of each folder by ‘--folder-summary’, 0 counts all.",
+		},
+		cli.IntFlag{
+			Name:  "bucket-parallel",
+			Value: 8,
+			Usage: "Number of buckets listed at once when listing all buckets recursively.",
+		},
+		cli.StringFlag{
+			Name:  "bucket-order",
+			Value: bucketOrderOrdered,
+			Usage: "Print contents of buckets ‘ordered’ bucket by bucket, or ‘interleaved’ as they are listed.",
+		},
+		outputFlag,
 	}
 )
 
@@ -75,6 +141,33 @@ EXAMPLES:
 
    6. List incomplete (previously failed) uploads of objects on Amazon S3. 
       $ mc {{.Name}} --incomplete s3/mybucket
+
+   7. List all versions of objects, including delete markers, in a versioned bucket on Amazon S3.
+      $ mc {{.Name}} --versions s3/mybucket/
+
+   8. List parquet files recursively, skipping temporary folders.
+      $ mc {{.Name}} --recursive --include "*.parquet" --exclude "_temporary/" s3/datalake/
+
+   9. List files larger than 1GiB modified in the last week.
+      $ mc {{.Name}} --recursive --newer-than 7d --larger-than 1GiB s3/datalake/
+
+  10. List the 10 largest objects of a bucket.
+      $ mc {{.Name}} --recursive --sort size --max-entries 10 s3/datalake/
+
+  11. List the oldest objects of a folder first.
+      $ mc {{.Name}} --sort time --reverse s3/datalake/raw/
+
+  12. Count all objects of a bucket along with their total size.
+      $ mc {{.Name}} --recursive --summarize s3/datalake/
+
+  13. List objects of a bucket as CSV, for a spreadsheet.
+      $ mc {{.Name}} --recursive --output csv s3/datalake/ > datalake.csv
+
+  14. List all buckets recursively, 32 buckets at once, printing objects as they are listed.
+      $ mc {{.Name}} --recursive --bucket-parallel 32 --bucket-order interleaved s3
+
+  15. Explore a large bucket folder by folder, along with the number and size of objects of each folder.
+      $ mc {{.Name}} --folder-summary s3/datalake/
 `,
 }
 
@@ -91,11 +184,37 @@ func checkListSyntax(ctx *cli.Context) {
 	}
 	// extract URLs.
 	URLs := ctx.Args()
+	if _, err := newContentFilter(filterOptsFromContext(ctx)); err != nil {
+		fatalIf(err.Trace(), "Unable to parse filters.")
+	}
+	if _, err := parseListOrder(ctx.String("sort"), ctx.Bool("reverse"), ctx.Int("max-entries")); err != nil {
+		fatalIf(err.Trace(), "Unable to parse sort order, ‘--sort’ has to be ‘name’, ‘size’ or ‘time’ and ‘--max-entries’ not negative.")
+	}
+	if _, err := parseBucketListOpts(ctx.Int("bucket-parallel"), ctx.String("bucket-order")); err != nil {
+		fatalIf(err.Trace(), "Unable to parse bucket listing, ‘--bucket-parallel’ has to be not negative and ‘--bucket-order’ ‘ordered’ or ‘interleaved’.")
+	}
+	if ctx.Bool("folder-summary") {
+		if ctx.Bool("recursive") || ctx.Bool("incomplete") || ctx.Bool("versions") {
+			fatalIf(errInvalidArgument().Trace(URLs...), "--folder-summary cannot be used with --recursive, --incomplete or --versions.")
+		}
+		if ctx.Int("summary-limit") < 0 {
+			fatalIf(errInvalidArgument().Trace(URLs...), "--summary-limit cannot be negative.")
+		}
+	}
+	checkOutputSyntax(ctx)
+	if ctx.Bool("summarize") && ctx.String("output") == outputCSV {
+		fatalIf(errInvalidArgument().Trace(URLs...), "--summarize cannot be used with CSV output.")
+	}
 	isIncomplete := ctx.Bool("incomplete")
+	isVersions := ctx.Bool("versions")
+	if isIncomplete && isVersions {
+		fatalIf(errInvalidArgument().Trace(URLs...), "--incomplete and --versions cannot be used together.")
+	}
 
 	for _, url := range URLs {
 		_, _, err := url2Stat(url)
-		if err != nil && !isURLPrefixExists(url, isIncomplete) {
+		// Objects which are only left with delete markers are still listed with versions.
+		if err != nil && !isURLPrefixExists(url, isIncomplete) && !isVersions {
 			// Bucket name empty is a valid error for 'ls myminio',
 			// treat it as such.
 			if _, ok := err.ToGoError().(BucketNameEmpty); ok {
@@ -113,6 +232,9 @@ func mainList(ctx *cli.Context) {
 	console.SetColor("Dir", color.New(color.FgCyan, color.Bold))
 	console.SetColor("Size", color.New(color.FgYellow))
 	console.SetColor("Time", color.New(color.FgGreen))
+	console.SetColor("Version", color.New(color.FgMagenta))
+	console.SetColor("DeleteMarker", color.New(color.FgRed))
+	console.SetColor("Summary", color.New(color.FgGreen, color.Bold))
 
 	// Set global flags from context.
 	setGlobalsFromContext(ctx)
@@ -123,6 +245,13 @@ func mainList(ctx *cli.Context) {
 	// Set command flags from context.
 	isRecursive := ctx.Bool("recursive")
 	isIncomplete := ctx.Bool("incomplete")
+	isVersions := ctx.Bool("versions")
+	filter, _ := newContentFilter(filterOptsFromContext(ctx))
+	order, _ := parseListOrder(ctx.String("sort"), ctx.Bool("reverse"), ctx.Int("max-entries"))
+	summary := listSummaryMessage{}
+	format, _ := parseOutputFormat(ctx.String("output"), globalJSON)
+	printer := newMessagePrinter(format)
+	globalBucketListing, _ = parseBucketListOpts(ctx.Int("bucket-parallel"), ctx.String("bucket-order"))
 
 	args := ctx.Args()
 	// mimic operating system tool behavior.
@@ -135,12 +264,17 @@ func mainList(ctx *cli.Context) {
 		clnt, err := newClient(targetURL)
 		fatalIf(err.Trace(targetURL), "Unable to initialize target ‘"+targetURL+"’.")
 
-		var st *clientContent
+		var st *ClientContent
 		if st, err = clnt.Stat(); err != nil {
 			switch err.ToGoError().(type) {
 			case BucketNameEmpty:
 			// For aliases like ``mc ls s3`` it's acceptable to receive BucketNameEmpty error.
 			// Nothing to do.
+			case ObjectMissing:
+				// Versions of deleted objects are still available.
+				if !isVersions {
+					fatalIf(err.Trace(targetURL), "Unable to initialize target ‘"+targetURL+"’.")
+				}
 			default:
 				fatalIf(err.Trace(targetURL), "Unable to initialize target ‘"+targetURL+"’.")
 			}
@@ -152,10 +286,20 @@ func mainList(ctx *cli.Context) {
 			fatalIf(err.Trace(targetURL), "Unable to initialize target ‘"+targetURL+"’.")
 		}
 
-		err = doList(clnt, isRecursive, isIncomplete)
+		alias, _, _ := mustExpandAlias(targetURL)
+		summarizer, err := newFolderSummarizer(alias, ctx.Bool("folder-summary"), int64(ctx.Int("summary-limit")))
+		fatalIf(err.Trace(targetURL), "Unable to parse ‘--summary-limit’.")
+
+		err = doList(clnt, isRecursive, isIncomplete, isVersions, filter, order, summarizer, &summary, printer)
 		if err != nil {
 			errorIf(err.Trace(clnt.GetURL().String()), "Unable to list target ‘"+clnt.GetURL().String()+"’.")
 			continue
 		}
 	}
+
+	// Totals of all targets.
+	if ctx.Bool("summarize") {
+		printer.Print(summary)
+	}
+	printer.Flush()
 }
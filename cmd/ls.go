@@ -21,6 +21,8 @@ import (
 	"fmt"
 	"path/filepath"
 	"runtime"
+	"sort"
+	"strconv"
 	"strings"
 	"time"
 
@@ -41,6 +43,14 @@ type contentMessage struct {
 	Time     time.Time `json:"lastModified"`
 	Size     int64     `json:"size"`
 	Key      string    `json:"key"`
+
+	// Set only while listing versions.
+	VersionID      string `json:"versionId,omitempty"`
+	IsLatest       bool   `json:"isLatest,omitempty"`
+	IsDeleteMarker bool   `json:"isDeleteMarker,omitempty"`
+
+	// Set only for folders of listings with folder summaries.
+	Summary *folderSummary `json:"summary,omitempty"`
 }
 
 // String colorized string message.
@@ -53,6 +63,18 @@ func (c contentMessage) String() string {
 		}
 		return message + console.Colorize("File", fmt.Sprintf("%s", c.Key))
 	}()
+	if c.Summary != nil {
+		message = message + console.Colorize("Summary", c.Summary.String())
+	}
+	if c.VersionID != "" {
+		message = message + console.Colorize("Version", fmt.Sprintf(" %s", c.VersionID))
+		if c.IsDeleteMarker {
+			message = message + console.Colorize("DeleteMarker", " (delete marker)")
+		}
+		if c.IsLatest {
+			message = message + console.Colorize("Version", " (latest)")
+		}
+	}
 	return message
 }
 
@@ -65,8 +87,19 @@ func (c contentMessage) JSON() string {
 	return string(jsonMessageBytes)
 }
 
+// Columns names the columns of content messages printed as rows.
+func (c contentMessage) Columns() []string {
+	return []string{"lastModified", "size", "type", "key", "versionId", "isLatest", "isDeleteMarker"}
+}
+
+// Row content message as a row of a table.
+func (c contentMessage) Row() []string {
+	return []string{c.Time.Format(time.RFC3339), strconv.FormatInt(c.Size, 10), c.Filetype, c.Key,
+		c.VersionID, strconv.FormatBool(c.IsLatest), strconv.FormatBool(c.IsDeleteMarker)}
+}
+
 // parseContent parse client Content container into printer struct.
-func parseContent(c *clientContent) contentMessage {
+func parseContent(c *ClientContent) contentMessage {
 	content := contentMessage{}
 	content.Time = c.Time.Local()
 
@@ -79,6 +112,9 @@ func parseContent(c *clientContent) contentMessage {
 	}()
 
 	content.Size = c.Size
+	content.VersionID = c.VersionID
+	content.IsLatest = c.IsLatest
+	content.IsDeleteMarker = c.IsDeleteMarker
 	// Convert OS Type to match console file printing style.
 	content.Key = func() string {
 		switch {
@@ -103,14 +139,247 @@ func parseContent(c *clientContent) contentMessage {
 	return content
 }
 
-// doList - list all entities inside a folder.
-func doList(clnt Client, isRecursive, isIncomplete bool) *probe.Error {
+// listSummaryMessage container for the totals of listed objects.
+type listSummaryMessage struct {
+	Status  string `json:"status"`
+	Objects int64  `json:"totalObjects"`
+	Size    int64  `json:"totalSize"`
+}
+
+// String colorized summary message.
+func (l listSummaryMessage) String() string {
+	message := console.Colorize("Summary", fmt.Sprintf("\nTotal Objects: %d\n", l.Objects))
+	return message + console.Colorize("Summary", fmt.Sprintf("   Total Size: %s", humanize.IBytes(uint64(l.Size))))
+}
+
+// JSON jsonified summary message.
+func (l listSummaryMessage) JSON() string {
+	l.Status = "success"
+	summaryJSONBytes, e := json.Marshal(l)
+	fatalIf(probe.NewError(e), "Unable to marshal into JSON.")
+
+	return string(summaryJSONBytes)
+}
+
+// add - counts a listed entry, folders and delete markers are not
+// objects.
+func (l *listSummaryMessage) add(content contentMessage) {
+	if content.Summary != nil {
+		l.Objects += content.Summary.Objects
+		l.Size += content.Size
+		return
+	}
+	if content.Filetype == "folder" || content.IsDeleteMarker {
+		return
+	}
+	l.Objects++
+	l.Size += content.Size
+}
+
+// folderSummary - number of objects of a folder, their size is the
+// size of the folder.
+type folderSummary struct {
+	Objects int64 `json:"objects"`
+	// Only the first objects of the folder were counted.
+	Truncated bool `json:"truncated,omitempty"`
+}
+
+// String - number of objects to follow the name of the folder.
+func (f folderSummary) String() string {
+	if f.Truncated {
+		return fmt.Sprintf(" (%d+ objects)", f.Objects)
+	}
+	return fmt.Sprintf(" (%d objects)", f.Objects)
+}
+
+// folderSummarizer - sums up the objects of folders of shallow
+// listings. Folders are only listed recursively once they are about to
+// be printed, so that exploring large buckets does not list all their
+// objects. A nil summarizer sums up nothing.
+type folderSummarizer struct {
+	// New client of a folder of the listed target.
+	newClient func(urlStr string) (Client, *probe.Error)
+	// Maximum number of objects counted of a folder, zero counts all.
+	limit int64
+}
+
+// newFolderSummarizer - summarizer of the folders of a target of the
+// alias, nil if folders are not summed up.
+func newFolderSummarizer(alias string, isSummary bool, limit int64) (*folderSummarizer, *probe.Error) {
+	if !isSummary {
+		return nil, nil
+	}
+	if limit < 0 {
+		return nil, errInvalidArgument().Trace(strconv.FormatInt(limit, 10))
+	}
+	newClient := func(urlStr string) (Client, *probe.Error) {
+		return newClientFromAlias(alias, urlStr)
+	}
+	return &folderSummarizer{newClient: newClient, limit: limit}, nil
+}
+
+// summarize - lists the folder recursively, counting its objects up to
+// the limit.
+func (f *folderSummarizer) summarize(folderURL ClientURL) (int64, folderSummary, *probe.Error) {
+	separator := string(folderURL.Separator)
+	if !strings.HasSuffix(folderURL.Path, separator) {
+		folderURL.Path += separator
+	}
+	clnt, err := f.newClient(folderURL.String())
+	if err != nil {
+		return 0, folderSummary{}, err.Trace(folderURL.String())
+	}
+	var size int64
+	summary := folderSummary{}
+	for content := range clnt.List(globalContext, true, false) {
+		if content.Err != nil {
+			return 0, folderSummary{}, content.Err.Trace(folderURL.String())
+		}
+		if content.Type.IsDir() {
+			continue
+		}
+		if f.limit > 0 && summary.Objects == f.limit {
+			// Remaining objects are not listed.
+			summary.Truncated = true
+			break
+		}
+		summary.Objects++
+		size += content.Size
+	}
+	return size, summary, nil
+}
+
+// listOrder - order of listed entries, the zero value prints all
+// entries in the order they are listed.
+type listOrder struct {
+	// Names sort ascending, sizes and times descending, so that the
+	// largest or newest entries come first.
+	SortBy  string
+	Reverse bool
+	// Maximum number of entries printed, zero prints all.
+	MaxEntries int
+}
+
+// parseListOrder - order of ‘--sort’, ‘--reverse’ and ‘--max-entries’.
+func parseListOrder(sortBy string, reverse bool, maxEntries int) (listOrder, *probe.Error) {
+	switch sortBy {
+	case "", "name", "size", "time":
+	default:
+		return listOrder{}, errInvalidArgument().Trace(sortBy)
+	}
+	if maxEntries < 0 {
+		return listOrder{}, errInvalidArgument().Trace(strconv.Itoa(maxEntries))
+	}
+	return listOrder{SortBy: sortBy, Reverse: reverse, MaxEntries: maxEntries}, nil
+}
+
+// Orders of recursive listings of all buckets.
+const (
+	bucketOrderOrdered     = "ordered"
+	bucketOrderInterleaved = "interleaved"
+)
+
+// parseBucketListOpts - options of ‘--bucket-parallel’ and
+// ‘--bucket-order’, buckets are listed in order by default.
+func parseBucketListOpts(parallel int, order string) (BucketListOpts, *probe.Error) {
+	n, err := parseParallel(parallel)
+	if err != nil {
+		return BucketListOpts{}, err.Trace()
+	}
+	switch order {
+	case "", bucketOrderOrdered:
+	case bucketOrderInterleaved:
+	default:
+		return BucketListOpts{}, errInvalidArgument().Trace(order)
+	}
+	return BucketListOpts{Parallel: n, Interleaved: order == bucketOrderInterleaved}, nil
+}
+
+// isStreamed - entries are printed while listing.
+func (o listOrder) isStreamed() bool {
+	return o.SortBy == "" && !o.Reverse
+}
+
+// byListOrder - sorts content messages in a list order.
+type byListOrder struct {
+	contents []contentMessage
+	order    listOrder
+}
+
+func (b byListOrder) Len() int      { return len(b.contents) }
+func (b byListOrder) Swap(i, j int) { b.contents[i], b.contents[j] = b.contents[j], b.contents[i] }
+func (b byListOrder) Less(i, j int) bool {
+	if b.order.Reverse {
+		i, j = j, i
+	}
+	c1, c2 := b.contents[i], b.contents[j]
+	switch {
+	case b.order.SortBy == "size" && c1.Size != c2.Size:
+		return c1.Size > c2.Size
+	case b.order.SortBy == "time" && !c1.Time.Equal(c2.Time):
+		return c1.Time.After(c2.Time)
+	}
+	return c1.Key < c2.Key
+}
+
+// sortContents - sorts contents in the list order, keeping at most
+// its maximum number of entries.
+func sortContents(contents []contentMessage, order listOrder) []contentMessage {
+	if order.SortBy != "" {
+		sort.Stable(byListOrder{contents: contents, order: order})
+	} else if order.Reverse {
+		for i, j := 0, len(contents)-1; i < j; i, j = i+1, j-1 {
+			contents[i], contents[j] = contents[j], contents[i]
+		}
+	}
+	if order.MaxEntries > 0 && len(contents) > order.MaxEntries {
+		contents = contents[:order.MaxEntries]
+	}
+	return contents
+}
+
+// trimContents - drops listed contents which cannot be among the
+// maximum number of entries, so that sorted listings of limited length
+// are kept in bounded memory. Contents are trimmed once twice as many
+// were listed, sorting stably keeps the same entries as sorting all.
+func trimContents(contents []contentMessage, order listOrder) []contentMessage {
+	if order.MaxEntries == 0 || len(contents) < 2*order.MaxEntries {
+		return contents
+	}
+	kept := make([]contentMessage, order.MaxEntries, 2*order.MaxEntries)
+	switch {
+	case order.SortBy != "":
+		sort.Stable(byListOrder{contents: contents, order: order})
+		copy(kept, contents)
+	case order.Reverse:
+		// Reversed listings start with the last entries listed.
+		copy(kept, contents[len(contents)-order.MaxEntries:])
+	default:
+		copy(kept, contents)
+	}
+	return kept
+}
+
+// doList - list all entities inside a folder. Entries are printed
+// while listing unless they are sorted, the printed objects are
+// counted to the summary.
+// Folders are summed up by the summarizer, if any.
+func doList(clnt Client, isRecursive, isIncomplete, isVersions bool, filter *contentFilter, order listOrder, summarizer *folderSummarizer, summary *listSummaryMessage, printer *messagePrinter) *probe.Error {
 	prefixPath := clnt.GetURL().Path
 	separator := string(clnt.GetURL().Separator)
 	if !strings.HasSuffix(prefixPath, separator) {
 		prefixPath = prefixPath[:strings.LastIndex(prefixPath, separator)+1]
 	}
-	for content := range clnt.List(isRecursive, isIncomplete) {
+	var contentCh <-chan *ClientContent
+	if isVersions {
+		contentCh = clnt.ListVersions(isRecursive)
+	} else {
+		contentCh = clnt.List(globalContext, isRecursive, isIncomplete)
+	}
+	contentCh = filter.Filter(contentCh, clnt.GetURL().Path)
+	var contents []contentMessage
+	printed := 0
+	for content := range contentCh {
 		if content.Err != nil {
 			switch content.Err.ToGoError().(type) {
 			// handle this specifically for filesystem related errors.
@@ -133,6 +402,7 @@ func doList(clnt Client, isRecursive, isIncomplete bool) *probe.Error {
 			errorIf(content.Err.Trace(clnt.GetURL().String()), "Unable to list folder.")
 			continue
 		}
+		folderURL := content.URL
 		// Convert any os specific delimiters to "/".
 		contentURL := filepath.ToSlash(content.URL.Path)
 		prefixPath = filepath.ToSlash(prefixPath)
@@ -141,8 +411,31 @@ func doList(clnt Client, isRecursive, isIncomplete bool) *probe.Error {
 		contentURL = strings.TrimPrefix(contentURL, prefixPath)
 		content.URL.Path = contentURL
 		parsedContent := parseContent(content)
+		if summarizer != nil && content.Type.IsDir() {
+			size, folder, err := summarizer.summarize(folderURL)
+			if err != nil {
+				errorIf(err.Trace(clnt.GetURL().String()), "Unable to sum up folder.")
+			} else {
+				parsedContent.Size = size
+				parsedContent.Summary = &folder
+			}
+		}
+		if !order.isStreamed() {
+			contents = trimContents(append(contents, parsedContent), order)
+			continue
+		}
 		// Print colorized or jsonized content info.
-		printMsg(parsedContent)
+		printer.Print(parsedContent)
+		summary.add(parsedContent)
+		printed++
+		if printed == order.MaxEntries {
+			// Remaining entries are not listed.
+			return nil
+		}
+	}
+	for _, parsedContent := range sortContents(contents, order) {
+		printer.Print(parsedContent)
+		summary.add(parsedContent)
 	}
 	return nil
 }
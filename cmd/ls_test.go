@@ -15,3 +15,129 @@
  */
 
 package cmd
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"time"
+
+	. "gopkg.in/check.v1"
+)
+
+// Test sorting listed contents.
+func (s *TestSuite) TestSortContents(c *C) {
+	now := time.Now()
+	newContents := func() []contentMessage {
+		return []contentMessage{
+			{Key: "b", Size: 10, Time: now.Add(-time.Hour)},
+			{Key: "c", Size: 30, Time: now.Add(-2 * time.Hour)},
+			{Key: "a", Size: 20, Time: now},
+			{Key: "d", Size: 20, Time: now.Add(-3 * time.Hour)},
+		}
+	}
+	keys := func(contents []contentMessage) (keys []string) {
+		for _, content := range contents {
+			keys = append(keys, content.Key)
+		}
+		return keys
+	}
+
+	testCases := []struct {
+		order listOrder
+		keys  []string
+	}{
+		{listOrder{}, []string{"b", "c", "a", "d"}},
+		{listOrder{Reverse: true}, []string{"d", "a", "c", "b"}},
+		{listOrder{SortBy: "name"}, []string{"a", "b", "c", "d"}},
+		{listOrder{SortBy: "name", Reverse: true}, []string{"d", "c", "b", "a"}},
+		{listOrder{SortBy: "size"}, []string{"c", "a", "d", "b"}},
+		{listOrder{SortBy: "size", Reverse: true}, []string{"b", "d", "a", "c"}},
+		{listOrder{SortBy: "time"}, []string{"a", "b", "c", "d"}},
+		{listOrder{SortBy: "time", Reverse: true, MaxEntries: 2}, []string{"d", "c"}},
+		{listOrder{SortBy: "size", MaxEntries: 10}, []string{"c", "a", "d", "b"}},
+	}
+	for i, testCase := range testCases {
+		sorted := sortContents(newContents(), testCase.order)
+		c.Assert(keys(sorted), DeepEquals, testCase.keys, Commentf("Test %d", i+1))
+	}
+
+	// Contents trimmed while listing sort like all contents.
+	for i, testCase := range testCases {
+		for _, maxEntries := range []int{1, 2} {
+			order := testCase.order
+			order.MaxEntries = maxEntries
+			var trimmed []contentMessage
+			for _, content := range newContents() {
+				trimmed = trimContents(append(trimmed, content), order)
+			}
+			c.Assert(keys(sortContents(trimmed, order)), DeepEquals, keys(sortContents(newContents(), order)), Commentf("Test %d", i+1))
+		}
+	}
+
+	_, err := parseListOrder("owner", false, 0)
+	c.Assert(err, NotNil)
+	_, err = parseListOrder("size", false, -1)
+	c.Assert(err, NotNil)
+}
+
+// Test totals of listed objects.
+func (s *TestSuite) TestListSummary(c *C) {
+	summary := listSummaryMessage{}
+	summary.add(contentMessage{Filetype: "file", Size: 10})
+	summary.add(contentMessage{Filetype: "folder", Size: 4096})
+	summary.add(contentMessage{Filetype: "file", Size: 20, VersionID: "v1"})
+	summary.add(contentMessage{Filetype: "file", VersionID: "v2", IsDeleteMarker: true})
+	c.Assert(summary, Equals, listSummaryMessage{Objects: 2, Size: 30})
+	c.Assert(summary.JSON(), Equals, `{"status":"success","totalObjects":2,"totalSize":30}`)
+}
+
+// Test parsing options of recursive listings of all buckets.
+func (s *TestSuite) TestParseBucketListOpts(c *C) {
+	opts, err := parseBucketListOpts(0, "")
+	c.Assert(err, IsNil)
+	c.Assert(opts, Equals, BucketListOpts{Parallel: 1})
+	opts, err = parseBucketListOpts(8, "interleaved")
+	c.Assert(err, IsNil)
+	c.Assert(opts, Equals, BucketListOpts{Parallel: 8, Interleaved: true})
+
+	_, err = parseBucketListOpts(-1, "ordered")
+	c.Assert(err, NotNil)
+	_, err = parseBucketListOpts(8, "random")
+	c.Assert(err, NotNil)
+}
+
+// Test summing up folders of shallow listings.
+func (s *TestSuite) TestFolderSummarizer(c *C) {
+	root, e := ioutil.TempDir(os.TempDir(), "fs-")
+	c.Assert(e, IsNil)
+	defer os.RemoveAll(root)
+	c.Assert(os.MkdirAll(filepath.Join(root, "folder", "nested"), 0700), IsNil)
+	c.Assert(ioutil.WriteFile(filepath.Join(root, "folder", "object1"), []byte("hello"), 0600), IsNil)
+	c.Assert(ioutil.WriteFile(filepath.Join(root, "folder", "nested", "object2"), []byte("world!"), 0600), IsNil)
+
+	summarizer, err := newFolderSummarizer("", false, 0)
+	c.Assert(err, IsNil)
+	c.Assert(summarizer, IsNil)
+	_, err = newFolderSummarizer("", true, -1)
+	c.Assert(err, NotNil)
+
+	folderURL := *newClientURL(filepath.Join(root, "folder"))
+	summarizer = &folderSummarizer{newClient: fsNew}
+	size, folder, err := summarizer.summarize(folderURL)
+	c.Assert(err, IsNil)
+	c.Assert(size, Equals, int64(11))
+	c.Assert(folder, Equals, folderSummary{Objects: 2})
+	c.Assert(folder.String(), Equals, " (2 objects)")
+
+	summarizer = &folderSummarizer{newClient: fsNew, limit: 1}
+	_, folder, err = summarizer.summarize(folderURL)
+	c.Assert(err, IsNil)
+	c.Assert(folder, Equals, folderSummary{Objects: 1, Truncated: true})
+	c.Assert(folder.String(), Equals, " (1+ objects)")
+
+	summary := listSummaryMessage{}
+	summary.add(contentMessage{Filetype: "folder", Size: 11, Summary: &folderSummary{Objects: 2}})
+	summary.add(contentMessage{Filetype: "file", Size: 5})
+	c.Assert(summary, Equals, listSummaryMessage{Objects: 3, Size: 16})
+}
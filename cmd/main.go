@@ -65,6 +65,11 @@ VERSION:
 
 // Main starts mc application
 func Main() {
+	// mc answers password prompts of ssh for SFTP sessions.
+	if sftpAskPass() {
+		return
+	}
+
 	// Enable profiling supported modes are [cpu, mem, block].
 	// ``MC_PROFILER`` supported options are [cpu, mem, block].
 	switch os.Getenv("MC_PROFILER") {
@@ -77,6 +82,9 @@ func Main() {
 	}
 
 	probe.Init() // Set project's root source path.
+
+	// Language of messages and help, before the help is set up.
+	fatalIf(setLang(os.Args[1:]).Trace(os.Args[1:]...), "Unable to set language.")
 	probe.SetAppInfo("Release-Tag", ReleaseTag)
 	probe.SetAppInfo("Commit", ShortCommitID)
 
@@ -84,7 +92,7 @@ func Main() {
 	app.Before = registerBefore
 	app.ExtraInfo = func() map[string]string {
 		if _, e := pb.GetTerminalWidth(); e != nil {
-			globalQuiet = true
+			globalNoProgress = true
 		}
 		if globalDebug {
 			return getSystemData()
@@ -93,14 +101,24 @@ func Main() {
 	}
 
 	app.RunAndExitOnError()
+
+	if globalTraceRecorder != nil {
+		globalTraceRecorder.Close()
+	}
+
+	// Commands carry on after some errors, the first one decides the
+	// exit status.
+	if status := getExitStatus(); status != exitStatusSuccess {
+		os.Exit(status)
+	}
 }
 
 // Function invoked when invalid command is passed.
 func commandNotFound(ctx *cli.Context, command string) {
-	msg := fmt.Sprintf("‘%s’ is not a mc command. See ‘mc --help’.", command)
+	msg := trf("‘%s’ is not a mc command. See ‘mc --help’.", command)
 	closestCommands := findClosestCommands(command)
 	if len(closestCommands) > 0 {
-		msg += fmt.Sprintf("\n\nDid you mean one of these?\n")
+		msg += "\n\n" + tr("Did you mean one of these?") + "\n"
 		if len(closestCommands) == 1 {
 			cmd := closestCommands[0]
 			msg += fmt.Sprintf("        ‘%s’", cmd)
@@ -180,7 +198,7 @@ func initMC() {
 		err := saveMcConfig(newMcConfig())
 		fatalIf(err.Trace(), "Unable to save new mc config.")
 
-		console.Infoln("Configuration written to ‘" + mustGetMcConfigPath() + "’. Please update your access credentials.")
+		printInfo("Configuration written to ‘" + mustGetMcConfigPath() + "’. Please update your access credentials.")
 	}
 
 	// Check if mc session folder exists.
@@ -192,6 +210,9 @@ func initMC() {
 	if !isShareDirExists() {
 		initShareConfig()
 	}
+
+	// Load regions of buckets looked up by earlier invocations.
+	initBucketRegions()
 }
 
 func registerBefore(ctx *cli.Context) error {
@@ -201,15 +222,15 @@ func registerBefore(ctx *cli.Context) error {
 	// Set the config folder.
 	setMcConfigDir(ctx.GlobalString("config-folder"))
 
+	// Set global flags, messages of migrations are printed as JSON too.
+	setGlobalsFromContext(ctx)
+
 	// Migrate any old version of config / state files to newer format.
 	migrate()
 
 	// Initialize default config files.
 	initMC()
 
-	// Set global flags.
-	setGlobalsFromContext(ctx)
-
 	// Check if config can be read.
 	checkConfig()
 
@@ -238,22 +259,42 @@ func findClosestCommands(command string) []string {
 
 func registerApp() *cli.App {
 	// Register all the commands (refer flags.go)
-	registerCmd(lsCmd)      // List contents of a bucket.
-	registerCmd(mbCmd)      // Make a bucket.
-	registerCmd(catCmd)     // Display contents of a file.
-	registerCmd(pipeCmd)    // Write contents of stdin to a file.
-	registerCmd(shareCmd)   // Share documents via URL.
-	registerCmd(cpCmd)      // Copy objects and files from multiple sources to single destination.
-	registerCmd(mirrorCmd)  // Mirror objects and files from single source to multiple destinations.
-	registerCmd(diffCmd)    // Computer differences between two files or folders.
-	registerCmd(rmCmd)      // Remove a file or bucket
-	registerCmd(eventsCmd)  // Add events cmd
-	registerCmd(watchCmd)   // Add watch cmd
-	registerCmd(policyCmd)  // Set policy permissions.
-	registerCmd(sessionCmd) // Manage sessions for copy and mirror.
-	registerCmd(configCmd)  // Configure minio client.
-	registerCmd(updateCmd)  // Check for new software updates.
-	registerCmd(versionCmd) // Print version.
+	registerCmd(lsCmd)         // List contents of a bucket.
+	registerCmd(duCmd)         // Summarize disk usage.
+	registerCmd(findCmd)       // Find objects matching expressions.
+	registerCmd(treeCmd)       // Render prefixes as a tree.
+	registerCmd(statCmd)       // Display metadata of objects.
+	registerCmd(benchCmd)      // Measure throughput of a target.
+	registerCmd(mbCmd)         // Make a bucket.
+	registerCmd(rbCmd)         // Remove a bucket.
+	registerCmd(catCmd)        // Display contents of a file.
+	registerCmd(headCmd)       // Display first part of files.
+	registerCmd(tailCmd)       // Display last part of files.
+	registerCmd(sqlCmd)        // Run SQL queries on objects.
+	registerCmd(pipeCmd)       // Write contents of stdin to a file.
+	registerCmd(shareCmd)      // Share documents via URL.
+	registerCmd(cpCmd)         // Copy objects and files from multiple sources to single destination.
+	registerCmd(mvCmd)         // Move objects and files from multiple sources to single destination.
+	registerCmd(renameCmd)     // Rename objects within a bucket.
+	registerCmd(mirrorCmd)     // Mirror objects and files from single source to multiple destinations.
+	registerCmd(diffCmd)       // Computer differences between two files or folders.
+	registerCmd(rmCmd)         // Remove a file or bucket
+	registerCmd(undoCmd)       // Restore removed or overwritten objects.
+	registerCmd(eventsCmd)     // Add events cmd
+	registerCmd(ilmCmd)        // Manage bucket lifecycle.
+	registerCmd(tagCmd)        // Manage object tags.
+	registerCmd(retentionCmd)  // Manage object retention.
+	registerCmd(legalHoldCmd)  // Manage object legal hold.
+	registerCmd(restoreCmd)    // Restore archived objects.
+	registerCmd(aclCmd)        // Manage access control lists.
+	registerCmd(versioningCmd) // Manage bucket versioning.
+	registerCmd(watchCmd)      // Add watch cmd
+	registerCmd(policyCmd)     // Set policy permissions.
+	registerCmd(sessionCmd)    // Manage sessions for copy and mirror.
+	registerCmd(configCmd)     // Configure minio client.
+	registerCmd(completionCmd) // Generate shell completion scripts.
+	registerCmd(updateCmd)     // Check for new software updates.
+	registerCmd(versionCmd)    // Print version.
 
 	app := cli.NewApp()
 	app.Action = func(ctx *cli.Context) {
@@ -276,6 +317,7 @@ func registerApp() *cli.App {
 	app.Flags = append(mcFlags, globalFlags...)
 	app.CustomAppHelpTemplate = mcHelpTemplate
 	app.CommandNotFound = commandNotFound // handler function declared above.
+	localizeApp(app)
 	return app
 }
 
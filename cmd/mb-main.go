@@ -81,7 +81,7 @@ type makeBucketMessage struct {
 
 // String colorized make bucket message.
 func (s makeBucketMessage) String() string {
-	return console.Colorize("MakeBucket", "Bucket created successfully ‘"+s.Bucket+"’.")
+	return console.Colorize("MakeBucket", trf("Bucket created successfully ‘%s’.", s.Bucket))
 }
 
 // JSON jsonified make bucket message.
@@ -17,6 +17,8 @@
 package cmd
 
 import (
+	"io/ioutil"
+	"os"
 	"path/filepath"
 	"runtime"
 	"testing"
@@ -26,6 +28,7 @@ import (
 
 	"github.com/hashicorp/go-version"
 	"github.com/minio/cli"
+	"github.com/minio/minio/pkg/probe"
 	. "gopkg.in/check.v1"
 )
 
@@ -118,6 +121,61 @@ func (s *TestSuite) TestIsvalidAliasName(c *C) {
 	c.Check(isValidAlias("-fdslka"), Equals, false)
 }
 
+func (s *TestSuite) TestEndpointHostConfig(c *C) {
+	c.Check(isValidAlias(endpointAlias), Equals, false)
+
+	hostCfg, err := newEndpointHostConfig("", "", "")
+	c.Assert(err, IsNil)
+	c.Assert(hostCfg, IsNil)
+	hostCfg, err = newEndpointHostConfig("https://play.minio.io:9000", "Q3AM3UQ867SPQQA43P2F", "zuf+tfteSlswRu7BJ86wekitnifILbZam1KYY3TG")
+	c.Assert(err, IsNil)
+	c.Assert(*hostCfg, Equals, hostConfigV8{URL: "https://play.minio.io:9000", AccessKey: "Q3AM3UQ867SPQQA43P2F", SecretKey: "zuf+tfteSlswRu7BJ86wekitnifILbZam1KYY3TG", API: "S3v4"})
+	_, err = newEndpointHostConfig("", "Q3AM3UQ867SPQQA43P2F", "zuf+tfteSlswRu7BJ86wekitnifILbZam1KYY3TG")
+	c.Assert(err, Not(IsNil))
+	_, err = newEndpointHostConfig("https://play.minio.io:9000", "Q3AM3UQ867SPQQA43P2F", "")
+	c.Assert(err, Not(IsNil))
+	_, err = newEndpointHostConfig("play.minio.io", "", "")
+	c.Assert(err, Not(IsNil))
+
+	c.Check(isEndpointPath("bucket/object"), Equals, false)
+	defer func(load func() (*configV8, *probe.Error)) { loadMcConfig = load }(loadMcConfig)
+	loadMcConfig = func() (*configV8, *probe.Error) {
+		conf := newMcConfig()
+		conf.Hosts["s3"] = hostConfigV8{URL: "https://s3.amazonaws.com", API: "S3v4"}
+		return conf, nil
+	}
+	globalEndpoint = hostCfg
+	defer func() { globalEndpoint = nil }()
+	c.Check(isEndpointPath("bucket/object"), Equals, true)
+	c.Check(isEndpointPath("bucket"), Equals, true)
+	c.Check(isEndpointPath("/tmp/object"), Equals, false)
+	c.Check(isEndpointPath("./object"), Equals, false)
+	c.Check(isEndpointPath("~/object"), Equals, false)
+	c.Check(isEndpointPath("https://example.com/object"), Equals, false)
+	c.Check(isEndpointPath(""), Equals, false)
+
+	// Aliases of the config are no paths of the endpoint.
+	c.Check(isEndpointPath("s3/bucket/object"), Equals, false)
+	alias, urlStr, _ := mustExpandAlias("s3/bucket/object")
+	c.Check(alias, Equals, "s3")
+	c.Check(urlStr, Equals, "https://s3.amazonaws.com/bucket/object")
+	alias, urlStr, _ = mustExpandAlias("bucket/object")
+	c.Check(alias, Equals, endpointAlias)
+	c.Check(urlStr, Equals, "https://play.minio.io:9000/bucket/object")
+
+	// Neither are existing relative paths.
+	root, e := ioutil.TempDir("", "mc-endpoint-")
+	c.Assert(e, IsNil)
+	defer os.RemoveAll(root)
+	c.Assert(ioutil.WriteFile(filepath.Join(root, "object"), []byte("local"), 0600), IsNil)
+	wd, e := os.Getwd()
+	c.Assert(e, IsNil)
+	defer os.Chdir(wd)
+	c.Assert(os.Chdir(root), IsNil)
+	c.Check(isEndpointPath("object"), Equals, false)
+	c.Check(isEndpointPath("missing"), Equals, true)
+}
+
 func (s *TestSuite) TestHumanizedTime(c *C) {
 	hTime := timeDurationToHumanizedTime(time.Duration(10) * time.Second)
 	c.Assert(hTime.Minutes, Equals, int64(0))
@@ -0,0 +1,102 @@
+/*
+ * Minio Client (C) 2016 Minio, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"net/http"
+	"strings"
+
+	"github.com/minio/minio/pkg/probe"
+)
+
+// Prefix of user defined metadata headers.
+const userMetadataPrefix = "X-Amz-Meta-"
+
+// Standard headers stored along with an object, other metadata is
+// user defined.
+var standardMetadataHeaders = []string{
+	"Cache-Control",
+	"Content-Disposition",
+	"Content-Encoding",
+	"Content-Language",
+	"Content-Type",
+	"Expires",
+}
+
+// metadataKey - header name of a metadata key, keys which are not
+// standard headers are user defined metadata.
+func metadataKey(key string) string {
+	key = http.CanonicalHeaderKey(key)
+	if strings.HasPrefix(key, userMetadataPrefix) {
+		return key
+	}
+	for _, header := range standardMetadataHeaders {
+		if key == header {
+			return key
+		}
+	}
+	return userMetadataPrefix + key
+}
+
+// parseAttr - parses the value of ‘--attr’, a semicolon separated list
+// of ‘KEY=VALUE’ pairs, into metadata keyed by header name.
+func parseAttr(attr string) (map[string]string, *probe.Error) {
+	metadata := make(map[string]string)
+	for _, field := range strings.Split(attr, ";") {
+		if field = strings.TrimSpace(field); field == "" {
+			continue
+		}
+		i := strings.Index(field, "=")
+		if i <= 0 {
+			return nil, errInvalidAttr(field).Trace(field)
+		}
+		metadata[metadataKey(strings.TrimSpace(field[:i]))] = strings.TrimSpace(field[i+1:])
+	}
+	return metadata, nil
+}
+
+// metadataFromHeader - metadata of an object from the headers of a
+// response.
+func metadataFromHeader(h http.Header) map[string]string {
+	metadata := make(map[string]string)
+	for key := range h {
+		if metadataKey(key) == key {
+			metadata[key] = h.Get(key)
+		}
+	}
+	return metadata
+}
+
+// mergeMetadata - metadata of the source with the given entries added,
+// replacing existing ones.
+func mergeMetadata(source, metadata map[string]string) map[string]string {
+	merged := make(map[string]string, len(source)+len(metadata))
+	for key, value := range source {
+		merged[key] = value
+	}
+	for key, value := range metadata {
+		merged[key] = value
+	}
+	return merged
+}
+
+// setMetadataHeaders - sets headers of the metadata.
+func setMetadataHeaders(h http.Header, metadata map[string]string) {
+	for key, value := range metadata {
+		h.Set(key, value)
+	}
+}
@@ -0,0 +1,238 @@
+/*
+ * Minio Client (C) 2016 Minio, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/minio/cli"
+	"github.com/minio/minio/pkg/probe"
+)
+
+// metricsFlags - flags of long running commands exposing metrics.
+var metricsFlags = []cli.Flag{
+	cli.StringFlag{
+		Name:  "metrics-address",
+		Usage: "Serve metrics in the format of Prometheus at ‘/metrics’ on the address, e.g. ‘localhost:9100’.",
+	},
+	cli.StringFlag{
+		Name:  "statsd-address",
+		Usage: "Push metrics to statsd at the address, e.g. ‘localhost:8125’.",
+	},
+}
+
+// Metrics of long running commands.
+const (
+	metricTransferredObjects = "mc_transferred_objects_total"
+	metricTransferredBytes   = "mc_transferred_bytes_total"
+	metricRemovedObjects     = "mc_removed_objects_total"
+	metricEvents             = "mc_events_total"
+	metricErrors             = "mc_errors_total"
+	metricThroughput         = "mc_throughput_bytes_per_second"
+)
+
+// metricInfo - help and Prometheus type of a metric.
+type metricInfo struct {
+	help string
+	kind string
+}
+
+var metricInfos = map[string]metricInfo{
+	metricTransferredObjects: {"Objects copied to the target.", "counter"},
+	metricTransferredBytes:   {"Bytes of the objects copied to the target.", "counter"},
+	metricRemovedObjects:     {"Objects removed from the target.", "counter"},
+	metricEvents:             {"Events received, by type.", "counter"},
+	metricErrors:             {"Objects which failed and other errors.", "counter"},
+	metricThroughput:         {"Bytes copied per second, over the last sampling interval.", "gauge"},
+}
+
+// Interval of sampling the throughput and of pushing gauges to statsd.
+const metricsInterval = 10 * time.Second
+
+// metricSample - value of a metric with labels.
+type metricSample struct {
+	name   string
+	labels string
+	value  float64
+}
+
+// metricsRegistry - metrics of the running command, served to
+// Prometheus and pushed to statsd. A nil registry records nothing.
+type metricsRegistry struct {
+	mutex   sync.Mutex
+	samples map[string]*metricSample
+	// Counters are pushed as they change, nil without statsd.
+	statsd io.Writer
+
+	// Bytes transferred at the last sample of the throughput.
+	lastBytes  float64
+	lastSample time.Time
+}
+
+// Metrics of the running command, nil unless they are exposed.
+var globalMetrics *metricsRegistry
+
+// newMetricsRegistry - registry holding all unlabeled metrics at zero.
+func newMetricsRegistry(statsd io.Writer) *metricsRegistry {
+	r := &metricsRegistry{samples: map[string]*metricSample{}, statsd: statsd, lastSample: time.Now()}
+	for name := range metricInfos {
+		if name != metricEvents {
+			r.sample(name, nil)
+		}
+	}
+	return r
+}
+
+// sample - sample of the metric with labels given as pairs of names
+// and values, created if missing.
+func (r *metricsRegistry) sample(name string, labels []string) *metricSample {
+	var pairs []string
+	for i := 0; i+1 < len(labels); i += 2 {
+		pairs = append(pairs, fmt.Sprintf("%s=%q", labels[i], labels[i+1]))
+	}
+	key := name + "{" + strings.Join(pairs, ",") + "}"
+	s, ok := r.samples[key]
+	if !ok {
+		s = &metricSample{name: name, labels: strings.Join(pairs, ",")}
+		r.samples[key] = s
+	}
+	return s
+}
+
+// Add - adds to a counter, labels are given as pairs of names and
+// values.
+func (r *metricsRegistry) Add(name string, value float64, labels ...string) {
+	if r == nil {
+		return
+	}
+	r.mutex.Lock()
+	r.sample(name, labels).value += value
+	r.mutex.Unlock()
+	r.push(name, value, "c", labels)
+}
+
+// Set - sets a gauge.
+func (r *metricsRegistry) Set(name string, value float64, labels ...string) {
+	if r == nil {
+		return
+	}
+	r.mutex.Lock()
+	r.sample(name, labels).value = value
+	r.mutex.Unlock()
+	r.push(name, value, "g", labels)
+}
+
+// push - sends the change of a metric to statsd, like
+// ‘mc.events.ObjectCreated:1|c’. Values of labels are part of the name.
+func (r *metricsRegistry) push(name string, value float64, kind string, labels []string) {
+	if r.statsd == nil {
+		return
+	}
+	statsdName := strings.Replace(strings.TrimSuffix(name, "_total"), "mc_", "mc.", 1)
+	for i := 1; i < len(labels); i += 2 {
+		statsdName += "." + labels[i]
+	}
+	// Metrics are lost rather than held up if statsd is not listening.
+	if _, e := fmt.Fprintf(r.statsd, "%s:%v|%s", statsdName, value, kind); e != nil {
+		logDebug("Unable to push metrics to statsd.", "error", e)
+	}
+}
+
+// sampleThroughput - sets the throughput since the last sample.
+func (r *metricsRegistry) sampleThroughput(now time.Time) {
+	r.mutex.Lock()
+	bytes := r.sample(metricTransferredBytes, nil).value
+	elapsed := now.Sub(r.lastSample).Seconds()
+	throughput := 0.0
+	if elapsed > 0 {
+		throughput = (bytes - r.lastBytes) / elapsed
+	}
+	r.lastBytes, r.lastSample = bytes, now
+	r.mutex.Unlock()
+	r.Set(metricThroughput, throughput)
+}
+
+// WritePrometheus - writes all metrics in the text format of Prometheus.
+func (r *metricsRegistry) WritePrometheus(w io.Writer) {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	var keys []string
+	for key := range r.samples {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	lastName := ""
+	for _, key := range keys {
+		s := r.samples[key]
+		if s.name != lastName {
+			fmt.Fprintf(w, "# HELP %s %s\n# TYPE %s %s\n", s.name, metricInfos[s.name].help, s.name, metricInfos[s.name].kind)
+			lastName = s.name
+		}
+		if s.labels != "" {
+			fmt.Fprintf(w, "%s{%s} %v\n", s.name, s.labels, s.value)
+		} else {
+			fmt.Fprintf(w, "%s %v\n", s.name, s.value)
+		}
+	}
+}
+
+// ServeHTTP - serves the metrics to Prometheus.
+func (r *metricsRegistry) ServeHTTP(w http.ResponseWriter, req *http.Request) {
+	w.Header().Set("Content-Type", "text/plain; version=0.0.4")
+	r.WritePrometheus(w)
+}
+
+// startMetrics - exposes metrics of the running command on the address
+// and pushes them to statsd, either address may be empty.
+func startMetrics(address, statsdAddress string) *probe.Error {
+	if address == "" && statsdAddress == "" {
+		return nil
+	}
+	var statsd io.Writer
+	if statsdAddress != "" {
+		conn, e := net.Dial("udp", statsdAddress)
+		if e != nil {
+			return probe.NewError(e).Trace(statsdAddress)
+		}
+		statsd = conn
+	}
+	r := newMetricsRegistry(statsd)
+	if address != "" {
+		listener, e := net.Listen("tcp", address)
+		if e != nil {
+			return probe.NewError(e).Trace(address)
+		}
+		mux := http.NewServeMux()
+		mux.Handle("/metrics", r)
+		go http.Serve(listener, mux)
+	}
+	go func() {
+		for now := range time.Tick(metricsInterval) {
+			r.sampleThroughput(now)
+		}
+	}()
+	globalMetrics = r
+	return nil
+}
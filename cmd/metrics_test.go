@@ -0,0 +1,91 @@
+/*
+ * Minio Client (C) 2016 Minio, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"bytes"
+	"strings"
+	"time"
+
+	. "gopkg.in/check.v1"
+)
+
+func (s *TestSuite) TestMetricsPrometheus(c *C) {
+	r := newMetricsRegistry(nil)
+	r.Add(metricTransferredObjects, 2)
+	r.Add(metricTransferredBytes, 1024)
+	r.Add(metricEvents, 1, "type", "ObjectCreated")
+	r.Add(metricEvents, 2, "type", "ObjectCreated")
+	r.Add(metricEvents, 1, "type", "ObjectRemoved")
+
+	var buf bytes.Buffer
+	r.WritePrometheus(&buf)
+	c.Assert(buf.String(), Equals, `# HELP mc_errors_total Objects which failed and other errors.
+# TYPE mc_errors_total counter
+mc_errors_total 0
+# HELP mc_events_total Events received, by type.
+# TYPE mc_events_total counter
+mc_events_total{type="ObjectCreated"} 3
+mc_events_total{type="ObjectRemoved"} 1
+# HELP mc_removed_objects_total Objects removed from the target.
+# TYPE mc_removed_objects_total counter
+mc_removed_objects_total 0
+# HELP mc_throughput_bytes_per_second Bytes copied per second, over the last sampling interval.
+# TYPE mc_throughput_bytes_per_second gauge
+mc_throughput_bytes_per_second 0
+# HELP mc_transferred_bytes_total Bytes of the objects copied to the target.
+# TYPE mc_transferred_bytes_total counter
+mc_transferred_bytes_total 1024
+# HELP mc_transferred_objects_total Objects copied to the target.
+# TYPE mc_transferred_objects_total counter
+mc_transferred_objects_total 2
+`)
+}
+
+func (s *TestSuite) TestMetricsThroughput(c *C) {
+	r := newMetricsRegistry(nil)
+	start := r.lastSample
+	r.Add(metricTransferredBytes, 1000)
+	r.sampleThroughput(start.Add(10 * time.Second))
+	c.Assert(r.sample(metricThroughput, nil).value, Equals, 100.0)
+	r.sampleThroughput(start.Add(20 * time.Second))
+	c.Assert(r.sample(metricThroughput, nil).value, Equals, 0.0)
+}
+
+// statsdRecorder - records packets pushed to statsd.
+type statsdRecorder struct {
+	packets []string
+}
+
+func (w *statsdRecorder) Write(p []byte) (int, error) {
+	w.packets = append(w.packets, string(p))
+	return len(p), nil
+}
+
+func (s *TestSuite) TestMetricsStatsd(c *C) {
+	statsd := &statsdRecorder{}
+	r := newMetricsRegistry(statsd)
+	r.Add(metricTransferredBytes, 1024)
+	r.Add(metricEvents, 1, "type", "ObjectCreated")
+	r.Set(metricThroughput, 12.5)
+	c.Assert(strings.Join(statsd.packets, "\n"), Equals, "mc.transferred_bytes:1024|c\nmc.events.ObjectCreated:1|c\nmc.throughput_bytes_per_second:12.5|g")
+
+	// Nil registries record nothing.
+	var nilRegistry *metricsRegistry
+	nilRegistry.Add(metricErrors, 1)
+	nilRegistry.Set(metricThroughput, 1)
+}
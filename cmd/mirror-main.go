@@ -18,6 +18,7 @@ package cmd
 
 import (
 	"bufio"
+	"context"
 	"encoding/json"
 	"fmt"
 	"os"
@@ -51,12 +52,85 @@ var (
 		},
 		cli.BoolFlag{
 			Name:  "watch, w",
-			Usage: "Watch and mirror for changes.",
+			Usage: "Keep watching the source after the initial mirror, and mirror created and removed objects.",
 		},
 		cli.BoolFlag{
 			Name:  "remove",
 			Usage: "Remove extraneous file(s) on target.",
 		},
+		yesFlag,
+		cli.StringFlag{
+			Name:  "compare",
+			Value: compareSize,
+			Usage: "Compare files existing on both sides by ‘size’, or by ‘checksum’ to also mirror files of the same size which differ in contents.",
+		},
+		cli.StringFlag{
+			Name:  "part-size",
+			Usage: "Part size of multipart uploads, e.g. ‘128MiB’. Between 5MiB and 5GiB.",
+		},
+		cli.IntFlag{
+			Name:  "concurrent-parts",
+			Usage: "Number of parts of an object uploaded in parallel. Each part is buffered in memory.",
+		},
+		cli.IntFlag{
+			Name:  "parallel",
+			Value: 1,
+			Usage: "Number of objects mirrored in parallel, each with its own concurrent parts.",
+		},
+		cli.StringSliceFlag{
+			Name:  "include",
+			Value: &cli.StringSlice{},
+			Usage: "Only mirror files matching the gitignore style pattern, may be repeated.",
+		},
+		cli.StringSliceFlag{
+			Name:  "exclude",
+			Value: &cli.StringSlice{},
+			Usage: "Skip files and folders matching the gitignore style pattern, may be repeated. Excluded files on the target are not removed.",
+		},
+		cli.StringFlag{
+			Name:  "newer-than",
+			Usage: "Only mirror files modified within the given age, e.g. ‘12h’ or ‘7d’.",
+		},
+		cli.StringFlag{
+			Name:  "older-than",
+			Usage: "Only mirror files modified before the given age, e.g. ‘30d’.",
+		},
+		cli.StringFlag{
+			Name:  "larger-than",
+			Usage: "Only mirror files larger than the given size, e.g. ‘10MiB’.",
+		},
+		cli.StringFlag{
+			Name:  "smaller-than",
+			Usage: "Only mirror files smaller than the given size, e.g. ‘1KiB’.",
+		},
+		cli.StringFlag{
+			Name:  "staging-prefix",
+			Usage: "Upload objects under the given prefix of the target bucket, and copy them to their names and remove extraneous objects only once all objects were mirrored.",
+		},
+		cli.StringFlag{
+			Name:  "failure-manifest",
+			Usage: "Write objects which failed to mirror to the given file, as one JSON object with source, target and error per line.",
+		},
+		cli.BoolFlag{
+			Name:  "follow-symlinks",
+			Usage: "Follow links to folders and mirror the files in them, loops are reported.",
+		},
+		cli.BoolFlag{
+			Name:  "skip-symlinks",
+			Usage: "Skip links to files and folders.",
+		},
+		cli.BoolFlag{
+			Name:  "preserve-links",
+			Usage: "Mirror links as empty objects holding the target of the link as metadata, and such objects back to links.",
+		},
+		cli.BoolFlag{
+			Name:  "preserve",
+			Usage: "Store mode, owner and modification time of files as metadata, and restore them when mirroring back to files.",
+		},
+		cli.BoolFlag{
+			Name:  "incremental",
+			Usage: "Compare the source against the files as last mirrored, kept in the config folder, instead of listing the target. The target must not be modified otherwise.",
+		},
 	}
 )
 
@@ -65,7 +139,7 @@ var mirrorCmd = cli.Command{
 	Name:   "mirror",
 	Usage:  "Mirror folders recursively from a single source to single destination.",
 	Action: mainMirror,
-	Flags:  append(mirrorFlags, globalFlags...),
+	Flags:  append(append(append(mirrorFlags, listingCacheFlags...), metricsFlags...), globalFlags...),
 	CustomHelpTemplate: `NAME:
    mc {{.Name}} - {{.Usage}}
 
@@ -93,9 +167,48 @@ EXAMPLES:
       $ mc {{.Name}} --force --remove play/photos/2014 s3/backup-photos/2014
 
    6. Continuously mirror a local folder recursively to Minio cloud storage. '--watch' continuously watches for
-      new objects and uploads them.
+      new objects and uploads them, with '--remove' removed objects are removed from the target as well.
       $ mc {{.Name}} --force --remove --watch /var/lib/backups play/backups
 
+   7. Mirror only parquet files of a local folder to Amazon S3 cloud storage.
+      $ mc {{.Name}} --include "*.parquet" datalake/ s3/datalake
+
+   8. Mirror a local folder to Amazon S3 cloud storage, skipping files of 5GiB or more.
+      $ mc {{.Name}} --smaller-than 5GiB backup/ s3/archive
+
+   9. Mirror a local folder to Amazon S3 cloud storage, overwriting objects whose contents changed but size did not.
+      $ mc {{.Name}} --force --compare checksum backup/ s3/archive
+
+  10. Mirror a local folder to Amazon S3 cloud storage, eight files at a time.
+      $ mc {{.Name}} --parallel 8 backup/ s3/archive
+
+  11. Mirror a dataset to Amazon S3 cloud storage, so that readers never see only part of it.
+      $ mc {{.Name}} --force --remove --staging-prefix .staging/ dataset/ s3/datalake/dataset
+
+  12. Mirror a local folder to Amazon S3 cloud storage, printing a summary as JSON and listing failed objects in a manifest.
+      $ mc {{.Name}} --json --failure-manifest failed.json backup/ s3/archive
+
+  13. Mirror a local folder to Amazon S3 cloud storage, skipping links to other files and folders.
+      $ mc {{.Name}} --skip-symlinks backup/ s3/archive
+
+  14. Mirror a local folder to Amazon S3 cloud storage, keeping permissions, owners and modification times of the files.
+      $ mc {{.Name}} --preserve backup/ s3/archive
+
+  15. Mirror a bucket and remove extraneous objects without being asked to confirm, e.g. in scripts.
+      $ mc {{.Name}} --force --remove --yes play/photos/2014 s3/backup-photos/2014
+
+  16. Continuously mirror a local folder, serving transfer counters and throughput to Prometheus at http://localhost:9100/metrics.
+      $ mc {{.Name}} --watch --metrics-address localhost:9100 /var/lib/backups play/backups
+
+  17. Continuously mirror a local folder, pushing metrics to statsd.
+      $ mc {{.Name}} --watch --statsd-address localhost:8125 /var/lib/backups play/backups
+
+  18. Mirror a bucket to a local folder, reusing the listing of the bucket taken by a diff within the last ten minutes.
+      $ mc diff --cache-ttl 10m s3/archive backup/ && mc {{.Name}} --cache-ttl 10m s3/archive backup/
+
+  19. Frequently mirror a large bucket, comparing it against the objects mirrored by the previous run instead of listing the target.
+      $ mc {{.Name}} --incremental --force s3/archive play/archive
+
 `,
 }
 
@@ -137,6 +250,30 @@ type mirrorSession struct {
 
 	sourceURL string
 	targetURL string
+
+	// multipart options of uploads
+	uploadOpts PutOpts
+
+	// include and exclude patterns, age and size bounds, nil mirrors
+	// all files
+	filter *contentFilter
+
+	// objects are uploaded under the staging prefix, and extraneous
+	// objects removed only once all objects were mirrored
+	stagingPrefix string
+	// removals waiting for the staged objects to be committed
+	stagedRemovals []URLs
+	stagingM       *sync.Mutex
+	// set if any object failed to mirror
+	isFailed bool
+
+	// objects and bytes mirrored, for the summary
+	report *transferReport
+
+	// recent listings of the source, nil lists every time
+	cache *listingCache
+	// files of the source as last mirrored, nil lists the target
+	state *mirrorState
 }
 
 // mirrorMessage container for file mirror messages
@@ -194,7 +331,7 @@ func (ms *mirrorSession) doRemove(sURLs URLs) URLs {
 	isIncomplete := false
 
 	// Remove extraneous file on target.
-	err := rm(targetAlias, targetURL.String(), isIncomplete, isFake, time.Duration(0))
+	err := rm(targetAlias, targetURL.String(), isIncomplete, isFake, time.Duration(0), nil)
 	if err != nil {
 		return sURLs.WithError(err.Trace(targetAlias, targetURL.String()))
 	}
@@ -203,7 +340,7 @@ func (ms *mirrorSession) doRemove(sURLs URLs) URLs {
 }
 
 // doMirror - Mirror an object to multiple destination. URLs status contains a copy of sURLs and error if any.
-func (ms *mirrorSession) doMirror(sURLs URLs) URLs {
+func (ms *mirrorSession) doMirror(ctx context.Context, sURLs URLs) URLs {
 	isFake := ms.Header.CommandBoolFlags["fake"]
 
 	if sURLs.Error != nil { // Errorneous sURLs passed.
@@ -214,6 +351,7 @@ func (ms *mirrorSession) doMirror(sURLs URLs) URLs {
 	// and accounting readers under relevant conditions.
 	if isFake {
 		ms.status.Add(sURLs.SourceContent.Size)
+		ms.status.FinishFile(nil)
 		return sURLs.WithError(nil)
 	}
 
@@ -223,7 +361,20 @@ func (ms *mirrorSession) doMirror(sURLs URLs) URLs {
 	targetURL := sURLs.TargetContent.URL
 	length := sURLs.SourceContent.Size
 
-	ms.status.SetCaption(sourceURL.String() + ": ")
+	if ms.stagingPrefix != "" {
+		stagedURLs := stageURLs(sURLs, ms.stagingPrefix)
+		if stagedURLs.Error != nil {
+			return sURLs.WithError(stagedURLs.Error.Trace())
+		}
+		targetURL = stagedURLs.TargetContent.URL
+	}
+
+	// Data of the file is read through its progress.
+	progress := ms.status.StartFile(sourceURL.String(), length)
+	defer ms.status.FinishFile(progress)
+
+	uploadOpts := ms.uploadOpts
+	uploadOpts.ResumeID = uploadResumeID(sourceAlias, sURLs.SourceContent)
 
 	sourcePath := filepath.ToSlash(filepath.Join(sourceAlias, sourceURL.Path))
 	targetPath := filepath.ToSlash(filepath.Join(targetAlias, targetURL.Path))
@@ -232,46 +383,85 @@ func (ms *mirrorSession) doMirror(sURLs URLs) URLs {
 		Target: targetPath,
 	})
 
-	// If source size is <= 5GB and operation is across same server type try to use Copy.
-	if length <= fiveGB && sourceURL.Type == targetURL.Type {
+	// Links listed with preserved links are mirrored as links.
+	if sURLs.SourceContent.Symlink != "" {
+		if err := copySymlink(ctx, sURLs.SourceContent.Symlink, targetAlias, targetURL, progress, uploadOpts); err != nil {
+			return sURLs.WithError(err.Trace(sourceURL.String()))
+		}
+		return sURLs.WithError(nil)
+	}
+
+	// Attributes of files are stored as metadata of the objects.
+	if uploadOpts.Preserve && targetURL.Type == ObjectStorage {
+		attrs, err := sourceFileAttrs(sourceAlias, sourceURL)
+		if err != nil {
+			return sURLs.WithError(err.Trace(sourceURL.String()))
+		}
+		uploadOpts.Metadata = mergeMetadata(attrs, uploadOpts.Metadata)
+	}
+
+	// Operations across the same server type use Copy, object storage
+	// copies objects of any size without transferring data.
+	if sourceURL.Type == targetURL.Type {
 		// FS -> FS Copy includes alias in path.
-		if sourceURL.Type == fileSystem {
+		if sourceURL.Type == FileSystem {
 			sourcePath := filepath.ToSlash(filepath.Join(sourceAlias, sourceURL.Path))
-			err := copySourceStreamFromAlias(targetAlias, targetURL.String(), sourcePath, length, ms.status)
+			err := copySourceStreamFromAlias(ctx, targetAlias, targetURL.String(), sourcePath, length, progress, CopyOpts{})
 			if err != nil {
 				return sURLs.WithError(err.Trace(sourceURL.String()))
 			}
-		} else if sourceURL.Type == objectStorage {
-			if sourceAlias == targetAlias {
-				// If source/target are object storage their aliases must be the same
+		} else if sourceURL.Type == ObjectStorage {
+			if sourceAlias == targetAlias && sourceURL.Host == targetURL.Host {
+				// If source/target are object storage their aliases must be the same,
+				// SFTP URLs have no alias but their hosts must match.
 				// Do not include alias inside path for ObjStore -> ObjStore.
-				err := copySourceStreamFromAlias(targetAlias, targetURL.String(), sourceURL.Path, length, ms.status)
+				err := copySourceStreamFromAlias(ctx, targetAlias, targetURL.String(), sourceURL.Path, length, progress, CopyOpts{})
 				if err != nil {
 					return sURLs.WithError(err.Trace(sourceURL.String()))
 				}
 			} else {
-				reader, err := getSourceStreamFromAlias(sourceAlias, sourceURL.String())
+				// Metadata of the source is retained.
+				metadata, err := getSourceMetadataFromAlias(sourceAlias, sourceURL.String(), EncryptOpts{})
 				if err != nil {
 					return sURLs.WithError(err.Trace(sourceURL.String()))
 				}
-				_, err = putTargetStreamFromAlias(targetAlias, targetURL.String(), reader, length, ms.status)
+				uploadOpts.Metadata = metadata
+				reader, err := getSourceStreamFromAlias(ctx, sourceAlias, sourceURL.String(), GetOpts{})
+				if err != nil {
+					return sURLs.WithError(err.Trace(sourceURL.String()))
+				}
+				_, err = putTargetStreamFromAlias(ctx, targetAlias, targetURL.String(), reader, length, progress, uploadOpts)
 				if err != nil {
 					return sURLs.WithError(err.Trace(targetURL.String()))
 				}
 			}
 		}
 	} else {
-		// Standard GET/PUT for size > 5GB
-		reader, err := getSourceStreamFromAlias(sourceAlias, sourceURL.String())
+		// Objects uploaded from links are restored as links.
+		isSymlink, err := restoreSymlink(sourceAlias, sURLs.SourceContent, targetURL, EncryptOpts{})
+		if err != nil {
+			return sURLs.WithError(err.Trace(sourceURL.String()))
+		}
+		if isSymlink {
+			return sURLs.WithError(nil)
+		}
+		// Standard GET/PUT across server types.
+		reader, err := getSourceStreamFromAlias(ctx, sourceAlias, sourceURL.String(), GetOpts{})
 		if err != nil {
 			return sURLs.WithError(err.Trace(sourceURL.String()))
 		}
-		_, err = putTargetStreamFromAlias(targetAlias, targetURL.String(), reader, length, ms.status)
+		_, err = putTargetStreamFromAlias(ctx, targetAlias, targetURL.String(), reader, length, progress, uploadOpts)
 		if err != nil {
 			return sURLs.WithError(err.Trace(targetURL.String()))
 		}
 	}
 
+	if uploadOpts.Preserve {
+		if err := restoreTargetAttrs(sourceAlias, sourceURL, targetURL, EncryptOpts{}); err != nil {
+			return sURLs.WithError(err.Trace(targetURL.String()))
+		}
+	}
+
 	return sURLs.WithError(nil)
 }
 
@@ -295,6 +485,9 @@ func (ms *mirrorSession) startStatus() {
 					errorIf(sURLs.Error.Trace(sURLs.TargetContent.URL.String()),
 						fmt.Sprintf("Failed to remove ‘%s’.", sURLs.TargetContent.URL.String()))
 				}
+				ms.isFailed = true
+				ms.report.Failed(sURLs)
+				globalMetrics.Add(metricErrors, 1)
 
 				// For all non critical errors we can continue for the
 				// remaining files.
@@ -311,9 +504,9 @@ func (ms *mirrorSession) startStatus() {
 
 				// For critical errors we should exit. Session
 				// can be resumed after the user figures out
-				// the  problem. We know that
-				// there are no current copy actions, because
-				// we're not multi threading now.
+				// the  problem. Copies still running in
+				// parallel are saved with the queue and
+				// restarted on resume.
 
 				// this issue could be separated using separate
 				// error channel instead of using sURLs.Error
@@ -327,14 +520,21 @@ func (ms *mirrorSession) startStatus() {
 
 			if sURLs.SourceContent != nil {
 				ms.Header.LastCopied = sURLs.SourceContent.URL.String()
+				ms.report.Transferred(sURLs.SourceContent.Size)
+				globalMetrics.Add(metricTransferredObjects, 1)
+				globalMetrics.Add(metricTransferredBytes, float64(sURLs.SourceContent.Size))
 			} else if sURLs.TargetContent != nil {
 				ms.Header.LastRemoved = sURLs.TargetContent.URL.String()
+				ms.report.Removed()
+				globalMetrics.Add(metricRemovedObjects, 1)
 			}
 
 			ms.Save()
 
 			if sURLs.SourceContent != nil {
 			} else if sURLs.TargetContent != nil {
+				// Removed objects are done as well.
+				ms.status.FinishFile(nil)
 				// Construct user facing message and path.
 				targetPath := filepath.ToSlash(filepath.Join(sURLs.TargetAlias, sURLs.TargetContent.URL.Path))
 				ms.status.PrintMsg(rmMessage{
@@ -348,42 +548,68 @@ func (ms *mirrorSession) startStatus() {
 
 func (ms *mirrorSession) startMirror(wait bool) {
 	isRemove := ms.Header.CommandBoolFlags["remove"]
-
-	ms.wgMirror.Add(1)
+	parallel, _ := parseParallel(ms.Header.CommandIntFlags["parallel"])
 
 	// wait for new urls to mirror or delete in the queue, and
-	// run the actual mirror or remove.
-	go func() {
-		defer ms.wgMirror.Done()
-
-		for {
-			if !wait {
-			} else if err := ms.queue.Wait(); err != nil {
-				break
-			}
+	// run the actual mirror or remove, up to ‘--parallel’ at once.
+	for i := 0; i < parallel; i++ {
+		ms.wgMirror.Add(1)
+		go func() {
+			defer ms.wgMirror.Done()
+
+			for {
+				if !wait {
+				} else if err := ms.queue.Wait(); err != nil {
+					break
+				}
 
-			v := ms.queue.Pop()
-			if v == nil {
-				break
-			}
+				v := ms.queue.Pop()
+				if v == nil {
+					if wait {
+						// another worker took it.
+						continue
+					}
+					break
+				}
 
-			sURLs, ok := v.(URLs)
-			if !ok {
-				fatalIf(errInvalidArgument(), fmt.Sprintf("URLs type not found, %#v", v))
-			}
+				sURLs, ok := v.(URLs)
+				if !ok {
+					fatalIf(errInvalidArgument(), fmt.Sprintf("URLs type not found, %#v", v))
+				}
 
-			if sURLs.SourceContent != nil {
-				ms.statusCh <- ms.doMirror(sURLs)
-			} else if sURLs.TargetContent != nil && isRemove {
-				ms.statusCh <- ms.doRemove(sURLs)
+				if sURLs.SourceContent != nil {
+					sURLs = ms.doMirror(globalContext, sURLs)
+				} else if sURLs.TargetContent != nil && isRemove && ms.stagingPrefix != "" {
+					// Removed once the staged objects are committed.
+					ms.stagingM.Lock()
+					ms.stagedRemovals = append(ms.stagedRemovals, sURLs)
+					ms.stagingM.Unlock()
+					ms.queue.Done(v)
+					continue
+				} else if sURLs.TargetContent != nil && isRemove {
+					sURLs = ms.doRemove(sURLs)
+				} else {
+					ms.queue.Done(v)
+					continue
+				}
+				// Interrupted transfers stay queued, so that they
+				// are retried by the resumed session.
+				if globalContext.Err() != nil {
+					break
+				}
+				ms.queue.Done(v)
+				ms.statusCh <- sURLs
 			}
-		}
-	}()
+		}()
+	}
 }
 
-// this goroutine will watch for notifications, and add modified objects to the queue
+// this goroutine will watch for notifications, and add modified objects to the queue.
+// It is started before the source is listed, so that changes during the initial
+// mirror are not lost.
 func (ms *mirrorSession) watch() {
 	isForce := ms.Header.CommandBoolFlags["force"]
+	isRemove := ms.Header.CommandBoolFlags["remove"]
 
 	for {
 		select {
@@ -392,6 +618,7 @@ func (ms *mirrorSession) watch() {
 				// channel closed
 				return
 			}
+			globalMetrics.Add(metricEvents, 1, "type", string(event.Type))
 
 			// this code seems complicated, it will change the expanded alias back to the alias
 			// again, by replacing the sourceUrlFull with the sourceAlias. This url will be
@@ -404,6 +631,9 @@ func (ms *mirrorSession) watch() {
 			// build target path, it is the relative of the event.Path with the sourceUrl
 			// joined to the targetURL.
 			sourceSuffix := strings.TrimPrefix(event.Path, sourceURLFull)
+			if !ms.filter.Matches(sourceSuffix, false) {
+				continue
+			}
 			targetPath := urlJoinPath(ms.targetURL, sourceSuffix)
 
 			// newClient needs the unexpanded  path, newCLientURL needs the expanded path
@@ -417,9 +647,9 @@ func (ms *mirrorSession) watch() {
 				// overwrite it when force is enabled.
 				mirrorURL := URLs{
 					SourceAlias:   sourceAlias,
-					SourceContent: &clientContent{URL: *sourceURL},
+					SourceContent: &ClientContent{URL: *sourceURL},
 					TargetAlias:   targetAlias,
-					TargetContent: &clientContent{URL: *targetURL},
+					TargetContent: &ClientContent{URL: *targetURL},
 				}
 				if event.Size == 0 {
 					sourceClient, err := newClient(aliasedPath)
@@ -434,11 +664,14 @@ func (ms *mirrorSession) watch() {
 						ms.statusCh <- mirrorURL.WithError(err)
 						continue
 					}
+					if !ms.filter.MatchesContent(sourceSuffix, sourceContent) {
+						continue
+					}
 					targetClient, err := newClient(targetPath)
 					if err != nil {
 						// cannot create targetclient
 						ms.statusCh <- mirrorURL.WithError(err)
-						return
+						continue
 					}
 					shouldQueue := false
 					if !isForce {
@@ -459,13 +692,17 @@ func (ms *mirrorSession) watch() {
 					}
 					continue
 				}
+				// Files written just now are only filtered by size.
+				if !ms.filter.MatchesContent(sourceSuffix, &ClientContent{Size: event.Size}) {
+					continue
+				}
 				shouldQueue := false
 				if !isForce {
 					targetClient, err := newClient(targetPath)
 					if err != nil {
 						// cannot create targetclient
 						ms.statusCh <- mirrorURL.WithError(err)
-						return
+						continue
 					}
 					_, err = targetClient.Stat()
 					if err == nil {
@@ -482,12 +719,12 @@ func (ms *mirrorSession) watch() {
 					// adjust total, because we want to show progress of the items stiil queued to be copied.
 					ms.status.SetTotal(ms.status.Total() + event.Size).Update()
 				}
-			} else if event.Type == EventRemove {
+			} else if event.Type == EventRemove && isRemove {
 				mirrorURL := URLs{
 					SourceAlias:   sourceAlias,
 					SourceContent: nil,
 					TargetAlias:   targetAlias,
-					TargetContent: &clientContent{URL: *targetURL},
+					TargetContent: &ClientContent{URL: *targetURL},
 				}
 				if err := ms.queue.Push(mirrorURL); err != nil {
 					// will throw an error if already queue, ignoring this error
@@ -497,6 +734,12 @@ func (ms *mirrorSession) watch() {
 
 		case err := <-ms.watcher.Errors():
 			errorIf(err, "Unexpected error during monitoring.")
+			globalMetrics.Add(metricErrors, 1)
+		case status, ok := <-ms.watcher.Status():
+			if !ok {
+				return
+			}
+			ms.status.PrintMsg(newWatchStatusMessage(status))
 		}
 	}
 }
@@ -509,6 +752,16 @@ func (ms *mirrorSession) unwatchSourceURL(recursive bool) *probe.Error {
 	return err
 }
 
+// startWatch - watches the source and queues its changes until the
+// watcher is stopped.
+func (ms *mirrorSession) startWatch() *probe.Error {
+	if err := ms.watchSourceURL(true); err != nil {
+		return err.Trace(ms.sourceURL)
+	}
+	go ms.watch()
+	return nil
+}
+
 func (ms *mirrorSession) watchSourceURL(recursive bool) *probe.Error {
 	sourceClient, err := newClient(ms.sourceURL)
 	if err == nil {
@@ -541,11 +794,30 @@ func (ms *mirrorSession) harvestSourceUrls(recursive bool) {
 	isForce := ms.Header.CommandBoolFlags["force"]
 	isFake := ms.Header.CommandBoolFlags["fake"]
 	isRemove := ms.Header.CommandBoolFlags["remove"]
+	isChecksum, _ := parseCompareMode(ms.Header.CommandStringFlags["compare"])
 
 	defer close(ms.harvestCh)
 
-	URLsCh := prepareMirrorURLs(ms.sourceURL, ms.targetURL, isForce, isFake, isRemove)
+	// Staged objects on the target are neither mirrored nor removed.
+	stagingRoot := ""
+	if ms.stagingPrefix != "" {
+		_, stagingRoot, _ = ms.stagingRoot()
+	}
+
+	// Listings of the target taken before are stale once it is modified.
+	if !isFake {
+		ms.invalidateTarget()
+	}
+
+	URLsCh := prepareMirrorURLs(ms.sourceURL, ms.targetURL, isForce, isFake, isRemove, isChecksum, ms.filter, ms.cache, ms.state)
 	for url := range URLsCh {
+		if url.Error != nil {
+			ms.state.Fail()
+		}
+		if stagingRoot != "" && url.SourceContent == nil && url.TargetContent != nil &&
+			strings.HasPrefix(url.TargetContent.URL.String(), stagingRoot) {
+			continue
+		}
 		ms.harvestCh <- url
 	}
 }
@@ -610,10 +882,13 @@ loop:
 	// update session file and save
 	ms.Header.TotalBytes = totalBytes
 	ms.Header.TotalObjects = totalObjects
+	ms.Header.Prepared = true
 	ms.Save()
 
-	// update progressbar and accounting reader
-	ms.status.SetTotal(totalBytes)
+	// update progressbar and accounting reader, objects changed while
+	// watching are already accounted for.
+	ms.status.SetTotal(ms.status.Total() + totalBytes)
+	ms.status.SetTotalObjects(int64(totalObjects))
 }
 
 // when using a struct for copying, we could save a lot of passing of variables
@@ -621,16 +896,35 @@ func (ms *mirrorSession) mirror() {
 	watch := ms.Header.CommandBoolFlags["watch"]
 	recursive := ms.Header.CommandBoolFlags["recursive"]
 
-	if globalQuiet {
+	// Metrics are exposed while mirroring, resumed sessions too.
+	metricsAddress, statsdAddress := ms.Header.CommandStringFlags["metrics-address"], ms.Header.CommandStringFlags["statsd-address"]
+	if err := startMetrics(metricsAddress, statsdAddress); err != nil {
+		ms.status.fatalIf(err.Trace(metricsAddress, statsdAddress), "Unable to expose metrics.")
+	}
+
+	if globalNoProgress {
 	} else if globalJSON {
 	} else {
 		// Enable progress bar reader only during default mode
 		ms.scanBar = scanBarFactory()
 	}
 
+	// monitor mode will watch the source folders for changes,
+	// and queue them for copying. Watching starts before the
+	// source is listed, changes made during the initial mirror
+	// are queued after it.
+	if watch {
+		if err := ms.startWatch(); err != nil {
+			ms.status.fatalIf(err, fmt.Sprintf("Failed to start monitoring."))
+		}
+	}
+
 	// harvest urls to copy
 	ms.harvest(recursive)
 
+	// the summary is printed once the progress bar is finished
+	defer ms.printSummary()
+
 	// now we want to start the progress bar
 	ms.status.Start()
 	defer ms.status.Finish()
@@ -650,27 +944,23 @@ func (ms *mirrorSession) mirror() {
 		ms.unwatchSourceURL(true)
 
 		// no items left, just stop
-		if ms.queue.Count() == 0 {
+		if ms.queue.Count() == 0 && ms.queue.Active() == 0 {
 			ms.Delete()
 			os.Exit(0)
 			return
 		}
 
+		// Transfers interrupted are saved along with the queue.
+		if err := ms.queue.Save(ms.NewDataWriter()); err != nil {
+			ms.status.fatalIf(probe.NewError(err), "Unable to save queue.")
+		}
 		ms.CloseAndDie()
 	}()
 
-	// monitor mode will watch the source folders for changes,
-	// and queue them for copying. Monitor mode can be stopped
-	// only by SIGTERM.
+	// Monitor mode can be stopped only by SIGTERM.
 	if watch {
-		if err := ms.watchSourceURL(true); err != nil {
-			ms.status.fatalIf(err, fmt.Sprintf("Failed to start monitoring."))
-		}
-
 		ms.startMirror(true)
 
-		ms.watch()
-
 		// don't let monitor finish, only on SIGTERM
 		done := make(chan bool)
 		<-done
@@ -680,6 +970,106 @@ func (ms *mirrorSession) mirror() {
 		// wait for copy to finish
 		ms.wgMirror.Wait()
 		ms.shutdown()
+
+		if ms.stagingPrefix != "" && !ms.Header.CommandBoolFlags["fake"] {
+			ms.commitStaged()
+		}
+		if !ms.Header.CommandBoolFlags["fake"] {
+			ms.invalidateTarget()
+		}
+		if ms.Header.CommandBoolFlags["fake"] || ms.isFailed {
+			ms.state.Fail()
+		}
+		errorIf(ms.state.Commit().Trace(ms.sourceURL, ms.targetURL), "Unable to save the state of the mirror.")
+	}
+}
+
+// invalidateTarget - retires cached listings of the bucket of the
+// target, which were taken before it was modified.
+func (ms *mirrorSession) invalidateTarget() {
+	targetAlias, targetURL, _ := mustExpandAlias(ms.targetURL)
+	errorIf(ms.cache.Invalidate(targetAlias, targetURL).Trace(targetURL), "Unable to invalidate cached listings of ‘"+ms.targetURL+"’.")
+}
+
+// stagingRoot - expanded URLs of the target folder and of the folder
+// its objects are staged in, both with a trailing separator.
+func (ms *mirrorSession) stagingRoot() (targetURL string, stagingRoot string, err *probe.Error) {
+	targetAlias, targetURL, _ := mustExpandAlias(ms.targetURL)
+	separator := string(newClientURL(targetURL).Separator)
+	if !strings.HasSuffix(targetURL, separator) {
+		targetURL = targetURL + separator
+	}
+	clnt, err := newClientFromAlias(targetAlias, targetURL)
+	if err != nil {
+		return "", "", err.Trace(targetURL)
+	}
+	stagingRoot, err = stagingURL(clnt, ms.stagingPrefix)
+	if err != nil {
+		return "", "", err.Trace(targetURL)
+	}
+	if !strings.HasSuffix(stagingRoot, separator) {
+		stagingRoot = stagingRoot + separator
+	}
+	return targetURL, stagingRoot, nil
+}
+
+// commitStaged - copies all objects staged for the target to their
+// names and removes extraneous objects, unless any object failed.
+func (ms *mirrorSession) commitStaged() {
+	if ms.isFailed {
+		errorIf(errStagingNotCommitted(ms.stagingPrefix).Trace(ms.stagingPrefix), "Unable to commit staged objects.")
+		return
+	}
+	targetAlias, _, _ := mustExpandAlias(ms.targetURL)
+	targetURL, stagingRoot, err := ms.stagingRoot()
+	if err != nil {
+		errorIf(err.Trace(ms.targetURL), "Unable to commit staged objects.")
+		return
+	}
+	clnt, err := newClientFromAlias(targetAlias, stagingRoot)
+	if err != nil {
+		errorIf(err.Trace(stagingRoot), "Unable to commit staged objects.")
+		return
+	}
+
+	// Objects staged by resumed sessions are committed as well.
+	var objects []stagedObject
+	for content := range clnt.List(globalContext, true, false) {
+		if content.Err != nil {
+			errorIf(content.Err.Trace(stagingRoot), "Unable to commit staged objects.")
+			return
+		}
+		if !content.Type.IsRegular() {
+			continue
+		}
+		objects = append(objects, stagedObject{
+			Alias:     targetAlias,
+			StagedURL: content.URL.String(),
+			FinalURL:  targetURL + strings.TrimPrefix(content.URL.String(), stagingRoot),
+			Size:      content.Size,
+		})
+	}
+	commitStagedObjects(objects, nil)
+
+	for _, sURLs := range ms.stagedRemovals {
+		targetURL := sURLs.TargetContent.URL.String()
+		if err := rm(sURLs.TargetAlias, targetURL, false, false, time.Duration(0), nil); err != nil {
+			errorIf(err.Trace(targetURL), fmt.Sprintf("Failed to remove ‘%s’.", targetURL))
+			continue
+		}
+		targetPath := filepath.ToSlash(filepath.Join(sURLs.TargetAlias, sURLs.TargetContent.URL.Path))
+		printMsg(rmMessage{Status: "success", URL: targetPath})
+		ms.report.Removed()
+	}
+}
+
+// printSummary - prints the summary of the mirror and writes the
+// failure manifest.
+func (ms *mirrorSession) printSummary() {
+	printMsg(ms.report.Summary())
+	manifestPath := ms.Header.CommandStringFlags["failure-manifest"]
+	if err := ms.report.WriteFailures(manifestPath); err != nil {
+		errorIf(err.Trace(manifestPath), "Unable to write failure manifest.")
 	}
 }
 
@@ -708,7 +1098,7 @@ func newMirrorSession(session *sessionV8) *mirrorSession {
 	// we'll define the status to use here,
 	// do we want the quiet status? or the progressbar
 	var status = NewProgressStatus()
-	if globalQuiet {
+	if globalNoProgress {
 		status = NewQuietStatus()
 	} else if globalJSON {
 		status = NewDummyStatus()
@@ -732,6 +1122,7 @@ func newMirrorSession(session *sessionV8) *mirrorSession {
 		wgStatus: new(sync.WaitGroup),
 		wgMirror: new(sync.WaitGroup),
 		m:        new(sync.Mutex),
+		stagingM: new(sync.Mutex),
 
 		status: status,
 		// scanbar starts with no action
@@ -740,6 +1131,40 @@ func newMirrorSession(session *sessionV8) *mirrorSession {
 
 		sourceURL: args[0],
 		targetURL: args[len(args)-1], // Last one is target
+
+		stagingPrefix: session.Header.CommandStringFlags["staging-prefix"],
+
+		report: newTransferReport(),
+	}
+
+	// Upload options are part of the session, so that resumed
+	// sessions continue with the same options.
+	uploadOpts, err := parsePutOpts(session.Header.CommandStringFlags["part-size"], session.Header.CommandIntFlags["concurrent-parts"])
+	if err != nil {
+		session.Delete()
+		fatalIf(err.Trace(), "Unable to parse upload options.")
+	}
+	uploadOpts.Preserve = session.Header.CommandBoolFlags["preserve"]
+	ms.uploadOpts = uploadOpts
+	ms.filter, err = newContentFilter(filterOptsFromSession(session))
+	if err != nil {
+		session.Delete()
+		fatalIf(err.Trace(), "Unable to parse filters.")
+	}
+	// Links are listed by the mode of the session.
+	if fsSymlinks, err = symlinkModeFromSession(session); err != nil {
+		session.Delete()
+		fatalIf(err.Trace(), "Unable to parse symlink flags.")
+	}
+	ms.cache, err = newListingCache(session.Header.CommandStringFlags["cache-ttl"], session.Header.CommandBoolFlags["no-cache"])
+	if err != nil {
+		session.Delete()
+		fatalIf(err.Trace(), "Unable to initialize listing cache.")
+	}
+	ms.state, err = newMirrorState(session.Header.CommandBoolFlags["incremental"])
+	if err != nil {
+		session.Delete()
+		fatalIf(err.Trace(), "Unable to initialize mirror state.")
 	}
 
 	return &ms
@@ -753,8 +1178,14 @@ func mainMirror(ctx *cli.Context) {
 	// check 'mirror' cli arguments.
 	checkMirrorSyntax(ctx)
 
+	// Removing extraneous objects is confirmed, these are gone for good.
+	if ctx.Bool("remove") && ctx.Bool("force") && !ctx.Bool("fake") {
+		mustConfirm(ctx, trf("Objects of ‘%s’ missing in ‘%s’ will be removed.", ctx.Args()[1], ctx.Args()[0]))
+	}
+
 	// Additional command speific theme customization.
 	console.SetColor("Mirror", color.New(color.FgGreen, color.Bold))
+	console.SetColor("Summary", color.New(color.FgGreen))
 
 	session := newSessionV8()
 	session.Header.CommandType = "mirror"
@@ -772,6 +1203,22 @@ func mainMirror(ctx *cli.Context) {
 	session.Header.CommandBoolFlags["fake"] = ctx.Bool("fake")
 	session.Header.CommandBoolFlags["watch"] = ctx.Bool("watch")
 	session.Header.CommandBoolFlags["remove"] = ctx.Bool("remove")
+	session.Header.CommandBoolFlags["follow-symlinks"] = ctx.Bool("follow-symlinks")
+	session.Header.CommandBoolFlags["skip-symlinks"] = ctx.Bool("skip-symlinks")
+	session.Header.CommandBoolFlags["preserve-links"] = ctx.Bool("preserve-links")
+	session.Header.CommandBoolFlags["preserve"] = ctx.Bool("preserve")
+	session.Header.CommandBoolFlags["no-cache"] = ctx.Bool("no-cache")
+	session.Header.CommandBoolFlags["incremental"] = ctx.Bool("incremental")
+	session.Header.CommandStringFlags["part-size"] = ctx.String("part-size")
+	session.Header.CommandStringFlags["compare"] = ctx.String("compare")
+	session.Header.CommandStringFlags["cache-ttl"] = ctx.String("cache-ttl")
+	session.Header.CommandIntFlags["concurrent-parts"] = ctx.Int("concurrent-parts")
+	session.Header.CommandIntFlags["parallel"] = ctx.Int("parallel")
+	session.Header.CommandStringFlags["staging-prefix"] = ctx.String("staging-prefix")
+	session.Header.CommandStringFlags["failure-manifest"] = ctx.String("failure-manifest")
+	session.Header.CommandStringFlags["metrics-address"] = ctx.String("metrics-address")
+	session.Header.CommandStringFlags["statsd-address"] = ctx.String("statsd-address")
+	setSessionFilterOpts(session, filterOptsFromContext(ctx))
 
 	// extract URLs.
 	session.Header.CommandArgs = ctx.Args()
@@ -0,0 +1,205 @@
+/*
+ * Minio Client (C) 2016 Minio, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"bufio"
+	"encoding/json"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/minio/minio/pkg/probe"
+)
+
+// mirrorStateEntry - a file of the source as last mirrored, keyed by
+// its path relative to the source folder.
+type mirrorStateEntry struct {
+	Key  string      `json:"key"`
+	Size int64       `json:"size"`
+	Time time.Time   `json:"time"`
+	Type os.FileMode `json:"type"`
+	ETag string      `json:"etag,omitempty"`
+}
+
+// mirrorState - state of the files of the source as last mirrored to
+// the target, one entry per line in listing order. Once a state of a
+// pair of folders is known, the source is compared against it by size,
+// ETag and modification time instead of listing the target. The files
+// listed are recorded as the new state, which replaces the old one
+// only if the mirror succeeded. A nil state lists both folders.
+type mirrorState struct {
+	dir string
+
+	mutex   sync.Mutex
+	path    string
+	f       *os.File
+	w       *bufio.Writer
+	encoder *json.Encoder
+	failed  bool
+}
+
+// getMirrorStateDir - get mirror state directory.
+func getMirrorStateDir() (string, *probe.Error) {
+	configDir, err := getMcConfigDir()
+	if err != nil {
+		return "", err.Trace()
+	}
+	return filepath.Join(configDir, globalMirrorStateDir), nil
+}
+
+// newMirrorState - state in the mc config folder, nil unless mirroring
+// incrementally.
+func newMirrorState(isIncremental bool) (*mirrorState, *probe.Error) {
+	if !isIncremental {
+		return nil, nil
+	}
+	dir, err := getMirrorStateDir()
+	if err != nil {
+		return nil, err.Trace()
+	}
+	if e := os.MkdirAll(dir, 0700); e != nil {
+		return nil, probe.NewError(e)
+	}
+	return &mirrorState{dir: dir}, nil
+}
+
+// Difference - finds the difference between source and target, the
+// target is only listed if no state of both folders is known yet.
+// Files kept by the filter are recorded as the new state.
+func (s *mirrorState) Difference(sourceClnt, targetClnt Client, sourceURL, targetURL string, compare compareOpts, filter *contentFilter) chan diffMessage {
+	if s == nil {
+		return objectDifference(sourceClnt, targetClnt, sourceURL, targetURL, compare)
+	}
+	s.path = filepath.Join(s.dir, hashKey(compare.SourceAlias, sourceURL, compare.TargetAlias, targetURL)+".json")
+	srcCh := s.record(compare.SourceCache.List(globalContext, sourceClnt, compare.SourceAlias), sourceURL, filter)
+	if tgtCh, ok := s.read(targetURL); ok {
+		compare.ETag = true
+		compare.Time = true
+		return listingDifference(srcCh, tgtCh, sourceURL, targetURL, compare)
+	}
+	tgtCh := compare.TargetCache.List(globalContext, targetClnt, compare.TargetAlias)
+	return listingDifference(srcCh, tgtCh, sourceURL, targetURL, compare)
+}
+
+// read - streams the entries of the state as contents of the target,
+// false if there is no state yet.
+func (s *mirrorState) read(targetURL string) (<-chan *ClientContent, bool) {
+	f, e := os.Open(s.path)
+	if e != nil {
+		return nil, false
+	}
+	contentCh := make(chan *ClientContent)
+	go func() {
+		defer close(contentCh)
+		defer f.Close()
+		decoder := json.NewDecoder(bufio.NewReader(f))
+		for {
+			var entry mirrorStateEntry
+			if e := decoder.Decode(&entry); e != nil {
+				break
+			}
+			contentCh <- &ClientContent{
+				URL:  *newClientURL(urlJoinPath(targetURL, entry.Key)),
+				Size: entry.Size,
+				Time: entry.Time,
+				Type: entry.Type,
+				ETag: entry.ETag,
+			}
+		}
+	}()
+	return contentCh, true
+}
+
+// record - passes the listed contents along, recording the files kept
+// by the filter. Listing errors fail the new state.
+func (s *mirrorState) record(contentCh <-chan *ClientContent, sourceURL string, filter *contentFilter) <-chan *ClientContent {
+	f, e := ioutil.TempFile(s.dir, "state-")
+	if e == nil {
+		s.f = f
+		s.w = bufio.NewWriter(f)
+		s.encoder = json.NewEncoder(s.w)
+	}
+	recordCh := make(chan *ClientContent)
+	go func() {
+		defer close(recordCh)
+		for content := range contentCh {
+			key := strings.TrimPrefix(content.URL.String(), sourceURL)
+			if content.Err != nil {
+				s.Fail()
+			} else if filter.MatchesContent(key, content) {
+				s.add(mirrorStateEntry{Key: key, Size: content.Size, Time: content.Time, Type: content.Type, ETag: content.ETag})
+			}
+			recordCh <- content
+		}
+	}()
+	return recordCh
+}
+
+// add - appends an entry to the new state.
+func (s *mirrorState) add(entry mirrorStateEntry) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	if s.f == nil || s.failed {
+		return
+	}
+	if e := s.encoder.Encode(entry); e != nil {
+		s.failed = true
+	}
+}
+
+// Fail - the new state is not to be kept, files which failed to
+// mirror are compared again by the next mirror.
+func (s *mirrorState) Fail() {
+	if s == nil {
+		return
+	}
+	s.mutex.Lock()
+	s.failed = true
+	s.mutex.Unlock()
+}
+
+// Commit - replaces the state with the new one, unless it failed.
+func (s *mirrorState) Commit() *probe.Error {
+	if s == nil {
+		return nil
+	}
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	if s.f == nil {
+		return nil
+	}
+	name := s.f.Name()
+	e := s.w.Flush()
+	if ce := s.f.Close(); e == nil {
+		e = ce
+	}
+	s.f = nil
+	if e == nil && !s.failed {
+		e = os.Rename(name, s.path)
+	}
+	if e != nil || s.failed {
+		os.Remove(name)
+	}
+	if e != nil {
+		return probe.NewError(e)
+	}
+	return nil
+}
@@ -0,0 +1,103 @@
+/*
+ * Minio Client (C) 2016 Minio, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"time"
+
+	. "gopkg.in/check.v1"
+)
+
+// Test mirroring against the state of the last mirror.
+func (s *TestSuite) TestMirrorState(c *C) {
+	root, e := ioutil.TempDir(os.TempDir(), "mirror-state-")
+	c.Assert(e, IsNil)
+	defer os.RemoveAll(root)
+
+	source := filepath.Join(root, "source") + string(filepath.Separator)
+	target := filepath.Join(root, "target") + string(filepath.Separator)
+	stateDir := filepath.Join(root, "state")
+	modTime := time.Now().Add(-time.Hour)
+	for _, dir := range []string{source, target, stateDir} {
+		c.Assert(os.MkdirAll(dir, 0700), IsNil)
+	}
+	write := func(path, data string, modTime time.Time) {
+		c.Assert(ioutil.WriteFile(path, []byte(data), 0600), IsNil)
+		c.Assert(os.Chtimes(path, modTime, modTime), IsNil)
+	}
+	write(source+"a", "a", modTime)
+	write(source+"b", "bb", modTime)
+	write(source+"c", "c", modTime)
+	write(target+"a", "a", modTime)
+	write(target+"c", "cc", modTime)
+
+	difference := func(state *mirrorState, targetURL string) map[string]differType {
+		sourceClnt, err := fsNew(source)
+		c.Assert(err, IsNil)
+		targetClnt, err := fsNew(targetURL)
+		c.Assert(err, IsNil)
+		diffs := map[string]differType{}
+		for diffMsg := range state.Difference(sourceClnt, targetClnt, source, targetURL, compareOpts{}, nil) {
+			url := diffMsg.FirstURL
+			if url == "" {
+				url = diffMsg.SecondURL
+			}
+			diffs[filepath.Base(url)] = diffMsg.Diff
+		}
+		return diffs
+	}
+
+	// Without a state the target is listed.
+	state := &mirrorState{dir: stateDir}
+	c.Assert(difference(state, target), DeepEquals, map[string]differType{
+		"b": differInFirst,
+		"c": differInSize,
+	})
+	c.Assert(state.Commit(), IsNil)
+
+	// Once mirrored, the source is compared against its last state,
+	// the target is not listed.
+	c.Assert(os.Remove(target+"a"), IsNil)
+	write(source+"a", "a", modTime.Add(time.Minute))
+	c.Assert(os.Remove(source+"b"), IsNil)
+	write(source+"d", "d", modTime)
+	state = &mirrorState{dir: stateDir}
+	c.Assert(difference(state, target), DeepEquals, map[string]differType{
+		"a": differInNewerFirst,
+		"b": differInSecond,
+		"d": differInFirst,
+	})
+
+	// Failed mirrors keep the last state.
+	state.Fail()
+	c.Assert(state.Commit(), IsNil)
+	state = &mirrorState{dir: stateDir}
+	c.Assert(len(difference(state, target)), Equals, 3)
+	c.Assert(state.Commit(), IsNil)
+	state = &mirrorState{dir: stateDir}
+	c.Assert(difference(state, target), DeepEquals, map[string]differType{})
+
+	// Nil states list both folders.
+	c.Assert(difference(nil, target), DeepEquals, map[string]differType{
+		"a": differInFirst,
+		"c": differInSize,
+		"d": differInFirst,
+	})
+}
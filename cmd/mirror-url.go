@@ -39,6 +39,33 @@ func checkMirrorSyntax(ctx *cli.Context) {
 	srcURL := URLs[0]
 	tgtURL := URLs[1]
 
+	// Verify upload options before doing anything.
+	if _, err := parsePutOpts(ctx.String("part-size"), ctx.Int("concurrent-parts")); err != nil {
+		fatalIf(err.Trace(), "Unable to parse upload options.")
+	}
+	if _, err := parseParallel(ctx.Int("parallel")); err != nil {
+		fatalIf(err.Trace(), "Unable to parse number of parallel transfers.")
+	}
+	if _, err := newContentFilter(filterOptsFromContext(ctx)); err != nil {
+		fatalIf(err.Trace(), "Unable to parse filters.")
+	}
+	if _, err := parseCompareMode(ctx.String("compare")); err != nil {
+		fatalIf(err.Trace(), "Unable to parse compare mode.")
+	}
+	if _, err := parseCacheTTL(ctx.String("cache-ttl"), ctx.Bool("no-cache")); err != nil {
+		fatalIf(err.Trace(), "Unable to parse cache TTL.")
+	}
+	checkStagingSyntax(tgtURL, ctx.String("staging-prefix"))
+	if _, err := parseSymlinkMode(ctx.Bool("follow-symlinks"), ctx.Bool("skip-symlinks"), ctx.Bool("preserve-links")); err != nil {
+		fatalIf(err.Trace(), "Unable to parse symlink flags.")
+	}
+	if ctx.String("staging-prefix") != "" && ctx.Bool("watch") {
+		fatalIf(errInvalidArgument().Trace(), "Staging objects is not supported while watching.")
+	}
+	if ctx.Bool("incremental") && ctx.Bool("watch") {
+		fatalIf(errInvalidArgument().Trace(), "Incremental mirroring is not supported while watching.")
+	}
+
 	/****** Generic rules *******/
 	_, srcContent, err := url2Stat(srcURL)
 	// incomplete uploads are not necessary for copy operation, no need to verify for them.
@@ -71,7 +98,7 @@ func checkMirrorSyntax(ctx *cli.Context) {
 	}
 }
 
-func deltaSourceTarget(sourceURL string, targetURL string, isForce bool, isFake bool, isRemove bool, URLsCh chan<- URLs) {
+func deltaSourceTarget(sourceURL string, targetURL string, isForce bool, isFake bool, isRemove bool, isChecksum bool, filter *contentFilter, cache *listingCache, state *mirrorState, URLsCh chan<- URLs) {
 	// source and targets are always directories
 	sourceSeparator := string(newClientURL(sourceURL).Separator)
 	if !strings.HasSuffix(sourceURL, sourceSeparator) {
@@ -101,7 +128,17 @@ func deltaSourceTarget(sourceURL string, targetURL string, isForce bool, isFake
 	}
 
 	// List both source and target, compare and return values through channel.
-	for diffMsg := range objectDifference(sourceClnt, targetClnt, sourceURL, targetURL) {
+	// Only the source is listed through the cache, the target is modified.
+	compare := compareOpts{Checksum: isChecksum, SourceAlias: sourceAlias, TargetAlias: targetAlias, SourceCache: cache}
+	for diffMsg := range state.Difference(sourceClnt, targetClnt, sourceURL, targetURL, compare, filter) {
+		// Filtered files are neither copied nor removed.
+		if diffMsg.Diff == differInSecond {
+			if !matchesDiffContent(filter, strings.TrimPrefix(diffMsg.SecondURL, targetURL), diffMsg.secondContent) {
+				continue
+			}
+		} else if !matchesDiffContent(filter, strings.TrimPrefix(diffMsg.FirstURL, sourceURL), diffMsg.firstContent) {
+			continue
+		}
 		switch diffMsg.Diff {
 		case differInNone:
 			// No difference, continue.
@@ -109,9 +146,9 @@ func deltaSourceTarget(sourceURL string, targetURL string, isForce bool, isFake
 		case differInType:
 			URLsCh <- URLs{Error: errInvalidTarget(diffMsg.SecondURL)}
 			continue
-		case differInSize:
+		case differInSize, differInChecksum, differInNewerFirst, differInNewerSecond:
 			if !isForce && !isFake {
-				// Size differs and force not set
+				// Size, checksum or time differs and force not set
 				URLsCh <- URLs{Error: errOverWriteNotAllowed(diffMsg.SecondURL)}
 				continue
 			}
@@ -119,7 +156,7 @@ func deltaSourceTarget(sourceURL string, targetURL string, isForce bool, isFake
 			// Either available only in source or size differs and force is set
 			targetPath := urlJoinPath(targetURL, sourceSuffix)
 			sourceContent := diffMsg.firstContent
-			targetContent := &clientContent{URL: *newClientURL(targetPath)}
+			targetContent := &ClientContent{URL: *newClientURL(targetPath)}
 			URLsCh <- URLs{
 				SourceAlias:   sourceAlias,
 				SourceContent: sourceContent,
@@ -132,7 +169,7 @@ func deltaSourceTarget(sourceURL string, targetURL string, isForce bool, isFake
 			// Either available only in source or size differs and force is set
 			targetPath := urlJoinPath(targetURL, sourceSuffix)
 			sourceContent := diffMsg.firstContent
-			targetContent := &clientContent{URL: *newClientURL(targetPath)}
+			targetContent := &ClientContent{URL: *newClientURL(targetPath)}
 			URLsCh <- URLs{
 				SourceAlias:   sourceAlias,
 				SourceContent: sourceContent,
@@ -164,9 +201,18 @@ func deltaSourceTarget(sourceURL string, targetURL string, isForce bool, isFake
 	}
 }
 
+// matchesDiffContent - reports whether the filter keeps a content of
+// a difference, contents which were not listed are only matched by path.
+func matchesDiffContent(filter *contentFilter, relPath string, content *ClientContent) bool {
+	if content == nil {
+		return filter.Matches(relPath, false)
+	}
+	return filter.MatchesContent(relPath, content)
+}
+
 // Prepares urls that need to be copied or removed based on requested options.
-func prepareMirrorURLs(sourceURL string, targetURL string, isForce bool, isFake bool, isRemove bool) <-chan URLs {
+func prepareMirrorURLs(sourceURL string, targetURL string, isForce bool, isFake bool, isRemove bool, isChecksum bool, filter *contentFilter, cache *listingCache, state *mirrorState) <-chan URLs {
 	URLsCh := make(chan URLs)
-	go deltaSourceTarget(sourceURL, targetURL, isForce, isFake, isRemove, URLsCh)
+	go deltaSourceTarget(sourceURL, targetURL, isForce, isFake, isRemove, isChecksum, filter, cache, state, URLsCh)
 	return URLsCh
 }
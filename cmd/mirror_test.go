@@ -0,0 +1,107 @@
+/*
+ * Minio Client (C) 2016 Minio, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/minio/minio/pkg/probe"
+	. "gopkg.in/check.v1"
+)
+
+// Test queueing changes of the source made after it was listed by the
+// initial mirror, removals only with ‘--remove’.
+func (s *TestSuite) TestMirrorWatch(c *C) {
+	defer func(load func() (*configV8, *probe.Error)) { loadMcConfig = load }(loadMcConfig)
+	loadMcConfig = func() (*configV8, *probe.Error) { return newMcConfig(), nil }
+	defer func(noProgress bool) { globalNoProgress = noProgress }(globalNoProgress)
+	globalNoProgress = true
+	c.Assert(createSessionDir(), IsNil)
+
+	for _, remove := range []bool{false, true} {
+		root, e := ioutil.TempDir(os.TempDir(), "mirror-watch-")
+		c.Assert(e, IsNil)
+		defer os.RemoveAll(root)
+
+		source := filepath.Join(root, "source")
+		target := filepath.Join(root, "target")
+		c.Assert(os.MkdirAll(source, 0700), IsNil)
+		c.Assert(os.MkdirAll(target, 0700), IsNil)
+		c.Assert(ioutil.WriteFile(filepath.Join(source, "old"), []byte("old"), 0600), IsNil)
+
+		session := newSessionV8()
+		session.Header.CommandType = "mirror"
+		session.Header.CommandArgs = []string{source + string(filepath.Separator), target + string(filepath.Separator)}
+		session.Header.CommandBoolFlags["watch"] = true
+		session.Header.CommandBoolFlags["recursive"] = true
+		session.Header.CommandBoolFlags["remove"] = remove
+		ms := newMirrorSession(session)
+
+		// Watching starts before the source is listed, as in mirror.
+		c.Assert(ms.startWatch(), IsNil)
+		ms.harvest(true)
+
+		// popQueued - pops queued objects until one has the given
+		// source or target name.
+		popQueued := func(name string, isSource bool) []URLs {
+			var queued []URLs
+			for timeout := time.After(5 * time.Second); ; {
+				if v := ms.queue.Pop(); v != nil {
+					sURLs := v.(URLs)
+					queued = append(queued, sURLs)
+					if isSource && sURLs.SourceContent != nil && filepath.Base(sURLs.SourceContent.URL.Path) == name {
+						return queued
+					}
+					if !isSource && sURLs.SourceContent == nil && filepath.Base(sURLs.TargetContent.URL.Path) == name {
+						return queued
+					}
+					continue
+				}
+				select {
+				case <-timeout:
+					c.Fatalf("%s was not queued, remove %t.", name, remove)
+				case <-time.After(10 * time.Millisecond):
+				}
+			}
+		}
+		c.Assert(popQueued("old", true), HasLen, 1)
+
+		// Changes made during the initial mirror are queued after it.
+		c.Assert(os.Remove(filepath.Join(source, "old")), IsNil)
+		if remove {
+			c.Assert(popQueued("old", false), HasLen, 1)
+		}
+		c.Assert(ioutil.WriteFile(filepath.Join(source, "new"), []byte("new"), 0600), IsNil)
+		queued := popQueued("new", true)
+		c.Assert(queued[len(queued)-1].TargetContent.URL.Path, Equals, filepath.Join(target, "new"))
+
+		// Without ‘--remove’ the removal is never queued.
+		time.Sleep(100 * time.Millisecond)
+		for v := ms.queue.Pop(); v != nil; v = ms.queue.Pop() {
+			queued = append(queued, v.(URLs))
+		}
+		for _, sURLs := range queued {
+			c.Assert(sURLs.SourceContent, NotNil)
+		}
+
+		ms.watcher.Stop()
+		c.Assert(session.Delete(), IsNil)
+	}
+}
@@ -0,0 +1,268 @@
+/*
+ * Minio Client (C) 2016 Minio, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/fatih/color"
+	"github.com/minio/cli"
+	"github.com/minio/mc/pkg/console"
+)
+
+// mv command flags.
+var (
+	mvFlags = []cli.Flag{
+		cli.BoolFlag{
+			Name:  "help, h",
+			Usage: "Help of mv.",
+		},
+		cli.BoolFlag{
+			Name:  "recursive, r",
+			Usage: "Move recursively.",
+		},
+		cli.StringSliceFlag{
+			Name:  "include",
+			Value: &cli.StringSlice{},
+			Usage: "Only move files matching the gitignore style pattern when moving recursively, may be repeated.",
+		},
+		cli.StringSliceFlag{
+			Name:  "exclude",
+			Value: &cli.StringSlice{},
+			Usage: "Skip files and folders matching the gitignore style pattern when moving recursively, may be repeated.",
+		},
+		cli.StringFlag{
+			Name:  "newer-than",
+			Usage: "Only move files modified within the given age, e.g. ‘12h’ or ‘7d’.",
+		},
+		cli.StringFlag{
+			Name:  "older-than",
+			Usage: "Only move files modified before the given age, e.g. ‘30d’.",
+		},
+		cli.StringFlag{
+			Name:  "larger-than",
+			Usage: "Only move files larger than the given size, e.g. ‘10MiB’.",
+		},
+		cli.StringFlag{
+			Name:  "smaller-than",
+			Usage: "Only move files smaller than the given size, e.g. ‘1KiB’.",
+		},
+	}
+)
+
+// Move command.
+var mvCmd = cli.Command{
+	Name:        "mv",
+	Usage:       "Move files and objects.",
+	Description: "Objects are copied on the server where possible, other transfers are verified against the ETag as with ‘cp --checksum’. Sources are only removed once the size of the target is verified.",
+	Action:      mainMove,
+	Flags:       append(mvFlags, globalFlags...),
+	CustomHelpTemplate: `NAME:
+   mc {{.Name}} - {{.Usage}}
+
+USAGE:
+   mc {{.Name}} [FLAGS] SOURCE [SOURCE...] TARGET
+
+FLAGS:
+  {{range .Flags}}{{.}}
+  {{end}}
+DESCRIPTION:
+   {{.Description}}
+
+EXAMPLES:
+   1. Move a file to another bucket on Amazon S3 cloud storage.
+      $ mc {{.Name}} s3/incoming/report.pdf s3/archive/2016/
+
+   2. Move a local folder recursively to Amazon S3 cloud storage.
+      $ mc {{.Name}} --recursive backup/ s3/backup/
+
+   3. Move log files older than a week to a bucket on Amazon S3 cloud storage.
+      $ mc {{.Name}} --recursive --include "*.log" --older-than 7d /var/log/app/ s3/logs/
+`,
+}
+
+// checkMoveSyntax - moves take the same arguments as copies.
+func checkMoveSyntax(ctx *cli.Context) {
+	if len(ctx.Args()) < 2 {
+		cli.ShowCommandHelpAndExit(ctx, "mv", 1) // last argument is exit code.
+	}
+	checkCopySyntax(ctx)
+}
+
+// verifyMove - verifies the size and the checksum of the target of a
+// move, the source is only removed if both match.
+func verifyMove(cpURLs URLs) URLs {
+	targetURL := cpURLs.TargetContent.URL.String()
+	clnt, err := newClientFromAlias(cpURLs.TargetAlias, targetURL)
+	if err != nil {
+		return cpURLs.WithError(err.Trace(targetURL))
+	}
+	targetContent, err := clnt.Stat()
+	if err != nil {
+		return cpURLs.WithError(err.Trace(targetURL))
+	}
+	if targetContent.Size != cpURLs.SourceContent.Size {
+		return cpURLs.WithError(errMoveNotVerified(targetURL).Trace(targetURL))
+	}
+	differs, err := checksumDiffers(cpURLs.SourceAlias, cpURLs.SourceContent, cpURLs.TargetAlias, targetContent)
+	if err != nil {
+		return cpURLs.WithError(err.Trace(targetURL))
+	}
+	if differs {
+		return cpURLs.WithError(errMoveNotVerified(targetURL).Trace(targetURL))
+	}
+	return cpURLs
+}
+
+// renameFile - moves a file within the filesystem by renaming it,
+// reports whether it was renamed. Files are not renamed across
+// filesystems, they are copied and removed instead.
+func renameFile(cpURLs URLs, progressReader *progressBar, accountingReader *accounter) bool {
+	sourceURL := cpURLs.SourceContent.URL
+	targetURL := cpURLs.TargetContent.URL
+	if sourceURL.Type != FileSystem || targetURL.Type != FileSystem {
+		return false
+	}
+	sourcePath := filepath.Join(cpURLs.SourceAlias, sourceURL.Path)
+	targetPath := filepath.Join(cpURLs.TargetAlias, targetURL.Path)
+	// Links are moved as the files they point to.
+	if st, e := os.Lstat(sourcePath); e != nil || !st.Mode().IsRegular() {
+		return false
+	}
+	if st, e := os.Lstat(targetPath); e == nil && st.IsDir() {
+		return false
+	}
+	if e := os.MkdirAll(filepath.Dir(targetPath), 0775); e != nil {
+		return false
+	}
+	if e := os.Rename(sourcePath, targetPath); e != nil {
+		return false
+	}
+	if globalNoProgress || globalJSON {
+		if !globalQuiet || globalJSON {
+			printMsg(copyMessage{
+				Source: filepath.ToSlash(sourcePath),
+				Target: filepath.ToSlash(targetPath),
+			})
+		}
+		accountingReader.Add(cpURLs.SourceContent.Size)
+	} else {
+		doCopyFake(cpURLs, progressReader)
+	}
+	return true
+}
+
+// removeEmptyFolders - removes folders of a moved file which are left
+// empty, up to the source folder given.
+func removeEmptyFolders(filePath string, sourceURLs []string) {
+	for _, sourceURL := range sourceURLs {
+		rootPath := strings.TrimSuffix(newClientURL(sourceURL).Path, string(os.PathSeparator))
+		if !strings.HasPrefix(filePath, rootPath+string(os.PathSeparator)) {
+			continue
+		}
+		for dir := filepath.Dir(filePath); len(dir) > len(rootPath); dir = filepath.Dir(dir) {
+			if e := os.Remove(dir); e != nil {
+				// Not empty.
+				break
+			}
+		}
+		return
+	}
+}
+
+// mainMove is the entry point for mv command.
+func mainMove(ctx *cli.Context) {
+	// Set global flags from context.
+	setGlobalsFromContext(ctx)
+
+	// check 'mv' cli arguments.
+	checkMoveSyntax(ctx)
+
+	// Additional command speific theme customization.
+	console.SetColor("Copy", color.New(color.FgGreen, color.Bold))
+
+	args := ctx.Args()
+	sourceURLs := args[:len(args)-1]
+	targetURL := args[len(args)-1]
+	filter, _ := newContentFilter(filterOptsFromContext(ctx))
+
+	// Sources are listed before moving, so that the progress bar
+	// knows the total size.
+	var moveURLs []URLs
+	var totalBytes int64
+	for cpURLs := range prepareCopyURLs(sourceURLs, targetURL, ctx.Bool("recursive"), filter) {
+		if cpURLs.Error != nil {
+			errorIf(cpURLs.Error.Trace(), "Unable to prepare URL for moving.")
+			continue
+		}
+		moveURLs = append(moveURLs, cpURLs)
+		totalBytes += cpURLs.SourceContent.Size
+	}
+
+	accntReader := newAccounter(totalBytes)
+	var progressReader *progressBar
+	if !globalNoProgress && !globalJSON {
+		progressReader = newProgressBar(totalBytes)
+		progressReader.setTotalObjects(int64(len(moveURLs)))
+	}
+
+	// Transfers are verified against ETags.
+	uploadOpts := PutOpts{Checksum: true}
+	for _, cpURLs := range moveURLs {
+		sourceURL := cpURLs.SourceContent.URL
+		if renameFile(cpURLs, progressReader, accntReader) {
+			removeEmptyFolders(sourceURL.Path, sourceURLs)
+			continue
+		}
+		cpURLs = doCopy(globalContext, cpURLs, progressReader, accntReader, nil, uploadOpts, CopyOpts{}, false)
+		if cpURLs.Error == nil {
+			cpURLs = verifyMove(cpURLs)
+		}
+		if cpURLs.Error != nil {
+			if !globalNoProgress && !globalJSON {
+				console.Eraseline()
+			}
+			errorIf(cpURLs.Error.Trace(sourceURL.String()), "Failed to move ‘"+sourceURL.String()+"’.")
+			continue
+		}
+		if err := rmObject(cpURLs.SourceAlias, sourceURL.String(), false); err != nil {
+			if !globalNoProgress && !globalJSON {
+				console.Eraseline()
+			}
+			errorIf(err.Trace(sourceURL.String()), "Unable to remove ‘"+sourceURL.String()+"’ after moving it.")
+			continue
+		}
+		if sourceURL.Type == FileSystem {
+			removeEmptyFolders(sourceURL.Path, sourceURLs)
+		}
+	}
+
+	if !globalNoProgress && !globalJSON {
+		if progressReader.ProgressBar.Get() > 0 {
+			progressReader.Finish()
+		}
+	} else if !globalJSON && globalNoProgress {
+		accntStat := accntReader.Stat()
+		console.Println(console.Colorize("Copy", copyStatMessage{
+			Total:       accntStat.Total,
+			Transferred: accntStat.Transferred,
+			Speed:       accntStat.Speed,
+		}.String()))
+	}
+}
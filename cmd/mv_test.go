@@ -0,0 +1,107 @@
+/*
+ * Minio Client (C) 2016 Minio, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+
+	"github.com/minio/minio/pkg/probe"
+	. "gopkg.in/check.v1"
+)
+
+// Test removing folders left empty by moves.
+func (s *TestSuite) TestMoveRemoveEmptyFolders(c *C) {
+	root, e := ioutil.TempDir(os.TempDir(), "mv-")
+	c.Assert(e, IsNil)
+	defer os.RemoveAll(root)
+
+	source := filepath.Join(root, "src", "a", "b", "file")
+	c.Assert(os.MkdirAll(filepath.Dir(source), 0700), IsNil)
+	c.Assert(ioutil.WriteFile(source, []byte("moved"), 0600), IsNil)
+	c.Assert(ioutil.WriteFile(filepath.Join(root, "src", "keep"), []byte("kept"), 0600), IsNil)
+
+	// Folders left empty are removed up to the source folder.
+	c.Assert(os.Remove(source), IsNil)
+	removeEmptyFolders(source, []string{filepath.Join(root, "src") + string(os.PathSeparator)})
+	_, e = os.Stat(filepath.Join(root, "src", "a"))
+	c.Assert(os.IsNotExist(e), Equals, true)
+	_, e = os.Stat(filepath.Join(root, "src"))
+	c.Assert(e, IsNil)
+}
+
+// Test moving files within the filesystem by renaming them.
+func (s *TestSuite) TestRenameFile(c *C) {
+	root, e := ioutil.TempDir(os.TempDir(), "mv-")
+	c.Assert(e, IsNil)
+	defer os.RemoveAll(root)
+
+	source := filepath.Join(root, "src", "file")
+	target := filepath.Join(root, "dst", "a", "file")
+	c.Assert(os.MkdirAll(filepath.Dir(source), 0700), IsNil)
+	c.Assert(ioutil.WriteFile(source, []byte("moved"), 0600), IsNil)
+	cpURLs := URLs{
+		SourceContent: &ClientContent{URL: *newClientURL(source), Size: 5},
+		TargetContent: &ClientContent{URL: *newClientURL(target)},
+	}
+
+	globalQuiet, globalNoProgress = true, true
+	defer func() { globalQuiet, globalNoProgress = false, false }()
+	accounter := newAccounter(5)
+	c.Assert(renameFile(cpURLs, nil, accounter), Equals, true)
+	c.Assert(accounter.Stat().Transferred, Equals, int64(5))
+	data, e := ioutil.ReadFile(target)
+	c.Assert(e, IsNil)
+	c.Assert(string(data), Equals, "moved")
+	_, e = os.Stat(source)
+	c.Assert(os.IsNotExist(e), Equals, true)
+
+	// Files are not renamed over folders.
+	c.Assert(ioutil.WriteFile(source, []byte("moved"), 0600), IsNil)
+	c.Assert(os.Remove(target), IsNil)
+	c.Assert(os.Mkdir(target, 0700), IsNil)
+	c.Assert(renameFile(cpURLs, nil, accounter), Equals, false)
+}
+
+// Test verifying the target of a move before removing the source.
+func (s *TestSuite) TestVerifyMove(c *C) {
+	root, e := ioutil.TempDir(os.TempDir(), "mv-")
+	c.Assert(e, IsNil)
+	defer os.RemoveAll(root)
+
+	source := filepath.Join(root, "source")
+	target := filepath.Join(root, "target")
+	c.Assert(ioutil.WriteFile(source, []byte("moved"), 0600), IsNil)
+	c.Assert(ioutil.WriteFile(target, []byte("moved"), 0600), IsNil)
+	cpURLs := URLs{
+		SourceContent: &ClientContent{URL: *newClientURL(source), Size: 5},
+		TargetContent: &ClientContent{URL: *newClientURL(target)},
+	}
+	// Files have no alias in an empty config.
+	defer func(load func() (*configV8, *probe.Error)) { loadMcConfig = load }(loadMcConfig)
+	loadMcConfig = func() (*configV8, *probe.Error) { return newMcConfig(), nil }
+	c.Assert(verifyMove(cpURLs).Error, IsNil)
+
+	// Targets of the same size but different content are refused.
+	c.Assert(ioutil.WriteFile(target, []byte("moves"), 0600), IsNil)
+	c.Assert(verifyMove(cpURLs).Error, NotNil)
+
+	// So are targets of a different size.
+	c.Assert(ioutil.WriteFile(target, []byte("move"), 0600), IsNil)
+	c.Assert(verifyMove(cpURLs).Error, NotNil)
+}
@@ -0,0 +1,130 @@
+/*
+ * Minio Client (C) 2016 Minio, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"encoding/csv"
+	"fmt"
+	"os"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/minio/cli"
+	"github.com/minio/minio/pkg/probe"
+)
+
+// Formats of listings selected by ‘--output’, listings are printed
+// as usual if unset.
+const (
+	outputJSON  = "json"
+	outputCSV   = "csv"
+	outputTable = "table"
+)
+
+// outputFlag - selects the format of listings.
+var outputFlag = cli.StringFlag{
+	Name:  "output",
+	Usage: "Print entries as ‘json’, ‘csv’ or ‘table’ with a header, for spreadsheets and unix tools.",
+}
+
+// tabularMessage - message printed as a row of a table, columns are
+// named like the fields of its JSON structure.
+type tabularMessage interface {
+	message
+	Columns() []string
+	Row() []string
+}
+
+// parseOutputFormat - validates the format of ‘--output’, tables and
+// CSV are not printed along with ‘--json’.
+func parseOutputFormat(format string, isJSON bool) (string, *probe.Error) {
+	switch format {
+	case "", outputJSON:
+		return format, nil
+	case outputCSV, outputTable:
+		if isJSON {
+			return "", errInvalidArgument().Trace(format)
+		}
+		return format, nil
+	}
+	return "", errInvalidOutputFormat(format).Trace(format)
+}
+
+// checkOutputSyntax - validates ‘--output’ of the command line.
+func checkOutputSyntax(ctx *cli.Context) {
+	if _, err := parseOutputFormat(ctx.String("output"), globalJSON); err != nil {
+		fatalIf(err.Trace(), "Unable to parse output format, ‘--output’ has to be ‘json’, ‘csv’ or ‘table’ and CSV or tables cannot be printed along with ‘--json’.")
+	}
+}
+
+// messagePrinter - prints messages in the format of ‘--output’,
+// messages which are no rows are printed as usual.
+type messagePrinter struct {
+	format    string
+	csv       *csv.Writer
+	table     *tabwriter.Writer
+	hasHeader bool
+}
+
+// newMessagePrinter - printer of the format, JSON output is the same
+// as with ‘--json’, so that errors are printed as JSON as well.
+func newMessagePrinter(format string) *messagePrinter {
+	p := &messagePrinter{format: format}
+	switch format {
+	case outputJSON:
+		globalJSON = true
+	case outputCSV:
+		p.csv = csv.NewWriter(os.Stdout)
+	case outputTable:
+		p.table = tabwriter.NewWriter(os.Stdout, 0, 8, 2, ' ', 0)
+	}
+	return p
+}
+
+// Print - prints a message, rows of a table are only aligned once
+// all of them were printed and the table is flushed.
+func (p *messagePrinter) Print(msg message) {
+	row, ok := msg.(tabularMessage)
+	if !ok || (p.csv == nil && p.table == nil) {
+		p.Flush()
+		printMsg(msg)
+		return
+	}
+	if p.csv != nil {
+		if !p.hasHeader {
+			p.csv.Write(row.Columns())
+			p.hasHeader = true
+		}
+		p.csv.Write(row.Row())
+		// Rows are written as they are listed.
+		p.csv.Flush()
+		fatalIf(probe.NewError(p.csv.Error()), "Unable to write CSV.")
+		return
+	}
+	if !p.hasHeader {
+		fmt.Fprintln(p.table, strings.ToUpper(strings.Join(row.Columns(), "\t")))
+		p.hasHeader = true
+	}
+	fmt.Fprintln(p.table, strings.Join(row.Row(), "\t"))
+}
+
+// Flush - prints the rows of a table.
+func (p *messagePrinter) Flush() {
+	if p.table != nil {
+		p.table.Flush()
+	}
+}
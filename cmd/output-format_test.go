@@ -0,0 +1,73 @@
+/*
+ * Minio Client (C) 2016 Minio, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"time"
+
+	. "gopkg.in/check.v1"
+)
+
+// Test validation of output formats.
+func (s *TestSuite) TestParseOutputFormat(c *C) {
+	testCases := []struct {
+		format  string
+		isJSON  bool
+		success bool
+	}{
+		{"", false, true},
+		{"", true, true},
+		{"json", true, true},
+		{"csv", false, true},
+		{"table", false, true},
+		// Tables are not printed as JSON.
+		{"csv", true, false},
+		{"table", true, false},
+		{"xml", false, false},
+	}
+	for i, testCase := range testCases {
+		format, err := parseOutputFormat(testCase.format, testCase.isJSON)
+		if testCase.success {
+			c.Assert(err, IsNil, Commentf("Test %d", i+1))
+			c.Assert(format, Equals, testCase.format, Commentf("Test %d", i+1))
+		} else {
+			c.Assert(err, NotNil, Commentf("Test %d", i+1))
+		}
+	}
+}
+
+// Test that rows of messages match their columns.
+func (s *TestSuite) TestTabularMessages(c *C) {
+	modTime := time.Date(2016, 4, 8, 3, 56, 14, 0, time.UTC)
+	testCases := []struct {
+		msg tabularMessage
+		row []string
+	}{
+		{contentMessage{Filetype: "file", Time: modTime, Size: 1024, Key: "albums/cover.jpg"},
+			[]string{"2016-04-08T03:56:14Z", "1024", "file", "albums/cover.jpg", "", "false", "false"}},
+		{duMessage{Prefix: "s3/photos/", Size: 2048, Objects: 2},
+			[]string{"2048", "2", "s3/photos/"}},
+		{diffMessage{FirstURL: "/photos/a.jpg", SecondURL: "s3/photos/a.jpg", Diff: differInSize},
+			[]string{"/photos/a.jpg", "s3/photos/a.jpg", "size"}},
+		{findMessage{Key: "s3/photos/a.jpg", Size: 512, Time: modTime},
+			[]string{"s3/photos/a.jpg", "512", "2016-04-08T03:56:14Z"}},
+	}
+	for i, testCase := range testCases {
+		c.Assert(testCase.msg.Row(), DeepEquals, testCase.row, Commentf("Test %d", i+1))
+		c.Assert(len(testCase.msg.Columns()), Equals, len(testCase.row), Commentf("Test %d", i+1))
+	}
+}
@@ -30,6 +30,14 @@ var (
 			Name:  "help, h",
 			Usage: "Help of pipe.",
 		},
+		cli.StringFlag{
+			Name:  "buffer-size",
+			Usage: "Size of the parts stdin is buffered in memory and uploaded in, e.g. ‘256MiB’. Streams hold at most 10000 parts, 625GiB with the default of 64MiB.",
+		},
+		cli.IntFlag{
+			Name:  "concurrent-parts",
+			Usage: "Number of parts uploaded in parallel, each of them buffered in memory.",
+		},
 	}
 )
 
@@ -60,10 +68,13 @@ EXAMPLES:
 
    4. Stream MySQL database dump to Amazon S3 directly.
       $ mysqldump -u root -p ******* accountsdb | mc {{.Name}} s3/ferenginar/backups/accountsdb-oct-9-2015.sql
+
+   5. Stream a multi terabyte archive to Amazon S3, uploading 4 parts of 512MiB at a time.
+      $ tar czf - /data | mc {{.Name}} --buffer-size 512MiB --concurrent-parts 4 s3/ferenginar/backups/data.tar.gz
 `,
 }
 
-func pipe(targetURL string) *probe.Error {
+func pipe(targetURL string, opts PutOpts) *probe.Error {
 	if targetURL == "" {
 		// When no target is specified, pipe cat's stdin to stdout.
 		return catOut(os.Stdin).Trace()
@@ -72,7 +83,14 @@ func pipe(targetURL string) *probe.Error {
 	// Stream from stdin to multiple objects until EOF.
 	// Ignore size, since os.Stat() would not return proper size all the time
 	// for local filesystem for example /proc files.
-	_, err := putTargetStream(targetURL, os.Stdin, -1)
+	// Object storage receives stdin in parts buffered in memory, it is
+	// never spooled to disk.
+	partSize, err := optimalPartSize(-1, opts.PartSize)
+	if err != nil {
+		return err.Trace(targetURL)
+	}
+	opts.PartSize = partSize
+	_, err = putTargetStream(globalContext, targetURL, os.Stdin, -1, opts)
 	// TODO: See if this check is necessary.
 	switch e := err.ToGoError().(type) {
 	case *os.PathError:
@@ -89,6 +107,9 @@ func checkPipeSyntax(ctx *cli.Context) {
 	if len(ctx.Args()) > 1 {
 		cli.ShowCommandHelpAndExit(ctx, "pipe", 1) // last argument is exit code.
 	}
+	if _, err := parsePutOpts(ctx.String("buffer-size"), ctx.Int("concurrent-parts")); err != nil {
+		fatalIf(err.Trace(), "Unable to parse buffer options.")
+	}
 }
 
 // mainPipe is the main entry point for pipe command.
@@ -99,13 +120,16 @@ func mainPipe(ctx *cli.Context) {
 	// validate pipe input arguments.
 	checkPipeSyntax(ctx)
 
+	opts, err := parsePutOpts(ctx.String("buffer-size"), ctx.Int("concurrent-parts"))
+	fatalIf(err, "Unable to parse buffer options.")
+
 	if len(ctx.Args()) == 0 {
-		err := pipe("")
+		err = pipe("", opts)
 		fatalIf(err.Trace("stdout"), "Unable to write to one or more targets.")
 	} else {
 		// extract URLs.
 		URLs := ctx.Args()
-		err := pipe(URLs[0])
+		err = pipe(URLs[0], opts)
 		fatalIf(err.Trace(URLs[0]), "Unable to write to one or more targets.")
 	}
 }
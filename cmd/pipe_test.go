@@ -0,0 +1,66 @@
+/*
+ * Minio Client (C) 2016 Minio, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"bytes"
+	"io/ioutil"
+	"net/http/httptest"
+	"os"
+	"sync"
+
+	"github.com/minio/minio/pkg/probe"
+	. "gopkg.in/check.v1"
+)
+
+// Test streaming stdin to objects in parts of the buffer size.
+func (s *TestSuite) TestPipeBuffered(c *C) {
+	var object []byte
+	uploaded := 0
+	server := httptest.NewServer(multipartHandler{mutex: &sync.Mutex{}, parts: make(map[int][]byte), object: &object, uploaded: &uploaded})
+	defer server.Close()
+
+	defer func(load func() (*configV8, *probe.Error)) { loadMcConfig = load }(loadMcConfig)
+	loadMcConfig = func() (*configV8, *probe.Error) {
+		conf := newMcConfig()
+		conf.Hosts["s3"] = hostConfigV8{
+			URL:       server.URL,
+			AccessKey: "WLGDGYAQYIGI833EV05A",
+			SecretKey: "BYvgJM101sHngl2uzjXS/OBF/aMxAN06JrJ3qJlF",
+			API:       "S3v4",
+			Lookup:    "path",
+		}
+		return conf, nil
+	}
+
+	// Two full parts of the smallest buffer size and a short one.
+	data := bytes.Repeat([]byte("0123456789abcdef"), (2*s3PartSizeLowerLimit+1024)/16)
+	stdin, e := ioutil.TempFile(os.TempDir(), "stdin-")
+	c.Assert(e, IsNil)
+	defer os.Remove(stdin.Name())
+	defer stdin.Close()
+	_, e = stdin.Write(data)
+	c.Assert(e, IsNil)
+	_, e = stdin.Seek(0, 0)
+	c.Assert(e, IsNil)
+
+	defer func(in *os.File) { os.Stdin = in }(os.Stdin)
+	os.Stdin = stdin
+	c.Assert(pipe("s3/bucket/object", PutOpts{PartSize: s3PartSizeLowerLimit, ConcurrentParts: 2}), IsNil)
+	c.Assert(uploaded, Equals, 3)
+	c.Assert(bytes.Equal(object, data), Equals, true)
+}
@@ -31,6 +31,7 @@ var (
 			Name:  "help, h",
 			Usage: "Help of policy.",
 		},
+		yesFlag,
 	}
 )
 
@@ -69,11 +70,15 @@ EXAMPLES:
    5. Get bucket permissions.
       $ mc {{.Name}} s3/shared
 
+   6. Set bucket to "download" without being asked to confirm, e.g. in scripts.
+      $ mc {{.Name}} --yes download s3/burningman2011
+
 `,
 }
 
 // policyRules contains policy rule
 type policyRules struct {
+	Status   string `json:"status"`
 	Resource string `json:"resource"`
 	Allow    string `json:"allow"`
 }
@@ -85,6 +90,7 @@ func (s policyRules) String() string {
 
 // JSON jsonified policy message.
 func (s policyRules) JSON() string {
+	s.Status = "success"
 	policyJSONBytes, e := json.Marshal(s)
 	fatalIf(probe.NewError(e), "Unable to marshal into JSON.")
 	return string(policyJSONBytes)
@@ -225,6 +231,10 @@ func mainPolicy(ctx *cli.Context) {
 		perms := accessPerms(ctx.Args().First())
 		if perms.isValidAccessPERM() {
 			targetURL := ctx.Args().Last()
+			// Anonymous access is confirmed, as anybody could read or write.
+			if perms != accessNone {
+				mustConfirm(ctx, trf("Anonymous users will be allowed to %s objects of ‘%s’.", perms, targetURL))
+			}
 			err := doSetAccess(targetURL, perms)
 			// Upon error exit.
 			if err != nil {
@@ -16,7 +16,12 @@
 
 package cmd
 
-import "github.com/minio/mc/pkg/console"
+import (
+	"encoding/json"
+
+	"github.com/minio/mc/pkg/console"
+	"github.com/minio/minio/pkg/probe"
+)
 
 // message interface for all structured messages implementing JSON(), String() methods.
 type message interface {
@@ -32,3 +37,36 @@ func printMsg(msg message) {
 		console.Println(msg.JSON())
 	}
 }
+
+// infoMessage container for informational messages.
+type infoMessage struct {
+	Status  string `json:"status"`
+	Message string `json:"message"`
+}
+
+// String colorized informational message.
+func (i infoMessage) String() string {
+	return console.Colorize("Info", i.Message)
+}
+
+// JSON jsonified informational message.
+func (i infoMessage) JSON() string {
+	i.Status = "success"
+	infoJSONBytes, e := json.Marshal(i)
+	fatalIf(probe.NewError(e), "Unable to marshal into JSON.")
+
+	return string(infoJSONBytes)
+}
+
+// printInfo prints an informational message, as JSON structure in
+// JSON mode so that the output stays parseable.
+func printInfo(msg string) {
+	// Informational messages are not essential in quiet mode.
+	if globalQuiet && !globalJSON {
+		return
+	}
+	if !globalJSON {
+		msg = tr(msg)
+	}
+	printMsg(infoMessage{Message: msg})
+}
@@ -0,0 +1,119 @@
+/*
+ * Minio Client (C) 2016 Minio, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/minio/mc/pkg/console"
+	. "gopkg.in/check.v1"
+)
+
+// Test that JSON messages report their status.
+func (s *TestSuite) TestMessageStatus(c *C) {
+	messages := []message{
+		infoMessage{Message: "Configuration written."},
+		clearSessionMessage{Status: "success", SessionID: "ygVIpSJs", Forced: true},
+		policyRules{Resource: "bucket/*", Allow: "readonly"},
+		rmMessage{Status: "success", URL: "s3/bucket/object"},
+	}
+	for _, msg := range messages {
+		var fields map[string]interface{}
+		c.Assert(json.Unmarshal([]byte(msg.JSON()), &fields), IsNil)
+		c.Assert(fields["status"], Equals, "success", Commentf("%s", msg.JSON()))
+	}
+}
+
+// Test that quiet mode prints only errors and summaries.
+func (s *TestSuite) TestQuietOutput(c *C) {
+	defer func(quiet, json, noProgress bool) {
+		globalQuiet, globalJSON, globalNoProgress = quiet, json, noProgress
+	}(globalQuiet, globalJSON, globalNoProgress)
+	defer func(println, errorln func(data ...interface{})) {
+		console.Println, console.Errorln = println, errorln
+	}(console.Println, console.Errorln)
+	var printed, errors []string
+	console.Println = func(data ...interface{}) { printed = append(printed, fmt.Sprint(data...)) }
+	console.Errorln = func(data ...interface{}) { errors = append(errors, fmt.Sprint(data...)) }
+
+	// Quiet mode implies no progress bar.
+	setGlobals(true, false, false, false, false, false)
+	c.Assert(globalNoProgress, Equals, true)
+
+	status := NewQuietStatus()
+	printInfo("Configuration written.")
+	status.Println("Copying ‘a’.")
+	status.PrintMsg(infoMessage{Message: "Copied ‘a’."})
+	status.errorIf(errDummy().Trace(), "Failed to copy ‘b’.")
+	status.Add(5)
+	status.Finish()
+	c.Assert(errors, HasLen, 1)
+	c.Assert(strings.HasPrefix(errors[0], "Failed to copy ‘b’."), Equals, true)
+	c.Assert(printed, HasLen, 1)
+	c.Assert(strings.Contains(printed[0], "5 B"), Equals, true, Commentf("%s", printed[0]))
+
+	// Otherwise messages are printed, in JSON even if quiet.
+	printed, errors = nil, nil
+	for _, json := range []bool{false, true} {
+		globalQuiet, globalJSON = json, json
+		printInfo("Configuration written.")
+		status.PrintMsg(infoMessage{Message: "Copied ‘a’."})
+	}
+	c.Assert(printed, HasLen, 4)
+	c.Assert(errors, HasLen, 0)
+}
+
+// assertStreamed - runs a command listing the endless bucket of the
+// handler, checks that its JSON messages are printed while listing and
+// that listing stops while they are not read, as when piped into head.
+func assertStreamed(c *C, handler *endlessListHandler, run func()) {
+	defer func(println func(data ...interface{}), json bool) {
+		console.Println, globalJSON = println, json
+	}(console.Println, globalJSON)
+	printed := make(chan string)
+	console.Println = func(data ...interface{}) { printed <- fmt.Sprint(data...) }
+	globalJSON = true
+
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		run()
+	}()
+	for i := 0; i < 5; i++ {
+		var fields map[string]interface{}
+		msg := <-printed
+		c.Assert(json.Unmarshal([]byte(msg), &fields), IsNil, Commentf("%s", msg))
+	}
+	time.Sleep(100 * time.Millisecond)
+	listed := handler.listed()
+	time.Sleep(100 * time.Millisecond)
+	c.Assert(handler.listed(), Equals, listed)
+	// At most the page being printed and the next one are listed.
+	c.Assert(listed <= 2, Equals, true, Commentf("%d pages listed", listed))
+
+	handler.end()
+	for {
+		select {
+		case <-printed:
+		case <-done:
+			return
+		}
+	}
+}
@@ -17,24 +17,71 @@
 package cmd
 
 import (
-	"io"
+	"fmt"
+	"os"
 	"runtime"
 	"strings"
+	"sync"
+	"sync/atomic"
 	"time"
 
 	"github.com/cheggaaa/pb"
+	"github.com/dustin/go-humanize"
 	"github.com/fatih/color"
+	"github.com/mattn/go-isatty"
 
 	"github.com/minio/mc/pkg/console"
 )
 
-// progress extender.
+// Maximum number of files in transfer shown below the progress bar.
+const maxProgressFiles = 3
+
+// Progress of transfers is printed at this interval when the output
+// is not a terminal.
+const plainProgressInterval = 10 * time.Second
+
+// Instantaneous throughput is measured over this window.
+const progressSpeedWindow = 3 * time.Second
+
+// progressSample - bytes transferred at a point in time.
+type progressSample struct {
+	time  time.Time
+	bytes int64
+}
+
+// fileProgress - progress of a file in transfer, reading from it
+// advances both the file and the progress bar.
+type fileProgress struct {
+	name string
+	size int64
+	read int64
+	bar  *progressBar
+}
+
+// Read implements the io.Reader interface.
+func (f *fileProgress) Read(p []byte) (n int, err error) {
+	n = len(p)
+	atomic.AddInt64(&f.read, int64(n))
+	f.bar.Add64(int64(n))
+	return n, nil
+}
+
+// progress extender, shows the overall progress of all objects with
+// throughput and ETA, followed by the files in transfer.
 type progressBar struct {
 	*pb.ProgressBar
-	reader       io.Reader
-	readerLength int64
-	bytesRead    int64
-	isResume     bool
+
+	mutex        sync.Mutex
+	files        []*fileProgress
+	objects      int64
+	totalObjects int64
+	samples      []progressSample
+	started      time.Time
+	// Number of lines drawn, the cursor is kept on the first one.
+	height     int
+	isTerminal bool
+	lastPrint  time.Time
+	isFinished bool
 }
 
 // newProgressBar - instantiate a progress bar.
@@ -42,7 +89,10 @@ func newProgressBar(total int64) *progressBar {
 	// Progress bar speific theme customization.
 	console.SetColor("Bar", color.New(color.FgGreen, color.Bold))
 
-	pgbar := progressBar{}
+	pgbar := &progressBar{
+		isTerminal: isatty.IsTerminal(os.Stdout.Fd()),
+		lastPrint:  time.Now(),
+	}
 
 	// get the new original progress bar.
 	bar := pb.New64(total)
@@ -56,13 +106,12 @@ func newProgressBar(total int64) *progressBar {
 	// Do not print a newline by default handled, it is handled manually.
 	bar.NotPrint = true
 
-	// Show current speed is true.
-	bar.ShowSpeed = true
+	// Throughput and time left are printed below the bar.
+	bar.ShowSpeed = false
+	bar.ShowTimeLeft = false
 
-	// Custom callback with colorized bar.
-	bar.Callback = func(s string) {
-		console.Print(console.Colorize("Bar", "\r"+s))
-	}
+	// Custom callback drawing all lines of the progress.
+	bar.Callback = pgbar.draw
 
 	// Use different unicodes for Linux, OS X and Windows.
 	switch runtime.GOOS {
@@ -77,23 +126,16 @@ func newProgressBar(total int64) *progressBar {
 		bar.Format("[=> ]")
 	}
 
+	// Copy for future
+	pgbar.ProgressBar = bar
+
 	// Start the progress bar.
 	if bar.Total > 0 {
 		bar.Start()
 	}
 
-	// Copy for future
-	pgbar.ProgressBar = bar
-
 	// Return new progress bar here.
-	return &pgbar
-}
-
-// Set caption.
-func (p *progressBar) SetCaption(caption string) *progressBar {
-	caption = fixateBarCaption(caption, getFixedWidth(p.ProgressBar.GetWidth(), 18))
-	p.ProgressBar.Prefix(caption)
-	return p
+	return pgbar
 }
 
 func (p *progressBar) Set64(length int64) *progressBar {
@@ -106,6 +148,188 @@ func (p *progressBar) SetTotal(total int64) *progressBar {
 	return p
 }
 
+// setTotalObjects - sets the number of objects to transfer, zero if
+// not known.
+func (p *progressBar) setTotalObjects(total int64) {
+	p.mutex.Lock()
+	p.totalObjects = total
+	p.mutex.Unlock()
+}
+
+// startFile - shows a file in transfer, data of the file is read
+// from the returned reader.
+func (p *progressBar) startFile(name string, size int64) *fileProgress {
+	f := &fileProgress{name: name, size: size, bar: p}
+	p.mutex.Lock()
+	p.files = append(p.files, f)
+	p.mutex.Unlock()
+	return f
+}
+
+// finishFile - counts an object as done and stops showing its file,
+// nil counts an object which was not transferred.
+func (p *progressBar) finishFile(f *fileProgress) {
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+	p.objects++
+	for i, file := range p.files {
+		if file == f {
+			p.files = append(p.files[:i], p.files[i+1:]...)
+			break
+		}
+	}
+}
+
+// Finish - draws the final progress and moves the cursor below it.
+func (p *progressBar) Finish() {
+	p.mutex.Lock()
+	p.isFinished = true
+	p.mutex.Unlock()
+	p.ProgressBar.Finish()
+}
+
+// speed - instantaneous and average throughput in bytes per second.
+func (p *progressBar) speed(now time.Time, current int64) (float64, float64) {
+	p.samples = append(p.samples, progressSample{now, current})
+	for len(p.samples) > 2 && now.Sub(p.samples[1].time) > progressSpeedWindow {
+		p.samples = p.samples[1:]
+	}
+	var speedNow, speedAvg float64
+	if first := p.samples[0]; now.After(first.time) {
+		speedNow = float64(current-first.bytes) / now.Sub(first.time).Seconds()
+	}
+	if elapsed := now.Sub(p.started); elapsed > 0 {
+		speedAvg = float64(current) / elapsed.Seconds()
+	}
+	return speedNow, speedAvg
+}
+
+// draw - callback of the progress bar, draws the bar followed by the
+// statistics and the files in transfer. When the output is not a
+// terminal the statistics are printed as plain text from time to time.
+func (p *progressBar) draw(bar string) {
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+
+	now := time.Now()
+	current := p.Get()
+	if p.started.IsZero() {
+		// Throughput counts from the first draw.
+		p.started = now
+		p.samples = append(p.samples, progressSample{now, 0})
+	}
+	speedNow, speedAvg := p.speed(now, current)
+	stats := progressStats{
+		Total:        p.Total,
+		Current:      current,
+		Objects:      p.objects,
+		TotalObjects: p.totalObjects,
+		SpeedNow:     speedNow,
+		SpeedAvg:     speedAvg,
+	}
+
+	if !p.isTerminal {
+		if p.isFinished || now.Sub(p.lastPrint) >= plainProgressInterval {
+			p.lastPrint = now
+			console.Println(stats.String())
+		}
+		return
+	}
+
+	lines := []string{bar, stats.String()}
+	if !p.isFinished {
+		width := p.GetWidth()
+		for i, f := range p.files {
+			if i == maxProgressFiles {
+				lines = append(lines, fmt.Sprintf("  ... %d more", len(p.files)-maxProgressFiles))
+				break
+			}
+			lines = append(lines, f.String(width))
+		}
+	}
+	if len(lines) > p.height {
+		p.height = len(lines)
+	}
+
+	// Lines are redrawn in place, the cursor is moved back to the
+	// first one so that messages printed meanwhile erase the bar.
+	var out string
+	for i := 0; i < p.height; i++ {
+		if i > 0 {
+			out += "\n"
+		}
+		out += "\r\x1b[2K"
+		if i < len(lines) {
+			out += console.Colorize("Bar", lines[i])
+		}
+	}
+	if p.isFinished {
+		// Leave the cursor below the final lines.
+		if below := p.height - len(lines); below > 0 {
+			out += fmt.Sprintf("\x1b[%dA", below)
+		}
+		out += "\n"
+	} else if p.height > 1 {
+		out += fmt.Sprintf("\x1b[%dA\r", p.height-1)
+	}
+	console.Print(out)
+}
+
+// String - line of a file in transfer fitting the width.
+func (f *fileProgress) String(width int) string {
+	read := atomic.LoadInt64(&f.read)
+	var counters string
+	if f.size > 0 {
+		counters = fmt.Sprintf(" %3d%% %s / %s", read*100/f.size,
+			humanize.IBytes(uint64(read)), humanize.IBytes(uint64(f.size)))
+	} else {
+		counters = " " + humanize.IBytes(uint64(read))
+	}
+	captionWidth := width - len(counters) - 2
+	if captionWidth < 10 {
+		captionWidth = 10
+	}
+	return "  " + fixateBarCaption(f.name, captionWidth) + counters
+}
+
+// progressStats - statistics of a transfer shown below the progress bar.
+type progressStats struct {
+	Total        int64
+	Current      int64
+	Objects      int64
+	TotalObjects int64
+	SpeedNow     float64
+	SpeedAvg     float64
+}
+
+// ETA - time left at the average throughput, negative if not known
+// or if nothing is left.
+func (s progressStats) ETA() time.Duration {
+	left := s.Total - s.Current
+	if s.SpeedAvg <= 0 || left <= 0 {
+		return -1
+	}
+	eta := time.Duration(float64(left) / s.SpeedAvg * float64(time.Second))
+	return eta / time.Second * time.Second
+}
+
+// String - statistics as a single line.
+func (s progressStats) String() string {
+	var objects string
+	if s.TotalObjects > 0 && s.Objects <= s.TotalObjects {
+		objects = fmt.Sprintf("%d/%d objects", s.Objects, s.TotalObjects)
+	} else {
+		objects = fmt.Sprintf("%d objects", s.Objects)
+	}
+	msg := fmt.Sprintf("%s / %s, %s, %s/s now, %s/s avg",
+		humanize.IBytes(uint64(s.Current)), humanize.IBytes(uint64(s.Total)), objects,
+		humanize.IBytes(uint64(s.SpeedNow)), humanize.IBytes(uint64(s.SpeedAvg)))
+	if eta := s.ETA(); eta >= 0 {
+		msg += ", ETA " + eta.String()
+	}
+	return msg
+}
+
 // cursorAnimate - returns a animated rune through read channel for every read.
 func cursorAnimate() <-chan rune {
 	cursorCh := make(chan rune)
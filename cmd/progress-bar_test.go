@@ -0,0 +1,58 @@
+/*
+ * Minio Client (C) 2016 Minio, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"time"
+
+	. "gopkg.in/check.v1"
+)
+
+// Test statistics shown below the progress bar.
+func (s *TestSuite) TestProgressStats(c *C) {
+	testCases := []struct {
+		stats progressStats
+		eta   time.Duration
+		line  string
+	}{
+		{progressStats{Total: 4 << 20, Current: 1 << 20, Objects: 1, TotalObjects: 4, SpeedNow: 2 << 20, SpeedAvg: 1 << 20}, 3 * time.Second,
+			"1.0MiB / 4.0MiB, 1/4 objects, 2.0MiB/s now, 1.0MiB/s avg, ETA 3s"},
+		// Nothing transferred yet.
+		{progressStats{Total: 4 << 20}, -1,
+			"0B / 4.0MiB, 0 objects, 0B/s now, 0B/s avg"},
+		// Objects added while watching exceed the total.
+		{progressStats{Total: 1 << 20, Current: 1 << 20, Objects: 5, TotalObjects: 4, SpeedAvg: 1 << 20}, -1,
+			"1.0MiB / 1.0MiB, 5 objects, 0B/s now, 1.0MiB/s avg"},
+	}
+	for i, testCase := range testCases {
+		c.Assert(testCase.stats.ETA(), Equals, testCase.eta, Commentf("Test %d", i+1))
+		c.Assert(testCase.stats.String(), Equals, testCase.line, Commentf("Test %d", i+1))
+	}
+}
+
+// Test that files in transfer are counted once finished.
+func (s *TestSuite) TestProgressFiles(c *C) {
+	bar := &progressBar{}
+	first := bar.startFile("a", 10)
+	bar.startFile("b", 20)
+	c.Assert(len(bar.files), Equals, 2)
+	bar.finishFile(first)
+	bar.finishFile(nil)
+	c.Assert(len(bar.files), Equals, 1)
+	c.Assert(bar.files[0].name, Equals, "b")
+	c.Assert(bar.objects, Equals, int64(2))
+}
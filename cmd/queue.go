@@ -37,6 +37,10 @@ type Queue struct {
 	i int // read index
 	j int // write index
 
+	// objects popped but not yet done, they are saved along with
+	// the queued objects
+	active []interface{}
+
 	idleCh chan interface{}
 
 	closed bool
@@ -55,6 +59,15 @@ func (q *Queue) Save(dst io.Writer) error {
 	q.m.Lock()
 	defer q.m.Unlock()
 
+	for _, v := range q.active {
+		jsonData, err := json.Marshal(v)
+		if err != nil {
+			return err
+		}
+
+		fmt.Fprintln(dst, string(jsonData))
+	}
+
 	for i := q.i; i < q.j; i++ {
 		jsonData, err := json.Marshal(q.a[i])
 		if err != nil {
@@ -117,9 +130,23 @@ func (q *Queue) Pop() interface{} {
 		q.i++
 	}()
 
+	q.active = append(q.active, q.a[q.i])
 	return q.a[q.i]
 }
 
+// Done removes a popped object, it is no longer saved
+func (q *Queue) Done(v interface{}) {
+	q.m.Lock()
+	defer q.m.Unlock()
+
+	for i := range q.active {
+		if q.active[i] == v {
+			q.active = append(q.active[:i], q.active[i+1:]...)
+			return
+		}
+	}
+}
+
 func (q *Queue) grow(n int) {
 	a := make([]interface{}, q.j+10)
 
@@ -139,6 +166,13 @@ func (q *Queue) Count() int {
 	return q.j - q.i
 }
 
+// Active returns the number of items popped but not yet done
+func (q *Queue) Active() int {
+	q.m.Lock()
+	defer q.m.Unlock()
+	return len(q.active)
+}
+
 // Push a new object to the queue
 func (q *Queue) Push(u interface{}) error {
 	q.m.Lock()
@@ -0,0 +1,39 @@
+/*
+ * Minio Client (C) 2016 Minio, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"bytes"
+
+	. "gopkg.in/check.v1"
+)
+
+// Test saving queued objects along with popped objects not yet done.
+func (s *TestSuite) TestQueueSave(c *C) {
+	q := NewQueue()
+	for _, v := range []string{"a", "b", "c"} {
+		c.Assert(q.Push(v), IsNil)
+	}
+	c.Assert(q.Pop(), Equals, "a")
+	c.Assert(q.Pop(), Equals, "b")
+	q.Done("a")
+
+	var buf bytes.Buffer
+	c.Assert(q.Save(&buf), IsNil)
+	c.Assert(buf.String(), Equals, "\"b\"\n\"c\"\n")
+	c.Assert(q.Count(), Equals, 1)
+}
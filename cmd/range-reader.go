@@ -0,0 +1,159 @@
+/*
+ * Minio Client (C) 2016 Minio, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"io/ioutil"
+	"sync"
+
+	"github.com/minio/minio/pkg/probe"
+)
+
+// rangePart - data of a range of an object, or the error reading it.
+type rangePart struct {
+	data []byte
+	err  *probe.Error
+}
+
+// rangeReader - reads an object as ranges fetched concurrently, so that
+// copies across endpoints download the next parts while the current
+// one is uploaded. Ranges are read in order, each buffered in memory.
+type rangeReader struct {
+	// Pending ranges in order, the capacity bounds ranges in flight.
+	parts   chan chan rangePart
+	current *bytes.Reader
+
+	doneCh    chan struct{}
+	closeOnce sync.Once
+}
+
+// newRangeReader - reads size bytes in ranges of partSize, fetching up
+// to concurrency ranges ahead with get.
+func newRangeReader(get func(offset, length int64) (io.Reader, *probe.Error), size, partSize int64, concurrency int) *rangeReader {
+	r := &rangeReader{
+		parts:  make(chan chan rangePart, concurrency),
+		doneCh: make(chan struct{}),
+	}
+	go func() {
+		defer close(r.parts)
+		for offset := int64(0); offset < size; offset += partSize {
+			length := partSize
+			if size-offset < length {
+				length = size - offset
+			}
+			partCh := make(chan rangePart, 1)
+			select {
+			case r.parts <- partCh:
+			case <-r.doneCh:
+				return
+			}
+			go func(offset, length int64) {
+				partCh <- readRange(get, offset, length)
+			}(offset, length)
+		}
+	}()
+	return r
+}
+
+// readRange - reads a whole range into memory.
+func readRange(get func(offset, length int64) (io.Reader, *probe.Error), offset, length int64) rangePart {
+	reader, err := get(offset, length)
+	if err != nil {
+		return rangePart{err: err.Trace()}
+	}
+	if closer, ok := reader.(io.Closer); ok {
+		defer closer.Close()
+	}
+	data, e := ioutil.ReadAll(io.LimitReader(reader, length))
+	if e != nil {
+		return rangePart{err: probe.NewError(e)}
+	}
+	if int64(len(data)) != length {
+		return rangePart{err: probe.NewError(UnexpectedEOF{TotalSize: length, TotalWritten: int64(len(data))})}
+	}
+	return rangePart{data: data}
+}
+
+// Read - implements io.Reader, the first failed range fails the read.
+func (r *rangeReader) Read(p []byte) (int, error) {
+	for r.current == nil || r.current.Len() == 0 {
+		partCh, ok := <-r.parts
+		if !ok {
+			return 0, io.EOF
+		}
+		part := <-partCh
+		if part.err != nil {
+			r.Close()
+			return 0, part.err.ToGoError()
+		}
+		r.current = bytes.NewReader(part.data)
+	}
+	return r.current.Read(p)
+}
+
+// Close - implements io.Closer, stops fetching further ranges.
+func (r *rangeReader) Close() error {
+	r.closeOnce.Do(func() {
+		close(r.doneCh)
+	})
+	return nil
+}
+
+// getSourceRangesFromAlias - reads an object of the given size for an
+// upload in parts of partSize. Objects of several parts are read as
+// ranges fetched concurrently, at least one range ahead of the upload.
+func getSourceRangesFromAlias(ctx context.Context, alias string, urlStr string, size, partSize int64, concurrency int, opts GetOpts) (io.Reader, *probe.Error) {
+	// Verified reads hash the whole object as one stream.
+	if size < 2*partSize || opts.Checksum {
+		return getSourceStreamFromAlias(ctx, alias, urlStr, opts)
+	}
+	sourceClnt, err := newClientFromAlias(alias, urlStr)
+	if err != nil {
+		return nil, err.Trace(alias, urlStr)
+	}
+	if concurrency < 2 {
+		concurrency = 2
+	}
+	get := func(offset, length int64) (io.Reader, *probe.Error) {
+		rangeOpts := opts
+		rangeOpts.Offset = offset
+		rangeOpts.Length = length
+		return sourceClnt.Get(ctx, rangeOpts)
+	}
+	return newRangeReader(get, size, partSize, concurrency), nil
+}
+
+// getSourceRanges - reads a whole object in ranges of partSize, the
+// size of the object is looked up first.
+func getSourceRanges(ctx context.Context, urlStr string, partSize int64, concurrency int, opts GetOpts) (io.Reader, *probe.Error) {
+	alias, urlStrFull, _, err := expandAlias(urlStr)
+	if err != nil {
+		return nil, err.Trace(urlStr)
+	}
+	sourceClnt, err := newClientFromAlias(alias, urlStrFull)
+	if err != nil {
+		return nil, err.Trace(alias, urlStrFull)
+	}
+	content, err := sourceClnt.Stat()
+	if err != nil {
+		return nil, err.Trace(alias, urlStrFull)
+	}
+	return getSourceRangesFromAlias(ctx, alias, urlStrFull, content.Size, partSize, concurrency, opts)
+}
@@ -0,0 +1,92 @@
+/*
+ * Minio Client (C) 2016 Minio, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"io/ioutil"
+	"sync/atomic"
+	"time"
+
+	"github.com/minio/minio/pkg/probe"
+	. "gopkg.in/check.v1"
+)
+
+// Test reading an object in ranges fetched concurrently.
+func (s *TestSuite) TestRangeReader(c *C) {
+	data := bytes.Repeat([]byte("0123456789"), 10)
+	get := func(offset, length int64) (io.Reader, *probe.Error) {
+		return bytes.NewReader(data[offset : offset+length]), nil
+	}
+	r := newRangeReader(get, int64(len(data)), 7, 3)
+	read, e := ioutil.ReadAll(r)
+	c.Assert(e, IsNil)
+	c.Assert(read, DeepEquals, data)
+	c.Assert(r.Close(), IsNil)
+
+	// Failed and short ranges fail the read.
+	get = func(offset, length int64) (io.Reader, *probe.Error) {
+		if offset >= 50 {
+			return nil, probe.NewError(errors.New("range failed"))
+		}
+		return bytes.NewReader(data[offset : offset+length]), nil
+	}
+	r = newRangeReader(get, int64(len(data)), 10, 2)
+	_, e = ioutil.ReadAll(r)
+	c.Assert(e, NotNil)
+
+	get = func(offset, length int64) (io.Reader, *probe.Error) {
+		return bytes.NewReader(data[offset : offset+length-1]), nil
+	}
+	r = newRangeReader(get, int64(len(data)), 10, 2)
+	_, e = ioutil.ReadAll(r)
+	c.Assert(e, FitsTypeOf, UnexpectedEOF{})
+
+	// Ranges are only read ahead while buffers are free, reading a
+	// range frees its buffer for the next one.
+	var fetched int32
+	get = func(offset, length int64) (io.Reader, *probe.Error) {
+		atomic.AddInt32(&fetched, 1)
+		return bytes.NewReader(data[offset : offset+length]), nil
+	}
+	waitFetched := func(n int32) {
+		for timeout := time.After(5 * time.Second); atomic.LoadInt32(&fetched) < n; {
+			select {
+			case <-timeout:
+				c.Fatalf("%d of %d ranges fetched.", atomic.LoadInt32(&fetched), n)
+			case <-time.After(time.Millisecond):
+			}
+		}
+		time.Sleep(20 * time.Millisecond)
+		c.Assert(atomic.LoadInt32(&fetched), Equals, n)
+	}
+	r = newRangeReader(get, int64(len(data)), 10, 3)
+	waitFetched(3)
+	read = make([]byte, 10)
+	_, e = io.ReadFull(r, read)
+	c.Assert(e, IsNil)
+	c.Assert(read, DeepEquals, data[:10])
+	waitFetched(4)
+	c.Assert(r.Close(), IsNil)
+
+	// Closed readers stop fetching ranges.
+	r = newRangeReader(get, int64(len(data)), 10, 2)
+	c.Assert(r.Close(), IsNil)
+	c.Assert(r.Close(), IsNil)
+}
@@ -0,0 +1,235 @@
+/*
+ * Minio Client (C) 2016 Minio, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"encoding/json"
+	"strings"
+
+	"github.com/fatih/color"
+	"github.com/minio/cli"
+	"github.com/minio/mc/pkg/console"
+	"github.com/minio/minio/pkg/probe"
+)
+
+var (
+	rbFlags = []cli.Flag{
+		cli.BoolFlag{
+			Name:  "help, h",
+			Usage: "Help of rb.",
+		},
+		cli.BoolFlag{
+			Name:  "force",
+			Usage: "Remove all objects, versions and incomplete uploads of the bucket before removing it.",
+		},
+		yesFlag,
+		cli.BoolFlag{
+			Name:  "fake",
+			Usage: "Only count what would be removed, without removing anything.",
+		},
+	}
+)
+
+// remove a bucket.
+var rbCmd = cli.Command{
+	Name:   "rb",
+	Usage:  "Remove a bucket [WARNING: Use with care].",
+	Action: mainRemoveBucket,
+	Flags:  append(rbFlags, globalFlags...),
+	CustomHelpTemplate: `NAME:
+   mc {{.Name}} - {{.Usage}}
+
+USAGE:
+   mc {{.Name}} [FLAGS] TARGET [TARGET...]
+
+   Only empty buckets are removed unless ‘--force’ is given. Objects are removed in bulk, all
+   versions are removed from versioned buckets.
+
+FLAGS:
+  {{range .Flags}}{{.}}
+  {{end}}
+EXAMPLES:
+   1. Remove an empty bucket on Amazon S3 cloud storage.
+      $ mc {{.Name}} s3/mybucket
+
+   2. Count the objects which would be removed along with a bucket.
+      $ mc {{.Name}} --force --fake s3/mybucket
+
+   3. Remove a bucket along with all its objects, versions and incomplete uploads.
+      $ mc {{.Name}} --force s3/mybucket
+
+   4. Remove a bucket along with all its objects without being asked to confirm, e.g. in scripts.
+      $ mc {{.Name}} --force --yes s3/mybucket
+`,
+}
+
+// removeBucketMessage is container for remove bucket success messages.
+type removeBucketMessage struct {
+	Status  string `json:"status"`
+	Bucket  string `json:"bucket"`
+	Objects int64  `json:"objects"`
+	Uploads int64  `json:"uploads"`
+	Fake    bool   `json:"fake,omitempty"`
+}
+
+// String colorized remove bucket message.
+func (s removeBucketMessage) String() string {
+	if s.Fake {
+		return console.Colorize("RemoveBucket", trf("Would remove %d objects, %d incomplete uploads and bucket ‘%s’.", s.Objects, s.Uploads, s.Bucket))
+	}
+	if s.Objects > 0 || s.Uploads > 0 {
+		return console.Colorize("RemoveBucket", trf("Removed %d objects, %d incomplete uploads and bucket ‘%s’.", s.Objects, s.Uploads, s.Bucket))
+	}
+	return console.Colorize("RemoveBucket", trf("Removed bucket ‘%s’.", s.Bucket))
+}
+
+// JSON jsonified remove bucket message.
+func (s removeBucketMessage) JSON() string {
+	removeBucketJSONBytes, e := json.Marshal(s)
+	fatalIf(probe.NewError(e), "Unable to marshal into JSON.")
+
+	return string(removeBucketJSONBytes)
+}
+
+// Validate command line arguments.
+func checkRemoveBucketSyntax(ctx *cli.Context) {
+	if !ctx.Args().Present() {
+		cli.ShowCommandHelpAndExit(ctx, "rb", 1) // last argument is exit code
+	}
+	for _, arg := range ctx.Args() {
+		_, targetURL, _ := mustExpandAlias(arg)
+		url := newClientURL(targetURL)
+		bucket := strings.Trim(url.Path, string(url.Separator))
+		if url.Type != ObjectStorage || bucket == "" || strings.Contains(bucket, string(url.Separator)) {
+			fatalIf(errInvalidArgument().Trace(arg), "‘"+arg+"’ is not a bucket.")
+		}
+	}
+	if ctx.Bool("fake") && !ctx.Bool("force") {
+		fatalIf(errInvalidArgument().Trace(ctx.Args()...), "--fake can only be used with --force.")
+	}
+}
+
+// drainBucket - removes all incomplete uploads and objects of the
+// bucket, all versions of versioned buckets. Objects are removed in
+// bulk while listing continues, progress is reported for every object.
+func drainBucket(clnt Client, targetAlias string, isFake bool, progress func(string)) (msg removeBucketMessage, err *probe.Error) {
+	for content := range clnt.List(globalContext, true, true) {
+		if content.Err != nil {
+			return msg, content.Err.Trace(clnt.GetURL().String())
+		}
+		if content.Type.IsDir() {
+			continue
+		}
+		if !isFake {
+			if err = rmObject(targetAlias, content.URL.String(), true); err != nil {
+				return msg, err.Trace(content.URL.String())
+			}
+		}
+		progress(content.URL.String())
+		msg.Uploads++
+	}
+
+	// Buckets which were ever versioned may hold versions besides the
+	// latest objects.
+	listCh := clnt.List(globalContext, true, false)
+	if s3Clnt, ok := clnt.(*s3Client); ok {
+		if versioning, err := s3Clnt.GetVersioning(); err == nil && versioning.Status != "" {
+			listCh = clnt.ListVersions(true)
+		}
+	}
+
+	contentCh := make(chan *ClientContent)
+	go func() {
+		defer close(contentCh)
+		for content := range listCh {
+			if content.Err != nil {
+				err = content.Err.Trace(clnt.GetURL().String())
+				return
+			}
+			if !content.Type.IsDir() {
+				contentCh <- content
+			}
+		}
+	}()
+	resultCh := (<-chan *ClientContent)(contentCh)
+	if !isFake {
+		resultCh = clnt.RemoveBulk(contentCh)
+	}
+	for result := range resultCh {
+		if result.Err != nil {
+			errorIf(result.Err.Trace(result.URL.String()), "Unable to remove ‘"+result.URL.String()+"’.")
+			continue
+		}
+		progress(result.URL.String())
+		msg.Objects++
+	}
+	// Listing failed, if at all, before its channel was closed.
+	return msg, err
+}
+
+// mainRemoveBucket is entry point for rb command.
+func mainRemoveBucket(ctx *cli.Context) {
+	// Set global flags from context.
+	setGlobalsFromContext(ctx)
+
+	// check 'rb' cli arguments.
+	checkRemoveBucketSyntax(ctx)
+
+	// Additional command speific theme customization.
+	console.SetColor("RemoveBucket", color.New(color.FgGreen, color.Bold))
+
+	isForce := ctx.Bool("force")
+	isFake := ctx.Bool("fake")
+	for _, url := range ctx.Args() {
+		targetAlias, targetURL, _ := mustExpandAlias(url)
+		clnt, err := newClientFromAlias(targetAlias, targetURL)
+		if err != nil {
+			errorIf(err.Trace(url), "Invalid target ‘"+url+"’.")
+			continue
+		}
+
+		msg := removeBucketMessage{}
+		if isForce {
+			if !isFake {
+				mustConfirm(ctx, trf("Bucket ‘%s’ will be removed along with %s.", url, describeObjects(url, false)))
+			}
+			progress := func(string) {}
+			if !globalNoProgress && !globalJSON {
+				progress = scanBarFactory()
+			}
+			msg, err = drainBucket(clnt, targetAlias, isFake, progress)
+			if !globalNoProgress && !globalJSON {
+				eraseScanBar()
+			}
+			if err != nil {
+				errorIf(err.Trace(url), "Unable to remove objects of ‘"+url+"’.")
+				continue
+			}
+		}
+
+		if !isFake {
+			if err = clnt.Remove(globalContext, false); err != nil {
+				errorIf(err.Trace(url), "Unable to remove bucket ‘"+url+"’.")
+				continue
+			}
+		}
+		msg.Status = "success"
+		msg.Bucket = url
+		msg.Fake = isFake
+		printMsg(msg)
+	}
+}
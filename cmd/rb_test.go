@@ -0,0 +1,93 @@
+/*
+ * Minio Client (C) 2016 Minio, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"encoding/xml"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+
+	. "gopkg.in/check.v1"
+)
+
+// drainHandler - serves a versioned bucket without incomplete uploads
+// and records the versions removed by multi-object deletes.
+type drainHandler struct {
+	mutex   *sync.Mutex
+	removed *[]deleteObject
+}
+
+func (h drainHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+	switch {
+	case len(query["location"]) == 1:
+		w.Write([]byte("<LocationConstraint xmlns=\"http://doc.s3.amazonaws.com/2006-03-01\"></LocationConstraint>"))
+	case len(query["uploads"]) == 1:
+		w.Write([]byte("<ListMultipartUploadsResult><Bucket>bucket</Bucket><IsTruncated>false</IsTruncated></ListMultipartUploadsResult>"))
+	case len(query["versioning"]) == 1:
+		w.Write([]byte("<VersioningConfiguration><Status>Suspended</Status></VersioningConfiguration>"))
+	case len(query["versions"]) == 1:
+		w.Write([]byte(`<ListVersionsResult><IsTruncated>false</IsTruncated>` +
+			`<Version><Key>a</Key><VersionId>v1</VersionId><IsLatest>true</IsLatest><LastModified>2016-09-01T10:00:00.000Z</LastModified><Size>1</Size></Version>` +
+			`<Version><Key>a</Key><VersionId>v2</VersionId><IsLatest>false</IsLatest><LastModified>2016-08-01T10:00:00.000Z</LastModified><Size>1</Size></Version>` +
+			`<DeleteMarker><Key>b</Key><VersionId>v3</VersionId><IsLatest>true</IsLatest><LastModified>2016-09-02T10:00:00.000Z</LastModified></DeleteMarker>` +
+			`</ListVersionsResult>`))
+	case r.Method == "POST" && len(query["delete"]) == 1:
+		request := deleteRequest{}
+		if e := xml.NewDecoder(r.Body).Decode(&request); e != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		h.mutex.Lock()
+		*h.removed = append(*h.removed, request.Objects...)
+		h.mutex.Unlock()
+		w.Write([]byte("<DeleteResult></DeleteResult>"))
+	default:
+		w.WriteHeader(http.StatusBadRequest)
+	}
+}
+
+// Test removing all versions of a bucket before removing it.
+func (s *TestSuite) TestDrainBucket(c *C) {
+	var removed []deleteObject
+	server := httptest.NewServer(drainHandler{mutex: &sync.Mutex{}, removed: &removed})
+	defer server.Close()
+
+	conf := new(Config)
+	conf.HostURL = server.URL + "/bucket"
+	conf.AccessKey = "WLGDGYAQYIGI833EV05A"
+	conf.SecretKey = "BYvgJM101sHngl2uzjXS/OBF/aMxAN06JrJ3qJlF"
+	conf.Signature = "S3v4"
+	clnt, err := s3New(conf)
+	c.Assert(err, IsNil)
+
+	var progress []string
+	report := func(url string) { progress = append(progress, url) }
+
+	// Nothing is removed while faking.
+	msg, err := drainBucket(clnt, "", true, report)
+	c.Assert(err, IsNil)
+	c.Assert(msg, Equals, removeBucketMessage{Objects: 3})
+	c.Assert(removed, HasLen, 0)
+	c.Assert(progress, HasLen, 3)
+
+	msg, err = drainBucket(clnt, "", false, report)
+	c.Assert(err, IsNil)
+	c.Assert(msg, Equals, removeBucketMessage{Objects: 3})
+	c.Assert(removed, DeepEquals, []deleteObject{{Key: "a", VersionID: "v1"}, {Key: "a", VersionID: "v2"}, {Key: "b", VersionID: "v3"}})
+}
@@ -0,0 +1,266 @@
+/*
+ * Minio Client (C) 2016 Minio, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"path"
+	"path/filepath"
+	"strings"
+	"sync"
+
+	"github.com/fatih/color"
+	"github.com/minio/cli"
+	"github.com/minio/mc/pkg/console"
+	"github.com/minio/minio/pkg/probe"
+)
+
+var (
+	renameFlags = []cli.Flag{
+		cli.BoolFlag{
+			Name:  "help, h",
+			Usage: "Help of rename.",
+		},
+		cli.BoolFlag{
+			Name:  "recursive, r",
+			Usage: "Rename all objects below a prefix.",
+		},
+		cli.IntFlag{
+			Name:  "parallel",
+			Value: 4,
+			Usage: "Number of objects renamed in parallel.",
+		},
+		cli.StringFlag{
+			Name:  "encrypt-key",
+			Usage: "Decrypt and encrypt objects with customer provided keys, as comma separated list of ‘ALIAS/PREFIX=KEY’.",
+		},
+	}
+)
+
+// Rename objects within a bucket.
+var renameCmd = cli.Command{
+	Name:   "rename",
+	Usage:  "Rename objects within a bucket.",
+	Action: mainRename,
+	Flags:  append(renameFlags, globalFlags...),
+	CustomHelpTemplate: `NAME:
+   mc {{.Name}} - {{.Usage}}
+
+USAGE:
+   mc {{.Name}} [FLAGS] SOURCE TARGET
+
+   Objects are copied on the server along with their metadata and removed once copied, no data
+   is transferred. With ‘--recursive’ all objects below the SOURCE prefix are moved below the
+   TARGET prefix.
+
+FLAGS:
+  {{range .Flags}}{{.}}
+  {{end}}
+EXAMPLES:
+   1. Rename an object.
+      $ mc {{.Name}} s3/mybucket/report.csv s3/mybucket/reports/2016-09.csv
+
+   2. Move an object into a folder, keeping its name.
+      $ mc {{.Name}} s3/mybucket/report.csv s3/mybucket/archive/
+
+   3. Rename all objects below a prefix, 16 at a time.
+      $ mc {{.Name}} --recursive --parallel 16 s3/mybucket/logs/2016/ s3/mybucket/archive/logs/2016/
+
+   4. Rename an object encrypted with a customer provided key.
+      $ mc {{.Name}} --encrypt-key "s3/mybucket/=32byteslongsecretkeymustbegiven1" s3/mybucket/secret.txt s3/mybucket/private.txt
+`,
+}
+
+// renameMessage container for renamed objects.
+type renameMessage struct {
+	Status string `json:"status"`
+	Source string `json:"source"`
+	Target string `json:"target"`
+	Size   int64  `json:"size"`
+}
+
+// String colorized rename message.
+func (r renameMessage) String() string {
+	return console.Colorize("Rename", fmt.Sprintf("‘%s’ -> ‘%s’", r.Source, r.Target))
+}
+
+// JSON jsonified rename message.
+func (r renameMessage) JSON() string {
+	r.Status = "success"
+	renameJSONBytes, e := json.Marshal(r)
+	fatalIf(probe.NewError(e), "Unable to marshal into JSON.")
+
+	return string(renameJSONBytes)
+}
+
+// renameBucket - bucket of an alias expanded URL, empty unless it is
+// on S3 compatible storage.
+func renameBucket(alias, urlStr string) string {
+	clnt, err := newClientFromAlias(alias, urlStr)
+	fatalIf(err.Trace(urlStr), "Unable to initialize ‘"+urlStr+"’.")
+	s3Clnt, ok := clnt.(*s3Client)
+	if !ok {
+		return ""
+	}
+	bucket, _ := s3Clnt.url2BucketAndObject()
+	return bucket
+}
+
+// checkRenameSyntax - validate all the passed arguments
+func checkRenameSyntax(ctx *cli.Context) {
+	if len(ctx.Args()) != 2 {
+		cli.ShowCommandHelpAndExit(ctx, "rename", 1) // last argument is exit code
+	}
+	if _, err := parseParallel(ctx.Int("parallel")); err != nil {
+		fatalIf(err.Trace(), "Unable to parse number of parallel renames.")
+	}
+	if _, err := parseEncryptKeys(ctx.String("encrypt-key"), ""); err != nil {
+		fatalIf(err.Trace(), "Unable to parse encryption keys.")
+	}
+
+	source, target := ctx.Args().Get(0), ctx.Args().Get(1)
+	sourceAlias, sourceURL, _ := mustExpandAlias(source)
+	targetAlias, targetURL, _ := mustExpandAlias(target)
+	bucket := renameBucket(sourceAlias, sourceURL)
+	if bucket == "" {
+		fatalIf(errInvalidArgument().Trace(source), "Only objects on S3 compatible storage can be renamed.")
+	}
+	if sourceAlias != targetAlias || newClientURL(sourceURL).Host != newClientURL(targetURL).Host ||
+		renameBucket(targetAlias, targetURL) != bucket {
+		fatalIf(errInvalidArgument().Trace(source, target), "Objects can only be renamed within their bucket.")
+	}
+
+	if ctx.Bool("recursive") {
+		sourcePrefix := strings.TrimSuffix(newClientURL(sourceURL).Path, "/") + "/"
+		if strings.HasPrefix(newClientURL(targetURL).Path, sourcePrefix) {
+			fatalIf(errInvalidArgument().Trace(source, target), "Unable to rename ‘"+source+"’ into itself.")
+		}
+		return
+	}
+	_, content, err := url2Stat(source)
+	fatalIf(err.Trace(source), "Unable to stat ‘"+source+"’.")
+	if content.Type.IsDir() {
+		fatalIf(errInvalidArgument().Trace(source), "‘"+source+"’ is a folder, please use ‘--recursive’ to rename all objects below it.")
+	}
+	// The source would be removed after copying it onto itself.
+	sourcePath := newClientURL(sourceURL).Path
+	targetPath := newClientURL(targetURL).Path
+	if strings.HasSuffix(targetURL, "/") {
+		targetPath = path.Join(targetPath, path.Base(sourcePath))
+	}
+	if targetPath == sourcePath {
+		fatalIf(errInvalidArgument().Trace(source, target), "Unable to rename ‘"+source+"’ to itself.")
+	}
+}
+
+// renameTarget - URL an object below the source prefix is renamed to,
+// its path relative to the prefix is kept below the target prefix.
+func renameTarget(sourcePrefix, targetPrefix string, source ClientURL) string {
+	relPath := strings.TrimPrefix(source.Path, newClientURL(sourcePrefix).Path)
+	return urlJoinPath(targetPrefix, relPath)
+}
+
+// renameObject - copies an object along with its metadata on the
+// server and removes the source once copied.
+func renameObject(alias string, source ClientURL, targetURL string, size int64, encKeys map[string]EncryptOpts) *probe.Error {
+	opts := CopyOpts{
+		SrcSSE: getEncryptOpts(encKeys, filepath.ToSlash(filepath.Join(alias, source.Path))),
+		TgtSSE: getEncryptOpts(encKeys, filepath.ToSlash(filepath.Join(alias, newClientURL(targetURL).Path))),
+	}
+	if err := copySourceStreamFromAlias(globalContext, alias, targetURL, source.Path, size, nil, opts); err != nil {
+		return err.Trace(source.String(), targetURL)
+	}
+	if err := rmObject(alias, source.String(), false); err != nil {
+		return err.Trace(source.String())
+	}
+	return nil
+}
+
+// renameJob - an object to be renamed.
+type renameJob struct {
+	source    ClientURL
+	targetURL string
+	size      int64
+}
+
+// mainRename is the entry point for rename command.
+func mainRename(ctx *cli.Context) {
+	// Set global flags from context.
+	setGlobalsFromContext(ctx)
+
+	// check 'rename' cli arguments.
+	checkRenameSyntax(ctx)
+
+	// Additional command specific theme customization.
+	console.SetColor("Rename", color.New(color.FgGreen, color.Bold))
+
+	isRecursive := ctx.Bool("recursive")
+	parallel, _ := parseParallel(ctx.Int("parallel"))
+	encKeys, _ := parseEncryptKeys(ctx.String("encrypt-key"), "")
+	alias, sourceURL, _ := mustExpandAlias(ctx.Args().Get(0))
+	_, targetURL, _ := mustExpandAlias(ctx.Args().Get(1))
+
+	// Objects are renamed by a pool of workers while listing continues.
+	jobCh := make(chan renameJob)
+	var wg sync.WaitGroup
+	for i := 0; i < parallel; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for job := range jobCh {
+				if err := renameObject(alias, job.source, job.targetURL, job.size, encKeys); err != nil {
+					errorIf(err.Trace(job.source.String()), "Unable to rename ‘"+job.source.String()+"’.")
+					continue
+				}
+				printMsg(renameMessage{
+					Source: filepath.ToSlash(filepath.Join(alias, job.source.Path)),
+					Target: filepath.ToSlash(filepath.Join(alias, newClientURL(job.targetURL).Path)),
+					Size:   job.size,
+				})
+			}
+		}()
+	}
+
+	clnt, err := newClientFromAlias(alias, sourceURL)
+	fatalIf(err.Trace(sourceURL), "Unable to initialize ‘"+sourceURL+"’.")
+	if isRecursive {
+		sourceURL = strings.TrimSuffix(sourceURL, "/") + "/"
+		targetURL = strings.TrimSuffix(targetURL, "/") + "/"
+		clnt, err = newClientFromAlias(alias, sourceURL)
+		fatalIf(err.Trace(sourceURL), "Unable to initialize ‘"+sourceURL+"’.")
+		for content := range clnt.List(globalContext, true, false) {
+			if content.Err != nil {
+				errorIf(content.Err.Trace(sourceURL), "Unable to list ‘"+sourceURL+"’.")
+				break
+			}
+			if content.Type.IsDir() {
+				continue
+			}
+			jobCh <- renameJob{source: content.URL, targetURL: renameTarget(sourceURL, targetURL, content.URL), size: content.Size}
+		}
+	} else {
+		content, err := clnt.Stat()
+		fatalIf(err.Trace(sourceURL), "Unable to stat ‘"+sourceURL+"’.")
+		if strings.HasSuffix(targetURL, "/") {
+			targetURL = urlJoinPath(targetURL, path.Base(content.URL.Path))
+		}
+		jobCh <- renameJob{source: content.URL, targetURL: targetURL, size: content.Size}
+	}
+	close(jobCh)
+	wg.Wait()
+}
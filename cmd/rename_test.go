@@ -0,0 +1,39 @@
+/*
+ * Minio Client (C) 2016 Minio, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	. "gopkg.in/check.v1"
+)
+
+// Test targets of objects renamed below a prefix.
+func (s *TestSuite) TestRenameTarget(c *C) {
+	testCases := []struct {
+		sourcePrefix string
+		targetPrefix string
+		source       string
+		target       string
+	}{
+		{"https://s3.amazonaws.com/bucket/logs/", "https://s3.amazonaws.com/bucket/archive/", "https://s3.amazonaws.com/bucket/logs/a.log", "https://s3.amazonaws.com/bucket/archive/a.log"},
+		{"https://s3.amazonaws.com/bucket/logs/", "https://s3.amazonaws.com/bucket/archive/logs/", "https://s3.amazonaws.com/bucket/logs/2016/09/b.log", "https://s3.amazonaws.com/bucket/archive/logs/2016/09/b.log"},
+		{"https://s3.amazonaws.com/bucket/a/", "https://s3.amazonaws.com/bucket/", "https://s3.amazonaws.com/bucket/a/b/c", "https://s3.amazonaws.com/bucket/b/c"},
+	}
+	for i, testCase := range testCases {
+		target := renameTarget(testCase.sourcePrefix, testCase.targetPrefix, *newClientURL(testCase.source))
+		c.Assert(target, Equals, testCase.target, Commentf("Test %d", i+1))
+	}
+}
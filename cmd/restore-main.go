@@ -0,0 +1,173 @@
+/*
+ * Minio Client (C) 2016 Minio, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"encoding/json"
+	"strconv"
+	"strings"
+
+	"github.com/fatih/color"
+	"github.com/minio/cli"
+	"github.com/minio/mc/pkg/console"
+	"github.com/minio/minio/pkg/probe"
+)
+
+var (
+	restoreFlags = []cli.Flag{
+		cli.BoolFlag{
+			Name:  "help, h",
+			Usage: "Help of restore.",
+		},
+		cli.BoolFlag{
+			Name:  "recursive, r",
+			Usage: "Restore all archived objects under the prefix.",
+		},
+		cli.IntFlag{
+			Name:  "days",
+			Value: 1,
+			Usage: "Number of days the restored copy is kept.",
+		},
+		cli.StringFlag{
+			Name:  "tier",
+			Value: restoreTierStandard,
+			Usage: "Retrieval tier, one of ‘Expedited’, ‘Standard’ or ‘Bulk’.",
+		},
+	}
+)
+
+// Restore archived objects.
+var restoreCmd = cli.Command{
+	Name:   "restore",
+	Usage:  "Restore archived objects for a number of days.",
+	Action: mainRestore,
+	Flags:  append(restoreFlags, globalFlags...),
+	CustomHelpTemplate: `NAME:
+   mc {{.Name}} - {{.Usage}}
+
+USAGE:
+   mc {{.Name}} [FLAGS] TARGET [TARGET...]
+
+FLAGS:
+  {{range .Flags}}{{.}}
+  {{end}}
+EXAMPLES:
+   1. Restore an object archived on Glacier for a week.
+      $ mc {{.Name}} --days 7 s3/archive/2015/ledger.csv
+
+   2. Restore all archived objects under a prefix with the cheapest retrieval tier.
+      $ mc {{.Name}} --recursive --tier Bulk s3/archive/2015/
+
+   3. Copy an archived object once its restore has completed.
+      $ mc {{.Name}} s3/archive/2015/ledger.csv
+      $ mc cp --wait-restore s3/archive/2015/ledger.csv ledger.csv
+`,
+}
+
+// checkRestoreSyntax - validate all the passed arguments
+func checkRestoreSyntax(ctx *cli.Context) {
+	if len(ctx.Args()) == 0 {
+		cli.ShowCommandHelpAndExit(ctx, "restore", 1) // last argument is exit code
+	}
+	if ctx.Int("days") < 1 {
+		fatalIf(errInvalidArgument().Trace(strconv.Itoa(ctx.Int("days"))), "Restored copies have to be kept for at least one day.")
+	}
+	if _, ok := restoreTier(ctx.String("tier")); !ok {
+		fatalIf(errInvalidArgument().Trace(ctx.String("tier")), "Unknown retrieval tier ‘"+ctx.String("tier")+"’.")
+	}
+}
+
+// restoreTier - retrieval tier by name, case is ignored.
+func restoreTier(tier string) (string, bool) {
+	for _, t := range []string{restoreTierExpedited, restoreTierStandard, restoreTierBulk} {
+		if strings.EqualFold(t, tier) {
+			return t, true
+		}
+	}
+	return "", false
+}
+
+// restoreMessage container
+type restoreMessage struct {
+	Status string `json:"status"`
+	Target string `json:"target"`
+	Days   int    `json:"days"`
+	Tier   string `json:"tier"`
+}
+
+func (r restoreMessage) JSON() string {
+	r.Status = "success"
+	restoreMessageJSONBytes, e := json.Marshal(r)
+	fatalIf(probe.NewError(e), "Unable to marshal into JSON.")
+	return string(restoreMessageJSONBytes)
+}
+
+func (r restoreMessage) String() string {
+	return console.Colorize("Restore", "Restore of ‘"+r.Target+"’ requested for "+strconv.Itoa(r.Days)+" day(s).")
+}
+
+// restoreObject - requests the restore of a single object.
+func restoreObject(alias, urlStr string, days int, tier string) *probe.Error {
+	client, err := newClientFromAlias(alias, urlStr)
+	if err != nil {
+		return err.Trace(urlStr)
+	}
+	if err = client.RestoreObject(days, tier); err != nil {
+		return err.Trace(urlStr)
+	}
+	printMsg(restoreMessage{Target: urlStr, Days: days, Tier: tier})
+	return nil
+}
+
+// restoreRecursive - requests the restore of all archived objects
+// under the prefix, other objects are skipped.
+func restoreRecursive(alias, urlStr string, days int, tier string) {
+	client, err := newClientFromAlias(alias, urlStr)
+	fatalIf(err.Trace(urlStr), "Unable to initialize ‘"+urlStr+"’.")
+	for content := range client.List(globalContext, true, false) {
+		if content.Err != nil {
+			errorIf(content.Err.Trace(urlStr), "Unable to list ‘"+urlStr+"’.")
+			continue
+		}
+		if !isArchived(content.StorageClass) {
+			continue
+		}
+		objectURL := content.URL.String()
+		err = restoreObject(alias, objectURL, days, tier)
+		errorIf(err, "Unable to restore ‘"+objectURL+"’.")
+	}
+}
+
+func mainRestore(ctx *cli.Context) {
+	console.SetColor("Restore", color.New(color.FgGreen, color.Bold))
+
+	setGlobalsFromContext(ctx)
+	checkRestoreSyntax(ctx)
+
+	days := ctx.Int("days")
+	tier, _ := restoreTier(ctx.String("tier"))
+	for _, targetURL := range ctx.Args() {
+		alias, urlStr, _, err := expandAlias(targetURL)
+		fatalIf(err.Trace(targetURL), "Unable to parse ‘"+targetURL+"’.")
+		if ctx.Bool("recursive") {
+			restoreRecursive(alias, urlStr, days, tier)
+			continue
+		}
+		err = restoreObject(alias, urlStr, days, tier)
+		errorIf(err, "Unable to restore ‘"+targetURL+"’. Restore is only supported for archived objects on object storage.")
+	}
+}
@@ -0,0 +1,99 @@
+/*
+ * Minio Client (C) 2016 Minio, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"encoding/json"
+
+	"github.com/fatih/color"
+	"github.com/minio/cli"
+	"github.com/minio/mc/pkg/console"
+	"github.com/minio/minio/pkg/probe"
+)
+
+var (
+	retentionClearFlags = []cli.Flag{
+		cli.StringFlag{
+			Name:  "version-id",
+			Usage: "Clear retention of a specific version of the object.",
+		},
+	}
+)
+
+var retentionClearCmd = cli.Command{
+	Name:   "clear",
+	Usage:  "Clear governance retention of an object.",
+	Action: mainRetentionClear,
+	Flags:  append(retentionClearFlags, globalFlags...),
+	CustomHelpTemplate: `NAME:
+   mc retention {{.Name}} - {{.Usage}}
+
+USAGE:
+   mc retention {{.Name}} ALIAS/BUCKET/OBJECT [FLAGS]
+
+FLAGS:
+  {{range .Flags}}{{.}}
+  {{end}}
+EXAMPLES:
+   1. Clear retention of an object, this is allowed only in governance mode.
+     $ mc retention {{.Name}} s3/records/draft.csv
+`,
+}
+
+// checkRetentionClearSyntax - validate all the passed arguments
+func checkRetentionClearSyntax(ctx *cli.Context) {
+	if len(ctx.Args()) != 1 {
+		cli.ShowCommandHelpAndExit(ctx, "clear", 1) // last argument is exit code
+	}
+}
+
+// retentionClearMessage container
+type retentionClearMessage struct {
+	Status    string `json:"status"`
+	Target    string `json:"target"`
+	VersionID string `json:"versionId,omitempty"`
+}
+
+func (r retentionClearMessage) JSON() string {
+	r.Status = "success"
+	retentionClearMessageJSONBytes, e := json.Marshal(r)
+	fatalIf(probe.NewError(e), "Unable to marshal into JSON.")
+	return string(retentionClearMessageJSONBytes)
+}
+
+func (r retentionClearMessage) String() string {
+	return console.Colorize("Retention", "Retention of ‘"+r.Target+"’ cleared.")
+}
+
+func mainRetentionClear(ctx *cli.Context) {
+	console.SetColor("Retention", color.New(color.FgGreen, color.Bold))
+
+	setGlobalsFromContext(ctx)
+	checkRetentionClearSyntax(ctx)
+
+	path := ctx.Args().First()
+	versionID := ctx.String("version-id")
+
+	client, err := newS3Client(path)
+	fatalIf(err, "Unable to initialize ‘"+path+"’, retention is only supported on object storage.")
+
+	// Compliance retention can not be bypassed, server refuses it.
+	err = client.PutObjectRetention(versionID, objectRetention{}, true)
+	fatalIf(err.Trace(path), "Unable to clear retention of ‘"+path+"’.")
+
+	printMsg(retentionClearMessage{Target: path, VersionID: versionID})
+}
@@ -0,0 +1,104 @@
+/*
+ * Minio Client (C) 2016 Minio, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"encoding/json"
+	"strings"
+
+	"github.com/fatih/color"
+	"github.com/minio/cli"
+	"github.com/minio/mc/pkg/console"
+	"github.com/minio/minio/pkg/probe"
+)
+
+var (
+	retentionInfoFlags = []cli.Flag{
+		cli.StringFlag{
+			Name:  "version-id",
+			Usage: "Show retention of a specific version of the object.",
+		},
+	}
+)
+
+var retentionInfoCmd = cli.Command{
+	Name:   "info",
+	Usage:  "Show retention of an object.",
+	Action: mainRetentionInfo,
+	Flags:  append(retentionInfoFlags, globalFlags...),
+	CustomHelpTemplate: `NAME:
+   mc retention {{.Name}} - {{.Usage}}
+
+USAGE:
+   mc retention {{.Name}} ALIAS/BUCKET/OBJECT [FLAGS]
+
+FLAGS:
+  {{range .Flags}}{{.}}
+  {{end}}
+EXAMPLES:
+   1. Show retention of an object.
+     $ mc retention {{.Name}} s3/records/2016/ledger.csv
+`,
+}
+
+// checkRetentionInfoSyntax - validate all the passed arguments
+func checkRetentionInfoSyntax(ctx *cli.Context) {
+	if len(ctx.Args()) != 1 {
+		cli.ShowCommandHelpAndExit(ctx, "info", 1) // last argument is exit code
+	}
+}
+
+// retentionInfoMessage container
+type retentionInfoMessage struct {
+	Status    string          `json:"status"`
+	Target    string          `json:"target"`
+	VersionID string          `json:"versionId,omitempty"`
+	Retention objectRetention `json:"retention"`
+}
+
+func (r retentionInfoMessage) JSON() string {
+	r.Status = "success"
+	retentionInfoMessageJSONBytes, e := json.Marshal(r)
+	fatalIf(probe.NewError(e), "Unable to marshal into JSON.")
+	return string(retentionInfoMessageJSONBytes)
+}
+
+func (r retentionInfoMessage) String() string {
+	if r.Retention.Mode == "" || r.Retention.RetainUntilDate == nil {
+		return console.Colorize("Retention", "‘"+r.Target+"’ is not retained.")
+	}
+	return console.Colorize("Retention", "‘"+r.Target+"’ is retained in "+strings.ToLower(r.Retention.Mode)+
+		" mode until "+r.Retention.RetainUntilDate.Format(printDate)+".")
+}
+
+func mainRetentionInfo(ctx *cli.Context) {
+	console.SetColor("Retention", color.New(color.FgCyan, color.Bold))
+
+	setGlobalsFromContext(ctx)
+	checkRetentionInfoSyntax(ctx)
+
+	path := ctx.Args().First()
+	versionID := ctx.String("version-id")
+
+	client, err := newS3Client(path)
+	fatalIf(err, "Unable to initialize ‘"+path+"’, retention is only supported on object storage.")
+
+	retention, err := client.GetObjectRetention(versionID)
+	fatalIf(err.Trace(path), "Unable to get retention of ‘"+path+"’.")
+
+	printMsg(retentionInfoMessage{Target: path, VersionID: versionID, Retention: retention})
+}
@@ -0,0 +1,156 @@
+/*
+ * Minio Client (C) 2016 Minio, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"encoding/json"
+	"strings"
+	"time"
+
+	"github.com/fatih/color"
+	"github.com/minio/cli"
+	"github.com/minio/mc/pkg/console"
+	"github.com/minio/minio/pkg/probe"
+)
+
+var (
+	retentionSetFlags = []cli.Flag{
+		cli.StringFlag{
+			Name:  "mode",
+			Usage: "Retention mode, one of ‘governance’ or ‘compliance’.",
+		},
+		cli.IntFlag{
+			Name:  "days",
+			Usage: "Retain the object for a number of days from now.",
+		},
+		cli.StringFlag{
+			Name:  "until",
+			Usage: "Retain the object until a date, formatted as YYYY-MM-DD.",
+		},
+		cli.BoolFlag{
+			Name:  "bypass",
+			Usage: "Bypass governance retention, needed to shorten it.",
+		},
+		cli.StringFlag{
+			Name:  "version-id",
+			Usage: "Set retention of a specific version of the object.",
+		},
+	}
+)
+
+var retentionSetCmd = cli.Command{
+	Name:   "set",
+	Usage:  "Set retention of an object.",
+	Action: mainRetentionSet,
+	Flags:  append(retentionSetFlags, globalFlags...),
+	CustomHelpTemplate: `NAME:
+   mc retention {{.Name}} - {{.Usage}}
+
+USAGE:
+   mc retention {{.Name}} --mode MODE --days DAYS | --until DATE ALIAS/BUCKET/OBJECT
+
+FLAGS:
+  {{range .Flags}}{{.}}
+  {{end}}
+EXAMPLES:
+   1. Retain an object in compliance mode for a year.
+     $ mc retention {{.Name}} --mode compliance --days 365 s3/records/2016/ledger.csv
+   2. Retain an object in governance mode until a date.
+     $ mc retention {{.Name}} --mode governance --until 2017-12-31 s3/records/2016/ledger.csv
+   3. Shorten governance retention of a specific version.
+     $ mc retention {{.Name}} --mode governance --days 7 --bypass --version-id "3/L4kqtJlcpXroDTDmJ+rmSpXd3dIbrHY" s3/records/draft.csv
+`,
+}
+
+// checkRetentionSetSyntax - validate all the passed arguments
+func checkRetentionSetSyntax(ctx *cli.Context) {
+	if len(ctx.Args()) != 1 || ctx.String("mode") == "" {
+		cli.ShowCommandHelpAndExit(ctx, "set", 1) // last argument is exit code
+	}
+	switch strings.ToUpper(ctx.String("mode")) {
+	case retentionGovernance, retentionCompliance:
+	default:
+		fatalIf(errInvalidArgument().Trace(ctx.String("mode")), "Unknown retention mode ‘"+ctx.String("mode")+"’.")
+	}
+}
+
+// retentionUntil - date until which an object is retained, given
+// either as days from now or as a date.
+func retentionUntil(days int, until string, now time.Time) (time.Time, *probe.Error) {
+	if (days == 0) == (until == "") {
+		return time.Time{}, errInvalidArgument().Trace(until)
+	}
+	if days < 0 {
+		return time.Time{}, errInvalidArgument().Trace(until)
+	}
+	if days > 0 {
+		return now.UTC().AddDate(0, 0, days), nil
+	}
+	t, e := time.Parse(ilmDateFormat, until)
+	if e != nil {
+		return time.Time{}, probe.NewError(e)
+	}
+	if !t.After(now) {
+		return time.Time{}, errInvalidArgument().Trace(until)
+	}
+	return t.UTC(), nil
+}
+
+// retentionSetMessage container
+type retentionSetMessage struct {
+	Status    string          `json:"status"`
+	Target    string          `json:"target"`
+	VersionID string          `json:"versionId,omitempty"`
+	Retention objectRetention `json:"retention"`
+}
+
+func (r retentionSetMessage) JSON() string {
+	r.Status = "success"
+	retentionSetMessageJSONBytes, e := json.Marshal(r)
+	fatalIf(probe.NewError(e), "Unable to marshal into JSON.")
+	return string(retentionSetMessageJSONBytes)
+}
+
+func (r retentionSetMessage) String() string {
+	return console.Colorize("Retention", "‘"+r.Target+"’ retained in "+strings.ToLower(r.Retention.Mode)+
+		" mode until "+r.Retention.RetainUntilDate.Format(printDate)+".")
+}
+
+func mainRetentionSet(ctx *cli.Context) {
+	console.SetColor("Retention", color.New(color.FgGreen, color.Bold))
+
+	setGlobalsFromContext(ctx)
+	checkRetentionSetSyntax(ctx)
+
+	path := ctx.Args().First()
+	versionID := ctx.String("version-id")
+
+	until, err := retentionUntil(ctx.Int("days"), ctx.String("until"), time.Now())
+	fatalIf(err, "Either --days or --until in the future must be specified.")
+	retention := objectRetention{
+		Mode:            strings.ToUpper(ctx.String("mode")),
+		RetainUntilDate: &until,
+	}
+
+	client, err := newS3Client(path)
+	fatalIf(err, "Unable to initialize ‘"+path+"’, retention is only supported on object storage.")
+
+	err = client.PutObjectRetention(versionID, retention, ctx.Bool("bypass"))
+	fatalIf(err.Trace(path), "Unable to set retention of ‘"+path+"’.")
+
+	printMsg(retentionSetMessage{Target: path, VersionID: versionID, Retention: retention})
+}
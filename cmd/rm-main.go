@@ -19,7 +19,6 @@ package cmd
 import (
 	"bufio"
 	"encoding/json"
-	"fmt"
 	"os"
 	"path"
 	"path/filepath"
@@ -51,6 +50,7 @@ var (
 			Name:  "force",
 			Usage: "Force a dangerous remove operation.",
 		},
+		yesFlag,
 		cli.BoolFlag{
 			Name:  "prefix",
 			Usage: "Remove objects matching this prefix.",
@@ -71,6 +71,36 @@ var (
 			Name:  "older",
 			Usage: "Remove object only if its created older than given time.",
 		},
+		cli.StringFlag{
+			Name:  "version-id",
+			Usage: "Remove a specific version of the object.",
+		},
+		cli.StringSliceFlag{
+			Name:  "include",
+			Value: &cli.StringSlice{},
+			Usage: "Only remove files matching the gitignore style pattern when removing recursively, may be repeated.",
+		},
+		cli.StringSliceFlag{
+			Name:  "exclude",
+			Value: &cli.StringSlice{},
+			Usage: "Keep files and folders matching the gitignore style pattern when removing recursively, may be repeated.",
+		},
+		cli.StringFlag{
+			Name:  "newer-than",
+			Usage: "Only remove files modified within the given age, e.g. ‘12h’ or ‘7d’.",
+		},
+		cli.StringFlag{
+			Name:  "older-than",
+			Usage: "Only remove files modified before the given age, e.g. ‘30d’.",
+		},
+		cli.StringFlag{
+			Name:  "larger-than",
+			Usage: "Only remove files larger than the given size, e.g. ‘10MiB’.",
+		},
+		cli.StringFlag{
+			Name:  "smaller-than",
+			Usage: "Only remove files smaller than the given size, e.g. ‘1KiB’.",
+		},
 	}
 )
 
@@ -110,18 +140,37 @@ EXAMPLES:
 
    7. Remove object only if its created older than one day.
       $ mc {{.Name}} --force --older=24h s3/jazz-songs/louis/
+
+   8. Remove a specific version of an object in a versioned bucket.
+      $ mc {{.Name}} --version-id "3/L4kqtJlcpXroDTDmJ+rmSpXd3dIbrHY" s3/jazz-songs/louis/file01.mp4
+
+   9. Remove a specific version of an object in a bucket with MFA delete enabled.
+      $ MC_MFA_s3="arn:aws:iam::123456789012:mfa/root-account-mfa-device 123456" mc {{.Name}} --version-id "3/L4kqtJlcpXroDTDmJ+rmSpXd3dIbrHY" s3/jazz-songs/louis/file01.mp4
+
+  10. Remove temporary files of a folder recursively, keeping everything else.
+      $ mc {{.Name}} --recursive --force --include "*.tmp" s3/jazz-songs/louis/
+
+  11. Prune backups older than 90 days, keeping small marker files.
+      $ mc {{.Name}} --recursive --force --older-than 90d --larger-than 1KiB s3/backups/
+
+  12. Remove contents of a folder recursively without being asked to confirm, e.g. in scripts.
+      $ mc {{.Name}} --recursive --yes s3/jazz-songs/louis/
 `,
 }
 
 // Structured message depending on the type of console.
 type rmMessage struct {
-	Status string `json:"status"`
-	URL    string `json:"url"`
+	Status    string `json:"status"`
+	URL       string `json:"url"`
+	VersionID string `json:"versionId,omitempty"`
 }
 
 // Colorized message for console printing.
 func (r rmMessage) String() string {
-	return console.Colorize("Remove", fmt.Sprintf("Removed ‘%s’.", r.URL))
+	if r.VersionID != "" {
+		return console.Colorize("Remove", trf("Removed ‘%s’ (version ‘%s’).", r.URL, r.VersionID))
+	}
+	return console.Colorize("Remove", trf("Removed ‘%s’.", r.URL))
 }
 
 // JSON'ified message for scripting.
@@ -158,10 +207,21 @@ func checkRmSyntax(ctx *cli.Context) {
 		cli.ShowCommandHelpAndExit(ctx, "rm", exitCode)
 	}
 
-	// For all recursive operations make sure to check for 'force' flag.
-	if (isPrefix || isRecursive || isStdin) && !isForce {
+	// A version can only be removed from a single object.
+	if ctx.String("version-id") != "" && (isPrefix || isRecursive || isStdin || ctx.Bool("incomplete") || olderString != "") {
+		fatalIf(errInvalidArgument().Trace(),
+			"--version-id cannot be used with --prefix, --recursive, --stdin, --incomplete or --older.")
+	}
+
+	if _, err := newContentFilter(filterOptsFromContext(ctx)); err != nil {
+		fatalIf(err.Trace(), "Unable to parse filters.")
+	}
+
+	// For all recursive operations make sure to check for 'force' flag,
+	// interactive users are asked instead.
+	if (isPrefix || isRecursive || isStdin) && !isForce && !ctx.Bool("yes") && (isStdin || !isInteractive()) {
 		fatalIf(errDummy().Trace(),
-			"Removal requires --force option. This operational is irreversible. Please review carefully before performing this *DANGEROUS* operation.")
+			"Removal requires --force or --yes option. This operational is irreversible. Please review carefully before performing this *DANGEROUS* operation.")
 	}
 }
 
@@ -172,15 +232,30 @@ func rmObject(targetAlias, targetURL string, isIncomplete bool) *probe.Error {
 		return err.Trace(targetURL)
 	}
 
-	if err = clnt.Remove(isIncomplete); err != nil {
+	if err = clnt.Remove(globalContext, isIncomplete); err != nil {
 		return err.Trace(targetURL)
 	}
 
 	return nil
 }
 
-// Remove a single object.
-func rm(targetAlias, targetURL string, isIncomplete, isFake bool, older time.Duration) *probe.Error {
+// Remove a specific version of a single object.
+func rmVersion(targetAlias, targetURL, versionID string, isFake bool) *probe.Error {
+	clnt, err := newClientFromAlias(targetAlias, targetURL)
+	if err != nil {
+		return err.Trace(targetURL)
+	}
+	if isFake {
+		return nil
+	}
+	if err = clnt.RemoveVersion(versionID); err != nil {
+		return err.Trace(targetURL, versionID)
+	}
+	return nil
+}
+
+// Remove a single object, removals are recorded to the journal.
+func rm(targetAlias, targetURL string, isIncomplete, isFake bool, older time.Duration, journal *undoJournal) *probe.Error {
 	clnt, err := newClientFromAlias(targetAlias, targetURL)
 	if err != nil {
 		return err.Trace(targetURL)
@@ -205,13 +280,21 @@ func rm(targetAlias, targetURL string, isIncomplete, isFake bool, older time.Dur
 		if err := rmObject(targetAlias, targetURL, isIncomplete); err != nil {
 			return err.Trace(targetURL)
 		}
+		if !isIncomplete {
+			if err := journal.recordRemove(targetAlias, targetURL); err != nil {
+				errorIf(err.Trace(targetURL), "Unable to record removal of ‘"+targetURL+"’ for undo.")
+			}
+		}
 	}
 
 	return nil
 }
 
-// Remove all objects recursively.
-func rmAll(targetAlias, targetURL, prefix string, isRecursive, isIncomplete, isFake bool, older time.Duration) {
+// Remove all objects recursively, objects are removed in bulk. Paths
+// matched by the filter are relative to the folder of rootPath, folders
+// are kept when filtering as they may hold files which are kept. Removed
+// objects are recorded to the journal.
+func rmAll(targetAlias, targetURL, prefix string, isRecursive, isIncomplete, isFake bool, older time.Duration, filter *contentFilter, rootPath string, journal *undoJournal) {
 	// Initialize new client.
 	clnt, err := newClientFromAlias(targetAlias, targetURL)
 	if err != nil {
@@ -219,10 +302,33 @@ func rmAll(targetAlias, targetURL, prefix string, isRecursive, isIncomplete, isF
 		return // End of journey.
 	}
 
+	// Objects are sent to be removed in bulk while listing continues.
+	contentCh := make(chan *ClientContent)
+	doneCh := make(chan struct{})
+	go func() {
+		defer close(doneCh)
+		for result := range clnt.RemoveBulk(contentCh) {
+			if result.Err != nil {
+				errorIf(result.Err.Trace(result.URL.String()), "Unable to remove ‘"+result.URL.String()+"’.")
+				continue
+			}
+			if err := journal.recordRemove(targetAlias, result.URL.String()); err != nil {
+				errorIf(err.Trace(result.URL.String()), "Unable to record removal of ‘"+result.URL.String()+"’ for undo.")
+			}
+			// Construct user facing message and path.
+			entryPath := filepath.ToSlash(filepath.Join(targetAlias, result.URL.Path))
+			printMsg(rmMessage{Status: "success", URL: entryPath})
+		}
+	}()
+	defer func() {
+		close(contentCh)
+		<-doneCh
+	}()
+
 	/* Disable recursion and only list this folder's contents. We
 	perform manual depth-first recursion ourself here. */
 	nonRecursive := false
-	for entry := range clnt.List(nonRecursive, isIncomplete) {
+	for entry := range filter.Filter(clnt.List(globalContext, nonRecursive, isIncomplete), rootPath) {
 		if entry.Err != nil {
 			errorIf(entry.Err.Trace(targetURL), "Unable to list ‘"+targetURL+"’.")
 			return // End of journey.
@@ -239,7 +345,10 @@ func rmAll(targetAlias, targetURL, prefix string, isRecursive, isIncomplete, isF
 			url.Path = strings.TrimSuffix(entry.URL.Path, string(entry.URL.Separator)) + string(entry.URL.Separator)
 
 			// Recursively remove contents of this directory.
-			rmAll(targetAlias, url.String(), prefix, isRecursive, isIncomplete, isFake, older)
+			rmAll(targetAlias, url.String(), prefix, isRecursive, isIncomplete, isFake, older, filter, rootPath, journal)
+		}
+		if entry.Type.IsDir() && filter != nil {
+			continue
 		}
 
 		// Check whether object is created older than given time only if older is >= one hour.
@@ -253,6 +362,13 @@ func rmAll(targetAlias, targetURL, prefix string, isRecursive, isIncomplete, isF
 		}
 
 		// Regular type.
+		if !entry.Type.IsDir() && !isIncomplete && !isFake {
+			contentCh <- entry
+			continue
+		}
+
+		// Folders are removed once their contents are, incomplete
+		// uploads one at a time.
 		if !isFake {
 			if err = rmObject(targetAlias, entry.URL.String(), isIncomplete); err != nil {
 				errorIf(err.Trace(entry.URL.String()), "Unable to remove ‘"+entry.URL.String()+"’.")
@@ -283,10 +399,35 @@ func mainRm(ctx *cli.Context) {
 	isStdin := ctx.Bool("stdin")
 	olderString := ctx.String("older")
 	older, _ := time.ParseDuration(olderString)
+	versionID := ctx.String("version-id")
+	filter, _ := newContentFilter(filterOptsFromContext(ctx))
 
 	// Set color.
 	console.SetColor("Remove", color.New(color.FgGreen, color.Bold))
 
+	// Recursive removals without --force are confirmed with the objects
+	// which would be removed.
+	if (isPrefix || isRecursive) && !isForce && !isFake {
+		for _, url := range ctx.Args() {
+			impact := trf("All objects with prefix ‘%s’ will be removed.", url)
+			if isRecursive {
+				impact = trf("All %s of ‘%s’ will be removed.", describeObjects(url, isIncomplete), url)
+			}
+			mustConfirm(ctx, impact)
+		}
+		isForce = true
+	}
+
+	// Removals in versioned buckets are recorded, so that they can be
+	// undone. Versions and incomplete uploads are removed for good.
+	var journal *undoJournal
+	if !isFake && !isIncomplete && versionID == "" {
+		journal = newUndoJournal("rm", ctx.Args())
+		defer func() {
+			errorIf(journal.Close().Trace(), "Unable to save removals for undo.")
+		}()
+	}
+
 	// Support multiple targets.
 	for _, url := range ctx.Args() {
 		prefix := ""
@@ -301,10 +442,16 @@ func mainRm(ctx *cli.Context) {
 		}
 
 		targetAlias, targetURL, _ := mustExpandAlias(url)
-		if (isPrefix || isRecursive) && isForce {
-			rmAll(targetAlias, targetURL, prefix, isRecursive, isIncomplete, isFake, older)
+		if versionID != "" {
+			if err := rmVersion(targetAlias, targetURL, versionID, isFake); err != nil {
+				errorIf(err.Trace(url), "Unable to remove version ‘"+versionID+"’ of ‘"+url+"’.")
+				continue
+			}
+			printMsg(rmMessage{Status: "success", URL: url, VersionID: versionID})
+		} else if (isPrefix || isRecursive) && isForce {
+			rmAll(targetAlias, targetURL, prefix, isRecursive, isIncomplete, isFake, older, filter, newClientURL(targetURL).Path, journal)
 		} else {
-			if err := rm(targetAlias, targetURL, isIncomplete, isFake, older); err != nil {
+			if err := rm(targetAlias, targetURL, isIncomplete, isFake, older, journal); err != nil {
 				errorIf(err.Trace(url), "Unable to remove ‘"+url+"’.")
 				continue
 			}
@@ -332,9 +479,9 @@ func mainRm(ctx *cli.Context) {
 
 		targetAlias, targetURL, _ := mustExpandAlias(url)
 		if (isPrefix || isRecursive) && isForce {
-			rmAll(targetAlias, targetURL, prefix, isRecursive, isIncomplete, isFake, older)
+			rmAll(targetAlias, targetURL, prefix, isRecursive, isIncomplete, isFake, older, filter, newClientURL(targetURL).Path, journal)
 		} else {
-			if err := rm(targetAlias, targetURL, isIncomplete, isFake, older); err != nil {
+			if err := rm(targetAlias, targetURL, isIncomplete, isFake, older, journal); err != nil {
 				errorIf(err.Trace(url), "Unable to remove ‘"+url+"’.")
 				continue
 			}
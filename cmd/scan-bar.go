@@ -23,7 +23,6 @@ import (
 	"github.com/cheggaaa/pb"
 	"github.com/dustin/go-humanize"
 	"github.com/minio/mc/pkg/console"
-	"github.com/minio/minio/pkg/probe"
 )
 
 // fixateScanBar truncates or stretches text to fit within the terminal size.
@@ -47,7 +46,10 @@ type scanBarFunc func(string)
 func scanBarFactory() scanBarFunc {
 	fileCount := 0
 	termWidth, e := pb.GetTerminalWidth()
-	fatalIf(probe.NewError(e), "Unable to get terminal size. Please use --quiet option.")
+	if e != nil {
+		// Scanning is not shown when the output is not a terminal.
+		return func(string) {}
+	}
 
 	// Cursor animate channel.
 	cursorCh := cursorAnimate()
@@ -59,3 +61,12 @@ func scanBarFactory() scanBarFunc {
 		fileCount++
 	}
 }
+
+// eraseScanBar clears the line of the scan bar once scanning is done.
+func eraseScanBar() {
+	termWidth, e := pb.GetTerminalWidth()
+	if e != nil {
+		return
+	}
+	console.PrintC("\r" + strings.Repeat(" ", termWidth) + "\r")
+}
@@ -104,21 +104,19 @@ func listSessions() *probe.Error {
 
 // clearSessionMessage container for clearing session messages.
 type clearSessionMessage struct {
-	Status    string `json:"success"`
+	Status    string `json:"status"`
 	SessionID string `json:"sessionId"`
+	// Broken sessions are cleared forcefully.
+	Forced bool `json:"forced,omitempty"`
 }
 
 // String colorized clear session message.
 func (c clearSessionMessage) String() string {
 	msg := "Session ‘" + c.SessionID + "’"
-	var colorizedMsg string
-	switch c.Status {
-	case "success":
-		colorizedMsg = console.Colorize("ClearSession", msg+" cleared successfully.")
-	case "forced":
-		colorizedMsg = console.Colorize("ClearSession", msg+" cleared forcefully.")
+	if c.Forced {
+		return console.Colorize("ClearSession", msg+" cleared forcefully.")
 	}
-	return colorizedMsg
+	return console.Colorize("ClearSession", msg+" cleared successfully.")
 }
 
 // JSON jsonified clear session message.
@@ -153,7 +151,7 @@ func clearSession(sid string) {
 		// and wants the associated session files to be removed
 		removeSessionFile(sid)
 		removeSessionDataFile(sid)
-		printMsg(clearSessionMessage{Status: "forced", SessionID: sid})
+		printMsg(clearSessionMessage{Status: "success", SessionID: sid, Forced: true})
 		return
 	}
 
@@ -248,6 +246,10 @@ func mainSession(ctx *cli.Context) {
 		}
 		s, err := loadSessionV8(sid)
 		fatalIf(err.Trace(sid), "Unable to load session.")
+		if s.Header.CommandType == "undo" {
+			s.Close()
+			fatalIf(errInvalidArgument().Trace(sid), "Session ‘"+sid+"’ records operations to undo, please use ‘mc undo "+sid+"’.")
+		}
 
 		// Restore the state of global variables from this previous session.
 		s.restoreGlobals()
@@ -20,7 +20,6 @@ import (
 	"os"
 	"strconv"
 
-	"github.com/minio/mc/pkg/console"
 	"github.com/minio/minio/pkg/probe"
 	"github.com/minio/minio/pkg/quick"
 )
@@ -73,7 +72,7 @@ func migrateSessionV7ToV8() {
 		e = qs.Save(sessionFile)
 		fatalIf(probe.NewError(e).Trace(sid, sessionFile), "Unable to migrate session from '7' to '8'.")
 
-		console.Println("Successfully migrated ‘" + sessionFile + "’ from version ‘" + sV7.Header.Version + "’ to " + "‘" + sV8Header.Version + "’.")
+		printInfo("Successfully migrated ‘" + sessionFile + "’ from version ‘" + sV7.Header.Version + "’ to " + "‘" + sV8Header.Version + "’.")
 	}
 }
 
@@ -122,7 +121,7 @@ func migrateSessionV6ToV7() {
 		e = qs.Save(sessionFile)
 		fatalIf(probe.NewError(e).Trace(sid, sessionFile), "Unable to migrate session from '6' to '7'.")
 
-		console.Println("Successfully migrated ‘" + sessionFile + "’ from version ‘" + sV6Header.Version + "’ to " + "‘" + sV7Header.Version + "’.")
+		printInfo("Successfully migrated ‘" + sessionFile + "’ from version ‘" + sV6Header.Version + "’ to " + "‘" + sV7Header.Version + "’.")
 	}
 }
 
@@ -153,7 +152,7 @@ func migrateSessionV5ToV6() {
 		sessionDataFile, err := getSessionDataFile(sid)
 		fatalIf(err.Trace(sid), "Unable to get session data file.")
 
-		console.Println("Removing unsupported session file ‘" + sessionFile + "’ version ‘" + sV6Header.Version + "’.")
+		printInfo("Removing unsupported session file ‘" + sessionFile + "’ version ‘" + sV6Header.Version + "’.")
 		if e := os.Remove(sessionFile); e != nil {
 			fatalIf(probe.NewError(e), "Unable to remove version ‘"+sV6Header.Version+"’ session file ‘"+sessionFile+"’.")
 		}
@@ -0,0 +1,148 @@
+/*
+ * Minio Client (C) 2016 Minio, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"github.com/minio/minio/pkg/probe"
+	"github.com/minio/minio/pkg/quick"
+)
+
+// uploadStateV1 - persisted state of an interrupted multipart upload,
+// kept in the session folder until the upload completes.
+type uploadStateV1 struct {
+	Version  string         `json:"version"`
+	UploadID string         `json:"uploadId"`
+	Bucket   string         `json:"bucket"`
+	Object   string         `json:"object"`
+	Size     int64          `json:"size"`
+	PartSize int64          `json:"partSize"`
+	Parts    []completePart `json:"parts"`
+	When     time.Time      `json:"time"`
+
+	key   string
+	mutex *sync.Mutex
+}
+
+// getUploadStateFile - file of the upload state, uploads are stored
+// along sessions but with their own extension.
+func getUploadStateFile(key string) (string, *probe.Error) {
+	sessionDir, err := getSessionDir()
+	if err != nil {
+		return "", err.Trace()
+	}
+	return filepath.Join(sessionDir, key+".upload"), nil
+}
+
+// newUploadState - state of a new upload.
+func newUploadState(key, uploadID, bucket, object string, size, partSize int64) *uploadStateV1 {
+	return &uploadStateV1{
+		Version:  "1",
+		UploadID: uploadID,
+		Bucket:   bucket,
+		Object:   object,
+		Size:     size,
+		PartSize: partSize,
+		When:     time.Now().UTC(),
+		key:      key,
+		mutex:    new(sync.Mutex),
+	}
+}
+
+// loadUploadState - loads the state of an earlier upload, nil is
+// returned if there is none.
+func loadUploadState(key string) (*uploadStateV1, *probe.Error) {
+	stateFile, err := getUploadStateFile(key)
+	if err != nil {
+		return nil, err.Trace(key)
+	}
+	if _, e := os.Stat(stateFile); e != nil {
+		if os.IsNotExist(e) {
+			return nil, nil
+		}
+		return nil, probe.NewError(e)
+	}
+	qs, e := quick.Load(stateFile, &uploadStateV1{Version: "1"})
+	if e != nil {
+		return nil, probe.NewError(e).Trace(stateFile)
+	}
+	state := qs.Data().(*uploadStateV1)
+	state.key = key
+	state.mutex = new(sync.Mutex)
+	return state, nil
+}
+
+// addPart - records an uploaded part and saves the state.
+func (u *uploadStateV1) addPart(part completePart) *probe.Error {
+	u.mutex.Lock()
+	defer u.mutex.Unlock()
+	u.Parts = append(u.Parts, part)
+	return u.save()
+}
+
+// completedParts - parts uploaded without a gap from the first part,
+// the upload resumes after them.
+func (u *uploadStateV1) completedParts() []completePart {
+	u.mutex.Lock()
+	defer u.mutex.Unlock()
+	uploaded := make(map[int]completePart)
+	for _, part := range u.Parts {
+		uploaded[part.PartNumber] = part
+	}
+	var parts []completePart
+	for partNumber := 1; ; partNumber++ {
+		part, ok := uploaded[partNumber]
+		if !ok {
+			return parts
+		}
+		parts = append(parts, part)
+	}
+}
+
+// save - writes the state, callers hold the mutex.
+func (u *uploadStateV1) save() *probe.Error {
+	if err := createSessionDir(); err != nil {
+		return err.Trace()
+	}
+	stateFile, err := getUploadStateFile(u.key)
+	if err != nil {
+		return err.Trace(u.key)
+	}
+	qs, e := quick.New(u)
+	if e != nil {
+		return probe.NewError(e).Trace(u.key)
+	}
+	if e = qs.Save(stateFile); e != nil {
+		return probe.NewError(e).Trace(stateFile)
+	}
+	return nil
+}
+
+// removeUploadState - removes the state of an upload, along with the
+// backup kept by quick.
+func removeUploadState(key string) {
+	stateFile, err := getUploadStateFile(key)
+	if err != nil {
+		return
+	}
+	os.Remove(stateFile)
+	os.Remove(stateFile + ".old")
+}
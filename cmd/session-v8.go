@@ -49,6 +49,14 @@ type sessionV8Header struct {
 	LastRemoved        string            `json:"lastRemoved"`
 	TotalBytes         int64             `json:"totalBytes"`
 	TotalObjects       int               `json:"totalObjects"`
+
+	// Prepared is set once all URLs are saved to the session data,
+	// resumed sessions do not list the sources again.
+	Prepared bool `json:"prepared,omitempty"`
+	// Copied holds indexes in the session data of objects copied
+	// after the last copied object, parallel copies finish out of
+	// order.
+	Copied []int `json:"copied,omitempty"`
 }
 
 // sessionMessage container for session messages
@@ -180,7 +188,7 @@ func newSessionV8() *sessionV8 {
 
 // HasData provides true if this is a session resume, false otherwise.
 func (s sessionV8) HasData() bool {
-	return s.Header.LastCopied != "" || s.Header.LastRemoved != ""
+	return s.Header.Prepared || s.Header.LastCopied != "" || s.Header.LastRemoved != ""
 }
 
 // NewDataReader provides reader interface to session data file.
@@ -236,6 +244,16 @@ func (s *sessionV8) setGlobals() {
 	s.Header.GlobalBoolFlags["json"] = globalJSON
 	s.Header.GlobalBoolFlags["noColor"] = globalNoColor
 	s.Header.GlobalBoolFlags["insecure"] = globalInsecure
+	s.Header.GlobalBoolFlags["noProgress"] = globalNoProgress
+	s.Header.GlobalStringFlags["dial-timeout"] = globalTransport.DialTimeout.String()
+	s.Header.GlobalStringFlags["response-header-timeout"] = globalTransport.ResponseHeaderTimeout.String()
+	s.Header.GlobalStringFlags["tls-handshake-timeout"] = globalTransport.TLSHandshakeTimeout.String()
+	s.Header.GlobalIntFlags["max-idle-conns-per-host"] = globalTransport.MaxIdleConnsPerHost
+	// Secret keys are not saved, they are set again on resume.
+	if globalEndpoint != nil {
+		s.Header.GlobalStringFlags["endpoint-url"] = globalEndpoint.URL
+		s.Header.GlobalStringFlags["access-key"] = globalEndpoint.AccessKey
+	}
 }
 
 // RestoreGlobals restores the state of global variables.
@@ -246,7 +264,24 @@ func (s sessionV8) restoreGlobals() {
 	json := s.Header.GlobalBoolFlags["json"]
 	noColor := s.Header.GlobalBoolFlags["noColor"]
 	insecure := s.Header.GlobalBoolFlags["insecure"]
-	setGlobals(quiet, debug, json, noColor, insecure)
+	noProgress := s.Header.GlobalBoolFlags["noProgress"]
+	setGlobals(quiet, debug, json, noColor, insecure, noProgress)
+
+	// Sessions of older versions have no transport flags.
+	var transport TransportOpts
+	transport.DialTimeout, _ = time.ParseDuration(s.Header.GlobalStringFlags["dial-timeout"])
+	transport.ResponseHeaderTimeout, _ = time.ParseDuration(s.Header.GlobalStringFlags["response-header-timeout"])
+	transport.TLSHandshakeTimeout, _ = time.ParseDuration(s.Header.GlobalStringFlags["tls-handshake-timeout"])
+	transport.MaxIdleConnsPerHost = s.Header.GlobalIntFlags["max-idle-conns-per-host"]
+	globalTransport = transport
+
+	if urlStr := s.Header.GlobalStringFlags["endpoint-url"]; urlStr != "" {
+		var secretKey string
+		if globalEndpoint != nil {
+			secretKey = globalEndpoint.SecretKey
+		}
+		globalEndpoint = &hostConfigV8{URL: urlStr, AccessKey: s.Header.GlobalStringFlags["access-key"], SecretKey: secretKey, API: "S3v4"}
+	}
 }
 
 // IsModified - returns if in memory session header has changed from
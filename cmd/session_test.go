@@ -40,6 +40,13 @@ func (s *TestSuite) TestSession(c *C) {
 	c.Assert(len(session.SessionID), Equals, 8)
 	_, e := os.Stat(session.DataFP.Name())
 	c.Assert(e, IsNil)
+	c.Assert(session.HasData(), Equals, false)
+
+	// Prepared sessions resume from their data, along with the
+	// objects copied out of order.
+	session.Header.Prepared = true
+	session.Header.LastCopied = "a"
+	session.Header.Copied = []int{3, 5}
 
 	err = session.Close()
 	c.Assert(err, IsNil)
@@ -48,6 +55,8 @@ func (s *TestSuite) TestSession(c *C) {
 	savedSession, err := loadSessionV8(session.SessionID)
 	c.Assert(err, IsNil)
 	c.Assert(session.SessionID, Equals, savedSession.SessionID)
+	c.Assert(savedSession.HasData(), Equals, true)
+	c.Assert(savedSession.Header.Copied, DeepEquals, []int{3, 5})
 
 	err = savedSession.Close()
 	c.Assert(err, IsNil)
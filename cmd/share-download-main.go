@@ -17,6 +17,9 @@
 package cmd
 
 import (
+	"net/http"
+	"net/url"
+	"strings"
 	"time"
 
 	"github.com/minio/cli"
@@ -33,6 +36,11 @@ var (
 			Name:  "recursive, r",
 			Usage: "Share all objects recursively.",
 		},
+		cli.StringSliceFlag{
+			Name:  "header",
+			Value: &cli.StringSlice{},
+			Usage: "Set a response header like Content-Disposition or a query parameter of the URL as KEY=VALUE.",
+		},
 		shareFlagExpire,
 	}
 )
@@ -64,6 +72,12 @@ EXAMPLES:
 
    4. Share all objects under this folder and all its sub-folders with 5 days expiry.
       $ mc share {{.Name}} --recursive --expire=120h s3/backup/
+
+   5. Share this object so that it is downloaded as 'backup.tar.gz' instead of being displayed.
+      $ mc share {{.Name}} --header "Content-Disposition=attachment; filename=backup.tar.gz" s3/backup/2006-Mar-1/backup.tar.gz
+
+   6. Share this object and serve it as plain text.
+      $ mc share {{.Name}} --header Content-Type=text/plain s3/backup/2006-Mar-1/backup.log
 `,
 }
 
@@ -91,14 +105,39 @@ func checkShareDownloadSyntax(ctx *cli.Context) {
 		fatalIf(errDummy().Trace(expiry.String()), "Expiry cannot be larger than 7 days.")
 	}
 
+	_, err := parseShareHeaders(ctx.StringSlice("header"))
+	fatalIf(err, "Unable to parse headers.")
+
 	for _, url := range ctx.Args() {
 		_, _, err := url2Stat(url)
 		fatalIf(err.Trace(url), "Unable to stat ‘"+url+"’.")
 	}
 }
 
+// parseShareHeaders - parses KEY=VALUE arguments into query parameters
+// of a shared URL. Standard headers like ‘Content-Disposition’ override
+// the header of the response, other keys are passed as is.
+func parseShareHeaders(args []string) (url.Values, *probe.Error) {
+	reqParams := make(url.Values)
+	for _, arg := range args {
+		i := strings.Index(arg, "=")
+		if i <= 0 {
+			return nil, errInvalidShareHeader(arg).Trace(arg)
+		}
+		key, value := arg[:i], arg[i+1:]
+		for _, header := range standardMetadataHeaders {
+			if http.CanonicalHeaderKey(key) == header {
+				key = "response-" + strings.ToLower(header)
+				break
+			}
+		}
+		reqParams.Add(key, value)
+	}
+	return reqParams, nil
+}
+
 // doShareURL share files from target.
-func doShareDownloadURL(targetURL string, isRecursive bool, expiry time.Duration) *probe.Error {
+func doShareDownloadURL(targetURL string, isRecursive bool, expiry time.Duration, reqParams url.Values) *probe.Error {
 	targetAlias, targetURLFull, _, err := expandAlias(targetURL)
 	if err != nil {
 		return err.Trace(targetURL)
@@ -118,7 +157,7 @@ func doShareDownloadURL(targetURL string, isRecursive bool, expiry time.Duration
 
 	// Generate share URL for each target.
 	incomplete := false
-	for content := range clnt.List(isRecursive, incomplete) {
+	for content := range clnt.List(globalContext, isRecursive, incomplete) {
 		if content.Err != nil {
 			return content.Err.Trace(clnt.GetURL().String())
 		}
@@ -133,7 +172,7 @@ func doShareDownloadURL(targetURL string, isRecursive bool, expiry time.Duration
 		}
 
 		// Generate share URL.
-		shareURL, err := newClnt.ShareDownload(expiry)
+		shareURL, err := newClnt.ShareDownload(expiry, reqParams)
 		if err != nil {
 			// add objectURL and expiry as part of the trace arguments.
 			return err.Trace(objectURL, "expiry="+expiry.String())
@@ -177,8 +216,11 @@ func mainShareDownload(ctx *cli.Context) {
 		fatalIf(probe.NewError(e), "Unable to parse expire=‘"+ctx.String("expire")+"’.")
 	}
 
+	// Validated by checkShareDownloadSyntax.
+	reqParams, _ := parseShareHeaders(ctx.StringSlice("header"))
+
 	for _, targetURL := range ctx.Args() {
-		err := doShareDownloadURL(targetURL, isRecursive, expiry)
+		err := doShareDownloadURL(targetURL, isRecursive, expiry, reqParams)
 		if err != nil {
 			switch err.ToGoError().(type) {
 			case APINotImplemented:
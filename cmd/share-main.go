@@ -21,7 +21,6 @@ import (
 	"path/filepath"
 
 	"github.com/minio/cli"
-	"github.com/minio/mc/pkg/console"
 	"github.com/minio/minio/pkg/probe"
 )
 
@@ -72,7 +71,7 @@ func migrateShare() {
 		// Old file exits.
 		e := os.Remove(oldShareFile)
 		fatalIf(probe.NewError(e), "Unable to delete old ‘"+oldShareFile+"’.")
-		console.Infof("Removed older version of share ‘%s’ file.\n", oldShareFile)
+		printInfo("Removed older version of share ‘" + oldShareFile + "’ file.")
 	}
 }
 
@@ -21,6 +21,7 @@ import (
 	"strings"
 	"time"
 
+	"github.com/dustin/go-humanize"
 	"github.com/minio/cli"
 	"github.com/minio/minio/pkg/probe"
 )
@@ -35,6 +36,14 @@ var (
 			Name:  "recursive, r",
 			Usage: "Recursively upload any object matching the prefix.",
 		},
+		cli.BoolFlag{
+			Name:  "put",
+			Usage: "Generate a presigned PUT URL instead of a form upload.",
+		},
+		cli.StringFlag{
+			Name:  "content-length-range",
+			Usage: "Allowed size of uploads as MIN-MAX or an exact SIZE, e.g. 1KiB-10MiB.",
+		},
 		shareFlagExpire,
 		shareFlagContentType,
 	}
@@ -67,6 +76,12 @@ EXAMPLES:
 
    4. Generate a curl command to allow upload access to any objects matching the key prefix 'backup/'. Command expires in 2 hours.
       $ mc share {{.Name}} --recursive --expire=2h s3/backup/2007-Mar-2/backup/
+
+   5. Generate a curl command to allow uploads of up to 10MiB to a folder.
+      $ mc share {{.Name}} --recursive --content-length-range=0-10MiB s3/backup/2007-Mar-2/
+
+   6. Generate a curl command with a presigned PUT URL to upload a single '.png' image of exactly 2MiB.
+      $ mc share {{.Name}} --put --content-type=image/png --content-length-range=2MiB s3/backup/2007-Mar-2/logo.png
 `,
 }
 
@@ -99,6 +114,21 @@ func checkShareUploadSyntax(ctx *cli.Context) {
 			"Expiry cannot be larger than 7 days.")
 	}
 
+	// Validate size of uploads.
+	minSize, maxSize, err := parseContentLengthRange(ctx.String("content-length-range"))
+	fatalIf(err, "Unable to parse content-length-range=‘"+ctx.String("content-length-range")+"’.")
+
+	if ctx.Bool("put") {
+		if isRecursive {
+			fatalIf(errInvalidArgument().Trace(),
+				"Presigned PUT URLs upload a single object, --recursive is not supported.")
+		}
+		if minSize != maxSize {
+			fatalIf(errInvalidArgument().Trace(ctx.String("content-length-range")),
+				"Presigned PUT URLs can only restrict uploads to an exact size.")
+		}
+	}
+
 	for _, targetURL := range ctx.Args() {
 		url := newClientURL(targetURL)
 		if strings.HasSuffix(targetURL, string(url.Separator)) && !isRecursive {
@@ -108,6 +138,27 @@ func checkShareUploadSyntax(ctx *cli.Context) {
 	}
 }
 
+// parseContentLengthRange - parses MIN-MAX or an exact SIZE, both
+// zero if no range is given.
+func parseContentLengthRange(lengthRange string) (minSize, maxSize int64, err *probe.Error) {
+	if lengthRange == "" {
+		return 0, 0, nil
+	}
+	sizes := strings.SplitN(lengthRange, "-", 2)
+	if len(sizes) == 1 {
+		sizes = append(sizes, sizes[0])
+	}
+	min, e := humanize.ParseBytes(sizes[0])
+	if e != nil {
+		return 0, 0, errInvalidContentLengthRange(lengthRange).Trace(lengthRange)
+	}
+	max, e := humanize.ParseBytes(sizes[1])
+	if e != nil || max == 0 || min > max {
+		return 0, 0, errInvalidContentLengthRange(lengthRange).Trace(lengthRange)
+	}
+	return int64(min), int64(max), nil
+}
+
 // makeCurlCmd constructs curl command-line.
 func makeCurlCmd(key string, isRecursive bool, uploadInfo map[string]string) string {
 	URL := newClientURL(key)
@@ -134,6 +185,17 @@ func makeCurlCmd(key string, isRecursive bool, uploadInfo map[string]string) str
 	return curlCommand
 }
 
+// makeCurlPutCmd constructs curl command-line for a presigned PUT URL.
+func makeCurlPutCmd(shareURL string, contentType string) string {
+	curlCommand := "curl -X PUT "
+	if contentType != "" {
+		curlCommand += fmt.Sprintf("-H \"Content-Type: %s\" ", contentType)
+	}
+	curlCommand += "--upload-file <FILE> " // File to upload.
+	curlCommand += "'" + shareURL + "'"
+	return curlCommand
+}
+
 // save shared URL to disk.
 func saveSharedURL(objectURL string, shareURL string, expiry time.Duration, contentType string) *probe.Error {
 	// Load previously saved upload-shares.
@@ -150,33 +212,40 @@ func saveSharedURL(objectURL string, shareURL string, expiry time.Duration, cont
 }
 
 // doShareUploadURL uploads files to the target.
-func doShareUploadURL(objectURL string, isRecursive bool, expiry time.Duration, contentType string) *probe.Error {
+func doShareUploadURL(objectURL string, isPut bool, opts ShareUploadOpts) *probe.Error {
 	clnt, err := newClient(objectURL)
 	if err != nil {
 		return err.Trace(objectURL)
 	}
 
-	// Generate pre-signed access info.
-	uploadInfo, err := clnt.ShareUpload(isRecursive, expiry, contentType)
-	if err != nil {
-		return err.Trace(objectURL, "expiry="+expiry.String(), "contentType="+contentType)
+	// Generate curl command from pre-signed access info.
+	var curlCmd string
+	if isPut {
+		shareURL, err := clnt.ShareUploadURL(opts)
+		if err != nil {
+			return err.Trace(objectURL, "expiry="+opts.Expires.String(), "contentType="+opts.ContentType)
+		}
+		curlCmd = makeCurlPutCmd(shareURL, opts.ContentType)
+	} else {
+		uploadInfo, err := clnt.ShareUpload(opts)
+		if err != nil {
+			return err.Trace(objectURL, "expiry="+opts.Expires.String(), "contentType="+opts.ContentType)
+		}
+		curlCmd = makeCurlCmd(clnt.GetURL().String(), opts.Recursive, uploadInfo)
 	}
 
 	// Get the new expanded url.
 	objectURL = clnt.GetURL().String()
 
-	// Generate curl command.
-	curlCmd := makeCurlCmd(objectURL, isRecursive, uploadInfo)
-
 	printMsg(shareMesssage{
 		ObjectURL:   objectURL,
 		ShareURL:    curlCmd,
-		TimeLeft:    expiry,
-		ContentType: contentType,
+		TimeLeft:    opts.Expires,
+		ContentType: opts.ContentType,
 	})
 
 	// save shared URL to disk.
-	return saveSharedURL(objectURL, curlCmd, expiry, contentType)
+	return saveSharedURL(objectURL, curlCmd, opts.Expires, opts.ContentType)
 }
 
 // main for share upload command.
@@ -203,9 +272,18 @@ func mainShareUpload(ctx *cli.Context) {
 		expiry, e = time.ParseDuration(expireArg)
 		fatalIf(probe.NewError(e), "Unable to parse expire=‘"+expireArg+"’.")
 	}
+	// Validated by checkShareUploadSyntax.
+	minSize, maxSize, _ := parseContentLengthRange(ctx.String("content-length-range"))
+	opts := ShareUploadOpts{
+		Expires:     expiry,
+		Recursive:   isRecursive,
+		ContentType: contentType,
+		MinSize:     minSize,
+		MaxSize:     maxSize,
+	}
 
 	for _, targetURL := range ctx.Args() {
-		err := doShareUploadURL(targetURL, isRecursive, expiry, contentType)
+		err := doShareUploadURL(targetURL, ctx.Bool("put"), opts)
 		if err != nil {
 			switch err.ToGoError().(type) {
 			case APINotImplemented:
@@ -178,20 +178,20 @@ func initShareConfig() {
 	if !isShareDirExists() {
 		fatalIf(createShareDir().Trace(mustGetShareDir()),
 			"Failed to create share ‘"+mustGetShareDir()+"’ folder.")
-		console.Infof("Successfully created ‘%s’.\n", mustGetShareDir())
+		printInfo("Successfully created ‘" + mustGetShareDir() + "’.")
 	}
 
 	// Uploads share file.
 	if !isShareUploadsExists() {
 		fatalIf(initShareUploadsFile().Trace(getShareUploadsFile()),
 			"Failed to initialize share uploads ‘"+getShareUploadsFile()+"’ file.")
-		console.Infof("Initialized share uploads ‘%s’ file.\n", getShareUploadsFile())
+		printInfo("Initialized share uploads ‘" + getShareUploadsFile() + "’ file.")
 	}
 
 	// Downloads share file.
 	if !isShareDownloadsExists() {
 		fatalIf(initShareDownloadsFile().Trace(getShareDownloadsFile()),
 			"Failed to initialize share downloads ‘"+getShareDownloadsFile()+"’ file.")
-		console.Infof("Initialized share downloads ‘%s’ file.\n", getShareDownloadsFile())
+		printInfo("Initialized share downloads ‘" + getShareDownloadsFile() + "’ file.")
 	}
 }
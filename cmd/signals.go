@@ -17,11 +17,29 @@
 package cmd
 
 import (
+	"context"
 	"os"
 	"os/signal"
+	"time"
 )
 
-// signalTrap traps the registered signals and notifies the caller.
+// globalContext - context commands pass to their clients, cancelled
+// once a trapped signal is received, so that requests still running
+// are aborted instead of keeping the command from shutting down.
+var globalContext, globalCancel = context.WithCancel(context.Background())
+
+// cleanupTimeout - time requests undoing changes of an interrupted
+// command may take.
+const cleanupTimeout = 10 * time.Second
+
+// newCleanupContext - context of requests undoing changes of a command,
+// which are not cancelled once the command is interrupted.
+func newCleanupContext() (context.Context, context.CancelFunc) {
+	return context.WithTimeout(context.Background(), cleanupTimeout)
+}
+
+// signalTrap traps the registered signals and notifies the caller,
+// requests still running are cancelled.
 func signalTrap(sig ...os.Signal) <-chan bool {
 	// channel to notify the caller.
 	trapCh := make(chan bool, 1)
@@ -41,6 +59,8 @@ func signalTrap(sig ...os.Signal) <-chan bool {
 		// Once signal has been received stop signal Notify handler.
 		signal.Stop(sigCh)
 
+		globalCancel()
+
 		// Notify the caller.
 		trapCh <- true
 	}(trapCh)
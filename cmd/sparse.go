@@ -0,0 +1,184 @@
+/*
+ * Minio Client (C) 2016 Minio, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"bytes"
+	"io"
+	"os"
+	"strconv"
+
+	"github.com/minio/minio/pkg/probe"
+)
+
+// Metadata of objects uploaded from sparse files with ‘--sparse’, holds
+// the size of the file including the trailing zero blocks which were
+// not uploaded.
+const sparseSizeMetadataKey = "X-Amz-Meta-Mc-Sparse-Size"
+
+// Size of the blocks checked for zeros, blocks entirely of zeros are
+// not written to files but left as holes.
+const sparseBlockSize = 64 * 1024
+
+// zeroBlock - a block of zeros to compare blocks against.
+var zeroBlock = make([]byte, sparseBlockSize)
+
+// sparseWriter - writes to a file, leaving holes for blocks of zeros.
+// Blocks are aligned to the start of the file, so that holes are
+// aligned to blocks of the filesystem. Data is buffered until its
+// block is complete.
+type sparseWriter struct {
+	file *os.File
+	// Offset of the buffered block, and of the file after the last
+	// write which was not skipped.
+	offset     int64
+	fileOffset int64
+	block      []byte
+}
+
+// newSparseWriter - writes to the file from its current offset.
+func newSparseWriter(file *os.File) (*sparseWriter, error) {
+	offset, e := file.Seek(0, os.SEEK_CUR)
+	if e != nil {
+		return nil, e
+	}
+	return &sparseWriter{
+		file:       file,
+		offset:     offset,
+		fileOffset: offset,
+		block:      make([]byte, 0, sparseBlockSize),
+	}, nil
+}
+
+// Write - buffers p, writing complete blocks which are not all zeros.
+func (w *sparseWriter) Write(p []byte) (int, error) {
+	written := 0
+	for len(p) > 0 {
+		// Up to the end of the current block.
+		n := int(sparseBlockSize - (w.offset+int64(len(w.block)))%sparseBlockSize)
+		if n > len(p) {
+			n = len(p)
+		}
+		w.block = append(w.block, p[:n]...)
+		if (w.offset+int64(len(w.block)))%sparseBlockSize == 0 {
+			if e := w.flush(); e != nil {
+				return written, e
+			}
+		}
+		written += n
+		p = p[n:]
+	}
+	return written, nil
+}
+
+// flush - writes the buffered block unless it is all zeros.
+func (w *sparseWriter) flush() error {
+	if !bytes.Equal(w.block, zeroBlock[:len(w.block)]) {
+		if w.fileOffset != w.offset {
+			if _, e := w.file.Seek(w.offset, os.SEEK_SET); e != nil {
+				return e
+			}
+			w.fileOffset = w.offset
+		}
+		n, e := w.file.Write(w.block)
+		w.fileOffset += int64(n)
+		if e != nil {
+			return e
+		}
+	}
+	w.offset += int64(len(w.block))
+	w.block = w.block[:0]
+	return nil
+}
+
+// Close - writes the last block, extends the file over trailing holes
+// and closes it.
+func (w *sparseWriter) Close() error {
+	e := w.flush()
+	if e == nil && w.fileOffset != w.offset {
+		e = w.file.Truncate(w.offset)
+	}
+	if e != nil {
+		w.file.Close()
+		return e
+	}
+	return w.file.Close()
+}
+
+// trimSparseTail - a reader of the data of a file without its trailing
+// zero blocks, along with its size and the number of bytes trimmed.
+// Readers which are no files are not trimmed.
+func trimSparseTail(reader io.Reader, size int64) (io.Reader, int64, int64, *probe.Error) {
+	file, ok := reader.(*os.File)
+	if !ok || size < sparseBlockSize {
+		return reader, size, 0, nil
+	}
+	offset, e := file.Seek(0, os.SEEK_CUR)
+	if e != nil {
+		return nil, 0, 0, probe.NewError(e)
+	}
+	// Blocks are checked backwards from the end of the data.
+	end := offset + size
+	block := make([]byte, sparseBlockSize)
+	for end-offset >= sparseBlockSize {
+		if _, e = file.ReadAt(block, end-sparseBlockSize); e != nil {
+			return nil, 0, 0, probe.NewError(e)
+		}
+		if !bytes.Equal(block, zeroBlock) {
+			break
+		}
+		end -= sparseBlockSize
+	}
+	trimmed := offset + size - end
+	if trimmed == 0 {
+		return reader, size, 0, nil
+	}
+	return io.NewSectionReader(file, offset, end-offset), end - offset, trimmed, nil
+}
+
+// restoreSparseTail - extends a file downloaded with ‘--sparse’ from
+// an object of the given size over the trailing zero blocks which were
+// not uploaded, they are left as holes. Only sizes of the object with
+// whole blocks trimmed are accepted.
+func restoreSparseTail(reader io.Reader, size int64, targetURL ClientURL) *probe.Error {
+	r, ok := reader.(metadataReader)
+	if !ok || targetURL.Type != FileSystem {
+		return nil
+	}
+	value, ok := r.Metadata()[sparseSizeMetadataKey]
+	if !ok {
+		return nil
+	}
+	sparseSize, e := strconv.ParseInt(value, 10, 64)
+	if e != nil {
+		return probe.NewError(e).Trace(targetURL.Path, value)
+	}
+	if trimmed := sparseSize - size; trimmed <= 0 || trimmed%sparseBlockSize != 0 {
+		return errInvalidSparseSize(value, size).Trace(targetURL.Path)
+	}
+	st, e := os.Stat(targetURL.Path)
+	if e != nil {
+		return probe.NewError(e).Trace(targetURL.Path)
+	}
+	if st.Size() != size {
+		return errInvalidSparseSize(value, st.Size()).Trace(targetURL.Path)
+	}
+	if e = os.Truncate(targetURL.Path, sparseSize); e != nil {
+		return probe.NewError(e).Trace(targetURL.Path)
+	}
+	return nil
+}
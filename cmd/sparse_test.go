@@ -0,0 +1,142 @@
+/*
+ * Minio Client (C) 2016 Minio, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strconv"
+
+	. "gopkg.in/check.v1"
+)
+
+func (s *TestSuite) TestSparseWriter(c *C) {
+	root, e := ioutil.TempDir(os.TempDir(), "sparse-")
+	c.Assert(e, IsNil)
+	defer os.RemoveAll(root)
+
+	// Data, a block of zeros, data and trailing zero blocks, written
+	// in pieces not aligned to blocks.
+	data := bytes.Repeat([]byte("a"), sparseBlockSize/2)
+	data = append(data, make([]byte, 2*sparseBlockSize)...)
+	data = append(data, bytes.Repeat([]byte("b"), 100)...)
+	data = append(data, make([]byte, 3*sparseBlockSize)...)
+
+	fpath := filepath.Join(root, "file")
+	file, e := os.Create(fpath)
+	c.Assert(e, IsNil)
+	w, e := newSparseWriter(file)
+	c.Assert(e, IsNil)
+	for p := data; len(p) > 0; {
+		n := 1000
+		if n > len(p) {
+			n = len(p)
+		}
+		written, e := w.Write(p[:n])
+		c.Assert(e, IsNil)
+		c.Assert(written, Equals, n)
+		p = p[n:]
+	}
+	c.Assert(w.Close(), IsNil)
+	written, e := ioutil.ReadFile(fpath)
+	c.Assert(e, IsNil)
+	c.Assert(bytes.Equal(written, data), Equals, true)
+
+	// Trailing zero blocks are trimmed.
+	file, e = os.Open(fpath)
+	c.Assert(e, IsNil)
+	defer file.Close()
+	reader, size, trimmed, err := trimSparseTail(file, int64(len(data)))
+	c.Assert(err, IsNil)
+	c.Assert(size+trimmed, Equals, int64(len(data)))
+	c.Assert(trimmed, Equals, int64(3*sparseBlockSize))
+	trimmedData, e := ioutil.ReadAll(reader)
+	c.Assert(e, IsNil)
+	c.Assert(bytes.Equal(trimmedData, data[:size]), Equals, true)
+}
+
+// Test files downloaded with ‘--sparse’ are extended by the metadata
+// returned along with the data, to sizes of whole trimmed blocks only.
+func (s *TestSuite) TestRestoreSparseTail(c *C) {
+	root, e := ioutil.TempDir(os.TempDir(), "sparse-")
+	c.Assert(e, IsNil)
+	defer os.RemoveAll(root)
+
+	data := []byte("Hello, World")
+	sparseSize := strconv.Itoa(len(data) + 2*sparseBlockSize)
+	heads := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != "GET" {
+			heads++
+			w.WriteHeader(http.StatusMethodNotAllowed)
+			return
+		}
+		if _, ok := r.URL.Query()["location"]; ok {
+			w.Write([]byte("<LocationConstraint></LocationConstraint>"))
+			return
+		}
+		w.Header().Set("X-Amz-Meta-Mc-Sparse-Size", sparseSize)
+		w.Write(data)
+	}))
+	defer server.Close()
+
+	conf := new(Config)
+	conf.HostURL = server.URL + "/bucket/object"
+	conf.AccessKey = "WLGDGYAQYIGI833EV05A"
+	conf.SecretKey = "BYvgJM101sHngl2uzjXS/OBF/aMxAN06JrJ3qJlF"
+	conf.Signature = "S3v4"
+	s3c, err := s3New(conf)
+	c.Assert(err, IsNil)
+
+	fpath := filepath.Join(root, "file")
+	targetURL := newClientURL(fpath)
+	download := func() io.Reader {
+		reader, err := s3c.Get(context.Background(), GetOpts{Metadata: true})
+		c.Assert(err, IsNil)
+		written, e := ioutil.ReadAll(reader)
+		c.Assert(e, IsNil)
+		c.Assert(ioutil.WriteFile(fpath, written, 0600), IsNil)
+		return reader
+	}
+
+	c.Assert(restoreSparseTail(download(), int64(len(data)), *targetURL), IsNil)
+	st, e := os.Stat(fpath)
+	c.Assert(e, IsNil)
+	c.Assert(st.Size(), Equals, int64(len(data)+2*sparseBlockSize))
+	c.Assert(heads, Equals, 0)
+
+	// Sizes not adding whole blocks to the object are rejected.
+	for _, size := range []string{"1099511627776", strconv.Itoa(len(data)), strconv.Itoa(len(data) + 1), "-1"} {
+		sparseSize = size
+		c.Assert(restoreSparseTail(download(), int64(len(data)), *targetURL), NotNil)
+		st, e = os.Stat(fpath)
+		c.Assert(e, IsNil)
+		c.Assert(st.Size(), Equals, int64(len(data)))
+	}
+
+	// Readers without metadata are not restored.
+	c.Assert(restoreSparseTail(bytes.NewReader(data), int64(len(data)), *targetURL), IsNil)
+	st, e = os.Stat(fpath)
+	c.Assert(e, IsNil)
+	c.Assert(st.Size(), Equals, int64(len(data)))
+}
@@ -0,0 +1,217 @@
+/*
+ * Minio Client (C) 2016 Minio, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"path/filepath"
+	"strings"
+
+	"github.com/minio/cli"
+	"github.com/minio/minio/pkg/probe"
+)
+
+var (
+	sqlFlags = []cli.Flag{
+		cli.BoolFlag{
+			Name:  "help, h",
+			Usage: "Help of sql.",
+		},
+		cli.StringFlag{
+			Name:  "query, e",
+			Usage: "SQL expression to run, e.g. \"select * from S3Object\".",
+		},
+		cli.StringFlag{
+			Name:  "input-format",
+			Usage: "Format of objects, one of ‘csv’, ‘json’ or ‘parquet’. Guessed from the object name by default.",
+		},
+		cli.StringFlag{
+			Name:  "compression",
+			Usage: "Compression of objects, one of ‘none’, ‘gzip’ or ‘bzip2’. Guessed from the object name by default.",
+		},
+		cli.StringFlag{
+			Name:  "csv-header",
+			Value: "USE",
+			Usage: "Header line of CSV objects, one of ‘USE’, ‘IGNORE’ or ‘NONE’.",
+		},
+		cli.StringFlag{
+			Name:  "csv-delimiter",
+			Value: ",",
+			Usage: "Field delimiter of CSV objects and results.",
+		},
+		cli.StringFlag{
+			Name:  "json-type",
+			Value: "LINES",
+			Usage: "Layout of JSON objects, one of ‘LINES’ or ‘DOCUMENT’.",
+		},
+		cli.StringFlag{
+			Name:  "output-format",
+			Usage: "Format of results, one of ‘csv’ or ‘json’. Defaults to JSON for JSON objects and CSV otherwise.",
+		},
+		cli.StringFlag{
+			Name:  "encrypt-key",
+			Usage: "Decrypt objects with customer provided keys, as comma separated list of ‘ALIAS/PREFIX=KEY’.",
+		},
+	}
+)
+
+// Run SQL queries on objects.
+var sqlCmd = cli.Command{
+	Name:   "sql",
+	Usage:  "Run SQL queries on objects.",
+	Action: mainSQL,
+	Flags:  append(sqlFlags, globalFlags...),
+	CustomHelpTemplate: `NAME:
+   mc {{.Name}} - {{.Usage}}
+
+USAGE:
+   mc {{.Name}} --query "EXPRESSION" [FLAGS] TARGET [TARGET...]
+
+FLAGS:
+  {{range .Flags}}{{.}}
+  {{end}}
+EXAMPLES:
+   1. Select the first ten records of a CSV object.
+      $ mc {{.Name}} --query "select * from S3Object limit 10" s3/mybucket/people.csv
+
+   2. Count the records of a gzip compressed CSV object without a header line.
+      $ mc {{.Name}} --csv-header NONE --query "select count(*) from S3Object" s3/mybucket/people.csv.gz
+
+   3. Select fields of JSON lines as CSV.
+      $ mc {{.Name}} --output-format csv --query "select s.name, s.age from S3Object s" s3/mybucket/people.json
+
+   4. Query a Parquet object.
+      $ mc {{.Name}} --input-format parquet --query "select * from S3Object s where s.age > 30" s3/mybucket/people.parquet
+
+`,
+}
+
+// checkSQLSyntax - validate all the passed arguments
+func checkSQLSyntax(ctx *cli.Context) {
+	if !ctx.Args().Present() || ctx.String("query") == "" {
+		cli.ShowCommandHelpAndExit(ctx, "sql", 1) // last argument is exit code
+	}
+	switch strings.ToLower(ctx.String("input-format")) {
+	case "", "csv", "json", "parquet":
+	default:
+		fatalIf(errInvalidArgument().Trace(ctx.String("input-format")), "Unknown input format ‘"+ctx.String("input-format")+"’.")
+	}
+	switch strings.ToLower(ctx.String("compression")) {
+	case "", "none", "gzip", "bzip2":
+	default:
+		fatalIf(errInvalidArgument().Trace(ctx.String("compression")), "Unknown compression ‘"+ctx.String("compression")+"’.")
+	}
+	switch strings.ToLower(ctx.String("output-format")) {
+	case "", "csv", "json":
+	default:
+		fatalIf(errInvalidArgument().Trace(ctx.String("output-format")), "Unknown output format ‘"+ctx.String("output-format")+"’.")
+	}
+}
+
+// guessSelectFormat - guesses format and compression of an object from
+// its extensions, e.g. ‘people.csv.gz’ is gzip compressed CSV.
+func guessSelectFormat(object string) (format, compression string) {
+	format, compression = "csv", "none"
+	ext := strings.ToLower(filepath.Ext(object))
+	switch ext {
+	case ".gz":
+		compression = "gzip"
+	case ".bz2":
+		compression = "bzip2"
+	}
+	if compression != "none" {
+		ext = strings.ToLower(filepath.Ext(strings.TrimSuffix(object, filepath.Ext(object))))
+	}
+	switch ext {
+	case ".json":
+		format = "json"
+	case ".parquet":
+		format = "parquet"
+	}
+	return format, compression
+}
+
+// selectOptsFromContext - builds serialization options of a query on
+// the object from flags.
+func selectOptsFromContext(ctx *cli.Context, object string) SelectObjectOpts {
+	format, compression := guessSelectFormat(object)
+	if ctx.String("input-format") != "" {
+		format = strings.ToLower(ctx.String("input-format"))
+	}
+	if ctx.String("compression") != "" {
+		compression = strings.ToLower(ctx.String("compression"))
+	}
+
+	opts := SelectObjectOpts{}
+	opts.Input.CompressionType = strings.ToUpper(compression)
+	switch format {
+	case "json":
+		opts.Input.JSON = &selectJSONInput{Type: strings.ToUpper(ctx.String("json-type"))}
+	case "parquet":
+		// Parquet objects carry their own compression.
+		opts.Input.CompressionType = ""
+		opts.Input.Parquet = &struct{}{}
+	default:
+		opts.Input.CSV = &selectCSVInput{
+			FileHeaderInfo: strings.ToUpper(ctx.String("csv-header")),
+			FieldDelimiter: ctx.String("csv-delimiter"),
+		}
+	}
+
+	outputFormat := strings.ToLower(ctx.String("output-format"))
+	if outputFormat == "" && format == "json" {
+		outputFormat = "json"
+	}
+	if outputFormat == "json" {
+		opts.Output.JSON = &selectJSONOutput{RecordDelimiter: "\n"}
+	} else {
+		opts.Output.CSV = &selectCSVOutput{RecordDelimiter: "\n", FieldDelimiter: ctx.String("csv-delimiter")}
+	}
+	return opts
+}
+
+// sqlURL - runs the query on the object and writes results to stdout.
+func sqlURL(targetURL, query string, sse EncryptOpts, opts SelectObjectOpts) *probe.Error {
+	clnt, err := newClient(targetURL)
+	if err != nil {
+		return err.Trace(targetURL)
+	}
+	reader, err := clnt.SelectObjectContent(query, sse, opts)
+	if err != nil {
+		return err.Trace(targetURL)
+	}
+	defer reader.Close()
+	return catOut(reader).Trace(targetURL)
+}
+
+// mainSQL is the main entry point for sql command.
+func mainSQL(ctx *cli.Context) {
+	// Set global flags from context.
+	setGlobalsFromContext(ctx)
+
+	// check 'sql' cli arguments.
+	checkSQLSyntax(ctx)
+
+	encKeys, err := parseEncryptKeys(ctx.String("encrypt-key"), "")
+	fatalIf(err, "Unable to parse encryption keys.")
+
+	query := ctx.String("query")
+	for _, targetURL := range ctx.Args() {
+		opts := selectOptsFromContext(ctx, targetURL)
+		err = sqlURL(targetURL, query, getEncryptOpts(encKeys, targetURL), opts)
+		fatalIf(err.Trace(targetURL), "Unable to run query on ‘"+targetURL+"’.")
+	}
+}
@@ -0,0 +1,123 @@
+/*
+ * Minio Client (C) 2016 Minio, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"path/filepath"
+	"strings"
+
+	"github.com/minio/minio/pkg/probe"
+)
+
+// stagedObject - object uploaded under the staging prefix of its bucket,
+// to be copied to its final name once all objects were uploaded.
+type stagedObject struct {
+	Alias     string
+	StagedURL string
+	FinalURL  string
+	Size      int64
+}
+
+// normalizeStagingPrefix - staging prefixes are folders of the bucket.
+func normalizeStagingPrefix(prefix string) string {
+	prefix = strings.TrimPrefix(prefix, "/")
+	if prefix != "" && !strings.HasSuffix(prefix, "/") {
+		prefix = prefix + "/"
+	}
+	return prefix
+}
+
+// stagingURL - URL of the object or folder of the client staged under
+// the prefix of its bucket.
+func stagingURL(clnt Client, prefix string) (string, *probe.Error) {
+	clntURL := clnt.GetURL()
+	urlStr := clntURL.String()
+	s3Clnt, ok := clnt.(*s3Client)
+	if !ok {
+		return "", errStagingUnsupported(urlStr).Trace(urlStr)
+	}
+	bucket, object := s3Clnt.url2BucketAndObject()
+	if bucket == "" {
+		return "", probe.NewError(BucketNameEmpty{}).Trace(urlStr)
+	}
+	bucketURL := strings.TrimSuffix(urlStr, object)
+	if !strings.HasSuffix(bucketURL, string(clntURL.Separator)) {
+		bucketURL = bucketURL + string(clntURL.Separator)
+	}
+	return bucketURL + normalizeStagingPrefix(prefix) + object, nil
+}
+
+// stageURLs - copies to the object staged for the target instead.
+func stageURLs(cpURLs URLs, prefix string) URLs {
+	targetURL := cpURLs.TargetContent.URL.String()
+	clnt, err := newClientFromAlias(cpURLs.TargetAlias, targetURL)
+	if err != nil {
+		return cpURLs.WithError(err.Trace(targetURL))
+	}
+	stagedURL, err := stagingURL(clnt, prefix)
+	if err != nil {
+		return cpURLs.WithError(err.Trace(targetURL))
+	}
+	targetContent := *cpURLs.TargetContent
+	targetContent.URL = *newClientURL(stagedURL)
+	cpURLs.TargetContent = &targetContent
+	return cpURLs
+}
+
+// commitStaged - copies a staged object to its final name on the
+// server and removes the staged object. Encryption keys are looked up
+// for both names.
+func commitStaged(object stagedObject, encKeys map[string]EncryptOpts) *probe.Error {
+	stagedURL := newClientURL(object.StagedURL)
+	finalURL := newClientURL(object.FinalURL)
+	opts := CopyOpts{
+		SrcSSE: getEncryptOpts(encKeys, filepath.ToSlash(filepath.Join(object.Alias, stagedURL.Path))),
+		TgtSSE: getEncryptOpts(encKeys, filepath.ToSlash(filepath.Join(object.Alias, finalURL.Path))),
+	}
+	// Do not include alias inside path for ObjStore -> ObjStore.
+	if err := copySourceStreamFromAlias(globalContext, object.Alias, object.FinalURL, stagedURL.Path, object.Size, nil, opts); err != nil {
+		return err.Trace(object.StagedURL, object.FinalURL)
+	}
+	clnt, err := newClientFromAlias(object.Alias, object.StagedURL)
+	if err != nil {
+		return err.Trace(object.StagedURL)
+	}
+	return clnt.Remove(globalContext, false)
+}
+
+// commitStagedObjects - commits all staged objects, objects failing to
+// commit are reported and left staged.
+func commitStagedObjects(objects []stagedObject, encKeys map[string]EncryptOpts) {
+	for _, object := range objects {
+		if err := commitStaged(object, encKeys); err != nil {
+			errorIf(err.Trace(object.StagedURL), "Failed to commit ‘"+object.StagedURL+"’.")
+		}
+	}
+}
+
+// checkStagingSyntax - objects can only be staged on object storage.
+func checkStagingSyntax(tgtURL, prefix string) {
+	if prefix == "" {
+		return
+	}
+	if _, _, hostCfg := mustExpandAlias(tgtURL); hostCfg == nil {
+		fatalIf(errStagingUnsupported(tgtURL).Trace(tgtURL), "Unable to stage objects.")
+	}
+	if normalizeStagingPrefix(prefix) == "" {
+		fatalIf(errInvalidArgument().Trace(prefix), "Staging prefix cannot be empty.")
+	}
+}
@@ -0,0 +1,59 @@
+/*
+ * Minio Client (C) 2016 Minio, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"io/ioutil"
+	"os"
+
+	. "gopkg.in/check.v1"
+)
+
+// Test URLs of objects staged under the prefix of their bucket.
+func (s *TestSuite) TestStagingURL(c *C) {
+	testCases := []struct {
+		hostURL   string
+		prefix    string
+		stagedURL string
+	}{
+		{"http://localhost:9000/bucket/dataset/part-1", ".staging", "http://localhost:9000/bucket/.staging/dataset/part-1"},
+		{"http://localhost:9000/bucket/dataset/", "/.staging/", "http://localhost:9000/bucket/.staging/dataset/"},
+		{"http://localhost:9000/bucket", "tmp/upload-1/", "http://localhost:9000/bucket/tmp/upload-1/"},
+		{"https://bucket.s3.amazonaws.com/dataset/part-1", ".staging/", "https://bucket.s3.amazonaws.com/.staging/dataset/part-1"},
+	}
+	for _, testCase := range testCases {
+		conf := new(Config)
+		conf.HostURL = testCase.hostURL
+		conf.AccessKey = "WLGDGYAQYIGI833EV05A"
+		conf.SecretKey = "BYvgJM101sHngl2uzjXS/OBF/aMxAN06JrJ3qJlF"
+		conf.Signature = "S3v4"
+		s3c, err := s3New(conf)
+		c.Assert(err, IsNil)
+		stagedURL, err := stagingURL(s3c, testCase.prefix)
+		c.Assert(err, IsNil)
+		c.Assert(stagedURL, Equals, testCase.stagedURL)
+	}
+
+	// Files can't be staged.
+	root, e := ioutil.TempDir(os.TempDir(), "staging-")
+	c.Assert(e, IsNil)
+	defer os.RemoveAll(root)
+	fsClnt, err := fsNew(root)
+	c.Assert(err, IsNil)
+	_, err = stagingURL(fsClnt, ".staging")
+	c.Assert(err, NotNil)
+}
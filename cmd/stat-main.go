@@ -0,0 +1,235 @@
+/*
+ * Minio Client (C) 2016 Minio, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/dustin/go-humanize"
+	"github.com/fatih/color"
+	"github.com/minio/cli"
+	"github.com/minio/mc/pkg/console"
+	"github.com/minio/minio/pkg/probe"
+)
+
+// stat specific flags.
+var (
+	statFlags = []cli.Flag{
+		cli.BoolFlag{
+			Name:  "help, h",
+			Usage: "Help of stat.",
+		},
+		cli.StringFlag{
+			Name:  "version-id",
+			Usage: "Display metadata of a specific version of the object.",
+		},
+		cli.StringFlag{
+			Name:  "encrypt-key",
+			Usage: "Decrypt objects with customer provided keys, as comma separated list of ‘ALIAS/PREFIX=KEY’.",
+		},
+	}
+)
+
+// display metadata of objects.
+var statCmd = cli.Command{
+	Name:   "stat",
+	Usage:  "Display metadata of objects and files.",
+	Action: mainStat,
+	Flags:  append(statFlags, globalFlags...),
+	CustomHelpTemplate: `NAME:
+   mc {{.Name}} - {{.Usage}}
+
+USAGE:
+   mc {{.Name}} [FLAGS] TARGET [TARGET ...]
+
+FLAGS:
+  {{range .Flags}}{{.}}
+  {{end}}
+EXAMPLES:
+   1. Display metadata of an object on Amazon S3 cloud storage.
+      $ mc {{.Name}} s3/mybucket/photos/2017/kitten.jpg
+
+   2. Display metadata of a previous version of an object in a versioned bucket.
+      $ mc {{.Name}} --version-id "3/L4kqtJlcpXroDTDmJ+rmSpXd3dIbrHY" s3/mybucket/config.json
+
+   3. Display metadata of an object encrypted with a customer provided key.
+      $ mc {{.Name}} --encrypt-key "s3/secret=32byteslongsecretkeymustbegiven1" s3/secret/passwords.txt
+
+   4. Display metadata of a local file as JSON.
+      $ mc --json {{.Name}} /var/log/syslog
+`,
+}
+
+// checkStatSyntax - validate all the passed arguments
+func checkStatSyntax(ctx *cli.Context) {
+	if !ctx.Args().Present() {
+		cli.ShowCommandHelpAndExit(ctx, "stat", 1) // last argument is exit code
+	}
+	for _, arg := range ctx.Args() {
+		if strings.TrimSpace(arg) == "" {
+			fatalIf(errInvalidArgument().Trace(ctx.Args()...), "Unable to validate empty argument.")
+		}
+	}
+	if ctx.String("version-id") != "" && len(ctx.Args()) != 1 {
+		fatalIf(errInvalidArgument().Trace(ctx.Args()...), "--version-id can only be used with a single object.")
+	}
+}
+
+// statMessage container for metadata of an object, folder or bucket.
+type statMessage struct {
+	Status       string            `json:"status"`
+	Key          string            `json:"name"`
+	Type         string            `json:"type"`
+	Size         int64             `json:"size"`
+	LastModified time.Time         `json:"lastModified"`
+	ETag         string            `json:"etag,omitempty"`
+	ContentType  string            `json:"contentType,omitempty"`
+	StorageClass string            `json:"storageClass,omitempty"`
+	VersionID    string            `json:"versionId,omitempty"`
+	Encryption   string            `json:"encryption,omitempty"`
+	KMSKeyID     string            `json:"kmsKeyId,omitempty"`
+	Metadata     map[string]string `json:"metadata,omitempty"`
+}
+
+// String colorized stat message.
+func (s statMessage) String() string {
+	var lines []string
+	field := func(name, value string) {
+		if value != "" {
+			lines = append(lines, console.Colorize("Field", fmt.Sprintf("%-13s: ", name))+value)
+		}
+	}
+	field("Name", console.Colorize("Name", s.Key))
+	field("Type", s.Type)
+	if !s.LastModified.IsZero() {
+		field("Date", s.LastModified.Local().Format(printDate))
+	}
+	if s.Type == "file" {
+		field("Size", humanize.IBytes(uint64(s.Size)))
+	}
+	field("ETag", s.ETag)
+	field("Content-Type", s.ContentType)
+	field("Storage", s.StorageClass)
+	field("Version ID", s.VersionID)
+	field("Encryption", s.Encryption)
+	field("KMS Key ID", s.KMSKeyID)
+	if len(s.Metadata) > 0 {
+		var keys []string
+		for key := range s.Metadata {
+			keys = append(keys, key)
+		}
+		sort.Strings(keys)
+		lines = append(lines, console.Colorize("Field", fmt.Sprintf("%-13s:", "Metadata")))
+		for _, key := range keys {
+			lines = append(lines, "  "+console.Colorize("Field", key+": ")+s.Metadata[key])
+		}
+	}
+	return strings.Join(lines, "\n") + "\n"
+}
+
+// JSON jsonified stat message.
+func (s statMessage) JSON() string {
+	s.Status = "success"
+	statMessageJSONBytes, e := json.Marshal(s)
+	fatalIf(probe.NewError(e), "Unable to marshal into JSON.")
+	return string(statMessageJSONBytes)
+}
+
+// doStat - metadata of the target, objects on S3 are read with a HEAD
+// request. Other clients report what they know of their files.
+func doStat(clnt Client, targetURL, versionID string, sse EncryptOpts) (statMessage, *probe.Error) {
+	msg := statMessage{Key: targetURL, Type: "file"}
+	if s3Clnt, ok := clnt.(*s3Client); ok {
+		_, object := s3Clnt.url2BucketAndObject()
+		if object != "" && !strings.HasSuffix(object, string(clnt.GetURL().Separator)) {
+			info, err := s3Clnt.StatObject(versionID, sse)
+			if err == nil {
+				msg.Size = info.Size
+				msg.LastModified = info.LastModified
+				msg.ETag = info.ETag
+				msg.ContentType = info.ContentType
+				msg.StorageClass = info.StorageClass
+				msg.VersionID = info.VersionID
+				msg.Encryption = info.Encryption
+				msg.KMSKeyID = info.KMSKeyID
+				msg.Metadata = info.Metadata
+				return msg, nil
+			}
+			// Prefixes have no object of their own.
+			if _, ok := err.ToGoError().(ObjectMissing); !ok || versionID != "" {
+				return statMessage{}, err.Trace(targetURL)
+			}
+		}
+	} else if versionID != "" {
+		return statMessage{}, probe.NewError(APINotImplemented{API: "--version-id", APIType: targetURL})
+	}
+
+	content, err := clnt.Stat()
+	if err != nil {
+		return statMessage{}, err.Trace(targetURL)
+	}
+	msg.Size = content.Size
+	msg.LastModified = content.Time
+	msg.StorageClass = content.StorageClass
+	if content.Type.IsDir() {
+		msg.Type = "folder"
+		return msg, nil
+	}
+	if gcsClnt, ok := clnt.(*gcsClient); ok {
+		metadata, err := gcsClnt.GetMetadata(sse)
+		if err != nil {
+			return statMessage{}, err.Trace(targetURL)
+		}
+		msg.ContentType = metadata["Content-Type"]
+		delete(metadata, "Content-Type")
+		msg.Metadata = metadata
+	}
+	return msg, nil
+}
+
+// mainStat - is a handler for mc stat command
+func mainStat(ctx *cli.Context) {
+	// Additional command specific theme customization.
+	console.SetColor("Name", color.New(color.Bold))
+	console.SetColor("Field", color.New(color.FgCyan))
+
+	// Set global flags from context.
+	setGlobalsFromContext(ctx)
+
+	// check 'stat' cli arguments.
+	checkStatSyntax(ctx)
+
+	encKeys, err := parseEncryptKeys(ctx.String("encrypt-key"), "")
+	fatalIf(err, "Unable to parse encryption keys.")
+
+	versionID := ctx.String("version-id")
+	for _, targetURL := range ctx.Args() {
+		clnt, err := newClient(targetURL)
+		fatalIf(err.Trace(targetURL), "Unable to initialize target ‘"+targetURL+"’.")
+
+		msg, err := doStat(clnt, targetURL, versionID, getEncryptOpts(encKeys, targetURL))
+		if err != nil {
+			errorIf(err.Trace(targetURL), "Unable to stat ‘"+targetURL+"’.")
+			continue
+		}
+		printMsg(msg)
+	}
+}
@@ -17,6 +17,8 @@
 package cmd
 
 import (
+	"io"
+
 	"github.com/minio/mc/pkg/console"
 	"github.com/minio/minio/pkg/probe"
 )
@@ -33,7 +35,10 @@ type Status interface {
 	Update()
 	Total() int64
 	SetTotal(int64) Status
-	SetCaption(string)
+	SetTotalObjects(int64)
+
+	StartFile(name string, size int64) io.Reader
+	FinishFile(io.Reader)
 
 	Read(p []byte) (n int, err error)
 
@@ -66,8 +71,16 @@ func (ds *DummyStatus) SetTotal(v int64) Status {
 	return ds
 }
 
-// SetCaption sets the caption of the progressbar, ignored for quietstatus
-func (ds *DummyStatus) SetCaption(s string) {}
+// SetTotalObjects sets the number of objects, ignored for dummystatus
+func (ds *DummyStatus) SetTotalObjects(v int64) {}
+
+// StartFile returns the reader of a file in transfer
+func (ds *DummyStatus) StartFile(name string, size int64) io.Reader {
+	return ds
+}
+
+// FinishFile is ignored for dummystatus
+func (ds *DummyStatus) FinishFile(r io.Reader) {}
 
 // Total returns the total number of bytes
 func (ds *DummyStatus) Total() int64 {
@@ -124,8 +137,17 @@ func (qs *QuietStatus) SetTotal(v int64) Status {
 	return qs
 }
 
-// SetCaption sets the caption of the progressbar, ignored for quietstatus
-func (qs *QuietStatus) SetCaption(s string) {
+// SetTotalObjects sets the number of objects, ignored for quietstatus
+func (qs *QuietStatus) SetTotalObjects(v int64) {
+}
+
+// StartFile returns the reader of a file in transfer
+func (qs *QuietStatus) StartFile(name string, size int64) io.Reader {
+	return qs.accounter
+}
+
+// FinishFile is ignored for quietstatus
+func (qs *QuietStatus) FinishFile(r io.Reader) {
 }
 
 // Total returns the total number of bytes
@@ -143,8 +165,11 @@ func (qs *QuietStatus) Add(v int64) Status {
 func (qs *QuietStatus) Println(data ...interface{}) {
 }
 
-// PrintMsg prints message
+// PrintMsg prints message, ignored in quiet mode
 func (qs *QuietStatus) PrintMsg(msg message) {
+	if globalQuiet && !globalJSON {
+		return
+	}
 	if !globalJSON {
 		console.Println(msg.String())
 	} else {
@@ -197,9 +222,20 @@ func (ps *ProgressStatus) Read(p []byte) (n int, err error) {
 	return ps.progressBar.Read(p)
 }
 
-// SetCaption sets the caption of the progressbar
-func (ps *ProgressStatus) SetCaption(s string) {
-	ps.progressBar.SetCaption(s)
+// SetTotalObjects sets the number of objects
+func (ps *ProgressStatus) SetTotalObjects(v int64) {
+	ps.progressBar.setTotalObjects(v)
+}
+
+// StartFile shows a file in transfer below the progressbar
+func (ps *ProgressStatus) StartFile(name string, size int64) io.Reader {
+	return ps.progressBar.startFile(name, size)
+}
+
+// FinishFile counts an object as done, nil if it was not transferred
+func (ps *ProgressStatus) FinishFile(r io.Reader) {
+	f, _ := r.(*fileProgress)
+	ps.progressBar.finishFile(f)
 }
 
 // Total returns the total number of bytes
@@ -0,0 +1,129 @@
+/*
+ * Minio Client (C) 2016 Minio, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"os"
+	"path/filepath"
+
+	"github.com/minio/minio/pkg/probe"
+)
+
+// Metadata of objects copied from links with ‘--preserve-links’, holds
+// the target of the link.
+const symlinkMetadataKey = "X-Amz-Meta-Mc-Symlink"
+
+// symlinkMode - handling of symbolic links while listing files.
+type symlinkMode int
+
+const (
+	// Files behind links are listed, folders behind links are not
+	// listed recursively.
+	symlinksDefault symlinkMode = iota
+	// Folders behind links are listed recursively as well.
+	symlinksFollow
+	// Links are not listed.
+	symlinksSkip
+	// Links are listed as empty files, copied to objects holding the
+	// target of the link as metadata and back to links.
+	symlinksPreserve
+)
+
+// fsSymlinks - handling of symbolic links by filesystem clients, set
+// by cp and mirror.
+var fsSymlinks = symlinksDefault
+
+// parseSymlinkMode - mode of the ‘--follow-symlinks’, ‘--skip-symlinks’
+// and ‘--preserve-links’ flags, at most one of them may be set.
+func parseSymlinkMode(follow, skip, preserve bool) (symlinkMode, *probe.Error) {
+	mode := symlinksDefault
+	n := 0
+	if follow {
+		mode = symlinksFollow
+		n++
+	}
+	if skip {
+		mode = symlinksSkip
+		n++
+	}
+	if preserve {
+		mode = symlinksPreserve
+		n++
+	}
+	if n > 1 {
+		return symlinksDefault, errConflictingSymlinkFlags().Trace()
+	}
+	return mode, nil
+}
+
+// symlinkModeFromSession - symlink mode stored in a session.
+func symlinkModeFromSession(session *sessionV8) (symlinkMode, *probe.Error) {
+	flags := session.Header.CommandBoolFlags
+	return parseSymlinkMode(flags["follow-symlinks"], flags["skip-symlinks"], flags["preserve-links"])
+}
+
+// copySymlink - copies a link listed with preserved links. Links are
+// uploaded as empty objects with the target as metadata.
+func copySymlink(ctx context.Context, symlink string, targetAlias string, targetURL ClientURL, progress io.Reader, opts PutOpts) *probe.Error {
+	if targetURL.Type == FileSystem {
+		return makeSymlink(symlink, targetURL.Path)
+	}
+	opts.Metadata = mergeMetadata(opts.Metadata, map[string]string{symlinkMetadataKey: symlink})
+	_, err := putTargetStreamFromAlias(ctx, targetAlias, targetURL.String(), bytes.NewReader(nil), 0, progress, opts)
+	if err != nil {
+		return err.Trace(targetURL.String())
+	}
+	return nil
+}
+
+// restoreSymlink - restores the link of an empty object uploaded with
+// preserved links, reports whether the object was a link.
+func restoreSymlink(sourceAlias string, sourceContent *ClientContent, targetURL ClientURL, sse EncryptOpts) (bool, *probe.Error) {
+	if fsSymlinks != symlinksPreserve || sourceContent.Size != 0 ||
+		sourceContent.URL.Type != ObjectStorage || targetURL.Type != FileSystem {
+		return false, nil
+	}
+	metadata, err := getSourceMetadataFromAlias(sourceAlias, sourceContent.URL.String(), sse)
+	if err != nil {
+		return false, err.Trace(sourceContent.URL.String())
+	}
+	symlink, ok := metadata[symlinkMetadataKey]
+	if !ok {
+		return false, nil
+	}
+	return true, makeSymlink(symlink, targetURL.Path)
+}
+
+// makeSymlink - creates a link, replacing a link or file of the same
+// name.
+func makeSymlink(symlink, fpath string) *probe.Error {
+	if e := os.MkdirAll(filepath.Dir(fpath), 0777); e != nil {
+		return probe.NewError(e).Trace(fpath)
+	}
+	if fi, e := os.Lstat(fpath); e == nil && !fi.IsDir() {
+		if e = os.Remove(fpath); e != nil {
+			return probe.NewError(e).Trace(fpath)
+		}
+	}
+	if e := os.Symlink(symlink, fpath); e != nil {
+		return probe.NewError(e).Trace(fpath)
+	}
+	return nil
+}
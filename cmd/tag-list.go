@@ -0,0 +1,100 @@
+/*
+ * Minio Client (C) 2016 Minio, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"encoding/json"
+	"sort"
+
+	"github.com/fatih/color"
+	"github.com/minio/cli"
+	"github.com/minio/mc/pkg/console"
+	"github.com/minio/minio/pkg/probe"
+)
+
+var (
+	tagListFlags = []cli.Flag{}
+)
+
+var tagListCmd = cli.Command{
+	Name:   "list",
+	Usage:  "List tags of an object.",
+	Action: mainTagList,
+	Flags:  append(tagListFlags, globalFlags...),
+	CustomHelpTemplate: `NAME:
+   mc tag {{.Name}} - {{.Usage}}
+
+USAGE:
+   mc tag {{.Name}} ALIAS/BUCKET/OBJECT [FLAGS]
+
+FLAGS:
+  {{range .Flags}}{{.}}
+  {{end}}
+EXAMPLES:
+   1. List tags of an object.
+     $ mc tag {{.Name}} s3/mybucket/report.pdf
+`,
+}
+
+// checkTagListSyntax - validate all the passed arguments
+func checkTagListSyntax(ctx *cli.Context) {
+	if len(ctx.Args()) != 1 {
+		cli.ShowCommandHelpAndExit(ctx, "list", 1) // last argument is exit code
+	}
+}
+
+// tagListMessage container for a single tag.
+type tagListMessage struct {
+	Status string `json:"status"`
+	Key    string `json:"key"`
+	Value  string `json:"value"`
+}
+
+func (t tagListMessage) JSON() string {
+	t.Status = "success"
+	tagListMessageJSONBytes, e := json.Marshal(t)
+	fatalIf(probe.NewError(e), "Unable to marshal into JSON.")
+	return string(tagListMessageJSONBytes)
+}
+
+func (t tagListMessage) String() string {
+	return console.Colorize("TagKey", t.Key+"=") + console.Colorize("TagValue", t.Value)
+}
+
+func mainTagList(ctx *cli.Context) {
+	console.SetColor("TagKey", color.New(color.FgCyan, color.Bold))
+	console.SetColor("TagValue", color.New(color.FgWhite))
+
+	setGlobalsFromContext(ctx)
+	checkTagListSyntax(ctx)
+
+	path := ctx.Args().First()
+	client, err := newS3Client(path)
+	fatalIf(err, "Unable to initialize ‘"+path+"’, tags are only supported on object storage.")
+
+	tags, err := client.GetObjectTagging()
+	fatalIf(err.Trace(path), "Unable to get tags of ‘"+path+"’.")
+
+	var keys []string
+	for key := range tags {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	for _, key := range keys {
+		printMsg(tagListMessage{Key: key, Value: tags[key]})
+	}
+}
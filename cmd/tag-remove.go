@@ -0,0 +1,90 @@
+/*
+ * Minio Client (C) 2016 Minio, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"encoding/json"
+
+	"github.com/fatih/color"
+	"github.com/minio/cli"
+	"github.com/minio/mc/pkg/console"
+	"github.com/minio/minio/pkg/probe"
+)
+
+var (
+	tagRemoveFlags = []cli.Flag{}
+)
+
+var tagRemoveCmd = cli.Command{
+	Name:   "remove",
+	Usage:  "Remove all tags of an object.",
+	Action: mainTagRemove,
+	Flags:  append(tagRemoveFlags, globalFlags...),
+	CustomHelpTemplate: `NAME:
+   mc tag {{.Name}} - {{.Usage}}
+
+USAGE:
+   mc tag {{.Name}} ALIAS/BUCKET/OBJECT [FLAGS]
+
+FLAGS:
+  {{range .Flags}}{{.}}
+  {{end}}
+EXAMPLES:
+   1. Remove tags of an object.
+     $ mc tag {{.Name}} s3/mybucket/report.pdf
+`,
+}
+
+// checkTagRemoveSyntax - validate all the passed arguments
+func checkTagRemoveSyntax(ctx *cli.Context) {
+	if len(ctx.Args()) != 1 {
+		cli.ShowCommandHelpAndExit(ctx, "remove", 1) // last argument is exit code
+	}
+}
+
+// tagRemoveMessage container
+type tagRemoveMessage struct {
+	Status string `json:"status"`
+	Target string `json:"target"`
+}
+
+func (t tagRemoveMessage) JSON() string {
+	t.Status = "success"
+	tagRemoveMessageJSONBytes, e := json.Marshal(t)
+	fatalIf(probe.NewError(e), "Unable to marshal into JSON.")
+	return string(tagRemoveMessageJSONBytes)
+}
+
+func (t tagRemoveMessage) String() string {
+	return console.Colorize("Tag", "Tags removed from ‘"+t.Target+"’.")
+}
+
+func mainTagRemove(ctx *cli.Context) {
+	console.SetColor("Tag", color.New(color.FgGreen, color.Bold))
+
+	setGlobalsFromContext(ctx)
+	checkTagRemoveSyntax(ctx)
+
+	path := ctx.Args().First()
+	client, err := newS3Client(path)
+	fatalIf(err, "Unable to initialize ‘"+path+"’, tags are only supported on object storage.")
+
+	err = client.DeleteObjectTagging()
+	fatalIf(err.Trace(path), "Unable to remove tags of ‘"+path+"’.")
+
+	printMsg(tagRemoveMessage{Target: path})
+}
@@ -0,0 +1,128 @@
+/*
+ * Minio Client (C) 2016 Minio, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"encoding/json"
+	"strings"
+
+	"github.com/fatih/color"
+	"github.com/minio/cli"
+	"github.com/minio/mc/pkg/console"
+	"github.com/minio/minio/pkg/probe"
+)
+
+const (
+	// Maximum number of tags of an object.
+	tagMaxCount = 10
+	// Maximum length of a tag key.
+	tagMaxKeyLength = 128
+	// Maximum length of a tag value.
+	tagMaxValueLength = 256
+)
+
+var (
+	tagSetFlags = []cli.Flag{}
+)
+
+var tagSetCmd = cli.Command{
+	Name:   "set",
+	Usage:  "Replace tags of an object.",
+	Action: mainTagSet,
+	Flags:  append(tagSetFlags, globalFlags...),
+	CustomHelpTemplate: `NAME:
+   mc tag {{.Name}} - {{.Usage}}
+
+USAGE:
+   mc tag {{.Name}} ALIAS/BUCKET/OBJECT KEY=VALUE [KEY=VALUE...]
+
+FLAGS:
+  {{range .Flags}}{{.}}
+  {{end}}
+EXAMPLES:
+   1. Tag an object with its project and cost center.
+     $ mc tag {{.Name}} s3/mybucket/report.pdf project=apollo costcenter=1024
+`,
+}
+
+// checkTagSetSyntax - validate all the passed arguments
+func checkTagSetSyntax(ctx *cli.Context) {
+	if len(ctx.Args()) < 2 {
+		cli.ShowCommandHelpAndExit(ctx, "set", 1) // last argument is exit code
+	}
+}
+
+// parseTags - parses KEY=VALUE arguments into a tag set.
+func parseTags(args []string) (map[string]string, *probe.Error) {
+	if len(args) > tagMaxCount {
+		return nil, errInvalidTag(args[tagMaxCount]).Trace(args...)
+	}
+	tags := make(map[string]string)
+	for _, arg := range args {
+		i := strings.Index(arg, "=")
+		if i <= 0 {
+			return nil, errInvalidTag(arg).Trace(arg)
+		}
+		key, value := arg[:i], arg[i+1:]
+		if len(key) > tagMaxKeyLength || len(value) > tagMaxValueLength {
+			return nil, errInvalidTag(arg).Trace(arg)
+		}
+		if _, ok := tags[key]; ok {
+			return nil, errInvalidTag(arg).Trace(arg)
+		}
+		tags[key] = value
+	}
+	return tags, nil
+}
+
+// tagSetMessage container
+type tagSetMessage struct {
+	Status string            `json:"status"`
+	Target string            `json:"target"`
+	Tags   map[string]string `json:"tags"`
+}
+
+func (t tagSetMessage) JSON() string {
+	t.Status = "success"
+	tagSetMessageJSONBytes, e := json.Marshal(t)
+	fatalIf(probe.NewError(e), "Unable to marshal into JSON.")
+	return string(tagSetMessageJSONBytes)
+}
+
+func (t tagSetMessage) String() string {
+	return console.Colorize("Tag", "Tags set on ‘"+t.Target+"’.")
+}
+
+func mainTagSet(ctx *cli.Context) {
+	console.SetColor("Tag", color.New(color.FgGreen, color.Bold))
+
+	setGlobalsFromContext(ctx)
+	checkTagSetSyntax(ctx)
+
+	args := ctx.Args()
+	path := args.First()
+	tags, err := parseTags(args.Tail())
+	fatalIf(err, "Unable to parse tags.")
+
+	client, err := newS3Client(path)
+	fatalIf(err, "Unable to initialize ‘"+path+"’, tags are only supported on object storage.")
+
+	err = client.PutObjectTagging(tags)
+	fatalIf(err.Trace(path), "Unable to set tags of ‘"+path+"’.")
+
+	printMsg(tagSetMessage{Target: path, Tags: tags})
+}
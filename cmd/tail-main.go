@@ -0,0 +1,307 @@
+/*
+ * Minio Client (C) 2016 Minio, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"syscall"
+	"time"
+
+	"github.com/dustin/go-humanize"
+	"github.com/minio/cli"
+	"github.com/minio/mc/pkg/console"
+	"github.com/minio/minio/pkg/probe"
+)
+
+var (
+	tailFlags = []cli.Flag{
+		cli.BoolFlag{
+			Name:  "help, h",
+			Usage: "Help of tail.",
+		},
+		cli.IntFlag{
+			Name:  "lines, n",
+			Value: 10,
+			Usage: "Display the last number of lines.",
+		},
+		cli.StringFlag{
+			Name:  "bytes, c",
+			Usage: "Display the last number of bytes, e.g. ‘512’ or ‘1KiB’. Only this range is downloaded.",
+		},
+		cli.BoolFlag{
+			Name:  "follow, f",
+			Usage: "Keep displaying data appended to the object, until interrupted.",
+		},
+		cli.DurationFlag{
+			Name:  "sleep-interval, s",
+			Value: time.Second,
+			Usage: "Interval of checking whether data was appended while following.",
+		},
+		cli.BoolFlag{
+			Name:  "notify",
+			Usage: "Wait for bucket notifications instead of checking at an interval while following.",
+		},
+		cli.StringFlag{
+			Name:  "encrypt-key",
+			Usage: "Decrypt objects with customer provided keys, as comma separated list of ‘ALIAS/PREFIX=KEY’.",
+		},
+	}
+)
+
+// Display the end of files.
+var tailCmd = cli.Command{
+	Name:   "tail",
+	Usage:  "Display last part of files.",
+	Action: mainTail,
+	Flags:  append(tailFlags, globalFlags...),
+	CustomHelpTemplate: `NAME:
+   mc {{.Name}} - {{.Usage}}
+
+USAGE:
+   mc {{.Name}} [FLAGS] SOURCE [SOURCE...]
+
+   Only the end of objects is downloaded. Objects are followed by their size, data written
+   in their place is displayed from the offset displayed last.
+
+FLAGS:
+  {{range .Flags}}{{.}}
+  {{end}}
+EXAMPLES:
+   1. Display the last ten lines of a log object.
+      $ mc {{.Name}} s3/mybucket/server.log
+
+   2. Display the last 1KiB of a large object.
+      $ mc {{.Name}} -c 1KiB s3/mybucket/backup.tar
+
+   3. Keep displaying lines appended to a log object, checking every 5 seconds.
+      $ mc {{.Name}} -f -s 5s s3/mybucket/server.log
+
+   4. Keep displaying lines appended to a log object on a Minio server as it is written.
+      $ mc {{.Name}} -f --notify myminio/mybucket/server.log
+`,
+}
+
+// checkTailSyntax performs command-line input validation for tail command.
+func checkTailSyntax(ctx *cli.Context) {
+	if !ctx.Args().Present() {
+		cli.ShowCommandHelpAndExit(ctx, "tail", 1) // last argument is exit code
+	}
+	if ctx.Int("lines") < 0 {
+		fatalIf(errInvalidArgument().Trace(ctx.Args()...), "Number of lines cannot be negative.")
+	}
+	if ctx.String("bytes") != "" {
+		if _, e := humanize.ParseBytes(ctx.String("bytes")); e != nil {
+			fatalIf(probe.NewError(e), "Unable to parse number of bytes.")
+		}
+	}
+	if ctx.Bool("follow") && len(ctx.Args()) > 1 {
+		fatalIf(errInvalidArgument().Trace(ctx.Args()...), "Only a single object can be followed.")
+	}
+	if ctx.Duration("sleep-interval") <= 0 {
+		fatalIf(errInvalidArgument().Trace(ctx.String("sleep-interval")), "Sleep interval has to be positive.")
+	}
+	if ctx.Bool("notify") && !ctx.Bool("follow") {
+		fatalIf(errInvalidArgument().Trace(ctx.Args()...), "Notifications are only used while following.")
+	}
+}
+
+// Size of the ranges read backwards from the end of an object while
+// looking for the start of its last lines.
+const tailBlockSize = 64 * humanize.KiByte
+
+// tailLinesOffset - offset of the last n lines of an object of size,
+// reading ranges backwards with get. A newline ending the object does
+// not start another line.
+func tailLinesOffset(get func(offset, length int64) (io.Reader, *probe.Error), size int64, n int) (int64, *probe.Error) {
+	if n == 0 {
+		return size, nil
+	}
+	end := size
+	newlines := 0
+	for end > 0 {
+		start := end - tailBlockSize
+		if start < 0 {
+			start = 0
+		}
+		reader, err := get(start, end-start)
+		if err != nil {
+			return 0, err.Trace()
+		}
+		block, e := ioutil.ReadAll(reader)
+		if closer, ok := reader.(io.Closer); ok {
+			closer.Close()
+		}
+		if e != nil {
+			return 0, probe.NewError(e)
+		}
+		for i := len(block) - 1; i >= 0; i-- {
+			if block[i] != '\n' || start+int64(i) == size-1 {
+				continue
+			}
+			if newlines++; newlines == n {
+				return start + int64(i) + 1, nil
+			}
+		}
+		end = start
+	}
+	return 0, nil
+}
+
+// tailRange - displays the range of the object from offset to size.
+func tailRange(clnt Client, sse EncryptOpts, offset, size int64) *probe.Error {
+	if offset >= size {
+		return nil
+	}
+	reader, err := clnt.Get(globalContext, GetOpts{SSE: sse, Offset: offset, Length: size - offset})
+	if err != nil {
+		return err.Trace()
+	}
+	if closer, ok := reader.(io.Closer); ok {
+		defer closer.Close()
+	}
+	return catOut(reader).Trace()
+}
+
+// tailURL displays the end of a URL to stdout, either the last lines
+// or, if byteCount is not negative, the last bytes. Returns the size
+// displayed up to.
+func tailURL(clnt Client, sse EncryptOpts, lines int, byteCount int64) (int64, *probe.Error) {
+	st, err := clnt.Stat()
+	if err != nil {
+		return 0, err.Trace()
+	}
+	if st.Type.IsDir() {
+		return 0, errInvalidArgument().Trace(clnt.GetURL().String())
+	}
+
+	offset := st.Size - byteCount
+	if byteCount < 0 {
+		get := func(offset, length int64) (io.Reader, *probe.Error) {
+			return clnt.Get(globalContext, GetOpts{SSE: sse, Offset: offset, Length: length})
+		}
+		if offset, err = tailLinesOffset(get, st.Size, lines); err != nil {
+			return 0, err.Trace()
+		}
+	} else if offset < 0 {
+		offset = 0
+	}
+	return st.Size, tailRange(clnt, sse, offset, st.Size).Trace()
+}
+
+// followURL keeps displaying data appended to the object beyond offset,
+// until interrupted. Objects are checked at every interval, or on
+// bucket notifications of uploads.
+func followURL(clnt Client, sse EncryptOpts, offset int64, interval time.Duration, notify bool) *probe.Error {
+	trapCh := signalTrap(os.Interrupt, syscall.SIGTERM)
+
+	var changedCh <-chan Event
+	var errorCh <-chan *probe.Error
+	var tickCh <-chan time.Time
+	if notify {
+		params := WatchParams{
+			AccountID: fmt.Sprintf("%d", time.Now().Unix()),
+			Events:    []string{"put"},
+		}
+		wo, err := clnt.Watch(params)
+		if err != nil {
+			return err.Trace()
+		}
+		defer clnt.Unwatch(params)
+		defer wo.Close()
+		changedCh, errorCh = wo.Events(), wo.Errors()
+	} else {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		tickCh = ticker.C
+	}
+
+	for {
+		select {
+		case <-trapCh:
+			return nil
+		case err, ok := <-errorCh:
+			if !ok {
+				return nil
+			}
+			return err.Trace()
+		case _, ok := <-changedCh:
+			if !ok {
+				return nil
+			}
+		case <-tickCh:
+		}
+		st, err := clnt.Stat()
+		if err != nil {
+			// Objects may be missing while they are replaced.
+			switch err.ToGoError().(type) {
+			case ObjectMissing, PathNotFound:
+				continue
+			}
+			return err.Trace()
+		}
+		if st.Size < offset {
+			errorIf(errObjectTruncated(clnt.GetURL().String()).Trace(), "Displaying the object from its beginning.")
+			offset = 0
+		}
+		if err = tailRange(clnt, sse, offset, st.Size); err != nil {
+			return err.Trace()
+		}
+		offset = st.Size
+	}
+}
+
+// mainTail is the main entry point for tail command.
+func mainTail(ctx *cli.Context) {
+	// Set global flags from context.
+	setGlobalsFromContext(ctx)
+
+	// check 'tail' cli arguments.
+	checkTailSyntax(ctx)
+
+	encKeys, err := parseEncryptKeys(ctx.String("encrypt-key"), "")
+	fatalIf(err, "Unable to parse encryption keys.")
+
+	// Lines are counted unless a number of bytes is asked for.
+	lines := ctx.Int("lines")
+	var byteCount int64 = -1
+	if ctx.String("bytes") != "" {
+		n, _ := humanize.ParseBytes(ctx.String("bytes"))
+		byteCount = int64(n)
+	}
+
+	args := ctx.Args()
+	for i, url := range args {
+		if len(args) > 1 {
+			if i > 0 {
+				console.Println()
+			}
+			console.Println("==> " + url + " <==")
+		}
+		clnt, err := newClient(url)
+		fatalIf(err.Trace(url), "Unable to initialize source ‘"+url+"’.")
+		sse := getEncryptOpts(encKeys, url)
+		offset, err := tailURL(clnt, sse, lines, byteCount)
+		fatalIf(err.Trace(url), "Unable to read from ‘"+url+"’.")
+		if ctx.Bool("follow") {
+			err = followURL(clnt, sse, offset, ctx.Duration("sleep-interval"), ctx.Bool("notify"))
+			fatalIf(err.Trace(url), "Unable to follow ‘"+url+"’.")
+		}
+	}
+}
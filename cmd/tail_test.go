@@ -0,0 +1,55 @@
+/*
+ * Minio Client (C) 2016 Minio, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"bytes"
+	"io"
+	"strings"
+
+	"github.com/minio/minio/pkg/probe"
+	. "gopkg.in/check.v1"
+)
+
+// Test finding the start of the last lines of objects.
+func (s *TestSuite) TestTailLinesOffset(c *C) {
+	longLine := strings.Repeat("x", tailBlockSize+10) + "\n"
+	testCases := []struct {
+		data   string
+		lines  int
+		offset int64
+	}{
+		{"", 10, 0},
+		{"a\nb\nc\n", 0, 6},
+		{"a\nb\nc\n", 2, 2},
+		{"a\nb\nc", 2, 2},
+		{"a\nb\nc\n", 10, 0},
+		{"a\n\nc\n", 2, 2},
+		// Lines spanning ranges read.
+		{"a\n" + longLine + longLine, 1, int64(2 + len(longLine))},
+		{"a\n" + longLine + longLine, 2, 2},
+	}
+	for i, testCase := range testCases {
+		data := []byte(testCase.data)
+		get := func(offset, length int64) (io.Reader, *probe.Error) {
+			return bytes.NewReader(data[offset : offset+length]), nil
+		}
+		offset, err := tailLinesOffset(get, int64(len(data)), testCase.lines)
+		c.Assert(err, IsNil)
+		c.Assert(offset, Equals, testCase.offset, Commentf("Test %d", i+1))
+	}
+}
@@ -0,0 +1,173 @@
+/*
+ * Minio Client (C) 2016 Minio, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/dustin/go-humanize"
+	"github.com/minio/mc/pkg/console"
+	"github.com/minio/minio/pkg/probe"
+)
+
+// transferFailure - an object which failed to transfer, written to the
+// failure manifest as a JSON line so that it can be retried.
+type transferFailure struct {
+	Source string `json:"source,omitempty"`
+	Target string `json:"target,omitempty"`
+	Error  string `json:"error"`
+}
+
+// transferReport - counts objects and bytes transferred by cp and
+// mirror, safe for concurrent use.
+type transferReport struct {
+	mutex     *sync.Mutex
+	startTime time.Time
+
+	transferred int
+	skipped     int
+	failed      int
+	removed     int
+	bytes       int64
+	failures    []transferFailure
+}
+
+// newTransferReport - report of a transfer starting now.
+func newTransferReport() *transferReport {
+	return &transferReport{
+		mutex:     &sync.Mutex{},
+		startTime: time.Now(),
+	}
+}
+
+// Transferred - counts an object transferred.
+func (r *transferReport) Transferred(size int64) {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	r.transferred++
+	r.bytes += size
+}
+
+// Skipped - counts an object skipped, e.g. copied before resuming.
+func (r *transferReport) Skipped() {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	r.skipped++
+}
+
+// Removed - counts an object removed from the target.
+func (r *transferReport) Removed() {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	r.removed++
+}
+
+// Failed - counts an object failed to transfer or remove.
+func (r *transferReport) Failed(sURLs URLs) {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	r.failed++
+	failure := transferFailure{}
+	if sURLs.SourceContent != nil {
+		failure.Source = sURLs.SourceContent.URL.String()
+	}
+	if sURLs.TargetContent != nil {
+		failure.Target = sURLs.TargetContent.URL.String()
+	}
+	if sURLs.Error != nil {
+		failure.Error = sURLs.Error.ToGoError().Error()
+	}
+	r.failures = append(r.failures, failure)
+}
+
+// Summary - summary of the transfer so far.
+func (r *transferReport) Summary() transferSummaryMessage {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	elapsed := time.Since(r.startTime)
+	summary := transferSummaryMessage{
+		Transferred: r.transferred,
+		Skipped:     r.skipped,
+		Failed:      r.failed,
+		Removed:     r.removed,
+		Bytes:       r.bytes,
+		Elapsed:     elapsed.Seconds(),
+	}
+	if elapsed > 0 {
+		summary.Speed = float64(r.bytes) / elapsed.Seconds()
+	}
+	return summary
+}
+
+// WriteFailures - writes the failure manifest, one JSON line for every
+// failed object. No manifest is written if no object failed.
+func (r *transferReport) WriteFailures(manifestPath string) *probe.Error {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	if manifestPath == "" || len(r.failures) == 0 {
+		return nil
+	}
+	f, e := os.Create(manifestPath)
+	if e != nil {
+		return probe.NewError(e)
+	}
+	defer f.Close()
+	encoder := json.NewEncoder(f)
+	for _, failure := range r.failures {
+		if e = encoder.Encode(failure); e != nil {
+			return probe.NewError(e)
+		}
+	}
+	return nil
+}
+
+// transferSummaryMessage container for the summary of cp and mirror.
+type transferSummaryMessage struct {
+	Status      string `json:"status"`
+	Transferred int    `json:"transferred"`
+	Skipped     int    `json:"skipped"`
+	Failed      int    `json:"failed"`
+	Removed     int    `json:"removed,omitempty"`
+	Bytes       int64  `json:"bytes"`
+	// Elapsed time in seconds and average speed in bytes per second.
+	Elapsed float64 `json:"elapsed"`
+	Speed   float64 `json:"speed"`
+}
+
+// String colorized transfer summary message.
+func (t transferSummaryMessage) String() string {
+	message := fmt.Sprintf("Transferred: %d objects, %s in %s (%s/s), Skipped: %d, Failed: %d",
+		t.Transferred, humanize.IBytes(uint64(t.Bytes)),
+		time.Duration(t.Elapsed)*time.Second,
+		humanize.IBytes(uint64(t.Speed)), t.Skipped, t.Failed)
+	if t.Removed > 0 {
+		message += fmt.Sprintf(", Removed: %d", t.Removed)
+	}
+	return console.Colorize("Summary", message+".")
+}
+
+// JSON jsonified transfer summary message.
+func (t transferSummaryMessage) JSON() string {
+	t.Status = "success"
+	summaryBytes, e := json.Marshal(t)
+	fatalIf(probe.NewError(e), "Unable to marshal transfer summary.")
+	return string(summaryBytes)
+}
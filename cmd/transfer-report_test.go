@@ -0,0 +1,71 @@
+/*
+ * Minio Client (C) 2016 Minio, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/minio/minio/pkg/probe"
+	. "gopkg.in/check.v1"
+)
+
+// Test counting transfers and writing the failure manifest.
+func (s *TestSuite) TestTransferReport(c *C) {
+	report := newTransferReport()
+	report.Transferred(10)
+	report.Transferred(20)
+	report.Skipped()
+	report.Removed()
+	report.Failed(URLs{
+		SourceContent: &ClientContent{URL: *newClientURL("/tmp/source/file")},
+		TargetContent: &ClientContent{URL: *newClientURL("/tmp/target/file")},
+		Error:         probe.NewError(PathInsufficientPermission{Path: "/tmp/source/file"}),
+	})
+
+	summary := report.Summary()
+	c.Assert(summary.Transferred, Equals, 2)
+	c.Assert(summary.Skipped, Equals, 1)
+	c.Assert(summary.Failed, Equals, 1)
+	c.Assert(summary.Removed, Equals, 1)
+	c.Assert(summary.Bytes, Equals, int64(30))
+
+	root, e := ioutil.TempDir(os.TempDir(), "report-")
+	c.Assert(e, IsNil)
+	defer os.RemoveAll(root)
+
+	manifestPath := filepath.Join(root, "failed.json")
+	c.Assert(report.WriteFailures(manifestPath), IsNil)
+	data, e := ioutil.ReadFile(manifestPath)
+	c.Assert(e, IsNil)
+	lines := strings.Split(strings.TrimSpace(string(data)), "\n")
+	c.Assert(len(lines), Equals, 1)
+	var failure transferFailure
+	c.Assert(json.Unmarshal([]byte(lines[0]), &failure), IsNil)
+	c.Assert(failure.Source, Equals, "/tmp/source/file")
+	c.Assert(failure.Target, Equals, "/tmp/target/file")
+	c.Assert(failure.Error, Not(Equals), "")
+
+	// No manifest is written without failures.
+	manifestPath = filepath.Join(root, "none.json")
+	c.Assert(newTransferReport().WriteFailures(manifestPath), IsNil)
+	_, e = os.Stat(manifestPath)
+	c.Assert(os.IsNotExist(e), Equals, true)
+}
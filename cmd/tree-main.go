@@ -0,0 +1,270 @@
+/*
+ * Minio Client (C) 2016 Minio, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/dustin/go-humanize"
+	"github.com/fatih/color"
+	"github.com/minio/cli"
+	"github.com/minio/mc/pkg/console"
+	"github.com/minio/minio/pkg/probe"
+)
+
+// tree specific flags.
+var (
+	treeFlags = []cli.Flag{
+		cli.BoolFlag{
+			Name:  "help, h",
+			Usage: "Help of tree.",
+		},
+		cli.IntFlag{
+			Name:  "depth, d",
+			Usage: "Render prefixes up to this depth below the target, deeper objects are counted at their branch. Unlimited if not set.",
+		},
+		cli.BoolFlag{
+			Name:  "files, f",
+			Usage: "Render objects as well as prefixes.",
+		},
+	}
+)
+
+// render a tree of prefixes.
+var treeCmd = cli.Command{
+	Name:   "tree",
+	Usage:  "Render prefixes of buckets and folders as a tree.",
+	Action: mainTree,
+	Flags:  append(treeFlags, globalFlags...),
+	CustomHelpTemplate: `NAME:
+   mc {{.Name}} - {{.Usage}}
+
+USAGE:
+   mc {{.Name}} [FLAGS] TARGET [TARGET ...]
+
+FLAGS:
+  {{range .Flags}}{{.}}
+  {{end}}
+EXAMPLES:
+   1. Render the layout of a bucket on Amazon S3 cloud storage.
+      $ mc {{.Name}} s3/mybucket
+
+   2. Render the prefixes of all buckets on Amazon S3 cloud storage, two levels deep.
+      $ mc {{.Name}} --depth 2 s3
+
+   3. Render a local folder along with its files.
+      $ mc {{.Name}} --files /var/log/
+`,
+}
+
+// checkTreeSyntax - validate all the passed arguments
+func checkTreeSyntax(ctx *cli.Context) {
+	if !ctx.Args().Present() {
+		cli.ShowCommandHelpAndExit(ctx, "tree", 1) // last argument is exit code
+	}
+	for _, arg := range ctx.Args() {
+		if strings.TrimSpace(arg) == "" {
+			fatalIf(errInvalidArgument().Trace(ctx.Args()...), "Unable to validate empty argument.")
+		}
+	}
+	if ctx.Int("depth") < 0 {
+		fatalIf(errInvalidArgument().Trace(ctx.String("depth")), "Depth cannot be negative.")
+	}
+}
+
+// treeMessage container for a branch or an object of a tree.
+type treeMessage struct {
+	Status  string `json:"status"`
+	Type    string `json:"type"`
+	Key     string `json:"key"`
+	Size    int64  `json:"size"`
+	Objects int64  `json:"objects"`
+	Depth   int    `json:"depth"`
+
+	// Lines drawn in front of the name, and the name itself.
+	branches string
+	name     string
+}
+
+// String colorized tree message.
+func (t treeMessage) String() string {
+	if t.Type == "file" {
+		return console.Colorize("Branch", t.branches) + console.Colorize("File", t.name) +
+			console.Colorize("Size", fmt.Sprintf(" [%s]", humanize.IBytes(uint64(t.Size))))
+	}
+	return console.Colorize("Branch", t.branches) + console.Colorize("Dir", t.name) +
+		console.Colorize("Size", fmt.Sprintf(" [%s, %d objects]", humanize.IBytes(uint64(t.Size)), t.Objects))
+}
+
+// JSON jsonified tree message.
+func (t treeMessage) JSON() string {
+	t.Status = "success"
+	treeMessageJSONBytes, e := json.Marshal(t)
+	fatalIf(probe.NewError(e), "Unable to marshal into JSON.")
+	return string(treeMessageJSONBytes)
+}
+
+// treeNode - a prefix or an object of a tree, prefixes sum up the
+// objects below them.
+type treeNode struct {
+	isDir    bool
+	size     int64
+	objects  int64
+	children map[string]*treeNode
+}
+
+// child - child node of the name, created if missing.
+func (n *treeNode) child(name string, isDir bool) *treeNode {
+	if n.children == nil {
+		n.children = make(map[string]*treeNode)
+	}
+	if n.children[name] == nil {
+		n.children[name] = &treeNode{isDir: isDir}
+	}
+	return n.children[name]
+}
+
+// doTree - walks all objects below the client URL and renders their
+// prefixes up to depth as a tree, unlimited if depth is zero. Objects
+// are only rendered withFiles. Branches are named relative to
+// targetURL and sorted by name, the target comes first.
+func doTree(clnt Client, targetURL string, depth int, withFiles bool) ([]treeMessage, *probe.Error) {
+	clntURL := clnt.GetURL()
+	separator := string(clntURL.Separator)
+
+	root := &treeNode{isDir: true}
+	for content := range clnt.List(globalContext, true, false) {
+		if content.Err != nil {
+			return nil, content.Err.Trace(clntURL.String())
+		}
+		if content.Type.IsDir() {
+			continue
+		}
+		root.size += content.Size
+		root.objects++
+
+		// Objects are counted at all prefixes of their parents which
+		// are rendered.
+		segments := strings.Split(strings.TrimPrefix(content.URL.Path, clntURL.Path), separator)
+		dirs := segments[:len(segments)-1]
+		node := root
+		for i, dir := range dirs {
+			if depth > 0 && i >= depth {
+				break
+			}
+			node = node.child(dir, true)
+			node.size += content.Size
+			node.objects++
+		}
+		if withFiles && (depth == 0 || len(segments) <= depth) {
+			file := node.child(segments[len(segments)-1], false)
+			file.size = content.Size
+			file.objects = 1
+		}
+	}
+
+	msgs := []treeMessage{{Type: "folder", Key: targetURL, Size: root.size, Objects: root.objects, name: targetURL}}
+	return renderTree(msgs, root, targetURL, separator, "", 1), nil
+}
+
+// renderTree - appends messages of all children of the node, drawn
+// below the branches of its parents.
+func renderTree(msgs []treeMessage, node *treeNode, key, separator, branches string, depth int) []treeMessage {
+	var names []string
+	for name := range node.children {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for i, name := range names {
+		child := node.children[name]
+		last := i == len(names)-1
+		msg := treeMessage{
+			Type:     "file",
+			Key:      key + name,
+			Size:     child.size,
+			Objects:  child.objects,
+			Depth:    depth,
+			branches: branches + "|-- ",
+			name:     name,
+		}
+		if last {
+			msg.branches = branches + "`-- "
+		}
+		if child.isDir {
+			msg.Type = "folder"
+			msg.Key += separator
+			msg.name += separator
+		}
+		msgs = append(msgs, msg)
+		if child.isDir {
+			childBranches := branches + "|   "
+			if last {
+				childBranches = branches + "    "
+			}
+			msgs = renderTree(msgs, child, msg.Key, separator, childBranches, depth+1)
+		}
+	}
+	return msgs
+}
+
+// mainTree - is a handler for mc tree command
+func mainTree(ctx *cli.Context) {
+	// Additional command specific theme customization.
+	console.SetColor("Branch", color.New(color.FgWhite))
+	console.SetColor("Dir", color.New(color.FgCyan, color.Bold))
+	console.SetColor("File", color.New(color.FgWhite))
+	console.SetColor("Size", color.New(color.FgYellow))
+
+	// Set global flags from context.
+	setGlobalsFromContext(ctx)
+
+	// check 'tree' cli arguments.
+	checkTreeSyntax(ctx)
+
+	depth := ctx.Int("depth")
+	withFiles := ctx.Bool("files")
+	for _, targetURL := range ctx.Args() {
+		clnt, err := newClient(targetURL)
+		fatalIf(err.Trace(targetURL), "Unable to initialize target ‘"+targetURL+"’.")
+
+		// Folders, buckets and aliases are rendered by their contents.
+		separator := string(clnt.GetURL().Separator)
+		st, err := clnt.Stat()
+		if err != nil {
+			if _, ok := err.ToGoError().(BucketNameEmpty); !ok {
+				fatalIf(err.Trace(targetURL), "Unable to initialize target ‘"+targetURL+"’.")
+			}
+		}
+		if (err != nil || st.Type.IsDir()) && !strings.HasSuffix(targetURL, separator) {
+			targetURL = targetURL + separator
+			clnt, err = newClient(targetURL)
+			fatalIf(err.Trace(targetURL), "Unable to initialize target ‘"+targetURL+"’.")
+		}
+
+		msgs, err := doTree(clnt, targetURL, depth, withFiles)
+		if err != nil {
+			errorIf(err.Trace(targetURL), "Unable to render tree of ‘"+targetURL+"’.")
+			continue
+		}
+		for _, msg := range msgs {
+			printMsg(msg)
+		}
+	}
+}
@@ -0,0 +1,92 @@
+/*
+ * Minio Client (C) 2016 Minio, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"context"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	. "gopkg.in/check.v1"
+)
+
+// Test rendering prefixes of a folder as a tree.
+func (s *TestSuite) TestTree(c *C) {
+	root, e := ioutil.TempDir(os.TempDir(), "tree-")
+	c.Assert(e, IsNil)
+	defer os.RemoveAll(root)
+
+	objects := map[string]string{
+		"a/x":     "x",
+		"a/b/y":   "yy",
+		"a/b/c/z": "zzz",
+		"d/w":     "wwww",
+		"v":       "vvvvv",
+	}
+	for name, data := range objects {
+		objectPath := filepath.Join(root, filepath.FromSlash(name))
+		fsClient, err := fsNew(objectPath)
+		c.Assert(err, IsNil)
+		_, err = fsClient.Put(context.Background(), strings.NewReader(data), int64(len(data)), "application/octet-stream", nil, PutOpts{})
+		c.Assert(err, IsNil)
+	}
+
+	targetURL := root + string(filepath.Separator)
+	clnt, err := fsNew(targetURL)
+	c.Assert(err, IsNil)
+
+	// Lines of the tree, with sizes and object counts.
+	render := func(depth int, withFiles bool) []string {
+		msgs, err := doTree(clnt, targetURL, depth, withFiles)
+		c.Assert(err, IsNil)
+		var lines []string
+		for _, msg := range msgs[1:] {
+			lines = append(lines, msg.branches+filepath.ToSlash(msg.name)+" "+msg.Type+" "+branchCount(msg.Size, msg.Objects))
+		}
+		c.Assert(msgs[0].Key, Equals, targetURL)
+		c.Assert(branchCount(msgs[0].Size, msgs[0].Objects), Equals, "15/5")
+		return lines
+	}
+
+	c.Assert(render(0, false), DeepEquals, []string{
+		"|-- a/ folder 6/3",
+		"|   `-- b/ folder 5/2",
+		"|       `-- c/ folder 3/1",
+		"`-- d/ folder 4/1",
+	})
+	c.Assert(render(1, true), DeepEquals, []string{
+		"|-- a/ folder 6/3",
+		"|-- d/ folder 4/1",
+		"`-- v file 5/1",
+	})
+	c.Assert(render(2, true), DeepEquals, []string{
+		"|-- a/ folder 6/3",
+		"|   |-- b/ folder 5/2",
+		"|   `-- x file 1/1",
+		"|-- d/ folder 4/1",
+		"|   `-- w file 4/1",
+		"`-- v file 5/1",
+	})
+}
+
+// branchCount - size and objects of a branch as ‘size/objects’.
+func branchCount(size, objects int64) string {
+	return strconv.FormatInt(size, 10) + "/" + strconv.FormatInt(objects, 10)
+}
@@ -18,7 +18,10 @@ package cmd
 
 import (
 	"errors"
+	"strconv"
+	"strings"
 
+	"github.com/dustin/go-humanize"
 	"github.com/minio/minio/pkg/probe"
 )
 
@@ -62,7 +65,227 @@ var (
 		return probe.NewError(errors.New("Source ‘" + URL + "’ is a folder.")).Untrace()
 	}
 
+	errInvalidEncryptKey = func(prefix string) *probe.Error {
+		return probe.NewError(errors.New("Invalid encryption key for ‘" + prefix + "’, key must be 32 bytes long or base64 encoded 32 bytes.")).Untrace()
+	}
+
+	errConflictingEncryption = func(prefix string) *probe.Error {
+		return probe.NewError(errors.New("More than one encryption type specified for ‘" + prefix + "’.")).Untrace()
+	}
+
+	errInvalidTag = func(tag string) *probe.Error {
+		return probe.NewError(errors.New("Invalid tag ‘" + tag + "’, tags must be unique KEY=VALUE pairs, at most 10 per object.")).Untrace()
+	}
+
+	errInvalidACLGrant = func(grant string) *probe.Error {
+		return probe.NewError(errors.New("Invalid grant ‘" + grant + "’, grants must be PERMISSION=TYPE:GRANTEE with TYPE one of id, email, uri.")).Untrace()
+	}
+
+	errInvalidCannedACL = func(canned string) *probe.Error {
+		return probe.NewError(errors.New("Invalid canned ACL ‘" + canned + "’, valid values are " + strings.Join(cannedACLs, ", ") + ".")).Untrace()
+	}
+
+	errInvalidAttr = func(attr string) *probe.Error {
+		return probe.NewError(errors.New("Invalid attribute ‘" + attr + "’, attributes must be KEY=VALUE pairs separated by ‘;’.")).Untrace()
+	}
+
+	errInvalidContentLengthRange = func(lengthRange string) *probe.Error {
+		return probe.NewError(errors.New("Invalid content length range ‘" + lengthRange + "’, range must be MIN-MAX or an exact SIZE, e.g. 1KiB-10MiB.")).Untrace()
+	}
+
+	errInvalidShareHeader = func(header string) *probe.Error {
+		return probe.NewError(errors.New("Invalid header ‘" + header + "’, headers must be KEY=VALUE pairs.")).Untrace()
+	}
+
+	errAnonymousPresign = func(URL string) *probe.Error {
+		return probe.NewError(errors.New("Unable to presign ‘" + URL + "’ without credentials, please set access and secret keys of the host.")).Untrace()
+	}
+
+	errInvalidFilterPattern = func(pattern string) *probe.Error {
+		return probe.NewError(errors.New("Invalid pattern ‘" + pattern + "’, patterns must be globs like ‘*.tmp’, ‘.git/’ or ‘logs/**/*.gz’.")).Untrace()
+	}
+
+	errInvalidFilterAge = func(age string) *probe.Error {
+		return probe.NewError(errors.New("Invalid age ‘" + age + "’, ages must be durations like ‘90m’, ‘12h’, ‘7d’ or ‘1d12h’.")).Untrace()
+	}
+
+	errInvalidFilterSize = func(size string) *probe.Error {
+		return probe.NewError(errors.New("Invalid size ‘" + size + "’, sizes must be like ‘512KiB’ or ‘10MB’.")).Untrace()
+	}
+
+	errInvalidCompareMode = func(mode string) *probe.Error {
+		return probe.NewError(errors.New("Invalid compare mode ‘" + mode + "’, supported modes are ‘size’ and ‘checksum’.")).Untrace()
+	}
+
+	errMoveNotVerified = func(target string) *probe.Error {
+		return probe.NewError(errors.New("Size or checksum of ‘" + target + "’ does not match the source, the source was not removed.")).Untrace()
+	}
+
+	errInvalidMFA = func(alias string) *probe.Error {
+		return probe.NewError(errors.New("Invalid MFA of ‘" + alias + "’, it must be the serial number and current token of the device separated by a space.")).Untrace()
+	}
+
+	errInvalidPartSize = func(partSize string) *probe.Error {
+		return probe.NewError(errors.New("Invalid part size ‘" + partSize + "’, part size must be between 5MiB and 5GiB.")).Untrace()
+	}
+
+	errCopyConditionsUnsupported = func(URL string) *probe.Error {
+		return probe.NewError(errors.New("Copy conditions are only supported for server side copies, ‘" + URL + "’ would be streamed.")).Untrace()
+	}
+
+	errInvalidSparseSize = func(sparseSize string, size int64) *probe.Error {
+		return probe.NewError(errors.New("Sparse size ‘" + sparseSize + "’ is not the size " + strconv.FormatInt(size, 10) + " with trailing zero blocks added.")).Untrace()
+	}
+
 	errSourceTargetSame = func(URL string) *probe.Error {
 		return probe.NewError(errors.New("Source and target URL can not be same : " + URL)).Untrace()
 	}
+
+	errConflictingSymlinkFlags = func() *probe.Error {
+		return probe.NewError(errors.New("Only one of ‘--follow-symlinks’, ‘--skip-symlinks’ and ‘--preserve-links’ may be set.")).Untrace()
+	}
+
+	errStagingUnsupported = func(URL string) *probe.Error {
+		return probe.NewError(errors.New("Staging is only supported for targets on object storage, ‘" + URL + "’ is not.")).Untrace()
+	}
+
+	errStagingNotCommitted = func(prefix string) *probe.Error {
+		return probe.NewError(errors.New("Not all objects were copied, objects staged under ‘" + prefix + "’ were not committed.")).Untrace()
+	}
+
+	errInvalidGCSCredentials = func(credentials string) *probe.Error {
+		return probe.NewError(errors.New("Invalid credentials ‘" + credentials + "’, expected the JSON key file of a Google service account.")).Untrace()
+	}
+
+	errSFTPConnectFailed = func(host string) *probe.Error {
+		return probe.NewError(errors.New("Unable to start an SFTP session with ‘" + host + "’.")).Untrace()
+	}
+
+	errObjectTooLarge = func(size int64) *probe.Error {
+		return probe.NewError(errors.New("Object of " + humanize.IBytes(uint64(size)) + " exceeds the maximum of " + strconv.Itoa(s3MaxPartsCount) + " parts of " + humanize.IBytes(s3PartSizeUpperLimit) + ".")).Untrace()
+	}
+
+	errTooManyParts = func(partSize int64) *probe.Error {
+		return probe.NewError(errors.New("Stream exceeds the maximum of " + strconv.Itoa(s3MaxPartsCount) + " parts of " + humanize.IBytes(uint64(partSize)) + ", please increase the buffer size.")).Untrace()
+	}
+
+	errInvalidAWSConfigLine = func(line string) *probe.Error {
+		return probe.NewError(errors.New("Invalid line ‘" + line + "’ in AWS config, expected ‘key = value’.")).Untrace()
+	}
+
+	errAWSProfileNotFound = func(profile string) *probe.Error {
+		return probe.NewError(errors.New("AWS profile ‘" + profile + "’ not found.")).Untrace()
+	}
+
+	errAWSProfileUnsupported = func(profile, reason string) *probe.Error {
+		return probe.NewError(errors.New("AWS profile ‘" + profile + "’ can not be imported, " + reason + ".")).Untrace()
+	}
+
+	errInvalidCredentialProcessOutput = func(command, reason string) *probe.Error {
+		return probe.NewError(errors.New("Invalid output of credential process ‘" + command + "’, " + reason + ".")).Untrace()
+	}
+
+	errWrongConfigPassphrase = func() *probe.Error {
+		return probe.NewError(errors.New("Wrong passphrase of the config.")).Untrace()
+	}
+
+	errConfigLocked = func() *probe.Error {
+		return probe.NewError(errors.New("Config is encrypted, please set its passphrase in ‘MC_CONFIG_PASSPHRASE’.")).Untrace()
+	}
+
+	errInvalidCACert = func(caCert string) *probe.Error {
+		return probe.NewError(errors.New("No PEM encoded certificates found in CA bundle ‘" + caCert + "’.")).Untrace()
+	}
+
+	errInvalidClientCert = func(cert, key string) *probe.Error {
+		return probe.NewError(errors.New("Client certificate ‘" + cert + "’ and key ‘" + key + "’ must be set together.")).Untrace()
+	}
+
+	errInvalidEndpoint = func(reason string) *probe.Error {
+		return probe.NewError(errors.New("Invalid host set with ‘--endpoint-url’, " + reason + ".")).Untrace()
+	}
+
+	errInvalidTransportOpts = func() *probe.Error {
+		return probe.NewError(errors.New("Timeouts and idle connections of HTTP transports can not be negative.")).Untrace()
+	}
+
+	errInvalidFindExpression = func(flag, value string) *probe.Error {
+		return probe.NewError(errors.New("Invalid expression ‘" + flag + " " + value + "’.")).Untrace()
+	}
+
+	errObjectTruncated = func(URL string) *probe.Error {
+		return probe.NewError(errors.New("Object ‘" + URL + "’ was truncated.")).Untrace()
+	}
+
+	errNoMatchingObjects = func(URL string) *probe.Error {
+		return probe.NewError(errors.New("No objects match ‘" + URL + "’.")).Untrace()
+	}
+
+	errUndoConflict = func(URL string) *probe.Error {
+		return probe.NewError(errors.New("Object ‘" + URL + "’ was changed since, its previous version cannot be restored.")).Untrace()
+	}
+
+	errInvalidOutputFormat = func(format string) *probe.Error {
+		return probe.NewError(errors.New("Output format ‘" + format + "’ is not supported.")).Untrace()
+	}
+
+	errNotConfirmed = func() *probe.Error {
+		return probe.NewError(errors.New("Operation was not confirmed.")).Untrace()
+	}
+
+	errUnsupportedLang = func(lang string, langs []string) *probe.Error {
+		return probe.NewError(errors.New("Unsupported language ‘" + lang + "’, supported languages are " + strings.Join(langs, ", ") + ".")).Untrace()
+	}
+
+	errInvalidLogLevel = func(level string) *probe.Error {
+		return probe.NewError(errors.New("Invalid log level ‘" + level + "’, supported levels are " + strings.Join(logLevelNames, ", ") + ".")).Untrace()
+	}
+
+	errInvalidMemoryLimit = func(limit string) *probe.Error {
+		return probe.NewError(errors.New("Memory limit ‘" + limit + "’ is not a valid size, e.g. ‘512MiB’.")).Untrace()
+	}
+
+	errInvalidCacheTTL = func(ttl string) *probe.Error {
+		return probe.NewError(errors.New("Cache TTL ‘" + ttl + "’ is not a valid duration, e.g. ‘10m’.")).Untrace()
+	}
+
+	errInvalidWebhook = func(urlStr string) *probe.Error {
+		return probe.NewError(errors.New("Webhook ‘" + urlStr + "’ is not a valid HTTP(S) URL.")).Untrace()
+	}
+
+	errWebhookFailed = func(urlStr, status string) *probe.Error {
+		return probe.NewError(errors.New("Webhook ‘" + urlStr + "’ responded with ‘" + status + "’.")).Untrace()
+	}
+
+	errInvalidWatchEvent = func(event string) *probe.Error {
+		return probe.NewError(errors.New("Event ‘" + event + "’ is not valid, use ‘put’, ‘delete’, ‘get’, ‘lifecycle’, ‘replication’ or names like ‘s3:ObjectCreated:Put’.")).Untrace()
+	}
+
+	errInvalidKeyRegexp = func(expr string) *probe.Error {
+		return probe.NewError(errors.New("Key regular expression ‘" + expr + "’ is not valid.")).Untrace()
+	}
+
+	errWatchDropped = func(bucket string) *probe.Error {
+		return probe.NewError(errors.New("Listening on events of bucket ‘" + bucket + "’ stopped.")).Untrace()
+	}
+
+	errNoEventJournal = func(urlStr string) *probe.Error {
+		return probe.NewError(errors.New("No events of ‘" + urlStr + "’ were recorded, please watch it with ‘--journal’.")).Untrace()
+	}
+
+	errInvalidNotificationConfig = func(reason string) *probe.Error {
+		return probe.NewError(errors.New("Notification configuration is not valid, " + reason + ".")).Untrace()
+	}
+
+	errInvalidExecCommand = func(command, reason string) *probe.Error {
+		return probe.NewError(errors.New("Command ‘" + command + "’ is not valid, " + reason + ".")).Untrace()
+	}
+
+	errInvalidArn = func(arn, reason string) *probe.Error {
+		return probe.NewError(errors.New("ARN ‘" + arn + "’ is not valid, " + reason + ".")).Untrace()
+	}
+
+	errNotificationRejected = func(arns, reason string) *probe.Error {
+		return probe.NewError(errors.New("Notification of ‘" + arns + "’ was rejected: " + reason + " Please check that the target is configured on the server, in the region of the ARN.")).Untrace()
+	}
 )
@@ -0,0 +1,186 @@
+/*
+ * Minio Client (C) 2016 Minio, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/fatih/color"
+	"github.com/minio/cli"
+	"github.com/minio/mc/pkg/console"
+	"github.com/minio/minio/pkg/probe"
+)
+
+var (
+	undoFlags = []cli.Flag{
+		cli.BoolFlag{
+			Name:  "help, h",
+			Usage: "Help of undo.",
+		},
+		cli.BoolFlag{
+			Name:  "fake",
+			Usage: "Only show which objects would be restored.",
+		},
+	}
+)
+
+// Undo removals and overwrites in versioned buckets.
+var undoCmd = cli.Command{
+	Name:   "undo",
+	Usage:  "Restore objects removed or overwritten in versioned buckets.",
+	Action: mainUndo,
+	Flags:  append(undoFlags, globalFlags...),
+	CustomHelpTemplate: `NAME:
+   mc {{.Name}} - {{.Usage}}
+
+USAGE:
+   mc {{.Name}} [FLAGS] SESSION-ID
+
+   ‘rm’ and ‘cp’ record the objects they remove or write in buckets with versioning enabled to
+   a session, listed by ‘mc session list’. Undoing it removes the delete markers and versions
+   left by these operations, objects removed since a copy or written since a removal are not
+   restored. Undoing a copy removes the latest version of the object, a new object is removed.
+
+FLAGS:
+  {{range .Flags}}{{.}}
+  {{end}}
+EXAMPLES:
+   1. Restore the objects removed by a recursive rm.
+      $ mc {{.Name}} ygVIpSJs
+
+   2. Show which objects would be restored, without restoring them.
+      $ mc {{.Name}} --fake ygVIpSJs
+`,
+}
+
+// undoMessage container for restored objects.
+type undoMessage struct {
+	Status    string `json:"status"`
+	Op        string `json:"operation"`
+	URL       string `json:"url"`
+	VersionID string `json:"versionId"`
+}
+
+// String colorized undo message.
+func (u undoMessage) String() string {
+	if u.Op == undoOpRemove {
+		return console.Colorize("Undo", fmt.Sprintf("Restored ‘%s’, removed delete marker ‘%s’.", u.URL, u.VersionID))
+	}
+	return console.Colorize("Undo", fmt.Sprintf("Restored ‘%s’, removed version ‘%s’.", u.URL, u.VersionID))
+}
+
+// JSON jsonified undo message.
+func (u undoMessage) JSON() string {
+	u.Status = "success"
+	undoJSONBytes, e := json.Marshal(u)
+	fatalIf(probe.NewError(e), "Unable to marshal into JSON.")
+
+	return string(undoJSONBytes)
+}
+
+// checkUndoSyntax - validate all the passed arguments
+func checkUndoSyntax(ctx *cli.Context) {
+	if len(ctx.Args()) != 1 || strings.TrimSpace(ctx.Args().First()) == "" {
+		cli.ShowCommandHelpAndExit(ctx, "undo", 1) // last argument is exit code
+	}
+	sid := strings.TrimSpace(ctx.Args().First())
+	if !isSessionExists(sid) {
+		fatalIf(errDummy().Trace(sid), "Session ‘"+sid+"’ not found.")
+	}
+}
+
+// loadUndoEntries - operations recorded in the session, in the order
+// they were done.
+func loadUndoEntries(s *sessionV8) ([]undoEntry, *probe.Error) {
+	var entries []undoEntry
+	scanner := bufio.NewScanner(s.NewDataReader())
+	for scanner.Scan() {
+		var entry undoEntry
+		if e := json.Unmarshal(scanner.Bytes(), &entry); e != nil {
+			return nil, probe.NewError(e).Trace(s.SessionID)
+		}
+		entries = append(entries, entry)
+	}
+	if e := scanner.Err(); e != nil {
+		return nil, probe.NewError(e).Trace(s.SessionID)
+	}
+	return entries, nil
+}
+
+// mainUndo is the entry point for undo command.
+func mainUndo(ctx *cli.Context) {
+	// Set global flags from context.
+	setGlobalsFromContext(ctx)
+
+	// check 'undo' cli arguments.
+	checkUndoSyntax(ctx)
+
+	// Additional command specific theme customization.
+	console.SetColor("Undo", color.New(color.FgGreen, color.Bold))
+
+	isFake := ctx.Bool("fake")
+	sid := strings.TrimSpace(ctx.Args().First())
+	s, err := loadSessionV8(sid)
+	fatalIf(err.Trace(sid), "Unable to load session.")
+	if s.Header.CommandType != "undo" {
+		s.Close()
+		fatalIf(errInvalidArgument().Trace(sid), "Session ‘"+sid+"’ did not record any operations to undo.")
+	}
+	entries, err := loadUndoEntries(s)
+	if err != nil {
+		s.Close()
+		fatalIf(err.Trace(sid), "Unable to read session ‘"+sid+"’.")
+	}
+
+	// Latest operations are undone first, entries which could not be
+	// undone are kept in the session to retry.
+	var failed []undoEntry
+	for i := len(entries) - 1; i >= 0; i-- {
+		entry := entries[i]
+		clnt, err := newClientFromAlias(entry.Alias, entry.URL)
+		if err == nil {
+			var versionID string
+			if versionID, err = undoOperation(clnt, entry, isFake); err == nil {
+				printMsg(undoMessage{Op: entry.Op, URL: entry.URL, VersionID: versionID})
+				continue
+			}
+		}
+		errorIf(err.Trace(entry.URL), "Unable to restore ‘"+entry.URL+"’.")
+		failed = append([]undoEntry{entry}, failed...)
+	}
+
+	if isFake {
+		fatalIf(s.Close().Trace(sid), "Unable to close session file properly.")
+		return
+	}
+	if len(failed) == 0 {
+		fatalIf(s.Delete().Trace(sid), "Unable to clear session files properly.")
+		return
+	}
+	writer := s.NewDataWriter()
+	for _, entry := range failed {
+		entryBytes, e := json.Marshal(entry)
+		fatalIf(probe.NewError(e), "Unable to marshal into JSON.")
+		_, e = writer.Write(append(entryBytes, '\n'))
+		fatalIf(probe.NewError(e), "Unable to update session ‘"+sid+"’.")
+	}
+	fatalIf(s.Save().Trace(sid), "Unable to save session ‘"+sid+"’.")
+	fatalIf(s.Close().Trace(sid), "Unable to close session file properly.")
+}
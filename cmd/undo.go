@@ -0,0 +1,209 @@
+/*
+ * Minio Client (C) 2016 Minio, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"encoding/json"
+	"path/filepath"
+	"sync"
+
+	"github.com/minio/minio/pkg/probe"
+)
+
+// Operations recorded by undo sessions.
+const (
+	undoOpRemove = "rm"
+	undoOpPut    = "put"
+)
+
+// undoEntry - an operation on an object of a versioned bucket, a
+// removal left a delete marker, a put wrote the version VersionID.
+// Puts are recorded without their version, which is the latest one
+// when they are undone.
+type undoEntry struct {
+	Op        string `json:"op"`
+	Alias     string `json:"alias"`
+	URL       string `json:"url"`
+	VersionID string `json:"versionId,omitempty"`
+}
+
+// undoJournal - records objects removed or overwritten in buckets with
+// versioning enabled to a session of type ‘undo’, so that ‘mc undo’
+// can restore their previous versions. The session is only created
+// once the first operation is recorded. A nil journal records nothing.
+type undoJournal struct {
+	mutex   *sync.Mutex
+	command string
+	args    []string
+	session *sessionV8
+	// Versioning status of buckets, keyed by alias and bucket.
+	versioned map[string]bool
+}
+
+// newUndoJournal - journal of a command run with the given arguments.
+func newUndoJournal(command string, args []string) *undoJournal {
+	return &undoJournal{
+		mutex:     &sync.Mutex{},
+		command:   command,
+		args:      args,
+		versioned: make(map[string]bool),
+	}
+}
+
+// versionedClient - client of an object in a bucket with versioning
+// enabled, nil for all other objects. Buckets whose versioning can not
+// be read are taken as not versioned, it is only read once per bucket.
+func (j *undoJournal) versionedClient(alias, urlStr string) (*s3Client, *probe.Error) {
+	clnt, err := newClientFromAlias(alias, urlStr)
+	if err != nil {
+		return nil, err.Trace(urlStr)
+	}
+	s3Clnt, ok := clnt.(*s3Client)
+	if !ok {
+		return nil, nil
+	}
+	bucket, _ := s3Clnt.url2BucketAndObject()
+	key := alias + "/" + bucket
+
+	j.mutex.Lock()
+	versioned, ok := j.versioned[key]
+	j.mutex.Unlock()
+	if !ok {
+		// Objects of suspended buckets overwrite their null version.
+		versioning, err := s3Clnt.GetVersioning()
+		versioned = err == nil && versioning.Status == "Enabled"
+		j.mutex.Lock()
+		j.versioned[key] = versioned
+		j.mutex.Unlock()
+	}
+	if !versioned {
+		return nil, nil
+	}
+	return s3Clnt, nil
+}
+
+// recordRemove - records the removal of an object.
+func (j *undoJournal) recordRemove(alias, urlStr string) *probe.Error {
+	if j == nil {
+		return nil
+	}
+	clnt, err := j.versionedClient(alias, urlStr)
+	if err != nil || clnt == nil {
+		return err
+	}
+	return j.record(undoEntry{Op: undoOpRemove, Alias: alias, URL: urlStr})
+}
+
+// recordPut - records an object just written.
+func (j *undoJournal) recordPut(alias, urlStr string) *probe.Error {
+	if j == nil {
+		return nil
+	}
+	clnt, err := j.versionedClient(alias, urlStr)
+	if err != nil || clnt == nil {
+		return err
+	}
+	return j.record(undoEntry{Op: undoOpPut, Alias: alias, URL: urlStr})
+}
+
+// record - appends an entry to the session data.
+func (j *undoJournal) record(entry undoEntry) *probe.Error {
+	entryBytes, e := json.Marshal(entry)
+	if e != nil {
+		return probe.NewError(e)
+	}
+
+	j.mutex.Lock()
+	defer j.mutex.Unlock()
+	if j.session == nil {
+		if !isSessionDirExists() {
+			if err := createSessionDir(); err != nil {
+				return err.Trace()
+			}
+		}
+		j.session = newSessionV8()
+		j.session.Header.CommandType = "undo"
+		j.session.Header.CommandArgs = append([]string{j.command}, j.args...)
+		if err := j.session.Save(); err != nil {
+			return err.Trace(j.session.SessionID)
+		}
+	}
+	if _, e = j.session.DataFP.Write(append(entryBytes, '\n')); e != nil {
+		return probe.NewError(e)
+	}
+	return nil
+}
+
+// Close - closes the session of recorded operations, if any, telling
+// how to undo them.
+func (j *undoJournal) Close() *probe.Error {
+	if j == nil || j.session == nil {
+		return nil
+	}
+	if err := j.session.Save(); err != nil {
+		return err.Trace(j.session.SessionID)
+	}
+	if err := j.session.Close(); err != nil {
+		return err.Trace(j.session.SessionID)
+	}
+	if !globalQuiet {
+		printInfo("Previous versions of removed and overwritten objects can be restored with ‘mc undo " + j.session.SessionID + "’.")
+	}
+	return nil
+}
+
+// latestVersion - the latest version or delete marker of the object,
+// nil if the object has no versions.
+func latestVersion(clnt Client) (*ClientContent, *probe.Error) {
+	objectPath := filepath.Clean(clnt.GetURL().Path)
+	for content := range clnt.ListVersions(false) {
+		if content.Err != nil {
+			return nil, content.Err.Trace(clnt.GetURL().String())
+		}
+		if content.IsLatest && content.URL.Path == objectPath {
+			return content, nil
+		}
+	}
+	return nil, nil
+}
+
+// undoOperation - restores the version of an object preceding the
+// operation by removing the delete marker or the version it left,
+// which have to still be the latest. Puts recorded with their version
+// only remove that version. Returns the removed version.
+func undoOperation(clnt Client, entry undoEntry, isFake bool) (string, *probe.Error) {
+	latest, err := latestVersion(clnt)
+	if err != nil {
+		return "", err.Trace(entry.URL)
+	}
+	switch {
+	case latest == nil:
+		return "", errUndoConflict(entry.URL).Trace(entry.URL)
+	case entry.Op == undoOpRemove && !latest.IsDeleteMarker:
+		return "", errUndoConflict(entry.URL).Trace(entry.URL)
+	case entry.Op == undoOpPut && latest.IsDeleteMarker:
+		return "", errUndoConflict(entry.URL).Trace(entry.URL)
+	case entry.Op == undoOpPut && entry.VersionID != "" && latest.VersionID != entry.VersionID:
+		return "", errUndoConflict(entry.URL).Trace(entry.URL, entry.VersionID)
+	}
+	if !isFake {
+		if err = clnt.RemoveVersion(latest.VersionID); err != nil {
+			return "", err.Trace(entry.URL, latest.VersionID)
+		}
+	}
+	return latest.VersionID, nil
+}
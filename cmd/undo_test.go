@@ -0,0 +1,163 @@
+/*
+ * Minio Client (C) 2016 Minio, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+
+	"github.com/minio/minio/pkg/probe"
+	. "gopkg.in/check.v1"
+)
+
+// undoHandler - serves versions of a versioned bucket, where ‘a’ was
+// removed and ‘b’ overwritten, and records removed versions. Versioning
+// of bucket ‘denied’ can not be read.
+type undoHandler struct {
+	mutex   *sync.Mutex
+	removed *[]string
+	// Methods and paths of all other requests.
+	requests *[]string
+}
+
+func (h undoHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+	if len(query["location"]) == 0 && len(query["versions"]) == 0 && len(query["versionId"]) == 0 && h.requests != nil {
+		h.mutex.Lock()
+		*h.requests = append(*h.requests, r.Method+" "+r.URL.Path)
+		h.mutex.Unlock()
+	}
+	switch {
+	case len(query["location"]) == 1:
+		w.Write([]byte("<LocationConstraint xmlns=\"http://doc.s3.amazonaws.com/2006-03-01\"></LocationConstraint>"))
+	case len(query["versioning"]) == 1 && strings.HasPrefix(r.URL.Path, "/denied"):
+		w.WriteHeader(http.StatusForbidden)
+		w.Write([]byte("<Error><Code>AccessDenied</Code><Message>Access Denied.</Message></Error>"))
+	case len(query["versioning"]) == 1:
+		w.Write([]byte("<VersioningConfiguration><Status>Enabled</Status></VersioningConfiguration>"))
+	case len(query["versions"]) == 1:
+		w.Write([]byte(`<ListVersionsResult><IsTruncated>false</IsTruncated>` +
+			`<Version><Key>a</Key><VersionId>v1</VersionId><IsLatest>false</IsLatest><LastModified>2016-09-01T10:00:00.000Z</LastModified><Size>1</Size></Version>` +
+			`<Version><Key>ab</Key><VersionId>v2</VersionId><IsLatest>true</IsLatest><LastModified>2016-09-01T10:00:00.000Z</LastModified><Size>1</Size></Version>` +
+			`<Version><Key>b</Key><VersionId>v4</VersionId><IsLatest>true</IsLatest><LastModified>2016-09-03T10:00:00.000Z</LastModified><Size>1</Size></Version>` +
+			`<Version><Key>b</Key><VersionId>v5</VersionId><IsLatest>false</IsLatest><LastModified>2016-08-01T10:00:00.000Z</LastModified><Size>1</Size></Version>` +
+			`<DeleteMarker><Key>a</Key><VersionId>v3</VersionId><IsLatest>true</IsLatest><LastModified>2016-09-02T10:00:00.000Z</LastModified></DeleteMarker>` +
+			`</ListVersionsResult>`))
+	case r.Method == "DELETE" && len(query["versionId"]) == 1:
+		h.mutex.Lock()
+		*h.removed = append(*h.removed, r.URL.Path+"?"+query.Get("versionId"))
+		h.mutex.Unlock()
+		w.WriteHeader(http.StatusNoContent)
+	default:
+		w.WriteHeader(http.StatusBadRequest)
+	}
+}
+
+// Test undoing removals and overwrites of objects.
+func (s *TestSuite) TestUndoOperation(c *C) {
+	var removed []string
+	server := httptest.NewServer(undoHandler{mutex: &sync.Mutex{}, removed: &removed})
+	defer server.Close()
+
+	newObjectClient := func(object string) Client {
+		conf := new(Config)
+		conf.HostURL = server.URL + "/bucket/" + object
+		conf.AccessKey = "WLGDGYAQYIGI833EV05A"
+		conf.SecretKey = "BYvgJM101sHngl2uzjXS/OBF/aMxAN06JrJ3qJlF"
+		conf.Signature = "S3v4"
+		clnt, err := s3New(conf)
+		c.Assert(err, IsNil)
+		return clnt
+	}
+
+	// Nothing is removed while faking.
+	versionID, err := undoOperation(newObjectClient("a"), undoEntry{Op: undoOpRemove, URL: "a"}, true)
+	c.Assert(err, IsNil)
+	c.Assert(versionID, Equals, "v3")
+	c.Assert(removed, HasLen, 0)
+
+	// The delete marker of a removed object is removed.
+	versionID, err = undoOperation(newObjectClient("a"), undoEntry{Op: undoOpRemove, URL: "a"}, false)
+	c.Assert(err, IsNil)
+	c.Assert(versionID, Equals, "v3")
+
+	// The version written by an overwrite is removed.
+	versionID, err = undoOperation(newObjectClient("b"), undoEntry{Op: undoOpPut, URL: "b", VersionID: "v4"}, false)
+	c.Assert(err, IsNil)
+	c.Assert(versionID, Equals, "v4")
+	c.Assert(removed, DeepEquals, []string{"/bucket/a?v3", "/bucket/b?v4"})
+
+	// Objects changed since are not restored.
+	_, err = undoOperation(newObjectClient("b"), undoEntry{Op: undoOpRemove, URL: "b"}, false)
+	c.Assert(err, NotNil)
+	_, err = undoOperation(newObjectClient("b"), undoEntry{Op: undoOpPut, URL: "b", VersionID: "v5"}, false)
+	c.Assert(err, NotNil)
+	_, err = undoOperation(newObjectClient("c"), undoEntry{Op: undoOpRemove, URL: "c"}, false)
+	c.Assert(err, NotNil)
+	c.Assert(removed, HasLen, 2)
+
+	// Puts recorded without their version remove the latest version,
+	// unless it is a delete marker.
+	versionID, err = undoOperation(newObjectClient("b"), undoEntry{Op: undoOpPut, URL: "b"}, true)
+	c.Assert(err, IsNil)
+	c.Assert(versionID, Equals, "v4")
+	_, err = undoOperation(newObjectClient("a"), undoEntry{Op: undoOpPut, URL: "a"}, true)
+	c.Assert(err, NotNil)
+}
+
+// Test recording operations on objects of versioned buckets.
+func (s *TestSuite) TestUndoJournal(c *C) {
+	var removed, requests []string
+	server := httptest.NewServer(undoHandler{mutex: &sync.Mutex{}, removed: &removed, requests: &requests})
+	defer server.Close()
+
+	defer func(load func() (*configV8, *probe.Error)) { loadMcConfig = load }(loadMcConfig)
+	loadMcConfig = func() (*configV8, *probe.Error) {
+		conf := newMcConfig()
+		conf.Hosts["s3"] = hostConfigV8{
+			URL:       server.URL,
+			AccessKey: "WLGDGYAQYIGI833EV05A",
+			SecretKey: "BYvgJM101sHngl2uzjXS/OBF/aMxAN06JrJ3qJlF",
+			API:       "S3v4",
+			Lookup:    "path",
+		}
+		return conf, nil
+	}
+
+	// Versioning is read once per bucket, failures to read it are
+	// taken as not versioned.
+	journal := newUndoJournal("cp", nil)
+	for _, object := range []string{"a", "b"} {
+		clnt, err := journal.versionedClient("s3", server.URL+"/denied/"+object)
+		c.Assert(err, IsNil)
+		c.Assert(clnt, IsNil)
+		clnt, err = journal.versionedClient("s3", server.URL+"/bucket/"+object)
+		c.Assert(err, IsNil)
+		c.Assert(clnt, NotNil)
+	}
+	c.Assert(requests, DeepEquals, []string{"GET /denied/", "GET /bucket/"})
+
+	// Objects written are recorded without further requests.
+	c.Assert(journal.recordPut("s3", server.URL+"/denied/a"), IsNil)
+	c.Assert(journal.session, IsNil)
+	c.Assert(journal.recordPut("s3", server.URL+"/bucket/a"), IsNil)
+	c.Assert(journal.session, NotNil)
+	defer journal.session.Delete()
+	c.Assert(requests, HasLen, 2)
+}
@@ -145,9 +145,19 @@ func getReleaseUpdate(updateURL string) (updateMsg updateMessage, errMsg string,
 	newUpdateURLPrefix := updateURL + "/" + runtime.GOOS + "-" + runtime.GOARCH
 	newUpdateURL := newUpdateURLPrefix + "/mc.shasum"
 
-	// Instantiate a new client with 3 sec timeout.
+	// Instantiate a new client with 3 sec timeout, sharing the
+	// transport of object storage clients.
+	opts, err := getTransportOpts()
+	if err != nil {
+		return updateMessage{}, "Unable to parse HTTP transport settings.", err.Trace(updateURL)
+	}
+	transport, err := newClientTransport(&Config{HostURL: updateURL, Insecure: globalInsecure, Transport: opts})
+	if err != nil {
+		return updateMessage{}, "Unable to set up HTTP transport.", err.Trace(updateURL)
+	}
 	client := &http.Client{
-		Timeout: 3 * time.Second,
+		Transport: transport,
+		Timeout:   3 * time.Second,
 	}
 
 	// Get the downloadURL.
@@ -21,9 +21,9 @@ import "github.com/minio/minio/pkg/probe"
 // URLs contains source and target urls
 type URLs struct {
 	SourceAlias   string
-	SourceContent *clientContent
+	SourceContent *ClientContent
 	TargetAlias   string
-	TargetContent *clientContent
+	TargetContent *ClientContent
 	Error         *probe.Error `json:"-"`
 }
 
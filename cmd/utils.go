@@ -21,10 +21,11 @@ import (
 	"io"
 	"math/rand"
 	"os"
+	"os/exec"
 	"path/filepath"
+	"runtime"
+	"strings"
 	"time"
-
-	"github.com/minio/mc/pkg/console"
 )
 
 var letters = []rune("abcdefghijklmnopqrstuvwxyzABCDEFGHIJKLMNOPQRSTUVWXYZ")
@@ -50,15 +51,31 @@ func isBucketVirtualStyle(host string) bool {
 // Fields will be inspected by the user, so they must be conscise and useful
 func dumpTLSCertificates(t *tls.ConnectionState) {
 	for _, cert := range t.PeerCertificates {
-		console.Debugln("TLS Certificate found: ")
+		var country, organization string
 		if len(cert.Issuer.Country) > 0 {
-			console.Debugln(" >> Country: " + cert.Issuer.Country[0])
+			country = cert.Issuer.Country[0]
 		}
 		if len(cert.Issuer.Organization) > 0 {
-			console.Debugln(" >> Organization: " + cert.Issuer.Organization[0])
+			organization = cert.Issuer.Organization[0]
 		}
-		console.Debugln(" >> Expires: " + cert.NotAfter.String())
+		logDebug("TLS certificate found.", "country", country, "organization", organization, "expires", cert.NotAfter)
+	}
+}
+
+// shellCommand - command run through the shell of the platform.
+func shellCommand(command string) *exec.Cmd {
+	if runtime.GOOS == "windows" {
+		return exec.Command("cmd", "/C", command)
+	}
+	return exec.Command("/bin/sh", "-c", command)
+}
+
+// shellQuote - quotes the argument for the shell of the platform.
+func shellQuote(arg string) string {
+	if runtime.GOOS == "windows" {
+		return `"` + strings.Replace(arg, `"`, `""`, -1) + `"`
 	}
+	return "'" + strings.Replace(arg, "'", `'\''`, -1) + "'"
 }
 
 // isStdIO checks if the input parameter is one of the standard input/output streams
@@ -0,0 +1,67 @@
+/*
+ * Minio Client (C) 2016 Minio, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"github.com/fatih/color"
+	"github.com/minio/cli"
+	"github.com/minio/mc/pkg/console"
+)
+
+var versioningInfoCmd = cli.Command{
+	Name:   "info",
+	Usage:  "Show versioning of a bucket.",
+	Action: mainVersioningInfo,
+	Flags:  globalFlags,
+	CustomHelpTemplate: `NAME:
+   mc versioning {{.Name}} - {{.Usage}}
+
+USAGE:
+   mc versioning {{.Name}} ALIAS/BUCKET [FLAGS]
+
+FLAGS:
+  {{range .Flags}}{{.}}
+  {{end}}
+EXAMPLES:
+   1. Show versioning and MFA delete of a bucket.
+     $ mc versioning {{.Name}} s3/records
+`,
+}
+
+// checkVersioningInfoSyntax - validate all the passed arguments
+func checkVersioningInfoSyntax(ctx *cli.Context) {
+	if len(ctx.Args()) != 1 {
+		cli.ShowCommandHelpAndExit(ctx, "info", 1) // last argument is exit code
+	}
+}
+
+func mainVersioningInfo(ctx *cli.Context) {
+	console.SetColor("Versioning", color.New(color.FgCyan, color.Bold))
+
+	setGlobalsFromContext(ctx)
+	checkVersioningInfoSyntax(ctx)
+
+	path := ctx.Args().First()
+
+	client, err := newS3Client(path)
+	fatalIf(err, "Unable to initialize ‘"+path+"’, versioning is only supported on object storage.")
+
+	versioning, err := client.GetVersioning()
+	fatalIf(err.Trace(path), "Unable to get versioning of ‘"+path+"’.")
+
+	printMsg(versioningMessage{Target: path, Versioning: versioning.Status, MFADelete: versioning.MFADelete})
+}
@@ -0,0 +1,70 @@
+/*
+ * Minio Client (C) 2016 Minio, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import "github.com/minio/cli"
+
+var (
+	versioningFlags = []cli.Flag{
+		cli.BoolFlag{
+			Name:  "help, h",
+			Usage: "Help of versioning.",
+		},
+	}
+)
+
+var versioningCmd = cli.Command{
+	Name:   "versioning",
+	Usage:  "Manage versioning of buckets.",
+	Action: mainVersioning,
+	Flags:  append(versioningFlags, globalFlags...),
+	Subcommands: []cli.Command{
+		versioningSetCmd,
+		versioningInfoCmd,
+	},
+	CustomHelpTemplate: `NAME:
+   {{.Name}} - {{.Usage}}
+
+USAGE:
+   {{.Name}} [FLAGS] COMMAND
+
+FLAGS:
+  {{range .Flags}}{{.}}
+  {{end}}
+COMMANDS:
+   {{range .Commands}}{{join .Names ", "}}{{ "\t" }}{{.Usage}}
+   {{end}}
+ENVIRONMENT VARIABLES:
+   MC_MFA_<ALIAS>: Serial number and current token of the MFA device of buckets with MFA delete, separated by a space.
+                   Overrides the MFA set with ‘mc config host add --mfa’.
+`,
+}
+
+// mainVersioning is the handle for "mc versioning" command.
+func mainVersioning(ctx *cli.Context) {
+	// Set global flags from context.
+	setGlobalsFromContext(ctx)
+
+	if ctx.Args().First() != "" { // command help.
+		cli.ShowCommandHelp(ctx, ctx.Args().First())
+	} else {
+		// command with Subcommands is an App.
+		cli.ShowAppHelp(ctx)
+	}
+
+	// Sub-commands like "set", "info" have their own main.
+}
@@ -0,0 +1,137 @@
+/*
+ * Minio Client (C) 2016 Minio, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"encoding/json"
+	"strings"
+
+	"github.com/fatih/color"
+	"github.com/minio/cli"
+	"github.com/minio/mc/pkg/console"
+	"github.com/minio/minio/pkg/probe"
+)
+
+var (
+	versioningSetFlags = []cli.Flag{
+		cli.StringFlag{
+			Name:  "mfa-delete",
+			Usage: "Turn MFA delete ‘enabled’ or ‘disabled’, requires the MFA of the bucket owner.",
+		},
+	}
+)
+
+var versioningSetCmd = cli.Command{
+	Name:   "set",
+	Usage:  "Enable or suspend versioning of a bucket.",
+	Action: mainVersioningSet,
+	Flags:  append(versioningSetFlags, globalFlags...),
+	CustomHelpTemplate: `NAME:
+   mc versioning {{.Name}} - {{.Usage}}
+
+USAGE:
+   mc versioning {{.Name}} ALIAS/BUCKET enabled|suspended [FLAGS]
+
+FLAGS:
+  {{range .Flags}}{{.}}
+  {{end}}
+EXAMPLES:
+   1. Enable versioning of a bucket.
+     $ mc versioning {{.Name}} s3/records enabled
+   2. Enable versioning and MFA delete of a bucket.
+     $ MC_MFA_s3="arn:aws:iam::123456789012:mfa/root-account-mfa-device 123456" mc versioning {{.Name}} --mfa-delete enabled s3/records enabled
+   3. Suspend versioning of a bucket with MFA delete enabled.
+     $ MC_MFA_s3="arn:aws:iam::123456789012:mfa/root-account-mfa-device 654321" mc versioning {{.Name}} s3/records suspended
+`,
+}
+
+// parseVersioning - parses versioning and MFA delete states given on
+// the command line.
+func parseVersioning(status, mfaDelete string) (versioningConfiguration, *probe.Error) {
+	versioning := versioningConfiguration{}
+	switch strings.ToLower(status) {
+	case strings.ToLower(versioningEnabled):
+		versioning.Status = versioningEnabled
+	case strings.ToLower(versioningSuspended):
+		versioning.Status = versioningSuspended
+	default:
+		return versioningConfiguration{}, errInvalidArgument().Trace(status)
+	}
+	switch strings.ToLower(mfaDelete) {
+	case "":
+	case strings.ToLower(mfaDeleteEnabled):
+		versioning.MFADelete = mfaDeleteEnabled
+	case strings.ToLower(mfaDeleteDisabled):
+		versioning.MFADelete = mfaDeleteDisabled
+	default:
+		return versioningConfiguration{}, errInvalidArgument().Trace(mfaDelete)
+	}
+	return versioning, nil
+}
+
+// checkVersioningSetSyntax - validate all the passed arguments
+func checkVersioningSetSyntax(ctx *cli.Context) {
+	if len(ctx.Args()) != 2 {
+		cli.ShowCommandHelpAndExit(ctx, "set", 1) // last argument is exit code
+	}
+	_, err := parseVersioning(ctx.Args().Get(1), ctx.String("mfa-delete"))
+	fatalIf(err, "Versioning can only be ‘enabled’ or ‘suspended’, MFA delete ‘enabled’ or ‘disabled’.")
+}
+
+// versioningMessage container, shared by set and info.
+type versioningMessage struct {
+	Status     string `json:"status"`
+	Target     string `json:"target"`
+	Versioning string `json:"versioning,omitempty"`
+	MFADelete  string `json:"mfaDelete,omitempty"`
+}
+
+func (v versioningMessage) JSON() string {
+	v.Status = "success"
+	versioningMessageJSONBytes, e := json.Marshal(v)
+	fatalIf(probe.NewError(e), "Unable to marshal into JSON.")
+	return string(versioningMessageJSONBytes)
+}
+
+func (v versioningMessage) String() string {
+	if v.Versioning == "" {
+		return console.Colorize("Versioning", "Versioning of ‘"+v.Target+"’ was never enabled.")
+	}
+	msg := "Versioning of ‘" + v.Target + "’ is " + strings.ToLower(v.Versioning)
+	if v.MFADelete != "" {
+		msg += ", MFA delete is " + strings.ToLower(v.MFADelete)
+	}
+	return console.Colorize("Versioning", msg+".")
+}
+
+func mainVersioningSet(ctx *cli.Context) {
+	console.SetColor("Versioning", color.New(color.FgGreen, color.Bold))
+
+	setGlobalsFromContext(ctx)
+	checkVersioningSetSyntax(ctx)
+
+	path := ctx.Args().First()
+	versioning, _ := parseVersioning(ctx.Args().Get(1), ctx.String("mfa-delete"))
+
+	client, err := newS3Client(path)
+	fatalIf(err, "Unable to initialize ‘"+path+"’, versioning is only supported on object storage.")
+
+	err = client.SetVersioning(versioning)
+	fatalIf(err.Trace(path), "Unable to set versioning of ‘"+path+"’.")
+
+	printMsg(versioningMessage{Target: path, Versioning: versioning.Status, MFADelete: versioning.MFADelete})
+}
@@ -41,7 +41,7 @@ var (
 		cli.StringFlag{
 			Name:  "events",
 			Value: "put,delete",
-			Usage: "Filter specific type of events. Defaults to all events by default.",
+			Usage: "Filter specific type of events, ‘put’, ‘delete’, ‘get’, ‘lifecycle’, ‘replication’ or names like ‘s3:ObjectCreated:CompleteMultipartUpload’.",
 		},
 		cli.StringFlag{
 			Name:  "prefix",
@@ -51,6 +51,18 @@ var (
 			Name:  "suffix",
 			Usage: "Filter events for a suffix.",
 		},
+		cli.BoolFlag{
+			Name:  "journal",
+			Usage: "Record events in the mc config folder, to be replayed by ‘mc events replay’.",
+		},
+		cli.StringFlag{
+			Name:  "arn",
+			Usage: "Notify the ARN of events while watching, the bucket notification is removed on exit.",
+		},
+		cli.StringFlag{
+			Name:  "regex",
+			Usage: "Filter events for keys matching a regular expression, keys on filesystems are relative to the folder.",
+		},
 		cli.BoolFlag{
 			Name:  "recursive",
 			Usage: "Recursively watch for events.",
@@ -62,7 +74,7 @@ var watchCmd = cli.Command{
 	Name:   "watch",
 	Usage:  "Watch for events on object storage and filesystem.",
 	Action: mainWatch,
-	Flags:  append(watchFlags, globalFlags...),
+	Flags:  append(append(append(append(watchFlags, webhookFlags...), execHookFlags...), metricsFlags...), globalFlags...),
 	CustomHelpTemplate: `NAME:
    mc {{.Name}} - {{.Usage}}
 
@@ -87,6 +99,25 @@ EXAMPLES:
 
    5. Watch for events on local directory.
       $ mc {{.Name}} /usr/share
+
+   6. Watch for events, serving their counts to Prometheus at http://localhost:9100/metrics.
+      $ mc {{.Name}} --metrics-address localhost:9100 play/testbucket
+
+   7. Watch only for completed multipart uploads of keys ending in a date.
+      $ mc {{.Name}} play/testbucket --events "s3:ObjectCreated:CompleteMultipartUpload" --regex "[0-9]{8}$"
+
+   8. Notify a queue of new objects while watching.
+      $ mc {{.Name}} s3/testbucket --events put --arn arn:aws:sqs:us-west-2:444455556666:your-queue
+
+   9. Forward events to a webhook, signed with the secret in MC_WEBHOOK_SECRET.
+      $ export MC_WEBHOOK_SECRET=mysecret
+      $ mc {{.Name}} --webhook https://events.example.com/minio play/testbucket
+
+  10. Record events, so that they can be replayed to another bucket with ‘mc events replay’.
+      $ mc {{.Name}} --journal play/testbucket
+
+  11. Run a handler for every new object, at most 2 at once.
+      $ mc {{.Name}} --events put --exec "handler.sh {event} {url} {size}" --exec-concurrency 2 play/testbucket
 `,
 }
 
@@ -95,6 +126,18 @@ func checkWatchSyntax(ctx *cli.Context) {
 	if len(ctx.Args()) != 1 {
 		cli.ShowCommandHelpAndExit(ctx, "watch", 1) // last argument is exit code
 	}
+	if _, _, err := parseWatchEvents(strings.Split(ctx.String("events"), ",")); err != nil {
+		fatalIf(err.Trace(), "Unable to parse events.")
+	}
+	if _, err := parseKeyRegexp(ctx.String("regex")); err != nil {
+		fatalIf(err.Trace(), "Unable to parse key regular expression.")
+	}
+	if _, err := newWebhookRelay(ctx.String("webhook"), ctx.String("webhook-secret"), ctx.Int("webhook-retries")); err != nil {
+		fatalIf(err.Trace(), "Unable to parse webhook.")
+	}
+	if _, err := newExecHook(ctx.String("exec"), ctx.Int("exec-concurrency")); err != nil {
+		fatalIf(err.Trace(), "Unable to parse command of events.")
+	}
 }
 
 // watchMessage container to hold one event notification
@@ -122,11 +165,44 @@ func (u watchMessage) String() string {
 	return msg
 }
 
+// watchStatusMessage - change of the connection of a watch.
+type watchStatusMessage struct {
+	Status    string `json:"status"`
+	Connected bool   `json:"connected"`
+	Error     string `json:"error,omitempty"`
+	Retry     string `json:"retry,omitempty"`
+}
+
+// newWatchStatusMessage - message of the change of the connection.
+func newWatchStatusMessage(status WatchStatus) watchStatusMessage {
+	msg := watchStatusMessage{Connected: status.Connected}
+	if !status.Connected {
+		msg.Error = status.Err.ToGoError().Error()
+		msg.Retry = status.Delay.String()
+	}
+	return msg
+}
+
+func (u watchStatusMessage) JSON() string {
+	u.Status = "success"
+	watchStatusMessageJSONBytes, e := json.Marshal(u)
+	fatalIf(probe.NewError(e), "Unable to marshal into JSON.")
+	return string(watchStatusMessageJSONBytes)
+}
+
+func (u watchStatusMessage) String() string {
+	if u.Connected {
+		return console.Colorize("WatchStatus", "Reconnected, watching for events.")
+	}
+	return console.Colorize("WatchStatus", fmt.Sprintf("Lost connection, reconnecting in %s: %s", u.Retry, u.Error))
+}
+
 func mainWatch(ctx *cli.Context) {
 	console.SetColor("Time", color.New(color.FgGreen))
 	console.SetColor("Size", color.New(color.FgYellow))
 	console.SetColor("EventType", color.New(color.FgCyan, color.Bold))
 	console.SetColor("ObjectName", color.New(color.Bold))
+	console.SetColor("WatchStatus", color.New(color.FgRed, color.Bold))
 
 	setGlobalsFromContext(ctx)
 	checkWatchSyntax(ctx)
@@ -136,7 +212,10 @@ func mainWatch(ctx *cli.Context) {
 
 	prefix := ctx.String("prefix")
 	suffix := ctx.String("suffix")
-	events := strings.Split(ctx.String("events"), ",")
+	events, names, err := parseWatchEvents(strings.Split(ctx.String("events"), ","))
+	fatalIf(err, "Unable to parse events.")
+	keyRegexp, err := parseKeyRegexp(ctx.String("regex"))
+	fatalIf(err, "Unable to parse key regular expression.")
 	recursive := ctx.Bool("recursive")
 
 	s3Client, pErr := newClient(path)
@@ -144,14 +223,32 @@ func mainWatch(ctx *cli.Context) {
 		fatalIf(pErr.Trace(), "Cannot parse the provided url.")
 	}
 
-	params := watchParams{
-		recursive: recursive,
-		accountID: fmt.Sprintf("%d", time.Now().Unix()),
-		events:    events,
-		prefix:    prefix,
-		suffix:    suffix,
+	params := WatchParams{
+		Recursive: recursive,
+		AccountID: fmt.Sprintf("%d", time.Now().Unix()),
+		Events:    events,
+		Prefix:    prefix,
+		Suffix:    suffix,
+		Names:     names,
+		KeyRegexp: keyRegexp,
+		ARN:       ctx.String("arn"),
 	}
 
+	metricsAddress, statsdAddress := ctx.String("metrics-address"), ctx.String("statsd-address")
+	fatalIf(startMetrics(metricsAddress, statsdAddress).Trace(metricsAddress, statsdAddress), "Unable to expose metrics.")
+
+	relay, err := newWebhookRelay(ctx.String("webhook"), ctx.String("webhook-secret"), ctx.Int("webhook-retries"))
+	fatalIf(err, "Unable to parse webhook.")
+	relay.Start()
+
+	hook, err := newExecHook(ctx.String("exec"), ctx.Int("exec-concurrency"))
+	fatalIf(err, "Unable to parse command of events.")
+
+	alias, urlStr, _ := mustExpandAlias(path)
+	journal, err := newEventJournal(alias, urlStr, ctx.Bool("journal"))
+	fatalIf(err, "Unable to open event journal.")
+	defer journal.Close()
+
 	// Start watching on events
 	wo, err := s3Client.Watch(params)
 	fatalIf(err, "Cannot watch on the specified bucket.")
@@ -172,18 +269,29 @@ func mainWatch(ctx *cli.Context) {
 		for {
 			select {
 			case <-trapCh:
-				s3Client.Unwatch(params)
+				errorIf(s3Client.Unwatch(params), "Unable to remove bucket notification of the watch.")
 				return
 			case event, ok := <-wo.Events():
 				if !ok {
 					return
 				}
+				globalMetrics.Add(metricEvents, 1, "type", string(event.Type))
 				msg := watchMessage{Event: event}
 				printMsg(msg)
+				relay.Send(msg)
+				hook.Run(event)
+				errorIf(journal.Record(event).Trace(path), "Unable to record event of ‘"+event.Path+"’.")
+			case status, ok := <-wo.Status():
+				if !ok {
+					return
+				}
+				printMsg(newWatchStatusMessage(status))
 			case err, ok := <-wo.Errors():
 				if !ok {
 					return
 				}
+				globalMetrics.Add(metricErrors, 1)
+				errorIf(s3Client.Unwatch(params), "Unable to remove bucket notification of the watch.")
 				fatalIf(err, "Cannot watch on events.")
 				return
 			}
@@ -192,4 +300,6 @@ func mainWatch(ctx *cli.Context) {
 
 	// Wait on the routine to be finished or exit.
 	wg.Wait()
+	relay.Close()
+	hook.Close()
 }
@@ -18,6 +18,9 @@ package cmd
 
 import (
 	"fmt"
+	"path"
+	"regexp"
+	"strings"
 	"sync"
 	"time"
 
@@ -32,8 +35,44 @@ const (
 	EventCreate EventType = "ObjectCreated"
 	// EventRemove notifies when a new object has been deleted
 	EventRemove = "ObjectRemoved"
+	// EventAccess notifies when an object has been read
+	EventAccess = "ObjectAccessed"
 )
 
+// eventTypeNames - names of the bucket notification events of the
+// types of events accepted by commands.
+var eventTypeNames = map[string][]string{
+	"put":         {"s3:ObjectCreated:*"},
+	"delete":      {"s3:ObjectRemoved:*"},
+	"get":         {"s3:ObjectAccessed:*"},
+	"lifecycle":   {"s3:LifecycleExpiration:*", "s3:LifecycleTransition"},
+	"replication": {"s3:Replication:*"},
+}
+
+// notificationEvents - names of the bucket notification events of the
+// types, names like ‘s3:ObjectRestore:Post’ are passed through so that
+// events of newer servers can be used.
+func notificationEvents(events []string) ([]string, *probe.Error) {
+	var names []string
+	for _, event := range events {
+		if typeNames, ok := eventTypeNames[event]; ok {
+			names = append(names, typeNames...)
+			continue
+		}
+		if !strings.HasPrefix(event, "s3:") || len(event) == len("s3:") {
+			return nil, errInvalidWatchEvent(event).Trace(event)
+		}
+		names = append(names, event)
+	}
+	return names, nil
+}
+
+// eventNameType - type of the event of the name, e.g. ‘ObjectCreated’
+// of ‘s3:ObjectCreated:Put’.
+func eventNameType(name string) EventType {
+	return EventType(strings.SplitN(strings.TrimPrefix(name, "s3:"), ":", 2)[0])
+}
+
 // Event contains the information of the event that occurred
 type Event struct {
 	Time   string    `json:"time"`
@@ -41,37 +80,201 @@ type Event struct {
 	Path   string    `json:"path"`
 	Client Client    `json:"-"`
 	Type   EventType `json:"type"`
+	// Name of the event, e.g. ‘s3:ObjectCreated:Put’.
+	Name string `json:"name,omitempty"`
+	// Key of the object, relative to the watched folder on filesystems.
+	Key string `json:"key"`
+	// Details of the notification record, unknown on filesystems.
+	Bucket    string `json:"bucket,omitempty"`
+	ETag      string `json:"etag,omitempty"`
+	VersionID string `json:"versionId,omitempty"`
+	SourceIP  string `json:"sourceIP,omitempty"`
+	Sequencer string `json:"sequencer,omitempty"`
+}
+
+// WatchParams - what a client is asked to watch, events of types
+// ‘put’ and ‘delete’ on keys kept by Matches.
+type WatchParams struct {
+	AccountID string
+	Prefix    string
+	Suffix    string
+	Events    []string
+	Recursive bool
+	// Names of the events to keep, e.g. ‘s3:ObjectCreated:Put’, with
+	// ‘*’ as wildcard. All events are kept if empty.
+	Names []string
+	// Keys of the events to keep, all are kept if nil.
+	KeyRegexp *regexp.Regexp
+	// ARN of a notification registered while watching, if any.
+	ARN string
+}
+
+// notificationID - ID of the notification registered by the watch.
+func (p WatchParams) notificationID() string {
+	return "mc-watch-" + p.AccountID
+}
+
+// Prefixes of the names of events of each type.
+const (
+	eventNameCreated = "s3:ObjectCreated:"
+	eventNameRemoved = "s3:ObjectRemoved:"
+)
+
+// parseWatchEvents - types and names of the events to watch. Besides
+// the types of eventTypeNames events may be given by name, like
+// ‘s3:ObjectCreated:CompleteMultipartUpload’ or ‘ObjectRemoved:*’.
+// Names of objects created or removed are of type ‘put’ or ‘delete’,
+// other names are watched as they are.
+func parseWatchEvents(events []string) (types []string, names []string, err *probe.Error) {
+	addType := func(t string) {
+		for _, s := range types {
+			if s == t {
+				return
+			}
+		}
+		types = append(types, t)
+	}
+	for _, event := range events {
+		event = strings.TrimSpace(event)
+		if typeNames, ok := eventTypeNames[event]; ok {
+			addType(event)
+			names = append(names, typeNames...)
+			continue
+		}
+		name := event
+		if !strings.HasPrefix(name, "s3:") {
+			// Unknown types are not taken for names.
+			if !strings.Contains(name, ":") {
+				return nil, nil, errInvalidWatchEvent(event).Trace(event)
+			}
+			name = "s3:" + name
+		}
+		if _, e := path.Match(name, ""); e != nil || name == "s3:" {
+			return nil, nil, errInvalidWatchEvent(event).Trace(event)
+		}
+		switch {
+		case strings.HasPrefix(name, eventNameCreated):
+			addType("put")
+		case strings.HasPrefix(name, eventNameRemoved):
+			addType("delete")
+		default:
+			addType(name)
+		}
+		names = append(names, name)
+	}
+	return types, names, nil
+}
+
+// parseKeyRegexp - regular expression of keys, nil if empty.
+func parseKeyRegexp(expr string) (*regexp.Regexp, *probe.Error) {
+	if expr == "" {
+		return nil, nil
+	}
+	re, e := regexp.Compile(expr)
+	if e != nil {
+		return nil, errInvalidKeyRegexp(expr).Trace(expr)
+	}
+	return re, nil
 }
 
-type watchParams struct {
-	accountID string
-	prefix    string
-	suffix    string
-	events    []string
-	recursive bool
+// Matches - reports whether the event of the name on the key is kept.
+func (p WatchParams) Matches(key, name string) bool {
+	if !strings.HasPrefix(key, p.Prefix) || !strings.HasSuffix(key, p.Suffix) {
+		return false
+	}
+	if p.KeyRegexp != nil && !p.KeyRegexp.MatchString(key) {
+		return false
+	}
+	if len(p.Names) == 0 {
+		return true
+	}
+	for _, pattern := range p.Names {
+		if ok, _ := path.Match(pattern, name); ok {
+			return true
+		}
+	}
+	return false
 }
 
-type watchObject struct {
+// Delays before reconnecting dropped watches, doubled on every
+// failed attempt up to the maximum.
+var (
+	watchRetryDelay    = time.Second
+	maxWatchRetryDelay = time.Minute
+)
+
+// Number of recent events remembered to skip events sent again.
+const watchDedupeSize = 10000
+
+// WatchStatus - change of the connection of a watch. Lost connections
+// are retried after the delay.
+type WatchStatus struct {
+	Connected bool
+	Err       *probe.Error
+	Delay     time.Duration
+}
+
+// eventDeduper - remembers the IDs of recent events.
+type eventDeduper struct {
+	seen  map[string]struct{}
+	order []string
+	next  int
+}
+
+// newEventDeduper - deduper remembering the size most recent events.
+func newEventDeduper(size int) *eventDeduper {
+	return &eventDeduper{seen: make(map[string]struct{}, size), order: make([]string, size)}
+}
+
+// Seen - reports whether the event of the ID was seen before and
+// remembers it, forgetting the oldest event once full.
+func (d *eventDeduper) Seen(id string) bool {
+	if _, ok := d.seen[id]; ok {
+		return true
+	}
+	delete(d.seen, d.order[d.next])
+	d.order[d.next] = id
+	d.next = (d.next + 1) % len(d.order)
+	d.seen[id] = struct{}{}
+	return false
+}
+
+// WatchObject - events of a watching client, until it is closed.
+type WatchObject struct {
 	// events will be put on this chan
 	events chan Event
 	// errors will be put on this chan
 	errors chan *probe.Error
+	// changes of the connection will be put on this chan, nil if
+	// watching has no connection
+	status chan WatchStatus
 	// will stop the watcher goroutines
 	done chan bool
 }
 
+// NewWatchObject - watch of a client sending on the events and errors
+// channels until done is closed.
+func NewWatchObject(events chan Event, errors chan *probe.Error, done chan bool) *WatchObject {
+	return &WatchObject{events: events, errors: errors, done: done}
+}
+
 // Events returns the chan receiving events
-func (w *watchObject) Events() chan Event {
+func (w *WatchObject) Events() chan Event {
 	return w.events
 }
 
 // Errors returns the chan receiving errors
-func (w *watchObject) Errors() chan *probe.Error {
+func (w *WatchObject) Errors() chan *probe.Error {
 	return w.errors
 }
 
+// Status returns the chan receiving changes of the connection
+func (w *WatchObject) Status() chan WatchStatus {
+	return w.status
+}
+
 // Close the watcher, will stop all goroutines
-func (w *watchObject) Close() {
+func (w *WatchObject) Close() {
 	close(w.done)
 }
 
@@ -83,9 +286,11 @@ type Watcher struct {
 	errorsChan chan *probe.Error
 	// all events will be added to this chan
 	eventsChan chan Event
+	// all changes of connections will be added to this chan
+	statusChan chan WatchStatus
 
 	// array of watchers joined
-	o []*watchObject
+	o []*WatchObject
 
 	// all watchers joining will enter this waitgroup
 	wg sync.WaitGroup
@@ -97,7 +302,8 @@ func NewWatcher(sessionStartTime time.Time) *Watcher {
 		sessionStartTime: sessionStartTime,
 		errorsChan:       make(chan *probe.Error),
 		eventsChan:       make(chan Event),
-		o:                []*watchObject{},
+		statusChan:       make(chan WatchStatus),
+		o:                []*WatchObject{},
 	}
 }
 
@@ -111,6 +317,11 @@ func (w *Watcher) Events() chan Event {
 	return w.eventsChan
 }
 
+// Status returns a channel which will receive changes of connections
+func (w *Watcher) Status() chan WatchStatus {
+	return w.statusChan
+}
+
 // Stop watcher
 func (w *Watcher) Stop() {
 	// close all running goroutines
@@ -122,6 +333,7 @@ func (w *Watcher) Stop() {
 
 	close(w.errorsChan)
 	close(w.eventsChan)
+	close(w.statusChan)
 }
 
 // Watching returns if the watcher is watching for notifications
@@ -136,9 +348,9 @@ func (w *Watcher) Wait() {
 
 // Unjoin the watcher from client.
 func (w *Watcher) Unjoin(client Client, recursive bool) *probe.Error {
-	err := client.Unwatch(watchParams{
-		recursive: recursive,
-		accountID: fmt.Sprintf("%d", w.sessionStartTime.Unix()),
+	err := client.Unwatch(WatchParams{
+		Recursive: recursive,
+		AccountID: fmt.Sprintf("%d", w.sessionStartTime.Unix()),
 	})
 	if err != nil {
 		return err
@@ -148,10 +360,10 @@ func (w *Watcher) Unjoin(client Client, recursive bool) *probe.Error {
 
 // Join the watcher with client
 func (w *Watcher) Join(client Client, recursive bool) *probe.Error {
-	wo, err := client.Watch(watchParams{
-		recursive: recursive,
-		events:    []string{"put", "delete"},
-		accountID: fmt.Sprintf("%d", w.sessionStartTime.Unix()),
+	wo, err := client.Watch(WatchParams{
+		Recursive: recursive,
+		Events:    []string{"put", "delete"},
+		AccountID: fmt.Sprintf("%d", w.sessionStartTime.Unix()),
 	})
 	if err != nil {
 		return err
@@ -181,6 +393,12 @@ func (w *Watcher) Join(client Client, recursive bool) *probe.Error {
 				}
 
 				w.errorsChan <- err
+			case status, ok := <-wo.Status():
+				if !ok {
+					return
+				}
+
+				w.statusChan <- status
 			}
 		}
 	}()
@@ -0,0 +1,74 @@
+/*
+ * Minio Client (C) 2016 Minio, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	. "gopkg.in/check.v1"
+)
+
+// Test parsing and filtering of watched events.
+func (s *TestSuite) TestWatchEvents(c *C) {
+	testCases := []struct {
+		events  []string
+		types   []string
+		names   []string
+		success bool
+	}{
+		{[]string{"put", "delete"}, []string{"put", "delete"}, []string{"s3:ObjectCreated:*", "s3:ObjectRemoved:*"}, true},
+		{[]string{"s3:ObjectCreated:CompleteMultipartUpload"}, []string{"put"}, []string{"s3:ObjectCreated:CompleteMultipartUpload"}, true},
+		{[]string{"ObjectCreated:Put", "ObjectCreated:Copy", " delete"}, []string{"put", "delete"}, []string{"s3:ObjectCreated:Put", "s3:ObjectCreated:Copy", "s3:ObjectRemoved:*"}, true},
+		{[]string{"get", "lifecycle"}, []string{"get", "lifecycle"}, []string{"s3:ObjectAccessed:*", "s3:LifecycleExpiration:*", "s3:LifecycleTransition"}, true},
+		{[]string{"s3:ObjectRestore:Post", "ObjectRestore:Post"}, []string{"s3:ObjectRestore:Post"}, []string{"s3:ObjectRestore:Post", "s3:ObjectRestore:Post"}, true},
+		{[]string{"access"}, nil, nil, false},
+		{[]string{"s3:"}, nil, nil, false},
+		{[]string{"s3:ObjectCreated:[Put"}, nil, nil, false},
+	}
+	for i, testCase := range testCases {
+		types, names, err := parseWatchEvents(testCase.events)
+		if !testCase.success {
+			c.Assert(err, NotNil, Commentf("Test %d", i+1))
+			continue
+		}
+		c.Assert(err, IsNil, Commentf("Test %d", i+1))
+		c.Assert(types, DeepEquals, testCase.types, Commentf("Test %d", i+1))
+		c.Assert(names, DeepEquals, testCase.names, Commentf("Test %d", i+1))
+	}
+
+	_, err := parseKeyRegexp("photos/(")
+	c.Assert(err, NotNil)
+	keyRegexp, err := parseKeyRegexp(`^photos/.*\.jpg$`)
+	c.Assert(err, IsNil)
+
+	_, names, _ := parseWatchEvents([]string{"s3:ObjectCreated:CompleteMultipartUpload", "delete"})
+	params := WatchParams{Names: names, KeyRegexp: keyRegexp}
+	c.Assert(params.Matches("photos/a.jpg", "s3:ObjectCreated:CompleteMultipartUpload"), Equals, true)
+	c.Assert(params.Matches("photos/a.jpg", "s3:ObjectCreated:Put"), Equals, false)
+	c.Assert(params.Matches("photos/a.jpg", "s3:ObjectRemoved:Delete"), Equals, true)
+	c.Assert(params.Matches("photos/a.png", "s3:ObjectRemoved:Delete"), Equals, false)
+	// Without filters all events are kept.
+	c.Assert(WatchParams{}.Matches("a.png", "s3:ObjectCreated:Post"), Equals, true)
+
+	names, err = notificationEvents([]string{"put", "replication", "s3:ObjectRestore:Completed"})
+	c.Assert(err, IsNil)
+	c.Assert(names, DeepEquals, []string{"s3:ObjectCreated:*", "s3:Replication:*", "s3:ObjectRestore:Completed"})
+	_, err = notificationEvents([]string{"ObjectRestore:Completed"})
+	c.Assert(err, NotNil)
+
+	c.Assert(eventNameType("s3:ObjectCreated:Put"), Equals, EventCreate)
+	c.Assert(eventNameType("s3:ObjectAccessed:Head"), Equals, EventType(EventAccess))
+	c.Assert(eventNameType("s3:LifecycleTransition"), Equals, EventType("LifecycleTransition"))
+}
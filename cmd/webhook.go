@@ -0,0 +1,191 @@
+/*
+ * Minio Client (C) 2016 Minio, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"bytes"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"time"
+
+	"github.com/minio/cli"
+	"github.com/minio/minio/pkg/probe"
+)
+
+// Flags of forwarding events to a webhook.
+var webhookFlags = []cli.Flag{
+	cli.StringFlag{
+		Name:  "webhook",
+		Usage: "Forward events as JSON to the HTTP(S) URL.",
+	},
+	cli.StringFlag{
+		Name:   "webhook-secret",
+		Usage:  "Sign events forwarded to the webhook with HMAC-SHA256 of the secret.",
+		EnvVar: "MC_WEBHOOK_SECRET",
+	},
+	cli.IntFlag{
+		Name:  "webhook-retries",
+		Value: 3,
+		Usage: "Number of retries of events the webhook failed to receive.",
+	},
+}
+
+// Header of the signature of forwarded events, ‘sha256=’ followed by
+// the hex encoded HMAC-SHA256 of the body with the secret.
+const webhookSignatureHeader = "X-Mc-Signature"
+
+// Number of events waiting to be forwarded before watching blocks.
+const webhookQueueSize = 1000
+
+// webhookRelay - forwards events to a webhook in the order they were
+// received. A nil relay forwards nothing.
+type webhookRelay struct {
+	url     string
+	secret  string
+	retries int
+	// Delay before the first retry, doubled on every further retry.
+	backoff time.Duration
+	client  *http.Client
+
+	msgCh  chan watchMessage
+	doneCh chan struct{}
+}
+
+// newWebhookRelay - relay to the URL, nil if there is no URL.
+func newWebhookRelay(urlStr, secret string, retries int) (*webhookRelay, *probe.Error) {
+	if urlStr == "" {
+		return nil, nil
+	}
+	u, e := url.Parse(urlStr)
+	if e != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return nil, errInvalidWebhook(urlStr).Trace(urlStr)
+	}
+	if retries < 0 {
+		return nil, errInvalidArgument().Trace(fmt.Sprintf("%d", retries))
+	}
+	opts, err := getTransportOpts()
+	if err != nil {
+		return nil, err.Trace(urlStr)
+	}
+	transport, err := newClientTransport(&Config{HostURL: urlStr, Insecure: globalInsecure, Transport: opts})
+	if err != nil {
+		return nil, err.Trace(urlStr)
+	}
+	return &webhookRelay{
+		url:     urlStr,
+		secret:  secret,
+		retries: retries,
+		backoff: time.Second,
+		client:  &http.Client{Transport: transport, Timeout: 30 * time.Second},
+	}, nil
+}
+
+// Start - starts forwarding events sent to the relay.
+func (r *webhookRelay) Start() {
+	if r == nil {
+		return
+	}
+	r.msgCh = make(chan watchMessage, webhookQueueSize)
+	r.doneCh = make(chan struct{})
+	go func() {
+		defer close(r.doneCh)
+		for msg := range r.msgCh {
+			// Events still queued once interrupted are dropped.
+			if globalContext.Err() != nil {
+				continue
+			}
+			if err := r.Deliver(msg); err != nil {
+				globalMetrics.Add(metricErrors, 1)
+				errorIf(err.Trace(r.url), "Unable to forward event of ‘"+msg.Event.Path+"’ to webhook.")
+			}
+		}
+	}()
+}
+
+// Send - queues the event for forwarding.
+func (r *webhookRelay) Send(msg watchMessage) {
+	if r == nil {
+		return
+	}
+	r.msgCh <- msg
+}
+
+// Close - waits until queued events are forwarded.
+func (r *webhookRelay) Close() {
+	if r == nil {
+		return
+	}
+	close(r.msgCh)
+	<-r.doneCh
+}
+
+// Deliver - posts the event to the webhook, retrying network errors
+// and responses the webhook may accept later.
+func (r *webhookRelay) Deliver(msg watchMessage) *probe.Error {
+	msg.Status = "success"
+	body, e := json.Marshal(msg)
+	if e != nil {
+		return probe.NewError(e)
+	}
+	backoff := r.backoff
+	for i := 0; ; i++ {
+		err, retry := r.post(body)
+		if err == nil || !retry || i >= r.retries {
+			return err
+		}
+		select {
+		case <-time.After(backoff):
+		case <-globalContext.Done():
+			return err
+		}
+		backoff *= 2
+	}
+}
+
+// post - posts the body once, reports whether failures may be retried.
+func (r *webhookRelay) post(body []byte) (*probe.Error, bool) {
+	req, e := http.NewRequest("POST", r.url, bytes.NewReader(body))
+	if e != nil {
+		return probe.NewError(e), false
+	}
+	req.Header.Set("Content-Type", "application/json")
+	if r.secret != "" {
+		req.Header.Set(webhookSignatureHeader, "sha256="+webhookSignature(r.secret, body))
+	}
+	resp, e := r.client.Do(req.WithContext(globalContext))
+	if e != nil {
+		return probe.NewError(e), true
+	}
+	resp.Body.Close()
+	if resp.StatusCode >= http.StatusBadRequest {
+		retry := resp.StatusCode >= http.StatusInternalServerError || resp.StatusCode == http.StatusTooManyRequests
+		return errWebhookFailed(r.url, resp.Status), retry
+	}
+	return nil, false
+}
+
+// webhookSignature - hex encoded HMAC-SHA256 of the body.
+func webhookSignature(secret string, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write(body)
+	return hex.EncodeToString(mac.Sum(nil))
+}
@@ -0,0 +1,101 @@
+/*
+ * Minio Client (C) 2016 Minio, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"time"
+
+	. "gopkg.in/check.v1"
+)
+
+// Test forwarding of events to a webhook.
+func (s *TestSuite) TestWebhookRelay(c *C) {
+	for _, urlStr := range []string{"ftp://localhost/events", "localhost:9000", "http://"} {
+		_, err := newWebhookRelay(urlStr, "", 3)
+		c.Assert(err, NotNil)
+	}
+	relay, err := newWebhookRelay("", "", 3)
+	c.Assert(err, IsNil)
+	c.Assert(relay, IsNil)
+
+	var mutex sync.Mutex
+	var paths, contentTypes, signatures, bodies []string
+	requests := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mutex.Lock()
+		defer mutex.Unlock()
+		requests++
+		body, _ := ioutil.ReadAll(r.Body)
+		msg := watchMessage{}
+		json.Unmarshal(body, &msg)
+		switch msg.Event.Path {
+		case "retried.txt":
+			// Fail the first attempt.
+			if requests == 1 {
+				w.WriteHeader(http.StatusServiceUnavailable)
+				return
+			}
+		case "rejected.txt":
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		paths = append(paths, msg.Event.Path)
+		contentTypes = append(contentTypes, r.Header.Get("Content-Type"))
+		signatures = append(signatures, r.Header.Get(webhookSignatureHeader))
+		bodies = append(bodies, string(body))
+	}))
+	defer server.Close()
+
+	relay, err = newWebhookRelay(server.URL, "secret", 2)
+	c.Assert(err, IsNil)
+	relay.backoff = time.Millisecond
+
+	// Failed responses are retried, except for client errors.
+	c.Assert(relay.Deliver(watchMessage{Event: Event{Path: "retried.txt", Type: EventCreate}}), IsNil)
+	c.Assert(requests, Equals, 2)
+	c.Assert(relay.Deliver(watchMessage{Event: Event{Path: "rejected.txt", Type: EventCreate}}), NotNil)
+	c.Assert(requests, Equals, 3)
+
+	// Queued events are forwarded in order.
+	relay.Start()
+	relay.Send(watchMessage{Event: Event{Path: "a.txt", Type: EventCreate}})
+	relay.Send(watchMessage{Event: Event{Path: "b.txt", Type: EventRemove}})
+	relay.Close()
+
+	// Without a secret events are not signed.
+	relay, err = newWebhookRelay(server.URL, "", 0)
+	c.Assert(err, IsNil)
+	c.Assert(relay.Deliver(watchMessage{Event: Event{Path: "c.txt", Type: EventCreate}}), IsNil)
+
+	c.Assert(paths, DeepEquals, []string{"retried.txt", "a.txt", "b.txt", "c.txt"})
+	for i := range paths {
+		c.Assert(contentTypes[i], Equals, "application/json")
+		msg := watchMessage{}
+		c.Assert(json.Unmarshal([]byte(bodies[i]), &msg), IsNil)
+		c.Assert(msg.Status, Equals, "success")
+		if i < 3 {
+			c.Assert(signatures[i], Equals, "sha256="+webhookSignature("secret", []byte(bodies[i])))
+		} else {
+			c.Assert(signatures[i], Equals, "")
+		}
+	}
+}
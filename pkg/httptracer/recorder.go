@@ -0,0 +1,408 @@
+/*
+ * Minio Client (C) 2016 Minio, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package httptracer
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/json"
+	"io"
+	"net/http"
+	"os"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+	"unicode/utf8"
+)
+
+// Redacted replaces values of secrets in traces.
+const Redacted = "**REDACTED**"
+
+// RedactedHeaders - headers holding secrets, their values are never
+// recorded.
+var RedactedHeaders = []string{"Authorization", "X-Amz-Security-Token", "Cookie", "Set-Cookie"}
+
+// RedactedQueries - query parameters holding secrets, like those of
+// presigned URLs.
+var RedactedQueries = []string{"X-Amz-Signature", "X-Amz-Credential", "X-Amz-Security-Token", "Signature", "AWSAccessKeyId"}
+
+// Formats of trace files.
+const (
+	FormatHAR   = "har"
+	FormatJSONL = "jsonl"
+)
+
+// Recorder - records HTTP requests and responses to a trace file,
+// either as HAR which can be opened with the developer tools of
+// browsers, or as JSON lines holding one HAR entry each. Bodies are
+// recorded up to a limit.
+type Recorder struct {
+	mutex     sync.Mutex
+	file      *os.File
+	format    string
+	bodyLimit int64
+	entries   int
+}
+
+// HAR documents are written with this trailer after the last entry, so
+// that the file is valid even if the process exits early.
+const harTrailer = "\n]}}\n"
+
+// NewRecorder - recorder writing the trace file at path, as HAR if the
+// path ends with ‘.har’ and as JSON lines otherwise. HAR files are
+// overwritten while JSON lines are appended. Creator and version name
+// the program in HAR files.
+func NewRecorder(path string, bodyLimit int64, creator, version string) (*Recorder, error) {
+	r := &Recorder{format: FormatJSONL, bodyLimit: bodyLimit}
+	if strings.HasSuffix(strings.ToLower(path), ".har") {
+		r.format = FormatHAR
+	}
+
+	var e error
+	if r.format == FormatJSONL {
+		r.file, e = os.OpenFile(path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0600)
+		return r, e
+	}
+	r.file, e = os.OpenFile(path, os.O_CREATE|os.O_RDWR|os.O_TRUNC, 0600)
+	if e != nil {
+		return nil, e
+	}
+	header, e := json.Marshal(harCreator{Name: creator, Version: version})
+	if e != nil {
+		return nil, e
+	}
+	if _, e = io.WriteString(r.file, `{"log":{"version":"1.2","creator":`+string(header)+`,"entries":[`+harTrailer); e != nil {
+		return nil, e
+	}
+	return r, nil
+}
+
+// Format - format of the trace file.
+func (r *Recorder) Format() string {
+	return r.format
+}
+
+// Close - closes the trace file.
+func (r *Recorder) Close() error {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	return r.file.Close()
+}
+
+// record - writes the entry to the trace file.
+func (r *Recorder) record(entry harEntry) error {
+	entryBytes, e := json.Marshal(entry)
+	if e != nil {
+		return e
+	}
+
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	if r.format == FormatJSONL {
+		_, e = r.file.Write(append(entryBytes, '\n'))
+		return e
+	}
+	// Entries overwrite the trailer, which is written again.
+	if _, e = r.file.Seek(-int64(len(harTrailer)), io.SeekEnd); e != nil {
+		return e
+	}
+	separator := "\n"
+	if r.entries > 0 {
+		separator = ",\n"
+	}
+	if _, e = io.WriteString(r.file, separator+string(entryBytes)+harTrailer); e != nil {
+		return e
+	}
+	r.entries++
+	return nil
+}
+
+// Transport - transport recording the requests of the transport, once
+// their responses were read or closed.
+func (r *Recorder) Transport(transport http.RoundTripper) http.RoundTripper {
+	return recordingTransport{recorder: r, transport: transport}
+}
+
+type recordingTransport struct {
+	recorder  *Recorder
+	transport http.RoundTripper
+}
+
+// RoundTrip - sends the request, capturing bodies.
+func (t recordingTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	started := time.Now()
+
+	var reqBody *bodyCapture
+	if req.Body != nil && t.recorder.bodyLimit > 0 {
+		reqBody = &bodyCapture{ReadCloser: req.Body, limit: t.recorder.bodyLimit}
+		clone := *req
+		clone.Body = reqBody
+		req = &clone
+	}
+
+	resp, e := t.transport.RoundTrip(req)
+	if e != nil {
+		entry := newHAREntry(req, reqBody, started)
+		entry.Response = harResponse{Headers: []harHeader{}, Cookies: []harHeader{}, Content: harContent{Size: 0}, HeadersSize: -1, BodySize: -1}
+		entry.Comment = e.Error()
+		t.recorder.record(entry)
+		return resp, e
+	}
+
+	waited := time.Since(started)
+	respBody := &bodyCapture{ReadCloser: resp.Body, limit: t.recorder.bodyLimit}
+	respBody.done = func() {
+		entry := newHAREntry(req, reqBody, started)
+		entry.Response = newHARResponse(resp, respBody)
+		entry.Time = milliseconds(time.Since(started))
+		entry.Timings = harTimings{Send: 0, Wait: milliseconds(waited), Receive: milliseconds(time.Since(started) - waited)}
+		t.recorder.record(entry)
+	}
+	resp.Body = respBody
+	return resp, nil
+}
+
+// bodyCapture - captures a body up to a limit while it is read, done is
+// called once it was read or closed.
+type bodyCapture struct {
+	io.ReadCloser
+	limit int64
+
+	mutex sync.Mutex
+	buf   bytes.Buffer
+	size  int64
+	done  func()
+	once  sync.Once
+}
+
+func (b *bodyCapture) Read(p []byte) (int, error) {
+	n, e := b.ReadCloser.Read(p)
+	b.mutex.Lock()
+	b.size += int64(n)
+	if rest := b.limit - int64(b.buf.Len()); rest > 0 {
+		if int64(n) < rest {
+			rest = int64(n)
+		}
+		b.buf.Write(p[:rest])
+	}
+	b.mutex.Unlock()
+	if e == io.EOF {
+		b.finish()
+	}
+	return n, e
+}
+
+func (b *bodyCapture) Close() error {
+	e := b.ReadCloser.Close()
+	b.finish()
+	return e
+}
+
+func (b *bodyCapture) finish() {
+	b.once.Do(func() {
+		if b.done != nil {
+			b.done()
+		}
+	})
+}
+
+// content - captured body as HAR content, binary bodies are base64
+// encoded.
+func (b *bodyCapture) content(mimeType string) harContent {
+	if b == nil {
+		return harContent{MimeType: mimeType}
+	}
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+	content := harContent{Size: b.size, MimeType: mimeType}
+	captured := b.buf.Bytes()
+	if len(captured) == 0 {
+		return content
+	}
+	if utf8.Valid(captured) {
+		content.Text = string(captured)
+	} else {
+		content.Text = base64.StdEncoding.EncodeToString(captured)
+		content.Encoding = "base64"
+	}
+	if int64(len(captured)) < b.size {
+		content.Comment = "truncated"
+	}
+	return content
+}
+
+// HAR 1.2 entries, see http://www.softwareishard.com/blog/har-12-spec/.
+type harCreator struct {
+	Name    string `json:"name"`
+	Version string `json:"version"`
+}
+
+type harHeader struct {
+	Name  string `json:"name"`
+	Value string `json:"value"`
+}
+
+type harContent struct {
+	Size     int64  `json:"size"`
+	MimeType string `json:"mimeType"`
+	Text     string `json:"text,omitempty"`
+	Encoding string `json:"encoding,omitempty"`
+	Comment  string `json:"comment,omitempty"`
+}
+
+type harPostData struct {
+	MimeType string `json:"mimeType"`
+	Text     string `json:"text"`
+	Encoding string `json:"encoding,omitempty"`
+	Comment  string `json:"comment,omitempty"`
+}
+
+type harRequest struct {
+	Method      string       `json:"method"`
+	URL         string       `json:"url"`
+	HTTPVersion string       `json:"httpVersion"`
+	Cookies     []harHeader  `json:"cookies"`
+	Headers     []harHeader  `json:"headers"`
+	QueryString []harHeader  `json:"queryString"`
+	PostData    *harPostData `json:"postData,omitempty"`
+	HeadersSize int64        `json:"headersSize"`
+	BodySize    int64        `json:"bodySize"`
+}
+
+type harResponse struct {
+	Status      int         `json:"status"`
+	StatusText  string      `json:"statusText"`
+	HTTPVersion string      `json:"httpVersion"`
+	Cookies     []harHeader `json:"cookies"`
+	Headers     []harHeader `json:"headers"`
+	Content     harContent  `json:"content"`
+	RedirectURL string      `json:"redirectURL"`
+	HeadersSize int64       `json:"headersSize"`
+	BodySize    int64       `json:"bodySize"`
+}
+
+type harTimings struct {
+	Send    float64 `json:"send"`
+	Wait    float64 `json:"wait"`
+	Receive float64 `json:"receive"`
+}
+
+type harEntry struct {
+	StartedDateTime string      `json:"startedDateTime"`
+	Time            float64     `json:"time"`
+	Request         harRequest  `json:"request"`
+	Response        harResponse `json:"response"`
+	Cache           struct{}    `json:"cache"`
+	Timings         harTimings  `json:"timings"`
+	Comment         string      `json:"comment,omitempty"`
+}
+
+func milliseconds(d time.Duration) float64 {
+	return float64(d) / float64(time.Millisecond)
+}
+
+// harHeaders - headers sorted by name, secrets are redacted.
+func harHeaders(header http.Header) []harHeader {
+	headers := []harHeader{}
+	for _, name := range sortedKeys(header) {
+		for _, value := range header[name] {
+			if isRedacted(name, RedactedHeaders) {
+				value = Redacted
+			}
+			headers = append(headers, harHeader{Name: name, Value: value})
+		}
+	}
+	return headers
+}
+
+func isRedacted(name string, names []string) bool {
+	for _, redacted := range names {
+		if strings.EqualFold(name, redacted) {
+			return true
+		}
+	}
+	return false
+}
+
+func sortedKeys(m map[string][]string) []string {
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// newHAREntry - entry of the request, its response is set once read.
+func newHAREntry(req *http.Request, body *bodyCapture, started time.Time) harEntry {
+	u := *req.URL
+	query := u.Query()
+	for name := range query {
+		if isRedacted(name, RedactedQueries) {
+			query.Set(name, Redacted)
+		}
+	}
+	u.RawQuery = query.Encode()
+
+	request := harRequest{
+		Method:      req.Method,
+		URL:         u.String(),
+		HTTPVersion: req.Proto,
+		Cookies:     []harHeader{},
+		Headers:     harHeaders(req.Header),
+		QueryString: []harHeader{},
+		HeadersSize: -1,
+		BodySize:    req.ContentLength,
+	}
+	if request.HTTPVersion == "" {
+		request.HTTPVersion = "HTTP/1.1"
+	}
+	for _, name := range sortedKeys(query) {
+		for _, value := range query[name] {
+			request.QueryString = append(request.QueryString, harHeader{Name: name, Value: value})
+		}
+	}
+	if body != nil {
+		content := body.content(req.Header.Get("Content-Type"))
+		request.PostData = &harPostData{MimeType: content.MimeType, Text: content.Text, Encoding: content.Encoding, Comment: content.Comment}
+	}
+	return harEntry{
+		StartedDateTime: started.UTC().Format(time.RFC3339Nano),
+		Request:         request,
+	}
+}
+
+// newHARResponse - response with its captured body.
+func newHARResponse(resp *http.Response, body *bodyCapture) harResponse {
+	content := body.content(resp.Header.Get("Content-Type"))
+	statusText := resp.Status
+	if i := strings.Index(statusText, " "); i >= 0 {
+		statusText = statusText[i+1:]
+	}
+	return harResponse{
+		Status:      resp.StatusCode,
+		StatusText:  statusText,
+		HTTPVersion: resp.Proto,
+		Cookies:     []harHeader{},
+		Headers:     harHeaders(resp.Header),
+		Content:     content,
+		RedirectURL: resp.Header.Get("Location"),
+		HeadersSize: -1,
+		BodySize:    content.Size,
+	}
+}
@@ -0,0 +1,122 @@
+/*
+ * Minio Client (C) 2016 Minio, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package httptracer
+
+import (
+	"bufio"
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+
+	. "gopkg.in/check.v1"
+)
+
+// recordRequests - sends a PUT and a GET through a recorder, returns
+// the trace file.
+func recordRequests(c *C, name string, bodyLimit int64) string {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		ioutil.ReadAll(r.Body)
+		w.Header().Set("Content-Type", "text/plain")
+		w.Write([]byte("hello world"))
+	}))
+	defer server.Close()
+
+	dir, e := ioutil.TempDir("", "httptracer-")
+	c.Assert(e, IsNil)
+	path := filepath.Join(dir, name)
+	recorder, e := NewRecorder(path, bodyLimit, "mc", "DEVELOPMENT")
+	c.Assert(e, IsNil)
+	clnt := &http.Client{Transport: recorder.Transport(http.DefaultTransport)}
+
+	req, e := http.NewRequest("PUT", server.URL+"/bucket/object?X-Amz-Signature=secret&uploads=", strings.NewReader("uploaded data"))
+	c.Assert(e, IsNil)
+	req.Header.Set("Authorization", "AWS4-HMAC-SHA256 Credential=secret")
+	resp, e := clnt.Do(req)
+	c.Assert(e, IsNil)
+	ioutil.ReadAll(resp.Body)
+	resp.Body.Close()
+
+	resp, e = clnt.Get(server.URL + "/bucket/object")
+	c.Assert(e, IsNil)
+	resp.Body.Close()
+
+	c.Assert(recorder.Close(), IsNil)
+	return path
+}
+
+func (s *MySuite) TestRecorderHAR(c *C) {
+	path := recordRequests(c, "trace.har", 5)
+	defer os.RemoveAll(filepath.Dir(path))
+
+	data, e := ioutil.ReadFile(path)
+	c.Assert(e, IsNil)
+	c.Assert(strings.Contains(string(data), "secret"), Equals, false)
+
+	var har struct {
+		Log struct {
+			Version string     `json:"version"`
+			Creator harCreator `json:"creator"`
+			Entries []harEntry `json:"entries"`
+		} `json:"log"`
+	}
+	c.Assert(json.Unmarshal(data, &har), IsNil)
+	c.Assert(har.Log.Version, Equals, "1.2")
+	c.Assert(har.Log.Creator, Equals, harCreator{Name: "mc", Version: "DEVELOPMENT"})
+	c.Assert(har.Log.Entries, HasLen, 2)
+
+	put := har.Log.Entries[0]
+	c.Assert(put.Request.Method, Equals, "PUT")
+	c.Assert(put.Request.QueryString, DeepEquals, []harHeader{{"X-Amz-Signature", Redacted}, {"uploads", ""}})
+	c.Assert(put.Request.Headers, DeepEquals, []harHeader{{"Authorization", Redacted}})
+	// Bodies are truncated to the limit.
+	c.Assert(put.Request.PostData, NotNil)
+	c.Assert(put.Request.PostData.Text, Equals, "uploa")
+	c.Assert(put.Request.PostData.Comment, Equals, "truncated")
+	c.Assert(put.Response.Status, Equals, http.StatusOK)
+	c.Assert(put.Response.StatusText, Equals, "OK")
+	c.Assert(put.Response.Content, Equals, harContent{Size: 11, MimeType: "text/plain", Text: "hello", Comment: "truncated"})
+
+	// Bodies which were not read are recorded once closed.
+	get := har.Log.Entries[1]
+	c.Assert(get.Request.Method, Equals, "GET")
+	c.Assert(get.Request.PostData, IsNil)
+	c.Assert(get.Response.Content.Text, Equals, "")
+}
+
+func (s *MySuite) TestRecorderJSONL(c *C) {
+	path := recordRequests(c, "trace.jsonl", 0)
+	defer os.RemoveAll(filepath.Dir(path))
+
+	file, e := os.Open(path)
+	c.Assert(e, IsNil)
+	defer file.Close()
+	var entries []harEntry
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		var entry harEntry
+		c.Assert(json.Unmarshal(scanner.Bytes(), &entry), IsNil)
+		entries = append(entries, entry)
+	}
+	c.Assert(entries, HasLen, 2)
+	// Bodies are not recorded without a limit.
+	c.Assert(entries[0].Request.PostData, IsNil)
+	c.Assert(entries[0].Response.Content, Equals, harContent{Size: 11, MimeType: "text/plain"})
+}
@@ -49,23 +49,19 @@ func FTW(root string, walkFn FTWFunc) error {
 }
 
 // byName implements sort.Interface for sorting os.FileInfo list.
-type byName []os.FileInfo
-
-func (f byName) Len() int      { return len(f) }
-func (f byName) Swap(i, j int) { f[i], f[j] = f[j], f[i] }
-func (f byName) Less(i, j int) bool {
-	n1 := f[i].Name()
-	if f[i].IsDir() {
-		n1 = n1 + string(os.PathSeparator)
-	}
-
-	n2 := f[j].Name()
-	if f[j].IsDir() {
-		n2 = n2 + string(os.PathSeparator)
-	}
+// Directories, and links to directories, are sorted by their name
+// with a trailing separator.
+type byName struct {
+	fis   []os.FileInfo
+	names []string
+}
 
-	return n1 < n2
+func (f byName) Len() int { return len(f.fis) }
+func (f byName) Swap(i, j int) {
+	f.fis[i], f.fis[j] = f.fis[j], f.fis[i]
+	f.names[i], f.names[j] = f.names[j], f.names[i]
 }
+func (f byName) Less(i, j int) bool { return f.names[i] < f.names[j] }
 
 // readDir reads the directory named by dirname and returns
 // a sorted list of directory entries.
@@ -74,7 +70,20 @@ func readDir(dirname string) (fi []os.FileInfo, err error) {
 	if err == nil {
 		defer f.Close()
 		if fi, err = f.Readdir(-1); fi != nil {
-			sort.Sort(byName(fi))
+			names := make([]string, len(fi))
+			for i, info := range fi {
+				names[i] = info.Name()
+				isDir := info.IsDir()
+				if info.Mode()&os.ModeSymlink == os.ModeSymlink {
+					if st, e := os.Stat(filepath.Join(dirname, info.Name())); e == nil {
+						isDir = st.IsDir()
+					}
+				}
+				if isDir {
+					names[i] = names[i] + string(os.PathSeparator)
+				}
+			}
+			sort.Sort(byName{fis: fi, names: names})
 		}
 	}
 
@@ -9,7 +9,7 @@
 
 
 initial_count=`mc config host list | wc -l`
-add_test_result=`mc config --json host add testdisk  https://storage.googleapis.com BKIKJAA5BMMU2RHO6IBB V8f1CwQqAcwo80UEIJEjc5gVQUSSx5ohQ9GSrr12 S3v2`
+add_test_result=`mc config --json host add --no-verify testdisk  https://storage.googleapis.com BKIKJAA5BMMU2RHO6IBB V8f1CwQqAcwo80UEIJEjc5gVQUSSx5ohQ9GSrr12 S3v2`
 final_count=`mc config host list | wc -l`
 remove_test_result=`mc config host remove testdisk`
 